@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/cmd/util/templates"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/client/v2"
+	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
+)
+
+var (
+	queueLong = templates.LongDesc(`
+		List the executions waiting for enough capacity to run on the agent's compute node,
+		in the order they are expected to run.
+
+		Executions are ordered by the priority of their job, raised the longer they wait, and then
+		by the share of the node already used by their namespace.
+`)
+
+	queueExample = templates.Examples(`
+		# List the queued executions of the node
+		bacalhau agent queue
+
+		# List the queued executions of the node in JSON
+		bacalhau agent queue --output json
+`)
+)
+
+// QueueOptions is a struct to support queue command
+type QueueOptions struct {
+	OutputOpts output.OutputOptions
+}
+
+// NewQueueOptions returns initialized Options
+func NewQueueOptions() *QueueOptions {
+	return &QueueOptions{
+		OutputOpts: output.OutputOptions{Format: output.TableFormat},
+	}
+}
+
+func NewQueueCmd() *cobra.Command {
+	o := NewQueueOptions()
+	queueCmd := &cobra.Command{
+		Use:     "queue",
+		Short:   "List the executions queued on the agent's compute node.",
+		Long:    queueLong,
+		Example: queueExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// initialize a new or open an existing repo merging any config file(s) it contains into cfg.
+			cfg, err := util.SetupRepoConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to setup repo: %w", err)
+			}
+			// create an api client
+			api, err := util.NewAPIClientManager(cmd, cfg).GetAuthenticatedAPIClient()
+			if err != nil {
+				return fmt.Errorf("failed to create api client: %w", err)
+			}
+			return o.runQueue(cmd, api)
+		},
+	}
+	queueCmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOpts))
+	return queueCmd
+}
+
+var queueColumns = []output.TableColumn[models.QueuedExecution]{
+	{
+		ColumnConfig: table.ColumnConfig{
+			Name:             "Execution ID",
+			WidthMax:         idgen.ShortIDLengthWithPrefix,
+			WidthMaxEnforcer: func(col string, maxLen int) string { return idgen.ShortUUID(col) }},
+		Value: func(e models.QueuedExecution) string { return e.ExecutionID },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Job", WidthMax: 30, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e models.QueuedExecution) string { return e.JobName },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Namespace", WidthMax: 20, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e models.QueuedExecution) string { return e.Namespace },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Priority", WidthMax: 8, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e models.QueuedExecution) string { return strconv.Itoa(e.Priority) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Effective", WidthMax: 9, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e models.QueuedExecution) string { return strconv.Itoa(e.EffectivePriority) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Waiting", WidthMax: 10, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e models.QueuedExecution) string { return output.Elapsed(e.EnqueuedAt) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Resources", WidthMax: 40, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e models.QueuedExecution) string { return e.Resources.String() },
+	},
+}
+
+// runQueue executes queue command
+func (o *QueueOptions) runQueue(cmd *cobra.Command, api client.API) error {
+	response, err := api.Agent().Queue(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("could not get the queue of the node: %w", err)
+	}
+
+	if err = output.Output(cmd, queueColumns, o.OutputOpts, response.Executions); err != nil {
+		return fmt.Errorf("failed to output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+//go:build unit || !integration
+
+package agent_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/marshaller"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+
+	cmdtesting "github.com/bacalhau-project/bacalhau/cmd/testing"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+)
+
+func TestQueueSuite(t *testing.T) {
+	suite.Run(t, new(QueueSuite))
+}
+
+type QueueSuite struct {
+	cmdtesting.BaseSuite
+}
+
+func (s *QueueSuite) TestQueueJSONOutput() {
+	_, out, err := s.ExecuteTestCobraCommand("agent", "queue", "--output", string(output.JSONFormat))
+	s.Require().NoError(err, "Could not request queue with json output.")
+
+	var queued []models.QueuedExecution
+	err = marshaller.JSONUnmarshalWithMax([]byte(out), &queued)
+	s.Require().NoError(err, "Could not unmarshal the output into json - %+v", out)
+	s.Empty(queued)
+}
+
+func (s *QueueSuite) TestQueueTableOutput() {
+	_, out, err := s.ExecuteTestCobraCommand("agent", "queue")
+	s.Require().NoError(err, "Could not request queue with table output.")
+	s.Contains(out, "EXECUTION ID")
+}
@@ -22,5 +22,6 @@ func NewCmd() *cobra.Command {
 	cmd.AddCommand(NewNodeCmd())
 	cmd.AddCommand(NewVersionCmd())
 	cmd.AddCommand(NewConfigCmd())
+	cmd.AddCommand(NewQueueCmd())
 	return cmd
 }
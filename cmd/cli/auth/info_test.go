@@ -76,6 +76,12 @@ func (m *mockClient) Dial(ctx context.Context, path string, req apimodels.Reques
 	return nil, nil
 }
 
+func (m *mockClient) DialWithInput(
+	ctx context.Context, path string, req apimodels.Request, input <-chan []byte,
+) (<-chan *concurrency.AsyncResult[[]byte], error) {
+	return nil, nil
+}
+
 // TestInfo_NoSSOSupport tests when the server doesn't support any auth methods
 func TestInfo_NoSSOSupport(t *testing.T) {
 	// Create a mock client that returns an error
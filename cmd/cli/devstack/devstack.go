@@ -37,6 +37,11 @@ var (
 		# Create a devstack cluster with a single hybrid (requester and compute) nodes
 		bacalhau devstack  --requester-nodes 0 --compute-nodes 0 --hybrid-nodes 1
 
+		# Create a devstack cluster with 3 orchestrators electing a leader, which fail over to each other
+		# and share a Postgres job store
+		bacalhau devstack  --orchestrators 3 --leader-election \
+			-c Orchestrator.JobStore.Type=Postgres -c Orchestrator.JobStore.DSN=postgres://localhost/bacalhau
+
 		# Run a devstack and create (or use) the config repo in a specific folder
 		bacalhau devstack  --stack-repo ./my-devstack-configuration
 `)
@@ -54,6 +59,7 @@ type options struct {
 	MemoryProfilingFile string
 	BasePath            string
 	RandomPorts         bool // Use random ports for the nodes. Useful to avoid conflicts with active orchestrators
+	LeaderElection      bool // Run the orchestrators as a cluster electing a leader
 }
 
 func (o *options) devstackOptions() []devstack.ConfigOption {
@@ -66,6 +72,7 @@ func (o *options) devstackOptions() []devstack.ConfigOption {
 		devstack.WithMemoryProfilingFile(o.MemoryProfilingFile),
 		devstack.WithBasePath(o.BasePath),
 		devstack.WithUseStandardPorts(!o.RandomPorts),
+		devstack.WithLeaderElection(o.LeaderElection),
 	}
 	return opts
 }
@@ -171,6 +178,11 @@ func NewCmd() *cobra.Command {
 			"Useful to avoid conflicts with active orchestrators",
 	)
 
+	devstackCmd.PersistentFlags().BoolVar(&ODs.LeaderElection, "leader-election", ODs.LeaderElection,
+		"Run the orchestrators as a cluster electing a leader, sharing a SQLite job store. "+
+			"Only the leader schedules jobs, and another orchestrator takes over when it stops",
+	)
+
 	return devstackCmd
 }
 
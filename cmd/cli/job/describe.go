@@ -4,6 +4,7 @@ import (
 	"cmp"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/samber/lo"
@@ -140,6 +141,10 @@ func (o *DescribeOptions) run(cmd *cobra.Command, args []string, api client.API)
 		return fmt.Errorf("failed to write job executions for job %s: %w", jobIDOrName, err)
 	}
 
+	if err = o.printResourceUsage(cmd, executions); err != nil {
+		return fmt.Errorf("failed to write resource usage for job %s: %w", jobIDOrName, err)
+	}
+
 	for _, execution := range executions {
 		executionHistory := lo.Filter(history, func(item *models.JobHistory, _ int) bool {
 			return item.ExecutionID == execution.ID
@@ -166,6 +171,24 @@ func (o *DescribeOptions) printHeaderData(cmd *cobra.Command, job *models.Job) {
 	if job.Type == models.JobTypeBatch || job.Type == models.JobTypeService {
 		headerData = append(headerData, collections.NewPair[string, any]("Count", job.Count))
 	}
+	if job.HasDependencies() {
+		headerData = append(headerData, collections.NewPair[string, any]("Depends On", strings.Join(job.DependsOn, ", ")))
+	}
+	if job.IsScheduled() {
+		headerData = append(headerData, collections.NewPair[string, any]("Schedule", job.Schedule.Cron))
+		if job.Schedule.Timezone != "" {
+			headerData = append(headerData, collections.NewPair[string, any]("Schedule Timezone", job.Schedule.Timezone))
+		}
+		headerData = append(headerData, collections.NewPair[string, any]("Schedule Overlap", job.Schedule.OverlapPolicy))
+		headerData = append(headerData, collections.NewPair[string, any]("Next Run", nextScheduledRun(job)))
+	}
+	if len(job.Webhooks) > 0 {
+		urls := make([]string, len(job.Webhooks))
+		for i, webhook := range job.Webhooks {
+			urls[i] = webhook.URL
+		}
+		headerData = append(headerData, collections.NewPair[string, any]("Webhooks", strings.Join(urls, ", ")))
+	}
 
 	// Additional data
 	headerData = append(headerData, []collections.Pair[string, any]{
@@ -214,6 +237,30 @@ func (o *DescribeOptions) printExecutions(cmd *cobra.Command, executions []*mode
 	return output.Output(cmd, executionCols, tableOptions, executions)
 }
 
+// printResourceUsage prints the resources consumed by the executions that reported them
+func (o *DescribeOptions) printResourceUsage(cmd *cobra.Command, executions []*models.Execution) error {
+	measured := lo.Filter(executions, func(e *models.Execution, _ int) bool {
+		return !e.ResourceUsage.IsZero()
+	})
+	if len(measured) == 0 {
+		return nil
+	}
+
+	tableOptions := output.OutputOptions{
+		Format:  output.TableFormat,
+		NoStyle: true,
+	}
+	usageCols := []output.TableColumn[*models.Execution]{
+		executionColumnID,
+		executionColumnCPUTime,
+		executionColumnAverageCPU,
+		executionColumnPeakMemory,
+		executionColumnDisk,
+	}
+	output.Bold(cmd, "\nResource Usage\n")
+	return output.Output(cmd, usageCols, tableOptions, measured)
+}
+
 func (o *DescribeOptions) printHistory(cmd *cobra.Command, label string, history []*models.JobHistory) error {
 	if len(history) < 1 {
 		return nil
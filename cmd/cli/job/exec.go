@@ -0,0 +1,172 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/templates"
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+var (
+	execShortDesc = templates.LongDesc(`
+		Run a command inside a running execution of a job
+`)
+
+	execLongDesc = templates.LongDesc(`
+		Run a command inside a running execution of a job, and stream its output until it exits.
+		The execution must be selected with --execution-id if the job has more than one running execution.
+		Commands are recorded in the history of the execution.
+`)
+
+	execExample = templates.Examples(`
+		# List the files of the working directory of a running job
+		bacalhau job exec j-51225160-807e-48b8-88c9-28311c7899e1 -- ls -la
+
+		# Run an interactive shell inside a specific execution of a job
+		bacalhau job exec j-51225160-807e-48b8-88c9-28311c7899e1 -e e-4f3b0ac2 -i -- sh
+`)
+
+	attachShortDesc = templates.LongDesc(`
+		Attach to the main process of a running execution of a job
+`)
+
+	attachExample = templates.Examples(`
+		# Stream the output of a running job until it exits
+		bacalhau job attach j-51225160-807e-48b8-88c9-28311c7899e1
+
+		# Forward input to the main process of a running job
+		bacalhau job attach j-51225160-807e-48b8-88c9-28311c7899e1 --stdin
+`)
+)
+
+type ExecCommandOptions struct {
+	ExecutionID string
+	Namespace   string
+	Stdin       bool
+}
+
+func NewExecCmd() *cobra.Command {
+	options := ExecCommandOptions{}
+
+	execCmd := &cobra.Command{
+		Use:           "exec [id] -- [command] [args...]",
+		Short:         execShortDesc,
+		Long:          execLongDesc,
+		Example:       execExample,
+		Args:          cobra.MinimumNArgs(2), //nolint:mnd // job ID and command
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, cmdArgs []string) error {
+			return runExec(cmd, options, cmdArgs[0], cmdArgs[1:])
+		},
+	}
+	addExecFlags(execCmd, &options)
+	return execCmd
+}
+
+func NewAttachCmd() *cobra.Command {
+	options := ExecCommandOptions{}
+
+	attachCmd := &cobra.Command{
+		Use:           "attach [id]",
+		Short:         attachShortDesc,
+		Example:       attachExample,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, cmdArgs []string) error {
+			return runExec(cmd, options, cmdArgs[0], nil)
+		},
+	}
+	addExecFlags(attachCmd, &options)
+	return attachCmd
+}
+
+func addExecFlags(cmd *cobra.Command, options *ExecCommandOptions) {
+	cmd.Flags().StringVarP(&options.ExecutionID, "execution-id", "e", "",
+		"The execution to run the command in. Required if the job has more than one running execution.")
+	cmd.Flags().BoolVarP(&options.Stdin, "stdin", "i", false,
+		"Forward the standard input to the command.")
+	cmd.Flags().StringVar(&options.Namespace, "namespace", options.Namespace,
+		`Job Namespace. If not provided, default namespace will be used.`)
+}
+
+func runExec(cmd *cobra.Command, options ExecCommandOptions, jobID string, command []string) error {
+	// initialize a new or open an existing repo merging any config file(s) it contains into cfg.
+	cfg, err := util.SetupRepoConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to setup repo: %w", err)
+	}
+	// create an api client
+	api, err := util.NewAPIClientManager(cmd, cfg).GetAuthenticatedAPIClient()
+	if err != nil {
+		return fmt.Errorf("failed to create api client: %w", err)
+	}
+
+	request := &apimodels.ExecJobRequest{
+		JobID:       jobID,
+		ExecutionID: options.ExecutionID,
+		Command:     command,
+	}
+	request.Namespace = options.Namespace
+
+	var stdin io.Reader
+	if options.Stdin {
+		stdin = cmd.InOrStdin()
+	}
+	ch, err := api.Jobs().Exec(cmd.Context(), request, stdin)
+	if err != nil {
+		if bacerrors.IsError(err) {
+			return err
+		}
+		return fmt.Errorf("failed to exec into job (ID: %s): %w", jobID, err)
+	}
+
+	exitCode, err := readExecOutput(cmd.Context(), ch, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("command exited with code %d", exitCode)
+	}
+	return nil
+}
+
+// readExecOutput writes the output of a command until it exits, and returns its exit code
+func readExecOutput(ctx context.Context, ch <-chan *concurrency.AsyncResult[models.ExecOutput],
+	stdout, stderr io.Writer) (int, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case result, ok := <-ch:
+			if !ok {
+				return 0, fmt.Errorf("connection closed before the command exited")
+			}
+			if result.Err != nil {
+				return 0, fmt.Errorf("error received from server: %w", result.Err)
+			}
+			var err error
+			switch result.Value.Type {
+			case models.ExecOutputStdout:
+				_, err = stdout.Write(result.Value.Data)
+			case models.ExecOutputStderr:
+				_, err = stderr.Write(result.Value.Data)
+			case models.ExecOutputExit:
+				return result.Value.ExitCode, nil
+			default:
+			}
+			if err != nil {
+				return 0, fmt.Errorf("failed to write output: %w", err)
+			}
+		}
+	}
+}
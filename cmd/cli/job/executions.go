@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
@@ -163,6 +164,24 @@ var (
 			Name: "Comment", WidthMax: 40, WidthMaxEnforcer: output.WrapSoftPreserveNewlines},
 		Value: func(e *models.Execution) string { return e.ComputeState.Message },
 	}
+	executionColumnCPUTime = output.TableColumn[*models.Execution]{
+		ColumnConfig: table.ColumnConfig{Name: "CPU Time", WidthMax: 12, WidthMaxEnforcer: text.WrapText},
+		Value: func(e *models.Execution) string {
+			return time.Duration(e.ResourceUsage.CPUSeconds * float64(time.Second)).Round(time.Millisecond).String()
+		},
+	}
+	executionColumnAverageCPU = output.TableColumn[*models.Execution]{
+		ColumnConfig: table.ColumnConfig{Name: "Avg. CPU", WidthMax: 8, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e *models.Execution) string { return fmt.Sprintf("%.2f", e.ResourceUsage.AverageCPU()) },
+	}
+	executionColumnPeakMemory = output.TableColumn[*models.Execution]{
+		ColumnConfig: table.ColumnConfig{Name: "Peak Memory", WidthMax: 11, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e *models.Execution) string { return humanize.Bytes(e.ResourceUsage.PeakMemory) },
+	}
+	executionColumnDisk = output.TableColumn[*models.Execution]{
+		ColumnConfig: table.ColumnConfig{Name: "Disk", WidthMax: 10, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e *models.Execution) string { return humanize.Bytes(e.ResourceUsage.Disk) },
+	}
 )
 
 var executionColumns = []output.TableColumn[*models.Execution]{
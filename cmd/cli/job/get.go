@@ -29,11 +29,15 @@ var (
 
 		# Get the results of a job, with a short ID.
 		bacalhau job get ebd9bf2f
+
+		# Get the results published so far by a job that streams its results while running.
+		bacalhau job get --partial my-job
 `)
 )
 
 type GetOptions struct {
 	Namespace        string
+	Partial          bool
 	DownloadSettings *cliflags.DownloaderSettings
 }
 
@@ -73,6 +77,9 @@ func NewGetCmd() *cobra.Command {
 	getCmd.PersistentFlags().StringVar(&OG.Namespace, "namespace", OG.Namespace,
 		`Job Namespace. If not provided, default namespace will be used.`,
 	)
+	getCmd.PersistentFlags().BoolVar(&OG.Partial, "partial", OG.Partial,
+		`Also get the results published so far by executions that are still running, if the job streams its results.`,
+	)
 	getCmd.PersistentFlags().AddFlagSet(cliflags.NewDownloadFlags(OG.DownloadSettings))
 
 	return getCmd
@@ -105,6 +112,7 @@ func get(cmd *cobra.Command, cmdArgs []string, api client.API, cfg types.Bacalha
 		api,
 		jobIDOrName,
 		OG.Namespace,
+		OG.Partial,
 		OG.DownloadSettings,
 	); err != nil {
 		return err
@@ -135,6 +135,23 @@ var listColumns = []output.TableColumn[*models.Job]{
 		ColumnConfig: table.ColumnConfig{Name: "state", WidthMax: 20, WidthMaxEnforcer: text.WrapText},
 		Value:        func(j *models.Job) string { return j.State.StateType.String() },
 	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "next run", WidthMax: 19, WidthMaxEnforcer: text.WrapText},
+		Value:        nextScheduledRun,
+	},
+}
+
+// nextScheduledRun returns the time of the next scheduled run of the job,
+// or an empty string if the job is not scheduled or has been stopped.
+func nextScheduledRun(j *models.Job) string {
+	if !j.IsScheduled() || j.State.StateType == models.JobStateTypeStopped {
+		return ""
+	}
+	next := j.Schedule.Next(time.Now())
+	if next.IsZero() {
+		return ""
+	}
+	return next.UTC().Format(time.DateTime)
 }
 
 func (o *ListOptions) run(cmd *cobra.Command, api client.API) error {
@@ -18,7 +18,9 @@ func NewCmd() *cobra.Command {
 	// Register profile flag for client commands
 	cliflags.RegisterProfileFlag(cmd)
 
+	cmd.AddCommand(NewAttachCmd())
 	cmd.AddCommand(NewDescribeCmd())
+	cmd.AddCommand(NewExecCmd())
 	cmd.AddCommand(NewExecutionCmd())
 	cmd.AddCommand(NewHistoryCmd())
 	cmd.AddCommand(NewVersionsCmd())
@@ -0,0 +1,19 @@
+package jobstore
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util/hook"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:      "jobstore",
+		Short:    "Commands to manage the orchestrator's job store.",
+		PreRunE:  hook.ClientPreRunHooks,
+		PostRunE: hook.ClientPostRunHooks,
+	}
+
+	cmd.AddCommand(NewMigrateCmd())
+	return cmd
+}
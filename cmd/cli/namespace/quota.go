@@ -0,0 +1,114 @@
+package namespace
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/client/v2"
+)
+
+func NewQuotaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quota",
+		Short: "Commands to manage the resource quotas of namespaces.",
+		Long: `Commands to manage the resource quotas of namespaces.
+
+Quotas limit the concurrent executions, total resources and queued jobs of a namespace.
+Jobs that can never run within their namespace's quota are rejected at submission,
+and jobs that would exceed it at the moment are kept queued until enough quota is freed.`,
+	}
+
+	cmd.AddCommand(NewQuotaSetCmd())
+	cmd.AddCommand(NewQuotaGetCmd())
+	cmd.AddCommand(NewQuotaListCmd())
+	cmd.AddCommand(NewQuotaDeleteCmd())
+	return cmd
+}
+
+// QuotaGetOptions is a struct to support the quota get command
+type QuotaGetOptions struct {
+	OutputOpts output.NonTabularOutputOptions
+}
+
+func NewQuotaGetCmd() *cobra.Command {
+	o := &QuotaGetOptions{
+		OutputOpts: output.NonTabularOutputOptions{Format: output.YAMLFormat},
+	}
+
+	cmd := &cobra.Command{
+		Use:           "get [namespace]",
+		Short:         "Get the quota and current usage of a namespace.",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// initialize a new or open an existing repo merging any config file(s) it contains into cfg.
+			cfg, err := util.SetupRepoConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to setup repo: %w", err)
+			}
+			// create an api client
+			api, err := util.NewAPIClientManager(cmd, cfg).GetAuthenticatedAPIClient()
+			if err != nil {
+				return fmt.Errorf("failed to create api client: %w", err)
+			}
+			return o.run(cmd, args, api)
+		},
+	}
+
+	cmd.Flags().AddFlagSet(cliflags.OutputNonTabularFormatFlags(&o.OutputOpts))
+	return cmd
+}
+
+func (o *QuotaGetOptions) run(cmd *cobra.Command, args []string, api client.API) error {
+	ctx := cmd.Context()
+	namespace := args[0]
+	response, err := api.Namespaces().GetQuota(ctx, &apimodels.GetNamespaceQuotaRequest{
+		Name: namespace,
+	})
+	if err != nil {
+		return bacerrors.Wrapf(err, "could not get quota of namespace %s", namespace)
+	}
+
+	if err = output.OutputOneNonTabular(cmd, o.OutputOpts, response); err != nil {
+		return fmt.Errorf("failed to write quota of namespace %s: %w", namespace, err)
+	}
+	return nil
+}
+
+func NewQuotaDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:           "delete [namespace]",
+		Short:         "Delete the quota of a namespace, removing all its limits.",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// initialize a new or open an existing repo merging any config file(s) it contains into cfg.
+			cfg, err := util.SetupRepoConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to setup repo: %w", err)
+			}
+			// create an api client
+			api, err := util.NewAPIClientManager(cmd, cfg).GetAuthenticatedAPIClient()
+			if err != nil {
+				return fmt.Errorf("failed to create api client: %w", err)
+			}
+
+			namespace := args[0]
+			if _, err = api.Namespaces().DeleteQuota(cmd.Context(), &apimodels.DeleteNamespaceQuotaRequest{
+				Name: namespace,
+			}); err != nil {
+				return bacerrors.Wrapf(err, "failed to delete quota of namespace %s", namespace)
+			}
+			cmd.Println("Ok")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,114 @@
+package namespace
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/client/v2"
+)
+
+var quotaColumns = []output.TableColumn[*models.NamespaceQuota]{
+	{
+		ColumnConfig: table.ColumnConfig{Name: "namespace"},
+		Value:        func(q *models.NamespaceQuota) string { return q.Namespace },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "executions"},
+		Value:        func(q *models.NamespaceQuota) string { return formatLimit(q.MaxConcurrentExecutions, strconv.Itoa) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "queued jobs"},
+		Value:        func(q *models.NamespaceQuota) string { return formatLimit(q.MaxQueuedJobs, strconv.Itoa) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "cpu"},
+		Value: func(q *models.NamespaceQuota) string {
+			return formatLimit(q.MaxResources.CPU, func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) })
+		},
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "memory"},
+		Value:        func(q *models.NamespaceQuota) string { return formatLimit(q.MaxResources.Memory, humanize.Bytes) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "disk"},
+		Value:        func(q *models.NamespaceQuota) string { return formatLimit(q.MaxResources.Disk, humanize.Bytes) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "gpu"},
+		Value: func(q *models.NamespaceQuota) string {
+			return formatLimit(q.MaxResources.GPU, func(v uint64) string { return strconv.FormatUint(v, 10) })
+		},
+	},
+}
+
+// formatLimit formats a quota limit, where zero means no limit
+func formatLimit[T int | uint64 | float64](limit T, format func(T) string) string {
+	if limit == 0 {
+		return "-"
+	}
+	return format(limit)
+}
+
+// QuotaListOptions is a struct to support the quota list command
+type QuotaListOptions struct {
+	output.OutputOptions
+	Reverse bool
+}
+
+func NewQuotaListCmd() *cobra.Command {
+	o := &QuotaListOptions{
+		OutputOptions: output.OutputOptions{Format: output.TableFormat},
+	}
+
+	cmd := &cobra.Command{
+		Use:           "list",
+		Short:         "List the quotas of all namespaces.",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// initialize a new or open an existing repo merging any config file(s) it contains into cfg.
+			cfg, err := util.SetupRepoConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to setup repo: %w", err)
+			}
+			// create an api client
+			api, err := util.NewAPIClientManager(cmd, cfg).GetAuthenticatedAPIClient()
+			if err != nil {
+				return fmt.Errorf("failed to create api client: %w", err)
+			}
+			return o.run(cmd, api)
+		},
+	}
+
+	cmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
+	cmd.Flags().BoolVar(&o.Reverse, "reverse", false, "reverse order of the quotas")
+	return cmd
+}
+
+func (o *QuotaListOptions) run(cmd *cobra.Command, api client.API) error {
+	response, err := api.Namespaces().ListQuotas(cmd.Context(), &apimodels.ListNamespaceQuotasRequest{
+		BaseListRequest: apimodels.BaseListRequest{
+			Reverse: o.Reverse,
+		},
+	})
+	if err != nil {
+		return bacerrors.Wrapf(err, "failed to list namespace quotas")
+	}
+
+	if err = output.Output(cmd, quotaColumns, o.OutputOptions, response.Quotas); err != nil {
+		return fmt.Errorf("failed to output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package namespace
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/client/v2"
+)
+
+// QuotaSetOptions is a struct to support the quota set command
+type QuotaSetOptions struct {
+	MaxConcurrentExecutions int
+	MaxQueuedJobs           int
+	Resources               models.ResourcesConfig
+	OutputOpts              output.NonTabularOutputOptions
+}
+
+func NewQuotaSetCmd() *cobra.Command {
+	o := &QuotaSetOptions{
+		OutputOpts: output.NonTabularOutputOptions{Format: output.YAMLFormat},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "set [namespace]",
+		Short: "Set the quota of a namespace.",
+		Long: `Set the quota of a namespace, replacing any existing quota.
+Limits that are not set, or set to zero, are not enforced.`,
+		Example: `# Limit the "team-a" namespace to 10 concurrent executions using at most 16 CPUs and 64GB of memory
+bacalhau namespace quota set team-a --max-executions 10 --cpu 16 --memory 64gb
+
+# Limit the "team-b" namespace to 100 queued jobs
+bacalhau namespace quota set team-b --max-queued-jobs 100`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// initialize a new or open an existing repo merging any config file(s) it contains into cfg.
+			cfg, err := util.SetupRepoConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to setup repo: %w", err)
+			}
+			// create an api client
+			api, err := util.NewAPIClientManager(cmd, cfg).GetAuthenticatedAPIClient()
+			if err != nil {
+				return fmt.Errorf("failed to create api client: %w", err)
+			}
+			return o.run(cmd, args, api)
+		},
+	}
+
+	cmd.Flags().IntVar(&o.MaxConcurrentExecutions, "max-executions", 0,
+		"Maximum number of executions of the namespace's jobs that can be active at the same time")
+	cmd.Flags().IntVar(&o.MaxQueuedJobs, "max-queued-jobs", 0,
+		"Maximum number of the namespace's jobs that can be waiting to be scheduled")
+	cmd.Flags().StringVar(&o.Resources.CPU, "cpu", "",
+		"Maximum total CPU of the namespace's active executions (e.g. 500m, 2, 8)")
+	cmd.Flags().StringVar(&o.Resources.Memory, "memory", "",
+		"Maximum total memory of the namespace's active executions (e.g. 500Mb, 2Gb, 8Gb)")
+	cmd.Flags().StringVar(&o.Resources.Disk, "disk", "",
+		"Maximum total disk of the namespace's active executions (e.g. 500Gb, 2Tb)")
+	cmd.Flags().StringVar(&o.Resources.GPU, "gpu", "",
+		"Maximum total GPUs of the namespace's active executions (e.g. 1, 2, 8)")
+	cmd.Flags().AddFlagSet(cliflags.OutputNonTabularFormatFlags(&o.OutputOpts))
+	return cmd
+}
+
+func (o *QuotaSetOptions) run(cmd *cobra.Command, args []string, api client.API) error {
+	ctx := cmd.Context()
+	namespace := args[0]
+
+	resources, err := o.Resources.ToResources()
+	if err != nil {
+		return bacerrors.Wrapf(err, "invalid quota resources").WithCode(bacerrors.ValidationError)
+	}
+	quota := &models.NamespaceQuota{
+		Namespace:               namespace,
+		MaxConcurrentExecutions: o.MaxConcurrentExecutions,
+		MaxQueuedJobs:           o.MaxQueuedJobs,
+		MaxResources:            *resources,
+	}
+	quota.Normalize()
+	if err = quota.Validate(); err != nil {
+		return bacerrors.Wrapf(err, "invalid quota").WithCode(bacerrors.ValidationError)
+	}
+
+	response, err := api.Namespaces().PutQuota(ctx, &apimodels.PutNamespaceQuotaRequest{
+		Quota: quota,
+	})
+	if err != nil {
+		return bacerrors.Wrapf(err, "failed to set quota of namespace %s", namespace)
+	}
+
+	if err = output.OutputOneNonTabular(cmd, o.OutputOpts, response.Quota); err != nil {
+		return fmt.Errorf("failed to write quota of namespace %s: %w", namespace, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+//go:build unit || !integration
+
+package namespace_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	cmdtesting "github.com/bacalhau-project/bacalhau/cmd/testing"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type QuotaSuite struct {
+	cmdtesting.BaseSuite
+}
+
+func TestQuotaSuite(t *testing.T) {
+	suite.Run(t, new(QuotaSuite))
+}
+
+func (s *QuotaSuite) TestQuotaLifecycle() {
+	_, out, err := s.ExecuteTestCobraCommand(
+		"namespace", "quota", "set", "team-a",
+		"--max-executions", "1",
+		"--cpu", "2",
+		"--memory", "1gb",
+	)
+	s.Require().NoError(err)
+	s.Contains(out, "MaxConcurrentExecutions: 1")
+
+	_, out, err = s.ExecuteTestCobraCommand("namespace", "quota", "get", "team-a")
+	s.Require().NoError(err)
+	s.Contains(out, "Namespace: team-a")
+	s.Contains(out, "Usage:")
+
+	_, out, err = s.ExecuteTestCobraCommand("namespace", "quota", "list", "--output", "csv")
+	s.Require().NoError(err)
+	s.Contains(out, "team-a,1,-,2,1.0 GB,-,-")
+
+	// jobs that can never run within the quota are rejected
+	job := mock.Job()
+	job.Namespace = "team-a"
+	job.Count = 2
+	_, err = s.ClientV2.Jobs().Put(context.Background(), &apimodels.PutJobRequest{Job: job})
+	s.Require().Error(err)
+	s.ErrorContains(err, "exceeds the quota of namespace team-a")
+
+	_, out, err = s.ExecuteTestCobraCommand("namespace", "quota", "delete", "team-a")
+	s.Require().NoError(err)
+	s.Contains(out, "Ok")
+
+	_, _, err = s.ExecuteTestCobraCommand("namespace", "quota", "get", "team-a")
+	s.Require().Error(err)
+	s.ErrorContains(err, "quota not found for namespace team-a")
+}
+
+func (s *QuotaSuite) TestSetInvalidQuota() {
+	_, _, err := s.ExecuteTestCobraCommand("namespace", "quota", "set", "team-a", "--cpu", "lots")
+	s.Require().Error(err)
+	s.ErrorContains(err, "invalid quota resources")
+}
@@ -0,0 +1,23 @@
+package namespace
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/hook"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                "namespace",
+		Short:              "Commands to manage namespaces.",
+		PersistentPreRunE:  hook.AfterParentPreRunHook(hook.RemoteCmdPreRunHooks),
+		PersistentPostRunE: hook.AfterParentPostRunHook(hook.RemoteCmdPostRunHooks),
+	}
+
+	// Register profile flag for client commands
+	cliflags.RegisterProfileFlag(cmd)
+
+	cmd.AddCommand(NewQuotaCmd())
+	return cmd
+}
@@ -96,6 +96,48 @@ func (s *NodeActionSuite) TestListNodes() {
 	s.Require().NotContains(out, nodeID)
 }
 
+func (s *NodeActionSuite) TestCordonDrainUncordon() {
+	_, out, err := s.ExecuteTestCobraCommand("node", "list", "--output", "csv")
+	s.Require().NoError(err)
+	cells := getCells(out, 1)
+	s.Require().Equal("ELIGIBLE", cells[4], "Expected the node to be eligible for scheduling")
+	nodeID := cells[0]
+
+	// Cordon the node
+	_, out, err = s.ExecuteTestCobraCommand("node", "cordon", nodeID, "--message", "maintenance")
+	s.Require().NoError(err)
+	s.Require().Contains(out, "Ok")
+
+	_, out, err = s.ExecuteTestCobraCommand("node", "list", "--output", "csv")
+	s.Require().NoError(err)
+	s.Require().Equal("CORDONED", getCells(out, 1)[4])
+
+	_, _, err = s.ExecuteTestCobraCommand("node", "cordon", nodeID)
+	s.Require().ErrorContains(err, "already cordoned")
+
+	// Drain the node, which has no executions to wait for
+	_, out, err = s.ExecuteTestCobraCommand("node", "drain", nodeID, "--wait")
+	s.Require().NoError(err)
+	s.Require().Contains(out, "Ok")
+	s.Require().Contains(out, "is drained")
+
+	_, out, err = s.ExecuteTestCobraCommand("node", "list", "--output", "csv")
+	s.Require().NoError(err)
+	s.Require().Equal("DRAINING", getCells(out, 1)[4])
+
+	// Uncordon the node
+	_, out, err = s.ExecuteTestCobraCommand("node", "uncordon", nodeID)
+	s.Require().NoError(err)
+	s.Require().Contains(out, "Ok")
+
+	_, out, err = s.ExecuteTestCobraCommand("node", "list", "--output", "csv")
+	s.Require().NoError(err)
+	s.Require().Equal("ELIGIBLE", getCells(out, 1)[4])
+
+	_, _, err = s.ExecuteTestCobraCommand("node", "uncordon", nodeID)
+	s.Require().ErrorContains(err, "not cordoned")
+}
+
 func getCells(output string, lineNo int) []string {
 	lines := strings.Split(output, "\n")
 	line := lines[lineNo]
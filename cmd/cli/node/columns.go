@@ -37,6 +37,10 @@ var alwaysColumns = []output.TableColumn[*models.NodeState]{
 			return ni.ConnectionState.Status.String()
 		},
 	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "scheduling"},
+		Value:        func(ni *models.NodeState) string { return ni.Scheduling.String() },
+	},
 }
 
 var toggleColumns = map[string][]output.TableColumn[*models.NodeState]{
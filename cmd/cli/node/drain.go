@@ -0,0 +1,129 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/client/v2"
+)
+
+const (
+	defaultDrainWaitTimeout   = 10 * time.Minute
+	drainProgressPollInterval = 2 * time.Second
+)
+
+type NodeDrainCmd struct {
+	message     string
+	wait        bool
+	waitTimeout time.Duration
+}
+
+func NewDrainCmd() *cobra.Command {
+	drainCmd := &NodeDrainCmd{
+		waitTimeout: defaultDrainWaitTimeout,
+	}
+
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s [id]", apimodels.NodeActionDrain),
+		Short: apimodels.NodeActionDrain.Description(),
+		Long: `Cordon a node so no new executions are scheduled on it, and move its service and daemon executions
+to other nodes. Batch executions are left to complete. The drain can be stopped by uncordoning the node.`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// initialize a new or open an existing repo merging any config file(s) it contains into cfg.
+			cfg, err := util.SetupRepoConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to setup repo: %w", err)
+			}
+			// create an api client
+			api, err := util.NewAPIClientManager(cmd, cfg).GetAuthenticatedAPIClient()
+			if err != nil {
+				return fmt.Errorf("failed to create api client: %w", err)
+			}
+			return drainCmd.run(cmd, args, api)
+		},
+	}
+
+	cmd.Flags().StringVarP(&drainCmd.message, "message", "m", "", "Message to include with the action")
+	cmd.Flags().BoolVar(&drainCmd.wait, "wait", false, "Wait for all executions to leave the node")
+	cmd.Flags().DurationVar(&drainCmd.waitTimeout, "wait-timeout", drainCmd.waitTimeout,
+		"Maximum time to wait for the node to be drained")
+	return cmd
+}
+
+func (n *NodeDrainCmd) run(cmd *cobra.Command, args []string, api client.API) error {
+	ctx := cmd.Context()
+	nodeID := args[0]
+
+	response, err := api.Nodes().Put(ctx, &apimodels.PutNodeRequest{
+		NodeID:  nodeID,
+		Action:  string(apimodels.NodeActionDrain),
+		Message: n.message,
+	})
+	if err != nil {
+		return bacerrors.Wrapf(err, "failed to drain node %s", nodeID)
+	}
+	if !response.Success {
+		cmd.PrintErrf("Failed to drain node %s: %s\n", nodeID, response.Error)
+		return nil
+	}
+
+	cmd.Println("Ok")
+	status := response.Drain
+	if status == nil || status.Complete || !n.wait {
+		printDrainStatus(cmd, nodeID, status)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.waitTimeout)
+	defer cancel()
+	ticker := time.NewTicker(drainProgressPollInterval)
+	defer ticker.Stop()
+
+	lastRemaining := -1
+	for !status.Complete {
+		if status.RemainingExecutions != lastRemaining {
+			printDrainStatus(cmd, nodeID, status)
+			lastRemaining = status.RemainingExecutions
+		}
+
+		select {
+		case <-ctx.Done():
+			return bacerrors.Newf("timed out waiting for node %s to be drained with %d executions remaining",
+				nodeID, status.RemainingExecutions)
+		case <-ticker.C:
+		}
+
+		nodeResponse, err := api.Nodes().Get(ctx, &apimodels.GetNodeRequest{NodeID: nodeID})
+		if err != nil {
+			return bacerrors.Wrapf(err, "failed to get node %s", nodeID)
+		}
+		if nodeResponse.Drain == nil {
+			// the drain was stopped by uncordoning the node
+			cmd.Printf("Node %s is no longer draining\n", nodeID)
+			return nil
+		}
+		status = nodeResponse.Drain
+	}
+	printDrainStatus(cmd, nodeID, status)
+	return nil
+}
+
+func printDrainStatus(cmd *cobra.Command, nodeID string, status *apimodels.NodeDrainStatus) {
+	switch {
+	case status == nil:
+		return
+	case status.Complete:
+		cmd.Printf("Node %s is drained\n", nodeID)
+	default:
+		cmd.Printf("Draining node %s: %d executions remaining\n", nodeID, status.RemainingExecutions)
+	}
+}
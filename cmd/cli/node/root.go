@@ -31,5 +31,14 @@ func NewCmd() *cobra.Command {
 	// Reject Action
 	cmd.AddCommand(NewActionCmd(apimodels.NodeActionDelete))
 
+	// Cordon Action
+	cmd.AddCommand(NewActionCmd(apimodels.NodeActionCordon))
+
+	// Uncordon Action
+	cmd.AddCommand(NewActionCmd(apimodels.NodeActionUncordon))
+
+	// Drain Action
+	cmd.AddCommand(NewDrainCmd())
+
 	return cmd
 }
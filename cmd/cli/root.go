@@ -17,6 +17,8 @@ import (
 	"github.com/bacalhau-project/bacalhau/cmd/cli/devstack"
 	"github.com/bacalhau-project/bacalhau/cmd/cli/docker"
 	"github.com/bacalhau-project/bacalhau/cmd/cli/job"
+	"github.com/bacalhau-project/bacalhau/cmd/cli/jobstore"
+	"github.com/bacalhau-project/bacalhau/cmd/cli/namespace"
 	"github.com/bacalhau-project/bacalhau/cmd/cli/node"
 	"github.com/bacalhau-project/bacalhau/cmd/cli/profile"
 	"github.com/bacalhau-project/bacalhau/cmd/cli/serve"
@@ -115,7 +117,9 @@ func NewRootCmd() *cobra.Command {
 		devstack.NewCmd(),
 		docker.NewCmd(),
 		job.NewCmd(),
+		jobstore.NewCmd(),
 		auth.NewCmd(),
+		namespace.NewCmd(),
 		node.NewCmd(),
 		profile.NewCmd(),
 		serve.NewCmd(),
@@ -232,6 +232,9 @@ func serve(cmd *cobra.Command, cfg types.Bacalhau, fsRepo *repo.FsRepo) error {
 			if len(cfg.Compute.AllowListedLocalPaths) > 0 {
 				startupLog.Strs("volumes", cfg.Compute.AllowListedLocalPaths)
 			}
+			if len(cfg.Compute.AllowListedBinaries) > 0 {
+				startupLog.Strs("binaries", cfg.Compute.AllowListedBinaries)
+			}
 		}
 	}
 
@@ -64,6 +64,8 @@ type WasmRunOptions struct {
 	ImportModules []string
 	// The name of the WASM function to call in the entry module
 	Entrypoint string
+	// The maximum number of WASM instructions the job can run, or zero for no limit
+	InstructionLimit uint64
 
 	JobSettings     *cliflags.JobSettings
 	TaskSettings    *cliflags.TaskSettings
@@ -128,6 +130,10 @@ func newRunCmd() *cobra.Command {
 		`The name of the WASM function in the entry module to call. This should be a zero-parameter zero-result function that
 		will execute the job.`,
 	)
+	wasmFlags.Uint64Var(&opts.InstructionLimit, "instruction-limit", opts.InstructionLimit,
+		`The maximum number of WASM instructions the job can run across all of its modules. The job fails once the limit
+		is exceeded. Zero means no limit.`,
+	)
 
 	wasmRunCmd.Flags().AddFlagSet(wasmFlags)
 	return wasmRunCmd
@@ -264,6 +270,7 @@ func build(ctx context.Context, args []string, opts *WasmRunOptions) (*models.Jo
 		WithParameters(args[1:]...).
 		WithEntrypoint(opts.Entrypoint).
 		WithImportModules(importModulePaths).
+		WithInstructionLimit(opts.InstructionLimit).
 		Build()
 	if err != nil {
 		return nil, err
@@ -25,12 +25,14 @@ func DownloadResultsHandler(
 	apiV2 clientv2.API,
 	jobIDOrName string,
 	namespace string,
+	partial bool,
 	downloadSettings *cliflags.DownloaderSettings,
 ) error {
 	cmd.PrintErrf("Fetching results of job '%s'...\n", jobIDOrName)
 
 	request := &apimodels.ListJobResultsRequest{
-		JobID: jobIDOrName,
+		JobID:   jobIDOrName,
+		Partial: partial,
 	}
 	request.Namespace = namespace
 	response, err := apiV2.Jobs().Results(ctx, request)
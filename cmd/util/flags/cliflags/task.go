@@ -22,6 +22,16 @@ Examples:
 -i s3://bucket/key,dst=/my/input/path
 # Mount S3 object with specific endpoint and region
 -i src=s3://bucket/key,dst=/my/input/path,opt=endpoint=https://s3.example.com,opt=region=us-east-1
+# Download a file, verifying its checksum and size, with an authorization header
+-i src=https://example.com/data.csv,dst=/data,opt=checksum=sha256:<digest>,opt=header=Authorization: Bearer <token>,opt=max-size=1GB
+# Download and extract a tar, zip, gzip or zstd archive to /data
+-i src=https://example.com/dataset.tar.gz,dst=/data,opt=extract=true
+# Check out the v1.0 tag of a git repository over https to /src
+-i git://github.com/org/repo#v1.0,dst=/src
+# Check out two directories and the submodules of a git repository over ssh
+-i src=git+ssh://git@github.com/org/repo,dst=/src,opt=paths=docs:examples,opt=submodules=true
+# Check out a private git repository, authenticating with a token from the compute node's environment
+-i src=git://github.com/org/repo,dst=/src,opt=credential=env:GIT_TOKEN
 `
 
 	ResultPathUsageMsg = "name:path of the output data volumes"
@@ -8,9 +8,11 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/dustin/go-humanize"
 	flag "github.com/spf13/pflag"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	storage_git "github.com/bacalhau-project/bacalhau/pkg/storage/git"
 	storage_ipfs "github.com/bacalhau-project/bacalhau/pkg/storage/ipfs"
 	storage_local "github.com/bacalhau-project/bacalhau/pkg/storage/local"
 	storage_s3 "github.com/bacalhau-project/bacalhau/pkg/storage/s3"
@@ -122,6 +124,16 @@ func storageStringToSpecConfig(sourceURI, destinationPath, alias string, options
 		return nil, err
 	}
 
+	// extracting archives applies to all storages, unlike the other options
+	var extract bool
+	if value, ok := options["extract"]; ok {
+		extract, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse extract option: %s", err)
+		}
+		delete(options, "extract")
+	}
+
 	var sc *models.SpecConfig
 	switch parsedURI.Scheme {
 	case "ipfs":
@@ -130,7 +142,7 @@ func storageStringToSpecConfig(sourceURI, destinationPath, alias string, options
 			return nil, err
 		}
 	case "http", "https":
-		sc, err = storage_url.NewSpecConfig(sourceURI)
+		sc, err = urlStringToSpecConfig(sourceURI, options)
 		if err != nil {
 			return nil, err
 		}
@@ -180,15 +192,95 @@ func storageStringToSpecConfig(sourceURI, destinationPath, alias string, options
 		if err != nil {
 			return nil, err
 		}
-	case "git", "gitlfs":
+	case "git", "git+https", "git+http", "git+ssh":
+		sc, err = gitStringToSpecConfig(parsedURI, options)
+		if err != nil {
+			return nil, err
+		}
+	case "gitlfs":
 		return nil, fmt.Errorf("unsupported type: %s", parsedURI.Scheme)
 	default:
 		return nil, fmt.Errorf("unknown storage schema: %s", parsedURI.Scheme)
 	}
 
 	return &models.InputSource{
-		Source: sc,
-		Alias:  alias,
-		Target: destinationPath,
+		Source:  sc,
+		Alias:   alias,
+		Target:  destinationPath,
+		Extract: extract,
 	}, nil
 }
+
+// urlStringToSpecConfig returns the spec of a URL, with the checksum, headers and max size
+// to verify its content
+func urlStringToSpecConfig(sourceURI string, options map[string]string) (*models.SpecConfig, error) {
+	source := storage_url.Source{URL: sourceURI}
+	for key, value := range options {
+		switch {
+		case key == "checksum":
+			source.Checksum = value
+		case key == "header":
+			// a single header is set as name:value, as the options are comma separated
+			name, headerValue, ok := strings.Cut(value, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid header option %q: expected name:value", value)
+			}
+			setHeader(&source, strings.TrimSpace(name), strings.TrimSpace(headerValue))
+		case strings.HasPrefix(key, "header."):
+			setHeader(&source, strings.TrimPrefix(key, "header."), value)
+		case key == "max-size", key == "max_size", key == "maxsize":
+			maxSize, err := humanize.ParseBytes(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse max-size option: %s", err)
+			}
+			source.MaxSize = maxSize
+		default:
+			return nil, fmt.Errorf("unknown option %q for storage url", key)
+		}
+	}
+	return storage_url.NewSourceSpecConfig(source)
+}
+
+func setHeader(source *storage_url.Source, name, value string) {
+	if source.Headers == nil {
+		source.Headers = make(map[string]string)
+	}
+	source.Headers[name] = value
+}
+
+// gitStringToSpecConfig returns the spec of a git repository. git:// URIs are checked out over https,
+// while git+https://, git+http:// and git+ssh:// URIs use the transport of their scheme.
+// The ref to check out can be set either as the fragment of the URI or as the ref option.
+func gitStringToSpecConfig(parsedURI *url.URL, options map[string]string) (*models.SpecConfig, error) {
+	repo := *parsedURI
+	repo.Scheme = strings.TrimPrefix(parsedURI.Scheme, "git+")
+	if parsedURI.Scheme == "git" {
+		repo.Scheme = "https"
+	}
+	repo.Fragment = ""
+
+	source := storage_git.Source{
+		Repo: repo.String(),
+		Ref:  parsedURI.Fragment,
+	}
+	for key, value := range options {
+		switch key {
+		case "ref", "branch", "tag", "commit":
+			source.Ref = value
+		case "paths", "path":
+			// multiple paths are separated by colons, as the options are comma separated
+			source.Paths = strings.Split(value, ":")
+		case "submodules":
+			submodules, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse submodules option: %s", err)
+			}
+			source.Submodules = submodules
+		case "credential", "token":
+			source.Credential = value
+		default:
+			return nil, fmt.Errorf("unknown option %q for storage %s", key, parsedURI.Scheme)
+		}
+	}
+	return storage_git.NewSpecConfig(source)
+}
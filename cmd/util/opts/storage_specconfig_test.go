@@ -177,6 +177,104 @@ func TestParseInputSourceSpecConfig(t *testing.T) {
 				Target: "/mount/path",
 			},
 		},
+		{
+			name:  "url with extract",
+			input: "src=https://example.com/data.tar.gz,dst=/data,opt=extract=true",
+			expected: &models.InputSource{
+				Source: &models.SpecConfig{
+					Type: models.StorageSourceURL,
+					Params: map[string]interface{}{
+						"URL": "https://example.com/data.tar.gz",
+					},
+				},
+				Alias:   "https://example.com/data.tar.gz",
+				Target:  "/data",
+				Extract: true,
+			},
+		},
+		{
+			name: "url with checksum, headers and max size",
+			input: "src=https://example.com/data.csv,dst=/data,opt=checksum=sha256:" + strings.Repeat("ab", 32) +
+				",opt=header=Authorization: Bearer token,opt=header.X-Api-Key=key,opt=max-size=10MB",
+			expected: &models.InputSource{
+				Source: &models.SpecConfig{
+					Type: models.StorageSourceURL,
+					Params: map[string]interface{}{
+						"URL":      "https://example.com/data.csv",
+						"Checksum": "sha256:" + strings.Repeat("ab", 32),
+						"Headers": map[string]string{
+							"Authorization": "Bearer token",
+							"X-Api-Key":     "key",
+						},
+						"MaxSize": uint64(10_000_000),
+					},
+				},
+				Alias:  "https://example.com/data.csv",
+				Target: "/data",
+			},
+		},
+		{
+			name:  "url with invalid checksum",
+			input: "src=https://example.com/data.csv,opt=checksum=md5:abc",
+			error: true,
+		},
+		{
+			name:  "url with unknown option",
+			input: "src=https://example.com/data.csv,opt=depth=10",
+			error: true,
+		},
+		{
+			name:  "invalid extract",
+			input: "src=https://example.com/data.tar.gz,opt=extract=maybe",
+			error: true,
+		},
+		{
+			name:  "git",
+			input: "git://github.com/org/repo#v1.0,dst=/src",
+			expected: &models.InputSource{
+				Source: &models.SpecConfig{
+					Type: models.StorageSourceGit,
+					Params: map[string]interface{}{
+						"Repo":       "https://github.com/org/repo",
+						"Ref":        "v1.0",
+						"Paths":      []string(nil),
+						"Submodules": false,
+						"Credential": "",
+					},
+				},
+				Alias:  "git://github.com/org/repo#v1.0",
+				Target: "/src",
+			},
+		},
+		{
+			name: "git with options",
+			input: "src=git+ssh://git@github.com/org/repo.git,dst=/src,opt=ref=main,opt=paths=docs:examples," +
+				"opt=submodules=true,opt=credential=env:GIT_TOKEN",
+			expected: &models.InputSource{
+				Source: &models.SpecConfig{
+					Type: models.StorageSourceGit,
+					Params: map[string]interface{}{
+						"Repo":       "ssh://git@github.com/org/repo.git",
+						"Ref":        "main",
+						"Paths":      []string{"docs", "examples"},
+						"Submodules": true,
+						"Credential": "env:GIT_TOKEN",
+					},
+				},
+				Alias:  "git+ssh://git@github.com/org/repo.git",
+				Target: "/src",
+			},
+		},
+		{
+			name:  "git with unknown option",
+			input: "src=git://github.com/org/repo,opt=depth=10",
+			error: true,
+		},
+		{
+			name:  "git with invalid path",
+			input: "src=git://github.com/org/repo,opt=paths=../etc",
+			error: true,
+		},
 		{
 			name:  "empty",
 			input: "",
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 
@@ -18,7 +19,13 @@ import (
 	clientv2 "github.com/bacalhau-project/bacalhau/pkg/publicapi/client/v2"
 )
 
-const progressTimeout = 5 * time.Minute
+const (
+	progressTimeout = 5 * time.Minute
+	// jobStartTimeout is the maximum time to wait for a job to start before following its logs
+	jobStartTimeout = 10 * time.Second
+	// jobStatePollInterval is the interval at which the job state is polled when events can't be streamed
+	jobStatePollInterval = time.Second
+)
 
 type JobProgressPrinter struct {
 	client          clientv2.API
@@ -74,22 +81,64 @@ func (j *JobProgressPrinter) followLogs(ctx context.Context, job *models.Job, cm
 
 	// Wait until the job has actually been accepted and started, otherwise this will fail waiting for
 	// the execution to appear.
-	for i := 0; i < 10; i++ {
+	if err := j.waitForJobToStart(ctx, job); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	}
+
+	return util.Logs(cmd, j.client, util.LogOptions{JobID: job.ID, Follow: true})
+}
+
+// waitForJobToStart waits until the job is no longer pending, or until jobStartTimeout is reached.
+// It is notified of the job's changes through the orchestrator's event stream, and falls back to
+// polling orchestrators that don't support it.
+func (j *JobProgressPrinter) waitForJobToStart(ctx context.Context, job *models.Job) error {
+	ctx, cancel := context.WithTimeout(ctx, jobStartTimeout)
+	defer cancel()
+
+	// subscribe to the job's changes before checking its state, so that no change is missed in between
+	events, err := j.client.Events().Stream(ctx, &apimodels.StreamEventsRequest{
+		BaseGetRequest: apimodels.BaseGetRequest{BaseRequest: apimodels.BaseRequest{Namespace: job.Namespace}},
+		JobID:          job.ID,
+		ObjectTypes:    []apimodels.EventObjectType{apimodels.EventObjectJob},
+	})
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to stream job events, polling the job state instead")
+		events = nil
+	}
+
+	for {
 		resp, err := j.client.Jobs().Get(ctx, &apimodels.GetJobRequest{JobIDOrName: job.ID})
 		if err != nil {
-			if errors.Is(err, context.Canceled) {
+			if errors.Is(err, context.DeadlineExceeded) {
 				return nil
 			}
 			return fmt.Errorf("failed getting job: %w", err)
 		}
 		if resp.Job.State.StateType != models.JobStateTypePending {
-			break
+			return nil
 		}
-		// TODO: add exponential backoff if there were no state updates
-		time.Sleep(time.Second)
-	}
 
-	return util.Logs(cmd, j.client, util.LogOptions{JobID: job.ID, Follow: true})
+		var poll <-chan time.Time
+		if events == nil {
+			poll = time.After(jobStatePollInterval)
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				// follow the logs anyway, the job might still start
+				return nil
+			}
+			return ctx.Err()
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+			}
+		case <-poll:
+		}
+	}
 }
 
 func (j *JobProgressPrinter) followProgress(ctx context.Context, job *models.Job, cmd *cobra.Command) error {
@@ -2,6 +2,7 @@ package authz
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/bacalhau-project/bacalhau/pkg/config/types"
 )
@@ -23,8 +24,17 @@ const (
 	ResourceTypeJob   ResourceType = "job"
 	ResourceTypeAgent ResourceType = "agent"
 	ResourceTypeOpen  ResourceType = "open"
+	// ResourceTypeExec is running commands inside executions of jobs, which requires a capability of its own
+	// as it is reached with a GET request, but gives the same access to the execution as the job itself.
+	ResourceTypeExec ResourceType = "exec"
 )
 
+// CapabilityExecJob is the capability required to run commands inside, or attach to, executions of jobs
+const CapabilityExecJob = "exec:job"
+
+// jobsEndpoint is the endpoint of jobs, under which are the endpoints to run commands inside their executions
+const jobsEndpoint = "/api/v1/orchestrator/jobs/"
+
 // GetRequiredCapability determines the required capability for a specific resource type and HTTP method
 func (c *CapabilityChecker) GetRequiredCapability(resourceType ResourceType, method string) string {
 	isReadOperation := method == http.MethodGet
@@ -45,6 +55,8 @@ func (c *CapabilityChecker) GetRequiredCapability(resourceType ResourceType, met
 			return "read:agent"
 		}
 		return "write:agent"
+	case ResourceTypeExec:
+		return CapabilityExecJob
 	default:
 		// If no resource type matched, default to requiring node admin for safety
 		return "write:node"
@@ -118,10 +130,26 @@ func MapEndpointToResourceType(path string, endpointsPermissions map[string]stri
 		}
 	}
 
+	// Running commands inside executions of a job requires more than reading the job
+	if ResourceType(matchedResourceType) == ResourceTypeJob && isExecEndpoint(path) {
+		return ResourceTypeExec
+	}
+
 	// Return the resource type for the longest match (or empty if no match)
 	return ResourceType(matchedResourceType)
 }
 
+// isExecEndpoint returns true if the path runs commands inside, or attaches to, executions of a job
+func isExecEndpoint(path string) bool {
+	path, _, _ = strings.Cut(path, "?")
+	jobPath, found := strings.CutPrefix(path, jobsEndpoint)
+	if !found {
+		return false
+	}
+	parts := strings.Split(strings.TrimSuffix(jobPath, "/"), "/")
+	return len(parts) == 2 && parts[0] != "" && (parts[1] == "exec" || parts[1] == "attach")
+}
+
 // GetDefaultEndpointPermissions returns the default endpoint to permission mapping
 func GetDefaultEndpointPermissions() map[string]string {
 	return map[string]string{
@@ -133,7 +161,8 @@ func GetDefaultEndpointPermissions() map[string]string {
 		"/api/v1/agent/version":    "open",
 		"/api/v1/agent/authconfig": "open",
 
-		"/api/v1/orchestrator/jobs":  "job",
-		"/api/v1/orchestrator/nodes": "node",
+		"/api/v1/orchestrator/jobs":   "job",
+		"/api/v1/orchestrator/events": "job",
+		"/api/v1/orchestrator/nodes":  "node",
 	}
 }
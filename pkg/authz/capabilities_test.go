@@ -251,6 +251,7 @@ func TestGetDefaultEndpointPermissions(t *testing.T) {
 	assert.Equal(t, "open", permissions["/api/v1/agent/alive"])
 	assert.Equal(t, "node", permissions["/api/v1/orchestrator/nodes"])
 	assert.Equal(t, "job", permissions["/api/v1/orchestrator/jobs"])
+	assert.Equal(t, "job", permissions["/api/v1/orchestrator/events"])
 
 	// Ensure all important endpoints are covered
 	assert.Greater(t, len(permissions), 7, "Default permissions should include all important endpoints")
@@ -346,6 +347,44 @@ func TestResourceTypeAgent(t *testing.T) {
 	assert.Equal(t, "write:agent", requiredWriteCapability)
 }
 
+// TestResourceTypeExec tests that running commands inside executions of jobs requires the exec capability
+func TestResourceTypeExec(t *testing.T) {
+	checker := NewCapabilityChecker()
+	defaultPermissions := GetDefaultEndpointPermissions()
+
+	for _, path := range []string{
+		"/api/v1/orchestrator/jobs/j-123/exec",
+		"/api/v1/orchestrator/jobs/j-123/attach",
+		"/api/v1/orchestrator/jobs/j-123/exec?command=ls&stdin=true",
+	} {
+		assert.Equal(t, ResourceTypeExec, MapEndpointToResourceType(path, defaultPermissions), path)
+	}
+	for _, path := range []string{
+		"/api/v1/orchestrator/jobs/j-123/logs",
+		"/api/v1/orchestrator/jobs/exec",
+		"/api/v1/orchestrator/jobs/j-123/exec/other",
+	} {
+		assert.Equal(t, ResourceTypeJob, MapEndpointToResourceType(path, defaultPermissions), path)
+	}
+
+	// reading jobs does not grant running commands inside their executions
+	reader := types.AuthUser{
+		Alias:        "job_reader",
+		Capabilities: []types.Capability{{Actions: []string{"read:job", "read:*"}}},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orchestrator/jobs/j-123/exec", nil)
+	hasAccess, requiredCapability := checker.CheckUserAccess(reader, ResourceTypeExec, req)
+	assert.False(t, hasAccess)
+	assert.Equal(t, CapabilityExecJob, requiredCapability)
+
+	execer := types.AuthUser{
+		Alias:        "job_execer",
+		Capabilities: []types.Capability{{Actions: []string{CapabilityExecJob}}},
+	}
+	hasAccess, _ = checker.CheckUserAccess(execer, ResourceTypeExec, req)
+	assert.True(t, hasAccess)
+}
+
 // TestEdgeCaseEmptyCapabilities tests the capability checker with empty capabilities
 func TestEdgeCaseEmptyCapabilities(t *testing.T) {
 	checker := NewCapabilityChecker()
@@ -4,12 +4,20 @@ import rego.v1
 default allow = false
 
 job_endpoint := ["api", "v1", "orchestrator", "jobs"]
+events_endpoint := ["api", "v1", "orchestrator", "events"]
 
 # https://developer.mozilla.org/en-US/docs/Glossary/Safe/HTTP
 http_safe_methods := ["GET", "HEAD", "OPTIONS"]
 http_unsafe_methods := ["PUT", "DELETE", "POST"]
 
 
+# Endpoints running commands inside executions of a job, which are reached with safe methods
+is_exec_api if {
+    count(input.http.path) == 6
+    array.slice(input.http.path, 0, 4) == job_endpoint
+    input.http.path[5] in ["exec", "attach"]
+}
+
 is_legacy_api if {
     input.http.path[2] == "requester"
 }
@@ -30,10 +38,28 @@ allow if {
     namespace_readable(job_namespace_perms)
 }
 
+# Allow streaming events if the access token has read access to the requested namespace
+allow if {
+    input.http.path == events_endpoint
+    input.http.method in http_safe_methods
+
+    namespace_readable(events_namespace_perms)
+}
+
+# Allow running commands inside executions if the access token has write access to the job namespace
+allow if {
+    is_exec_api
+    input.http.method in http_safe_methods
+
+    namespace_writable(exec_namespace_perms)
+}
+
 # Allow reading all other endpoints, including by users who don't have a token
 allow if {
     input.http.path != job_endpoint
+    input.http.path != events_endpoint
     not is_legacy_api
+    not is_exec_api
     input.http.method in http_safe_methods
 }
 
@@ -91,6 +117,20 @@ job_namespace := ns if {
     ns := jobRequest["namespace"]
 }
 
+# The namespace whose events are being streamed
+default events_namespace := "default"
+events_namespace := input.http.query["namespace"][0]
+
+# The permissions the access token grants on the events namespace
+events_namespace_perms := bits.or(object.get(token_namespaces, events_namespace, 0), object.get(token_namespaces, "*", 0))
+
+# The namespace of the job commands are run in
+default exec_namespace := "default"
+exec_namespace := input.http.query["namespace"][0]
+
+# The permissions the access token grants on the namespace of the job commands are run in
+exec_namespace_perms := bits.or(object.get(token_namespaces, exec_namespace, 0), object.get(token_namespaces, "*", 0))
+
 # The list of namespaces from the verified access token
 token_namespaces := ns if {
     authHeader := input.http.headers["Authorization"][0]
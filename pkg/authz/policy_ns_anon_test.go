@@ -73,6 +73,18 @@ func TestAppliesAnonymousNamespacePolicy(t *testing.T) {
 			"other", "other", "test", NamespaceNoPermission, http.MethodGet, "/api/v1/orchestrator/nodes", sameKey, require.True},
 		{"deny writing other APIs",
 			"other", "other", "test", NamespaceNoPermission, http.MethodDelete, "/api/v1/orchestrator/nodes", sameKey, require.False},
+		{"allow valid events read",
+			"test", "test", "test", NamespaceReadable, http.MethodGet, "/api/v1/orchestrator/events?namespace=test", sameKey, require.True},
+		{"deny events read to alternative namespace",
+			"test", "test", "test", NamespaceReadable, http.MethodGet, "/api/v1/orchestrator/events?namespace=other", sameKey, require.False},
+		{"deny events read without token",
+			"test", "test", "test", NamespaceNoPermission, http.MethodGet, "/api/v1/orchestrator/events?namespace=test", sameKey, require.False},
+		{"allow exec into writable namespace",
+			"test", "test", "test", NamespaceWritable, http.MethodGet, "/api/v1/orchestrator/jobs/j1/exec?namespace=test", sameKey, require.True},
+		{"deny exec into readable namespace",
+			"test", "test", "test", NamespaceReadable, http.MethodGet, "/api/v1/orchestrator/jobs/j1/exec?namespace=test", sameKey, require.False},
+		{"deny attach without token",
+			"test", "test", "test", NamespaceNoPermission, http.MethodGet, "/api/v1/orchestrator/jobs/j1/attach", sameKey, require.False},
 		{"deny signed by wrong key",
 			"test", "test", "test", NamespaceWritable, http.MethodPut, "/api/v1/orchestrator/jobs", newKey, require.False},
 	}
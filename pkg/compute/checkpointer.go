@@ -0,0 +1,157 @@
+package compute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+)
+
+// CheckpointDir returns the directory on the host in which the checkpoint directory of an execution is collected
+func CheckpointDir(executionOutputDir string) string {
+	return filepath.Join(ExecutionResultsDir(executionOutputDir), models.CheckpointResultName)
+}
+
+type CheckpointerParams struct {
+	Store       store.ExecutionStore
+	Publishers  publisher.PublisherProvider
+	ResultsPath ResultsPath
+}
+
+// Checkpointer periodically publishes the checkpoint directory of running executions whose task enables
+// checkpointing through the task's publisher. Each published checkpoint is recorded in the execution store,
+// from which it is reported to the orchestrator to be restored if the execution fails and is retried.
+//
+// Tasks should write their checkpoints atomically, such as by renaming files once written,
+// as the directory is published while the task is running.
+type Checkpointer struct {
+	store       store.ExecutionStore
+	publishers  publisher.PublisherProvider
+	resultsPath ResultsPath
+}
+
+func NewCheckpointer(params CheckpointerParams) *Checkpointer {
+	return &Checkpointer{
+		store:       params.Store,
+		publishers:  params.Publishers,
+		resultsPath: params.ResultsPath,
+	}
+}
+
+// Run publishes the checkpoint directory of the execution at the interval configured by its task until
+// the context is done. The directory is only published if it changed since the last checkpoint.
+func (c *Checkpointer) Run(ctx context.Context, execution *models.Execution) {
+	logger := log.Ctx(ctx).With().Str("execution", execution.ID).Logger()
+	dir := CheckpointDir(c.resultsPath.ExecutionOutputDir(execution.ID))
+
+	sequence := 0
+	if execution.Checkpoint != nil {
+		sequence = execution.Checkpoint.Sequence
+	}
+
+	ticker := time.NewTicker(execution.Job.Task().Checkpoint.GetInterval())
+	defer ticker.Stop()
+
+	var published dirSnapshot
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		snapshot, err := snapshotDir(dir)
+		if err != nil || snapshot.files == 0 || snapshot == published {
+			// nothing to checkpoint yet, or nothing changed since the last checkpoint
+			continue
+		}
+		checkpoint, err := c.Checkpoint(ctx, execution, sequence+1)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn().Err(err).Msg("failed to checkpoint execution")
+			}
+			continue
+		}
+		sequence = checkpoint.Sequence
+		published = snapshot
+		logger.Debug().Int("sequence", sequence).Msg("checkpointed execution")
+	}
+}
+
+// Checkpoint publishes the checkpoint directory of the execution through the task's publisher,
+// and records it as the execution's last checkpoint with the given sequence.
+func (c *Checkpointer) Checkpoint(ctx context.Context, execution *models.Execution, sequence int) (*models.Checkpoint, error) {
+	jobPublisher, err := c.publishers.Get(ctx, execution.Job.Task().Publisher.Type)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get publisher %s: %w", execution.Job.Task().Publisher.Type, err)
+	}
+
+	// checkpoints are published under a name of their own, so that they neither overwrite
+	// each other while being restored, nor the results of the execution
+	checkpointExecution := *execution
+	checkpointExecution.ID = fmt.Sprintf("%s-checkpoint-%d", execution.ID, sequence)
+	dir := CheckpointDir(c.resultsPath.ExecutionOutputDir(execution.ID))
+	result, err := jobPublisher.PublishResult(ctx, &checkpointExecution, dir)
+	if err != nil {
+		return nil, bacerrors.Wrap(err, "failed to publish checkpoint")
+	}
+
+	checkpoint := &models.Checkpoint{
+		Result:     &result,
+		Sequence:   sequence,
+		CreateTime: time.Now().UTC().UnixNano(),
+	}
+	if err = c.store.UpdateExecutionState(ctx, store.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		Condition: store.UpdateExecutionCondition{
+			ExpectedStates: []models.ExecutionStateType{models.ExecutionStateRunning},
+		},
+		NewValues: models.Execution{
+			Checkpoint: checkpoint,
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// dirSnapshot summarizes the files of a directory to detect changes
+type dirSnapshot struct {
+	files   int
+	size    int64
+	modTime int64
+}
+
+// snapshotDir returns a summary of the regular files in a directory and its subdirectories
+func snapshotDir(dir string) (dirSnapshot, error) {
+	var snapshot dirSnapshot
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			// removed while walking the directory
+			return nil
+		} else if err != nil {
+			return err
+		}
+		snapshot.files++
+		snapshot.size += info.Size()
+		snapshot.modTime = max(snapshot.modTime, info.ModTime().UnixNano())
+		return nil
+	})
+	return snapshot, err
+}
@@ -0,0 +1,160 @@
+//go:build unit || !integration
+
+package compute_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store/boltdb"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/noop"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type CheckpointerTestSuite struct {
+	suite.Suite
+	ctx          context.Context
+	database     store.ExecutionStore
+	resultsPath  *compute.ResultsPath
+	checkpointer *compute.Checkpointer
+
+	mu        sync.Mutex
+	published []string
+}
+
+func TestCheckpointerTestSuite(t *testing.T) {
+	suite.Run(t, new(CheckpointerTestSuite))
+}
+
+func (s *CheckpointerTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	var err error
+	s.database, err = boltdb.NewStore(s.ctx, filepath.Join(s.T().TempDir(), "checkpointer-test.db"))
+	s.Require().NoError(err)
+	s.resultsPath, err = compute.NewResultsPath(s.T().TempDir())
+	s.Require().NoError(err)
+
+	s.published = nil
+	testPublisher := noop.NewNoopPublisherWithConfig(noop.PublisherConfig{
+		ExternalHooks: noop.PublisherExternalHooks{
+			PublishResult: func(ctx context.Context, execution *models.Execution, resultPath string) (models.SpecConfig, error) {
+				s.mu.Lock()
+				defer s.mu.Unlock()
+				s.published = append(s.published, execution.ID)
+				return models.SpecConfig{Type: models.PublisherNoop, Params: map[string]interface{}{"ID": execution.ID}}, nil
+			},
+		},
+	})
+	s.checkpointer = compute.NewCheckpointer(compute.CheckpointerParams{
+		Store:       s.database,
+		Publishers:  provider.NewMappedProvider(map[string]publisher.Publisher{models.PublisherNoop: testPublisher}),
+		ResultsPath: *s.resultsPath,
+	})
+}
+
+func (s *CheckpointerTestSuite) TearDownTest() {
+	s.database.Close(s.ctx)
+}
+
+// runningExecution creates a running execution that checkpoints at the given interval,
+// with a file in its checkpoint directory
+func (s *CheckpointerTestSuite) runningExecution(interval int64) *models.Execution {
+	job := mock.Job()
+	job.Task().Publisher = &models.SpecConfig{Type: models.PublisherNoop}
+	job.Task().Checkpoint = &models.CheckpointConfig{}
+	execution := mock.ExecutionForJob(job)
+	s.Require().NoError(s.database.CreateExecution(s.ctx, *execution))
+	// set after the execution is validated, as it may be shorter than the minimum interval to not wait for it in tests
+	job.Task().Checkpoint.Interval = interval
+	s.Require().NoError(s.database.UpdateExecutionState(s.ctx, store.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		NewValues: models.Execution{
+			ComputeState: models.NewExecutionState(models.ExecutionStateRunning),
+		},
+	}))
+
+	executionDir, err := s.resultsPath.PrepareExecutionOutputDir(execution.ID)
+	s.Require().NoError(err)
+	dir := compute.CheckpointDir(executionDir)
+	s.Require().NoError(os.Mkdir(dir, 0o755))
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "state"), []byte("step 1"), 0o644))
+	return execution
+}
+
+func (s *CheckpointerTestSuite) publishedIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.published...)
+}
+
+func (s *CheckpointerTestSuite) TestCheckpoint() {
+	execution := s.runningExecution(0)
+
+	checkpoint, err := s.checkpointer.Checkpoint(s.ctx, execution, 1)
+	s.Require().NoError(err)
+	s.Equal(1, checkpoint.Sequence)
+	s.Equal([]string{execution.ID + "-checkpoint-1"}, s.publishedIDs())
+
+	stored, err := s.database.GetExecution(s.ctx, execution.ID)
+	s.Require().NoError(err)
+	s.Equal(models.ExecutionStateRunning, stored.ComputeState.StateType)
+	s.Require().NotNil(stored.Checkpoint)
+	s.Equal(checkpoint.Sequence, stored.Checkpoint.Sequence)
+	s.Equal(execution.ID+"-checkpoint-1", stored.Checkpoint.Result.Params["ID"])
+}
+
+func (s *CheckpointerTestSuite) TestCheckpointNotRunning() {
+	execution := s.runningExecution(0)
+	s.Require().NoError(s.database.UpdateExecutionState(s.ctx, store.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		NewValues: models.Execution{
+			ComputeState: models.NewExecutionState(models.ExecutionStatePublishing),
+		},
+	}))
+
+	_, err := s.checkpointer.Checkpoint(s.ctx, execution, 1)
+	s.Error(err)
+
+	stored, err := s.database.GetExecution(s.ctx, execution.ID)
+	s.Require().NoError(err)
+	s.Nil(stored.Checkpoint)
+}
+
+func (s *CheckpointerTestSuite) TestRun() {
+	execution := s.runningExecution(1)
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.checkpointer.Run(ctx, execution)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	s.Eventually(func() bool {
+		return len(s.publishedIDs()) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// unchanged checkpoint directories are not published again
+	time.Sleep(1500 * time.Millisecond)
+	s.Equal([]string{execution.ID + "-checkpoint-1"}, s.publishedIDs())
+
+	stored, err := s.database.GetExecution(s.ctx, execution.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(stored.Checkpoint)
+	s.Equal(1, stored.Checkpoint.Sequence)
+}
@@ -0,0 +1,126 @@
+package execstream
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models/messages"
+)
+
+// defaultBuffer is the default size of the channel buffer of each exec stream
+const defaultBuffer = 100
+
+type ServerParams struct {
+	ExecutionStore store.ExecutionStore
+	Executors      executor.ExecProvider
+	Buffer         int // If not set (0), defaultBuffer will be used.
+}
+
+type server struct {
+	executionStore store.ExecutionStore
+	executors      executor.ExecProvider
+	buffer         int
+}
+
+// NewServer creates a new exec stream server
+func NewServer(params ServerParams) Server {
+	if params.Buffer <= 0 {
+		params.Buffer = defaultBuffer
+	}
+	return &server{
+		executionStore: params.ExecutionStore,
+		executors:      params.Executors,
+		buffer:         params.Buffer,
+	}
+}
+
+// Exec runs the command of the request inside its running execution, and streams its output
+func (s *server) Exec(ctx context.Context, request messages.ExecRequest, stdin io.Reader) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	execution, err := s.executionStore.GetExecution(ctx, request.ExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	if execution.ComputeState.StateType != models.ExecutionStateRunning {
+		return nil, bacerrors.Newf("execution %s is not running", execution.ID).
+			WithCode(bacerrors.BadRequestError)
+	}
+
+	engineType := execution.Job.Task().Engine.Type
+	exe, err := s.executors.Get(ctx, engineType)
+	if err != nil {
+		return nil, err
+	}
+	execer, ok := exe.(executor.Execer)
+	if !ok {
+		return nil, bacerrors.Newf("engine %s does not support exec", engineType).
+			WithCode(executor.ExecNotSupported)
+	}
+
+	log.Ctx(ctx).Debug().
+		Str("execution", execution.ID).
+		Strs("command", request.Command).
+		Msg("starting exec stream")
+
+	out := make(chan *concurrency.AsyncResult[models.ExecOutput], s.buffer)
+	go func() {
+		defer close(out)
+		if !send(ctx, out, concurrency.NewAsyncValue(models.ExecOutput{Type: models.ExecOutputStarted})) {
+			return
+		}
+		exitCode, err := execer.Exec(ctx, &executor.ExecRequest{
+			ExecutionID: execution.ID,
+			Command:     request.Command,
+			Stdin:       stdin,
+			Stdout:      &outputWriter{ctx: ctx, out: out, outputType: models.ExecOutputStdout},
+			Stderr:      &outputWriter{ctx: ctx, out: out, outputType: models.ExecOutputStderr},
+		})
+		if err != nil {
+			send(ctx, out, concurrency.NewAsyncError[models.ExecOutput](err))
+			return
+		}
+		send(ctx, out, concurrency.NewAsyncValue(models.ExecOutput{Type: models.ExecOutputExit, ExitCode: exitCode}))
+	}()
+	return out, nil
+}
+
+// send sends a result to the stream, and returns false if the context is done before it is sent
+func send(ctx context.Context, out chan<- *concurrency.AsyncResult[models.ExecOutput],
+	result *concurrency.AsyncResult[models.ExecOutput]) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case out <- result:
+		return true
+	}
+}
+
+// outputWriter sends everything written to it to the stream as outputs of a given type
+type outputWriter struct {
+	ctx        context.Context
+	out        chan<- *concurrency.AsyncResult[models.ExecOutput]
+	outputType models.ExecOutputType
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	// copy the data, as the caller may reuse the buffer once Write returns
+	data := append([]byte(nil), p...)
+	if !send(w.ctx, w.out, concurrency.NewAsyncValue(models.ExecOutput{Type: w.outputType, Data: data})) {
+		return 0, fmt.Errorf("exec stream closed: %w", w.ctx.Err())
+	}
+	return len(p), nil
+}
+
+// compile time check
+var _ Server = &server{}
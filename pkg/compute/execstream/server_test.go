@@ -0,0 +1,152 @@
+//go:build unit || !integration
+
+package execstream_test
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/execstream"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store/boltdb"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	noopexecutor "github.com/bacalhau-project/bacalhau/pkg/executor/noop"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models/messages"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type ServerTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	database store.ExecutionStore
+	server   execstream.Server
+	requests chan *executor.ExecRequest
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
+
+func (s *ServerTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	var err error
+	s.database, err = boltdb.NewStore(s.ctx, filepath.Join(s.T().TempDir(), "execstream-test.db"))
+	s.Require().NoError(err)
+
+	s.requests = make(chan *executor.ExecRequest, 1)
+	exe := noopexecutor.NewNoopExecutorWithConfig(noopexecutor.ExecutorConfig{
+		ExternalHooks: noopexecutor.ExecutorConfigExternalHooks{
+			Exec: func(ctx context.Context, request *executor.ExecRequest) (int, error) {
+				s.requests <- request
+				if request.Stdin != nil {
+					if _, err := io.Copy(request.Stdout, request.Stdin); err != nil {
+						return 0, err
+					}
+				}
+				_, _ = request.Stdout.Write([]byte("out"))
+				_, _ = request.Stderr.Write([]byte("err"))
+				return 3, nil
+			},
+		},
+	})
+	s.server = execstream.NewServer(execstream.ServerParams{
+		ExecutionStore: s.database,
+		Executors:      provider.NewMappedProvider(map[string]executor.Executor{models.EngineNoop: exe}),
+	})
+}
+
+func (s *ServerTestSuite) TearDownTest() {
+	s.database.Close(s.ctx)
+}
+
+func (s *ServerTestSuite) createExecution(state models.ExecutionStateType) *models.Execution {
+	execution := mock.ExecutionForJob(mock.Job())
+	s.Require().NoError(s.database.CreateExecution(s.ctx, *execution))
+	s.Require().NoError(s.database.UpdateExecutionState(s.ctx, store.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		NewValues: models.Execution{
+			ComputeState: models.NewExecutionState(state),
+		},
+	}))
+	return execution
+}
+
+// collect reads the stream until it is closed
+func (s *ServerTestSuite) collect(ch <-chan *concurrency.AsyncResult[models.ExecOutput]) []models.ExecOutput {
+	var outputs []models.ExecOutput
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case result, ok := <-ch:
+			if !ok {
+				return outputs
+			}
+			s.Require().NoError(result.Err)
+			outputs = append(outputs, result.Value)
+		case <-timeout:
+			s.FailNow("timed out waiting for exec stream to close")
+		}
+	}
+}
+
+func (s *ServerTestSuite) TestExec() {
+	execution := s.createExecution(models.ExecutionStateRunning)
+	ch, err := s.server.Exec(s.ctx, messages.ExecRequest{
+		ExecutionID: execution.ID,
+		Command:     []string{"ls", "-la"},
+	}, nil)
+	s.Require().NoError(err)
+
+	s.Equal([]models.ExecOutput{
+		{Type: models.ExecOutputStarted},
+		{Type: models.ExecOutputStdout, Data: []byte("out")},
+		{Type: models.ExecOutputStderr, Data: []byte("err")},
+		{Type: models.ExecOutputExit, ExitCode: 3},
+	}, s.collect(ch))
+
+	request := <-s.requests
+	s.Equal(execution.ID, request.ExecutionID)
+	s.Equal([]string{"ls", "-la"}, request.Command)
+	s.Nil(request.Stdin)
+}
+
+func (s *ServerTestSuite) TestExecWithStdin() {
+	execution := s.createExecution(models.ExecutionStateRunning)
+	ch, err := s.server.Exec(s.ctx, messages.ExecRequest{
+		ExecutionID: execution.ID,
+		Command:     []string{"cat"},
+	}, strings.NewReader("hello"))
+	s.Require().NoError(err)
+
+	outputs := s.collect(ch)
+	s.Require().Len(outputs, 5)
+	s.Equal(models.ExecOutput{Type: models.ExecOutputStdout, Data: []byte("hello")}, outputs[1])
+	s.Equal(models.ExecOutput{Type: models.ExecOutputExit, ExitCode: 3}, outputs[4])
+}
+
+func (s *ServerTestSuite) TestExecNotRunning() {
+	execution := s.createExecution(models.ExecutionStateBidAccepted)
+	_, err := s.server.Exec(s.ctx, messages.ExecRequest{
+		ExecutionID: execution.ID,
+		Command:     []string{"ls"},
+	}, nil)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "is not running")
+}
+
+func (s *ServerTestSuite) TestExecUnknownExecution() {
+	_, err := s.server.Exec(s.ctx, messages.ExecRequest{
+		ExecutionID: "unknown",
+		Command:     []string{"ls"},
+	}, nil)
+	s.Require().Error(err)
+}
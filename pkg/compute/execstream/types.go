@@ -0,0 +1,19 @@
+package execstream
+
+import (
+	"context"
+	"io"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models/messages"
+)
+
+// Server is an interface for running commands inside running executions, or attaching to them
+type Server interface {
+	// Exec runs the command of the request inside its execution with the given input, which can be nil,
+	// and returns a stream of the command's output. The stream starts with an ExecOutputStarted output
+	// once the command is ready to receive input, and ends with an ExecOutputExit output.
+	Exec(ctx context.Context, request messages.ExecRequest, stdin io.Reader) (
+		<-chan *concurrency.AsyncResult[models.ExecOutput], error)
+}
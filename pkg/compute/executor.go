@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 
 	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
@@ -23,6 +26,8 @@ import (
 const (
 	StorageDirectoryPerms     = 0o755
 	executionRootCleanupDelay = 1 * time.Hour
+	// timedOutExecutionWaitTimeout bounds the wait for an execution that timed out to stop, to record its usage
+	timedOutExecutionWaitTimeout = 10 * time.Second
 )
 
 type BaseExecutorParams struct {
@@ -36,6 +41,9 @@ type BaseExecutorParams struct {
 	FailureInjectionConfig models.FailureInjectionConfig
 	EnvResolver            EnvVarResolver
 	PortAllocator          PortAllocator
+	// ResultStreamer publishes the final update of the results streamed by executions whose task enables
+	// streaming once they complete. If not provided, the final update is not published.
+	ResultStreamer *ResultStreamer
 
 	// TODO: this is a temporary solution and should be replaced with a more generic
 	//  solution to populate jobs with default resources and network config.
@@ -56,6 +64,7 @@ type BaseExecutor struct {
 	failureInjection   models.FailureInjectionConfig
 	envResolver        EnvVarResolver
 	portAllocator      PortAllocator
+	resultStreamer     *ResultStreamer
 	defaultNetworkType models.Network
 }
 
@@ -71,6 +80,7 @@ func NewBaseExecutor(params BaseExecutorParams) *BaseExecutor {
 		resultsPath:        params.ResultsPath,
 		envResolver:        params.EnvResolver,
 		portAllocator:      params.PortAllocator,
+		resultStreamer:     params.ResultStreamer,
 		defaultNetworkType: params.DefaultNetworkType,
 	}
 }
@@ -137,12 +147,22 @@ func (e *BaseExecutor) PrepareRunArguments(
 		networkConfig.Type = e.defaultNetworkType
 	}
 
+	// the checkpoint directory is collected as an output of its own, which is published while the task runs
+	outputs := execution.Job.Task().ResultPaths
+	if execution.Job.Task().Checkpoint != nil {
+		outputs = append(slices.Clone(outputs), &models.ResultPath{
+			Name: models.CheckpointResultName,
+			Path: models.CheckpointPath,
+		})
+	}
+
 	return &executor.RunCommandRequest{
 			JobID:        execution.Job.ID,
+			Namespace:    execution.Job.Namespace,
 			ExecutionID:  execution.ID,
 			Resources:    execution.TotalAllocatedResources(),
 			Network:      networkConfig,
-			Outputs:      execution.Job.Task().ResultPaths,
+			Outputs:      outputs,
 			Inputs:       inputVolumes,
 			ExecutionDir: executionDir,
 			EngineParams: execution.Job.Task().Engine,
@@ -258,10 +278,12 @@ func (e *BaseExecutor) Run(ctx context.Context, execution *models.Execution) (er
 
 	stopwatch := telemetry.Timer(ctx, jobDurationMilliseconds, execution.Job.MetricAttributes()...)
 	topic := EventTopicExecutionRunning
+	// usage is recorded with the failure if the execution fails after it ran
+	var usage *models.ResourceUsage
 	defer func() {
 		if err != nil {
 			if !bacerrors.IsErrorWithCode(err, executor.ExecutionAlreadyCancelled) {
-				e.handleFailure(ctx, execution, err, topic)
+				e.handleFailure(ctx, execution, err, topic, usage)
 			}
 		}
 		dur := stopwatch()
@@ -274,6 +296,11 @@ func (e *BaseExecutor) Run(ctx context.Context, execution *models.Execution) (er
 
 	res := e.Start(ctx, execution)
 
+	if e.resultStreamer != nil && execution.Job.Task().Streaming != nil {
+		// the partial results are no longer needed once the execution ended and its results were published
+		defer e.resultStreamer.Cleanup(ctx, execution)
+	}
+
 	defer func(executionOutputDir string) {
 		// For now execution results are removed after a fixed delay.
 		go func() {
@@ -326,15 +353,24 @@ func (e *BaseExecutor) Run(ctx context.Context, execution *models.Execution) (er
 			// become the default since canceling the context will simply result in the RPC connection closing (I think)
 			// The general solution here is to stop using contexts for canceling jobs and to instead make explicit calls
 			// the an executors `Cancel` method.
+			usage = e.recordUsage(ctx, execution, e.waitTimedOut(ctx, execution))
 			return NewErrExecTimeout(execution.Job.Task().Timeouts.GetExecutionTimeout())
 		}
 		return err
 	}
+	usage = e.recordUsage(ctx, execution, result)
 	if result.ErrorMsg != "" {
 		return fmt.Errorf("%s", result.ErrorMsg)
 	}
 	jobsCompleted.Add(ctx, 1)
 
+	if e.resultStreamer != nil && execution.Job.Task().Streaming != nil {
+		// the results are still published as a whole below, so a failed final update doesn't fail the execution
+		if _, err = e.resultStreamer.Complete(ctx, execution); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("execution", execution.ID).Msg("failed to publish final partial result of execution")
+		}
+	}
+
 	expectedState := models.ExecutionStateRunning
 	var publishedResult *models.SpecConfig
 
@@ -347,8 +383,9 @@ func (e *BaseExecutor) Run(ctx context.Context, execution *models.Execution) (er
 				ExpectedStates: []models.ExecutionStateType{expectedState},
 			},
 			NewValues: models.Execution{
-				ComputeState: models.NewExecutionState(models.ExecutionStatePublishing),
-				RunOutput:    result,
+				ComputeState:  models.NewExecutionState(models.ExecutionStatePublishing),
+				RunOutput:     result,
+				ResourceUsage: result.ResourceUsage,
 			},
 		}); err != nil {
 			return err
@@ -356,7 +393,14 @@ func (e *BaseExecutor) Run(ctx context.Context, execution *models.Execution) (er
 
 		expectedState = models.ExecutionStatePublishing
 
-		resultsDir := ExecutionResultsDir(e.resultsPath.ExecutionOutputDir(execution.ID))
+		executionOutputDir := e.resultsPath.ExecutionOutputDir(execution.ID)
+		resultsDir := ExecutionResultsDir(executionOutputDir)
+		if execution.Job.Task().Checkpoint != nil {
+			// checkpoints are only needed to resume the execution, and are not part of its results
+			if err = os.RemoveAll(CheckpointDir(executionOutputDir)); err != nil {
+				return err
+			}
+		}
 		publishedResult, err = e.publish(ctx, execution, resultsDir)
 		if err != nil {
 			return err
@@ -385,6 +429,7 @@ func (e *BaseExecutor) Run(ctx context.Context, execution *models.Execution) (er
 			ComputeState:    models.NewExecutionState(models.ExecutionStateCompleted),
 			PublishedResult: publishedResult,
 			RunOutput:       result,
+			ResourceUsage:   result.ResourceUsage,
 		},
 		Events: []*models.Event{ExecCompletedEvent()},
 	}); err != nil {
@@ -394,6 +439,44 @@ func (e *BaseExecutor) Run(ctx context.Context, execution *models.Execution) (er
 	return err
 }
 
+// waitTimedOut waits for an execution that timed out to be stopped by its executor, as the timeout also
+// cancels the context it was started with, and returns its result. It returns an empty result if the
+// execution doesn't stop in time.
+func (e *BaseExecutor) waitTimedOut(ctx context.Context, execution *models.Execution) *models.RunCommandResult {
+	waitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timedOutExecutionWaitTimeout)
+	defer cancel()
+	result, err := e.Wait(waitCtx, execution)
+	if err != nil || result == nil {
+		return &models.RunCommandResult{}
+	}
+	return result
+}
+
+// recordUsage completes the resource usage sampled by the executor with the disk space written to
+// the output directory of the execution, records it as metrics, and returns it.
+func (e *BaseExecutor) recordUsage(
+	ctx context.Context, execution *models.Execution, result *models.RunCommandResult) *models.ResourceUsage {
+	if result.ResourceUsage == nil {
+		result.ResourceUsage = &models.ResourceUsage{}
+	}
+	usage := result.ResourceUsage
+	size, err := e.resultsPath.ExecutionOutputDirSize(execution.ID)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to measure the disk usage of the execution")
+	}
+	usage.Disk += size
+
+	attrs := metric.WithAttributes(append(execution.Job.MetricAttributes(),
+		attribute.String("namespace", execution.Namespace))...)
+	// executors that can't measure the CPU time of executions leave it unset
+	if usage.CPUSeconds > 0 {
+		executionCPUSeconds.Record(ctx, usage.CPUSeconds, attrs)
+	}
+	executionPeakMemoryBytes.Record(ctx, int64(usage.PeakMemory), attrs) //nolint:gosec // memory sizes fit in int64
+	executionDiskBytes.Record(ctx, int64(usage.Disk), attrs)             //nolint:gosec // disk sizes fit in int64
+	return usage
+}
+
 // Publish the result of an execution after it has been verified.
 func (e *BaseExecutor) publish(ctx context.Context, execution *models.Execution,
 	resultsDir string,
@@ -425,13 +508,15 @@ func (e *BaseExecutor) Cancel(ctx context.Context, execution *models.Execution)
 	return exe.Cancel(ctx, execution.ID)
 }
 
-func (e *BaseExecutor) handleFailure(ctx context.Context, execution *models.Execution, err error, topic models.EventTopic) {
+func (e *BaseExecutor) handleFailure(
+	ctx context.Context, execution *models.Execution, err error, topic models.EventTopic, usage *models.ResourceUsage) {
 	log.Ctx(ctx).Warn().Err(err).Msgf("%s failed", topic)
 
 	updateError := e.store.UpdateExecutionState(ctx, store.UpdateExecutionRequest{
 		ExecutionID: execution.ID,
 		NewValues: models.Execution{
-			ComputeState: models.NewExecutionState(models.ExecutionStateFailed).WithMessage(err.Error()),
+			ComputeState:  models.NewExecutionState(models.ExecutionStateFailed).WithMessage(err.Error()),
+			ResourceUsage: usage,
 		},
 		Events: []*models.Event{models.NewEvent(topic).WithError(err)},
 	})
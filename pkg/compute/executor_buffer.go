@@ -6,11 +6,11 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
 
 	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
-	"github.com/bacalhau-project/bacalhau/pkg/lib/collections"
 	"github.com/bacalhau-project/bacalhau/pkg/logger"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
@@ -34,38 +34,65 @@ type ExecutorBufferParams struct {
 	Store                  store.ExecutionStore
 	RunningCapacityTracker capacity.Tracker
 	EnqueuedUsageTracker   capacity.UsageTracker
+	// Checkpointer publishes the checkpoints of running executions whose task enables checkpointing.
+	// If not provided, checkpoints are not published.
+	Checkpointer *Checkpointer
+	// ResultStreamer publishes the results of running executions whose task enables streaming.
+	// If not provided, results are only published once executions complete.
+	ResultStreamer *ResultStreamer
+	// NamespaceWeights is the share of the node each namespace is entitled to relative to the others.
+	// Namespaces that are not listed have a weight of 1.
+	NamespaceWeights map[string]int
+	// AgingInterval is how long an execution waits in the queue before its priority is raised by one.
+	// Aging is disabled if zero.
+	AgingInterval time.Duration
+	// ReservationThreshold is how long the first queued execution that doesn't fit in the available capacity
+	// waits before later executions stop being run ahead of it. Executions are always run ahead if zero.
+	ReservationThreshold time.Duration
 }
 
 // ExecutorBuffer is a backend.Executor implementation that buffers executions locally until enough capacity is
 // available to be able to run them. The buffer accepts a delegate backend.Executor that will be used to run the jobs.
-// The buffer is implemented as a FIFO queue, where the order of the executions is determined by the order in which
-// they were enqueued. However, an execution with high resource usage requirements might be skipped if there are newer
-// jobs with lower resource usage requirements that can be executed immediately. This is done to improve utilization
-// of compute nodes, though it might result in starvation and should be re-evaluated in the future.
+// Queued executions are ordered by the priority of their job and the fair share of the node of their namespace,
+// with aging to avoid starving executions of low priority (see queuePolicy). However, an execution with high resource
+// usage requirements might be skipped if there are later executions with lower resource usage requirements that can
+// be executed immediately. This is done to improve utilization of compute nodes, though it might result in starvation
+// of the skipped execution. Once it has waited for the reservation threshold, later executions are no longer run
+// ahead of it, so that the capacity freed by the running executions is reserved for it.
 type ExecutorBuffer struct {
 	ID               string
 	runningCapacity  capacity.Tracker
 	enqueuedCapacity capacity.UsageTracker
 	delegateService  Executor
 	store            store.ExecutionStore
+	checkpointer     *Checkpointer
+	resultStreamer   *ResultStreamer
+	policy           queuePolicy
 	running          map[string]*bufferTask
-	queuedTasks      *collections.HashedPriorityQueue[string, *bufferTask]
+	queued           map[string]*bufferTask
 	mu               sync.Mutex
+
+	// reservationThreshold is how long a skipped execution waits before capacity is reserved for it
+	reservationThreshold time.Duration
 }
 
 func NewExecutorBuffer(params ExecutorBufferParams) *ExecutorBuffer {
-	indexer := func(b *bufferTask) string {
-		return b.execution.ID
-	}
-
 	r := &ExecutorBuffer{
 		ID:               params.ID,
 		runningCapacity:  params.RunningCapacityTracker,
 		enqueuedCapacity: params.EnqueuedUsageTracker,
 		delegateService:  params.DelegateExecutor,
 		store:            params.Store,
-		running:          make(map[string]*bufferTask),
-		queuedTasks:      collections.NewHashedPriorityQueue[string, *bufferTask](indexer),
+		checkpointer:     params.Checkpointer,
+		resultStreamer:   params.ResultStreamer,
+		policy: queuePolicy{
+			namespaceWeights: params.NamespaceWeights,
+			agingInterval:    params.AgingInterval,
+		},
+		running: make(map[string]*bufferTask),
+		queued:  make(map[string]*bufferTask),
+
+		reservationThreshold: params.ReservationThreshold,
 	}
 
 	return r
@@ -100,7 +127,7 @@ func (s *ExecutorBuffer) Run(ctx context.Context, execution *models.Execution) e
 		return err
 	}
 
-	if s.queuedTasks.Contains(execution.ID) {
+	if _, ok := s.queued[execution.ID]; ok {
 		err = bacerrors.Newf("execution %s already enqueued", execution.ID)
 		return err
 	}
@@ -109,7 +136,7 @@ func (s *ExecutorBuffer) Run(ctx context.Context, execution *models.Execution) e
 		return err
 	}
 	s.enqueuedCapacity.Add(ctx, *execution.TotalAllocatedResources())
-	s.queuedTasks.Enqueue(newBufferTask(execution), int64(execution.Job.Priority))
+	s.queued[execution.ID] = newBufferTask(execution)
 	s.deque()
 	return err
 }
@@ -137,9 +164,14 @@ func (s *ExecutorBuffer) doRun(ctx context.Context, task *bufferTask) {
 		ch <- s.delegateService.Run(innerCtx, task.execution)
 	}()
 
+	stopCheckpoints := s.startCheckpoints(innerCtx, task.execution)
+	stopResultStreaming := s.startResultStreaming(innerCtx, task.execution)
+
 	// no need to check for run errors as they are already handled by the delegate backend.Executor and
 	// to the callback.
 	<-ch
+	stopCheckpoints()
+	stopResultStreaming()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -148,52 +180,107 @@ func (s *ExecutorBuffer) doRun(ctx context.Context, task *bufferTask) {
 	s.deque()
 }
 
-// deque tries to run the next execution in the queue if there is enough capacity.
+// startCheckpoints periodically publishes the checkpoints of the execution while it runs, if its task enables
+// checkpointing. It returns a function that stops publishing checkpoints, and waits for any in progress to finish.
+func (s *ExecutorBuffer) startCheckpoints(ctx context.Context, execution *models.Execution) func() {
+	if s.checkpointer == nil || execution.Job.Task().Checkpoint == nil {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.checkpointer.Run(ctx, execution)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// startResultStreaming periodically publishes the results of the execution while it runs, if its task enables
+// streaming. It returns a function that stops publishing results, and waits for any in progress to finish.
+func (s *ExecutorBuffer) startResultStreaming(ctx context.Context, execution *models.Execution) func() {
+	if s.resultStreamer == nil || execution.Job.Task().Streaming == nil {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.resultStreamer.Run(ctx, execution)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// deque runs the next executions in the queue for as long as there is enough capacity.
 // It is called every time a job is finished or enqueued, where a lock is already held.
-// TODO: We loop through the queue every time a job runs or finishes, which is not very efficient.
+// TODO: We order the queue every time a job runs or finishes, which is not very efficient.
 func (s *ExecutorBuffer) deque() {
 	ctx := context.Background()
 
-	// There are at most max matches, so try at most that many times
-	max := s.queuedTasks.Len()
-	for i := 0; i < max; i++ {
-		qItem := s.queuedTasks.DequeueWhere(func(task *bufferTask) bool {
-			// If we don't have enough resources to run this task, then we will skip it
-			queuedResources := task.execution.TotalAllocatedResources()
-			allocatedResources := s.runningCapacity.AddIfHasCapacity(ctx, *queuedResources)
-			if allocatedResources == nil {
-				return false
-			}
-
-			// Update the execution to include all the resources that have
-			// actually been allocated
-			task.execution.AllocateResources(
-				task.execution.Job.Task().Name,
-				*allocatedResources,
-			)
-
-			// Claim the resources now so that we don't count queued resources
-			s.enqueuedCapacity.Remove(ctx, *queuedResources)
-			return true
-		})
-
-		if qItem == nil {
+	for {
+		task := s.claimNext(ctx)
+		if task == nil {
 			// We didn't find anything in the queue that matches our resource availability so we will
 			// break out of this look as there is nothing else to find
 			break
 		}
 
-		task := qItem.Value
-
 		// Move the execution to the running list and remove from the list of enqueued IDs
 		// before we actually run the task
 		execID := task.execution.ID
+		delete(s.queued, execID)
 		s.running[execID] = task
 
 		go s.doRun(logger.ContextWithNodeIDLogger(context.Background(), s.ID), task)
 	}
 }
 
+// claimNext allocates the resources of the first queued execution, in the order of the queue, that fits in the
+// available capacity and returns it. It returns nil if no queued execution fits, or if an execution that doesn't
+// fit has waited for the reservation threshold, as the executions after it are not run ahead of it anymore.
+func (s *ExecutorBuffer) claimNext(ctx context.Context) *bufferTask {
+	now := time.Now()
+	for _, ranked := range s.orderQueue(now) {
+		task := ranked.task
+		// If we don't have enough resources to run this task, then we will skip it,
+		// unless it waited long enough for the capacity to be reserved for it
+		queuedResources := task.execution.TotalAllocatedResources()
+		allocatedResources := s.runningCapacity.AddIfHasCapacity(ctx, *queuedResources)
+		if allocatedResources == nil {
+			if s.reservationThreshold > 0 && now.Sub(task.enqueuedAt) >= s.reservationThreshold {
+				return nil
+			}
+			continue
+		}
+
+		// Update the execution to include all the resources that have
+		// actually been allocated
+		task.execution.AllocateResources(
+			task.execution.Job.Task().Name,
+			*allocatedResources,
+		)
+
+		// Claim the resources now so that we don't count queued resources
+		s.enqueuedCapacity.Remove(ctx, *queuedResources)
+		return task
+	}
+	return nil
+}
+
+// orderQueue returns the queued executions in the order they should be run, where a lock is already held.
+func (s *ExecutorBuffer) orderQueue(now time.Time) []rankedTask {
+	running := make(map[string]int)
+	for _, task := range s.running {
+		running[task.execution.Job.Namespace]++
+	}
+	return s.policy.order(lo.Values(s.queued), running, now)
+}
+
 func (s *ExecutorBuffer) Cancel(_ context.Context, execution *models.Execution) error {
 	// TODO: Enqueue cancel tasks
 	go func() {
@@ -221,7 +308,31 @@ func (s *ExecutorBuffer) RunningExecutions() []*models.Execution {
 
 // EnqueuedExecutionsCount return number of items enqueued
 func (s *ExecutorBuffer) EnqueuedExecutionsCount() int {
-	return s.queuedTasks.Len()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queued)
+}
+
+// QueuedExecutions returns the executions waiting for enough capacity to run, in the order they are expected to run
+func (s *ExecutorBuffer) QueuedExecutions() []models.QueuedExecution {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ranked := s.orderQueue(time.Now())
+	queued := make([]models.QueuedExecution, len(ranked))
+	for i, r := range ranked {
+		execution := r.task.execution
+		queued[i] = models.QueuedExecution{
+			ExecutionID:       execution.ID,
+			JobID:             execution.JobID,
+			JobName:           execution.Job.Name,
+			Namespace:         execution.Job.Namespace,
+			Priority:          execution.Job.Priority,
+			EffectivePriority: r.effectivePriority,
+			EnqueuedAt:        r.task.enqueuedAt,
+			Resources:         execution.TotalAllocatedResources(),
+		}
+	}
+	return queued
 }
 
 func (s *ExecutorBuffer) mapValues(m map[string]*bufferTask) []*models.Execution {
@@ -236,3 +347,4 @@ func (s *ExecutorBuffer) mapValues(m map[string]*bufferTask) []*models.Execution
 
 // compile-time interface check
 var _ Executor = (*ExecutorBuffer)(nil)
+var _ models.ExecutionQueueProvider = (*ExecutorBuffer)(nil)
@@ -0,0 +1,192 @@
+//go:build unit || !integration
+
+package compute_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+// blockingExecutor runs executions until they are released, and records the order they started in
+type blockingExecutor struct {
+	started chan string
+	release chan struct{}
+}
+
+func (e *blockingExecutor) Run(ctx context.Context, execution *models.Execution) error {
+	e.started <- execution.ID
+	select {
+	case <-e.release:
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+func (e *blockingExecutor) Cancel(context.Context, *models.Execution) error {
+	return nil
+}
+
+type ExecutorBufferTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	delegate *blockingExecutor
+}
+
+func TestExecutorBufferTestSuite(t *testing.T) {
+	suite.Run(t, new(ExecutorBufferTestSuite))
+}
+
+func (s *ExecutorBufferTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.delegate = &blockingExecutor{
+		started: make(chan string, 10),
+		release: make(chan struct{}),
+	}
+}
+
+func (s *ExecutorBufferTestSuite) TearDownTest() {
+	close(s.delegate.release)
+}
+
+func (s *ExecutorBufferTestSuite) newBuffer(cpu float64, params compute.ExecutorBufferParams) *compute.ExecutorBuffer {
+	params.ID = "node"
+	params.DelegateExecutor = s.delegate
+	params.RunningCapacityTracker = capacity.NewLocalTracker(capacity.LocalTrackerParams{
+		MaxCapacity: models.Resources{CPU: cpu},
+	})
+	params.EnqueuedUsageTracker = capacity.NewLocalUsageTracker()
+	return compute.NewExecutorBuffer(params)
+}
+
+// run enqueues an execution of one CPU for a job of the namespace and priority
+func (s *ExecutorBufferTestSuite) run(buffer *compute.ExecutorBuffer, namespace string, priority int) string {
+	return s.runWithCPU(buffer, namespace, priority, 1)
+}
+
+// runWithCPU enqueues an execution of the given CPU for a job of the namespace and priority
+func (s *ExecutorBufferTestSuite) runWithCPU(buffer *compute.ExecutorBuffer, namespace string, priority int, cpu float64) string {
+	job := mock.Job()
+	job.Namespace = namespace
+	job.Priority = priority
+	execution := mock.ExecutionForJob(job)
+	execution.AllocateResources(job.Task().Name, models.Resources{CPU: cpu})
+	s.Require().NoError(buffer.Run(s.ctx, execution))
+	return execution.ID
+}
+
+func (s *ExecutorBufferTestSuite) waitStarted() string {
+	select {
+	case id := <-s.delegate.started:
+		return id
+	case <-time.After(5 * time.Second):
+		s.FailNow("timed out waiting for an execution to start")
+		return ""
+	}
+}
+
+func (s *ExecutorBufferTestSuite) queuedIDs(buffer *compute.ExecutorBuffer) []string {
+	return lo.Map(buffer.QueuedExecutions(), func(e models.QueuedExecution, _ int) string { return e.ExecutionID })
+}
+
+func (s *ExecutorBufferTestSuite) TestOrdersByPriority() {
+	buffer := s.newBuffer(1, compute.ExecutorBufferParams{})
+	running := s.run(buffer, "a", 0)
+	s.Equal(running, s.waitStarted())
+
+	low := s.run(buffer, "a", 1)
+	high := s.run(buffer, "a", 5)
+	s.Equal([]string{high, low}, s.queuedIDs(buffer))
+	s.Equal(2, buffer.EnqueuedExecutionsCount())
+
+	// the execution of highest priority runs next
+	s.delegate.release <- struct{}{}
+	s.Equal(high, s.waitStarted())
+	s.Equal([]string{low}, s.queuedIDs(buffer))
+}
+
+func (s *ExecutorBufferTestSuite) TestFairShareAcrossNamespaces() {
+	buffer := s.newBuffer(1, compute.ExecutorBufferParams{})
+	s.run(buffer, "a", 0)
+	s.waitStarted()
+
+	// namespace a already runs an execution, so b goes first even though it enqueued last
+	a1 := s.run(buffer, "a", 0)
+	a2 := s.run(buffer, "a", 0)
+	b1 := s.run(buffer, "b", 0)
+	s.Equal([]string{b1, a1, a2}, s.queuedIDs(buffer))
+}
+
+func (s *ExecutorBufferTestSuite) TestNamespaceWeights() {
+	buffer := s.newBuffer(2, compute.ExecutorBufferParams{
+		NamespaceWeights: map[string]int{"a": 2},
+	})
+	s.run(buffer, "a", 0)
+	s.run(buffer, "b", 0)
+	s.waitStarted()
+	s.waitStarted()
+
+	// both namespaces run an execution, but a is entitled to twice the share of b
+	b1 := s.run(buffer, "b", 0)
+	a1 := s.run(buffer, "a", 0)
+	s.Equal([]string{a1, b1}, s.queuedIDs(buffer))
+}
+
+func (s *ExecutorBufferTestSuite) TestAging() {
+	buffer := s.newBuffer(1, compute.ExecutorBufferParams{
+		AgingInterval: time.Millisecond,
+	})
+	s.run(buffer, "a", 0)
+	s.waitStarted()
+
+	low := s.run(buffer, "a", 0)
+	time.Sleep(10 * time.Millisecond)
+	high := s.run(buffer, "a", 5)
+
+	// the low priority execution aged up to the highest priority, and was enqueued first
+	queued := buffer.QueuedExecutions()
+	s.Require().Len(queued, 2)
+	s.Equal(low, queued[0].ExecutionID)
+	s.Equal(0, queued[0].Priority)
+	s.Equal(5, queued[0].EffectivePriority)
+	s.Equal(high, queued[1].ExecutionID)
+	s.Equal(5, queued[1].EffectivePriority)
+}
+
+func (s *ExecutorBufferTestSuite) TestRunsSmallerExecutionsAhead() {
+	buffer := s.newBuffer(2, compute.ExecutorBufferParams{})
+	s.run(buffer, "a", 0)
+	s.waitStarted()
+
+	// the large execution doesn't fit, so the smaller one queued after it runs first
+	large := s.runWithCPU(buffer, "a", 0, 2)
+	small := s.run(buffer, "a", 0)
+	s.Equal(small, s.waitStarted())
+	s.Equal([]string{large}, s.queuedIDs(buffer))
+}
+
+func (s *ExecutorBufferTestSuite) TestReservesCapacityForWaitingExecution() {
+	buffer := s.newBuffer(2, compute.ExecutorBufferParams{
+		ReservationThreshold: time.Millisecond,
+	})
+	s.run(buffer, "a", 0)
+	s.waitStarted()
+
+	// the large execution waited for the reservation threshold, so the smaller one doesn't run ahead of it
+	large := s.runWithCPU(buffer, "a", 0, 2)
+	time.Sleep(10 * time.Millisecond)
+	small := s.run(buffer, "a", 0)
+	s.Equal([]string{large, small}, s.queuedIDs(buffer))
+	s.Empty(s.delegate.started)
+
+	s.delegate.release <- struct{}{}
+	s.Equal(large, s.waitStarted())
+}
@@ -0,0 +1,131 @@
+//go:build unit || !integration
+
+package compute_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store/boltdb"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/noop"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type BaseExecutorTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	database store.ExecutionStore
+}
+
+func TestBaseExecutorTestSuite(t *testing.T) {
+	suite.Run(t, new(BaseExecutorTestSuite))
+}
+
+func (s *BaseExecutorTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	var err error
+	s.database, err = boltdb.NewStore(s.ctx, filepath.Join(s.T().TempDir(), "executor-test.db"))
+	s.Require().NoError(err)
+}
+
+func (s *BaseExecutorTestSuite) TearDownTest() {
+	s.database.Close(s.ctx)
+}
+
+// newExecutor creates a base executor running executions with the given job handler
+func (s *BaseExecutorTestSuite) newExecutor(handler noop.ExecutorHandlerJobHandler) *compute.BaseExecutor {
+	resultsPath, err := compute.NewResultsPath(s.T().TempDir())
+	s.Require().NoError(err)
+	portAllocator, err := compute.NewPortAllocator(models.MinimumPort, models.MaximumPort)
+	s.Require().NoError(err)
+	return compute.NewBaseExecutor(compute.BaseExecutorParams{
+		ID:    "test-node",
+		Store: s.database,
+		Executors: provider.NewMappedProvider(map[string]executor.Executor{
+			models.EngineNoop: noop.NewNoopExecutorWithConfig(noop.ExecutorConfig{
+				ExternalHooks: noop.ExecutorConfigExternalHooks{JobHandler: handler},
+			}),
+		}),
+		Storages:         provider.NewMappedProvider(map[string]storage.Storage{}),
+		Publishers:       provider.NewMappedProvider(map[string]publisher.Publisher{}),
+		StorageDirectory: s.T().TempDir(),
+		ResultsPath:      *resultsPath,
+		PortAllocator:    portAllocator,
+	})
+}
+
+// acceptedExecution creates an execution whose bid was accepted, ready to run
+func (s *BaseExecutorTestSuite) acceptedExecution() *models.Execution {
+	job := mock.Job()
+	job.Task().Engine = &models.SpecConfig{Type: models.EngineNoop}
+	job.Task().Publisher = nil
+	execution := mock.ExecutionForJob(job)
+	s.Require().NoError(s.database.CreateExecution(s.ctx, *execution))
+	s.Require().NoError(s.database.UpdateExecutionState(s.ctx, store.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		NewValues: models.Execution{
+			ComputeState: models.NewExecutionState(models.ExecutionStateBidAccepted),
+		},
+	}))
+	return execution
+}
+
+// writeOutput writes a file of the given size to the output directory of the execution
+func writeOutput(execContext noop.ExecutionContext, size int) error {
+	return os.WriteFile(filepath.Join(execContext.ExecutionDir, "output"), make([]byte, size), 0o644)
+}
+
+func (s *BaseExecutorTestSuite) TestRecordsUsageOfFailedExecution() {
+	e := s.newExecutor(func(_ context.Context, execContext noop.ExecutionContext) (*models.RunCommandResult, error) {
+		if err := writeOutput(execContext, 100); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("boom")
+	})
+	execution := s.acceptedExecution()
+
+	s.Require().Error(e.Run(s.ctx, execution))
+
+	stored, err := s.database.GetExecution(s.ctx, execution.ID)
+	s.Require().NoError(err)
+	s.Equal(models.ExecutionStateFailed, stored.ComputeState.StateType)
+	s.Require().NotNil(stored.ResourceUsage)
+	s.Equal(uint64(100), stored.ResourceUsage.Disk)
+}
+
+func (s *BaseExecutorTestSuite) TestRecordsUsageOfTimedOutExecution() {
+	e := s.newExecutor(func(ctx context.Context, execContext noop.ExecutionContext) (*models.RunCommandResult, error) {
+		if err := writeOutput(execContext, 200); err != nil {
+			return nil, err
+		}
+		// the execution is stopped by the timeout
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	execution := s.acceptedExecution()
+
+	ctx, cancel := context.WithTimeout(s.ctx, 100*time.Millisecond)
+	defer cancel()
+	err := e.Run(ctx, execution)
+	s.Require().Error(err)
+	s.ErrorContains(err, "timed out")
+
+	stored, err := s.database.GetExecution(s.ctx, execution.ID)
+	s.Require().NoError(err)
+	s.Equal(models.ExecutionStateFailed, stored.ComputeState.StateType)
+	s.Require().NotNil(stored.ResourceUsage)
+	s.Equal(uint64(200), stored.ResourceUsage.Disk)
+}
@@ -0,0 +1,82 @@
+package logstream
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// timestampFormat is RFC3339Nano in UTC with nanoseconds padded to 9 decimal places,
+// so that timestamps are always timestampLen long
+const timestampFormat = "2006-01-02T15:04:05.000000000Z"
+
+// TimestampedStdWriter multiplexes the standard output and error of a process into a single stream
+// of timestamped frames, in the format of Docker logs read by TimestampedStdCopy.
+// This allows executors that don't run Docker containers to serve logs the same way.
+type TimestampedStdWriter struct {
+	mu     sync.Mutex
+	dst    io.Writer
+	closed bool
+}
+
+// NewTimestampedStdWriter creates a writer of timestamped frames to dst
+func NewTimestampedStdWriter(dst io.Writer) *TimestampedStdWriter {
+	return &TimestampedStdWriter{dst: dst}
+}
+
+// Stdout returns a writer of standard output frames
+func (w *TimestampedStdWriter) Stdout() io.Writer {
+	return &timestampedStreamWriter{writer: w, streamType: stdcopy.Stdout}
+}
+
+// Stderr returns a writer of standard error frames
+func (w *TimestampedStdWriter) Stderr() io.Writer {
+	return &timestampedStreamWriter{writer: w, streamType: stdcopy.Stderr}
+}
+
+// Close writes a stream end frame, after which no more frames are written
+func (w *TimestampedStdWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	_, err := writeEndFrame(w.dst)
+	return err
+}
+
+// writeFrame writes p as a single frame of the given stream
+func (w *TimestampedStdWriter) writeFrame(streamType stdcopy.StdType, p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	frame := make([]byte, stdWriterPrefixLen, stdWriterPrefixLen+timestampLen+1+len(p))
+	frame[stdWriterFdIndex] = byte(streamType)
+	binary.BigEndian.PutUint32(frame[stdWriterSizeIndex:], uint32(timestampLen+1+len(p))) //nolint:gosec // writes are small
+	frame = time.Now().UTC().AppendFormat(frame, timestampFormat)
+	frame = append(frame, ' ')
+	frame = append(frame, p...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	if _, err := w.dst.Write(frame); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+type timestampedStreamWriter struct {
+	writer     *TimestampedStdWriter
+	streamType stdcopy.StdType
+}
+
+func (s *timestampedStreamWriter) Write(p []byte) (int, error) {
+	return s.writer.writeFrame(s.streamType, p)
+}
@@ -0,0 +1,61 @@
+//go:build unit || !integration
+
+package logstream
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestampedStdWriter_RoundTrip(t *testing.T) {
+	var stream bytes.Buffer
+	writer := NewTimestampedStdWriter(&stream)
+
+	start := time.Now()
+	_, err := writer.Stdout().Write([]byte("stdout 1\n"))
+	require.NoError(t, err)
+	_, err = writer.Stderr().Write([]byte("stderr 1\n"))
+	require.NoError(t, err)
+	_, err = writer.Stdout().Write([]byte("stdout 2\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	// the stream ends with an end frame, so following it returns once all frames are read
+	var output bytes.Buffer
+	_, err = TimestampedStdCopy(&output, &stream, &start, true, make(chan struct{}))
+	require.NoError(t, err)
+	assertStdCopyCompatibleOutput(t, &output, []byte("stdout 1\nstdout 2\n"), []byte("stderr 1\n"))
+}
+
+func TestTimestampedStdWriter_TimestampFiltering(t *testing.T) {
+	var stream bytes.Buffer
+	writer := NewTimestampedStdWriter(&stream)
+
+	_, err := writer.Stdout().Write([]byte("before"))
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	since := time.Now()
+	_, err = writer.Stdout().Write([]byte("after"))
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	_, err = TimestampedStdCopy(&output, &stream, &since, false, make(chan struct{}))
+	require.NoError(t, err)
+	assertStdCopyCompatibleOutput(t, &output, []byte("after"), nil)
+}
+
+func TestTimestampedStdWriter_WriteAfterClose(t *testing.T) {
+	var stream bytes.Buffer
+	writer := NewTimestampedStdWriter(&stream)
+	require.NoError(t, writer.Close())
+	require.NoError(t, writer.Close())
+
+	_, err := writer.Stdout().Write([]byte("late"))
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+	assert.Equal(t, stdWriterPrefixLen, stream.Len(), "only the end frame is written")
+}
@@ -30,6 +30,24 @@ var (
 		metric.WithUnit("ms"),
 	))
 
+	executionCPUSeconds = lo.Must(meter.Float64Histogram(
+		"execution_cpu_seconds",
+		metric.WithDescription("CPU time consumed by an execution on the compute node in seconds."),
+		metric.WithUnit("s"),
+	))
+
+	executionPeakMemoryBytes = lo.Must(meter.Int64Histogram(
+		"execution_peak_memory_bytes",
+		metric.WithDescription("Peak memory usage of an execution on the compute node in bytes."),
+		metric.WithUnit("By"),
+	))
+
+	executionDiskBytes = lo.Must(meter.Int64Histogram(
+		"execution_disk_bytes",
+		metric.WithDescription("Disk space written by an execution on the compute node in bytes."),
+		metric.WithUnit("By"),
+	))
+
 	ExecutionBiddingErrors = lo.Must(meter.Int64Counter(
 		"execution_bidding_errors",
 		metric.WithDescription("Number of errors encountered during execution bidding."),
@@ -3,6 +3,8 @@ package compute
 import (
 	"context"
 
+	"github.com/samber/lo"
+
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -49,10 +51,11 @@ func (n *NodeInfoDecorator) DecorateNodeInfo(ctx context.Context, nodeInfo model
 	// TODO(forrest): this method takes 10 seconds to run: https://github.com/bacalhau-project/bacalhau/issues/4153
 	// because the Keys() methods are slow when s3 is considered since we need to check for credentials.
 	nodeInfo.NodeType = models.NodeTypeCompute
+	storageSources := n.storages.Keys(ctx)
 	nodeInfo.ComputeNodeInfo = models.ComputeNodeInfo{
 		ExecutionEngines:   n.executors.Keys(ctx),
 		Publishers:         n.publishers.Keys(ctx),
-		StorageSources:     n.storages.Keys(ctx),
+		StorageSources:     storageSources,
 		MaxCapacity:        n.runningCapacityTracker.GetMaxCapacity(ctx),
 		AvailableCapacity:  n.runningCapacityTracker.GetAvailableCapacity(ctx),
 		QueueUsedCapacity:  n.queueCapacityTracker.GetUsedCapacity(ctx),
@@ -60,9 +63,25 @@ func (n *NodeInfoDecorator) DecorateNodeInfo(ctx context.Context, nodeInfo model
 		RunningExecutions:  len(n.executorBuffer.RunningExecutions()),
 		EnqueuedExecutions: n.executorBuffer.EnqueuedExecutionsCount(),
 		Address:            n.advertisedAddress,
+		CachedInputs:       n.cachedInputs(ctx, storageSources),
 	}
 	return nodeInfo
 }
 
+// cachedInputs returns the input sources cached by the storages of the node
+func (n *NodeInfoDecorator) cachedInputs(ctx context.Context, storageSources []string) []string {
+	var cached []string
+	for _, source := range storageSources {
+		strg, err := n.storages.Get(ctx, source)
+		if err != nil {
+			continue
+		}
+		if provider, ok := strg.(storage.CachedSourcesProvider); ok {
+			cached = append(cached, provider.CachedSources()...)
+		}
+	}
+	return lo.Uniq(cached)
+}
+
 // compile-time interface check
 var _ models.NodeInfoDecorator = &NodeInfoDecorator{}
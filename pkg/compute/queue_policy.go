@@ -0,0 +1,82 @@
+package compute
+
+import (
+	"sort"
+	"time"
+)
+
+// defaultNamespaceWeight is the weight of the namespaces that were not given one
+const defaultNamespaceWeight = 1
+
+// queuePolicy orders the executions waiting in the ExecutorBuffer for enough capacity to run.
+// Executions are ordered by the priority of their job, raised by one for every aging interval they wait so that
+// executions of low priority are not starved. Aging never raises an execution above the highest priority in the
+// queue, so an execution that waited long enough competes with the others on equal terms instead of jumping ahead
+// of them. Executions of the same priority are ordered by the share of the node their namespace already uses,
+// which is its number of running executions divided by its weight, so that a burst of executions from one namespace
+// does not delay the others. The remaining ties are broken in the order the executions were enqueued.
+type queuePolicy struct {
+	namespaceWeights map[string]int
+	agingInterval    time.Duration
+}
+
+// weight returns the weight of a namespace
+func (p queuePolicy) weight(namespace string) float64 {
+	if weight, ok := p.namespaceWeights[namespace]; ok && weight > 0 {
+		return float64(weight)
+	}
+	return defaultNamespaceWeight
+}
+
+// effectivePriority returns the priority of a task after aging, capped by the highest priority in the queue
+func (p queuePolicy) effectivePriority(task *bufferTask, maxPriority int, now time.Time) int {
+	priority := task.execution.Job.Priority
+	if p.agingInterval <= 0 || priority >= maxPriority {
+		return priority
+	}
+	aged := int64(now.Sub(task.enqueuedAt) / p.agingInterval)
+	return int(min(int64(maxPriority), int64(priority)+aged))
+}
+
+// rankedTask is a queued task along with the values it is ordered by
+type rankedTask struct {
+	task              *bufferTask
+	effectivePriority int
+	share             float64
+}
+
+// order returns the queued tasks in the order they should be run, given the number of running executions
+// of each namespace
+func (p queuePolicy) order(tasks []*bufferTask, running map[string]int, now time.Time) []rankedTask {
+	maxPriority := 0
+	for i, task := range tasks {
+		if i == 0 || task.execution.Job.Priority > maxPriority {
+			maxPriority = task.execution.Job.Priority
+		}
+	}
+
+	ranked := make([]rankedTask, len(tasks))
+	for i, task := range tasks {
+		namespace := task.execution.Job.Namespace
+		ranked[i] = rankedTask{
+			task:              task,
+			effectivePriority: p.effectivePriority(task, maxPriority, now),
+			share:             float64(running[namespace]) / p.weight(namespace),
+		}
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.effectivePriority != b.effectivePriority {
+			return a.effectivePriority > b.effectivePriority
+		}
+		if a.share != b.share {
+			return a.share < b.share
+		}
+		if !a.task.enqueuedAt.Equal(b.task.enqueuedAt) {
+			return a.task.enqueuedAt.Before(b.task.enqueuedAt)
+		}
+		return a.task.execution.ID < b.task.execution.ID
+	})
+	return ranked
+}
@@ -0,0 +1,199 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+)
+
+type ResultStreamerParams struct {
+	Store       store.ExecutionStore
+	Publishers  publisher.PublisherProvider
+	ResultsPath ResultsPath
+}
+
+// ResultStreamer periodically publishes the results of running executions whose task enables streaming through
+// the task's publisher, if it is a publisher.StreamingPublisher. Each update only publishes the files that changed
+// since the previous one, and is recorded in the execution store as the execution's partial result, from which it
+// is reported to the orchestrator so that the results can be fetched before the execution completes.
+//
+// Tasks should write their results atomically, such as by renaming files once written,
+// as the results are published while the task is running.
+type ResultStreamer struct {
+	store       store.ExecutionStore
+	publishers  publisher.PublisherProvider
+	resultsPath ResultsPath
+
+	mu      sync.Mutex
+	streams map[string]*resultStream
+}
+
+// resultStream is the state of the results streamed by an execution
+type resultStream struct {
+	mu       sync.Mutex
+	manifest publisher.Manifest
+	previous *models.SpecConfig
+	sequence int
+	complete bool
+}
+
+func NewResultStreamer(params ResultStreamerParams) *ResultStreamer {
+	return &ResultStreamer{
+		store:       params.Store,
+		publishers:  params.Publishers,
+		resultsPath: params.ResultsPath,
+		streams:     make(map[string]*resultStream),
+	}
+}
+
+// Run publishes the results of the execution at the interval configured by its task until the context is done.
+// The results are only published if they changed since the previous update.
+func (r *ResultStreamer) Run(ctx context.Context, execution *models.Execution) {
+	logger := log.Ctx(ctx).With().Str("execution", execution.ID).Logger()
+	stream := newResultStream(execution)
+	r.mu.Lock()
+	r.streams[execution.ID] = stream
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.streams, execution.ID)
+	}()
+
+	ticker := time.NewTicker(execution.Job.Task().Streaming.GetInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		partial, err := r.publish(ctx, execution, stream, false)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn().Err(err).Msg("failed to publish partial result of execution")
+			}
+			continue
+		}
+		if partial != nil {
+			logger.Debug().Int("sequence", partial.Sequence).Msg("published partial result of execution")
+		}
+	}
+}
+
+// Complete publishes the final update of the results of a completed execution, whose manifest lists all of its
+// results. No more updates are published for the execution afterwards.
+func (r *ResultStreamer) Complete(ctx context.Context, execution *models.Execution) (*models.PartialResult, error) {
+	r.mu.Lock()
+	stream, ok := r.streams[execution.ID]
+	r.mu.Unlock()
+	if !ok {
+		stream = newResultStream(execution)
+	}
+	return r.publish(ctx, execution, stream, true)
+}
+
+// Cleanup releases the partial results of an execution that ended through the task's publisher,
+// once its results were published as a whole if it completed
+func (r *ResultStreamer) Cleanup(ctx context.Context, execution *models.Execution) {
+	jobPublisher, err := r.publishers.Get(ctx, execution.Job.Task().Publisher.Type)
+	if err != nil {
+		return
+	}
+	streamingPublisher, ok := jobPublisher.(publisher.StreamingPublisher)
+	if !ok {
+		return
+	}
+	if err = streamingPublisher.CleanupPartialResult(ctx, execution); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("execution", execution.ID).Msg("failed to clean up partial results of execution")
+	}
+}
+
+// newResultStream returns the state of the results streamed by the execution,
+// starting from the execution's last partial result
+func newResultStream(execution *models.Execution) *resultStream {
+	stream := &resultStream{}
+	if execution.PartialResult != nil {
+		stream.previous = execution.PartialResult.Result
+		stream.sequence = execution.PartialResult.Sequence
+	}
+	return stream
+}
+
+// publish publishes the results of the execution that changed since its previous update through the task's
+// publisher, and records them as the execution's partial result. It returns nil if there was nothing to publish.
+func (r *ResultStreamer) publish(
+	ctx context.Context, execution *models.Execution, stream *resultStream, complete bool,
+) (*models.PartialResult, error) {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	if stream.complete {
+		return nil, nil
+	}
+
+	jobPublisher, err := r.publishers.Get(ctx, execution.Job.Task().Publisher.Type)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get publisher %s: %w", execution.Job.Task().Publisher.Type, err)
+	}
+	streamingPublisher, ok := jobPublisher.(publisher.StreamingPublisher)
+	if !ok {
+		// the results are only published once the execution completes
+		return nil, nil
+	}
+
+	// checkpoints are published on their own, and are not part of the results
+	resultsDir := ExecutionResultsDir(r.resultsPath.ExecutionOutputDir(execution.ID))
+	manifest, err := publisher.NewManifest(resultsDir, models.CheckpointResultName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list results: %w", err)
+	}
+	manifest.Complete = complete
+	changed, removed := manifest.Diff(stream.manifest)
+	if !complete && len(changed) == 0 && len(removed) == 0 {
+		// nothing changed since the previous update
+		return nil, nil
+	}
+
+	result, err := streamingPublisher.PublishPartialResult(ctx, execution, resultsDir, publisher.ResultUpdate{
+		Manifest: manifest,
+		Changed:  changed,
+		Removed:  removed,
+		Previous: stream.previous,
+	})
+	if err != nil {
+		return nil, bacerrors.Wrap(err, "failed to publish partial result")
+	}
+
+	partial := &models.PartialResult{
+		Result:     &result,
+		Sequence:   stream.sequence + 1,
+		Complete:   complete,
+		UpdateTime: time.Now().UTC().UnixNano(),
+	}
+	if err = r.store.UpdateExecutionState(ctx, store.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		Condition: store.UpdateExecutionCondition{
+			ExpectedStates: []models.ExecutionStateType{models.ExecutionStateRunning},
+		},
+		NewValues: models.Execution{
+			PartialResult: partial,
+		},
+	}); err != nil {
+		return nil, err
+	}
+	stream.manifest = manifest
+	stream.previous = &result
+	stream.sequence = partial.Sequence
+	stream.complete = complete
+	return partial, nil
+}
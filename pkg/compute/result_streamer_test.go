@@ -0,0 +1,221 @@
+//go:build unit || !integration
+
+package compute_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store/boltdb"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/noop"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+// recordingStreamingPublisher records the partial results published through it
+type recordingStreamingPublisher struct {
+	*noop.NoopPublisher
+	mu      sync.Mutex
+	updates []publisher.ResultUpdate
+	cleaned []string
+}
+
+func (p *recordingStreamingPublisher) PublishPartialResult(
+	_ context.Context, execution *models.Execution, _ string, update publisher.ResultUpdate,
+) (models.SpecConfig, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.updates = append(p.updates, update)
+	return models.SpecConfig{Type: models.PublisherNoop, Params: map[string]interface{}{"ID": execution.ID}}, nil
+}
+
+func (p *recordingStreamingPublisher) CleanupPartialResult(_ context.Context, execution *models.Execution) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cleaned = append(p.cleaned, execution.ID)
+	return nil
+}
+
+func (p *recordingStreamingPublisher) published() []publisher.ResultUpdate {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]publisher.ResultUpdate{}, p.updates...)
+}
+
+type ResultStreamerTestSuite struct {
+	suite.Suite
+	ctx         context.Context
+	database    store.ExecutionStore
+	resultsPath *compute.ResultsPath
+	publisher   *recordingStreamingPublisher
+	streamer    *compute.ResultStreamer
+}
+
+func TestResultStreamerTestSuite(t *testing.T) {
+	suite.Run(t, new(ResultStreamerTestSuite))
+}
+
+func (s *ResultStreamerTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	var err error
+	s.database, err = boltdb.NewStore(s.ctx, filepath.Join(s.T().TempDir(), "result-streamer-test.db"))
+	s.Require().NoError(err)
+	s.resultsPath, err = compute.NewResultsPath(s.T().TempDir())
+	s.Require().NoError(err)
+
+	s.publisher = &recordingStreamingPublisher{NoopPublisher: noop.NewNoopPublisher()}
+	s.streamer = compute.NewResultStreamer(compute.ResultStreamerParams{
+		Store:       s.database,
+		Publishers:  provider.NewMappedProvider(map[string]publisher.Publisher{models.PublisherNoop: s.publisher}),
+		ResultsPath: *s.resultsPath,
+	})
+}
+
+func (s *ResultStreamerTestSuite) TearDownTest() {
+	s.database.Close(s.ctx)
+}
+
+// runningExecution creates a running execution that streams its results at the given interval,
+// with a file in its results directory
+func (s *ResultStreamerTestSuite) runningExecution(interval int64) (*models.Execution, string) {
+	job := mock.Job()
+	job.Task().Publisher = &models.SpecConfig{Type: models.PublisherNoop}
+	job.Task().Streaming = &models.StreamingConfig{}
+	execution := mock.ExecutionForJob(job)
+	s.Require().NoError(s.database.CreateExecution(s.ctx, *execution))
+	// set after the execution is validated, as it may be shorter than the minimum interval to not wait for it in tests
+	job.Task().Streaming.Interval = interval
+	s.Require().NoError(s.database.UpdateExecutionState(s.ctx, store.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		NewValues: models.Execution{
+			ComputeState: models.NewExecutionState(models.ExecutionStateRunning),
+		},
+	}))
+
+	executionDir, err := s.resultsPath.PrepareExecutionOutputDir(execution.ID)
+	s.Require().NoError(err)
+	resultsDir := compute.ExecutionResultsDir(executionDir)
+	s.Require().NoError(os.WriteFile(filepath.Join(resultsDir, "first"), []byte("step 1"), 0o644))
+	return execution, resultsDir
+}
+
+func (s *ResultStreamerTestSuite) TestRun() {
+	execution, resultsDir := s.runningExecution(1)
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.streamer.Run(ctx, execution)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	s.Eventually(func() bool {
+		return len(s.publisher.published()) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+	s.Equal([]string{"first"}, s.publisher.published()[0].Changed)
+	s.Nil(s.publisher.published()[0].Previous)
+
+	// only the files that changed since the previous update are published
+	s.Require().NoError(os.WriteFile(filepath.Join(resultsDir, "second"), []byte("step 2"), 0o644))
+	s.Eventually(func() bool {
+		return len(s.publisher.published()) == 2
+	}, 5*time.Second, 50*time.Millisecond)
+	update := s.publisher.published()[1]
+	s.Equal([]string{"second"}, update.Changed)
+	s.Empty(update.Removed)
+	s.Len(update.Manifest.Files, 2)
+	s.False(update.Manifest.Complete)
+	s.NotNil(update.Previous)
+
+	stored, err := s.database.GetExecution(s.ctx, execution.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(stored.PartialResult)
+	s.Equal(2, stored.PartialResult.Sequence)
+	s.False(stored.PartialResult.Complete)
+
+	// unchanged results are not published again
+	time.Sleep(1500 * time.Millisecond)
+	s.Len(s.publisher.published(), 2)
+}
+
+func (s *ResultStreamerTestSuite) TestComplete() {
+	execution, resultsDir := s.runningExecution(0)
+
+	partial, err := s.streamer.Complete(s.ctx, execution)
+	s.Require().NoError(err)
+	s.Equal(1, partial.Sequence)
+	s.True(partial.Complete)
+
+	updates := s.publisher.published()
+	s.Require().Len(updates, 1)
+	s.True(updates[0].Manifest.Complete)
+	s.Equal([]string{"first"}, updates[0].Changed)
+
+	stored, err := s.database.GetExecution(s.ctx, execution.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(stored.PartialResult)
+	s.True(stored.PartialResult.Complete)
+	s.Equal(execution.ID, stored.PartialResult.Result.Params["ID"])
+
+	// the final update continues from the partial result of the execution
+	s.Require().NoError(os.Remove(filepath.Join(resultsDir, "first")))
+	execution.PartialResult = stored.PartialResult
+	partial, err = s.streamer.Complete(s.ctx, execution)
+	s.Require().NoError(err)
+	s.Equal(2, partial.Sequence)
+	s.NotNil(s.publisher.published()[1].Previous)
+}
+
+func (s *ResultStreamerTestSuite) TestCompleteNotRunning() {
+	execution, _ := s.runningExecution(0)
+	s.Require().NoError(s.database.UpdateExecutionState(s.ctx, store.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		NewValues: models.Execution{
+			ComputeState: models.NewExecutionState(models.ExecutionStatePublishing),
+		},
+	}))
+
+	_, err := s.streamer.Complete(s.ctx, execution)
+	s.Error(err)
+
+	stored, err := s.database.GetExecution(s.ctx, execution.ID)
+	s.Require().NoError(err)
+	s.Nil(stored.PartialResult)
+}
+
+func (s *ResultStreamerTestSuite) TestNotStreamingPublisher() {
+	s.streamer = compute.NewResultStreamer(compute.ResultStreamerParams{
+		Store:       s.database,
+		Publishers:  provider.NewMappedProvider(map[string]publisher.Publisher{models.PublisherNoop: noop.NewNoopPublisher()}),
+		ResultsPath: *s.resultsPath,
+	})
+	execution, _ := s.runningExecution(0)
+
+	// the results are only published once the execution completes
+	partial, err := s.streamer.Complete(s.ctx, execution)
+	s.Require().NoError(err)
+	s.Nil(partial)
+}
+
+func (s *ResultStreamerTestSuite) TestCleanup() {
+	execution, _ := s.runningExecution(0)
+	_, err := s.streamer.Complete(s.ctx, execution)
+	s.Require().NoError(err)
+
+	s.streamer.Cleanup(s.ctx, execution)
+	s.Equal([]string{execution.ID}, s.publisher.cleaned)
+}
@@ -2,6 +2,7 @@ package compute
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -75,6 +76,27 @@ func (r *ResultsPath) PrepareExecutionOutputDir(executionID string) (string, err
 	return executionOutputDir, nil
 }
 
+// ExecutionOutputDirSize returns the disk space used by the files of an execution's output directory,
+// not following symbolic links as they point to files the execution didn't write, such as its inputs.
+func (r *ResultsPath) ExecutionOutputDirSize(executionID string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(r.ExecutionOutputDir(executionID), func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += uint64(info.Size()) //nolint:gosec // file sizes are never negative
+		return nil
+	})
+	return size, err
+}
+
 func (r *ResultsPath) Close() error {
 	log.Debug().Str("path", r.OutputDir).Msg("removing root results dir")
 	return os.RemoveAll(r.OutputDir)
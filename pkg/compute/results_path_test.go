@@ -0,0 +1,33 @@
+//go:build unit || !integration
+
+package compute_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+)
+
+func TestExecutionOutputDirSize(t *testing.T) {
+	resultsPath, err := compute.NewResultsPath(t.TempDir())
+	require.NoError(t, err)
+	executionDir, err := resultsPath.PrepareExecutionOutputDir("e-1")
+	require.NoError(t, err)
+
+	resultsDir := compute.ExecutionResultsDir(executionDir)
+	require.NoError(t, os.WriteFile(filepath.Join(resultsDir, "stdout"), make([]byte, 100), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(compute.ExecutionLogsDir(executionDir), "logs"), make([]byte, 20), 0o644))
+
+	// links to files the execution didn't write are not counted
+	input := filepath.Join(t.TempDir(), "input")
+	require.NoError(t, os.WriteFile(input, make([]byte, 1000), 0o644))
+	require.NoError(t, os.Symlink(input, filepath.Join(resultsDir, "input")))
+
+	size, err := resultsPath.ExecutionOutputDirSize("e-1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(120), size)
+}
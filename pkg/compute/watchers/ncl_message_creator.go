@@ -48,6 +48,21 @@ func (d *NCLMessageCreator) CreateMessage(event watcher.Event) (*envelope.Messag
 		log.Debug().Msgf("Rejecting bid for execution %s", execution.ID)
 		message = envelope.NewMessage(messages.BidResult{Accepted: false, BaseResponse: baseResponse}).
 			WithMetadataValue(envelope.KeyMessageType, messages.BidResultMessageType)
+	case models.ExecutionStateRunning:
+		// running executions only report the checkpoints and partial results they publish
+		if hasNewCheckpoint(upsert) {
+			log.Debug().Msgf("Execution %s published checkpoint %d", execution.ID, execution.Checkpoint.Sequence)
+			message = envelope.NewMessage(messages.CheckpointResult{
+				BaseResponse: baseResponse,
+				Checkpoint:   execution.Checkpoint,
+			}).WithMetadataValue(envelope.KeyMessageType, messages.CheckpointResultMessageType)
+		} else if hasNewPartialResult(upsert) {
+			log.Debug().Msgf("Execution %s published partial result %d", execution.ID, execution.PartialResult.Sequence)
+			message = envelope.NewMessage(messages.PartialResult{
+				BaseResponse:  baseResponse,
+				PartialResult: execution.PartialResult,
+			}).WithMetadataValue(envelope.KeyMessageType, messages.PartialResultMessageType)
+		}
 	case models.ExecutionStateCompleted:
 		log.Debug().Msgf("Execution %s completed", execution.ID)
 		message = envelope.NewMessage(messages.RunResult{
@@ -66,5 +81,23 @@ func (d *NCLMessageCreator) CreateMessage(event watcher.Event) (*envelope.Messag
 	return message, nil
 }
 
+// hasNewCheckpoint returns true if the upsert records a checkpoint published by the execution
+func hasNewCheckpoint(upsert models.ExecutionUpsert) bool {
+	if upsert.Current.Checkpoint == nil {
+		return false
+	}
+	return upsert.Previous == nil || upsert.Previous.Checkpoint == nil ||
+		upsert.Previous.Checkpoint.Sequence != upsert.Current.Checkpoint.Sequence
+}
+
+// hasNewPartialResult returns true if the upsert records a partial result published by the execution
+func hasNewPartialResult(upsert models.ExecutionUpsert) bool {
+	if upsert.Current.PartialResult == nil {
+		return false
+	}
+	return upsert.Previous == nil || upsert.Previous.PartialResult == nil ||
+		upsert.Previous.PartialResult.Sequence != upsert.Current.PartialResult.Sequence
+}
+
 // compile-time check that NCLMessageCreator implements dispatcher.MessageCreator
 var _ nclprotocol.MessageCreator = &NCLMessageCreator{}
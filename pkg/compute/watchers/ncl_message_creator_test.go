@@ -141,6 +141,77 @@ func (s *NCLMessageCreatorTestSuite) TestCreateMessage_ExecutionCompleted() {
 	s.Equal(0, result.RunCommandResult.ExitCode)
 }
 
+func (s *NCLMessageCreatorTestSuite) TestCreateMessage_Checkpoint() {
+	previous := mock.Execution()
+	previous.Job.Meta[models.MetaOrchestratorProtocol] = models.ProtocolNCLV1.String()
+	previous.ComputeState = models.NewExecutionState(models.ExecutionStateRunning)
+
+	// no message while running without a new checkpoint
+	msg, err := s.creator.CreateMessage(watcher.Event{
+		Object: models.ExecutionUpsert{Current: previous, Previous: previous},
+	})
+	s.Require().NoError(err)
+	s.Nil(msg)
+
+	execution := previous.Copy()
+	execution.Checkpoint = &models.Checkpoint{Result: &models.SpecConfig{Type: "myCheckpoint"}, Sequence: 1}
+	msg, err = s.creator.CreateMessage(watcher.Event{
+		Object: models.ExecutionUpsert{Current: execution, Previous: previous},
+	})
+	s.Require().NoError(err)
+	s.Require().NotNil(msg)
+
+	s.Equal(messages.CheckpointResultMessageType, msg.Metadata.Get(envelope.KeyMessageType))
+
+	payload, ok := msg.GetPayload(messages.CheckpointResult{})
+	s.Require().True(ok)
+	result := payload.(messages.CheckpointResult)
+
+	s.Equal(execution.ID, result.ExecutionID)
+	s.Equal(execution.JobID, result.JobID)
+	s.Equal("myCheckpoint", result.Checkpoint.Result.Type)
+	s.Equal(1, result.Checkpoint.Sequence)
+
+	// no message when the checkpoint didn't change
+	msg, err = s.creator.CreateMessage(watcher.Event{
+		Object: models.ExecutionUpsert{Current: execution, Previous: execution},
+	})
+	s.Require().NoError(err)
+	s.Nil(msg)
+}
+
+func (s *NCLMessageCreatorTestSuite) TestCreateMessage_PartialResult() {
+	previous := mock.Execution()
+	previous.Job.Meta[models.MetaOrchestratorProtocol] = models.ProtocolNCLV1.String()
+	previous.ComputeState = models.NewExecutionState(models.ExecutionStateRunning)
+
+	execution := previous.Copy()
+	execution.PartialResult = &models.PartialResult{Result: &models.SpecConfig{Type: "myPartialResult"}, Sequence: 1}
+	msg, err := s.creator.CreateMessage(watcher.Event{
+		Object: models.ExecutionUpsert{Current: execution, Previous: previous},
+	})
+	s.Require().NoError(err)
+	s.Require().NotNil(msg)
+
+	s.Equal(messages.PartialResultMessageType, msg.Metadata.Get(envelope.KeyMessageType))
+
+	payload, ok := msg.GetPayload(messages.PartialResult{})
+	s.Require().True(ok)
+	result := payload.(messages.PartialResult)
+
+	s.Equal(execution.ID, result.ExecutionID)
+	s.Equal(execution.JobID, result.JobID)
+	s.Equal("myPartialResult", result.PartialResult.Result.Type)
+	s.Equal(1, result.PartialResult.Sequence)
+
+	// no message when the partial result didn't change
+	msg, err = s.creator.CreateMessage(watcher.Event{
+		Object: models.ExecutionUpsert{Current: execution, Previous: execution},
+	})
+	s.Require().NoError(err)
+	s.Nil(msg)
+}
+
 func (s *NCLMessageCreatorTestSuite) TestCreateMessage_ExecutionFailed() {
 	execution := mock.Execution()
 	execution.Job.Meta[models.MetaOrchestratorProtocol] = models.ProtocolNCLV1.String()
@@ -37,11 +37,25 @@ var Default = types.Bacalhau{
 			QueueBackoff:         types.Minute,
 			HousekeepingInterval: 30 * types.Second,
 			HousekeepingTimeout:  2 * types.Minute,
+			Preemption: types.Preemption{
+				Policy: "never",
+			},
 		},
 		EvaluationBroker: types.EvaluationBroker{
+			Type:              types.EvaluationBrokerTypeInMemory,
 			VisibilityTimeout: types.Minute,
 			MaxRetryCount:     10,
 		},
+		Webhooks: types.Webhooks{
+			MaxAttempts: 5,
+			Timeout:     10 * types.Second,
+		},
+		JobStore: types.JobStore{
+			Type: types.JobStoreTypeBoltDB,
+		},
+		LeaderElection: types.LeaderElection{
+			LeaseDuration: 15 * types.Second,
+		},
 	},
 	Compute: types.Compute{
 		Enabled:       false,
@@ -62,6 +76,10 @@ var Default = types.Bacalhau{
 			Disk:   "80%",
 			GPU:    "100%",
 		},
+		Queue: types.ComputeQueue{
+			AgingInterval:        5 * types.Minute,
+			ReservationThreshold: 10 * types.Minute,
+		},
 	},
 	JobDefaults: types.JobDefaults{
 		Batch: types.BatchJobDefaultsConfig{
@@ -104,6 +122,14 @@ var Default = types.Bacalhau{
 	InputSources: types.InputSourcesConfig{
 		ReadTimeout:   5 * types.Minute,
 		MaxRetryCount: 3,
+		Cache: types.InputCache{
+			Enabled: true,
+			MaxSize: "10GB",
+		},
+		Extraction: types.InputExtraction{
+			MaxRatio: 20,
+			MaxSize:  "50GB",
+		},
 	},
 	Engines: types.EngineConfig{
 		Types: types.EngineConfigTypes{
@@ -113,6 +139,17 @@ var Default = types.Bacalhau{
 					TTL:     types.Duration(1 * time.Hour),
 					Refresh: types.Duration(1 * time.Hour),
 				}},
+			WASM: types.WASM{
+				CompilationCache: types.WASMCompilationCache{
+					Enabled: true,
+					MaxSize: "1GB",
+				},
+				KeyValueStore: types.WASMKeyValueStore{
+					Enabled:       true,
+					MaxBucketSize: "100MB",
+					MaxValueSize:  "1MB",
+				},
+			},
 		},
 	},
 	Publishers: types.PublishersConfig{
@@ -157,9 +194,13 @@ var testOverrides = types.Bacalhau{
 			HousekeepingInterval: 1 * types.Second,
 		},
 		EvaluationBroker: types.EvaluationBroker{
+			Type:              types.EvaluationBrokerTypeInMemory,
 			VisibilityTimeout: types.Duration(5 * time.Second),
 			MaxRetryCount:     3,
 		},
+		LeaderElection: types.LeaderElection{
+			LeaseDuration: 3 * types.Second,
+		},
 	},
 	Compute: types.Compute{
 		Heartbeat: types.Heartbeat{
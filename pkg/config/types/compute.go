@@ -13,10 +13,19 @@ type Compute struct {
 	AllocatedCapacity ResourceScaler `yaml:"AllocatedCapacity,omitempty" json:"AllocatedCapacity,omitempty"`
 	// AllowListedLocalPaths specifies a list of local file system paths that the compute node is allowed to access.
 	AllowListedLocalPaths []string `yaml:"AllowListedLocalPaths" json:"AllowListedLocalPaths,omitempty"`
+	// AllowListedBinaries specifies a list of host binaries that jobs using the exec engine are allowed to run.
+	// Supports glob patterns (e.g., "/usr/bin/*"). No binaries can be run if empty.
+	AllowListedBinaries []string `yaml:"AllowListedBinaries,omitempty" json:"AllowListedBinaries,omitempty"`
+	// AllowListedExecEnv specifies the environment variables that jobs using the exec engine are allowed to set.
+	// Supports glob patterns (e.g., "PYTHON*"). Any variable can be set if empty, except the variables of the
+	// dynamic loader (e.g., LD_PRELOAD), which can never be set.
+	AllowListedExecEnv []string `yaml:"AllowListedExecEnv,omitempty" json:"AllowListedExecEnv,omitempty"`
 	// TLS specifies the TLS related configuration on the compute node when connecting with the orchestrator.
 	TLS ComputeTLS `yaml:"TLS,omitempty" json:"TLS,omitempty"`
 	// Env specifies environment variable configuration for the compute node
 	Env EnvConfig `yaml:"Env,omitempty" json:"Env,omitempty"`
+	// Queue specifies how the executions waiting for capacity on the compute node are ordered.
+	Queue ComputeQueue `yaml:"Queue,omitempty" json:"Queue,omitempty"`
 }
 
 type ComputeAuth struct {
@@ -58,3 +67,19 @@ type NetworkConfig struct {
 	// PortRangeEnd is the last port in the range (inclusive) that can be allocated to jobs
 	PortRangeEnd int `yaml:"PortRangeEnd,omitempty" json:"PortRangeEnd,omitempty"`
 }
+
+// ComputeQueue specifies how the executions waiting for capacity on the compute node are ordered.
+// Executions are ordered by job priority, then by the fair share of the node of their namespace.
+type ComputeQueue struct {
+	// NamespaceWeights specifies the share of the node each namespace is entitled to relative to the others.
+	// Namespaces that are not listed have a weight of 1.
+	NamespaceWeights map[string]int `yaml:"NamespaceWeights,omitempty" json:"NamespaceWeights,omitempty"`
+	// AgingInterval specifies how long an execution waits in the queue before its priority is raised by one,
+	// up to the highest priority in the queue, so that executions of low priority are not starved.
+	// Aging is disabled if zero.
+	AgingInterval Duration `yaml:"AgingInterval,omitempty" json:"AgingInterval,omitempty"`
+	// ReservationThreshold specifies how long the first queued execution that doesn't fit in the available capacity
+	// waits before smaller executions queued after it stop being run ahead of it, so that it is not starved.
+	// Smaller executions are always run ahead if zero.
+	ReservationThreshold Duration `yaml:"ReservationThreshold,omitempty" json:"ReservationThreshold,omitempty"`
+}
@@ -41,4 +41,30 @@ type DockerManifestCache struct {
 }
 
 type WASM struct {
+	// CompilationCache specifies the settings for the cache of compiled WASM modules shared by all executions.
+	CompilationCache WASMCompilationCache `yaml:"CompilationCache,omitempty" json:"CompilationCache,omitempty"`
+	// KeyValueStore specifies the settings for the key-value store WASM jobs can use to share state across executions.
+	KeyValueStore WASMKeyValueStore `yaml:"KeyValueStore,omitempty" json:"KeyValueStore,omitempty"`
+}
+
+// WASMCompilationCache represents the configuration settings for the cache of compiled WASM modules,
+// which is stored in the compute node's data directory.
+type WASMCompilationCache struct {
+	// Enabled specifies whether compiled WASM modules are cached,
+	// so that modules run by many executions are compiled once.
+	Enabled bool `yaml:"Enabled,omitempty" json:"Enabled,omitempty"`
+	// MaxSize specifies the maximum disk space used by compiled modules, such as 1GB.
+	// The least recently used modules are evicted beyond it.
+	MaxSize string `yaml:"MaxSize,omitempty" json:"MaxSize,omitempty"`
+}
+
+// WASMKeyValueStore represents the configuration settings for the key-value store exposed to WASM jobs
+// as host functions, which is stored in the compute node's data directory.
+type WASMKeyValueStore struct {
+	// Enabled specifies whether WASM jobs can store values in the key-value store of the node.
+	Enabled bool `yaml:"Enabled,omitempty" json:"Enabled,omitempty"`
+	// MaxBucketSize specifies the maximum total size of the keys and values of a bucket, such as 100MB.
+	MaxBucketSize string `yaml:"MaxBucketSize,omitempty" json:"MaxBucketSize,omitempty"`
+	// MaxValueSize specifies the maximum size of a single value, such as 1MB.
+	MaxValueSize string `yaml:"MaxValueSize,omitempty" json:"MaxValueSize,omitempty"`
 }
@@ -29,6 +29,8 @@ const ComputeAllocatedCapacityCPUKey = "Compute.AllocatedCapacity.CPU"
 const ComputeAllocatedCapacityDiskKey = "Compute.AllocatedCapacity.Disk"
 const ComputeAllocatedCapacityGPUKey = "Compute.AllocatedCapacity.GPU"
 const ComputeAllocatedCapacityMemoryKey = "Compute.AllocatedCapacity.Memory"
+const ComputeAllowListedBinariesKey = "Compute.AllowListedBinaries"
+const ComputeAllowListedExecEnvKey = "Compute.AllowListedExecEnv"
 const ComputeAllowListedLocalPathsKey = "Compute.AllowListedLocalPaths"
 const ComputeAuthTokenKey = "Compute.Auth.Token" //nolint:gosec // G101: Not a credential, just a config key name
 const ComputeEnabledKey = "Compute.Enabled"
@@ -40,6 +42,9 @@ const ComputeNetworkAdvertisedAddressKey = "Compute.Network.AdvertisedAddress"
 const ComputeNetworkPortRangeEndKey = "Compute.Network.PortRangeEnd"
 const ComputeNetworkPortRangeStartKey = "Compute.Network.PortRangeStart"
 const ComputeOrchestratorsKey = "Compute.Orchestrators"
+const ComputeQueueAgingIntervalKey = "Compute.Queue.AgingInterval"
+const ComputeQueueReservationThresholdKey = "Compute.Queue.ReservationThreshold"
+const ComputeQueueNamespaceWeightsKey = "Compute.Queue.NamespaceWeights"
 const ComputeTLSCACertKey = "Compute.TLS.CACert"
 const ComputeTLSRequireTLSKey = "Compute.TLS.RequireTLS"
 const DataDirKey = "DataDir"
@@ -48,7 +53,16 @@ const EnginesDisabledKey = "Engines.Disabled"
 const EnginesTypesDockerManifestCacheRefreshKey = "Engines.Types.Docker.ManifestCache.Refresh"
 const EnginesTypesDockerManifestCacheSizeKey = "Engines.Types.Docker.ManifestCache.Size"
 const EnginesTypesDockerManifestCacheTTLKey = "Engines.Types.Docker.ManifestCache.TTL"
+const EnginesTypesWASMCompilationCacheEnabledKey = "Engines.Types.WASM.CompilationCache.Enabled"
+const EnginesTypesWASMCompilationCacheMaxSizeKey = "Engines.Types.WASM.CompilationCache.MaxSize"
+const EnginesTypesWASMKeyValueStoreEnabledKey = "Engines.Types.WASM.KeyValueStore.Enabled"
+const EnginesTypesWASMKeyValueStoreMaxBucketSizeKey = "Engines.Types.WASM.KeyValueStore.MaxBucketSize"
+const EnginesTypesWASMKeyValueStoreMaxValueSizeKey = "Engines.Types.WASM.KeyValueStore.MaxValueSize"
+const InputSourcesCacheEnabledKey = "InputSources.Cache.Enabled"
+const InputSourcesCacheMaxSizeKey = "InputSources.Cache.MaxSize"
 const InputSourcesDisabledKey = "InputSources.Disabled"
+const InputSourcesExtractionMaxRatioKey = "InputSources.Extraction.MaxRatio"
+const InputSourcesExtractionMaxSizeKey = "InputSources.Extraction.MaxSize"
 const InputSourcesMaxRetryCountKey = "InputSources.MaxRetryCount"
 const InputSourcesReadTimeoutKey = "InputSources.ReadTimeout"
 const InputSourcesTypesIPFSEndpointKey = "InputSources.Types.IPFS.Endpoint"
@@ -99,13 +113,20 @@ const OrchestratorClusterPeersKey = "Orchestrator.Cluster.Peers"
 const OrchestratorClusterPortKey = "Orchestrator.Cluster.Port"
 const OrchestratorEnabledKey = "Orchestrator.Enabled"
 const OrchestratorEvaluationBrokerMaxRetryCountKey = "Orchestrator.EvaluationBroker.MaxRetryCount"
+const OrchestratorEvaluationBrokerTypeKey = "Orchestrator.EvaluationBroker.Type"
 const OrchestratorEvaluationBrokerVisibilityTimeoutKey = "Orchestrator.EvaluationBroker.VisibilityTimeout"
 const OrchestratorHostKey = "Orchestrator.Host"
+const OrchestratorJobStoreDSNKey = "Orchestrator.JobStore.DSN"
+const OrchestratorJobStoreTypeKey = "Orchestrator.JobStore.Type"
+const OrchestratorLeaderElectionEnabledKey = "Orchestrator.LeaderElection.Enabled"
+const OrchestratorLeaderElectionLeaseDurationKey = "Orchestrator.LeaderElection.LeaseDuration"
 const OrchestratorNodeManagerDisconnectTimeoutKey = "Orchestrator.NodeManager.DisconnectTimeout"
 const OrchestratorNodeManagerManualApprovalKey = "Orchestrator.NodeManager.ManualApproval"
 const OrchestratorPortKey = "Orchestrator.Port"
 const OrchestratorSchedulerHousekeepingIntervalKey = "Orchestrator.Scheduler.HousekeepingInterval"
 const OrchestratorSchedulerHousekeepingTimeoutKey = "Orchestrator.Scheduler.HousekeepingTimeout"
+const OrchestratorSchedulerPreemptionNamespacesKey = "Orchestrator.Scheduler.Preemption.Namespaces"
+const OrchestratorSchedulerPreemptionPolicyKey = "Orchestrator.Scheduler.Preemption.Policy"
 const OrchestratorSchedulerQueueBackoffKey = "Orchestrator.Scheduler.QueueBackoff"
 const OrchestratorSchedulerWorkerCountKey = "Orchestrator.Scheduler.WorkerCount"
 const OrchestratorSupportReverseProxyKey = "Orchestrator.SupportReverseProxy"
@@ -113,6 +134,11 @@ const OrchestratorTLSCACertKey = "Orchestrator.TLS.CACert"
 const OrchestratorTLSServerCertKey = "Orchestrator.TLS.ServerCert"
 const OrchestratorTLSServerKeyKey = "Orchestrator.TLS.ServerKey"
 const OrchestratorTLSServerTimeoutKey = "Orchestrator.TLS.ServerTimeout"
+const OrchestratorWebhooksAllowPrivateAddressesKey = "Orchestrator.Webhooks.AllowPrivateAddresses"
+const OrchestratorWebhooksMaxAttemptsKey = "Orchestrator.Webhooks.MaxAttempts"
+const OrchestratorWebhooksNamespacesKey = "Orchestrator.Webhooks.Namespaces"
+const OrchestratorWebhooksSigningKeyKey = "Orchestrator.Webhooks.SigningKey" //nolint:gosec // G101: Not a credential, just a config key name
+const OrchestratorWebhooksTimeoutKey = "Orchestrator.Webhooks.Timeout"
 const PublishersDisabledKey = "Publishers.Disabled"
 const PublishersTypesIPFSEndpointKey = "Publishers.Types.IPFS.Endpoint"
 const PublishersTypesLocalAddressKey = "Publishers.Types.Local.Address"
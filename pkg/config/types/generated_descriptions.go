@@ -31,6 +31,8 @@ var ConfigDescriptions = map[string]string{
 	ComputeAllocatedCapacityDiskKey:                   "Disk specifies the amount of Disk space a compute node allocates for running jobs. It can be expressed as a percentage (e.g., \"85%\") or a Kubernetes resource string (e.g., \"10Gi\").",
 	ComputeAllocatedCapacityGPUKey:                    "GPU specifies the amount of GPU a compute node allocates for running jobs. It can be expressed as a percentage (e.g., \"85%\") or a Kubernetes resource string (e.g., \"1\"). Note: When using percentages, the result is always rounded up to the nearest whole GPU.",
 	ComputeAllocatedCapacityMemoryKey:                 "Memory specifies the amount of Memory a compute node allocates for running jobs. It can be expressed as a percentage (e.g., \"85%\") or a Kubernetes resource string (e.g., \"1Gi\").",
+	ComputeAllowListedBinariesKey:                     "AllowListedBinaries specifies a list of host binaries that jobs using the exec engine are allowed to run. Supports glob patterns (e.g., \"/usr/bin/*\"). No binaries can be run if empty.",
+	ComputeAllowListedExecEnvKey:                      "AllowListedExecEnv specifies the environment variables that jobs using the exec engine are allowed to set. Supports glob patterns (e.g., \"PYTHON*\"). Any variable can be set if empty, except the variables of the dynamic loader (e.g., LD_PRELOAD), which can never be set.",
 	ComputeAllowListedLocalPathsKey:                   "AllowListedLocalPaths specifies a list of local file system paths that the compute node is allowed to access.",
 	ComputeAuthTokenKey:                               "Token specifies the key for compute nodes to be able to access the orchestrator.",
 	ComputeEnabledKey:                                 "Enabled indicates whether the compute node is active and available for job execution.",
@@ -42,6 +44,9 @@ var ConfigDescriptions = map[string]string{
 	ComputeNetworkPortRangeEndKey:                     "PortRangeEnd is the last port in the range (inclusive) that can be allocated to jobs",
 	ComputeNetworkPortRangeStartKey:                   "PortRangeStart is the first port in the range (inclusive) that can be allocated to jobs",
 	ComputeOrchestratorsKey:                           "Orchestrators specifies a list of orchestrator endpoints that this compute node connects to.",
+	ComputeQueueAgingIntervalKey:                      "AgingInterval specifies how long an execution waits in the queue before its priority is raised by one, up to the highest priority in the queue, so that executions of low priority are not starved. Aging is disabled if zero.",
+	ComputeQueueNamespaceWeightsKey:                   "NamespaceWeights specifies the share of the node each namespace is entitled to relative to the others. Namespaces that are not listed have a weight of 1.",
+	ComputeQueueReservationThresholdKey:               "ReservationThreshold specifies how long the first queued execution that doesn't fit in the available capacity waits before smaller executions queued after it stop being run ahead of it, so that it is not starved. Smaller executions are always run ahead if zero.",
 	ComputeTLSCACertKey:                               "CACert specifies the CA file path that the compute node trusts when connecting to orchestrator.",
 	ComputeTLSRequireTLSKey:                           "RequireTLS specifies if the compute node enforces encrypted communication with orchestrator.",
 	DataDirKey:                                        "DataDir specifies a location on disk where the bacalhau node will maintain state.",
@@ -50,7 +55,16 @@ var ConfigDescriptions = map[string]string{
 	EnginesTypesDockerManifestCacheRefreshKey:         "Refresh specifies the refresh interval for cache entries.",
 	EnginesTypesDockerManifestCacheSizeKey:            "Size specifies the size of the Docker manifest cache.",
 	EnginesTypesDockerManifestCacheTTLKey:             "TTL specifies the time-to-live duration for cache entries.",
+	EnginesTypesWASMCompilationCacheEnabledKey:        "Enabled specifies whether compiled WASM modules are cached, so that modules run by many executions are compiled once.",
+	EnginesTypesWASMCompilationCacheMaxSizeKey:        "MaxSize specifies the maximum disk space used by compiled modules, such as 1GB. The least recently used modules are evicted beyond it.",
+	EnginesTypesWASMKeyValueStoreEnabledKey:           "Enabled specifies whether WASM jobs can store values in the key-value store of the node.",
+	EnginesTypesWASMKeyValueStoreMaxBucketSizeKey:     "MaxBucketSize specifies the maximum total size of the keys and values of a bucket, such as 100MB.",
+	EnginesTypesWASMKeyValueStoreMaxValueSizeKey:      "MaxValueSize specifies the maximum size of a single value, such as 1MB.",
+	InputSourcesCacheEnabledKey:                       "Enabled specifies whether inputs are cached, so that inputs used by many executions are downloaded once. Only inputs whose content cannot change are cached: URLs with an ETag, S3 objects with a version or checksum, and IPFS content.",
+	InputSourcesCacheMaxSizeKey:                       "MaxSize specifies the maximum disk space used by cached inputs, such as 10GB. The least recently used inputs are evicted beyond it.",
 	InputSourcesDisabledKey:                           "Disabled specifies a list of storages that are disabled.",
+	InputSourcesExtractionMaxRatioKey:                 "MaxRatio specifies how many times bigger than an archive its extracted content can be.",
+	InputSourcesExtractionMaxSizeKey:                  "MaxSize specifies the maximum size of the extracted content of an archive, such as 50GB. The size is only bounded by MaxRatio if empty.",
 	InputSourcesMaxRetryCountKey:                      "ReadTimeout specifies the maximum number of attempts for reading from a storage.",
 	InputSourcesReadTimeoutKey:                        "ReadTimeout specifies the maximum time allowed for reading from a storage.",
 	InputSourcesTypesIPFSEndpointKey:                  "Endpoint specifies the multi-address to connect to for IPFS. e.g /ip4/127.0.0.1/tcp/5001",
@@ -101,13 +115,20 @@ var ConfigDescriptions = map[string]string{
 	OrchestratorClusterPortKey:                        "Port specifies the port number for cluster communication.",
 	OrchestratorEnabledKey:                            "Enabled indicates whether the orchestrator node is active and available for job submission.",
 	OrchestratorEvaluationBrokerMaxRetryCountKey:      "MaxRetryCount specifies the maximum number of times an evaluation can be retried before being marked as failed.",
+	OrchestratorEvaluationBrokerTypeKey:               "Type specifies where the evaluation broker keeps evaluations. Supported values are InMemory and BoltDB.",
 	OrchestratorEvaluationBrokerVisibilityTimeoutKey:  "VisibilityTimeout specifies how long an evaluation can be claimed before it's returned to the queue.",
 	OrchestratorHostKey:                               "Host specifies the hostname or IP address on which the Orchestrator server listens for compute node connections.",
+	OrchestratorJobStoreDSNKey:                        "DSN specifies the connection string of the database when Type is Postgres, or the path of the database file when Type is SQLite, which defaults to a file in the orchestrator's data directory.",
+	OrchestratorJobStoreTypeKey:                       "Type specifies the database backing the job store. Supported values are BoltDB, SQLite and Postgres.",
+	OrchestratorLeaderElectionEnabledKey:              "Enabled runs leader election between the orchestrators of the cluster. Only the leader schedules jobs, runs housekeeping and manages compute nodes, while the others serve API requests and take over if it fails. All orchestrators must share the same Postgres job store. The database is not replicated by the orchestrators, so it remains a single point of failure of the cluster, and must be made highly available separately.",
+	OrchestratorLeaderElectionLeaseDurationKey:        "LeaseDuration specifies how long leadership is held without being renewed, which is how long it takes for another orchestrator to take over after the leader fails.",
 	OrchestratorNodeManagerDisconnectTimeoutKey:       "DisconnectTimeout specifies how long to wait before considering a node disconnected.",
 	OrchestratorNodeManagerManualApprovalKey:          "ManualApproval, if true, requires manual approval for new compute nodes joining the cluster.",
 	OrchestratorPortKey:                               "Host specifies the port number on which the Orchestrator server listens for compute node connections.",
 	OrchestratorSchedulerHousekeepingIntervalKey:      "HousekeepingInterval specifies how often to run housekeeping tasks.",
 	OrchestratorSchedulerHousekeepingTimeoutKey:       "HousekeepingTimeout specifies the maximum time allowed for a single housekeeping run.",
+	OrchestratorSchedulerPreemptionNamespacesKey:      "Namespaces specifies the preemption policy of jobs in specific namespaces, overriding the default policy.",
+	OrchestratorSchedulerPreemptionPolicyKey:          "Policy specifies the default preemption policy for jobs in all namespaces: never, namespace to only preempt lower priority jobs in the same namespace, or any to preempt lower priority jobs in any namespace.",
 	OrchestratorSchedulerQueueBackoffKey:              "QueueBackoff specifies the time to wait before retrying a failed job.",
 	OrchestratorSchedulerWorkerCountKey:               "WorkerCount specifies the number of concurrent workers for job scheduling.",
 	OrchestratorSupportReverseProxyKey:                "SupportReverseProxy configures the orchestrator node to run behind a reverse proxy",
@@ -115,6 +136,11 @@ var ConfigDescriptions = map[string]string{
 	OrchestratorTLSServerCertKey:                      "ServerCert specifies the certificate file path given to NATS server to serve TLS connections.",
 	OrchestratorTLSServerKeyKey:                       "ServerKey specifies the private key file path given to NATS server to serve TLS connections.",
 	OrchestratorTLSServerTimeoutKey:                   "ServerTimeout specifies the TLS timeout, in seconds, set on the NATS server.",
+	OrchestratorWebhooksAllowPrivateAddressesKey:      "AllowPrivateAddresses allows the webhooks declared by jobs to target loopback, link-local and private addresses. Webhooks configured for namespaces can always target them.",
+	OrchestratorWebhooksMaxAttemptsKey:                "MaxAttempts specifies the maximum number of attempts to deliver a notification.",
+	OrchestratorWebhooksNamespacesKey:                 "Namespaces specifies webhooks notified of the transitions of all jobs in specific namespaces.",
+	OrchestratorWebhooksSigningKeyKey:                 "SigningKey is the secret used to sign webhook payloads with HMAC-SHA256. The signature is sent in the X-Bacalhau-Signature header. Payloads are not signed if empty.",
+	OrchestratorWebhooksTimeoutKey:                    "Timeout specifies the timeout of a single delivery attempt.",
 	PublishersDisabledKey:                             "Disabled specifies a list of publishers that are disabled.",
 	PublishersTypesIPFSEndpointKey:                    "Endpoint specifies the multi-address to connect to for IPFS. e.g /ip4/127.0.0.1/tcp/5001",
 	PublishersTypesLocalAddressKey:                    "Address specifies the endpoint the publisher serves on.",
@@ -1,5 +1,9 @@
 package types
 
+import (
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
 type Orchestrator struct {
 	// Enabled indicates whether the orchestrator node is active and available for job submission.
 	Enabled bool `yaml:"Enabled,omitempty" json:"Enabled,omitempty"`
@@ -17,6 +21,12 @@ type Orchestrator struct {
 	NodeManager      NodeManager      `yaml:"NodeManager,omitempty" json:"NodeManager,omitempty"`
 	Scheduler        Scheduler        `yaml:"Scheduler,omitempty" json:"Scheduler,omitempty"`
 	EvaluationBroker EvaluationBroker `yaml:"EvaluationBroker,omitempty" json:"EvaluationBroker,omitempty"`
+	// JobStore configures the database in which the orchestrator stores jobs, executions and their history.
+	JobStore JobStore `yaml:"JobStore,omitempty" json:"JobStore,omitempty"`
+	// LeaderElection configures electing a single active leader among the orchestrators of a cluster.
+	LeaderElection LeaderElection `yaml:"LeaderElection,omitempty" json:"LeaderElection,omitempty"`
+	// Webhooks configures the notifications sent to HTTP endpoints on job lifecycle transitions.
+	Webhooks Webhooks `yaml:"Webhooks,omitempty" json:"Webhooks,omitempty"`
 	// SupportReverseProxy configures the orchestrator node to run behind a reverse proxy
 	SupportReverseProxy bool `yaml:"SupportReverseProxy,omitempty" json:"SupportReverseProxy,omitempty"`
 }
@@ -68,9 +78,73 @@ type Scheduler struct {
 	HousekeepingInterval Duration `yaml:"HousekeepingInterval,omitempty" json:"HousekeepingInterval,omitempty"`
 	// HousekeepingTimeout specifies the maximum time allowed for a single housekeeping run.
 	HousekeepingTimeout Duration `yaml:"HousekeepingTimeout,omitempty" json:"HousekeepingTimeout,omitempty"`
+	// Preemption configures stopping executions of lower priority jobs when no node has capacity for a job.
+	Preemption Preemption `yaml:"Preemption,omitempty" json:"Preemption,omitempty"`
+}
+
+type Preemption struct {
+	// Policy specifies the default preemption policy for jobs in all namespaces: never, namespace to only
+	// preempt lower priority jobs in the same namespace, or any to preempt lower priority jobs in any namespace.
+	Policy string `yaml:"Policy,omitempty" json:"Policy,omitempty"`
+	// Namespaces specifies the preemption policy of jobs in specific namespaces, overriding the default policy.
+	Namespaces map[string]string `yaml:"Namespaces,omitempty" json:"Namespaces,omitempty"`
+}
+
+type Webhooks struct {
+	// SigningKey is the secret used to sign webhook payloads with HMAC-SHA256.
+	// The signature is sent in the X-Bacalhau-Signature header. Payloads are not signed if empty.
+	SigningKey string `yaml:"SigningKey,omitempty" json:"SigningKey,omitempty"`
+	// Namespaces specifies webhooks notified of the transitions of all jobs in specific namespaces.
+	Namespaces map[string][]models.Webhook `yaml:"Namespaces,omitempty" json:"Namespaces,omitempty"`
+	// MaxAttempts specifies the maximum number of attempts to deliver a notification.
+	MaxAttempts int `yaml:"MaxAttempts,omitempty" json:"MaxAttempts,omitempty"`
+	// Timeout specifies the timeout of a single delivery attempt.
+	Timeout Duration `yaml:"Timeout,omitempty" json:"Timeout,omitempty"`
+	// AllowPrivateAddresses allows the webhooks declared by jobs to target loopback, link-local and private addresses.
+	// Webhooks configured for namespaces can always target them.
+	AllowPrivateAddresses bool `yaml:"AllowPrivateAddresses,omitempty" json:"AllowPrivateAddresses,omitempty"`
+}
+
+const (
+	// JobStoreTypeBoltDB stores jobs in a BoltDB file in the orchestrator's data directory.
+	JobStoreTypeBoltDB = "BoltDB"
+	// JobStoreTypeSQLite stores jobs in a SQLite file, by default in the orchestrator's data directory.
+	JobStoreTypeSQLite = "SQLite"
+	// JobStoreTypePostgres stores jobs in the PostgreSQL database identified by the DSN.
+	JobStoreTypePostgres = "Postgres"
+)
+
+type JobStore struct {
+	// Type specifies the database backing the job store. Supported values are BoltDB, SQLite and Postgres.
+	Type string `yaml:"Type,omitempty" json:"Type,omitempty"`
+	// DSN specifies the connection string of the database when Type is Postgres, or the path of the
+	// database file when Type is SQLite, which defaults to a file in the orchestrator's data directory.
+	DSN string `yaml:"DSN,omitempty" json:"DSN,omitempty"`
+}
+
+type LeaderElection struct {
+	// Enabled runs leader election between the orchestrators of the cluster. Only the leader schedules jobs,
+	// runs housekeeping and manages compute nodes, while the others serve API requests and take over if it fails.
+	// All orchestrators must share the same Postgres job store. The database is not replicated by the orchestrators,
+	// so it remains a single point of failure of the cluster, and must be made highly available separately.
+	Enabled bool `yaml:"Enabled,omitempty" json:"Enabled,omitempty"`
+	// LeaseDuration specifies how long leadership is held without being renewed,
+	// which is how long it takes for another orchestrator to take over after the leader fails.
+	LeaseDuration Duration `yaml:"LeaseDuration,omitempty" json:"LeaseDuration,omitempty"`
 }
 
+const (
+	// EvaluationBrokerTypeInMemory keeps evaluations in memory, losing the pending ones when the orchestrator restarts.
+	EvaluationBrokerTypeInMemory = "InMemory"
+	// EvaluationBrokerTypeBoltDB also persists evaluations in a BoltDB file in the orchestrator's data directory,
+	// restoring the pending, delayed and inflight ones when the orchestrator restarts. With leader election, they are
+	// discarded instead, as an elected leader creates evaluations of the in progress jobs of the shared job store.
+	EvaluationBrokerTypeBoltDB = "BoltDB"
+)
+
 type EvaluationBroker struct {
+	// Type specifies where the evaluation broker keeps evaluations. Supported values are InMemory and BoltDB.
+	Type string `yaml:"Type,omitempty" json:"Type,omitempty"`
 	// VisibilityTimeout specifies how long an evaluation can be claimed before it's returned to the queue.
 	VisibilityTimeout Duration `yaml:"VisibilityTimeout,omitempty" json:"VisibilityTimeout,omitempty"`
 	// MaxRetryCount specifies the maximum number of times an evaluation can be retried before being marked as failed.
@@ -68,6 +68,32 @@ func (b Bacalhau) JobStoreFilePath() (string, error) {
 	return filepath.Join(b.DataDir, OrchestratorDirName, JobStoreFileName), nil
 }
 
+const SQLJobStoreFileName = "state.sqlite"
+
+func (b Bacalhau) SQLJobStoreFilePath() (string, error) {
+	if b.DataDir == "" {
+		return "", fmt.Errorf("data dir not set")
+	}
+	// make sure the parent dir exists first
+	if _, err := b.OrchestratorDir(); err != nil {
+		return "", fmt.Errorf("getting job store path: %w", err)
+	}
+	return filepath.Join(b.DataDir, OrchestratorDirName, SQLJobStoreFileName), nil
+}
+
+const EvaluationBrokerFileName = "evaluations_boltdb.db"
+
+func (b Bacalhau) EvaluationBrokerFilePath() (string, error) {
+	if b.DataDir == "" {
+		return "", fmt.Errorf("data dir not set")
+	}
+	// make sure the parent dir exists first
+	if _, err := b.OrchestratorDir(); err != nil {
+		return "", fmt.Errorf("getting evaluation broker path: %w", err)
+	}
+	return filepath.Join(b.DataDir, OrchestratorDirName, EvaluationBrokerFileName), nil
+}
+
 const NetworkTransportDirName = "nats-store"
 
 func (b Bacalhau) NetworkTransportDir() (string, error) {
@@ -94,6 +120,44 @@ func (b Bacalhau) ComputeDir() (string, error) {
 	return path, nil
 }
 
+const WASMCompilationCacheDirName = "wasm-cache"
+
+func (b Bacalhau) WASMCompilationCacheDir() (string, error) {
+	if b.DataDir == "" {
+		return "", fmt.Errorf("data dir not set")
+	}
+	path := filepath.Join(b.DataDir, ComputeDirName, WASMCompilationCacheDirName)
+	if err := ensureDir(path); err != nil {
+		return "", fmt.Errorf("getting wasm compilation cache path: %w", err)
+	}
+	return path, nil
+}
+
+const InputCacheDirName = "input-cache"
+
+func (b Bacalhau) InputCacheDir() (string, error) {
+	if b.DataDir == "" {
+		return "", fmt.Errorf("data dir not set")
+	}
+	path := filepath.Join(b.DataDir, ComputeDirName, InputCacheDirName)
+	if err := ensureDir(path); err != nil {
+		return "", fmt.Errorf("getting input cache path: %w", err)
+	}
+	return path, nil
+}
+
+const WASMKeyValueStoreFileName = "wasm_kv_boltdb.db"
+
+func (b Bacalhau) WASMKeyValueStoreFilePath() (string, error) {
+	if b.DataDir == "" {
+		return "", fmt.Errorf("data dir not set")
+	}
+	if _, err := b.ComputeDir(); err != nil {
+		return "", fmt.Errorf("getting wasm key-value store path: %w", err)
+	}
+	return filepath.Join(b.DataDir, ComputeDirName, WASMKeyValueStoreFileName), nil
+}
+
 const ExecutionDirName = "executions"
 
 func (b Bacalhau) ExecutionDir() (string, error) {
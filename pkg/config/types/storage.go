@@ -15,6 +15,34 @@ type InputSourcesConfig struct {
 	// ReadTimeout specifies the maximum number of attempts for reading from a storage.
 	MaxRetryCount int               `yaml:"MaxRetryCount,omitempty" json:"MaxRetryCount,omitempty"`
 	Types         InputSourcesTypes `yaml:"Types,omitempty" json:"Types,omitempty"`
+	// Cache specifies the settings for the cache of inputs shared by all executions of the compute node.
+	Cache InputCache `yaml:"Cache,omitempty" json:"Cache,omitempty"`
+	// Extraction specifies the limits of extracting archive inputs, which bound the disk space
+	// reserved for their extracted content.
+	Extraction InputExtraction `yaml:"Extraction,omitempty" json:"Extraction,omitempty"`
+}
+
+// InputCache represents the configuration settings for the cache of the inputs of executions,
+// which is stored in the compute node's data directory.
+type InputCache struct {
+	// Enabled specifies whether inputs are cached, so that inputs used by many executions are downloaded once.
+	// Only inputs whose content cannot change are cached: URLs with an ETag, S3 objects with a version or
+	// checksum, and IPFS content.
+	Enabled bool `yaml:"Enabled,omitempty" json:"Enabled,omitempty"`
+	// MaxSize specifies the maximum disk space used by cached inputs, such as 10GB.
+	// The least recently used inputs are evicted beyond it.
+	MaxSize string `yaml:"MaxSize,omitempty" json:"MaxSize,omitempty"`
+}
+
+// InputExtraction represents the limits of extracting the archive inputs of executions.
+// The extracted content of an archive can be up to MaxRatio times the size of the archive,
+// and no bigger than MaxSize.
+type InputExtraction struct {
+	// MaxRatio specifies how many times bigger than an archive its extracted content can be.
+	MaxRatio int `yaml:"MaxRatio,omitempty" json:"MaxRatio,omitempty"`
+	// MaxSize specifies the maximum size of the extracted content of an archive, such as 50GB.
+	// The size is only bounded by MaxRatio if empty.
+	MaxSize string `yaml:"MaxSize,omitempty" json:"MaxSize,omitempty"`
 }
 
 type InputSourcesTypes struct {
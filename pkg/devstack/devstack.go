@@ -3,13 +3,16 @@ package devstack
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/imdario/mergo"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	"go.ptx.dk/multierrgroup"
 
 	"github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/config/types"
@@ -26,6 +29,8 @@ type DevStack struct {
 	config                *DevStackConfig
 	nextNodeID            int
 	orchestratorEndpoints []string
+	// clusterEndpoints are the NATS cluster addresses of the orchestrators when running with leader election
+	clusterEndpoints []string
 }
 
 func Setup(
@@ -66,6 +71,7 @@ func Setup(
 	requesterNodeCount := stackConfig.NumberOfHybridNodes + stackConfig.NumberOfRequesterOnlyNodes
 	computeNodeCount := stackConfig.NumberOfHybridNodes + stackConfig.NumberOfComputeOnlyNodes
 
+	nodeOptions := make([]JoinNodeOptions, totalNodeCount)
 	for i := 0; i < totalNodeCount; i++ {
 		isRequesterNode := i < requesterNodeCount
 		isComputeNode := (totalNodeCount - i) <= computeNodeCount
@@ -75,13 +81,28 @@ func Setup(
 			nodeOverride = &stackConfig.NodeOverrides[i]
 		}
 
-		_, err := stack.JoinNode(ctx, cm, JoinNodeOptions{
+		nodeOptions[i] = JoinNodeOptions{
 			IsRequester:     isRequesterNode,
 			IsCompute:       isComputeNode,
 			BadComputeActor: isBadComputeActor,
 			ConfigOverride:  nodeOverride,
-		})
-		if err != nil {
+		}
+	}
+
+	if stackConfig.LeaderElection {
+		// orchestrators of a cluster wait for a majority of their peers before starting,
+		// so they are all configured as peers of each other and started together
+		if err = stack.allocateClusterEndpoints(requesterNodeCount); err != nil {
+			return nil, err
+		}
+		if err = stack.joinNodesTogether(ctx, cm, nodeOptions[:requesterNodeCount]); err != nil {
+			return nil, err
+		}
+		nodeOptions = nodeOptions[requesterNodeCount:]
+	}
+
+	for _, options := range nodeOptions {
+		if _, err = stack.JoinNode(ctx, cm, options); err != nil {
 			return nil, err
 		}
 	}
@@ -117,6 +138,48 @@ func (stack *DevStack) JoinHybridNode(ctx context.Context, cm *system.CleanupMan
 }
 
 func (stack *DevStack) JoinNode(ctx context.Context, cm *system.CleanupManager, options JoinNodeOptions) (*node.Node, error) {
+	nodeConfig, err := stack.newNodeConfig(ctx, cm, options)
+	if err != nil {
+		return nil, err
+	}
+	n, err := startNode(ctx, nodeConfig)
+	if err != nil {
+		return nil, err
+	}
+	stack.Nodes = append(stack.Nodes, n)
+	return n, nil
+}
+
+// joinNodesTogether creates the nodes one after the other, and starts them concurrently
+func (stack *DevStack) joinNodesTogether(ctx context.Context, cm *system.CleanupManager, options []JoinNodeOptions) error {
+	nodeConfigs := make([]node.NodeConfig, len(options))
+	for i := range options {
+		var err error
+		if nodeConfigs[i], err = stack.newNodeConfig(ctx, cm, options[i]); err != nil {
+			return err
+		}
+	}
+
+	nodes := make([]*node.Node, len(nodeConfigs))
+	wg := multierrgroup.Group{}
+	for i := range nodeConfigs {
+		wg.Go(func() error {
+			var err error
+			nodes[i], err = startNode(ctx, nodeConfigs[i])
+			return err
+		})
+	}
+	if err := wg.Wait(); err != nil {
+		return err
+	}
+	stack.Nodes = append(stack.Nodes, nodes...)
+	return nil
+}
+
+// newNodeConfig creates the configuration of the next node joining the devstack
+//
+//nolint:funlen
+func (stack *DevStack) newNodeConfig(ctx context.Context, cm *system.CleanupManager, options JoinNodeOptions) (node.NodeConfig, error) {
 	nodeID := fmt.Sprintf("node-%d", stack.nextNodeID)
 	ctx = logger.ContextWithNodeIDLogger(ctx, nodeID)
 
@@ -131,7 +194,7 @@ func (stack *DevStack) JoinNode(ctx context.Context, cm *system.CleanupManager,
 	if options.IsCompute && !options.IsRequester {
 		orchestrators := stack.orchestratorEndpoints
 		if len(orchestrators) == 0 {
-			return nil, fmt.Errorf("cannot add compute node: no orchestrators found in the existing devstack")
+			return node.NodeConfig{}, fmt.Errorf("cannot add compute node: no orchestrators found in the existing devstack")
 		}
 		cfg.Compute.Orchestrators = orchestrators
 	}
@@ -142,7 +205,12 @@ func (stack *DevStack) JoinNode(ctx context.Context, cm *system.CleanupManager,
 			cfg.Orchestrator.Port = cfg.Orchestrator.Port + stack.nextNodeID
 		} else {
 			if cfg.Orchestrator.Port, err = network.GetFreePort(); err != nil {
-				return nil, errors.Wrap(err, "failed to get free port for nats server")
+				return node.NodeConfig{}, errors.Wrap(err, "failed to get free port for nats server")
+			}
+		}
+		if stack.config.LeaderElection {
+			if err = stack.configureLeaderElection(&cfg); err != nil {
+				return node.NodeConfig{}, err
 			}
 		}
 		// Store the new orchestrator endpoint for future compute nodes
@@ -158,14 +226,14 @@ func (stack *DevStack) JoinNode(ctx context.Context, cm *system.CleanupManager,
 		}
 	} else {
 		if cfg.API.Port, err = network.GetFreePort(); err != nil {
-			return nil, errors.Wrap(err, "failed to get free port for API server")
+			return node.NodeConfig{}, errors.Wrap(err, "failed to get free port for API server")
 		}
 	}
 
 	if options.IsCompute {
 		freePort, err := network.GetFreePort()
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to get free port for local publisher")
+			return node.NodeConfig{}, errors.Wrap(err, "failed to get free port for local publisher")
 		}
 		cfg.Publishers.Types.Local.Port = freePort
 	}
@@ -184,12 +252,12 @@ func (stack *DevStack) JoinNode(ctx context.Context, cm *system.CleanupManager,
 		},
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to merge config")
+		return node.NodeConfig{}, errors.Wrap(err, "failed to merge config")
 	}
 
 	// Create node data directory
 	if err = os.MkdirAll(cfg.DataDir, util.OS_USER_RWX); err != nil {
-		return nil, errors.Wrap(err, "failed to create node data directory")
+		return node.NodeConfig{}, errors.Wrap(err, "failed to create node data directory")
 	}
 
 	// Create node config
@@ -204,28 +272,77 @@ func (stack *DevStack) JoinNode(ctx context.Context, cm *system.CleanupManager,
 		},
 	}
 
+	// the orchestrators of the devstack are cluster peers on the same host
+	nodeConfig.SystemConfig.AllowLocalClusterPeers = stack.config.LeaderElection
+
 	// allow overriding configs of some nodes
 	if options.ConfigOverride != nil {
 		originalConfig := nodeConfig
 		nodeConfig = *options.ConfigOverride
 		err = mergo.Merge(&nodeConfig, originalConfig)
 		if err != nil {
-			return nil, err
+			return node.NodeConfig{}, err
+		}
+	}
+
+	stack.nextNodeID++
+	return nodeConfig, nil
+}
+
+// configureLeaderElection configures the next orchestrator to join the cluster of the
+// devstack's orchestrators, which share the Postgres job store of the configuration
+func (stack *DevStack) configureLeaderElection(cfg *types.Bacalhau) error {
+	orchestratorIndex := len(stack.orchestratorEndpoints)
+	if err := stack.allocateClusterEndpoints(orchestratorIndex + 1); err != nil {
+		return err
+	}
+
+	var peers []string
+	for i, endpoint := range stack.clusterEndpoints {
+		if i != orchestratorIndex {
+			peers = append(peers, endpoint)
+		}
+	}
+	_, port, err := net.SplitHostPort(stack.clusterEndpoints[orchestratorIndex])
+	if err != nil {
+		return err
+	}
+	cfg.Orchestrator.Cluster = types.Cluster{Name: "devstack", Host: "127.0.0.1", Peers: peers}
+	if cfg.Orchestrator.Cluster.Port, err = strconv.Atoi(port); err != nil {
+		return err
+	}
+
+	if cfg.Orchestrator.JobStore.Type != types.JobStoreTypePostgres || cfg.Orchestrator.JobStore.DSN == "" {
+		return fmt.Errorf("leader election requires a job store shared by the orchestrators: set %s to %s and %s",
+			types.OrchestratorJobStoreTypeKey, types.JobStoreTypePostgres, types.OrchestratorJobStoreDSNKey)
+	}
+	cfg.Orchestrator.LeaderElection.Enabled = true
+	return nil
+}
+
+// allocateClusterEndpoints allocates NATS cluster addresses until there are enough for the given number of orchestrators
+func (stack *DevStack) allocateClusterEndpoints(count int) error {
+	for len(stack.clusterEndpoints) < count {
+		port, err := network.GetFreePort()
+		if err != nil {
+			return errors.Wrap(err, "failed to get free port for nats cluster")
 		}
+		stack.clusterEndpoints = append(stack.clusterEndpoints, fmt.Sprintf("127.0.0.1:%d", port))
 	}
+	return nil
+}
 
-	// Create and start the node
+// startNode creates and starts a node
+func startNode(ctx context.Context, nodeConfig node.NodeConfig) (*node.Node, error) {
+	ctx = logger.ContextWithNodeIDLogger(ctx, nodeConfig.NodeID)
 	n, err := node.NewNode(ctx, nodeConfig, NewMetadataStore())
 	if err != nil {
-		return nil, fmt.Errorf("failed to create node %s: %w", nodeID, err)
+		return nil, fmt.Errorf("failed to create node %s: %w", nodeConfig.NodeID, err)
 	}
 
 	if err = n.Start(ctx); err != nil {
-		return nil, fmt.Errorf("failed to start node %s: %w", nodeID, err)
+		return nil, fmt.Errorf("failed to start node %s: %w", nodeConfig.NodeID, err)
 	}
-
-	stack.Nodes = append(stack.Nodes, n)
-	stack.nextNodeID++
 	return n, nil
 }
 
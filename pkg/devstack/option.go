@@ -58,6 +58,9 @@ type DevStackConfig struct {
 	CPUProfilingFile           string
 	MemoryProfilingFile        string
 	BasePath                   string
+	// LeaderElection runs the orchestrators as a NATS cluster electing a leader, sharing
+	// the state replicated through NATS and the Postgres job store of the configuration
+	LeaderElection bool
 }
 
 func (o *DevStackConfig) MarshalZerologObject(e *zerolog.Event) {
@@ -66,7 +69,8 @@ func (o *DevStackConfig) MarshalZerologObject(e *zerolog.Event) {
 		Int("ComputeOnlyNodes", o.NumberOfComputeOnlyNodes).
 		Int("BadComputeActors", o.NumberOfBadComputeActors).
 		Str("CPUProfilingFile", o.CPUProfilingFile).
-		Str("MemoryProfilingFile", o.MemoryProfilingFile)
+		Str("MemoryProfilingFile", o.MemoryProfilingFile).
+		Bool("LeaderElection", o.LeaderElection)
 }
 
 func (o *DevStackConfig) Validate() error {
@@ -179,6 +183,12 @@ func WithAllowListedLocalPaths(paths []string) ConfigOption {
 	}
 }
 
+func WithAllowListedBinaries(binaries []string) ConfigOption {
+	return func(cfg *DevStackConfig) {
+		cfg.BacalhauConfig.Compute.AllowListedBinaries = binaries
+	}
+}
+
 func WithDefaultPublisher(publisher types.DefaultPublisherConfig) ConfigOption {
 	return func(cfg *DevStackConfig) {
 		cfg.BacalhauConfig.JobDefaults.Batch.Task.Publisher = publisher
@@ -200,6 +210,13 @@ func WithSelfSignedCertificate(cert string, key string) ConfigOption {
 	}
 }
 
+// WithLeaderElection runs the orchestrators as a cluster electing a leader
+func WithLeaderElection(enabled bool) ConfigOption {
+	return func(cfg *DevStackConfig) {
+		cfg.LeaderElection = enabled
+	}
+}
+
 // WithUseStandardPorts sets the standard ports for the services
 func WithUseStandardPorts(standardPorts bool) ConfigOption {
 	return func(cfg *DevStackConfig) {
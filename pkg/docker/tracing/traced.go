@@ -56,6 +56,46 @@ func (c TracedClient) ContainerCreate(
 	)
 }
 
+func (c TracedClient) ContainerAttach(
+	ctx context.Context,
+	containerID string,
+	options container.AttachOptions,
+) (types.HijackedResponse, error) {
+	ctx, span := c.span(ctx, "container.attach")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[types.HijackedResponse](span)(c.client.ContainerAttach(ctx, containerID, options))
+}
+
+func (c TracedClient) ContainerExecCreate(
+	ctx context.Context,
+	containerID string,
+	options container.ExecOptions,
+) (container.ExecCreateResponse, error) {
+	ctx, span := c.span(ctx, "container.exec.create")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[container.ExecCreateResponse](span)(c.client.ContainerExecCreate(ctx, containerID, options))
+}
+
+func (c TracedClient) ContainerExecAttach(
+	ctx context.Context,
+	execID string,
+	options container.ExecAttachOptions,
+) (types.HijackedResponse, error) {
+	ctx, span := c.span(ctx, "container.exec.attach")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[types.HijackedResponse](span)(c.client.ContainerExecAttach(ctx, execID, options))
+}
+
+func (c TracedClient) ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error) {
+	ctx, span := c.span(ctx, "container.exec.inspect")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[container.ExecInspect](span)(c.client.ContainerExecInspect(ctx, execID))
+}
+
 func (c TracedClient) ContainerInspect(ctx context.Context, containerID string) (container.InspectResponse, error) {
 	ctx, span := c.span(ctx, "container.inspect")
 	defer span.End()
@@ -63,6 +103,14 @@ func (c TracedClient) ContainerInspect(ctx context.Context, containerID string)
 	return telemetry.RecordErrorOnSpanTwo[container.InspectResponse](span)(c.client.ContainerInspect(ctx, containerID))
 }
 
+func (c TracedClient) ContainerInspectWithSize(ctx context.Context, containerID string) (container.InspectResponse, error) {
+	ctx, span := c.span(ctx, "container.inspect")
+	defer span.End()
+
+	response, _, err := c.client.ContainerInspectWithRaw(ctx, containerID, true)
+	return telemetry.RecordErrorOnSpanTwo[container.InspectResponse](span)(response, err)
+}
+
 func (c TracedClient) ContainerList(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
 	ctx, span := c.span(ctx, "container.list")
 	defer span.End()
@@ -91,6 +139,15 @@ func (c TracedClient) ContainerStart(ctx context.Context, id string, options con
 	return telemetry.RecordErrorOnSpan(span)(c.client.ContainerStart(ctx, id, options))
 }
 
+func (c TracedClient) ContainerStats(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error) {
+	ctx, span := c.span(ctx, "container.stats")
+	// span ends when the body is closed
+
+	stats, err := c.client.ContainerStats(ctx, containerID, stream)
+	stats.Body, err = telemetry.RecordErrorOnSpanReadCloserAndClose(span)(stats.Body, err)
+	return stats, err
+}
+
 func (c TracedClient) ContainerStop(ctx context.Context, containerID string, timeout time.Duration) error {
 	ctx, span := c.span(ctx, "container.stop")
 	defer span.End()
@@ -0,0 +1,118 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/bacalhau-project/bacalhau/pkg/docker"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+)
+
+// Exec runs a command inside the container of a running execution, or attaches to the main process
+// of the container if no command is given. Input is only forwarded to the main process if the
+// container was created with an open stdin.
+func (e *Executor) Exec(ctx context.Context, request *executor.ExecRequest) (int, error) {
+	handler, found := e.handlers.Get(request.ExecutionID)
+	if !found || !handler.active() {
+		return 0, executor.NewExecutorError(executor.ExecutionNotFound,
+			fmt.Sprintf("execution (%s) is not running", request.ExecutionID))
+	}
+	if len(request.Command) == 0 {
+		return handler.attach(ctx, request)
+	}
+	return handler.exec(ctx, request)
+}
+
+// exec runs a command inside the container and returns its exit code
+func (h *executionHandler) exec(ctx context.Context, request *executor.ExecRequest) (int, error) {
+	created, err := h.client.ContainerExecCreate(ctx, h.containerID, container.ExecOptions{
+		Cmd:          request.Command,
+		AttachStdin:  request.Stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return 0, docker.NewDockerError(err)
+	}
+	hijacked, err := h.client.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return 0, docker.NewDockerError(err)
+	}
+	if err = streamHijacked(ctx, hijacked, request.Stdin, request.Stdout, request.Stderr); err != nil {
+		return 0, err
+	}
+	inspect, err := h.client.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return 0, docker.NewDockerError(err)
+	}
+	return inspect.ExitCode, nil
+}
+
+// attach attaches to the main process of the container and returns its exit code once it exits
+func (h *executionHandler) attach(ctx context.Context, request *executor.ExecRequest) (int, error) {
+	inspect, err := h.client.ContainerInspect(ctx, h.containerID)
+	if err != nil {
+		return 0, docker.NewDockerError(err)
+	}
+	stdin := request.Stdin
+	if !inspect.Config.OpenStdin {
+		stdin = nil
+	}
+	hijacked, err := h.client.ContainerAttach(ctx, h.containerID, container.AttachOptions{
+		Stream: true,
+		Stdin:  stdin != nil,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return 0, docker.NewDockerError(err)
+	}
+	if err = streamHijacked(ctx, hijacked, stdin, request.Stdout, request.Stderr); err != nil {
+		return 0, err
+	}
+
+	// the output ends when the main process exits, wait for the handler to collect its result
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-h.waitCh:
+	}
+	if h.result == nil {
+		return 0, fmt.Errorf("execution (%s) result is nil", h.executionID)
+	}
+	return h.result.ExitCode, nil
+}
+
+// streamHijacked copies the input to a hijacked connection and its multiplexed output to stdout and stderr,
+// until the output ends or the context is done.
+func streamHijacked(ctx context.Context, hijacked types.HijackedResponse, stdin io.Reader, stdout, stderr io.Writer) error {
+	defer hijacked.Close()
+
+	if stdin != nil {
+		go func() {
+			_, _ = io.Copy(hijacked.Conn, stdin)
+			_ = hijacked.CloseWrite()
+		}()
+	}
+
+	outputDone := make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(stdout, stderr, hijacked.Reader)
+		outputDone <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-outputDone:
+		return err
+	}
+}
+
+// Compile-time interface check:
+var _ executor.Execer = (*Executor)(nil)
@@ -89,6 +89,14 @@ func (h *executionHandler) run(ctx context.Context) {
 	// The container is now active
 	close(h.activeCh)
 
+	// Sample the resource usage of the container until it exits, and report it with its result
+	sampler := h.sampleUsage(ctx)
+	defer func() {
+		if h.result != nil {
+			h.result.ResourceUsage = h.usage(ctx, sampler)
+		}
+	}()
+
 	// Capture the container logs in a separate goroutine.
 	logCaptureErrCh := make(chan error, 1)
 	go func() {
@@ -0,0 +1,102 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// usageInspectTimeout is how long to wait for the size of the container once it exited
+const usageInspectTimeout = 10 * time.Second
+
+// usageSampler samples the resource usage of a running container from its stats stream,
+// which docker reports about once a second from the cgroup of the container.
+type usageSampler struct {
+	cancel  context.CancelFunc
+	done    chan struct{}
+	started time.Time
+
+	mu         sync.Mutex
+	cpuUsage   uint64 // cumulative CPU time in nanoseconds
+	peakMemory uint64 // highest working set in bytes
+}
+
+// sampleUsage starts sampling the resource usage of the container of the handler until usage is called
+func (h *executionHandler) sampleUsage(ctx context.Context) *usageSampler {
+	ctx, cancel := context.WithCancel(ctx)
+	sampler := &usageSampler{
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		started: time.Now(),
+	}
+	go func() {
+		defer close(sampler.done)
+		stats, err := h.client.ContainerStats(ctx, h.containerID, true)
+		if err != nil {
+			h.logger.Debug().Err(err).Msg("failed to sample container resource usage")
+			return
+		}
+		defer stats.Body.Close()
+		decoder := json.NewDecoder(stats.Body)
+		for {
+			var sample container.StatsResponse
+			if err = decoder.Decode(&sample); err != nil {
+				return
+			}
+			sampler.record(sample)
+		}
+	}()
+	return sampler
+}
+
+func (s *usageSampler) record(sample container.StatsResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// samples taken after the container exited are zeroed, so keep the highest values seen
+	s.cpuUsage = max(s.cpuUsage, sample.CPUStats.CPUUsage.TotalUsage)
+	s.peakMemory = max(s.peakMemory, sample.MemoryStats.MaxUsage, workingSet(sample.MemoryStats))
+}
+
+// workingSet returns the memory used by the container without its inactive page cache,
+// the same way docker stats does, as the kernel reclaims it under memory pressure.
+func workingSet(stats container.MemoryStats) uint64 {
+	inactive, found := stats.Stats["inactive_file"] // cgroup v2
+	if !found {
+		inactive = stats.Stats["total_inactive_file"] // cgroup v1
+	}
+	if inactive > stats.Usage {
+		return 0
+	}
+	return stats.Usage - inactive
+}
+
+// usage stops sampling and returns the resource usage of the container, including the size of its
+// writable layer. It must be called once the container exited, and before it is removed.
+func (h *executionHandler) usage(ctx context.Context, sampler *usageSampler) *models.ResourceUsage {
+	wall := time.Since(sampler.started)
+	sampler.cancel()
+	<-sampler.done
+
+	sampler.mu.Lock()
+	usage := &models.ResourceUsage{
+		CPUSeconds:  time.Duration(sampler.cpuUsage).Seconds(), //nolint:gosec // CPU time fits in a duration
+		WallSeconds: wall.Seconds(),
+		PeakMemory:  sampler.peakMemory,
+	}
+	sampler.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), usageInspectTimeout)
+	defer cancel()
+	inspect, err := h.client.ContainerInspectWithSize(ctx, h.containerID)
+	if err != nil {
+		h.logger.Debug().Err(err).Msg("failed to inspect the size of the container")
+	} else if inspect.SizeRw != nil && *inspect.SizeRw > 0 {
+		usage.Disk = uint64(*inspect.SizeRw) //nolint:gosec // checked positive above
+	}
+	return usage
+}
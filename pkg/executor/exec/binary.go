@@ -0,0 +1,32 @@
+package exec
+
+import (
+	"os/exec"
+	"path/filepath"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// resolveBinary returns the absolute path of the binary run by a command,
+// which is either an absolute path or a name looked up in the PATH of the compute node.
+func resolveBinary(command string) (string, error) {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return "", NewBinaryNotFoundError(command, err)
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return "", NewBinaryNotFoundError(command, err)
+	}
+	return path, nil
+}
+
+// isAllowListed returns whether the binary at path matches one of the allow-listed paths or glob patterns
+func isAllowListed(allowList []string, path string) bool {
+	for _, pattern := range allowList {
+		if match, err := doublestar.PathMatch(filepath.Clean(pattern), path); err == nil && match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+//go:build linux
+
+package exec
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const (
+	cgroupRoot = "/sys/fs/cgroup"
+	// cgroupCPUPeriod is the period in microseconds over which the CPU quota of an execution applies
+	cgroupCPUPeriod = 100000
+	// cgroupRemoveTimeout is how long to wait for the processes of a cgroup to exit before giving up removing it
+	cgroupRemoveTimeout = 5 * time.Second
+)
+
+// cgroup limits the resources of the processes of an execution with a cgroup v2,
+// created as a child of the cgroup of the compute node.
+type cgroup struct {
+	path string
+	dir  *os.File
+}
+
+// newCgroup creates a cgroup limiting the CPU and memory of an execution.
+// It returns nil if cgroups v2 are not available, or if the compute node can't delegate
+// the CPU and memory controllers to child cgroups, in which case resources are not limited.
+func newCgroup(name string, resources *models.Resources) (*cgroup, error) {
+	if resources == nil || (resources.CPU == 0 && resources.Memory == 0) {
+		return nil, nil
+	}
+	parent, err := delegatingCgroup()
+	if err != nil {
+		log.Debug().Err(err).Msg("cgroups v2 are not available, not limiting the resources of exec executions")
+		return nil, nil
+	}
+
+	path := filepath.Join(parent, name)
+	if err = os.Mkdir(path, 0o755); err != nil && !errors.Is(err, os.ErrExist) { //nolint:mnd
+		return nil, NewResourceLimitsError(err)
+	}
+	c := &cgroup{path: path}
+	if err = c.limit(resources); err != nil {
+		c.destroy()
+		return nil, NewResourceLimitsError(err)
+	}
+	if c.dir, err = os.Open(path); err != nil {
+		c.destroy()
+		return nil, NewResourceLimitsError(err)
+	}
+	return c, nil
+}
+
+// delegatingCgroup returns the path of the cgroup of the compute node, after making sure it
+// delegates the CPU and memory controllers to its children.
+func delegatingCgroup() (string, error) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return "", fmt.Errorf("no cgroup v2 hierarchy at %s: %w", cgroupRoot, err)
+	}
+	self, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	var relative string
+	for _, line := range strings.Split(string(self), "\n") {
+		if path, found := strings.CutPrefix(line, "0::"); found {
+			relative = path
+			break
+		}
+	}
+	if relative == "" {
+		return "", errors.New("compute node is not in a cgroup v2")
+	}
+	path := filepath.Join(cgroupRoot, relative)
+
+	delegated, err := os.ReadFile(filepath.Join(path, "cgroup.subtree_control"))
+	if err != nil {
+		return "", err
+	}
+	for _, controller := range []string{"cpu", "memory"} {
+		if !strings.Contains(" "+strings.TrimSpace(string(delegated))+" ", " "+controller+" ") {
+			// this fails if the cgroup has processes and is not the root cgroup
+			if err = writeCgroupFile(path, "cgroup.subtree_control", "+"+controller); err != nil {
+				return "", fmt.Errorf("failed to delegate the %s controller: %w", controller, err)
+			}
+		}
+	}
+	return path, nil
+}
+
+// limit writes the CPU and memory limits of the cgroup
+func (c *cgroup) limit(resources *models.Resources) error {
+	if resources.Memory > 0 {
+		if err := writeCgroupFile(c.path, "memory.max", strconv.FormatUint(resources.Memory, 10)); err != nil {
+			return err
+		}
+		// don't let processes exceed the memory limit by swapping
+		if err := writeCgroupFile(c.path, "memory.swap.max", "0"); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if resources.CPU > 0 {
+		quota := int64(resources.CPU * cgroupCPUPeriod)
+		if err := writeCgroupFile(c.path, "cpu.max", fmt.Sprintf("%d %d", quota, cgroupCPUPeriod)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attach makes the process of cmd start in the cgroup
+func (c *cgroup) attach(cmd *exec.Cmd) {
+	if c == nil {
+		return
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(c.dir.Fd())
+}
+
+// oomKilled returns whether a process of the cgroup was killed for exceeding the memory limit
+func (c *cgroup) oomKilled() bool {
+	if c == nil {
+		return false
+	}
+	events, err := os.Open(filepath.Join(c.path, "memory.events"))
+	if err != nil {
+		return false
+	}
+	defer events.Close()
+	scanner := bufio.NewScanner(events)
+	for scanner.Scan() {
+		if count, found := strings.CutPrefix(scanner.Text(), "oom_kill "); found {
+			return count != "0"
+		}
+	}
+	return false
+}
+
+// usage returns the CPU time consumed by the processes of the cgroup, and their peak memory usage.
+// The peak memory usage is zero on kernels older than 5.19, which don't track it.
+func (c *cgroup) usage() (cpu time.Duration, peakMemory uint64, ok bool) {
+	if c == nil {
+		return 0, 0, false
+	}
+	stat, err := os.ReadFile(filepath.Join(c.path, "cpu.stat"))
+	if err != nil {
+		return 0, 0, false
+	}
+	for _, line := range strings.Split(string(stat), "\n") {
+		if value, found := strings.CutPrefix(line, "usage_usec "); found {
+			usec, parseErr := strconv.ParseInt(value, 10, 64)
+			if parseErr != nil {
+				return 0, 0, false
+			}
+			cpu = time.Duration(usec) * time.Microsecond
+			ok = true
+			break
+		}
+	}
+	if peak, err := os.ReadFile(filepath.Join(c.path, "memory.peak")); err == nil {
+		peakMemory, _ = strconv.ParseUint(strings.TrimSpace(string(peak)), 10, 64)
+	}
+	return cpu, peakMemory, ok
+}
+
+// destroy kills the remaining processes of the cgroup, and removes it
+func (c *cgroup) destroy() {
+	if c == nil {
+		return
+	}
+	if c.dir != nil {
+		_ = c.dir.Close()
+	}
+	// cgroup.kill is only available from Linux 5.14
+	_ = writeCgroupFile(c.path, "cgroup.kill", "1")
+	deadline := time.Now().Add(cgroupRemoveTimeout)
+	for {
+		err := os.Remove(c.path)
+		if err == nil || errors.Is(err, os.ErrNotExist) {
+			return
+		}
+		if time.Now().After(deadline) {
+			log.Warn().Err(err).Str("cgroup", c.path).Msg("failed to remove cgroup of exec execution")
+			return
+		}
+		time.Sleep(100 * time.Millisecond) //nolint:mnd
+	}
+}
+
+func writeCgroupFile(dir, name, value string) error {
+	return os.WriteFile(filepath.Join(dir, name), []byte(value), 0)
+}
@@ -0,0 +1,29 @@
+//go:build !linux
+
+package exec
+
+import (
+	"os/exec"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// cgroup is a no-op outside of Linux, where the resources of executions are not limited
+type cgroup struct{}
+
+func newCgroup(string, *models.Resources) (*cgroup, error) {
+	return nil, nil
+}
+
+func (c *cgroup) attach(*exec.Cmd) {}
+
+func (c *cgroup) oomKilled() bool {
+	return false
+}
+
+func (c *cgroup) usage() (time.Duration, uint64, bool) {
+	return 0, 0, false
+}
+
+func (c *cgroup) destroy() {}
@@ -0,0 +1,59 @@
+package exec
+
+import (
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// dynamicLoaderEnvPrefixes are the prefixes of the environment variables read by the dynamic loader, such as
+// LD_PRELOAD and LD_LIBRARY_PATH, which would let a job run its own shared objects inside an allow-listed binary
+var dynamicLoaderEnvPrefixes = []string{"LD_", "DYLD_"}
+
+// dynamicLoaderEnvNames are the other environment variables that make the C library load shared objects
+var dynamicLoaderEnvNames = []string{"GCONV_PATH"}
+
+// isDynamicLoaderEnv returns whether the environment variable is read by the dynamic loader
+func isDynamicLoaderEnv(name string) bool {
+	for _, prefix := range dynamicLoaderEnvPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	for _, loaderName := range dynamicLoaderEnvNames {
+		if name == loaderName {
+			return true
+		}
+	}
+	return false
+}
+
+// checkEnv returns an error if the job sets an environment variable that it is not allowed to set, which are
+// the variables of the dynamic loader, and the variables not matching the allow-list if it isn't empty.
+// The variables that bacalhau sets for the execution are always allowed.
+func checkEnv(allowList []string, env map[string]string) error {
+	for name := range env {
+		if isDynamicLoaderEnv(name) {
+			return NewEnvNotAllowedError(name)
+		}
+		if len(allowList) == 0 || strings.HasPrefix(name, models.EnvVarPrefix) {
+			continue
+		}
+		if !isEnvAllowListed(allowList, name) {
+			return NewEnvNotAllowListedError(name)
+		}
+	}
+	return nil
+}
+
+// isEnvAllowListed returns whether the environment variable matches one of the allow-listed names or glob patterns
+func isEnvAllowListed(allowList []string, name string) bool {
+	for _, pattern := range allowList {
+		if match, err := doublestar.Match(pattern, name); err == nil && match {
+			return true
+		}
+	}
+	return false
+}
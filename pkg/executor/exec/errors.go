@@ -0,0 +1,125 @@
+package exec
+
+import (
+	"net/http"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+)
+
+const Component = "Exec"
+
+// Exec-specific error codes
+const (
+	SpecError            = "SpecError"
+	BinaryNotFound       = "BinaryNotFound"
+	BinaryNotAllowListed = "BinaryNotAllowListed"
+	EnvNotAllowed        = "EnvNotAllowed"
+	SandboxPathError     = "SandboxPathError"
+	FilesystemError      = "FilesystemError"
+	ProcessStartError    = "ProcessStartError"
+	ResourceLimitsError  = "ResourceLimitsError"
+	InputConfigError     = "InputConfigError"
+	OutputConfigError    = "OutputConfigError"
+)
+
+// NewSpecError creates an error when there's an issue with the exec spec
+func NewSpecError(err error) bacerrors.Error {
+	return bacerrors.Wrap(err, "invalid exec spec").
+		WithCode(SpecError).
+		WithHTTPStatusCode(http.StatusBadRequest).
+		WithComponent(Component).
+		WithHint("Check that the exec spec is properly formatted and contains all required fields")
+}
+
+// NewBinaryNotFoundError creates an error when the command of a job is not installed on the compute node
+func NewBinaryNotFoundError(command string, err error) bacerrors.Error {
+	return bacerrors.Wrapf(err, "binary %q not found", command).
+		WithCode(BinaryNotFound).
+		WithHTTPStatusCode(http.StatusBadRequest).
+		WithComponent(Component).
+		WithHint("Use the absolute path of the binary, or check that it is installed in the PATH of the compute node")
+}
+
+// NewBinaryNotAllowListedError creates an error when the command of a job is not allow-listed by the compute node
+func NewBinaryNotAllowListedError(path string) bacerrors.Error {
+	return bacerrors.Newf("binary %q is not allow-listed", path).
+		WithCode(BinaryNotAllowListed).
+		WithHTTPStatusCode(http.StatusForbidden).
+		WithComponent(Component).
+		WithHint("Add the binary to %s in the configuration of the compute node", types.ComputeAllowListedBinariesKey)
+}
+
+// NewEnvNotAllowedError creates an error when a job sets an environment variable of the dynamic loader,
+// which would let it load its own code into the allow-listed binary
+func NewEnvNotAllowedError(name string) bacerrors.Error {
+	return bacerrors.Newf("environment variable %q is not allowed", name).
+		WithCode(EnvNotAllowed).
+		WithHTTPStatusCode(http.StatusForbidden).
+		WithComponent(Component).
+		WithHint("Variables of the dynamic loader, such as LD_PRELOAD and LD_LIBRARY_PATH, cannot be set by exec jobs")
+}
+
+// NewEnvNotAllowListedError creates an error when a job sets an environment variable that is not allow-listed
+// by the compute node
+func NewEnvNotAllowListedError(name string) bacerrors.Error {
+	return bacerrors.Newf("environment variable %q is not allow-listed", name).
+		WithCode(EnvNotAllowed).
+		WithHTTPStatusCode(http.StatusForbidden).
+		WithComponent(Component).
+		WithHint("Add the variable to %s in the configuration of the compute node", types.ComputeAllowListedExecEnvKey)
+}
+
+// NewSandboxPathError creates an error when a path of a job escapes the sandbox directory of its execution
+func NewSandboxPathError(path string) bacerrors.Error {
+	return bacerrors.Newf("path %q is outside of the sandbox directory of the execution", path).
+		WithCode(SandboxPathError).
+		WithHTTPStatusCode(http.StatusBadRequest).
+		WithComponent(Component).
+		WithHint("Paths of inputs, outputs and the working directory must not contain '..' elements that escape the sandbox")
+}
+
+// NewFilesystemError creates an error when there's an issue with the filesystem
+func NewFilesystemError(path string, err error) bacerrors.Error {
+	return bacerrors.Wrapf(err, "filesystem error at %q", path).
+		WithCode(FilesystemError).
+		WithHTTPStatusCode(http.StatusInternalServerError).
+		WithComponent(Component).
+		WithHint("This is an internal error with the filesystem. Please report this issue")
+}
+
+// NewProcessStartError creates an error when the process of an execution fails to start
+func NewProcessStartError(path string, err error) bacerrors.Error {
+	return bacerrors.Wrapf(err, "failed to start %q", path).
+		WithCode(ProcessStartError).
+		WithHTTPStatusCode(http.StatusInternalServerError).
+		WithComponent(Component).
+		WithHint("Check that the binary is executable by the user running the compute node")
+}
+
+// NewResourceLimitsError creates an error when the resource limits of an execution can't be applied
+func NewResourceLimitsError(err error) bacerrors.Error {
+	return bacerrors.Wrap(err, "failed to apply resource limits").
+		WithCode(ResourceLimitsError).
+		WithHTTPStatusCode(http.StatusInternalServerError).
+		WithComponent(Component).
+		WithHint("Check that the compute node can create cgroups below its own cgroup")
+}
+
+// NewInputConfigError creates an error when there's an issue with input configuration
+func NewInputConfigError(msg string) bacerrors.Error {
+	return bacerrors.Newf("input configuration error: %s", msg).
+		WithCode(InputConfigError).
+		WithHTTPStatusCode(http.StatusBadRequest).
+		WithComponent(Component).
+		WithHint("Check that all input sources have a source and a target path, and that targets don't conflict")
+}
+
+// NewOutputError creates an error when there's an issue with output configuration
+func NewOutputError(msg string) bacerrors.Error {
+	return bacerrors.Newf("output configuration error: %s", msg).
+		WithCode(OutputConfigError).
+		WithHTTPStatusCode(http.StatusBadRequest).
+		WithComponent(Component).
+		WithHint("Check that all outputs have a name and a path, and that paths don't conflict with inputs")
+}
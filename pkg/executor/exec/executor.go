@@ -0,0 +1,256 @@
+// Package exec provides an executor running allow-listed binaries of the compute node directly
+// on the host, for nodes that can't run Docker.
+package exec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+	"go.uber.org/atomic"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	execmodels "github.com/bacalhau-project/bacalhau/pkg/executor/exec/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models/messages"
+	"github.com/bacalhau-project/bacalhau/pkg/util/generic"
+)
+
+const (
+	// execDirName is the directory of an execution holding the files of the exec executor
+	execDirName = "exec"
+	// sandboxDirName is the directory the process of an execution runs in, in which inputs and outputs are linked
+	sandboxDirName = "sandbox"
+	// cgroupPrefix prefixes the name of the cgroups of executions
+	cgroupPrefix = "bacalhau-exec-"
+)
+
+// ExecutorParams configures the exec executor.
+type ExecutorParams struct {
+	// AllowListedBinaries are the paths, or glob patterns of paths, of the host binaries that jobs can run.
+	AllowListedBinaries []string
+	// AllowListedEnv are the names, or glob patterns of names, of the environment variables that jobs can set.
+	// Jobs can set any variable if empty, except the variables of the dynamic loader.
+	AllowListedEnv []string
+}
+
+// Executor runs allow-listed host binaries as processes of the compute node.
+// Each execution runs in a sandbox directory in which its inputs and outputs are linked,
+// with its CPU and memory limited by a cgroup where cgroups v2 are available.
+type Executor struct {
+	// handlers is a map of executionID to its handler.
+	handlers generic.SyncMap[string, *executionHandler]
+	// allowList holds the paths, or glob patterns of paths, of the binaries that jobs can run.
+	allowList []string
+	// envAllowList holds the names, or glob patterns of names, of the environment variables that jobs can set.
+	envAllowList []string
+}
+
+// NewExecutor creates a new exec executor instance.
+func NewExecutor(params ExecutorParams) (*Executor, error) {
+	return &Executor{
+		allowList:    params.AllowListedBinaries,
+		envAllowList: params.AllowListedEnv,
+	}, nil
+}
+
+// IsInstalled checks if the exec executor is available.
+// Processes can always be started, so it's always available.
+func (e *Executor) IsInstalled(context.Context) (bool, error) {
+	return true, nil
+}
+
+// ShouldBid determines if the executor should bid on a job.
+// It only bids on jobs running a binary that is installed and allow-listed,
+// and only setting environment variables they are allowed to set.
+func (e *Executor) ShouldBid(ctx context.Context, request bidstrategy.BidStrategyRequest) (bidstrategy.BidStrategyResponse, error) {
+	spec, err := execmodels.DecodeSpec(request.Job.Task().Engine)
+	if err != nil {
+		return bidstrategy.BidStrategyResponse{}, NewSpecError(err)
+	}
+	path, err := resolveBinary(spec.Command)
+	if err != nil {
+		return bidstrategy.NewBidResponse(false, "have the binary %q installed", spec.Command), nil
+	}
+	if !isAllowListed(e.allowList, path) {
+		return bidstrategy.NewBidResponse(false, "allow running the binary %q", path), nil
+	}
+	env := make(map[string]string, len(request.Job.Task().Env))
+	for name := range request.Job.Task().Env {
+		env[name] = ""
+	}
+	if err = checkEnv(e.envAllowList, env); err != nil {
+		return bidstrategy.NewBidResponse(false, "allow the environment of the job: %s", err), nil
+	}
+	return bidstrategy.NewBidResponse(true, "allow running the binary %q", path), nil
+}
+
+// ShouldBidBasedOnUsage determines if the executor should bid on a job based on resource usage.
+// Exec jobs don't have additional requirements, so it always returns true.
+func (e *Executor) ShouldBidBasedOnUsage(
+	ctx context.Context,
+	request bidstrategy.BidStrategyRequest,
+	usage models.Resources,
+) (bidstrategy.BidStrategyResponse, error) {
+	return bidstrategy.NewBidResponse(true, "not place additional requirements on exec jobs"), nil
+}
+
+// Start initiates an execution based on the provided RunCommandRequest.
+// It checks the binary and environment are allowed, prepares the sandbox directory and resource limits of the
+// execution, then runs its process in a separate goroutine.
+func (e *Executor) Start(ctx context.Context, request *executor.RunCommandRequest) error {
+	if handler, found := e.handlers.Get(request.ExecutionID); found {
+		if handler.active() {
+			return executor.NewExecutorError(executor.ExecutionAlreadyStarted, fmt.Sprintf("starting execution (%s)", request.ExecutionID))
+		} else {
+			return executor.NewExecutorError(executor.ExecutionAlreadyComplete, fmt.Sprintf("starting execution (%s)", request.ExecutionID))
+		}
+	}
+
+	spec, err := execmodels.DecodeSpec(request.EngineParams)
+	if err != nil {
+		return NewSpecError(err)
+	}
+	binaryPath, err := resolveBinary(spec.Command)
+	if err != nil {
+		return err
+	}
+	// the allow-list is checked again as the configuration of the node may have changed since it bid
+	if !isAllowListed(e.allowList, binaryPath) {
+		return NewBinaryNotAllowListedError(binaryPath)
+	}
+	if err = checkEnv(e.envAllowList, request.Env); err != nil {
+		return err
+	}
+
+	execDir := filepath.Join(request.ExecutionDir, execDirName)
+	sandboxDir := filepath.Join(execDir, sandboxDirName)
+	err = prepareSandbox(ctx, sandboxDir, compute.ExecutionResultsDir(request.ExecutionDir), request.Inputs, request.Outputs)
+	if err != nil {
+		return err
+	}
+	workingDir, err := sandboxPath(sandboxDir, spec.WorkingDirectory)
+	if err != nil {
+		return err
+	}
+	if info, statErr := os.Stat(workingDir); statErr != nil || !info.IsDir() {
+		if err = mkdirAll(sandboxDir, workingDir, spec.WorkingDirectory); err != nil {
+			return err
+		}
+	}
+
+	cg, err := newCgroup(cgroupPrefix+request.ExecutionID, request.Resources)
+	if err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	handler := &executionHandler{
+		request:    request,
+		spec:       spec,
+		binaryPath: binaryPath,
+		workingDir: workingDir,
+		spoolDir:   execDir,
+		cgroup:     cg,
+		logger: log.With().
+			Str("execution", request.ExecutionID).
+			Str("job", request.JobID).
+			Str("binary", binaryPath).
+			Logger(),
+		ctx:      runCtx,
+		cancel:   cancel,
+		activeCh: make(chan bool),
+		waitCh:   make(chan bool),
+		running:  atomic.NewBool(false),
+	}
+
+	// register the handler for this executionID
+	e.handlers.Put(request.ExecutionID, handler)
+	go handler.run()
+	return nil
+}
+
+// Wait initiates a wait for the completion of a specific execution using its executionID.
+// The function returns two channels: one for the result and another for any potential error.
+// If the executionID is not found, an error is immediately sent to the error channel.
+func (e *Executor) Wait(ctx context.Context, executionID string) (<-chan *models.RunCommandResult, <-chan error) {
+	handler, found := e.handlers.Get(executionID)
+	outCh := make(chan *models.RunCommandResult, 1)
+	errCh := make(chan error, 1)
+
+	if !found {
+		errCh <- executor.NewExecutorError(executor.ExecutionNotFound, fmt.Sprintf("waiting on execution (%s)", executionID))
+		return outCh, errCh
+	}
+
+	go e.doWait(ctx, outCh, errCh, handler)
+	return outCh, errCh
+}
+
+// doWait waits for the process of an execution to finish, and relays its result to the output channel,
+// or an error to the error channel if the context is done before.
+func (e *Executor) doWait(ctx context.Context, out chan *models.RunCommandResult, errCh chan error, handler *executionHandler) {
+	log.Info().Str("executionID", handler.request.ExecutionID).Msg("waiting on execution")
+
+	defer close(out)
+	defer close(errCh)
+
+	select {
+	case <-ctx.Done():
+		errCh <- ctx.Err()
+	case <-handler.waitCh:
+		log.Info().Str("executionID", handler.request.ExecutionID).Msg("received results from execution")
+		if handler.result != nil {
+			out <- handler.result
+		} else {
+			errCh <- fmt.Errorf("execution result is nil")
+		}
+	}
+}
+
+// Cancel kills the process of a specific execution by its executionID.
+// It returns an error if the execution is not found.
+func (e *Executor) Cancel(ctx context.Context, executionID string) error {
+	handler, found := e.handlers.Get(executionID)
+	if !found {
+		return executor.NewExecutorError(executor.ExecutionNotFound, fmt.Sprintf("canceling execution (%s)", executionID))
+	}
+	return handler.kill(ctx)
+}
+
+// GetLogStream provides a stream of output logs for a specific execution.
+// It returns an error if the execution is not found.
+func (e *Executor) GetLogStream(ctx context.Context, request messages.ExecutionLogsRequest) (io.ReadCloser, error) {
+	handler, found := e.handlers.Get(request.ExecutionID)
+	if !found {
+		return nil, executor.NewExecutorError(executor.ExecutionNotFound,
+			fmt.Sprintf("getting outputs for execution (%s)", request.ExecutionID))
+	}
+	return handler.outputStream(ctx, request)
+}
+
+// Run initiates and waits for the completion of an execution in one call.
+// It returns the result of the execution or an error if either starting
+// or waiting fails, or if the context is canceled.
+func (e *Executor) Run(ctx context.Context, request *executor.RunCommandRequest) (*models.RunCommandResult, error) {
+	if err := e.Start(ctx, request); err != nil {
+		return nil, err
+	}
+	resCh, errCh := e.Wait(ctx, request.ExecutionID)
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case out := <-resCh:
+		return out, nil
+	case err := <-errCh:
+		return nil, err
+	}
+}
+
+// Compile-time check that Executor implements the Executor interface.
+var _ executor.Executor = (*Executor)(nil)
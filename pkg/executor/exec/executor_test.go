@@ -0,0 +1,261 @@
+//go:build unit || !integration
+
+package exec
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	execmodels "github.com/bacalhau-project/bacalhau/pkg/executor/exec/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models/messages"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type ExecutorTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	executor *Executor
+	shell    string
+}
+
+func TestExecutorTestSuite(t *testing.T) {
+	suite.Run(t, new(ExecutorTestSuite))
+}
+
+func (s *ExecutorTestSuite) SetupTest() {
+	var err error
+	s.shell, err = osexec.LookPath("sh")
+	if err != nil {
+		s.T().Skip("no shell to run")
+	}
+	s.shell, err = filepath.Abs(s.shell)
+	s.Require().NoError(err)
+
+	s.ctx = context.Background()
+	s.executor, err = NewExecutor(ExecutorParams{AllowListedBinaries: []string{s.shell}})
+	s.Require().NoError(err)
+}
+
+// request returns a request running a shell script in a new execution directory
+func (s *ExecutorTestSuite) request(script string) *executor.RunCommandRequest {
+	executionDir := s.T().TempDir()
+	s.Require().NoError(os.Mkdir(compute.ExecutionLogsDir(executionDir), 0o755))
+	s.Require().NoError(os.Mkdir(compute.ExecutionResultsDir(executionDir), 0o755))
+	return &executor.RunCommandRequest{
+		JobID:        "job",
+		ExecutionID:  filepath.Base(executionDir),
+		Resources:    &models.Resources{},
+		ExecutionDir: executionDir,
+		EngineParams: execmodels.NewExecEngineBuilder("sh").WithArguments("-c", script).MustBuild(),
+		Env:          map[string]string{},
+		OutputLimits: executor.OutputLimits{
+			MaxStdoutFileLength:   1024,
+			MaxStdoutReturnLength: 1024,
+			MaxStderrFileLength:   1024,
+			MaxStderrReturnLength: 1024,
+		},
+	}
+}
+
+func (s *ExecutorTestSuite) TestShouldBid() {
+	testCases := []struct {
+		name      string
+		allowList []string
+		command   string
+		shouldBid bool
+	}{
+		{name: "allow-listed by name", allowList: []string{s.shell}, command: "sh", shouldBid: true},
+		{name: "allow-listed by path", allowList: []string{s.shell}, command: s.shell, shouldBid: true},
+		{name: "allow-listed by pattern", allowList: []string{filepath.Dir(s.shell) + "/*"}, command: "sh", shouldBid: true},
+		{name: "not allow-listed", allowList: []string{"/opt/tools/*"}, command: "sh", shouldBid: false},
+		{name: "empty allow-list", command: "sh", shouldBid: false},
+		{name: "not installed", allowList: []string{"**"}, command: "not-a-bacalhau-binary", shouldBid: false},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			e, err := NewExecutor(ExecutorParams{AllowListedBinaries: tc.allowList})
+			s.Require().NoError(err)
+			job := mock.Job()
+			job.Task().Engine = execmodels.NewExecEngineBuilder(tc.command).MustBuild()
+			response, err := e.ShouldBid(s.ctx, bidstrategy.BidStrategyRequest{Job: *job})
+			s.Require().NoError(err)
+			s.Equal(tc.shouldBid, response.ShouldBid, response.Reason)
+		})
+	}
+}
+
+func (s *ExecutorTestSuite) TestShouldBidOnEnvironment() {
+	testCases := []struct {
+		name         string
+		envAllowList []string
+		env          string
+		shouldBid    bool
+	}{
+		{name: "any variable", env: "DEBUG", shouldBid: true},
+		{name: "allow-listed", envAllowList: []string{"PYTHON*"}, env: "PYTHONPATH", shouldBid: true},
+		{name: "not allow-listed", envAllowList: []string{"PYTHON*"}, env: "DEBUG", shouldBid: false},
+		{name: "dynamic loader", env: "LD_PRELOAD", shouldBid: false},
+		{name: "allow-listed dynamic loader", envAllowList: []string{"*"}, env: "LD_LIBRARY_PATH", shouldBid: false},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			e, err := NewExecutor(ExecutorParams{AllowListedBinaries: []string{s.shell}, AllowListedEnv: tc.envAllowList})
+			s.Require().NoError(err)
+			job := mock.Job()
+			job.Task().Engine = execmodels.NewExecEngineBuilder("sh").MustBuild()
+			job.Task().Env = map[string]models.EnvVarValue{tc.env: "value"}
+			response, err := e.ShouldBid(s.ctx, bidstrategy.BidStrategyRequest{Job: *job})
+			s.Require().NoError(err)
+			s.Equal(tc.shouldBid, response.ShouldBid, response.Reason)
+		})
+	}
+}
+
+func (s *ExecutorTestSuite) TestRun() {
+	input := filepath.Join(s.T().TempDir(), "input.txt")
+	s.Require().NoError(os.WriteFile(input, []byte("input data"), 0o644))
+
+	request := s.request(`echo "hello $NAME"; echo oops >&2; cp inputs/data.txt outputs/copy.txt; exit 3`)
+	request.Env["NAME"] = "bacalhau"
+	request.Inputs = []storage.PreparedStorage{{
+		Volume: storage.StorageVolume{Source: input, Target: "/inputs/data.txt", Type: storage.StorageVolumeConnectorBind},
+	}}
+	request.Outputs = []*models.ResultPath{{Name: "outputs", Path: "/outputs"}}
+
+	result, err := s.executor.Run(s.ctx, request)
+	s.Require().NoError(err)
+	s.Empty(result.ErrorMsg)
+	s.Equal(3, result.ExitCode)
+	s.Equal("hello bacalhau\n", result.STDOUT)
+	s.Equal("oops\n", result.STDERR)
+
+	resultsDir := compute.ExecutionResultsDir(request.ExecutionDir)
+	output, err := os.ReadFile(filepath.Join(resultsDir, "outputs", "copy.txt"))
+	s.Require().NoError(err)
+	s.Equal("input data", string(output))
+	stdout, err := os.ReadFile(filepath.Join(resultsDir, models.DownloadFilenameStdout))
+	s.Require().NoError(err)
+	s.Equal("hello bacalhau\n", string(stdout))
+}
+
+func (s *ExecutorTestSuite) TestResourceUsage() {
+	result, err := s.executor.Run(s.ctx, s.request(`i=0; while [ $i -lt 20000 ]; do i=$((i+1)); done`))
+	s.Require().NoError(err)
+	s.Require().NotNil(result.ResourceUsage)
+	s.Positive(result.ResourceUsage.WallSeconds)
+	s.Positive(result.ResourceUsage.CPUSeconds)
+	s.LessOrEqual(result.ResourceUsage.CPUSeconds, result.ResourceUsage.WallSeconds*float64(runtime.NumCPU()))
+}
+
+func (s *ExecutorTestSuite) TestEnvironmentIsNotInherited() {
+	s.T().Setenv("BACALHAU_EXEC_TEST_SECRET", "secret")
+	result, err := s.executor.Run(s.ctx, s.request(`echo "[$BACALHAU_EXEC_TEST_SECRET]"; test -n "$PATH"`))
+	s.Require().NoError(err)
+	s.Equal("[]\n", result.STDOUT)
+	s.Equal(0, result.ExitCode)
+}
+
+func (s *ExecutorTestSuite) TestWorkingDirectory() {
+	request := s.request("pwd")
+	request.EngineParams = execmodels.NewExecEngineBuilder("sh").
+		WithArguments("-c", "pwd").
+		WithWorkingDirectory("/work/dir").
+		MustBuild()
+	result, err := s.executor.Run(s.ctx, request)
+	s.Require().NoError(err)
+	s.Equal(filepath.Join(request.ExecutionDir, execDirName, sandboxDirName, "work", "dir")+"\n", result.STDOUT)
+
+	request.ExecutionID += "-escape"
+	request.EngineParams = execmodels.NewExecEngineBuilder("sh").WithWorkingDirectory("../..").MustBuild()
+	err = s.executor.Start(s.ctx, request)
+	s.True(bacerrors.IsErrorWithCode(err, SandboxPathError), err)
+}
+
+func (s *ExecutorTestSuite) TestStartFailsIfEnvNotAllowed() {
+	request := s.request("true")
+	request.Env["LD_PRELOAD"] = "./evil.so"
+	err := s.executor.Start(s.ctx, request)
+	s.True(bacerrors.IsErrorWithCode(err, EnvNotAllowed), err)
+
+	e, err := NewExecutor(ExecutorParams{AllowListedBinaries: []string{s.shell}, AllowListedEnv: []string{"PYTHON*"}})
+	s.Require().NoError(err)
+	request = s.request("true")
+	request.Env["DEBUG"] = "1"
+	err = e.Start(s.ctx, request)
+	s.True(bacerrors.IsErrorWithCode(err, EnvNotAllowed), err)
+
+	// the variables set by bacalhau are always allowed
+	request = s.request("true")
+	request.Env[models.EnvVarPrefix+"JOB_ID"] = "job"
+	_, err = e.Run(s.ctx, request)
+	s.Require().NoError(err)
+}
+
+func (s *ExecutorTestSuite) TestStartFailsIfNotAllowListed() {
+	e, err := NewExecutor(ExecutorParams{})
+	s.Require().NoError(err)
+	err = e.Start(s.ctx, s.request("true"))
+	s.True(bacerrors.IsErrorWithCode(err, BinaryNotAllowListed), err)
+}
+
+func (s *ExecutorTestSuite) TestStartTwice() {
+	request := s.request("true")
+	_, err := s.executor.Run(s.ctx, request)
+	s.Require().NoError(err)
+	err = s.executor.Start(s.ctx, request)
+	s.True(bacerrors.IsErrorWithCode(err, executor.ExecutionAlreadyComplete), err)
+}
+
+func (s *ExecutorTestSuite) TestCancel() {
+	request := s.request("sleep 30 & wait")
+	s.Require().NoError(s.executor.Start(s.ctx, request))
+	handler, found := s.executor.handlers.Get(request.ExecutionID)
+	s.Require().True(found)
+	<-handler.activeCh
+	s.Require().NoError(s.executor.Cancel(s.ctx, request.ExecutionID))
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+	resCh, errCh := s.executor.Wait(ctx, request.ExecutionID)
+	select {
+	case result := <-resCh:
+		s.Contains(result.ErrorMsg, "execution cancelled")
+	case err := <-errCh:
+		s.Fail("unexpected error waiting for cancelled execution", err)
+	}
+}
+
+func (s *ExecutorTestSuite) TestLogStream() {
+	request := s.request("echo out; echo err >&2")
+	_, err := s.executor.Run(s.ctx, request)
+	s.Require().NoError(err)
+
+	reader, err := s.executor.GetLogStream(s.ctx, messages.ExecutionLogsRequest{
+		ExecutionID: request.ExecutionID,
+		Follow:      true,
+	})
+	s.Require().NoError(err)
+	defer reader.Close()
+
+	var stdout, stderr bytes.Buffer
+	_, err = stdcopy.StdCopy(&stdout, &stderr, reader)
+	s.Require().True(err == nil || err == io.EOF, err)
+	s.Equal("out\n", stdout.String())
+	s.Equal("err\n", stderr.String())
+}
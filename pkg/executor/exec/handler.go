@@ -0,0 +1,232 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
+	"golang.org/x/exp/maps"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/logstream"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	execmodels "github.com/bacalhau-project/bacalhau/pkg/executor/exec/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models/messages"
+	"github.com/bacalhau-project/bacalhau/pkg/util/closer"
+)
+
+// processWaitDelay is how long to wait for the output of a process to be closed after it exits,
+// which may be held open by processes it started in the background
+const processWaitDelay = 5 * time.Second
+
+// executionHandler manages the lifecycle of the process of a single exec execution
+type executionHandler struct {
+	// request contains all the information needed for execution
+	request *executor.RunCommandRequest
+	// spec contains the exec engine specification
+	spec execmodels.EngineSpec
+	// binaryPath is the absolute path of the allow-listed binary to run
+	binaryPath string
+	// workingDir is the directory on the host the process runs in, inside the sandbox directory
+	workingDir string
+	// spoolDir holds the complete stdout and stderr of the process, from which results are written
+	spoolDir string
+	// cgroup limits the resources of the process, and is nil if they are not limited
+	cgroup *cgroup
+
+	logger zerolog.Logger
+
+	// cancellation
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// synchronization channels
+	activeCh chan bool    // blocks until the process starts
+	waitCh   chan bool    // blocks until the run method returns
+	running  *atomic.Bool // true until the run method returns
+
+	// results
+	result *models.RunCommandResult
+}
+
+// run starts the process and waits for it to exit, capturing its output as logs and results
+func (h *executionHandler) run() {
+	ActiveExecutions.Inc(h.ctx)
+	h.running.Store(true)
+	defer func() {
+		h.cgroup.destroy()
+		h.running.Store(false)
+		close(h.waitCh)
+		h.cancel()
+		ActiveExecutions.Dec(h.ctx)
+	}()
+
+	// the log writer is always closed with an end frame, so that readers of logs stop waiting
+	logWriter, err := logstream.NewExecutionLogWriter(compute.ExecutionLogsDir(h.request.ExecutionDir))
+	if err != nil {
+		h.result = executor.NewFailedResult(fmt.Sprintf("failed to create execution logs writer: %s", err))
+		return
+	}
+	defer closer.CloseWithLogOnError("execution_logs_writer", logWriter)
+	frames := logstream.NewTimestampedStdWriter(logWriter)
+	defer closer.CloseWithLogOnError("execution_logs_frames", frames)
+
+	stdoutPath, stderrPath := filepath.Join(h.spoolDir, "stdout"), filepath.Join(h.spoolDir, "stderr")
+	stdout, err := os.Create(stdoutPath)
+	if err != nil {
+		h.result = executor.NewFailedResult(NewFilesystemError(stdoutPath, err).Error())
+		return
+	}
+	defer closer.CloseWithLogOnError("stdout", stdout)
+	stderr, err := os.Create(stderrPath)
+	if err != nil {
+		h.result = executor.NewFailedResult(NewFilesystemError(stderrPath, err).Error())
+		return
+	}
+	defer closer.CloseWithLogOnError("stderr", stderr)
+
+	cmd := exec.CommandContext(h.ctx, h.binaryPath, h.spec.Arguments...)
+	cmd.Dir = h.workingDir
+	cmd.Env = h.environment()
+	cmd.Stdout = io.MultiWriter(stdout, frames.Stdout())
+	cmd.Stderr = io.MultiWriter(stderr, frames.Stderr())
+	cmd.WaitDelay = processWaitDelay
+	configureProcess(cmd)
+	h.cgroup.attach(cmd)
+
+	h.logger.Info().Msg("starting process execution")
+	// measure the wall time from before the process starts, as its CPU time includes starting it
+	started := time.Now()
+	if err = cmd.Start(); err != nil {
+		if h.ctx.Err() != nil {
+			h.result = executor.NewFailedResult(fmt.Sprintf("execution cancelled: %s", context.Cause(h.ctx)))
+			return
+		}
+		startErr := NewProcessStartError(h.binaryPath, err)
+		h.logger.Warn().Err(startErr).Msg("failed to start process")
+		h.result = executor.NewFailedResult(startErr.Error())
+		return
+	}
+	close(h.activeCh)
+
+	processErr := h.processError(cmd.Wait())
+	usage := h.usage(cmd.ProcessState, time.Since(started))
+	defer func() {
+		if h.result != nil {
+			h.result.ResourceUsage = usage
+		}
+	}()
+	if h.ctx.Err() != nil {
+		h.logger.Info().Msg("process execution cancelled")
+		h.result = executor.NewFailedResult(fmt.Sprintf("execution cancelled: %s", context.Cause(h.ctx)))
+		return
+	}
+
+	exitCode := cmd.ProcessState.ExitCode()
+	if processErr != nil {
+		// the process didn't exit by itself, so we assume it failed
+		exitCode = 1
+		h.logger.Warn().Err(processErr).Int("exit_code", exitCode).Msg("process execution ended")
+	} else {
+		h.logger.Info().Int("exit_code", exitCode).Msg("process execution ended")
+	}
+
+	// persist the complete stdout and stderr to the results directory
+	stdoutReader, err := os.Open(stdoutPath)
+	if err != nil {
+		h.result = executor.NewFailedResult(NewFilesystemError(stdoutPath, err).Error())
+		return
+	}
+	defer closer.CloseWithLogOnError("stdout_reader", stdoutReader)
+	stderrReader, err := os.Open(stderrPath)
+	if err != nil {
+		h.result = executor.NewFailedResult(NewFilesystemError(stderrPath, err).Error())
+		return
+	}
+	defer closer.CloseWithLogOnError("stderr_reader", stderrReader)
+
+	resultsDir := compute.ExecutionResultsDir(h.request.ExecutionDir)
+	h.result = executor.WriteJobResults(resultsDir, stdoutReader, stderrReader, exitCode, processErr, h.request.OutputLimits)
+}
+
+// processError returns the error of a process that didn't exit by itself, or nil if it did,
+// whatever its exit code
+func (h *executionHandler) processError(waitErr error) error {
+	if waitErr == nil {
+		return nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(waitErr, &exitErr) {
+		return waitErr
+	}
+	if exitErr.Exited() {
+		return nil
+	}
+	if h.cgroup.oomKilled() {
+		return errors.New("memory limit exceeded")
+	}
+	return fmt.Errorf("process terminated: %s", exitErr.ProcessState)
+}
+
+// usage returns the resources consumed by the process. They are read from the cgroup of the execution
+// if it has one, as it also accounts for the processes started in the background, or from the
+// process itself otherwise.
+func (h *executionHandler) usage(state *os.ProcessState, wall time.Duration) *models.ResourceUsage {
+	usage := &models.ResourceUsage{WallSeconds: wall.Seconds()}
+	if cpu, peakMemory, ok := h.cgroup.usage(); ok {
+		usage.CPUSeconds, usage.PeakMemory = cpu.Seconds(), peakMemory
+	} else if state != nil {
+		usage.CPUSeconds = (state.UserTime() + state.SystemTime()).Seconds()
+		usage.PeakMemory = peakRSS(state)
+	}
+	return usage
+}
+
+// environment returns the environment variables of the process in a consistent order.
+// The process doesn't inherit the environment of the compute node, except for its PATH
+// if the job doesn't set one, so that the binary can find the programs it runs.
+func (h *executionHandler) environment() []string {
+	keys := maps.Keys(h.request.Env)
+	sort.Strings(keys)
+	env := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		env = append(env, key+"="+h.request.Env[key])
+	}
+	if _, found := h.request.Env["PATH"]; !found {
+		env = append(env, "PATH="+os.Getenv("PATH"))
+	}
+	return env
+}
+
+// active returns whether the execution is currently running
+func (h *executionHandler) active() bool {
+	return h.running.Load()
+}
+
+// kill cancels the execution, which kills the process group of the process
+func (h *executionHandler) kill(ctx context.Context) error {
+	h.logger.Info().Msg("killing the process")
+	h.cancel()
+	return nil
+}
+
+// outputStream provides a stream of execution logs
+func (h *executionHandler) outputStream(ctx context.Context, request messages.ExecutionLogsRequest) (io.ReadCloser, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	// wait until the process starts, or fails to start, so that the logs file is created
+	case <-h.activeCh:
+	case <-h.waitCh:
+	}
+	return logstream.NewReaderForRequest(compute.ExecutionLogsDir(h.request.ExecutionDir), request)
+}
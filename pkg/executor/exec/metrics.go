@@ -0,0 +1,20 @@
+package exec
+
+import (
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel"
+
+	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
+)
+
+var (
+	execExecutorMeter = otel.GetMeterProvider().Meter("exec-executor")
+)
+
+var (
+	ActiveExecutions = lo.Must(telemetry.NewGauge(
+		execExecutorMeter,
+		"exec_active_executions",
+		"Number of active exec executions",
+	))
+)
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fatih/structs"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// EngineSpec contains necessary parameters to execute an exec job, which runs a binary of the
+// compute node directly on the host.
+type EngineSpec struct {
+	// Command is the binary to run, either as an absolute path or as a name looked up in the PATH
+	// of the compute node. It must be allow-listed by the compute node.
+	Command string `json:"Command"`
+	// Arguments holds the commandline arguments passed to the command
+	Arguments []string `json:"Arguments,omitempty"`
+	// WorkingDirectory is the directory the command runs in, relative to the sandbox directory of the
+	// execution in which inputs and outputs are linked at their target paths. Defaults to the sandbox directory.
+	WorkingDirectory string `json:"WorkingDirectory,omitempty"`
+}
+
+func (c EngineSpec) Validate() error {
+	if c.Command == "" {
+		return errors.New("invalid exec engine param: 'Command' cannot be empty")
+	}
+	return nil
+}
+
+func (c EngineSpec) ToMap() map[string]interface{} {
+	return structs.Map(c)
+}
+
+func DecodeSpec(spec *models.SpecConfig) (EngineSpec, error) {
+	if !spec.IsType(models.EngineExec) {
+		return EngineSpec{}, errors.New("invalid exec engine type. expected " + models.EngineExec + ", but received: " + spec.Type)
+	}
+	inputParams := spec.Params
+	if inputParams == nil {
+		return EngineSpec{}, errors.New("invalid exec engine params. cannot be nil")
+	}
+
+	paramsBytes, err := json.Marshal(inputParams)
+	if err != nil {
+		return EngineSpec{}, fmt.Errorf("failed to encode exec engine specs. %w", err)
+	}
+
+	var c *EngineSpec
+	err = json.Unmarshal(paramsBytes, &c)
+	if err != nil {
+		return EngineSpec{}, fmt.Errorf("failed to decode exec engine specs. %w", err)
+	}
+	return *c, c.Validate()
+}
+
+type ExecEngineBuilder struct {
+	spec *EngineSpec
+}
+
+func NewExecEngineBuilder(command string) *ExecEngineBuilder {
+	return &ExecEngineBuilder{spec: &EngineSpec{Command: command}}
+}
+
+func (b *ExecEngineBuilder) WithArguments(e ...string) *ExecEngineBuilder {
+	b.spec.Arguments = e
+	return b
+}
+
+func (b *ExecEngineBuilder) WithWorkingDirectory(e string) *ExecEngineBuilder {
+	b.spec.WorkingDirectory = e
+	return b
+}
+
+func (b *ExecEngineBuilder) Build() (*models.SpecConfig, error) {
+	if err := b.spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &models.SpecConfig{
+		Type:   models.EngineExec,
+		Params: b.spec.ToMap(),
+	}, nil
+}
+
+func (b *ExecEngineBuilder) MustBuild() *models.SpecConfig {
+	spec, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
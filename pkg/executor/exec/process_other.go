@@ -0,0 +1,16 @@
+//go:build !unix
+
+package exec
+
+import (
+	"os"
+	"os/exec"
+)
+
+// configureProcess keeps the default behaviour of killing the process of cmd when cancelled
+func configureProcess(*exec.Cmd) {}
+
+// peakRSS is not available outside of unix systems
+func peakRSS(*os.ProcessState) uint64 {
+	return 0
+}
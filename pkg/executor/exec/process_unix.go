@@ -0,0 +1,35 @@
+//go:build unix
+
+package exec
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+)
+
+// configureProcess runs the process of cmd in its own process group, so that cancelling
+// the execution kills the processes it started along with it.
+func configureProcess(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// peakRSS returns the highest resident set size of the process that ran, in bytes
+func peakRSS(state *os.ProcessState) uint64 {
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok || rusage.Maxrss <= 0 {
+		return 0
+	}
+	// the resident set size is reported in bytes on macOS, and in kilobytes elsewhere
+	if runtime.GOOS == "darwin" {
+		return uint64(rusage.Maxrss)
+	}
+	return uint64(rusage.Maxrss) * 1024 //nolint:mnd
+}
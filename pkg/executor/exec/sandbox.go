@@ -0,0 +1,123 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/util"
+)
+
+// sandboxDirPerm is the permission of the directories created in the sandbox
+const sandboxDirPerm = util.OS_ALL_R | util.OS_ALL_X | util.OS_USER_W
+
+// sandboxPath returns the path on the host of a path of the job, which is resolved relative to the
+// sandbox directory. It fails if the path escapes the sandbox directory.
+func sandboxPath(sandboxDir, path string) (string, error) {
+	hostPath := filepath.Join(sandboxDir, filepath.FromSlash(path))
+	if hostPath != sandboxDir && !strings.HasPrefix(hostPath, sandboxDir+string(filepath.Separator)) {
+		return "", NewSandboxPathError(path)
+	}
+	return hostPath, nil
+}
+
+// prepareSandbox creates the sandbox directory of an execution, in which:
+//
+//   - each input is linked at its target path to its prepared source on the host
+//   - each output is linked at its path to a directory named after it in the results directory,
+//     so that files written by the process are collected as results
+//
+// Links don't restrict access to read-only inputs, so binaries must be trusted to not modify them,
+// which is why they have to be allow-listed by the compute node.
+func prepareSandbox(
+	ctx context.Context,
+	sandboxDir string,
+	resultsDir string,
+	inputs []storage.PreparedStorage,
+	outputs []*models.ResultPath,
+) error {
+	if err := os.MkdirAll(sandboxDir, sandboxDirPerm); err != nil {
+		return NewFilesystemError(sandboxDir, err)
+	}
+
+	for _, input := range inputs {
+		if input.Volume.Target == "" {
+			return NewInputConfigError("input source has no target path")
+		}
+		if input.Volume.Source == "" {
+			return NewInputConfigError("input source has no source path")
+		}
+		if _, err := os.Stat(input.Volume.Source); err != nil {
+			return NewInputConfigError(fmt.Sprintf("input source %q does not exist: %s", input.Volume.Source, err))
+		}
+		log.Ctx(ctx).Debug().
+			Str("target", input.Volume.Target).
+			Str("source", input.Volume.Source).
+			Msg("Linking input")
+		if err := link(sandboxDir, input.Volume.Source, input.Volume.Target); err != nil {
+			return err
+		}
+	}
+
+	for _, output := range outputs {
+		if output.Name == "" {
+			return NewOutputError("output volume has no name")
+		}
+		if output.Path == "" {
+			return NewOutputError("output volume has no path")
+		}
+		resultDir := filepath.Join(resultsDir, output.Name)
+		log.Ctx(ctx).Debug().
+			Str("output", output.Name).
+			Str("dir", resultDir).
+			Msg("Linking output")
+		if err := os.Mkdir(resultDir, sandboxDirPerm); err != nil {
+			return NewFilesystemError(output.Name, err)
+		}
+		if err := link(sandboxDir, resultDir, output.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// link creates a symbolic link at the target path of the sandbox to source
+func link(sandboxDir, source, target string) error {
+	hostPath, err := sandboxPath(sandboxDir, target)
+	if err != nil {
+		return err
+	}
+	if hostPath == sandboxDir {
+		return NewInputConfigError(fmt.Sprintf("target %q is the sandbox directory", target))
+	}
+	if _, err = os.Lstat(hostPath); err == nil {
+		return NewInputConfigError(fmt.Sprintf("target %q conflicts with another input or output", target))
+	}
+	if err = mkdirAll(sandboxDir, filepath.Dir(hostPath), target); err != nil {
+		return err
+	}
+	if err = os.Symlink(source, hostPath); err != nil {
+		return NewFilesystemError(target, err)
+	}
+	return nil
+}
+
+// mkdirAll creates a directory of the sandbox and its parents for the given path of the job.
+// It doesn't create directories through links, which would create them on the host.
+func mkdirAll(sandboxDir, hostPath, path string) error {
+	for dir := hostPath; dir != sandboxDir; dir = filepath.Dir(dir) {
+		if info, err := os.Lstat(dir); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			return NewInputConfigError(fmt.Sprintf("%q is nested in another input or output", path))
+		}
+	}
+	if err := os.MkdirAll(hostPath, sandboxDirPerm); err != nil {
+		return NewFilesystemError(path, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+//go:build unit || !integration
+
+package exec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+)
+
+func TestSandboxPath(t *testing.T) {
+	sandboxDir := filepath.Join(t.TempDir(), "sandbox")
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{path: "", expected: sandboxDir},
+		{path: "/", expected: sandboxDir},
+		{path: "/inputs/data", expected: filepath.Join(sandboxDir, "inputs", "data")},
+		{path: "outputs", expected: filepath.Join(sandboxDir, "outputs")},
+		{path: "/inputs/../outputs", expected: filepath.Join(sandboxDir, "outputs")},
+		{path: "../sandbox-other"},
+		{path: "/../../etc"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			path, err := sandboxPath(sandboxDir, tc.path)
+			if tc.expected == "" {
+				assert.True(t, bacerrors.IsErrorWithCode(err, SandboxPathError), err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, path)
+		})
+	}
+}
+
+func TestPrepareSandboxConflicts(t *testing.T) {
+	inputDir := t.TempDir()
+	input := func(target string) storage.PreparedStorage {
+		return storage.PreparedStorage{Volume: storage.StorageVolume{Source: inputDir, Target: target}}
+	}
+
+	testCases := []struct {
+		name    string
+		inputs  []storage.PreparedStorage
+		outputs []*models.ResultPath
+	}{
+		{
+			name:   "same input targets",
+			inputs: []storage.PreparedStorage{input("/inputs"), input("/inputs")},
+		},
+		{
+			name:   "input nested in input",
+			inputs: []storage.PreparedStorage{input("/inputs"), input("/inputs/nested")},
+		},
+		{
+			name:    "output nested in input",
+			inputs:  []storage.PreparedStorage{input("/data")},
+			outputs: []*models.ResultPath{{Name: "outputs", Path: "/data/outputs"}},
+		},
+		{
+			name:   "input at sandbox root",
+			inputs: []storage.PreparedStorage{input("/")},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			resultsDir := filepath.Join(dir, "results")
+			require.NoError(t, os.Mkdir(resultsDir, 0o755))
+			err := prepareSandbox(context.Background(), filepath.Join(dir, "sandbox"), resultsDir, tc.inputs, tc.outputs)
+			assert.True(t, bacerrors.IsErrorWithCode(err, InputConfigError), err)
+
+			// nothing is created in the input on the host
+			entries, err := os.ReadDir(inputDir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
@@ -20,6 +20,7 @@ type ExecutorHandlerHasStorageLocally func(ctx context.Context, volume models.In
 type ExecutorHandlerGetVolumeSize func(ctx context.Context, volume models.InputSource) (uint64, error)
 type ExecutorHandlerGetBidStrategy func(ctx context.Context) (bidstrategy.BidStrategy, error)
 type ExecutorHandlerJobHandler func(ctx context.Context, execContext ExecutionContext) (*models.RunCommandResult, error)
+type ExecutorHandlerExec func(ctx context.Context, request *executor.ExecRequest) (int, error)
 
 // ExecutionContext provides all the context needed for execution
 type ExecutionContext struct {
@@ -46,6 +47,7 @@ type ExecutorConfigExternalHooks struct {
 	GetVolumeSize     ExecutorHandlerGetVolumeSize
 	GetBidStrategy    ExecutorHandlerGetBidStrategy
 	JobHandler        ExecutorHandlerJobHandler
+	Exec              ExecutorHandlerExec
 }
 
 type ExecutorConfig struct {
@@ -239,5 +241,14 @@ func (e *NoopExecutor) GetLogStream(ctx context.Context, request messages.Execut
 	return nil, fmt.Errorf("not implemented for NoopExecutor")
 }
 
+func (e *NoopExecutor) Exec(ctx context.Context, request *executor.ExecRequest) (int, error) {
+	if e.Config.ExternalHooks.Exec != nil {
+		handler := e.Config.ExternalHooks.Exec
+		return handler(ctx, request)
+	}
+	return 0, NewNoopExecutorError(executor.ExecNotSupported, "exec not implemented for NoopExecutor")
+}
+
 // Compile-time check that Executor implements the Executor interface.
 var _ executor.Executor = (*NoopExecutor)(nil)
+var _ executor.Execer = (*NoopExecutor)(nil)
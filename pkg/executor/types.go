@@ -55,10 +55,30 @@ type Executor interface {
 	GetLogStream(ctx context.Context, request messages.ExecutionLogsRequest) (io.ReadCloser, error)
 }
 
+// Execer is implemented by executors that support running commands inside running executions,
+// or attaching to their main process.
+type Execer interface {
+	// Exec runs the command of the request inside the running execution, or attaches to the main
+	// process of the execution if no command is given. It blocks until the command exits, or the
+	// attached process exits or the context is done, and returns the exit code of the command.
+	// Returns an error if the execution does not exist or is not running.
+	Exec(ctx context.Context, request *ExecRequest) (int, error)
+}
+
+// ExecRequest holds the command to run inside a running execution, and the streams to connect to it.
+type ExecRequest struct {
+	ExecutionID string    // Unique identifier of the running execution.
+	Command     []string  // Command to run. Attaches to the main process of the execution if empty.
+	Stdin       io.Reader // Input of the command. The command has no input if nil.
+	Stdout      io.Writer // Where the standard output of the command is written.
+	Stderr      io.Writer // Where the standard error of the command is written.
+}
+
 // RunCommandRequest encapsulates the parameters required to initiate a job execution.
 // It includes identifiers, resource requirements, network configurations, and various other settings.
 type RunCommandRequest struct {
 	JobID        string                    // Unique identifier for the job.
+	Namespace    string                    // Namespace of the job.
 	ExecutionID  string                    // Unique identifier for a specific execution of the job.
 	Resources    *models.Resources         // Resource requirements like CPU, Memory, GPU, Disk.
 	Network      *models.NetworkConfig     // Network configuration for the execution.
@@ -77,6 +97,7 @@ const (
 	ExecutionAlreadyComplete  bacerrors.ErrorCode = "ExecutionAlreadyComplete"
 	ExecutionNotFound         bacerrors.ErrorCode = "ExecutionNotFound"
 	ExecutorSpecValidationErr bacerrors.ErrorCode = "ExecutorSpecValidationErr"
+	ExecNotSupported          bacerrors.ErrorCode = "ExecNotSupported"
 )
 
 func NewExecutorError(code bacerrors.ErrorCode, message string) bacerrors.Error {
@@ -2,19 +2,28 @@ package util
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/dustin/go-humanize"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/env"
 	"github.com/bacalhau-project/bacalhau/pkg/config/types"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	"github.com/bacalhau-project/bacalhau/pkg/executor/docker"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/exec"
 	noop_executor "github.com/bacalhau-project/bacalhau/pkg/executor/noop"
 	"github.com/bacalhau-project/bacalhau/pkg/executor/wasm"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/wasm/funcs/kv"
 	"github.com/bacalhau-project/bacalhau/pkg/ipfs"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/archive"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/git"
 	"github.com/bacalhau-project/bacalhau/pkg/storage/inline"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/inputcache"
 	ipfs_storage "github.com/bacalhau-project/bacalhau/pkg/storage/ipfs"
 	local_storage "github.com/bacalhau-project/bacalhau/pkg/storage/local"
 	noop_storage "github.com/bacalhau-project/bacalhau/pkg/storage/noop"
@@ -32,18 +41,47 @@ type StandardStorageProviderOptions struct {
 
 type StandardExecutorOptions struct {
 	DockerID string
+	// WASMCompilationCacheDir is the directory of the cache of compiled WASM modules.
+	// Compiled modules are not cached if empty.
+	WASMCompilationCacheDir string
+	// WASMKeyValueStorePath is the path of the database of the key-value store exposed to WASM jobs.
+	// WASM jobs have no key-value store if empty.
+	WASMKeyValueStorePath string
+	// AllowListedBinaries are the host binaries that jobs using the exec engine are allowed to run.
+	AllowListedBinaries []string
+	// AllowListedExecEnv are the environment variables that jobs using the exec engine are allowed to set.
+	AllowListedExecEnv []string
 }
 
 func NewStandardStorageProvider(cfg types.Bacalhau) (storage.StorageProvider, error) {
 	providers := make(map[string]storage.Storage)
 
+	inputCache, err := newInputCache(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// secrets of the sources are resolved like the environment variables of the jobs
+	resolver := env.NewResolver(env.ResolverParams{
+		AllowList: cfg.Compute.Env.AllowList,
+	})
+
 	if cfg.InputSources.IsNotDisabled(models.StorageSourceURL) {
 		providers[models.StorageSourceURL] = tracing.Wrap(urldownload.NewStorage(
 			time.Duration(cfg.InputSources.ReadTimeout),
 			cfg.InputSources.MaxRetryCount,
+			inputCache,
+			resolver,
 		))
 	}
 
+	if cfg.InputSources.IsNotDisabled(models.StorageSourceGit) {
+		providers[models.StorageSourceGit] = tracing.Wrap(git.NewStorage(git.StorageProviderParams{
+			Timeout:  time.Duration(cfg.InputSources.ReadTimeout),
+			Resolver: resolver,
+		}))
+	}
+
 	if cfg.InputSources.IsNotDisabled(models.StorageSourceInline) {
 		providers[models.StorageSourceInline] = tracing.Wrap(inline.NewStorage())
 	}
@@ -59,6 +97,7 @@ func NewStandardStorageProvider(cfg types.Bacalhau) (storage.StorageProvider, er
 		providers[models.StorageSourceS3] = tracing.Wrap(s3.NewStorage(
 			time.Duration(cfg.InputSources.ReadTimeout),
 			clientProvider,
+			inputCache,
 		))
 	}
 
@@ -82,7 +121,8 @@ func NewStandardStorageProvider(cfg types.Bacalhau) (storage.StorageProvider, er
 			if err != nil {
 				return nil, err
 			}
-			ipfsStorage, err := ipfs_storage.NewStorage(*ipfsClient, time.Duration(cfg.InputSources.ReadTimeout))
+			ipfsStorage, err := ipfs_storage.NewStorage(
+				*ipfsClient, time.Duration(cfg.InputSources.ReadTimeout), inputCache)
 			if err != nil {
 				return nil, err
 			}
@@ -90,6 +130,15 @@ func NewStandardStorageProvider(cfg types.Bacalhau) (storage.StorageProvider, er
 		}
 	}
 
+	// inputs of any storage can be archives to extract
+	extraction, err := newArchiveParams(cfg.InputSources.Extraction)
+	if err != nil {
+		return nil, err
+	}
+	for name, strg := range providers {
+		providers[name] = archive.Wrap(strg, extraction)
+	}
+
 	return provider.NewMappedProvider(providers), nil
 }
 
@@ -120,16 +169,117 @@ func NewStandardExecutorProvider(
 	}
 
 	if cfg.IsNotDisabled(models.EngineWasm) {
-		wasmExecutor, err := wasm.NewExecutor()
+		compilationCache, err := newWASMCompilationCache(
+			cfg.Types.WASM.CompilationCache, executorOptions.WASMCompilationCacheDir)
+		if err != nil {
+			return nil, err
+		}
+		keyValueStore, err := newWASMKeyValueStore(
+			cfg.Types.WASM.KeyValueStore, executorOptions.WASMKeyValueStorePath)
+		if err != nil {
+			return nil, err
+		}
+		wasmExecutor, err := wasm.NewExecutor(wasm.ExecutorParams{
+			CompilationCache: compilationCache,
+			KeyValueStore:    keyValueStore,
+		})
 		if err != nil {
 			return nil, err
 		}
 		providers[models.EngineWasm] = wasmExecutor
 	}
 
+	if cfg.IsNotDisabled(models.EngineExec) {
+		execExecutor, err := exec.NewExecutor(exec.ExecutorParams{
+			AllowListedBinaries: executorOptions.AllowListedBinaries,
+			AllowListedEnv:      executorOptions.AllowListedExecEnv,
+		})
+		if err != nil {
+			return nil, err
+		}
+		providers[models.EngineExec] = execExecutor
+	}
+
 	return provider.NewMappedProvider(providers), nil
 }
 
+// newWASMCompilationCache creates the cache of compiled WASM modules,
+// or returns nil if it is disabled or has no directory.
+func newWASMCompilationCache(cfg types.WASMCompilationCache, dir string) (*wasm.CompilationCache, error) {
+	if !cfg.Enabled || dir == "" {
+		return nil, nil
+	}
+	var maxSize uint64
+	if cfg.MaxSize != "" {
+		var err error
+		if maxSize, err = humanize.ParseBytes(cfg.MaxSize); err != nil {
+			return nil, fmt.Errorf("invalid WASM compilation cache max size %q: %w", cfg.MaxSize, err)
+		}
+	}
+	return wasm.NewCompilationCache(wasm.CompilationCacheParams{
+		Dir:     dir,
+		MaxSize: maxSize,
+	})
+}
+
+// newArchiveParams returns the limits of extracting archive inputs
+func newArchiveParams(cfg types.InputExtraction) (archive.Params, error) {
+	params := archive.Params{}
+	if cfg.MaxRatio > 0 {
+		params.MaxRatio = uint64(cfg.MaxRatio)
+	}
+	if cfg.MaxSize != "" {
+		var err error
+		if params.MaxSize, err = humanize.ParseBytes(cfg.MaxSize); err != nil {
+			return archive.Params{}, fmt.Errorf("invalid input extraction max size %q: %w", cfg.MaxSize, err)
+		}
+	}
+	return params, nil
+}
+
+// newInputCache creates the cache of the inputs of executions shared by the storages,
+// or returns nil if it is disabled or the node has no data directory.
+func newInputCache(cfg types.Bacalhau) (*inputcache.Cache, error) {
+	if !cfg.InputSources.Cache.Enabled || cfg.DataDir == "" {
+		return nil, nil
+	}
+	dir, err := cfg.InputCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	var maxSize uint64
+	if cfg.InputSources.Cache.MaxSize != "" {
+		if maxSize, err = humanize.ParseBytes(cfg.InputSources.Cache.MaxSize); err != nil {
+			return nil, fmt.Errorf("invalid input cache max size %q: %w", cfg.InputSources.Cache.MaxSize, err)
+		}
+	}
+	return inputcache.New(inputcache.Params{
+		Dir:     dir,
+		MaxSize: maxSize,
+	})
+}
+
+// newWASMKeyValueStore creates the key-value store exposed to WASM jobs,
+// or returns nil if it is disabled or has no path.
+func newWASMKeyValueStore(cfg types.WASMKeyValueStore, path string) (*kv.Store, error) {
+	if !cfg.Enabled || path == "" {
+		return nil, nil
+	}
+	params := kv.StoreParams{Path: path}
+	var err error
+	if cfg.MaxBucketSize != "" {
+		if params.MaxBucketSize, err = humanize.ParseBytes(cfg.MaxBucketSize); err != nil {
+			return nil, fmt.Errorf("invalid WASM key-value store max bucket size %q: %w", cfg.MaxBucketSize, err)
+		}
+	}
+	if cfg.MaxValueSize != "" {
+		if params.MaxValueSize, err = humanize.ParseBytes(cfg.MaxValueSize); err != nil {
+			return nil, fmt.Errorf("invalid WASM key-value store max value size %q: %w", cfg.MaxValueSize, err)
+		}
+	}
+	return kv.NewStore(params)
+}
+
 // return noop executors for all engines
 func NewNoopExecutors(config noop_executor.ExecutorConfig) executor.ExecProvider {
 	noopExecutor := noop_executor.NewNoopExecutorWithConfig(config)
@@ -0,0 +1,59 @@
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+
+	wasmmodels "github.com/bacalhau-project/bacalhau/pkg/executor/wasm/models"
+)
+
+// Headers of WASM binaries, made of the magic number followed by the version and layer.
+// Core modules are layer 0, and components are layer 1.
+var (
+	wasmMagic       = []byte{0x00, 0x61, 0x73, 0x6d}
+	moduleHeader    = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	componentLayer  = []byte{0x01, 0x00}
+	wasmHeaderBytes = len(moduleHeader)
+)
+
+// isComponent returns whether the header is the one of a WebAssembly component rather than a core module
+func isComponent(header []byte) bool {
+	return len(header) >= wasmHeaderBytes && bytes.HasPrefix(header, wasmMagic) && bytes.Equal(header[6:8], componentLayer)
+}
+
+// validateBinaries checks that the entry and import modules of the spec are core modules rather than
+// components, so that jobs with mismatched binaries fail before anything runs.
+func validateBinaries(fsys fs.FS, spec wasmmodels.EngineSpec) error {
+	loader := NewModuleLoader(nil, nil, fsys)
+	for _, modulePath := range append([]string{spec.EntryModule}, spec.ImportModules...) {
+		path, err := loader.resolveModulePath(modulePath)
+		if err != nil {
+			return err
+		}
+		header, err := readHeader(fsys, path)
+		if err != nil {
+			return NewModuleLoadError(path, err)
+		}
+		if isComponent(header) {
+			return NewComponentNotSupportedError(path)
+		}
+	}
+	return nil
+}
+
+// readHeader reads the header of the file at the given path, which may be shorter than a WASM header
+func readHeader(fsys fs.FS, path string) ([]byte, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	header := make([]byte, wasmHeaderBytes)
+	n, err := io.ReadFull(file, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return header[:n], nil
+}
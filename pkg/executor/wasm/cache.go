@@ -0,0 +1,245 @@
+package wasm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tetratelabs/wazero"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/diskcache"
+)
+
+// compilationCacheIndexFile is the name of the file in the cache directory that records
+// which compiled modules are cached, and when they were last used
+const compilationCacheIndexFile = "index.json"
+
+// CompilationCacheParams configures a CompilationCache
+type CompilationCacheParams struct {
+	// Dir is the directory in which compiled modules are stored
+	Dir string
+	// MaxSize is the maximum total size in bytes of the compiled modules stored in Dir.
+	// Zero means no limit.
+	MaxSize uint64
+}
+
+// CompilationCache is a node-level cache of compiled WASM modules, stored on disk and shared
+// by the runtimes of all executions, so that a module run by many executions is only compiled once.
+//
+// Compiled modules are stored by a wazero.CompilationCache. On top of it, the cache keeps an index of
+// the files of the compiled module of each WASM binary, addressed by the SHA-256 of its content, to limit
+// the total size of the cache by evicting the least recently used modules.
+//
+// Binaries that are not cached yet are compiled one at a time, which lets the cache identify the files wazero
+// writes for each compiled module, and callers compiling a binary being compiled wait for it, as recommended by
+// wazero. Cached modules are loaded concurrently, and are not evicted while they are being loaded.
+type CompilationCache struct {
+	cache wazero.CompilationCache
+	dir   string
+	index *diskcache.Index[[]string]
+
+	// compileMu serializes the compilations of binaries that are not cached yet
+	compileMu sync.Mutex
+
+	mu        sync.Mutex
+	compiling map[string]chan struct{}
+}
+
+// NewCompilationCache creates a compilation cache storing compiled modules in the given directory.
+// Entries of a previous run of the node are reused, and files not recorded in the index are removed.
+func NewCompilationCache(params CompilationCacheParams) (*CompilationCache, error) {
+	if params.Dir == "" {
+		return nil, errors.New("compilation cache directory is required")
+	}
+	cache, err := wazero.NewCompilationCacheWithDir(params.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compilation cache in %s: %w", params.Dir, err)
+	}
+	c := &CompilationCache{
+		cache:     cache,
+		dir:       params.Dir,
+		compiling: make(map[string]chan struct{}),
+	}
+	c.index = diskcache.NewIndex(diskcache.IndexParams[[]string]{
+		Name:            "WASM compilation cache",
+		Path:            filepath.Join(params.Dir, compilationCacheIndexFile),
+		MaxSize:         params.MaxSize,
+		Remove:          c.removeFiles,
+		SizeMetric:      CompilationCacheSize,
+		EvictionsMetric: CompilationCacheEvictions,
+	})
+	if err = c.loadIndex(); err != nil {
+		_ = cache.Close(context.Background())
+		return nil, err
+	}
+	return c, nil
+}
+
+// ConfigureRuntime returns the runtime configuration with the compilation cache set,
+// so that runtimes created with it share the compiled modules.
+func (c *CompilationCache) ConfigureRuntime(config wazero.RuntimeConfig) wazero.RuntimeConfig {
+	return config.WithCompilationCache(c.cache)
+}
+
+// CompileModule compiles the WASM binary with the runtime, which must be configured
+// with ConfigureRuntime, reusing the compiled module stored in the cache if any.
+func (c *CompilationCache) CompileModule(
+	ctx context.Context, runtime wazero.Runtime, binary []byte) (wazero.CompiledModule, error) {
+	hash := sha256.Sum256(binary)
+	key := hex.EncodeToString(hash[:])
+
+	for {
+		if c.index.Pin(key) {
+			defer c.index.Unpin(key)
+			CompilationCacheHits.Inc(ctx)
+			return runtime.CompileModule(ctx, binary)
+		}
+
+		c.mu.Lock()
+		inProgress, ok := c.compiling[key]
+		if !ok {
+			done := make(chan struct{})
+			c.compiling[key] = done
+			c.mu.Unlock()
+			defer func() {
+				c.mu.Lock()
+				delete(c.compiling, key)
+				c.mu.Unlock()
+				close(done)
+			}()
+			break
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-inProgress:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	CompilationCacheMisses.Inc(ctx)
+	c.compileMu.Lock()
+	defer c.compileMu.Unlock()
+	before, err := c.listFiles()
+	if err != nil {
+		return nil, err
+	}
+	module, err := runtime.CompileModule(ctx, binary)
+	if err != nil {
+		return nil, err
+	}
+	after, err := c.listFiles()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to list the files of the WASM compilation cache")
+		return module, nil
+	}
+
+	// the files wazero stored are the ones of this compiled module. There are none if the
+	// module was already compiled by a running execution, or if wazero does not support
+	// compiling on this platform.
+	var files []string
+	var size uint64
+	for file, fileSize := range after {
+		if _, existed := before[file]; !existed {
+			files = append(files, file)
+			size += fileSize
+		}
+	}
+	if len(files) > 0 {
+		c.index.Add(ctx, key, files, size)
+	}
+	return module, nil
+}
+
+// Close releases the resources of the cache, and persists the last uses of the compiled modules.
+// Compiled modules stay on disk.
+func (c *CompilationCache) Close(ctx context.Context) error {
+	c.index.Save(ctx)
+	return c.cache.Close(ctx)
+}
+
+// removeFiles deletes the files of a compiled module evicted from the cache
+func (c *CompilationCache) removeFiles(ctx context.Context, key string, files []string) {
+	for _, file := range files {
+		if err := os.Remove(filepath.Join(c.dir, file)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Ctx(ctx).Warn().Err(err).Str("file", file).Msg("failed to remove compiled WASM module")
+		}
+	}
+}
+
+// listFiles returns the size of the files stored by wazero in the cache directory,
+// by their path relative to the directory
+func (c *CompilationCache) listFiles() (map[string]uint64, error) {
+	files := make(map[string]uint64)
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(c.dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == compilationCacheIndexFile || strings.HasSuffix(rel, ".tmp") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files[rel] = uint64(info.Size())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the files of the compilation cache: %w", err)
+	}
+	return files, nil
+}
+
+// loadIndex loads the index of the entries of a previous run, dropping the entries whose files are
+// missing, and removes the files that are not part of any entry, such as modules compiled by
+// another version of wazero.
+func (c *CompilationCache) loadIndex() error {
+	ctx := context.Background()
+	files, err := c.listFiles()
+	if err != nil {
+		return err
+	}
+	err = c.index.Load(ctx, func(_ string, entry diskcache.Entry[[]string]) bool {
+		for _, file := range entry.Value {
+			if _, ok := files[file]; !ok {
+				return false
+			}
+		}
+		return len(entry.Value) > 0
+	})
+	if err != nil {
+		return err
+	}
+
+	indexed := make(map[string]bool)
+	for _, entry := range c.index.Entries() {
+		for _, file := range entry.Value {
+			indexed[file] = true
+		}
+	}
+	for file := range files {
+		if !indexed[file] {
+			if err = os.Remove(filepath.Join(c.dir, file)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				log.Warn().Err(err).Str("file", file).Msg("failed to remove unindexed compiled WASM module")
+			}
+		}
+	}
+	return nil
+}
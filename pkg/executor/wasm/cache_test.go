@@ -0,0 +1,151 @@
+//go:build unit || !integration
+
+package wasm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/tetratelabs/wazero"
+
+	"github.com/bacalhau-project/bacalhau/pkg/logger"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/easter"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/noop"
+)
+
+type CompilationCacheSuite struct {
+	suite.Suite
+	dir string
+}
+
+func TestCompilationCacheSuite(t *testing.T) {
+	suite.Run(t, new(CompilationCacheSuite))
+}
+
+func (s *CompilationCacheSuite) SetupTest() {
+	logger.ConfigureTestLogging(s.T())
+	s.dir = s.T().TempDir()
+}
+
+func (s *CompilationCacheSuite) newCache(maxSize uint64) *CompilationCache {
+	cache, err := NewCompilationCache(CompilationCacheParams{Dir: s.dir, MaxSize: maxSize})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		s.Require().NoError(cache.Close(context.Background()))
+	})
+	return cache
+}
+
+// compile compiles the binary in a new runtime, as an execution does
+func (s *CompilationCacheSuite) compile(cache *CompilationCache, binary []byte) {
+	ctx := context.Background()
+	runtime := wazero.NewRuntimeWithConfig(ctx, cache.ConfigureRuntime(wazero.NewRuntimeConfig()))
+	defer runtime.Close(ctx)
+	module, err := cache.CompileModule(ctx, runtime, binary)
+	s.Require().NoError(err)
+	s.Require().NotNil(module)
+}
+
+func (s *CompilationCacheSuite) keys(cache *CompilationCache) []string {
+	var keys []string
+	for key := range cache.index.Entries() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (s *CompilationCacheSuite) TestCachesCompiledModules() {
+	cache := s.newCache(0)
+	s.compile(cache, noop.Program())
+	s.Require().Len(s.keys(cache), 1)
+	s.Positive(cache.index.Size())
+	files, err := cache.listFiles()
+	s.Require().NoError(err)
+	s.Len(files, 1)
+
+	// compiling the same binary again reuses the cached module
+	s.compile(cache, noop.Program())
+	s.Len(s.keys(cache), 1)
+	files, err = cache.listFiles()
+	s.Require().NoError(err)
+	s.Len(files, 1)
+}
+
+func (s *CompilationCacheSuite) TestEvictsLeastRecentlyUsedModules() {
+	cache := s.newCache(0)
+	s.compile(cache, noop.Program())
+	noopSize := cache.index.Size()
+	s.compile(cache, easter.Program())
+	easterSize := cache.index.Size() - noopSize
+	s.Require().NoError(cache.Close(context.Background()))
+
+	// a cache only large enough for the biggest module keeps the most recently used one
+	limited := s.newCache(max(noopSize, easterSize))
+	s.Require().Len(s.keys(limited), 1)
+	s.Equal(easterSize, limited.index.Size())
+
+	s.compile(limited, noop.Program())
+	s.Require().Len(s.keys(limited), 1)
+	s.Equal(noopSize, limited.index.Size())
+	files, err := limited.listFiles()
+	s.Require().NoError(err)
+	s.Len(files, 1)
+}
+
+func (s *CompilationCacheSuite) TestReusesModulesAfterRestart() {
+	cache := s.newCache(0)
+	s.compile(cache, noop.Program())
+	keys := s.keys(cache)
+	s.Require().NoError(cache.Close(context.Background()))
+
+	restarted := s.newCache(0)
+	s.Equal(keys, s.keys(restarted))
+	s.compile(restarted, noop.Program())
+	s.Equal(keys, s.keys(restarted))
+}
+
+func (s *CompilationCacheSuite) TestRemovesUnindexedFiles() {
+	cache := s.newCache(0)
+	s.compile(cache, noop.Program())
+	s.Require().NoError(cache.Close(context.Background()))
+
+	// without its index, the compiled modules of the cache can't be attributed to binaries
+	s.Require().NoError(os.Remove(filepath.Join(s.dir, compilationCacheIndexFile)))
+	restarted := s.newCache(0)
+	s.Empty(s.keys(restarted))
+	s.Zero(restarted.index.Size())
+	files, err := restarted.listFiles()
+	s.Require().NoError(err)
+	s.Empty(files)
+}
+
+func (s *CompilationCacheSuite) TestCompilesConcurrently() {
+	cache := s.newCache(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		binary := noop.Program()
+		if i%2 == 1 {
+			binary = easter.Program()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := context.Background()
+			runtime := wazero.NewRuntimeWithConfig(ctx, cache.ConfigureRuntime(wazero.NewRuntimeConfig()))
+			defer runtime.Close(ctx)
+			_, err := cache.CompileModule(ctx, runtime, binary)
+			s.NoError(err)
+		}()
+	}
+	wg.Wait()
+
+	// each binary was compiled once, and its files attributed to it
+	s.Len(s.keys(cache), 2)
+	files, err := cache.listFiles()
+	s.Require().NoError(err)
+	s.Len(files, 2)
+}
@@ -17,12 +17,16 @@ const (
 	ModuleLoadError    = "ModuleLoadError"
 	WASIError          = "WASIError"
 	UnknownModuleError = "UnknownModuleError"
+	// ComponentNotSupported is returned when a module of a job is a WebAssembly component
+	ComponentNotSupported = "ComponentNotSupported"
 	// Handler and Executor specific error codes
 	SpecError        = "SpecError"
 	LogError         = "LogError"
 	EntrypointError  = "EntrypointError"
 	MemoryLimitError = "MemoryLimitError"
 	FilesystemError  = "FilesystemError"
+	// InstructionLimitExceeded is returned when an execution runs more instructions than its limit
+	InstructionLimitExceeded = "InstructionLimitExceeded"
 	// Configuration error codes
 	InputConfigError  = "InputConfigError"
 	OutputConfigError = "OutputConfigError"
@@ -81,6 +85,15 @@ func NewUnknownModuleError(moduleName string) bacerrors.Error {
 4. If using WASI, make sure the module name matches %q`, wasi_snapshot_preview1.ModuleName)
 }
 
+// NewComponentNotSupportedError creates an error when a WASM binary is a component rather than a core module.
+func NewComponentNotSupportedError(path string) bacerrors.Error {
+	return bacerrors.Newf("binary at %q is a WebAssembly component, which is not supported", path).
+		WithCode(ComponentNotSupported).
+		WithHTTPStatusCode(http.StatusBadRequest).
+		WithComponent(Component).
+		WithHint("Compile the job to a core WebAssembly module targeting WASI preview1")
+}
+
 // NewSpecError creates an error when there's an issue with the WASM spec
 func NewSpecError(err error) bacerrors.Error {
 	return bacerrors.Wrap(err, "invalid WASM spec").
@@ -119,6 +132,15 @@ func NewMemoryLimitError(requested, max uint64) bacerrors.Error {
 		WithHint("Reduce the requested memory to be within the WASM limit")
 }
 
+// NewInstructionLimitError creates an error when an execution exceeds its instruction limit
+func NewInstructionLimitError(limit uint64) bacerrors.Error {
+	return bacerrors.Newf("execution exceeded its limit of %d instructions", limit).
+		WithCode(InstructionLimitExceeded).
+		WithHTTPStatusCode(http.StatusBadRequest).
+		WithComponent(Component).
+		WithHint("Increase the instruction limit of the job, or reduce the work it does")
+}
+
 // NewFilesystemError creates an error when there's an issue with the filesystem
 func NewFilesystemError(path string, err error) bacerrors.Error {
 	return bacerrors.Wrapf(err, "filesystem error at %q", path).
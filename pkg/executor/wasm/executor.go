@@ -2,6 +2,7 @@ package wasm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -20,6 +21,7 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/wasm/funcs/kv"
 	"github.com/bacalhau-project/bacalhau/pkg/executor/wasm/util/filefs"
 	"github.com/bacalhau-project/bacalhau/pkg/executor/wasm/util/mountfs"
 	"github.com/bacalhau-project/bacalhau/pkg/executor/wasm/util/touchfs"
@@ -32,11 +34,38 @@ import (
 type Executor struct {
 	// handlers is a map of executionID to its handler.
 	handlers generic.SyncMap[string, *executionHandler]
+	// compilationCache is shared by the runtimes of all executions, and is nil if disabled.
+	compilationCache *CompilationCache
+	// keyValueStore backs the key-value host functions, and is nil if disabled.
+	keyValueStore *kv.Store
+}
+
+// ExecutorParams configures the WASM executor.
+type ExecutorParams struct {
+	// CompilationCache optionally caches compiled modules across executions.
+	CompilationCache *CompilationCache
+	// KeyValueStore optionally backs the key-value host functions exposed to executions.
+	KeyValueStore *kv.Store
 }
 
 // NewExecutor creates a new WASM executor instance.
-func NewExecutor() (*Executor, error) {
-	return &Executor{}, nil
+func NewExecutor(params ExecutorParams) (*Executor, error) {
+	return &Executor{
+		compilationCache: params.CompilationCache,
+		keyValueStore:    params.KeyValueStore,
+	}, nil
+}
+
+// Close releases the compilation cache and key-value store of the executor.
+func (e *Executor) Close(ctx context.Context) error {
+	var err error
+	if e.compilationCache != nil {
+		err = e.compilationCache.Close(ctx)
+	}
+	if e.keyValueStore != nil {
+		err = errors.Join(err, e.keyValueStore.Close(ctx))
+	}
+	return err
 }
 
 // IsInstalled checks if the WASM executor is available.
@@ -92,6 +121,8 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 	handler, err := newExecutionHandler(ctx,
 		request,
 		wazero.NewRuntimeWithConfig(ctx, engineConfig),
+		e.compilationCache,
+		e.keyValueStore,
 		rootFs)
 
 	if err != nil {
@@ -119,6 +150,9 @@ func (e *Executor) configureRuntime(memoryLimit uint64) (wazero.RuntimeConfig, e
 		}
 		engineConfig = engineConfig.WithMemoryLimitPages(uint32(requestedPages))
 	}
+	if e.compilationCache != nil {
+		engineConfig = e.compilationCache.ConfigureRuntime(engineConfig)
+	}
 	return engineConfig, nil
 }
 
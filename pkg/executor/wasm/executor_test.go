@@ -5,14 +5,21 @@ package wasm
 import (
 	"context"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	wasmmodels "github.com/bacalhau-project/bacalhau/pkg/executor/wasm/models"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/noop"
 )
 
+// componentHeader is the header of a WebAssembly component binary
+var componentHeader = []byte{0x00, 0x61, 0x73, 0x6d, 0x0d, 0x00, 0x01, 0x00}
+
 type ExecutorTestSuite struct {
 	suite.Suite
 }
@@ -22,7 +29,7 @@ func TestExecutorTestSuite(t *testing.T) {
 }
 
 func (s *ExecutorTestSuite) TestFailingRequestedMemGreaterThan4GB() {
-	e, err := NewExecutor()
+	e, err := NewExecutor(ExecutorParams{})
 	s.Require().NoError(err)
 
 	r := &executor.RunCommandRequest{
@@ -41,3 +48,52 @@ func (s *ExecutorTestSuite) TestFailingRequestedMemGreaterThan4GB() {
 
 	assert.Contains(s.T(), err.Error(), "requested memory exceeds the wasm limit")
 }
+
+func (s *ExecutorTestSuite) TestValidateBinaries() {
+	fsys := fstest.MapFS{
+		"module.wasm":        {Data: noop.Program()},
+		"component.wasm":     {Data: componentHeader},
+		"lib/component.wasm": {Data: componentHeader},
+		"data.txt":           {Data: []byte("not wasm")},
+	}
+
+	testCases := []struct {
+		name string
+		spec wasmmodels.EngineSpec
+		code bacerrors.ErrorCode
+	}{
+		{
+			name: "module",
+			spec: wasmmodels.EngineSpec{EntryModule: "module.wasm"},
+		},
+		{
+			name: "binaries that are not WASM are left to the loader",
+			spec: wasmmodels.EngineSpec{EntryModule: "module.wasm", ImportModules: []string{"data.txt"}},
+		},
+		{
+			name: "component entry module",
+			spec: wasmmodels.EngineSpec{EntryModule: "component.wasm"},
+			code: ComponentNotSupported,
+		},
+		{
+			name: "component import module in a directory",
+			spec: wasmmodels.EngineSpec{EntryModule: "module.wasm", ImportModules: []string{"lib"}},
+			code: ComponentNotSupported,
+		},
+		{
+			name: "missing module",
+			spec: wasmmodels.EngineSpec{EntryModule: "missing.wasm"},
+			code: ModuleNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			err := validateBinaries(fsys, tc.spec)
+			if tc.code == "" {
+				s.NoError(err)
+			} else {
+				s.True(bacerrors.IsErrorWithCode(err, tc.code), "expected %s, got %v", tc.code, err)
+			}
+		})
+	}
+}
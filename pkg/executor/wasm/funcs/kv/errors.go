@@ -0,0 +1,51 @@
+package kv
+
+import (
+	"net/http"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+)
+
+const errComponent = "WASMKeyValue"
+
+// Key-value store error codes
+const (
+	NotFound      bacerrors.ErrorCode = "KeyNotFound"
+	InvalidKey    bacerrors.ErrorCode = "InvalidKey"
+	ValueTooLarge bacerrors.ErrorCode = "ValueTooLarge"
+	QuotaExceeded bacerrors.ErrorCode = "QuotaExceeded"
+)
+
+// NewNotFoundError creates an error when a key is not set
+func NewNotFoundError(key string) bacerrors.Error {
+	return bacerrors.Newf("key %q not found", key).
+		WithCode(NotFound).
+		WithHTTPStatusCode(http.StatusNotFound).
+		WithComponent(errComponent)
+}
+
+// NewInvalidKeyError creates an error when a key is empty or too long
+func NewInvalidKeyError(key string) bacerrors.Error {
+	return bacerrors.Newf("invalid key %q", key).
+		WithCode(InvalidKey).
+		WithHTTPStatusCode(http.StatusBadRequest).
+		WithComponent(errComponent).
+		WithHint("Keys must be between 1 and %d bytes long", MaxKeySize)
+}
+
+// NewValueTooLargeError creates an error when a value exceeds the maximum value size
+func NewValueTooLargeError(key string, size int, maxSize uint64) bacerrors.Error {
+	return bacerrors.Newf("value of key %q is %d bytes, which exceeds the limit of %d bytes", key, size, maxSize).
+		WithCode(ValueTooLarge).
+		WithHTTPStatusCode(http.StatusRequestEntityTooLarge).
+		WithComponent(errComponent)
+}
+
+// NewQuotaExceededError creates an error when a bucket would exceed its maximum size
+func NewQuotaExceededError(bucket string, maxSize uint64) bacerrors.Error {
+	return bacerrors.Newf("bucket %q would exceed its quota of %d bytes", bucket, maxSize).
+		WithCode(QuotaExceeded).
+		WithHTTPStatusCode(http.StatusInsufficientStorage).
+		WithComponent(errComponent).
+		WithHint("Delete keys that are no longer needed from the bucket")
+}
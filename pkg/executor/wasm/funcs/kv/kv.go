@@ -0,0 +1,186 @@
+// Package kv provides host functions giving WASM jobs a key-value store, scoped to
+// a bucket shared by the executions of a job or of a namespace.
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+)
+
+// ModuleName defines the key-value function namespace
+const ModuleName = "bacalhau:kv/store"
+
+// Result codes
+const (
+	StatusSuccess        uint32 = 0
+	StatusNotFound       uint32 = 1
+	StatusBadInput       uint32 = 2
+	StatusMemoryError    uint32 = 3
+	StatusBufferTooSmall uint32 = 4
+	StatusValueTooLarge  uint32 = 5
+	StatusQuotaExceeded  uint32 = 6
+	StatusStorageError   uint32 = 7
+)
+
+type Params struct {
+	// Bucket is the bucket the functions operate on. No functions are registered if nil.
+	Bucket *Bucket
+}
+
+// InstantiateModule instantiates the key-value host functions
+func InstantiateModule(ctx context.Context, r wazero.Runtime, params Params) error {
+	if params.Bucket == nil {
+		return nil // Don't register any key-value functions
+	}
+
+	kvModule := &module{bucket: params.Bucket}
+	moduleBuilder := r.NewHostModuleBuilder(ModuleName)
+
+	moduleBuilder.NewFunctionBuilder().
+		WithFunc(kvModule.get).
+		WithName("kv_get").
+		WithParameterNames("key_ptr", "key_len", "value_ptr", "value_len_ptr").
+		WithResultNames("status").
+		Export("kv_get")
+
+	moduleBuilder.NewFunctionBuilder().
+		WithFunc(kvModule.put).
+		WithName("kv_put").
+		WithParameterNames("key_ptr", "key_len", "value_ptr", "value_len").
+		WithResultNames("status").
+		Export("kv_put")
+
+	moduleBuilder.NewFunctionBuilder().
+		WithFunc(kvModule.delete).
+		WithName("kv_delete").
+		WithParameterNames("key_ptr", "key_len").
+		WithResultNames("status").
+		Export("kv_delete")
+
+	moduleBuilder.NewFunctionBuilder().
+		WithFunc(kvModule.list).
+		WithName("kv_list").
+		WithParameterNames("prefix_ptr", "prefix_len", "keys_ptr", "keys_len_ptr").
+		WithResultNames("status").
+		Export("kv_list")
+
+	_, err := moduleBuilder.Instantiate(ctx)
+	return err
+}
+
+// module implements the key-value host functions on a bucket
+type module struct {
+	bucket *Bucket
+}
+
+// get reads the value of a key into the buffer at valuePtr. The uint32 at valueLenPtr holds the size of
+// the buffer, and is set to the size of the value. If the buffer is too small, only the size is written.
+func (m *module) get(ctx context.Context, mod api.Module, keyPtr, keyLen, valuePtr, valueLenPtr uint32) uint32 {
+	memory := mod.Memory()
+	key, ok := memory.Read(keyPtr, keyLen)
+	if !ok {
+		return StatusMemoryError
+	}
+	bufSize, ok := memory.ReadUint32Le(valueLenPtr)
+	if !ok {
+		return StatusMemoryError
+	}
+
+	value, err := m.bucket.Get(ctx, string(key))
+	if err != nil {
+		return statusFromError(ctx, err)
+	}
+	return writeBuffer(memory, value, valuePtr, valueLenPtr, bufSize)
+}
+
+// put sets the value of a key
+func (m *module) put(ctx context.Context, mod api.Module, keyPtr, keyLen, valuePtr, valueLen uint32) uint32 {
+	memory := mod.Memory()
+	key, ok := memory.Read(keyPtr, keyLen)
+	if !ok {
+		return StatusMemoryError
+	}
+	value, ok := memory.Read(valuePtr, valueLen)
+	if !ok {
+		return StatusMemoryError
+	}
+	return statusFromError(ctx, m.bucket.Put(ctx, string(key), value))
+}
+
+// delete deletes a key. Deleting a key that is not set succeeds.
+func (m *module) delete(ctx context.Context, mod api.Module, keyPtr, keyLen uint32) uint32 {
+	key, ok := mod.Memory().Read(keyPtr, keyLen)
+	if !ok {
+		return StatusMemoryError
+	}
+	return statusFromError(ctx, m.bucket.Delete(ctx, string(key)))
+}
+
+// list writes the keys starting with a prefix into the buffer at keysPtr, each as a little-endian uint32
+// length followed by the key. The uint32 at keysLenPtr holds the size of the buffer, and is set to the
+// size of the encoded keys. If the buffer is too small, only the size is written.
+func (m *module) list(ctx context.Context, mod api.Module, prefixPtr, prefixLen, keysPtr, keysLenPtr uint32) uint32 {
+	memory := mod.Memory()
+	prefix, ok := memory.Read(prefixPtr, prefixLen)
+	if !ok {
+		return StatusMemoryError
+	}
+	bufSize, ok := memory.ReadUint32Le(keysLenPtr)
+	if !ok {
+		return StatusMemoryError
+	}
+
+	keys, err := m.bucket.List(ctx, string(prefix))
+	if err != nil {
+		return statusFromError(ctx, err)
+	}
+	var encoded []byte
+	for _, key := range keys {
+		encoded = binary.LittleEndian.AppendUint32(encoded, uint32(len(key))) //nolint:gosec // keys are at most MaxKeySize
+		encoded = append(encoded, key...)
+	}
+	return writeBuffer(memory, encoded, keysPtr, keysLenPtr, bufSize)
+}
+
+// writeBuffer writes data to the buffer at ptr if it fits in bufSize, and its size to lenPtr
+func writeBuffer(memory api.Memory, data []byte, ptr, lenPtr, bufSize uint32) uint32 {
+	if uint64(len(data)) > uint64(^uint32(0)) {
+		return StatusValueTooLarge
+	}
+	size := uint32(len(data)) //nolint:gosec // checked above
+	if !memory.WriteUint32Le(lenPtr, size) {
+		return StatusMemoryError
+	}
+	if size > bufSize {
+		return StatusBufferTooSmall
+	}
+	if !memory.Write(ptr, data) {
+		return StatusMemoryError
+	}
+	return StatusSuccess
+}
+
+// statusFromError converts an error of the store to a result code
+func statusFromError(ctx context.Context, err error) uint32 {
+	switch {
+	case err == nil:
+		return StatusSuccess
+	case bacerrors.IsErrorWithCode(err, NotFound):
+		return StatusNotFound
+	case bacerrors.IsErrorWithCode(err, InvalidKey):
+		return StatusBadInput
+	case bacerrors.IsErrorWithCode(err, ValueTooLarge):
+		return StatusValueTooLarge
+	case bacerrors.IsErrorWithCode(err, QuotaExceeded):
+		return StatusQuotaExceeded
+	default:
+		log.Ctx(ctx).Warn().Err(err).Msg("WASM key-value store operation failed")
+		return StatusStorageError
+	}
+}
@@ -0,0 +1,157 @@
+//go:build unit || !integration
+
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// memoryModule is a WASM module exporting a memory of one page:
+//
+//	(module (memory (export "memory") 1))
+var memoryModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x05, 0x03, 0x01, 0x00, 0x01,
+	0x07, 0x0a, 0x01, 0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00,
+}
+
+// Offsets in the memory of the guest used by the tests
+const (
+	keyPtr    = 0
+	valuePtr  = 1024
+	lenPtr    = 8192
+	bufferPtr = 9000
+)
+
+// KVModuleSuite tests the host functions against the memory of a guest module
+type KVModuleSuite struct {
+	suite.Suite
+	ctx     context.Context
+	runtime wazero.Runtime
+	guest   api.Module
+	module  *module
+}
+
+func TestKVModuleSuite(t *testing.T) {
+	suite.Run(t, new(KVModuleSuite))
+}
+
+func (s *KVModuleSuite) SetupTest() {
+	s.ctx = context.Background()
+	store, err := NewStore(StoreParams{
+		Path:          filepath.Join(s.T().TempDir(), "kv.db"),
+		MaxBucketSize: 100,
+		MaxValueSize:  50,
+	})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = store.Close(s.ctx) })
+	s.module = &module{bucket: store.Bucket("job/1")}
+
+	s.runtime = wazero.NewRuntime(s.ctx)
+	s.T().Cleanup(func() { _ = s.runtime.Close(s.ctx) })
+	s.guest, err = s.runtime.Instantiate(s.ctx, memoryModule)
+	s.Require().NoError(err)
+}
+
+// write writes data to the memory of the guest, and returns its pointer and length
+func (s *KVModuleSuite) write(ptr uint32, data string) (uint32, uint32) {
+	s.Require().True(s.guest.Memory().Write(ptr, []byte(data)))
+	return ptr, uint32(len(data))
+}
+
+func (s *KVModuleSuite) put(key, value string) uint32 {
+	kPtr, kLen := s.write(keyPtr, key)
+	vPtr, vLen := s.write(valuePtr, value)
+	return s.module.put(s.ctx, s.guest, kPtr, kLen, vPtr, vLen)
+}
+
+// get calls kv_get with a buffer of the given size, and returns the status and the value
+func (s *KVModuleSuite) get(key string, bufSize uint32) (uint32, uint32, string) {
+	kPtr, kLen := s.write(keyPtr, key)
+	s.Require().True(s.guest.Memory().WriteUint32Le(lenPtr, bufSize))
+	status := s.module.get(s.ctx, s.guest, kPtr, kLen, bufferPtr, lenPtr)
+	size, ok := s.guest.Memory().ReadUint32Le(lenPtr)
+	s.Require().True(ok)
+	if status != StatusSuccess {
+		return status, size, ""
+	}
+	value, ok := s.guest.Memory().Read(bufferPtr, size)
+	s.Require().True(ok)
+	return status, size, string(value)
+}
+
+func (s *KVModuleSuite) TestPutGetDelete() {
+	status, _, _ := s.get("key", 64)
+	s.Equal(StatusNotFound, status)
+
+	s.Equal(StatusSuccess, s.put("key", "value"))
+	status, size, value := s.get("key", 64)
+	s.Equal(StatusSuccess, status)
+	s.Equal(uint32(5), size)
+	s.Equal("value", value)
+
+	kPtr, kLen := s.write(keyPtr, "key")
+	s.Equal(StatusSuccess, s.module.delete(s.ctx, s.guest, kPtr, kLen))
+	status, _, _ = s.get("key", 64)
+	s.Equal(StatusNotFound, status)
+}
+
+func (s *KVModuleSuite) TestGetWithSmallBuffer() {
+	s.Equal(StatusSuccess, s.put("key", "value"))
+	status, size, _ := s.get("key", 2)
+	s.Equal(StatusBufferTooSmall, status)
+	s.Equal(uint32(5), size, "the size of the value is returned to retry with a large enough buffer")
+}
+
+func (s *KVModuleSuite) TestList() {
+	for _, key := range []string{"b", "a/2", "a/1"} {
+		s.Equal(StatusSuccess, s.put(key, "v"))
+	}
+	pPtr, pLen := s.write(keyPtr, "a/")
+	s.Require().True(s.guest.Memory().WriteUint32Le(lenPtr, 64))
+	s.Equal(StatusSuccess, s.module.list(s.ctx, s.guest, pPtr, pLen, bufferPtr, lenPtr))
+
+	size, ok := s.guest.Memory().ReadUint32Le(lenPtr)
+	s.Require().True(ok)
+	encoded, ok := s.guest.Memory().Read(bufferPtr, size)
+	s.Require().True(ok)
+	var keys []string
+	for len(encoded) > 0 {
+		keyLen := binary.LittleEndian.Uint32(encoded)
+		keys = append(keys, string(encoded[4:4+keyLen]))
+		encoded = encoded[4+keyLen:]
+	}
+	s.Equal([]string{"a/1", "a/2"}, keys)
+}
+
+func (s *KVModuleSuite) TestErrors() {
+	s.Equal(StatusBadInput, s.put("", "value"))
+	s.Equal(StatusValueTooLarge, s.put("key", string(make([]byte, 51))))
+	s.Equal(StatusSuccess, s.put("k1", string(make([]byte, 48))))
+	s.Equal(StatusQuotaExceeded, s.put("k2", string(make([]byte, 50))))
+
+	// pointers outside of the memory of the guest
+	outOfBounds := uint32(2 * 65536)
+	s.Equal(StatusMemoryError, s.module.put(s.ctx, s.guest, outOfBounds, 3, valuePtr, 1))
+	s.Equal(StatusMemoryError, s.module.get(s.ctx, s.guest, keyPtr, 3, bufferPtr, outOfBounds))
+}
+
+func (s *KVModuleSuite) TestInstantiateModule() {
+	s.Require().NoError(InstantiateModule(s.ctx, s.runtime, Params{Bucket: s.module.bucket}))
+	module := s.runtime.Module(ModuleName)
+	s.Require().NotNil(module)
+	s.Len(module.ExportedFunctionDefinitions(), 4)
+
+	// no functions are registered without a bucket
+	runtime := wazero.NewRuntime(s.ctx)
+	defer runtime.Close(s.ctx)
+	s.Require().NoError(InstantiateModule(s.ctx, runtime, Params{}))
+	s.Nil(runtime.Module(ModuleName))
+}
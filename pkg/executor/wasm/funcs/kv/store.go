@@ -0,0 +1,208 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/boltdblib"
+)
+
+const (
+	// bucketsBucket holds a nested bucket with the keys and values of each bucket
+	bucketsBucket = "buckets"
+	// sizesBucket holds the total size of the keys and values of each bucket, to enforce quotas
+	sizesBucket = "sizes"
+
+	// MaxKeySize is the maximum size of a key
+	MaxKeySize = 1024
+)
+
+// StoreParams configures a Store
+type StoreParams struct {
+	// Path is the path of the BoltDB database
+	Path string
+	// MaxBucketSize is the maximum total size in bytes of the keys and values of a bucket.
+	// Zero means no limit.
+	MaxBucketSize uint64
+	// MaxValueSize is the maximum size in bytes of a value. Zero means no limit.
+	MaxValueSize uint64
+}
+
+// Store is a key-value store backed by BoltDB, partitioned in buckets that are
+// each used by the executions of a job or of a namespace.
+//
+// The database has two buckets:
+// - `buckets` holds a nested bucket per bucket of the store, with its keys and values
+// - `sizes` holds the total size of the keys and values of each bucket of the store
+type Store struct {
+	database      *bolt.DB
+	maxBucketSize uint64
+	maxValueSize  uint64
+}
+
+// NewStore creates a store backed by the BoltDB database at the given path
+func NewStore(params StoreParams) (*Store, error) {
+	database, err := boltdblib.Open(params.Path)
+	if err != nil {
+		return nil, err
+	}
+	err = database.Update(func(tx *bolt.Tx) error {
+		for _, name := range []string{bucketsBucket, sizesBucket} {
+			if _, err = tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return fmt.Errorf("error creating bucket %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = database.Close()
+		return nil, err
+	}
+	return &Store{
+		database:      database,
+		maxBucketSize: params.MaxBucketSize,
+		maxValueSize:  params.MaxValueSize,
+	}, nil
+}
+
+// Bucket returns the bucket with the given name, which is created when a value is first put in it
+func (s *Store) Bucket(name string) *Bucket {
+	return &Bucket{store: s, name: []byte(name)}
+}
+
+// Close closes the database
+func (s *Store) Close(ctx context.Context) error {
+	return s.database.Close()
+}
+
+// Bucket is a named partition of a Store
+type Bucket struct {
+	store *Store
+	name  []byte
+}
+
+// Get returns the value of the key, or an error with the NotFound code if it is not set
+func (b *Bucket) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	var value []byte
+	err := boltdblib.View(ctx, b.store.database, func(tx *bolt.Tx) error {
+		bucket := b.bucket(tx)
+		if bucket == nil {
+			return NewNotFoundError(key)
+		}
+		v := bucket.Get([]byte(key))
+		if v == nil {
+			return NewNotFoundError(key)
+		}
+		// values are only valid within the transaction
+		value = bytes.Clone(v)
+		return nil
+	})
+	return value, err
+}
+
+// Put sets the value of the key, unless it exceeds the quotas of the store
+func (b *Bucket) Put(ctx context.Context, key string, value []byte) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if b.store.maxValueSize > 0 && uint64(len(value)) > b.store.maxValueSize {
+		return NewValueTooLargeError(key, len(value), b.store.maxValueSize)
+	}
+	return boltdblib.Update(ctx, b.store.database, func(tx *bolt.Tx) error {
+		bucket, err := tx.Bucket([]byte(bucketsBucket)).CreateBucketIfNotExists(b.name)
+		if err != nil {
+			return err
+		}
+		size := b.size(tx)
+		if previous := bucket.Get([]byte(key)); previous != nil {
+			size -= uint64(len(key) + len(previous))
+		}
+		size += uint64(len(key) + len(value))
+		if b.store.maxBucketSize > 0 && size > b.store.maxBucketSize {
+			return NewQuotaExceededError(string(b.name), b.store.maxBucketSize)
+		}
+		if err = bucket.Put([]byte(key), value); err != nil {
+			return err
+		}
+		return b.setSize(tx, size)
+	})
+}
+
+// Delete deletes the key, if it is set
+func (b *Bucket) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	return boltdblib.Update(ctx, b.store.database, func(tx *bolt.Tx) error {
+		bucket := b.bucket(tx)
+		if bucket == nil {
+			return nil
+		}
+		previous := bucket.Get([]byte(key))
+		if previous == nil {
+			return nil
+		}
+		size := b.size(tx) - uint64(len(key)+len(previous))
+		if err := bucket.Delete([]byte(key)); err != nil {
+			return err
+		}
+		return b.setSize(tx, size)
+	})
+}
+
+// List returns the keys starting with the prefix, in lexicographical order
+func (b *Bucket) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := boltdblib.View(ctx, b.store.database, func(tx *bolt.Tx) error {
+		bucket := b.bucket(tx)
+		if bucket == nil {
+			return nil
+		}
+		cursor := bucket.Cursor()
+		for k, _ := cursor.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, _ = cursor.Next() {
+			keys = append(keys, string(k))
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// Size returns the total size in bytes of the keys and values of the bucket
+func (b *Bucket) Size(ctx context.Context) (uint64, error) {
+	var size uint64
+	err := boltdblib.View(ctx, b.store.database, func(tx *bolt.Tx) error {
+		size = b.size(tx)
+		return nil
+	})
+	return size, err
+}
+
+func (b *Bucket) bucket(tx *bolt.Tx) *bolt.Bucket {
+	return tx.Bucket([]byte(bucketsBucket)).Bucket(b.name)
+}
+
+func (b *Bucket) size(tx *bolt.Tx) uint64 {
+	data := tx.Bucket([]byte(sizesBucket)).Get(b.name)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+func (b *Bucket) setSize(tx *bolt.Tx, size uint64) error {
+	return tx.Bucket([]byte(sizesBucket)).Put(b.name, binary.BigEndian.AppendUint64(nil, size))
+}
+
+func validateKey(key string) error {
+	if key == "" || len(key) > MaxKeySize {
+		return NewInvalidKeyError(key)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+//go:build unit || !integration
+
+package kv
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+)
+
+type StoreSuite struct {
+	suite.Suite
+	ctx  context.Context
+	path string
+}
+
+func TestStoreSuite(t *testing.T) {
+	suite.Run(t, new(StoreSuite))
+}
+
+func (s *StoreSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.path = filepath.Join(s.T().TempDir(), "kv.db")
+}
+
+func (s *StoreSuite) newStore(params StoreParams) *Store {
+	params.Path = s.path
+	store, err := NewStore(params)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = store.Close(s.ctx) })
+	return store
+}
+
+func (s *StoreSuite) TestPutGetDelete() {
+	bucket := s.newStore(StoreParams{}).Bucket("job/1")
+
+	_, err := bucket.Get(s.ctx, "key")
+	s.True(bacerrors.IsErrorWithCode(err, NotFound))
+
+	s.Require().NoError(bucket.Put(s.ctx, "key", []byte("value")))
+	value, err := bucket.Get(s.ctx, "key")
+	s.Require().NoError(err)
+	s.Equal([]byte("value"), value)
+
+	s.Require().NoError(bucket.Put(s.ctx, "key", []byte("new value")))
+	value, err = bucket.Get(s.ctx, "key")
+	s.Require().NoError(err)
+	s.Equal([]byte("new value"), value)
+
+	s.Require().NoError(bucket.Delete(s.ctx, "key"))
+	s.Require().NoError(bucket.Delete(s.ctx, "key"))
+	_, err = bucket.Get(s.ctx, "key")
+	s.True(bacerrors.IsErrorWithCode(err, NotFound))
+}
+
+func (s *StoreSuite) TestBucketsAreIsolated() {
+	store := s.newStore(StoreParams{})
+	s.Require().NoError(store.Bucket("job/1").Put(s.ctx, "key", []byte("1")))
+	s.Require().NoError(store.Bucket("job/2").Put(s.ctx, "key", []byte("2")))
+
+	value, err := store.Bucket("job/1").Get(s.ctx, "key")
+	s.Require().NoError(err)
+	s.Equal([]byte("1"), value)
+	_, err = store.Bucket("namespace/default").Get(s.ctx, "key")
+	s.True(bacerrors.IsErrorWithCode(err, NotFound))
+}
+
+func (s *StoreSuite) TestList() {
+	bucket := s.newStore(StoreParams{}).Bucket("job/1")
+	keys, err := bucket.List(s.ctx, "")
+	s.Require().NoError(err)
+	s.Empty(keys)
+
+	for _, key := range []string{"b/2", "a", "b/1", "c"} {
+		s.Require().NoError(bucket.Put(s.ctx, key, []byte(key)))
+	}
+	keys, err = bucket.List(s.ctx, "")
+	s.Require().NoError(err)
+	s.Equal([]string{"a", "b/1", "b/2", "c"}, keys)
+
+	keys, err = bucket.List(s.ctx, "b/")
+	s.Require().NoError(err)
+	s.Equal([]string{"b/1", "b/2"}, keys)
+}
+
+func (s *StoreSuite) TestInvalidKeys() {
+	bucket := s.newStore(StoreParams{}).Bucket("job/1")
+	for _, key := range []string{"", strings.Repeat("k", MaxKeySize+1)} {
+		s.True(bacerrors.IsErrorWithCode(bucket.Put(s.ctx, key, nil), InvalidKey))
+		_, err := bucket.Get(s.ctx, key)
+		s.True(bacerrors.IsErrorWithCode(err, InvalidKey))
+		s.True(bacerrors.IsErrorWithCode(bucket.Delete(s.ctx, key), InvalidKey))
+	}
+}
+
+func (s *StoreSuite) TestQuotas() {
+	store := s.newStore(StoreParams{MaxBucketSize: 20, MaxValueSize: 10})
+	bucket := store.Bucket("job/1")
+
+	err := bucket.Put(s.ctx, "key", make([]byte, 11))
+	s.True(bacerrors.IsErrorWithCode(err, ValueTooLarge))
+
+	s.Require().NoError(bucket.Put(s.ctx, "k1", make([]byte, 8)))
+	s.Require().NoError(bucket.Put(s.ctx, "k2", make([]byte, 8)))
+	size, err := bucket.Size(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(uint64(20), size)
+
+	err = bucket.Put(s.ctx, "k3", nil)
+	s.True(bacerrors.IsErrorWithCode(err, QuotaExceeded))
+
+	// replacing a value only counts the difference
+	s.Require().NoError(bucket.Put(s.ctx, "k2", make([]byte, 4)))
+	s.Require().NoError(bucket.Put(s.ctx, "k3", make([]byte, 2)))
+
+	// deleting frees space, and the quota is per bucket
+	s.Require().NoError(bucket.Delete(s.ctx, "k1"))
+	size, err = bucket.Size(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(uint64(10), size)
+	s.Require().NoError(store.Bucket("job/2").Put(s.ctx, "k1", make([]byte, 10)))
+}
+
+func (s *StoreSuite) TestPersistsAcrossRestarts() {
+	store := s.newStore(StoreParams{})
+	s.Require().NoError(store.Bucket("job/1").Put(s.ctx, "key", []byte("value")))
+	s.Require().NoError(store.Close(s.ctx))
+
+	restarted := s.newStore(StoreParams{})
+	value, err := restarted.Bucket("job/1").Get(s.ctx, "key")
+	s.Require().NoError(err)
+	s.Equal([]byte("value"), value)
+	size, err := restarted.Bucket("job/1").Size(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(uint64(len("key")+len("value")), size)
+}
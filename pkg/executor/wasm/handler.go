@@ -21,6 +21,8 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	"github.com/bacalhau-project/bacalhau/pkg/executor/wasm/funcs/http"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/wasm/funcs/kv"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/wasm/metering"
 	wasmmodels "github.com/bacalhau-project/bacalhau/pkg/executor/wasm/models"
 	wasmlogs "github.com/bacalhau-project/bacalhau/pkg/executor/wasm/util/logger"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -35,10 +37,16 @@ import (
 type executionHandler struct {
 	// runtime configured with resource-limits
 	runtime wazero.Runtime
+	// compilationCache caches compiled modules across executions, and is nil if disabled
+	compilationCache *CompilationCache
+	// keyValueStore backs the key-value host functions, and is nil if disabled
+	keyValueStore *kv.Store
 	// spec contains the WASM engine specification
 	spec wasmmodels.EngineSpec
 	// virtual filesystem exposed to wasm module
 	fs fs.FS
+	// meter tracks the instructions run by the modules, and is nil if they are not limited
+	meter *metering.Meter
 
 	// request contains all the information needed for execution
 	request *executor.RunCommandRequest
@@ -64,6 +72,8 @@ func newExecutionHandler(
 	ctx context.Context,
 	request *executor.RunCommandRequest,
 	runtime wazero.Runtime,
+	compilationCache *CompilationCache,
+	keyValueStore *kv.Store,
 	fs fs.FS,
 ) (*executionHandler, error) {
 	// Decode WASM engine spec
@@ -71,6 +81,9 @@ func newExecutionHandler(
 	if err != nil {
 		return nil, NewSpecError(err)
 	}
+	if err = validateBinaries(fs, wasmSpec); err != nil {
+		return nil, err
+	}
 
 	// Create a new log manager and obtain writers for WASM configuration
 	wasmLogs, err := wasmlogs.NewLogManager(ctx, request.ExecutionID)
@@ -79,9 +92,11 @@ func newExecutionHandler(
 	}
 
 	return &executionHandler{
-		runtime: runtime,
-		spec:    wasmSpec,
-		fs:      fs,
+		runtime:          runtime,
+		compilationCache: compilationCache,
+		keyValueStore:    keyValueStore,
+		spec:             wasmSpec,
+		fs:               fs,
 
 		request: request,
 
@@ -196,7 +211,18 @@ func (h *executionHandler) createModuleConfig(stdout, stderr io.Writer) wazero.M
 // loadModules loads and instantiates all required WASM modules
 func (h *executionHandler) loadModules(ctx context.Context, engine tracedRuntime, config wazero.ModuleConfig) (api.Module, error) {
 	h.logger.Info().Msg("instantiating wasm modules")
-	loader := NewModuleLoader(engine, config, h.fs)
+	loader := NewModuleLoader(engine, config, h.fs).WithCompilationCache(h.compilationCache)
+
+	// Instantiate the metering module first, so that all modules consume its fuel
+	if h.spec.InstructionLimit > 0 {
+		meter, err := metering.InstantiateModule(ctx, engine.Runtime, h.spec.InstructionLimit)
+		if err != nil {
+			h.result = executor.NewFailedResult(fmt.Sprintf("failed to load metering module: %s", err))
+			return nil, err
+		}
+		h.meter = meter
+		loader = loader.WithMetering(true)
+	}
 
 	// in wasm, if network type is undefined, we default to host
 	if h.request.Network == nil || h.request.Network.Type == models.NetworkDefault {
@@ -217,10 +243,21 @@ func (h *executionHandler) loadModules(ctx context.Context, engine tracedRuntime
 		}
 	}
 
+	// Load key-value module if the node provides a store
+	if h.keyValueStore != nil {
+		kvParams := kv.Params{
+			Bucket: h.keyValueStore.Bucket(h.keyValueBucket()),
+		}
+		if err := kv.InstantiateModule(ctx, engine.Runtime, kvParams); err != nil {
+			h.result = executor.NewFailedResult(fmt.Sprintf("failed to load key-value module: %s", err))
+			return nil, err
+		}
+	}
+
 	// Load import modules first
 	for _, importModule := range h.spec.ImportModules {
 		if _, err := loader.InstantiateModule(ctx, importModule); err != nil {
-			h.result = executor.NewFailedResult(fmt.Sprintf("failed to load import module %s: %s", importModule, err))
+			h.result = h.loadFailedResult(fmt.Sprintf("failed to load import module %s: %s", importModule, err))
 			return nil, err
 		}
 	}
@@ -228,7 +265,7 @@ func (h *executionHandler) loadModules(ctx context.Context, engine tracedRuntime
 	// Load and instantiate the entry module
 	instance, err := loader.InstantiateModule(ctx, h.spec.EntryModule)
 	if err != nil {
-		h.result = executor.NewFailedResult(fmt.Sprintf("failed to load entry module %s: %s", h.spec.EntryModule, err))
+		h.result = h.loadFailedResult(fmt.Sprintf("failed to load entry module %s: %s", h.spec.EntryModule, err))
 		return nil, err
 	}
 
@@ -240,6 +277,25 @@ func (h *executionHandler) loadModules(ctx context.Context, engine tracedRuntime
 	return instance, nil
 }
 
+// keyValueBucket returns the name of the key-value bucket of the execution, depending on its scope
+func (h *executionHandler) keyValueBucket() string {
+	if h.spec.KeyValueScope == wasmmodels.KeyValueScopeNamespace {
+		return wasmmodels.KeyValueScopeNamespace + "/" + h.request.Namespace
+	}
+	return wasmmodels.KeyValueScopeJob + "/" + h.request.JobID
+}
+
+// loadFailedResult returns the result of an execution whose modules failed to load,
+// which is due to the instruction limit if start functions exhausted it
+func (h *executionHandler) loadFailedResult(reason string) *models.RunCommandResult {
+	if h.meter == nil || !h.meter.Exhausted() {
+		return executor.NewFailedResult(reason)
+	}
+	result := executor.NewFailedResult(NewInstructionLimitError(h.meter.Limit()).Error())
+	result.InstructionsUsed = h.meter.Used()
+	return result
+}
+
 // verifyEntryPoint checks if the specified entry point exists in the module
 func (h *executionHandler) verifyEntryPoint(instance api.Module) error {
 	definitions := instance.ExportedFunctionDefinitions()
@@ -284,7 +340,9 @@ func (h *executionHandler) executeMainFunction(ctx context.Context, instance api
 	// the exit code for inclusion in the job output, and ignore the return code
 	// from the function (most WASI compilers will not give one). Some compilers
 	// though do not set an exit code, so we use a default of -1.
+	started := time.Now()
 	_, wasmErr := entryFunc.Call(ctx)
+	wall := time.Since(started)
 	exitCode := int64(-1)
 	var errExit *sys.ExitError
 	if errors.As(wasmErr, &errExit) {
@@ -292,6 +350,10 @@ func (h *executionHandler) executeMainFunction(ctx context.Context, instance api
 		wasmErr = nil
 		h.logger.Info().Int64("exit_code", exitCode).Msg("execution ended")
 	}
+	if h.meter != nil && h.meter.Exhausted() {
+		// the module trapped when running out of fuel
+		wasmErr = NewInstructionLimitError(h.meter.Limit())
+	}
 	if wasmErr != nil {
 		// in the event that an error is returned without an exist code we'll assume the operation
 		// failed and set the exit code to 1
@@ -306,6 +368,25 @@ func (h *executionHandler) executeMainFunction(ctx context.Context, instance api
 	stdoutReader, stderrReader := h.logManager.GetDefaultReaders(false)
 	executionResultsDir := compute.ExecutionResultsDir(h.request.ExecutionDir)
 	h.result = executor.WriteJobResults(executionResultsDir, stdoutReader, stderrReader, int(exitCode), wasmErr, h.request.OutputLimits)
+	if h.meter != nil {
+		h.result.InstructionsUsed = h.meter.Used()
+	}
+	// the CPU time is left unset, as the CPU time of the goroutine running the module can't be measured,
+	// and the time it ran for includes the time it waited for its host functions and to be scheduled
+	h.result.ResourceUsage = &models.ResourceUsage{
+		WallSeconds: wall.Seconds(),
+		PeakMemory:  memorySize(instance),
+	}
+}
+
+// memorySize returns the size of the linear memory of a module in bytes. Linear memory can only grow,
+// so its size once the module returned is the peak memory it used.
+func memorySize(instance api.Module) uint64 {
+	memory := instance.Memory()
+	if memory == nil {
+		return 0
+	}
+	return uint64(memory.Size())
 }
 
 // active returns whether the execution is currently running
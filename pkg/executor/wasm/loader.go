@@ -11,6 +11,8 @@ import (
 	"github.com/tetratelabs/wazero/api"
 	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
 	"go.ptx.dk/multierrgroup"
+
+	"github.com/bacalhau-project/bacalhau/pkg/executor/wasm/metering"
 )
 
 // ModuleLoader handles the loading and instantiation of WebAssembly modules.
@@ -41,6 +43,10 @@ type ModuleLoader struct {
 	config wazero.ModuleConfig
 	// fs is the filesystem where modules are mounted
 	fs fs.FS
+	// compilationCache caches compiled modules across executions, and is nil if disabled
+	compilationCache *CompilationCache
+	// metered is whether modules are instrumented to consume the fuel of the metering module
+	metered bool
 
 	// mtx ensures thread-safe module instantiation
 	// The runtime will throw an error if the same module is instantiated more than once
@@ -57,6 +63,20 @@ func NewModuleLoader(runtime wazero.Runtime, config wazero.ModuleConfig, fs fs.F
 	}
 }
 
+// WithCompilationCache sets the cache used to compile modules, which the runtime
+// of the loader must be configured with. A nil cache compiles modules from scratch.
+func (loader *ModuleLoader) WithCompilationCache(cache *CompilationCache) *ModuleLoader {
+	loader.compilationCache = cache
+	return loader
+}
+
+// WithMetering instruments the loaded modules to consume the fuel of the metering
+// module, which must be instantiated in the runtime of the loader.
+func (loader *ModuleLoader) WithMetering(metered bool) *ModuleLoader {
+	loader.metered = metered
+	return loader
+}
+
 // InstantiateModule loads and instantiates the module at the given path and all of
 // its dependencies. It looks in the provided filesystem for modules.
 //
@@ -187,14 +207,25 @@ func (loader *ModuleLoader) resolveModulePath(path string) (string, error) {
 }
 
 // loadFile compiles a WASM module from the given path in the mounted filesystem.
-// It reads the module bytes and compiles them for execution.
+// It reads the module bytes, instruments them if metered, and compiles them for execution,
+// through the compilation cache if any.
 func (loader *ModuleLoader) loadFile(ctx context.Context, path string) (wazero.CompiledModule, error) {
 	bytes, err := fs.ReadFile(loader.fs, path)
 	if err != nil {
 		return nil, NewModuleLoadError(path, err)
 	}
+	if loader.metered {
+		if bytes, err = metering.Instrument(bytes); err != nil {
+			return nil, NewModuleCompileError(path, err)
+		}
+	}
 
-	module, err := loader.runtime.CompileModule(ctx, bytes)
+	var module wazero.CompiledModule
+	if loader.compilationCache != nil {
+		module, err = loader.compilationCache.CompileModule(ctx, loader.runtime, bytes)
+	} else {
+		module, err = loader.runtime.CompileModule(ctx, bytes)
+	}
 	if err != nil {
 		return nil, NewModuleCompileError(path, err)
 	}
@@ -0,0 +1,778 @@
+package metering
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WASM binary format constants used by the instrumentation.
+// See https://webassembly.github.io/spec/core/binary/index.html
+const (
+	sectionCustom = 0
+	sectionType   = 1
+	sectionImport = 2
+	sectionGlobal = 6
+	sectionExport = 7
+	sectionElem   = 9
+	sectionCode   = 10
+	sectionData   = 11
+
+	externFunc   = 0x00
+	externTable  = 0x01
+	externMemory = 0x02
+	externGlobal = 0x03
+	externTag    = 0x04
+
+	valueTypeI64 = 0x7e
+	mutable      = 0x01
+	blockTypeNil = 0x40
+
+	opUnreachable = 0x00
+	opBlock       = 0x02
+	opLoop        = 0x03
+	opIf          = 0x04
+	opElse        = 0x05
+	opEnd         = 0x0b
+	opGlobalGet   = 0x23
+	opGlobalSet   = 0x24
+	opI64Const    = 0x42
+	opI64LtS      = 0x53
+	opI64Sub      = 0x7d
+	opMiscPrefix  = 0xfc
+	opSIMDPrefix  = 0xfd
+	opAtomicPref  = 0xfe
+)
+
+var wasmHeader = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+// errUnexpectedEnd is returned when a binary ends in the middle of a construct
+var errUnexpectedEnd = errors.New("unexpected end of binary")
+
+// Instrument rewrites a WASM binary so that it consumes fuel as it runs.
+//
+// The instrumented module imports the mutable i64 global FuelGlobalName from the module ModuleName.
+// The code of each function is split into straight-line segments, which start at the beginning
+// of the function and after each loop, if, else and end instruction. Each segment is prefixed with
+// code subtracting the number of instructions of the segment from the fuel, and trapping when it
+// drops below zero. A branch leaving a segment early is still charged for the whole segment, so
+// the fuel consumed is an upper bound of the number of instructions that actually ran.
+//
+// Importing the global shifts the indices of the globals defined by the module, which are
+// rewritten everywhere they are referenced. DWARF sections are dropped, as the offsets they
+// refer to are invalidated by the instrumentation.
+func Instrument(source []byte) ([]byte, error) {
+	if !bytes.HasPrefix(source, wasmHeader) {
+		return nil, errors.New("invalid WASM binary header")
+	}
+	sections, err := readSections(source[len(wasmHeader):])
+	if err != nil {
+		return nil, err
+	}
+
+	// the imported fuel global comes after the globals already imported by the module
+	var fuelGlobal uint32
+	importIdx := -1
+	for i, s := range sections {
+		if s.id == sectionImport {
+			importIdx = i
+			if fuelGlobal, err = countImportedGlobals(s.payload); err != nil {
+				return nil, fmt.Errorf("invalid import section: %w", err)
+			}
+		}
+	}
+	ins := &instrumenter{fuelGlobal: fuelGlobal}
+
+	out := append([]byte{}, wasmHeader...)
+	if importIdx < 0 {
+		importIdx = importSectionPosition(sections)
+		sections = append(sections[:importIdx], append([]section{{id: sectionImport, payload: []byte{0}}}, sections[importIdx:]...)...)
+	}
+	for _, s := range sections {
+		payload := s.payload
+		switch s.id {
+		case sectionCustom:
+			if isDebugSection(payload) {
+				continue
+			}
+		case sectionImport:
+			payload, err = addFuelImport(payload)
+		case sectionGlobal:
+			payload, err = ins.rewriteGlobals(payload)
+		case sectionExport:
+			payload, err = ins.rewriteExports(payload)
+		case sectionElem:
+			payload, err = ins.rewriteElements(payload)
+		case sectionCode:
+			payload, err = ins.rewriteCode(payload)
+		case sectionData:
+			payload, err = ins.rewriteData(payload)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to instrument section %d: %w", s.id, err)
+		}
+		out = append(out, s.id)
+		out = binary.AppendUvarint(out, uint64(len(payload)))
+		out = append(out, payload...)
+	}
+	return out, nil
+}
+
+// section is a section of a WASM binary
+type section struct {
+	id      byte
+	payload []byte
+}
+
+func readSections(data []byte) ([]section, error) {
+	r := &reader{data: data}
+	var sections []section
+	for !r.done() {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := r.bytes(int(size))
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, section{id: id, payload: payload})
+	}
+	return sections, nil
+}
+
+// importSectionPosition returns where an import section must be inserted in sections,
+// which is after the custom and type sections that precede the other sections
+func importSectionPosition(sections []section) int {
+	for i, s := range sections {
+		if s.id != sectionCustom && s.id != sectionType {
+			return i
+		}
+	}
+	return len(sections)
+}
+
+// isDebugSection returns whether the custom section holds DWARF debug information
+func isDebugSection(payload []byte) bool {
+	r := &reader{data: payload}
+	name, err := r.name()
+	return err == nil && strings.HasPrefix(name, ".debug_")
+}
+
+func countImportedGlobals(payload []byte) (uint32, error) {
+	r := &reader{data: payload}
+	count, err := r.u32()
+	if err != nil {
+		return 0, err
+	}
+	var globals uint32
+	for i := uint32(0); i < count; i++ {
+		if _, err = r.name(); err != nil {
+			return 0, err
+		}
+		if _, err = r.name(); err != nil {
+			return 0, err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		switch kind {
+		case externFunc:
+			err = r.skipLEB()
+		case externTable:
+			if _, err = r.byte(); err == nil {
+				err = r.skipLimits()
+			}
+		case externMemory:
+			err = r.skipLimits()
+		case externGlobal:
+			globals++
+			_, err = r.bytes(2)
+		case externTag:
+			if _, err = r.byte(); err == nil {
+				err = r.skipLEB()
+			}
+		default:
+			err = fmt.Errorf("unsupported import kind 0x%x", kind)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return globals, nil
+}
+
+// addFuelImport appends the import of the fuel global to the import section
+func addFuelImport(payload []byte) ([]byte, error) {
+	r := &reader{data: payload}
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	out := binary.AppendUvarint(nil, uint64(count)+1)
+	out = append(out, r.data[r.pos:]...)
+	out = appendName(out, ModuleName)
+	out = appendName(out, FuelGlobalName)
+	return append(out, externGlobal, valueTypeI64, mutable), nil
+}
+
+// instrumenter rewrites the sections of a module importing the fuel global
+type instrumenter struct {
+	// fuelGlobal is the index of the imported fuel global. The globals defined
+	// by the module originally started at this index, and are shifted by one.
+	fuelGlobal uint32
+}
+
+func (ins *instrumenter) globalIndex(idx uint32) uint32 {
+	if idx >= ins.fuelGlobal {
+		return idx + 1
+	}
+	return idx
+}
+
+func (ins *instrumenter) rewriteGlobals(payload []byte) ([]byte, error) {
+	r := &reader{data: payload}
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	out := binary.AppendUvarint(nil, uint64(count))
+	for i := uint32(0); i < count; i++ {
+		globalType, err := r.bytes(2)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, globalType...)
+		if out, err = ins.rewriteExpr(r, out, false); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (ins *instrumenter) rewriteExports(payload []byte) ([]byte, error) {
+	r := &reader{data: payload}
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	out := binary.AppendUvarint(nil, uint64(count))
+	for i := uint32(0); i < count; i++ {
+		name, err := r.name()
+		if err != nil {
+			return nil, err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		idx, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		if kind == externGlobal {
+			idx = ins.globalIndex(idx)
+		}
+		out = appendName(out, name)
+		out = append(out, kind)
+		out = binary.AppendUvarint(out, uint64(idx))
+	}
+	return out, nil
+}
+
+//nolint:gocyclo // follows the encodings of element segments
+func (ins *instrumenter) rewriteElements(payload []byte) ([]byte, error) {
+	r := &reader{data: payload}
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	out := binary.AppendUvarint(nil, uint64(count))
+	for i := uint32(0); i < count; i++ {
+		flags, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		out = binary.AppendUvarint(out, uint64(flags))
+		if flags > 7 {
+			return nil, fmt.Errorf("unsupported element segment flags %d", flags)
+		}
+		// bit 0: passive or declarative, bit 1: explicit table index, or declarative if passive,
+		// bit 2: elements are expressions instead of function indices
+		active := flags&1 == 0
+		start := r.pos
+		if active && flags&2 != 0 {
+			if err = r.skipLEB(); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, r.data[start:r.pos]...)
+		if active {
+			if out, err = ins.rewriteExpr(r, out, false); err != nil {
+				return nil, err
+			}
+		}
+		if flags&3 != 0 {
+			// element kind or reference type
+			kind, err := r.byte()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, kind)
+		}
+		elements, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		out = binary.AppendUvarint(out, uint64(elements))
+		for j := uint32(0); j < elements; j++ {
+			if flags&4 != 0 {
+				out, err = ins.rewriteExpr(r, out, false)
+			} else {
+				start = r.pos
+				if err = r.skipLEB(); err == nil {
+					out = append(out, r.data[start:r.pos]...)
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return out, nil
+}
+
+func (ins *instrumenter) rewriteData(payload []byte) ([]byte, error) {
+	r := &reader{data: payload}
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	out := binary.AppendUvarint(nil, uint64(count))
+	for i := uint32(0); i < count; i++ {
+		flags, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		out = binary.AppendUvarint(out, uint64(flags))
+		switch flags {
+		case 0:
+			out, err = ins.rewriteExpr(r, out, false)
+		case 1:
+		case 2:
+			var memory uint32
+			if memory, err = r.u32(); err != nil {
+				return nil, err
+			}
+			out = binary.AppendUvarint(out, uint64(memory))
+			out, err = ins.rewriteExpr(r, out, false)
+		default:
+			err = fmt.Errorf("unsupported data segment flags %d", flags)
+		}
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		init, err := r.bytes(int(size))
+		if err != nil {
+			return nil, err
+		}
+		out = binary.AppendUvarint(out, uint64(size))
+		out = append(out, init...)
+	}
+	return out, nil
+}
+
+func (ins *instrumenter) rewriteCode(payload []byte) ([]byte, error) {
+	r := &reader{data: payload}
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	out := binary.AppendUvarint(nil, uint64(count))
+	for i := uint32(0); i < count; i++ {
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		code, err := r.bytes(int(size))
+		if err != nil {
+			return nil, err
+		}
+		body, err := ins.rewriteFunction(code)
+		if err != nil {
+			return nil, fmt.Errorf("function %d: %w", i, err)
+		}
+		out = binary.AppendUvarint(out, uint64(len(body)))
+		out = append(out, body...)
+	}
+	return out, nil
+}
+
+// rewriteFunction meters the body of a function, which is its local declarations followed by its code
+func (ins *instrumenter) rewriteFunction(code []byte) ([]byte, error) {
+	r := &reader{data: code}
+	locals, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < locals; i++ {
+		if err = r.skipLEB(); err != nil {
+			return nil, err
+		}
+		if err = r.skipValueType(); err != nil {
+			return nil, err
+		}
+	}
+	out := append([]byte{}, code[:r.pos]...)
+	if out, err = ins.rewriteExpr(r, out, true); err != nil {
+		return nil, err
+	}
+	if !r.done() {
+		return nil, errors.New("unexpected data after the end of the function")
+	}
+	return out, nil
+}
+
+// rewriteExpr appends to out the instructions of r up to the end of the current expression,
+// with the indices of globals shifted. If metered, the instructions are charged for.
+func (ins *instrumenter) rewriteExpr(r *reader, out []byte, metered bool) ([]byte, error) {
+	var segment []byte
+	var cost int64
+	depth := 1
+	for depth > 0 {
+		start := r.pos
+		op, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		boundary := false
+		switch op {
+		case opBlock, opLoop, opIf:
+			depth++
+			boundary = op != opBlock
+			err = r.skipLEB() // block type
+		case opElse:
+			boundary = true
+		case opEnd:
+			depth--
+			boundary = true
+		case opGlobalGet, opGlobalSet:
+			var idx uint32
+			if idx, err = r.u32(); err != nil {
+				return nil, err
+			}
+			segment = append(segment, op)
+			segment = binary.AppendUvarint(segment, uint64(ins.globalIndex(idx)))
+			cost++
+			continue
+		default:
+			err = skipImmediates(r, op)
+		}
+		if err != nil {
+			return nil, err
+		}
+		segment = append(segment, r.data[start:r.pos]...)
+		// else and end only delimit blocks, and are free
+		if op != opElse && op != opEnd {
+			cost++
+		}
+		if boundary {
+			if metered && cost > 0 {
+				out = ins.appendCharge(out, cost)
+			}
+			out = append(out, segment...)
+			segment, cost = segment[:0], 0
+		}
+	}
+	return out, nil
+}
+
+// appendCharge appends the code subtracting cost from the fuel, and trapping if it is exhausted:
+//
+//	global.get $fuel
+//	i64.const cost
+//	i64.sub
+//	global.set $fuel
+//	global.get $fuel
+//	i64.const 0
+//	i64.lt_s
+//	if
+//	  unreachable
+//	end
+func (ins *instrumenter) appendCharge(out []byte, cost int64) []byte {
+	out = append(out, opGlobalGet)
+	out = binary.AppendUvarint(out, uint64(ins.fuelGlobal))
+	out = append(out, opI64Const)
+	out = appendSLEB(out, cost)
+	out = append(out, opI64Sub, opGlobalSet)
+	out = binary.AppendUvarint(out, uint64(ins.fuelGlobal))
+	out = append(out, opGlobalGet)
+	out = binary.AppendUvarint(out, uint64(ins.fuelGlobal))
+	return append(out, opI64Const, 0, opI64LtS, opIf, blockTypeNil, opUnreachable, opEnd)
+}
+
+// skipImmediates skips the immediate arguments of an instruction, other than the
+// block and global instructions handled by rewriteExpr
+//
+//nolint:gocyclo // follows the encodings of instructions
+func skipImmediates(r *reader, op byte) error {
+	switch {
+	case op == 0x00 || op == 0x01 || op == 0x0f || op == 0x1a || op == 0x1b || op == 0xd1:
+		// unreachable, nop, return, drop, select, ref.is_null
+		return nil
+	case op >= 0x45 && op <= 0xc4:
+		// numeric instructions
+		return nil
+	case op == 0x0c || op == 0x0d || op == 0x10 || op == 0x12:
+		// br, br_if, call, return_call
+		return r.skipLEB()
+	case op == 0x11 || op == 0x13:
+		// call_indirect, return_call_indirect
+		return r.skipLEBs(2)
+	case op == 0x0e:
+		// br_table
+		targets, err := r.u32()
+		if err != nil {
+			return err
+		}
+		return r.skipLEBs(int(targets) + 1)
+	case op == 0x1c:
+		// select with types
+		types, err := r.u32()
+		if err != nil {
+			return err
+		}
+		for i := uint32(0); i < types; i++ {
+			if err = r.skipValueType(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case op >= 0x20 && op <= 0x22, op == 0x25, op == 0x26:
+		// local.get, local.set, local.tee, table.get, table.set
+		return r.skipLEB()
+	case op >= 0x28 && op <= 0x3e:
+		// loads and stores
+		return r.skipMemArg()
+	case op == 0x3f || op == 0x40:
+		// memory.size, memory.grow
+		return r.skipLEB()
+	case op == 0x41 || op == opI64Const:
+		return r.skipLEB()
+	case op == 0x43:
+		// f32.const
+		_, err := r.bytes(4)
+		return err
+	case op == 0x44:
+		// f64.const
+		_, err := r.bytes(8)
+		return err
+	case op == 0xd0 || op == 0xd2:
+		// ref.null, ref.func
+		return r.skipLEB()
+	case op == opMiscPrefix:
+		return skipMiscImmediates(r)
+	case op == opSIMDPrefix:
+		return skipSIMDImmediates(r)
+	case op == opAtomicPref:
+		sub, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if sub == 0x03 {
+			// atomic.fence
+			_, err = r.byte()
+			return err
+		}
+		return r.skipMemArg()
+	default:
+		return fmt.Errorf("unsupported instruction 0x%x", op)
+	}
+}
+
+func skipMiscImmediates(r *reader) error {
+	sub, err := r.u32()
+	if err != nil {
+		return err
+	}
+	switch {
+	case sub <= 7:
+		// saturating truncations
+		return nil
+	case sub == 8 || sub == 10 || sub == 12 || sub == 14:
+		// memory.init, memory.copy, table.init, table.copy
+		return r.skipLEBs(2)
+	case sub <= 17:
+		// data.drop, memory.fill, elem.drop, table.grow, table.size, table.fill
+		return r.skipLEB()
+	default:
+		return fmt.Errorf("unsupported instruction 0xfc %d", sub)
+	}
+}
+
+func skipSIMDImmediates(r *reader) error {
+	sub, err := r.u32()
+	if err != nil {
+		return err
+	}
+	switch {
+	case sub <= 11 || sub == 92 || sub == 93:
+		// loads and stores
+		return r.skipMemArg()
+	case sub == 12 || sub == 13:
+		// v128.const, i8x16.shuffle
+		_, err = r.bytes(16)
+		return err
+	case sub >= 21 && sub <= 34:
+		// extract and replace lane
+		_, err = r.byte()
+		return err
+	case sub >= 84 && sub <= 91:
+		// load and store lane
+		if err = r.skipMemArg(); err != nil {
+			return err
+		}
+		_, err = r.byte()
+		return err
+	default:
+		return nil
+	}
+}
+
+// reader reads the constructs of a WASM binary
+type reader struct {
+	data []byte
+	pos  int
+}
+
+func (r *reader) done() bool {
+	return r.pos >= len(r.data)
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.done() {
+		return 0, errUnexpectedEnd
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > len(r.data)-r.pos {
+		return nil, errUnexpectedEnd
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *reader) u32() (uint32, error) {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 || v > 0xffffffff {
+		return 0, errors.New("invalid unsigned integer")
+	}
+	r.pos += n
+	return uint32(v), nil
+}
+
+// skipLEB skips a signed or unsigned LEB128 integer
+func (r *reader) skipLEB() error {
+	for {
+		b, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if b&0x80 == 0 {
+			return nil
+		}
+	}
+}
+
+func (r *reader) skipLEBs(n int) error {
+	for i := 0; i < n; i++ {
+		if err := r.skipLEB(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reader) skipLimits() error {
+	flags, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if err = r.skipLEB(); err != nil {
+		return err
+	}
+	if flags&1 != 0 {
+		return r.skipLEB()
+	}
+	return nil
+}
+
+// skipMemArg skips the alignment, memory index if any, and offset of a memory instruction
+func (r *reader) skipMemArg() error {
+	align, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if align&0x40 != 0 {
+		if err = r.skipLEB(); err != nil {
+			return err
+		}
+	}
+	return r.skipLEB()
+}
+
+// skipValueType skips a number, vector or reference type
+func (r *reader) skipValueType() error {
+	t, err := r.byte()
+	if err != nil {
+		return err
+	}
+	switch t {
+	case 0x7f, 0x7e, 0x7d, 0x7c, 0x7b, 0x70, 0x6f:
+		return nil
+	default:
+		return fmt.Errorf("unsupported value type 0x%x", t)
+	}
+}
+
+func (r *reader) name() (string, error) {
+	size, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(int(size))
+	return string(b), err
+}
+
+func appendName(out []byte, name string) []byte {
+	out = binary.AppendUvarint(out, uint64(len(name)))
+	return append(out, name...)
+}
+
+// appendSLEB appends a signed LEB128 integer
+func appendSLEB(out []byte, v int64) []byte {
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
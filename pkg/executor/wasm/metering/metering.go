@@ -0,0 +1,83 @@
+// Package metering limits the number of instructions WASM modules can run.
+//
+// Modules are instrumented with Instrument to consume fuel from a global shared by all the
+// modules of a runtime, which is provided by the module instantiated with InstantiateModule.
+// A module trapping because it ran out of fuel is detected with Meter.Exhausted.
+package metering
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+const (
+	// ModuleName is the name of the module providing the fuel global to instrumented modules
+	ModuleName = "bacalhau_metering"
+	// FuelGlobalName is the name of the mutable i64 global holding the remaining fuel
+	FuelGlobalName = "fuel"
+)
+
+// Meter tracks the fuel consumed by the instrumented modules of a runtime
+type Meter struct {
+	limit uint64
+	fuel  api.MutableGlobal
+}
+
+// InstantiateModule instantiates the module providing the fuel global in the runtime, with
+// limit as the fuel available to the modules instrumented by Instrument instantiated after it.
+func InstantiateModule(ctx context.Context, r wazero.Runtime, limit uint64) (*Meter, error) {
+	limit = min(limit, math.MaxInt64)
+	module, err := r.InstantiateWithConfig(ctx, fuelModule(), wazero.NewModuleConfig().WithName(ModuleName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate metering module: %w", err)
+	}
+	fuel, ok := module.ExportedGlobal(FuelGlobalName).(api.MutableGlobal)
+	if !ok {
+		return nil, fmt.Errorf("metering module does not export the mutable global %s", FuelGlobalName)
+	}
+	fuel.Set(limit)
+	return &Meter{limit: limit, fuel: fuel}, nil
+}
+
+// Limit returns the fuel initially available
+func (m *Meter) Limit() uint64 {
+	return m.limit
+}
+
+// Used returns the fuel consumed so far, which is the limit once the fuel is exhausted
+func (m *Meter) Used() uint64 {
+	remaining := int64(m.fuel.Get())
+	if remaining < 0 {
+		return m.limit
+	}
+	return m.limit - uint64(remaining)
+}
+
+// Exhausted returns whether a module ran out of fuel
+func (m *Meter) Exhausted() bool {
+	return int64(m.fuel.Get()) < 0
+}
+
+// fuelModule returns the binary of a module exporting a mutable i64 global
+func fuelModule() []byte {
+	global := []byte{
+		1,                     // one global
+		valueTypeI64, mutable, // global type
+		opI64Const, 0, opEnd, // initial value
+	}
+	export := appendName([]byte{1}, FuelGlobalName) // one export
+	export = append(export, externGlobal, 0)
+
+	out := append([]byte{}, wasmHeader...)
+	out = append(out, sectionGlobal)
+	out = binary.AppendUvarint(out, uint64(len(global)))
+	out = append(out, global...)
+	out = append(out, sectionExport)
+	out = binary.AppendUvarint(out, uint64(len(export)))
+	return append(out, export...)
+}
@@ -0,0 +1,156 @@
+//go:build unit || !integration
+
+package metering
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/cat"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/csv"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/easter"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/exit_code"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/noop"
+)
+
+type MeteringSuite struct {
+	suite.Suite
+}
+
+func TestMeteringSuite(t *testing.T) {
+	suite.Run(t, new(MeteringSuite))
+}
+
+// run instantiates the instrumented binary with the given fuel limit, and calls its start function
+func (s *MeteringSuite) run(binary []byte, limit uint64) (*Meter, string, error) {
+	ctx := context.Background()
+	runtime := wazero.NewRuntime(ctx)
+	s.T().Cleanup(func() { _ = runtime.Close(ctx) })
+	wasi_snapshot_preview1.MustInstantiate(ctx, runtime)
+
+	meter, err := InstantiateModule(ctx, runtime, limit)
+	s.Require().NoError(err)
+	instrumented, err := Instrument(binary)
+	s.Require().NoError(err)
+
+	stdout := new(bytes.Buffer)
+	config := wazero.NewModuleConfig().WithStartFunctions().WithStdout(stdout)
+	module, err := runtime.InstantiateWithConfig(ctx, instrumented, config)
+	s.Require().NoError(err)
+	_, err = module.ExportedFunction("_start").Call(ctx)
+	var exitErr *sys.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 0 {
+		err = nil
+	}
+	return meter, stdout.String(), err
+}
+
+func (s *MeteringSuite) TestInstrumentedProgramsAreValid() {
+	ctx := context.Background()
+	runtime := wazero.NewRuntime(ctx)
+	defer runtime.Close(ctx)
+	for name, program := range map[string][]byte{
+		"cat":       cat.Program(),
+		"csv":       csv.Program(),
+		"easter":    easter.Program(),
+		"exit_code": exit_code.Program(),
+		"noop":      noop.Program(),
+	} {
+		instrumented, err := Instrument(program)
+		s.Require().NoError(err, name)
+		_, err = runtime.CompileModule(ctx, instrumented)
+		s.Require().NoError(err, name)
+	}
+}
+
+func (s *MeteringSuite) TestCountsInstructions() {
+	meter, stdout, err := s.run(exit_code.Program(), 1_000_000_000)
+	s.Require().NoError(err)
+	s.Equal("Exiting with 0\n", stdout)
+	s.False(meter.Exhausted())
+	s.Positive(meter.Used())
+	s.Less(meter.Used(), meter.Limit())
+
+	// the same program consumes the same fuel
+	again, _, err := s.run(exit_code.Program(), 1_000_000_000)
+	s.Require().NoError(err)
+	s.Equal(meter.Used(), again.Used())
+}
+
+func (s *MeteringSuite) TestTrapsWhenFuelIsExhausted() {
+	meter, _, err := s.run(exit_code.Program(), 1_000_000_000)
+	s.Require().NoError(err)
+	used := meter.Used()
+
+	meter, stdout, err := s.run(exit_code.Program(), used/2)
+	s.Require().Error(err)
+	s.True(meter.Exhausted())
+	s.Equal(used/2, meter.Used())
+	s.Empty(stdout)
+
+	// exactly enough fuel
+	meter, _, err = s.run(exit_code.Program(), used)
+	s.Require().NoError(err)
+	s.False(meter.Exhausted())
+	s.Equal(used, meter.Used())
+}
+
+func (s *MeteringSuite) TestRejectsInvalidBinaries() {
+	_, err := Instrument([]byte("not wasm"))
+	s.Error(err)
+
+	truncated := noop.Program()
+	_, err = Instrument(truncated[:len(truncated)/2])
+	s.Error(err)
+}
+
+func (s *MeteringSuite) TestShiftsDefinedGlobals() {
+	// (module
+	//   (import "env" "g" (global i32))
+	//   (global $a (mut i32) (global.get 0))
+	//   (func (export "f") (result i32) (global.set $a (i32.const 7)) (global.get $a))
+	//   (export "a" (global $a)))
+	binary := append([]byte{}, wasmHeader...)
+	binary = append(binary,
+		sectionType, 5, 1, 0x60, 0, 1, 0x7f,
+		sectionImport, 10, 1, 3, 'e', 'n', 'v', 1, 'g', externGlobal, 0x7f, 0,
+		3, 2, 1, 0, // function section
+		sectionGlobal, 6, 1, 0x7f, mutable, opGlobalGet, 0, opEnd,
+		sectionExport, 9, 2, 1, 'f', externFunc, 0, 1, 'a', externGlobal, 1,
+		sectionCode, 10, 1, 8, 0, 0x41, 7, opGlobalSet, 1, opGlobalGet, 1, opEnd,
+	)
+
+	// (module (global (export "g") i32 (i32.const 5)))
+	env := append([]byte{}, wasmHeader...)
+	env = append(env,
+		sectionGlobal, 6, 1, 0x7f, 0, 0x41, 5, opEnd,
+		sectionExport, 5, 1, 1, 'g', externGlobal, 0,
+	)
+
+	ctx := context.Background()
+	runtime := wazero.NewRuntime(ctx)
+	defer runtime.Close(ctx)
+	_, err := runtime.InstantiateWithConfig(ctx, env, wazero.NewModuleConfig().WithName("env"))
+	s.Require().NoError(err)
+	meter, err := InstantiateModule(ctx, runtime, 100)
+	s.Require().NoError(err)
+
+	instrumented, err := Instrument(binary)
+	s.Require().NoError(err)
+	module, err := runtime.Instantiate(ctx, instrumented)
+	s.Require().NoError(err)
+	s.Equal(uint64(5), module.ExportedGlobal("a").Get())
+
+	results, err := module.ExportedFunction("f").Call(ctx)
+	s.Require().NoError(err)
+	s.Equal([]uint64{7}, results)
+	s.Equal(uint64(7), module.ExportedGlobal("a").Get())
+	s.Equal(uint64(3), meter.Used())
+}
@@ -3,6 +3,7 @@ package wasm
 import (
 	"github.com/samber/lo"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 
 	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
 )
@@ -17,4 +18,28 @@ var (
 		"wasm_active_executions",
 		"Number of active WASM executions",
 	))
+
+	CompilationCacheHits = lo.Must(telemetry.NewCounter(
+		wasmExecutorMeter,
+		"wasm_compilation_cache_hits",
+		"Number of WASM modules whose compiled code was found in the compilation cache",
+	))
+
+	CompilationCacheMisses = lo.Must(telemetry.NewCounter(
+		wasmExecutorMeter,
+		"wasm_compilation_cache_misses",
+		"Number of WASM modules compiled because they were not in the compilation cache",
+	))
+
+	CompilationCacheEvictions = lo.Must(telemetry.NewCounter(
+		wasmExecutorMeter,
+		"wasm_compilation_cache_evictions",
+		"Number of compiled WASM modules evicted from the compilation cache to stay within its size limit",
+	))
+
+	CompilationCacheSize = lo.Must(wasmExecutorMeter.Int64UpDownCounter(
+		"wasm_compilation_cache_size",
+		metric.WithDescription("Total size of the compiled WASM modules in the compilation cache"),
+		metric.WithUnit("By"),
+	))
 )
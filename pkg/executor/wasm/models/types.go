@@ -10,6 +10,13 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 )
 
+const (
+	// KeyValueScopeJob shares the key-value bucket of a job between its executions
+	KeyValueScopeJob = "job"
+	// KeyValueScopeNamespace shares the key-value bucket of a namespace between the executions of its jobs
+	KeyValueScopeNamespace = "namespace"
+)
+
 // EngineSpec contains necessary parameters to execute a wasm job.
 type EngineSpec struct {
 	// EntryModule is the target path of the input source containing the WASM code to start running.
@@ -27,12 +34,24 @@ type EngineSpec struct {
 	// ImportModules is a slice of target paths for WASM modules whose exports will be available as imports
 	// to the EntryModule. These targets must match InputSource targets in the job spec.
 	ImportModules []string `json:"ImportModules,omitempty"`
+
+	// InstructionLimit is the maximum number of WASM instructions the execution can run, across
+	// all of its modules. The execution fails once the limit is exceeded. Zero means no limit.
+	InstructionLimit uint64 `json:"InstructionLimit,omitempty"`
+
+	// KeyValueScope is the scope of the key-value bucket exposed to the job by the compute node,
+	// either KeyValueScopeJob or KeyValueScopeNamespace. Defaults to KeyValueScopeJob.
+	KeyValueScope string `json:"KeyValueScope,omitempty"`
 }
 
 func (c EngineSpec) Validate() error {
 	if c.EntryModule == "" {
 		return errors.New("invalid wasm engine entry module. target path cannot be empty")
 	}
+	if c.KeyValueScope != "" && c.KeyValueScope != KeyValueScopeJob && c.KeyValueScope != KeyValueScopeNamespace {
+		return fmt.Errorf("invalid wasm engine key-value scope %q. expected %q or %q",
+			c.KeyValueScope, KeyValueScopeJob, KeyValueScopeNamespace)
+	}
 	return nil
 }
 
@@ -91,6 +110,16 @@ func (b *WasmEngineBuilder) WithImportModules(e []string) *WasmEngineBuilder {
 	return b
 }
 
+func (b *WasmEngineBuilder) WithInstructionLimit(limit uint64) *WasmEngineBuilder {
+	b.spec.InstructionLimit = limit
+	return b
+}
+
+func (b *WasmEngineBuilder) WithKeyValueScope(scope string) *WasmEngineBuilder {
+	b.spec.KeyValueScope = scope
+	return b
+}
+
 func (b *WasmEngineBuilder) Build() (*models.SpecConfig, error) {
 	if err := b.spec.Validate(); err != nil {
 		return nil, err
@@ -0,0 +1,86 @@
+package boltjobstore
+
+import (
+	"context"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/boltdblib"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
+)
+
+// ExportJobs calls fn with a snapshot of each job in the store, so that they
+// can be migrated to another job store. All jobs are read in a single transaction.
+func (b *BoltJobStore) ExportJobs(ctx context.Context, fn func(jobstore.JobSnapshot) error) (err error) {
+	recorder := b.metricRecorder(ctx, BucketJobs, jobstore.AttrOperationList,
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeAll))
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return boltdblib.View(ctx, b.database, func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketJobs)).ForEachBucket(func(jobID []byte) error {
+			snapshot, err := b.exportJob(ctx, tx, recorder, string(jobID))
+			if err != nil {
+				return err
+			}
+			return fn(snapshot)
+		})
+	})
+}
+
+func (b *BoltJobStore) exportJob(
+	ctx context.Context, tx *bolt.Tx, recorder *telemetry.MetricRecorder, jobID string) (jobstore.JobSnapshot, error) {
+	var snapshot jobstore.JobSnapshot
+	var err error
+
+	if snapshot.Job, err = b.getJob(ctx, tx, recorder, jobID); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Versions, err = b.getJobVersions(ctx, tx, recorder, jobID); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Executions, err = exportJobBucket[models.Execution](
+		ctx, b, tx, recorder, jobID, BucketJobExecutions); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Evaluations, err = exportJobBucket[models.Evaluation](
+		ctx, b, tx, recorder, jobID, BucketJobEvaluations); err != nil {
+		return snapshot, err
+	}
+	if snapshot.History, err = exportJobBucket[models.JobHistory](
+		ctx, b, tx, recorder, jobID, BucketJobHistory); err != nil {
+		return snapshot, err
+	}
+	return snapshot, nil
+}
+
+// exportJobBucket reads all the items stored in one of the buckets of a job, in key order
+func exportJobBucket[T any](ctx context.Context, b *BoltJobStore, tx *bolt.Tx,
+	recorder *telemetry.MetricRecorder, jobID, bucket string) ([]T, error) {
+	bkt, err := NewBucketPath(BucketJobs, jobID, bucket).Get(tx, false)
+	if err != nil {
+		return nil, NewBoltDBError(err)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartBucketRead)
+
+	var items []T
+	err = bkt.ForEach(func(_, data []byte) error {
+		recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartRead)
+		recorder.CountN(ctx, jobstore.DataRead, int64(len(data)))
+		recorder.Count(ctx, jobstore.RowsRead)
+
+		var item T
+		if err := b.marshaller.Unmarshal(data, &item); err != nil {
+			return err
+		}
+		recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartUnmarshal)
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
+
+// Static check to ensure that BoltJobStore can be migrated to other job stores
+var _ jobstore.Exporter = (*BoltJobStore)(nil)
@@ -40,6 +40,7 @@ const (
 	BucketJobEvaluations = "evaluations"
 	BucketJobHistory     = "history"
 	BucketJobVersions    = "versions" // bucket for job versions
+	BucketQuotas         = "quotas"   // namespace -> NamespaceQuota
 
 	BucketTagsIndex                 = "idx_tags"                  // tag -> Job id
 	BucketProgressIndex             = "idx_inprogress"            // job-id -> {}
@@ -49,6 +50,8 @@ const (
 	BucketJobsNamesIndex            = "idx_job_names"             // job-name -> Job id
 	BucketInProgressExecutionsIndex = "idx_inprogress_executions" // executionID:jobID -> {}
 	BucketExecutionsByNodeIndex     = "idx_executions_by_node"    // node-id -> executionID:jobID
+	BucketScheduledIndex            = "idx_scheduled"             // job-id -> {}
+	BucketDependentsIndex           = "idx_dependents"            // upstream-job-id -> Job id
 
 	// Event-related buckets
 	eventsBucket      = "v1_events"
@@ -71,6 +74,8 @@ type BoltJobStore struct {
 	namesIndex                *Index
 	inProgressExecutionsIndex *Index
 	executionsByNodeIndex     *Index
+	scheduledIndex            *Index
+	dependentsIndex           *Index
 }
 
 type Option func(store *BoltJobStore)
@@ -94,6 +99,10 @@ func WithClock(clock clock.Clock) Option {
 //		bucket history -> key  []sequence -> History
 //		bucket evaluations -> key executionID -> Execution
 //
+// bucket Quotas
+//
+//	key namespace -> NamespaceQuota
+//
 // Indexes are structured as :
 //
 //	TagsIndex        = tag -> Job id
@@ -101,6 +110,8 @@ func WithClock(clock clock.Clock) Option {
 //	NamespacesIndex  = namespace -> Job id
 //	ExecutionsIndex  = execution-id -> Job id
 //	EvaluationsIndex = evaluation-id -> Job id
+//	ScheduledIndex   = job-id -> {}
+//	DependentsIndex  = upstream-job-id -> Job id
 func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
 	db, err := boltdblib.Open(dbPath)
 	if err != nil {
@@ -126,6 +137,12 @@ func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
 			return err
 		}
 
+		// Create the top level namespace quotas bucket
+		_, err = tx.CreateBucketIfNotExists([]byte(BucketQuotas))
+		if err != nil {
+			return err
+		}
+
 		indexBuckets := []string{
 			BucketTagsIndex,
 			BucketProgressIndex,
@@ -135,6 +152,8 @@ func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
 			BucketJobsNamesIndex,
 			BucketInProgressExecutionsIndex,
 			BucketExecutionsByNodeIndex,
+			BucketScheduledIndex,
+			BucketDependentsIndex,
 		}
 		for _, ib := range indexBuckets {
 			_, err := tx.CreateBucketIfNotExists([]byte(ib))
@@ -156,9 +175,12 @@ func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
 	store.namesIndex = NewIndex(BucketJobsNamesIndex)
 	store.inProgressExecutionsIndex = NewIndex(BucketInProgressExecutionsIndex)
 	store.executionsByNodeIndex = NewIndex(BucketExecutionsByNodeIndex)
+	store.scheduledIndex = NewIndex(BucketScheduledIndex)
+	store.dependentsIndex = NewIndex(BucketDependentsIndex)
 
 	eventObjectSerializer := watcher.NewJSONSerializer()
 	err = errors.Join(
+		eventObjectSerializer.RegisterType(jobstore.EventObjectJobUpsert, reflect.TypeOf(models.JobUpsert{})),
 		eventObjectSerializer.RegisterType(jobstore.EventObjectExecutionUpsert, reflect.TypeOf(models.ExecutionUpsert{})),
 		eventObjectSerializer.RegisterType(jobstore.EventObjectEvaluation, reflect.TypeOf(models.Evaluation{})),
 	)
@@ -939,6 +961,142 @@ func (b *BoltJobStore) getInProgressJobs(
 	return infos, nil
 }
 
+// GetInProgressJobsInNamespace gets a list of the currently in-progress jobs of the namespace
+func (b *BoltJobStore) GetInProgressJobsInNamespace(ctx context.Context, namespace string) (jobs []models.Job, err error) {
+	recorder := b.metricRecorder(ctx, BucketJobs, jobstore.AttrOperationList,
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeInProgress), attribute.String("query.namespace", namespace))
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = boltdblib.View(ctx, b.database, func(tx *bolt.Tx) (err error) {
+		jobs, err = b.getInProgressJobsInNamespace(ctx, tx, recorder, namespace)
+		return
+	})
+	return jobs, err
+}
+
+func (b *BoltJobStore) getInProgressJobsInNamespace(
+	ctx context.Context, tx *bolt.Tx, recorder *telemetry.MetricRecorder, namespace string) ([]models.Job, error) {
+	ids, err := b.namespacesIndex.List(tx, []byte(namespace))
+	if err != nil {
+		return nil, NewBoltDBError(err)
+	}
+	inNamespace := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		inNamespace[string(id)] = true
+	}
+
+	keys, err := b.inProgressIndex.List(tx)
+	if err != nil {
+		return nil, NewBoltDBError(err)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartIndexRead)
+
+	var jobs []models.Job
+	for _, jobIDKey := range keys {
+		k, _ := splitInProgressIndexKey(string(jobIDKey))
+		if !inNamespace[k] {
+			continue
+		}
+		job, err := b.getJob(ctx, tx, recorder, k)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// GetScheduledJobs retrieves all jobs that run on a recurring schedule and have not been stopped.
+func (b *BoltJobStore) GetScheduledJobs(ctx context.Context) (jobs []models.Job, err error) {
+	recorder := b.metricRecorder(ctx, BucketJobs, jobstore.AttrOperationList,
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeScheduled))
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = boltdblib.View(ctx, b.database, func(tx *bolt.Tx) (err error) {
+		jobs, err = b.getScheduledJobs(ctx, tx, recorder)
+		return
+	})
+	return jobs, err
+}
+
+func (b *BoltJobStore) getScheduledJobs(
+	ctx context.Context, tx *bolt.Tx, recorder *telemetry.MetricRecorder) ([]models.Job, error) {
+	keys, err := b.scheduledIndex.List(tx)
+	if err != nil {
+		return nil, NewBoltDBError(err)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartIndexRead)
+
+	jobs := make([]models.Job, 0, len(keys))
+	for _, jobIDKey := range keys {
+		job, err := b.getJob(ctx, tx, recorder, string(jobIDKey))
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// GetDependentJobs retrieves the in progress jobs that depend on the job with the given ID.
+func (b *BoltJobStore) GetDependentJobs(ctx context.Context, jobID string) (jobs []models.Job, err error) {
+	recorder := b.metricRecorder(ctx, BucketJobs, jobstore.AttrOperationList,
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeDependents))
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = boltdblib.View(ctx, b.database, func(tx *bolt.Tx) (err error) {
+		jobs, err = b.getDependentJobs(ctx, tx, recorder, jobID)
+		return
+	})
+	return jobs, err
+}
+
+func (b *BoltJobStore) getDependentJobs(
+	ctx context.Context, tx *bolt.Tx, recorder *telemetry.MetricRecorder, jobID string) ([]models.Job, error) {
+	keys, err := b.dependentsIndex.List(tx, []byte(jobID))
+	if err != nil {
+		return nil, NewBoltDBError(err)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartIndexRead)
+
+	jobs := make([]models.Job, 0, len(keys))
+	for _, jobIDKey := range keys {
+		job, err := b.getJob(ctx, tx, recorder, string(jobIDKey))
+		if err != nil {
+			return nil, err
+		}
+		if !job.IsTerminal() {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// addDependents indexes the job as a dependent of each of its upstream jobs
+func (b *BoltJobStore) addDependents(tx *bolt.Tx, job models.Job) error {
+	for _, upstreamID := range job.DependsOn {
+		if err := b.dependentsIndex.Add(tx, []byte(job.ID), []byte(upstreamID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeDependents removes the job from the dependents of each of its upstream jobs
+func (b *BoltJobStore) removeDependents(tx *bolt.Tx, job models.Job) error {
+	for _, upstreamID := range job.DependsOn {
+		err := b.dependentsIndex.Remove(tx, []byte(job.ID), []byte(upstreamID))
+		// jobs created before the index was introduced are not in it
+		if err != nil && !errors.Is(err, bbolterrors.ErrBucketNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetJobHistory retrieves the paginated job history for a given job ID based on the specified query.
 //
 // This method performs a read transaction on the Bolt DB and fetches the job history
@@ -1262,6 +1420,16 @@ func (b *BoltJobStore) createJob(
 		return NewBoltDBError(err)
 	}
 
+	if job.IsScheduled() {
+		if err = b.scheduledIndex.Add(tx, jobIDKey); err != nil {
+			return NewBoltDBError(err)
+		}
+	}
+
+	if err = b.addDependents(tx, job); err != nil {
+		return NewBoltDBError(err)
+	}
+
 	if err = b.namespacesIndex.Add(tx, jobIDKey, []byte(job.Namespace)); err != nil {
 		return NewBoltDBError(err)
 	}
@@ -1282,7 +1450,7 @@ func (b *BoltJobStore) createJob(
 
 	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartIndexWrite)
 
-	return nil
+	return b.storeJobEvent(ctx, tx, recorder, watcher.OperationCreate, models.JobUpsert{Current: &job})
 }
 
 // DeleteJob removes the specified job from the system entirely
@@ -1350,6 +1518,14 @@ func (b *BoltJobStore) deleteJob(
 		return err
 	}
 
+	if err = b.scheduledIndex.Remove(tx, jobIDKey); err != nil {
+		return err
+	}
+
+	if err = b.removeDependents(tx, job); err != nil {
+		return err
+	}
+
 	if err = b.namespacesIndex.Remove(tx, jobIDKey, []byte(job.Namespace)); err != nil {
 		return err
 	}
@@ -1370,7 +1546,7 @@ func (b *BoltJobStore) deleteJob(
 
 	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartIndexDelete)
 
-	return nil
+	return b.storeJobEvent(ctx, tx, recorder, watcher.OperationDelete, models.JobUpsert{Previous: &job})
 }
 
 // UpdateJob updates an existing job in the data store
@@ -1457,6 +1633,7 @@ func (b *BoltJobStore) updateJob(
 	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartWrite)
 
 	// Update only the specified fields
+	previousJob := existingJob.Copy()
 	existingJob.Priority = updatedJob.Priority
 	existingJob.Count = updatedJob.Count
 	existingJob.State = models.NewJobState(models.JobStateTypePending)
@@ -1464,6 +1641,9 @@ func (b *BoltJobStore) updateJob(
 	existingJob.Meta = updatedJob.Meta
 	existingJob.Labels = updatedJob.Labels
 	existingJob.Tasks = updatedJob.Tasks
+	existingJob.DependsOn = updatedJob.DependsOn
+	existingJob.Schedule = updatedJob.Schedule
+	existingJob.Webhooks = updatedJob.Webhooks
 
 	// Increment version and update modification time
 	existingJob.Version++
@@ -1506,8 +1686,26 @@ func (b *BoltJobStore) updateJob(
 		return NewBoltDBError(err)
 	}
 
-	// Update tags index - first remove all existing tags
+	// Update the scheduled index in case the schedule was added or removed
 	jobIDKey := []byte(existingJob.ID)
+	if existingJob.IsScheduled() {
+		err = b.scheduledIndex.Add(tx, jobIDKey)
+	} else {
+		err = b.scheduledIndex.Remove(tx, jobIDKey)
+	}
+	if err != nil {
+		return NewBoltDBError(err)
+	}
+
+	// Update the dependents index in case the dependencies changed
+	if err = b.removeDependents(tx, *previousJob); err != nil {
+		return NewBoltDBError(err)
+	}
+	if err = b.addDependents(tx, existingJob); err != nil {
+		return NewBoltDBError(err)
+	}
+
+	// Update tags index - first remove all existing tags
 	for tag := range existingJob.Labels {
 		tagBytes := []byte(strings.ToLower(tag))
 		if err = b.tagsIndex.Remove(tx, jobIDKey, tagBytes); err != nil {
@@ -1524,6 +1722,21 @@ func (b *BoltJobStore) updateJob(
 	}
 	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartIndexWrite)
 
+	return b.storeJobEvent(ctx, tx, recorder, watcher.OperationUpdate,
+		models.JobUpsert{Current: &existingJob, Previous: previousJob})
+}
+
+// storeJobEvent records a job event in the event store as part of the transaction
+func (b *BoltJobStore) storeJobEvent(
+	ctx context.Context, tx *bolt.Tx, recorder *telemetry.MetricRecorder, op watcher.Operation, upsert models.JobUpsert) error {
+	if err := b.eventStore.StoreEventTx(tx, watcher.StoreEventRequest{
+		Operation:  op,
+		ObjectType: jobstore.EventObjectJobUpsert,
+		Object:     upsert,
+	}); err != nil {
+		return err
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartEventWrite)
 	return nil
 }
 
@@ -1562,6 +1775,7 @@ func (b *BoltJobStore) updateJobState(ctx context.Context, tx *bolt.Tx, recorder
 
 	// update the job state
 	// For state changes, we don't increment Version
+	previousJob := job.Copy()
 	job.State.StateType = request.NewState
 	job.State.Message = request.Message
 	job.Revision++
@@ -1612,10 +1826,18 @@ func (b *BoltJobStore) updateJobState(ctx context.Context, tx *bolt.Tx, recorder
 		if err != nil {
 			return err
 		}
+
+		// Stopping a scheduled job stops its future runs
+		if job.State.StateType == models.JobStateTypeStopped {
+			if err = b.scheduledIndex.Remove(tx, []byte(job.ID)); err != nil {
+				return err
+			}
+		}
 		recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartIndexWrite)
 	}
 
-	return nil
+	return b.storeJobEvent(ctx, tx, recorder, watcher.OperationUpdate,
+		models.JobUpsert{Current: &job, Previous: previousJob})
 }
 
 // AddJobHistory appends a new history entry to the job history
@@ -2295,3 +2517,115 @@ func (b *BoltJobStore) GetJobByIDOrName(ctx context.Context, idOrName, namespace
 	}
 	return job, err
 }
+
+// GetNamespaceQuota retrieves the quota of the specified namespace
+func (b *BoltJobStore) GetNamespaceQuota(ctx context.Context, namespace string) (quota models.NamespaceQuota, err error) {
+	recorder := b.metricRecorder(ctx, BucketQuotas, jobstore.AttrOperationGet)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = boltdblib.View(ctx, b.database, func(tx *bolt.Tx) (err error) {
+		quota, err = b.getNamespaceQuota(ctx, tx, recorder, namespace)
+		return
+	})
+	return quota, err
+}
+
+func (b *BoltJobStore) getNamespaceQuota(
+	ctx context.Context, tx *bolt.Tx, recorder *telemetry.MetricRecorder, namespace string) (models.NamespaceQuota, error) {
+	var quota models.NamespaceQuota
+	data := tx.Bucket([]byte(BucketQuotas)).Get([]byte(namespace))
+	if data == nil {
+		return quota, jobstore.NewErrNamespaceQuotaNotFound(namespace)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartRead)
+	recorder.CountN(ctx, jobstore.DataRead, int64(len(data)))
+	recorder.Count(ctx, jobstore.RowsRead)
+
+	err := b.marshaller.Unmarshal(data, &quota)
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartUnmarshal)
+	return quota, err
+}
+
+// GetNamespaceQuotas retrieves the quotas of all namespaces that have one, sorted by namespace
+func (b *BoltJobStore) GetNamespaceQuotas(ctx context.Context) (quotas []models.NamespaceQuota, err error) {
+	recorder := b.metricRecorder(ctx, BucketQuotas, jobstore.AttrOperationList)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = boltdblib.View(ctx, b.database, func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketQuotas)).ForEach(func(_, data []byte) error {
+			recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartRead)
+			recorder.CountN(ctx, jobstore.DataRead, int64(len(data)))
+			recorder.Count(ctx, jobstore.RowsRead)
+
+			var quota models.NamespaceQuota
+			if err := b.marshaller.Unmarshal(data, &quota); err != nil {
+				return err
+			}
+			recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartUnmarshal)
+			quotas = append(quotas, quota)
+			return nil
+		})
+	})
+	return quotas, err
+}
+
+// PutNamespaceQuota creates or replaces the quota of a namespace
+func (b *BoltJobStore) PutNamespaceQuota(ctx context.Context, quota models.NamespaceQuota) (err error) {
+	recorder := b.metricRecorder(ctx, BucketQuotas, jobstore.AttrOperationUpdate)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return boltdblib.Update(ctx, b.database, func(tx *bolt.Tx) (err error) {
+		return b.putNamespaceQuota(ctx, tx, recorder, quota)
+	})
+}
+
+func (b *BoltJobStore) putNamespaceQuota(
+	ctx context.Context, tx *bolt.Tx, recorder *telemetry.MetricRecorder, quota models.NamespaceQuota) error {
+	quota.Normalize()
+	if err := quota.Validate(); err != nil {
+		return jobstore.NewBadRequestError(err.Error())
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartValidate)
+
+	now := b.clock.Now().UTC().UnixNano()
+	quota.CreateTime = now
+	if existing, err := b.getNamespaceQuota(ctx, tx, recorder, quota.Namespace); err == nil {
+		quota.CreateTime = existing.CreateTime
+	}
+	quota.ModifyTime = now
+
+	data, err := b.marshaller.Marshal(quota)
+	if err != nil {
+		return err
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartMarshal)
+	recorder.CountN(ctx, jobstore.DataWritten, int64(len(data)))
+
+	if err = tx.Bucket([]byte(BucketQuotas)).Put([]byte(quota.Namespace), data); err != nil {
+		return NewBoltDBError(err)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartWrite)
+	return nil
+}
+
+// DeleteNamespaceQuota deletes the quota of the specified namespace
+func (b *BoltJobStore) DeleteNamespaceQuota(ctx context.Context, namespace string) (err error) {
+	recorder := b.metricRecorder(ctx, BucketQuotas, jobstore.AttrOperationDelete)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return boltdblib.Update(ctx, b.database, func(tx *bolt.Tx) (err error) {
+		bkt := tx.Bucket([]byte(BucketQuotas))
+		if bkt.Get([]byte(namespace)) == nil {
+			return jobstore.NewErrNamespaceQuotaNotFound(namespace)
+		}
+		if err = bkt.Delete([]byte(namespace)); err != nil {
+			return NewBoltDBError(err)
+		}
+		recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartDelete)
+		return nil
+	})
+}
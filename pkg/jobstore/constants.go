@@ -1,6 +1,9 @@
 package jobstore
 
 const (
+	// EventObjectJobUpsert is the event type for job upsert events, which holds the job
+	// before and after the change.
+	EventObjectJobUpsert = "JobUpsert"
 	// EventObjectExecutionUpsert is the event type for execution upsert events, which holds richer data
 	// about the execution's update, such as the previous and new execution data, and any events.
 	EventObjectExecutionUpsert = "ExecutionUpsert"
@@ -173,3 +173,9 @@ func NewBadRequestError(message string) bacerrors.Error {
 		WithCode(bacerrors.BadRequestError).
 		WithComponent(JobStoreComponent)
 }
+
+func NewErrNamespaceQuotaNotFound(namespace string) bacerrors.Error {
+	return bacerrors.Newf("quota not found for namespace %s", namespace).
+		WithCode(bacerrors.NotFoundError).
+		WithComponent(JobStoreComponent)
+}
@@ -112,6 +112,8 @@ const (
 	AttrScopeAll        = "all"
 	AttrScopeNamespace  = "namespace"
 	AttrScopeInProgress = "in_progress"
+	AttrScopeScheduled  = "scheduled"
+	AttrScopeDependents = "dependents"
 	AttrScopeJob        = "job"
 	AttrScopeExecution  = "execution"
 )
@@ -0,0 +1,84 @@
+package jobstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// JobSnapshot is a job along with everything stored about it, and is used
+// to copy jobs between job stores.
+type JobSnapshot struct {
+	// Job is the current state of the job
+	Job models.Job
+	// Versions are all the versions of the job, including the current one
+	Versions    []models.Job
+	Executions  []models.Execution
+	Evaluations []models.Evaluation
+	History     []models.JobHistory
+}
+
+// Exporter is implemented by job stores that can export their content
+type Exporter interface {
+	// ExportJobs calls fn with a snapshot of each job in the store
+	ExportJobs(ctx context.Context, fn func(JobSnapshot) error) error
+	// GetNamespaceQuotas retrieves the quotas of all namespaces that have one
+	GetNamespaceQuotas(ctx context.Context) ([]models.NamespaceQuota, error)
+}
+
+// Importer is implemented by job stores that can import the content of another store
+type Importer interface {
+	// ImportJob stores a job snapshot as is, without emitting events or changing
+	// versions, revisions or timestamps. It fails if the job already exists.
+	ImportJob(ctx context.Context, snapshot JobSnapshot) error
+	// ImportNamespaceQuota stores a namespace quota as is, replacing any existing one
+	ImportNamespaceQuota(ctx context.Context, quota models.NamespaceQuota) error
+}
+
+// MigrationResult summarizes what was copied by Migrate
+type MigrationResult struct {
+	Jobs            int
+	SkippedJobs     int
+	NamespaceQuotas int
+}
+
+// Migrate copies all jobs and namespace quotas from the source job store to the target one.
+// Jobs that already exist in the target are skipped, so an interrupted migration can be resumed.
+// Events are not copied, so the target should be migrated before any orchestrator uses it.
+func Migrate(ctx context.Context, source Exporter, target Importer) (MigrationResult, error) {
+	var result MigrationResult
+
+	err := source.ExportJobs(ctx, func(snapshot JobSnapshot) error {
+		err := target.ImportJob(ctx, snapshot)
+		if bacerrors.IsErrorWithCode(err, bacerrors.ResourceInUse) {
+			log.Ctx(ctx).Debug().Str("job_id", snapshot.Job.ID).Msg("skipping job that already exists in the target job store")
+			result.SkippedJobs++
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to import job %s: %w", snapshot.Job.ID, err)
+		}
+		result.Jobs++
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	quotas, err := source.GetNamespaceQuotas(ctx)
+	if err != nil {
+		return result, err
+	}
+	for _, quota := range quotas {
+		if err = target.ImportNamespaceQuota(ctx, quota); err != nil {
+			return result, fmt.Errorf("failed to import quota of namespace %s: %w", quota.Namespace, err)
+		}
+		result.NamespaceQuotas++
+	}
+
+	return result, nil
+}
@@ -282,6 +282,20 @@ func (mr *MockStoreMockRecorder) DeleteJob(ctx, jobID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteJob", reflect.TypeOf((*MockStore)(nil).DeleteJob), ctx, jobID)
 }
 
+// DeleteNamespaceQuota mocks base method.
+func (m *MockStore) DeleteNamespaceQuota(ctx context.Context, namespace string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNamespaceQuota", ctx, namespace)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNamespaceQuota indicates an expected call of DeleteNamespaceQuota.
+func (mr *MockStoreMockRecorder) DeleteNamespaceQuota(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNamespaceQuota", reflect.TypeOf((*MockStore)(nil).DeleteNamespaceQuota), ctx, namespace)
+}
+
 // GetEvaluation mocks base method.
 func (m *MockStore) GetEvaluation(ctx context.Context, id string) (models.Evaluation, error) {
 	m.ctrl.T.Helper()
@@ -341,6 +355,21 @@ func (mr *MockStoreMockRecorder) GetInProgressJobs(ctx, jobType interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInProgressJobs", reflect.TypeOf((*MockStore)(nil).GetInProgressJobs), ctx, jobType)
 }
 
+// GetInProgressJobsInNamespace mocks base method.
+func (m *MockStore) GetInProgressJobsInNamespace(ctx context.Context, namespace string) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInProgressJobsInNamespace", ctx, namespace)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInProgressJobsInNamespace indicates an expected call of GetInProgressJobsInNamespace.
+func (mr *MockStoreMockRecorder) GetInProgressJobsInNamespace(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInProgressJobsInNamespace", reflect.TypeOf((*MockStore)(nil).GetInProgressJobsInNamespace), ctx, namespace)
+}
+
 // GetJob mocks base method.
 func (m *MockStore) GetJob(ctx context.Context, id string) (models.Job, error) {
 	m.ctrl.T.Helper()
@@ -446,6 +475,80 @@ func (mr *MockStoreMockRecorder) GetJobs(ctx, query interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobs", reflect.TypeOf((*MockStore)(nil).GetJobs), ctx, query)
 }
 
+// GetNamespaceQuota mocks base method.
+func (m *MockStore) GetNamespaceQuota(ctx context.Context, namespace string) (models.NamespaceQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceQuota", ctx, namespace)
+	ret0, _ := ret[0].(models.NamespaceQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceQuota indicates an expected call of GetNamespaceQuota.
+func (mr *MockStoreMockRecorder) GetNamespaceQuota(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceQuota", reflect.TypeOf((*MockStore)(nil).GetNamespaceQuota), ctx, namespace)
+}
+
+// GetNamespaceQuotas mocks base method.
+func (m *MockStore) GetNamespaceQuotas(ctx context.Context) ([]models.NamespaceQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceQuotas", ctx)
+	ret0, _ := ret[0].([]models.NamespaceQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceQuotas indicates an expected call of GetNamespaceQuotas.
+func (mr *MockStoreMockRecorder) GetNamespaceQuotas(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceQuotas", reflect.TypeOf((*MockStore)(nil).GetNamespaceQuotas), ctx)
+}
+
+// GetScheduledJobs mocks base method.
+func (m *MockStore) GetScheduledJobs(ctx context.Context) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledJobs", ctx)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledJobs indicates an expected call of GetScheduledJobs.
+func (mr *MockStoreMockRecorder) GetScheduledJobs(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledJobs", reflect.TypeOf((*MockStore)(nil).GetScheduledJobs), ctx)
+}
+
+// GetDependentJobs mocks base method.
+func (m *MockStore) GetDependentJobs(ctx context.Context, jobID string) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDependentJobs", ctx, jobID)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDependentJobs indicates an expected call of GetDependentJobs.
+func (mr *MockStoreMockRecorder) GetDependentJobs(ctx, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDependentJobs", reflect.TypeOf((*MockStore)(nil).GetDependentJobs), ctx, jobID)
+}
+
+// PutNamespaceQuota mocks base method.
+func (m *MockStore) PutNamespaceQuota(ctx context.Context, quota models.NamespaceQuota) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutNamespaceQuota", ctx, quota)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutNamespaceQuota indicates an expected call of PutNamespaceQuota.
+func (mr *MockStoreMockRecorder) PutNamespaceQuota(ctx, quota interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutNamespaceQuota", reflect.TypeOf((*MockStore)(nil).PutNamespaceQuota), ctx, quota)
+}
+
 // UpdateExecution mocks base method.
 func (m *MockStore) UpdateExecution(ctx context.Context, request UpdateExecutionRequest) error {
 	m.ctrl.T.Helper()
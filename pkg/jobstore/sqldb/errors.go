@@ -0,0 +1,14 @@
+package sqljobstore
+
+import (
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+)
+
+const SQLDBComponent = "SQLDB"
+
+// NewSQLDBError wraps an error returned by the database
+func NewSQLDBError(err error) bacerrors.Error {
+	return bacerrors.Wrap(err, "job store database operation failed").
+		WithCode(bacerrors.DatastoreFailure).
+		WithComponent(SQLDBComponent)
+}
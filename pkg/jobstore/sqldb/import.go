@@ -0,0 +1,92 @@
+package sqljobstore
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/sqldblib"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
+)
+
+// ImportJob stores a job migrated from another job store as is, along with its versions,
+// executions, evaluations and history. No events are emitted for imported jobs.
+func (s *SQLJobStore) ImportJob(ctx context.Context, snapshot jobstore.JobSnapshot) (err error) {
+	recorder := s.metricRecorder(ctx, TableJobs, jobstore.AttrOperationCreate)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) error {
+		return s.importJob(ctx, tx, recorder, snapshot)
+	})
+}
+
+func (s *SQLJobStore) importJob(ctx context.Context, tx *sqldblib.Tx,
+	recorder *telemetry.MetricRecorder, snapshot jobstore.JobSnapshot) error {
+	job := snapshot.Job
+	if s.jobExists(ctx, tx, recorder, job.ID) {
+		return jobstore.NewErrJobAlreadyExists(job.ID)
+	}
+	if s.jobExistsByName(ctx, tx, recorder, job.Name, job.Namespace) {
+		return jobstore.NewErrJobNameAlreadyExists(job.Name, job.Namespace)
+	}
+
+	if err := s.insertJob(ctx, tx, recorder, job); err != nil {
+		return err
+	}
+
+	for _, version := range snapshot.Versions {
+		data, err := s.marshal(ctx, recorder, version)
+		if err != nil {
+			return err
+		}
+		if err = s.putJobVersion(ctx, tx, job.ID, version.Version, data); err != nil {
+			return err
+		}
+	}
+
+	for _, execution := range snapshot.Executions {
+		if err := s.insertExecution(ctx, tx, recorder, execution); err != nil {
+			return err
+		}
+	}
+
+	for _, eval := range snapshot.Evaluations {
+		data, err := s.marshal(ctx, recorder, eval)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(ctx, `INSERT INTO evaluations (id, job_id, spec) VALUES (?, ?, ?)`,
+			eval.ID, job.ID, data); err != nil {
+			return NewSQLDBError(err)
+		}
+	}
+
+	// history keeps its sequence numbers, so that paging tokens issued by the previous store remain valid
+	for _, item := range snapshot.History {
+		data, err := s.marshal(ctx, recorder, item)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(ctx, `INSERT INTO job_history (job_id, seq_num, spec) VALUES (?, ?, ?)`,
+			job.ID, item.SeqNum, data); err != nil {
+			return NewSQLDBError(err)
+		}
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartWrite)
+	return nil
+}
+
+// ImportNamespaceQuota stores a namespace quota migrated from another job store as is
+func (s *SQLJobStore) ImportNamespaceQuota(ctx context.Context, quota models.NamespaceQuota) (err error) {
+	recorder := s.metricRecorder(ctx, TableNamespaceQuotas, jobstore.AttrOperationUpdate)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) error {
+		return s.writeNamespaceQuota(ctx, tx, recorder, quota)
+	})
+}
+
+// Static check to ensure that other job stores can be migrated to SQLJobStore
+var _ jobstore.Importer = (*SQLJobStore)(nil)
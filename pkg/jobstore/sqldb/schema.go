@@ -0,0 +1,103 @@
+package sqljobstore
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/sqldblib"
+)
+
+const (
+	TableJobs            = "jobs"
+	TableJobTags         = "job_tags"
+	TableJobDependencies = "job_dependencies"
+	TableJobVersions     = "job_versions"
+	TableJobExecutions   = "executions"
+	TableJobEvaluations  = "evaluations"
+	TableJobHistory      = "job_history"
+	TableNamespaceQuotas = "namespace_quotas"
+
+	// Event-related tables
+	eventsTable      = "job_events"
+	checkpointsTable = "job_event_checkpoints"
+)
+
+// schema creates the tables of the job store. Specs are stored as JSON, and the columns
+// next to them hold the fields that queries filter on. Child rows are deleted along with their job.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS jobs (
+		id          TEXT PRIMARY KEY,
+		name        TEXT NOT NULL,
+		namespace   TEXT NOT NULL,
+		type        TEXT NOT NULL,
+		version     BIGINT NOT NULL,
+		in_progress BOOLEAN NOT NULL,
+		scheduled   BOOLEAN NOT NULL,
+		create_time BIGINT NOT NULL,
+		modify_time BIGINT NOT NULL,
+		spec        TEXT NOT NULL
+	)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_name ON jobs (namespace, name)`,
+	`CREATE INDEX IF NOT EXISTS idx_jobs_in_progress ON jobs (in_progress)`,
+	`CREATE INDEX IF NOT EXISTS idx_jobs_scheduled ON jobs (scheduled)`,
+
+	`CREATE TABLE IF NOT EXISTS job_tags (
+		job_id TEXT NOT NULL REFERENCES jobs (id) ON DELETE CASCADE,
+		tag    TEXT NOT NULL,
+		PRIMARY KEY (job_id, tag)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_job_tags_tag ON job_tags (tag)`,
+
+	`CREATE TABLE IF NOT EXISTS job_dependencies (
+		job_id      TEXT NOT NULL REFERENCES jobs (id) ON DELETE CASCADE,
+		upstream_id TEXT NOT NULL,
+		PRIMARY KEY (job_id, upstream_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_job_dependencies_upstream ON job_dependencies (upstream_id)`,
+
+	`CREATE TABLE IF NOT EXISTS job_versions (
+		job_id  TEXT NOT NULL REFERENCES jobs (id) ON DELETE CASCADE,
+		version BIGINT NOT NULL,
+		spec    TEXT NOT NULL,
+		PRIMARY KEY (job_id, version)
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS executions (
+		id          TEXT PRIMARY KEY,
+		job_id      TEXT NOT NULL REFERENCES jobs (id) ON DELETE CASCADE,
+		node_id     TEXT NOT NULL,
+		in_progress BOOLEAN NOT NULL,
+		spec        TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_executions_job ON executions (job_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_executions_node ON executions (node_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_executions_in_progress ON executions (in_progress)`,
+
+	`CREATE TABLE IF NOT EXISTS evaluations (
+		id     TEXT PRIMARY KEY,
+		job_id TEXT NOT NULL REFERENCES jobs (id) ON DELETE CASCADE,
+		spec   TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_evaluations_job ON evaluations (job_id)`,
+
+	`CREATE TABLE IF NOT EXISTS job_history (
+		job_id  TEXT NOT NULL REFERENCES jobs (id) ON DELETE CASCADE,
+		seq_num BIGINT NOT NULL,
+		spec    TEXT NOT NULL,
+		PRIMARY KEY (job_id, seq_num)
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS namespace_quotas (
+		namespace TEXT PRIMARY KEY,
+		spec      TEXT NOT NULL
+	)`,
+}
+
+// createSchema creates the tables and indexes of the job store if they don't exist
+func createSchema(ctx context.Context, tx *sqldblib.Tx) error {
+	for _, statement := range schema {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
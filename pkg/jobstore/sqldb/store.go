@@ -0,0 +1,1812 @@
+package sqljobstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/benbjohnson/clock"
+	"github.com/imdario/mergo"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/bacalhau-project/bacalhau/pkg/analytics"
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/marshaller"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/sqldblib"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/watcher"
+	sqldb_watcher "github.com/bacalhau-project/bacalhau/pkg/lib/watcher/sqldb"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
+	"github.com/bacalhau-project/bacalhau/pkg/util"
+	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
+)
+
+type SQLJobStore struct {
+	database   *sqldblib.DB
+	eventStore *sqldb_watcher.EventStore
+	clock      clock.Clock
+	marshaller marshaller.Marshaller
+}
+
+type Option func(store *SQLJobStore)
+
+func WithClock(clock clock.Clock) Option {
+	return func(store *SQLJobStore) {
+		store.clock = clock
+	}
+}
+
+// NewSQLJobStore creates a new job store backed by a SQL database, which can be an
+// embedded SQLite file or a PostgreSQL server shared by multiple orchestrators.
+// The store takes ownership of the database, and closes it when the store is closed.
+//
+// Jobs, their versions, executions, evaluations and history are kept in their own tables,
+// with the full objects stored as JSON next to the columns used to query them:
+//
+//	jobs             id -> spec, indexed by namespace+name, in_progress and scheduled
+//	job_tags         job_id+tag
+//	job_dependencies job_id+upstream_id, indexed by upstream_id
+//	job_versions     job_id+version -> spec
+//	executions       id -> spec, indexed by job_id, node_id and in_progress
+//	evaluations      id -> spec, indexed by job_id
+//	job_history      job_id+seq_num -> spec
+//	namespace_quotas namespace -> spec
+func NewSQLJobStore(db *sqldblib.DB, options ...Option) (*SQLJobStore, error) {
+	store := &SQLJobStore{
+		database:   db,
+		clock:      clock.New(),
+		marshaller: marshaller.NewJSONMarshaller(),
+	}
+
+	for _, opt := range options {
+		opt(store)
+	}
+
+	err := sqldblib.Update(context.Background(), db, func(tx *sqldblib.Tx) error {
+		return createSchema(context.Background(), tx)
+	})
+	if err != nil {
+		return nil, NewSQLDBError(err)
+	}
+
+	eventObjectSerializer := watcher.NewJSONSerializer()
+	err = errors.Join(
+		eventObjectSerializer.RegisterType(jobstore.EventObjectJobUpsert, reflect.TypeOf(models.JobUpsert{})),
+		eventObjectSerializer.RegisterType(jobstore.EventObjectExecutionUpsert, reflect.TypeOf(models.ExecutionUpsert{})),
+		eventObjectSerializer.RegisterType(jobstore.EventObjectEvaluation, reflect.TypeOf(models.Evaluation{})),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register event object types: %w", err)
+	}
+
+	eventStore, err := sqldb_watcher.NewEventStore(db,
+		sqldb_watcher.WithEventsTable(eventsTable),
+		sqldb_watcher.WithCheckpointsTable(checkpointsTable),
+		sqldb_watcher.WithEventSerializer(eventObjectSerializer),
+	)
+	store.eventStore = eventStore
+
+	return store, err
+}
+
+// metricRecorder returns a new metric recorder with the given attributes
+func (s *SQLJobStore) metricRecorder(
+	ctx context.Context, table, operation string, attrs ...attribute.KeyValue) *telemetry.MetricRecorder {
+	system := semconv.DBSystemSqlite
+	if s.database.Dialect() == sqldblib.DialectPostgres {
+		system = semconv.DBSystemPostgreSQL
+	}
+	recorder := telemetry.NewMetricRecorder(
+		append(attrs,
+			system,
+			semconv.DBNamespaceKey.String("jobstore"),
+			semconv.DBOperationName(operation),
+			semconv.DBCollectionName(table),
+		)...,
+	)
+	recorder.Count(ctx, jobstore.OperationCount)
+	return recorder
+}
+
+// BeginTx starts a new writable transaction for the store
+func (s *SQLJobStore) BeginTx(ctx context.Context) (jobstore.TxContext, error) {
+	tx, err := s.database.Begin(ctx, true)
+	if err != nil {
+		return nil, NewSQLDBError(err)
+	}
+	return sqldblib.NewTxContext(ctx, tx), nil
+}
+
+// unmarshal decodes a spec read from the database, and records the read metrics
+func (s *SQLJobStore) unmarshal(
+	ctx context.Context, recorder *telemetry.MetricRecorder, data []byte, object any) error {
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartRead)
+	recorder.CountN(ctx, jobstore.DataRead, int64(len(data)))
+	recorder.Count(ctx, jobstore.RowsRead)
+
+	err := s.marshaller.Unmarshal(data, object)
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartUnmarshal)
+	return err
+}
+
+// marshal encodes a spec to be written to the database, and records the write metrics
+func (s *SQLJobStore) marshal(
+	ctx context.Context, recorder *telemetry.MetricRecorder, object any) (string, error) {
+	data, err := s.marshaller.Marshal(object)
+	if err != nil {
+		return "", err
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartMarshal)
+	recorder.CountN(ctx, jobstore.DataWritten, int64(len(data)))
+	return string(data), nil
+}
+
+// querySpecs runs a query that selects a single spec column, and returns the specs
+// once all rows are read, so that the transaction can be used for other queries.
+func querySpecs(ctx context.Context, tx *sqldblib.Tx, query string, args ...any) ([][]byte, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, NewSQLDBError(err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var specs [][]byte
+	for rows.Next() {
+		var data []byte
+		if err = rows.Scan(&data); err != nil {
+			return nil, NewSQLDBError(err)
+		}
+		specs = append(specs, data)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, NewSQLDBError(err)
+	}
+	return specs, nil
+}
+
+// querySpec runs a query that selects a single spec column of at most one row.
+// It returns nil if no row is found.
+func querySpec(ctx context.Context, tx *sqldblib.Tx, query string, args ...any) ([]byte, error) {
+	var data []byte
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, NewSQLDBError(err)
+	}
+	return data, nil
+}
+
+// GetJob retrieves the Job identified by the id string. If the job isn't found it will
+// return an indicating the error.
+func (s *SQLJobStore) GetJob(ctx context.Context, id string) (job models.Job, err error) {
+	recorder := s.metricRecorder(ctx, TableJobs, jobstore.AttrOperationGet)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		job, err = s.getJob(ctx, tx, recorder, id)
+		return
+	})
+	return job, err
+}
+
+func (s *SQLJobStore) getJob(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, jobID string) (models.Job, error) {
+	var job models.Job
+
+	jobID, err := s.reifyJobID(ctx, tx, recorder, jobID)
+	if err != nil {
+		return job, err
+	}
+
+	data, err := querySpec(ctx, tx, `SELECT spec FROM jobs WHERE id = ?`, jobID)
+	if err != nil {
+		return job, err
+	}
+	if data == nil {
+		return job, jobstore.NewErrJobNotFound(jobID)
+	}
+
+	err = s.unmarshal(ctx, recorder, data, &job)
+	return job, err
+}
+
+// reifyJobID ensures the provided job ID is a full-length ID. This is either through
+// returning the ID, or resolving the short ID to a single job id.
+func (s *SQLJobStore) reifyJobID(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, jobID string) (string, error) {
+	if idgen.ShortUUID(jobID) != jobID {
+		// Return what we were given
+		return jobID, nil
+	}
+	defer recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartReifyID)
+
+	// fetching two IDs is enough to tell whether the prefix is ambiguous
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id FROM jobs WHERE substr(id, 1, ?) = ? ORDER BY id LIMIT 2`, len(jobID), jobID)
+	if err != nil {
+		return "", NewSQLDBError(err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	found := make([]string, 0, 2) //nolint:mnd
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return "", NewSQLDBError(err)
+		}
+		found = append(found, id)
+	}
+	if err = rows.Err(); err != nil {
+		return "", NewSQLDBError(err)
+	}
+
+	switch len(found) {
+	case 0:
+		return "", jobstore.NewErrJobNotFound(jobID)
+	case 1:
+		return found[0], nil
+	default:
+		return "", jobstore.NewErrMultipleJobsFound(jobID)
+	}
+}
+
+// getExecution retrieves the Execution identified by its ID
+func (s *SQLJobStore) getExecution(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, execID string) (models.Execution, error) {
+	var exec models.Execution
+
+	data, err := querySpec(ctx, tx, `SELECT spec FROM executions WHERE id = ?`, execID)
+	if err != nil {
+		return exec, err
+	}
+	if data == nil {
+		return exec, jobstore.NewErrExecutionNotFound(execID)
+	}
+
+	err = s.unmarshal(ctx, recorder, data, &exec)
+	return exec, err
+}
+
+func (s *SQLJobStore) getExecutions(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder,
+	options jobstore.GetExecutionsOptions) ([]models.Execution, error) {
+	// Get executions based on query type
+	executions, err := s.getExecutionsForQuery(ctx, tx, recorder, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(executions) == 0 {
+		return []models.Execution{}, nil
+	}
+
+	// cache for loaded jobs and versions to be included in executions if options.IncludeJob is true
+	loadedJobs := make(map[string]models.Job)
+	// cache for latest version of each job to filter executions if not all or specific version requested
+	latestVersions := make(map[string]uint64)
+	// set of node IDs for faster lookups
+	nodeSet := lo.SliceToMap(options.NodeIDs, func(nodeID string) (string, struct{}) {
+		return nodeID, struct{}{}
+	})
+
+	// helper function to generate job version keys
+	jobVersionKey := func(jobID string, version uint64) string {
+		return fmt.Sprintf("%s::%d", jobID, version)
+	}
+
+	var execs []models.Execution
+	for _, execution := range executions {
+		var latestJobVersion uint64
+		if options.JobVersion == 0 && !options.AllJobVersions {
+			if v, ok := latestVersions[execution.JobID]; ok {
+				latestJobVersion = v
+			} else {
+				latestJobVersion, err = s.getLatestJobVersion(ctx, tx, execution.JobID)
+				if err != nil {
+					return []models.Execution{}, err
+				}
+				latestVersions[execution.JobID] = latestJobVersion
+			}
+		}
+
+		// Apply all filters
+		if filterJobExecutionItem(execution, options, nodeSet, latestJobVersion) {
+			// Add job if requested
+			if options.IncludeJob {
+				loadedJob, ok := loadedJobs[jobVersionKey(execution.JobID, execution.JobVersion)]
+				if !ok {
+					// Load job if we don't have it cached
+					loadedJob, err = s.getJobVersion(ctx, tx, recorder, execution.JobID, execution.JobVersion)
+					if err != nil {
+						return nil, err
+					}
+					loadedJobs[jobVersionKey(execution.JobID, execution.JobVersion)] = loadedJob
+				}
+				execution.Job = &loadedJob
+			}
+			execs = append(execs, execution)
+		}
+	}
+
+	sortExecutions(options, execs)
+	recorder.Latency(ctx, jobstore.OperationPartDuration, "sort")
+
+	// Apply limit
+	if options.Limit > 0 && len(execs) > options.Limit {
+		execs = execs[:options.Limit]
+	}
+
+	return execs, nil
+}
+
+// getExecutionsForQuery gets the executions of a job if JobID is specified, or otherwise the
+// executions that match ALL the node and in-progress filters (AND relationship)
+func (s *SQLJobStore) getExecutionsForQuery(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder,
+	options jobstore.GetExecutionsOptions) ([]models.Execution, error) {
+	var conditions []string
+	var args []any
+
+	if options.JobID != "" {
+		job, err := s.getJob(ctx, tx, recorder, options.JobID)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, "job_id = ?")
+		args = append(args, job.ID)
+	} else {
+		if len(options.NodeIDs) > 0 {
+			conditions = append(conditions, fmt.Sprintf("node_id IN (%s)", sqldblib.Placeholders(len(options.NodeIDs))))
+			for _, nodeID := range options.NodeIDs {
+				args = append(args, nodeID)
+			}
+		}
+		if options.InProgressOnly {
+			conditions = append(conditions, "in_progress = ?")
+			args = append(args, true)
+		}
+		// If no filters were used, return empty
+		if len(conditions) == 0 {
+			return []models.Execution{}, nil
+		}
+	}
+
+	specs, err := querySpecs(ctx, tx, fmt.Sprintf(`SELECT spec FROM executions WHERE %s ORDER BY id`,
+		strings.Join(conditions, " AND ")), args...)
+	if err != nil {
+		return nil, err
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartIndexRead)
+
+	executions := make([]models.Execution, 0, len(specs))
+	for _, data := range specs {
+		var exec models.Execution
+		if err = s.unmarshal(ctx, recorder, data, &exec); err != nil {
+			return nil, err
+		}
+		executions = append(executions, exec)
+	}
+	return executions, nil
+}
+
+func sortExecutions(options jobstore.GetExecutionsOptions, execs []models.Execution) {
+	var sortFnc func(a, b models.Execution) int
+	switch options.OrderBy {
+	// create_time will eventually be deprecated. It is being used for backward compatibility.
+	case "create_time", "created_at", "": //nolint: goconst
+		sortFnc = func(a, b models.Execution) int { return util.Compare[int64]{}.Cmp(a.CreateTime, b.CreateTime) }
+	// modify_time will eventually be deprecated. It is being used for backward compatibility.
+	case "modify_time", "modified_at":
+		sortFnc = func(a, b models.Execution) int { return util.Compare[int64]{}.Cmp(a.ModifyTime, b.ModifyTime) }
+	default:
+		// This should be handled by the caller, but for safety:
+		return
+	}
+
+	if options.Reverse {
+		baseSortFnc := sortFnc
+		sortFnc = func(a, b models.Execution) int { return -baseSortFnc(a, b) }
+	}
+
+	slices.SortFunc(execs, sortFnc)
+}
+
+// filterJobExecutionItem applies filtering logic to an execution based on the provided query options.
+// It returns true if the execution should be included in the result set, or false if it should be filtered out.
+// The filters applied are, in order:
+//   - If JobVersion is specified, only executions with that version are included.
+//   - If AllJobVersions is not set, only executions with the latest job version are included.
+//   - If Namespace is specified, only executions in that namespace are included.
+//   - If InProgressOnly is set, only executions not in a terminal state are included.
+//   - If NodeIDs are specified, only executions on those nodes are included.
+func filterJobExecutionItem(
+	item models.Execution,
+	query jobstore.GetExecutionsOptions,
+	nodeSet map[string]struct{},
+	latestJobVersion uint64,
+) bool {
+	if query.JobVersion > 0 && item.JobVersion != query.JobVersion {
+		return false
+	}
+	if query.JobVersion == 0 && !query.AllJobVersions && item.JobVersion != latestJobVersion {
+		return false
+	}
+	if query.Namespace != "" && item.Namespace != query.Namespace {
+		return false
+	}
+	if query.InProgressOnly && item.IsTerminalState() {
+		return false
+	}
+	if len(nodeSet) > 0 && !lo.HasKey(nodeSet, item.NodeID) {
+		return false
+	}
+	return true
+}
+
+func (s *SQLJobStore) jobExists(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, jobID string) bool {
+	_, err := s.getJob(ctx, tx, recorder, jobID)
+	return err == nil
+}
+
+// jobExistsByName checks if a job with the specified name exists in the given namespace
+func (s *SQLJobStore) jobExistsByName(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, name, namespace string) bool {
+	_, err := s.getJobByName(ctx, tx, recorder, name, namespace)
+	return err == nil
+}
+
+// GetJobs returns all Jobs that match the provided query
+func (s *SQLJobStore) GetJobs(
+	ctx context.Context, query jobstore.JobQuery) (response *jobstore.JobQueryResponse, err error) {
+	scope := jobstore.AttrScopeAll
+	if query.Namespace != "" && !query.ReturnAll {
+		scope = jobstore.AttrScopeNamespace
+	}
+	attrs := []attribute.KeyValue{
+		jobstore.AttrScopeKey.String(scope),
+		jobstore.AttrNamespaceKey.String(query.Namespace),
+	}
+	if len(query.IncludeTags) > 0 {
+		attrs = append(attrs, attribute.Bool("query.include_tags", true))
+	}
+	if len(query.ExcludeTags) > 0 {
+		attrs = append(attrs, attribute.Bool("query.exclude_tags", true))
+	}
+	if query.Selector != nil {
+		attrs = append(attrs, attribute.Bool("query.selector", true))
+	}
+	recorder := s.metricRecorder(ctx, TableJobs, jobstore.AttrOperationList, attrs...)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		response, err = s.getJobs(ctx, tx, recorder, query)
+		return
+	})
+	return response, err
+}
+
+func (s *SQLJobStore) getJobs(ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder,
+	query jobstore.JobQuery) (*jobstore.JobQueryResponse, error) {
+	var orderBy string
+	switch query.SortBy {
+	case "created_at", "":
+		orderBy = "create_time"
+	case "modified_at":
+		orderBy = "modify_time"
+	default:
+		return nil, fmt.Errorf("OrderBy %s not supported for listJobs", query.SortBy)
+	}
+	if query.SortReverse {
+		orderBy += " DESC"
+	}
+
+	conditions := []string{"1 = 1"}
+	var args []any
+	if !query.ReturnAll && query.Namespace != "" {
+		conditions = append(conditions, "namespace = ?")
+		args = append(args, query.Namespace)
+	}
+	// jobs must have ANY of the included tags, and none of the excluded ones
+	if len(query.IncludeTags) > 0 {
+		conditions = append(conditions, fmt.Sprintf("id IN (SELECT job_id FROM job_tags WHERE tag IN (%s))",
+			sqldblib.Placeholders(len(query.IncludeTags))))
+		for _, tag := range query.IncludeTags {
+			args = append(args, strings.ToLower(tag))
+		}
+	}
+	if len(query.ExcludeTags) > 0 {
+		conditions = append(conditions, fmt.Sprintf("id NOT IN (SELECT job_id FROM job_tags WHERE tag IN (%s))",
+			sqldblib.Placeholders(len(query.ExcludeTags))))
+		for _, tag := range query.ExcludeTags {
+			args = append(args, strings.ToLower(tag))
+		}
+	}
+
+	specs, err := querySpecs(ctx, tx, fmt.Sprintf(`SELECT spec FROM jobs WHERE %s ORDER BY %s, id`,
+		strings.Join(conditions, " AND "), orderBy), args...)
+	if err != nil {
+		return nil, err
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartIndexRead)
+
+	var result []models.Job
+	for _, data := range specs {
+		var job models.Job
+		if err = s.unmarshal(ctx, recorder, data, &job); err != nil {
+			return nil, err
+		}
+		// If we have a selector, filter the results to only those that match
+		if query.Selector != nil && !query.Selector.Matches(labels.Set(job.Labels)) {
+			continue
+		}
+		result = append(result, job)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, "build_list")
+
+	jobs, more := getJobsWithinLimit(result, query)
+	recorder.Latency(ctx, jobstore.OperationPartDuration, "filter_limit")
+
+	response := &jobstore.JobQueryResponse{
+		Jobs:   jobs,
+		Offset: query.Offset,
+		Limit:  query.Limit,
+	}
+
+	if more {
+		response.NextOffset = query.Offset + uint64(query.Limit)
+	}
+
+	return response, nil
+}
+
+//nolint:gosec // G115: slice length is always non-negative in Go
+func getJobsWithinLimit(jobs []models.Job, query jobstore.JobQuery) ([]models.Job, bool) {
+	if query.Offset >= uint64(len(jobs)) {
+		return []models.Job{}, false
+	}
+
+	jobsFiltered := jobs[query.Offset:]
+	if query.Limit == 0 {
+		return jobsFiltered, false
+	}
+
+	limit := min(uint32(len(jobsFiltered)), query.Limit)
+	filteredLength := uint32(len(jobsFiltered))
+
+	jobsFiltered = jobsFiltered[:limit]
+
+	return jobsFiltered, filteredLength > query.Limit
+}
+
+// GetExecutions returns the executions that match the provided options
+func (s *SQLJobStore) GetExecutions(
+	ctx context.Context, options jobstore.GetExecutionsOptions) (state []models.Execution, err error) {
+	var attrs []attribute.KeyValue
+	if options.IncludeJob {
+		attrs = append(attrs, attribute.Bool("query.include_job", true))
+	}
+	recorder := s.metricRecorder(ctx, TableJobExecutions, jobstore.AttrOperationList, attrs...)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	if err = options.Validate(); err != nil {
+		recorder.Error(err)
+		return nil, err
+	}
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		state, err = s.getExecutions(ctx, tx, recorder, options)
+		return
+	})
+
+	return state, err
+}
+
+// GetInProgressJobs gets a list of the currently in-progress jobs, if a job type is supplied then
+// only jobs of that type will be retrieved
+func (s *SQLJobStore) GetInProgressJobs(ctx context.Context, jobType string) (jobs []models.Job, err error) {
+	attrs := []attribute.KeyValue{
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeInProgress),
+	}
+	if jobType != "" {
+		attrs = append(attrs, attribute.String("query.job_type", jobType))
+	}
+
+	recorder := s.metricRecorder(ctx, TableJobs, jobstore.AttrOperationList, attrs...)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		query := `SELECT spec FROM jobs WHERE in_progress = ?`
+		args := []any{true}
+		if jobType != "" {
+			query += ` AND type = ?`
+			args = append(args, jobType)
+		}
+		jobs, err = s.queryJobs(ctx, tx, recorder, query+` ORDER BY type, id`, args...)
+		return
+	})
+	return jobs, err
+}
+
+// GetInProgressJobsInNamespace gets a list of the currently in-progress jobs of the namespace
+func (s *SQLJobStore) GetInProgressJobsInNamespace(ctx context.Context, namespace string) (jobs []models.Job, err error) {
+	recorder := s.metricRecorder(ctx, TableJobs, jobstore.AttrOperationList,
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeInProgress), attribute.String("query.namespace", namespace))
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		jobs, err = s.queryJobs(ctx, tx, recorder,
+			`SELECT spec FROM jobs WHERE in_progress = ? AND namespace = ? ORDER BY type, id`, true, namespace)
+		return
+	})
+	return jobs, err
+}
+
+// GetScheduledJobs retrieves all jobs that run on a recurring schedule and have not been stopped.
+func (s *SQLJobStore) GetScheduledJobs(ctx context.Context) (jobs []models.Job, err error) {
+	recorder := s.metricRecorder(ctx, TableJobs, jobstore.AttrOperationList,
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeScheduled))
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		jobs, err = s.queryJobs(ctx, tx, recorder, `SELECT spec FROM jobs WHERE scheduled = ? ORDER BY id`, true)
+		return
+	})
+	return jobs, err
+}
+
+// GetDependentJobs retrieves the in progress jobs that depend on the job with the given ID.
+func (s *SQLJobStore) GetDependentJobs(ctx context.Context, jobID string) (jobs []models.Job, err error) {
+	recorder := s.metricRecorder(ctx, TableJobs, jobstore.AttrOperationList,
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeDependents))
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		jobs, err = s.queryJobs(ctx, tx, recorder, `SELECT jobs.spec FROM jobs
+			JOIN job_dependencies ON job_dependencies.job_id = jobs.id
+			WHERE job_dependencies.upstream_id = ? AND jobs.in_progress = ? ORDER BY jobs.id`, jobID, true)
+		return
+	})
+	return jobs, err
+}
+
+// queryJobs returns the jobs whose specs are selected by the query
+func (s *SQLJobStore) queryJobs(ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder,
+	query string, args ...any) ([]models.Job, error) {
+	specs, err := querySpecs(ctx, tx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartIndexRead)
+
+	jobs := make([]models.Job, 0, len(specs))
+	for _, data := range specs {
+		var job models.Job
+		if err = s.unmarshal(ctx, recorder, data, &job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// GetJobHistory retrieves the paginated job history for a given job ID based on the specified query.
+//
+// It supports pagination by processing an offset and limit defined either in the query or via
+// a `NextToken`, with the same behavior as the BoltDB job store:
+//   - If the result set reaches the limit specified in the query, a new `NextToken` is generated.
+//   - If no records are found in the current query, but the job or execution is not in a terminal state,
+//     the same `NextToken` will be returned to indicate that more history might still be available in the future.
+//   - Pagination only stops when there are no more records to fetch *and* the job/execution is in a terminal state.
+func (s *SQLJobStore) GetJobHistory(ctx context.Context,
+	jobID string,
+	query jobstore.JobHistoryQuery,
+) (response *jobstore.JobHistoryQueryResponse, err error) {
+	recorder := s.metricRecorder(ctx, TableJobHistory, jobstore.AttrOperationList,
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeJob))
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		response, err = s.getJobHistory(ctx, tx, recorder, jobID, query)
+		return
+	})
+	return response, err
+}
+
+func (s *SQLJobStore) getJobHistory(ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder,
+	jobID string, query jobstore.JobHistoryQuery) (*jobstore.JobHistoryQueryResponse, error) {
+	jobID, err := s.reifyJobID(ctx, tx, recorder, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit, err := parseHistoryPaginationParams(query)
+	if err != nil {
+		return nil, err
+	}
+
+	history, lastSeq, more, err := s.readJobHistory(ctx, tx, recorder, jobID, offset, limit, query)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &jobstore.JobHistoryQueryResponse{
+		JobHistory: history,
+	}
+
+	// Determine if we should continue pagination
+	shouldContinue := more
+	if !shouldContinue {
+		shouldContinue, err = s.isHistoryOngoing(ctx, tx, recorder, jobID, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, "determine_pagination")
+
+	if shouldContinue {
+		newOffset := lastSeq + 1
+		if len(history) == 0 {
+			// If we didn't find any items, then we need to continue from the last offset
+			newOffset = offset
+		}
+		response.NextToken = models.NewPagingToken(&models.PagingTokenParams{
+			Offset: newOffset,
+			Limit:  query.Limit,
+		}).String()
+	}
+
+	return response, nil
+}
+
+// readJobHistory reads up to limit history items starting at offset that match the query.
+// It returns the items, the sequence number of the last one, and whether more items follow.
+func (s *SQLJobStore) readJobHistory(ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder,
+	jobID string, offset uint64, limit uint32, query jobstore.JobHistoryQuery,
+) (history []models.JobHistory, lastSeq uint64, more bool, err error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT seq_num, spec FROM job_history WHERE job_id = ? AND seq_num >= ? ORDER BY seq_num`, jobID, offset)
+	if err != nil {
+		return nil, 0, false, NewSQLDBError(err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	for rows.Next() {
+		var seqNum uint64
+		var data []byte
+		if err = rows.Scan(&seqNum, &data); err != nil {
+			return nil, 0, false, NewSQLDBError(err)
+		}
+
+		var item models.JobHistory
+		if err = s.unmarshal(ctx, recorder, data, &item); err != nil {
+			return nil, 0, false, err
+		}
+
+		if filterHistoryItem(item, query) {
+			history = append(history, item)
+			lastSeq = seqNum
+		}
+
+		//nolint:gosec // G115: history within reasonable bounds
+		if uint32(len(history)) == limit {
+			more = rows.Next()
+			break
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, false, NewSQLDBError(err)
+	}
+	return history, lastSeq, more, nil
+}
+
+// isHistoryOngoing returns true if more history can still be added to the job or execution
+// being queried, which is when they are not in a terminal state
+func (s *SQLJobStore) isHistoryOngoing(ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder,
+	jobID string, query jobstore.JobHistoryQuery) (bool, error) {
+	// For execution level events, stop if the execution in terminal state
+	if query.ExecutionID != "" {
+		execution, err := s.getExecution(ctx, tx, recorder, query.ExecutionID)
+		if err != nil {
+			return false, err
+		}
+		return !execution.IsTerminalState(), nil
+	}
+
+	// If querying all executions or job level events, stop if the job is in terminal state
+	job, err := s.getJob(ctx, tx, recorder, jobID)
+	if err != nil {
+		return false, err
+	}
+	return !job.IsTerminal(), nil
+}
+
+func parseHistoryPaginationParams(query jobstore.JobHistoryQuery) (uint64, uint32, error) {
+	const defaultTokenLimit = 100
+	offset := uint64(0)
+	limit := uint32(defaultTokenLimit)
+
+	if query.NextToken != "" {
+		token, err := models.NewPagingTokenFromString(query.NextToken)
+		if err != nil {
+			return 0, 0, jobstore.NewBadRequestError(fmt.Sprintf("invalid next token: %s", err))
+		}
+		offset = token.Offset
+		if token.Limit != 0 {
+			limit = token.Limit
+		}
+	}
+
+	if query.Limit != 0 {
+		limit = query.Limit
+	}
+
+	return offset, limit, nil
+}
+
+// filterHistoryItem filter out anything before the specified Since time,
+// and anything that doesn't match the specified ExecutionID
+func filterHistoryItem(item models.JobHistory, query jobstore.JobHistoryQuery) bool {
+	if query.ExecutionID != "" && !strings.HasPrefix(item.ExecutionID, query.ExecutionID) {
+		return false
+	}
+	if item.Time.Unix() < query.Since {
+		return false
+	}
+	if query.ExcludeJobLevel && item.Type == models.JobHistoryTypeJobLevel {
+		return false
+	}
+	if query.ExcludeExecutionLevel && item.Type == models.JobHistoryTypeExecutionLevel {
+		return false
+	}
+	// If the requested job version is zero, return only the latest version items
+	if query.JobVersion == 0 {
+		// We explicitly are including all job versions
+		if query.AllJobVersions {
+			return true
+		}
+
+		// Only include latest job version
+		return item.JobVersion == query.LatestJobVersion
+	}
+
+	return item.JobVersion == query.JobVersion
+}
+
+// CreateJob creates a new record of a job in the data store
+func (s *SQLJobStore) CreateJob(ctx context.Context, job models.Job) (err error) {
+	recorder := s.metricRecorder(ctx, TableJobs, jobstore.AttrOperationCreate)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	job.State = models.NewJobState(models.JobStateTypePending)
+	job.Revision = 1
+	job.Version = 1
+	job.CreateTime = s.clock.Now().UTC().UnixNano()
+	job.ModifyTime = s.clock.Now().UTC().UnixNano()
+	job.Normalize()
+	err = job.Validate()
+	if err != nil {
+		return jobstore.NewJobStoreError(err.Error())
+	}
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		return s.createJob(ctx, tx, recorder, job)
+	})
+}
+
+func (s *SQLJobStore) createJob(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, job models.Job) error {
+	if s.jobExists(ctx, tx, recorder, job.ID) {
+		return jobstore.NewErrJobAlreadyExists(job.ID)
+	}
+
+	// Check if a job with this name already exists in the namespace
+	if s.jobExistsByName(ctx, tx, recorder, job.Name, job.Namespace) {
+		return jobstore.NewErrJobNameAlreadyExists(job.Name, job.Namespace)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartValidate)
+
+	if err := s.insertJob(ctx, tx, recorder, job); err != nil {
+		return err
+	}
+
+	return s.storeJobEvent(ctx, tx, recorder, watcher.OperationCreate, models.JobUpsert{Current: &job})
+}
+
+// insertJob writes a new job along with its current version and tags
+func (s *SQLJobStore) insertJob(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, job models.Job) error {
+	jobData, err := s.marshal(ctx, recorder, job)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO jobs
+		(id, name, namespace, type, version, in_progress, scheduled, create_time, modify_time, spec)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		job.ID, job.Name, job.Namespace, job.Type, job.Version, !job.IsTerminal(),
+		job.IsScheduled() && job.State.StateType != models.JobStateTypeStopped,
+		job.CreateTime, job.ModifyTime, jobData)
+	if err != nil {
+		return NewSQLDBError(err)
+	}
+
+	if err = s.putJobVersion(ctx, tx, job.ID, job.Version, jobData); err != nil {
+		return err
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartWrite)
+
+	if err = s.putJobTags(ctx, tx, job); err != nil {
+		return err
+	}
+	if err = s.putJobDependencies(ctx, tx, job); err != nil {
+		return err
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartIndexWrite)
+	return nil
+}
+
+// putJobVersion creates or replaces a version of a job
+func (s *SQLJobStore) putJobVersion(
+	ctx context.Context, tx *sqldblib.Tx, jobID string, version uint64, jobData string) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO job_versions (job_id, version, spec) VALUES (?, ?, ?)
+		ON CONFLICT (job_id, version) DO UPDATE SET spec = excluded.spec`, jobID, version, jobData)
+	if err != nil {
+		return NewSQLDBError(err)
+	}
+	return nil
+}
+
+// putJobTags replaces the tags of a job with its current labels
+func (s *SQLJobStore) putJobTags(ctx context.Context, tx *sqldblib.Tx, job models.Job) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM job_tags WHERE job_id = ?`, job.ID); err != nil {
+		return NewSQLDBError(err)
+	}
+	for tag := range job.Labels {
+		_, err := tx.ExecContext(ctx, `INSERT INTO job_tags (job_id, tag) VALUES (?, ?)
+			ON CONFLICT (job_id, tag) DO NOTHING`, job.ID, strings.ToLower(tag))
+		if err != nil {
+			return NewSQLDBError(err)
+		}
+	}
+	return nil
+}
+
+// putJobDependencies replaces the upstream jobs a job depends on with its current dependencies
+func (s *SQLJobStore) putJobDependencies(ctx context.Context, tx *sqldblib.Tx, job models.Job) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM job_dependencies WHERE job_id = ?`, job.ID); err != nil {
+		return NewSQLDBError(err)
+	}
+	for _, upstreamID := range job.DependsOn {
+		_, err := tx.ExecContext(ctx, `INSERT INTO job_dependencies (job_id, upstream_id) VALUES (?, ?)
+			ON CONFLICT (job_id, upstream_id) DO NOTHING`, job.ID, upstreamID)
+		if err != nil {
+			return NewSQLDBError(err)
+		}
+	}
+	return nil
+}
+
+// DeleteJob removes the specified job from the system entirely
+func (s *SQLJobStore) DeleteJob(ctx context.Context, jobID string) (err error) {
+	recorder := s.metricRecorder(ctx, TableJobs, jobstore.AttrOperationDelete)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		return s.deleteJob(ctx, tx, recorder, jobID)
+	})
+}
+
+func (s *SQLJobStore) deleteJob(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, jobID string) error {
+	job, err := s.getJob(ctx, tx, recorder, jobID)
+	if err != nil {
+		if bacerrors.IsError(err) {
+			return err
+		}
+		return NewSQLDBError(err)
+	}
+
+	// the job's versions, tags, executions, evaluations and history are deleted along with it
+	if _, err = tx.ExecContext(ctx, `DELETE FROM jobs WHERE id = ?`, job.ID); err != nil {
+		return NewSQLDBError(err)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartDelete)
+
+	return s.storeJobEvent(ctx, tx, recorder, watcher.OperationDelete, models.JobUpsert{Previous: &job})
+}
+
+// UpdateJob updates an existing job in the data store
+// Only specific fields are updated, and the current job is saved as a new version,
+// and the job state is updated to pending.
+func (s *SQLJobStore) UpdateJob(ctx context.Context, job models.Job) (err error) {
+	recorder := s.metricRecorder(ctx, TableJobs, jobstore.AttrOperationUpdate)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	// Ensure the job has a valid ID
+	if job.ID == "" {
+		return jobstore.NewJobStoreError("cannot update job without an ID")
+	}
+
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		return s.updateJob(ctx, tx, recorder, job)
+	})
+}
+
+func (s *SQLJobStore) updateJob(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, updatedJob models.Job) error {
+	// Get the existing job
+	existingJob, err := s.getJob(ctx, tx, recorder, updatedJob.ID)
+	if err != nil {
+		return err
+	}
+
+	if updatedJob.Name != existingJob.Name || updatedJob.Namespace != existingJob.Namespace {
+		return jobstore.NewJobStoreError("cannot change job name or namespace during update")
+	}
+
+	// Update only the specified fields
+	previousJob := existingJob.Copy()
+	existingJob.Priority = updatedJob.Priority
+	existingJob.Count = updatedJob.Count
+	existingJob.State = models.NewJobState(models.JobStateTypePending)
+	existingJob.Constraints = updatedJob.Constraints
+	existingJob.Meta = updatedJob.Meta
+	existingJob.Labels = updatedJob.Labels
+	existingJob.Tasks = updatedJob.Tasks
+	existingJob.DependsOn = updatedJob.DependsOn
+	existingJob.Schedule = updatedJob.Schedule
+	existingJob.Webhooks = updatedJob.Webhooks
+
+	// Increment version and update modification time
+	existingJob.Version++
+	existingJob.ModifyTime = s.clock.Now().UTC().UnixNano()
+
+	// Normalize and validate the updated job
+	existingJob.Normalize()
+	if err = existingJob.Validate(); err != nil {
+		return jobstore.NewJobStoreError(err.Error())
+	}
+
+	updatedJobData, err := s.marshal(ctx, recorder, existingJob)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE jobs SET version = ?, in_progress = ?, scheduled = ?, modify_time = ?, spec = ?
+		WHERE id = ?`,
+		existingJob.Version, true, existingJob.IsScheduled(), existingJob.ModifyTime, updatedJobData, existingJob.ID)
+	if err != nil {
+		return NewSQLDBError(err)
+	}
+
+	// Store the new version, keeping the previous ones
+	if err = s.putJobVersion(ctx, tx, existingJob.ID, existingJob.Version, updatedJobData); err != nil {
+		return err
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartWrite)
+
+	if err = s.putJobTags(ctx, tx, existingJob); err != nil {
+		return err
+	}
+	if err = s.putJobDependencies(ctx, tx, existingJob); err != nil {
+		return err
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartIndexWrite)
+
+	return s.storeJobEvent(ctx, tx, recorder, watcher.OperationUpdate,
+		models.JobUpsert{Current: &existingJob, Previous: previousJob})
+}
+
+// storeJobEvent records a job event in the event store as part of the transaction
+func (s *SQLJobStore) storeJobEvent(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, op watcher.Operation, upsert models.JobUpsert) error {
+	return s.storeEvent(ctx, tx, recorder, op, jobstore.EventObjectJobUpsert, upsert)
+}
+
+// storeEvent records an event in the event store as part of the transaction
+func (s *SQLJobStore) storeEvent(ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder,
+	op watcher.Operation, objectType string, object any) error {
+	if err := s.eventStore.StoreEventTx(ctx, tx, watcher.StoreEventRequest{
+		Operation:  op,
+		ObjectType: objectType,
+		Object:     object,
+	}); err != nil {
+		return err
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartEventWrite)
+	return nil
+}
+
+// UpdateJobState updates the current state for a single Job, appending an entry to
+// the history at the same time
+func (s *SQLJobStore) UpdateJobState(ctx context.Context, request jobstore.UpdateJobStateRequest) (err error) {
+	recorder := s.metricRecorder(ctx, TableJobs, jobstore.AttrOperationUpdate,
+		jobstore.AttrToStateKey.String(request.NewState.String()))
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		return s.updateJobState(ctx, tx, recorder, request)
+	})
+}
+
+func (s *SQLJobStore) updateJobState(ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder,
+	request jobstore.UpdateJobStateRequest) error {
+	job, err := s.getJob(ctx, tx, recorder, request.JobID)
+	if err != nil {
+		return err
+	}
+
+	// Add current state to metrics
+	recorder.AddAttributes(jobstore.AttrFromStateKey.String(job.State.StateType.String()))
+
+	// check the expected state
+	if err = request.Condition.Validate(job); err != nil {
+		return err
+	}
+
+	if job.IsTerminal() {
+		return jobstore.NewErrJobAlreadyTerminal(request.JobID, job.State.StateType, request.NewState)
+	}
+
+	// update the job state
+	// For state changes, we don't increment Version
+	previousJob := job.Copy()
+	job.State.StateType = request.NewState
+	job.State.Message = request.Message
+	job.Revision++
+	job.ModifyTime = s.clock.Now().UTC().UnixNano()
+
+	jobStateData, err := s.marshal(ctx, recorder, job)
+	if err != nil {
+		return err
+	}
+
+	// Stopping a scheduled job stops its future runs
+	_, err = tx.ExecContext(ctx, `UPDATE jobs SET in_progress = ?, scheduled = scheduled AND ?, modify_time = ?, spec = ?
+		WHERE id = ?`,
+		!job.IsTerminal(), job.State.StateType != models.JobStateTypeStopped, job.ModifyTime, jobStateData, job.ID)
+	if err != nil {
+		return NewSQLDBError(err)
+	}
+
+	// Update the current version
+	if err = s.putJobVersion(ctx, tx, job.ID, job.Version, jobStateData); err != nil {
+		return err
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartWrite)
+
+	if job.IsTerminal() {
+		tx.OnCommit(func() {
+			// TODO to include execution telemetry
+			analytics.Emit(analytics.NewJobTerminalEvent(job))
+		})
+	}
+
+	return s.storeJobEvent(ctx, tx, recorder, watcher.OperationUpdate,
+		models.JobUpsert{Current: &job, Previous: previousJob})
+}
+
+// AddJobHistory appends a new history entry to the job history
+func (s *SQLJobStore) AddJobHistory(ctx context.Context, jobID string, jobVersion uint64, events ...models.Event) (err error) {
+	recorder := s.metricRecorder(ctx, TableJobHistory, jobstore.AttrOperationCreate,
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeJob))
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		now := s.clock.Now().UTC()
+		for _, event := range events {
+			if err = s.addHistory(ctx, tx, recorder, models.JobHistory{
+				Type:       models.JobHistoryTypeJobLevel,
+				JobID:      jobID,
+				JobVersion: jobVersion,
+				Event:      event,
+				Time:       now,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AddExecutionHistory appends a new history entry to the execution history
+func (s *SQLJobStore) AddExecutionHistory(
+	ctx context.Context,
+	jobID string,
+	jobVersion uint64,
+	executionID string,
+	events ...models.Event,
+) (err error) {
+	recorder := s.metricRecorder(ctx, TableJobHistory, jobstore.AttrOperationCreate,
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeExecution))
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		eventsValues := make([]*models.Event, len(events))
+		for i := range events {
+			eventsValues[i] = &events[i]
+		}
+		return s.addExecutionHistory(ctx, tx, recorder, jobID, jobVersion, executionID, eventsValues...)
+	})
+}
+
+func (s *SQLJobStore) addExecutionHistory(ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder,
+	jobID string, jobVersion uint64, executionID string, events ...*models.Event) error {
+	now := s.clock.Now().UTC()
+	for _, event := range events {
+		if err := s.addHistory(ctx, tx, recorder, models.JobHistory{
+			Type:        models.JobHistoryTypeExecutionLevel,
+			JobID:       jobID,
+			JobVersion:  jobVersion,
+			ExecutionID: executionID,
+			Event:       *event,
+			Time:        now,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addHistory appends an entry to the history of a job, numbering it after the job's last entry
+func (s *SQLJobStore) addHistory(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, historyEntry models.JobHistory) error {
+	var exists bool
+	var seq uint64
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM jobs WHERE id = ?),
+		(SELECT COALESCE(MAX(seq_num), 0) FROM job_history WHERE job_id = ?)`,
+		historyEntry.JobID, historyEntry.JobID).Scan(&exists, &seq)
+	if err != nil {
+		return NewSQLDBError(err)
+	}
+	if !exists {
+		return jobstore.NewErrJobNotFound(historyEntry.JobID)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartSequence)
+
+	historyEntry.SeqNum = seq + 1
+	data, err := s.marshal(ctx, recorder, historyEntry)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO job_history (job_id, seq_num, spec) VALUES (?, ?, ?)`,
+		historyEntry.JobID, historyEntry.SeqNum, data)
+	if err != nil {
+		return NewSQLDBError(err)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartWrite)
+	return nil
+}
+
+// CreateExecution creates a record of a new execution
+func (s *SQLJobStore) CreateExecution(ctx context.Context, execution models.Execution) (err error) {
+	recorder := s.metricRecorder(ctx, TableJobExecutions, jobstore.AttrOperationCreate)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	if execution.CreateTime == 0 {
+		execution.CreateTime = s.clock.Now().UTC().UnixNano()
+	}
+	if execution.ModifyTime == 0 {
+		execution.ModifyTime = execution.CreateTime
+	}
+	if execution.Revision == 0 {
+		execution.Revision = 1
+	}
+	execution.Normalize()
+	err = execution.Validate()
+	if err != nil {
+		return err
+	}
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		return s.createExecution(ctx, tx, recorder, execution)
+	})
+}
+
+func (s *SQLJobStore) createExecution(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, execution models.Execution) error {
+	if !s.jobExists(ctx, tx, recorder, execution.JobID) {
+		return jobstore.NewErrJobNotFound(execution.JobID)
+	}
+
+	// Verify no duplicate execution
+	if _, err := s.getExecution(ctx, tx, recorder, execution.ID); err == nil {
+		return jobstore.NewErrExecutionAlreadyExists(execution.ID)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartValidate)
+
+	if err := s.insertExecution(ctx, tx, recorder, execution); err != nil {
+		return err
+	}
+
+	if err := s.storeEvent(ctx, tx, recorder, watcher.OperationCreate, jobstore.EventObjectExecutionUpsert,
+		models.ExecutionUpsert{Current: &execution}); err != nil {
+		return err
+	}
+
+	tx.OnCommit(func() {
+		analytics.Emit(analytics.NewCreatedExecutionEvent(execution))
+	})
+	return nil
+}
+
+// insertExecution writes a new execution
+func (s *SQLJobStore) insertExecution(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, execution models.Execution) error {
+	data, err := s.marshal(ctx, recorder, execution)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO executions (id, job_id, node_id, in_progress, spec) VALUES (?, ?, ?, ?, ?)`,
+		execution.ID, execution.JobID, execution.NodeID, !execution.IsTerminalState(), data)
+	if err != nil {
+		return NewSQLDBError(err)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartWrite)
+	return nil
+}
+
+// UpdateExecution updates the state of a single execution by loading from storage,
+// updating and then writing back in a single transaction
+func (s *SQLJobStore) UpdateExecution(ctx context.Context, request jobstore.UpdateExecutionRequest) (err error) {
+	recorder := s.metricRecorder(ctx, TableJobExecutions, jobstore.AttrOperationUpdate)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		return s.updateExecution(ctx, tx, recorder, request)
+	})
+}
+
+func (s *SQLJobStore) updateExecution(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, request jobstore.UpdateExecutionRequest) error {
+	// Get current execution
+	existingExecution, err := s.getExecution(ctx, tx, recorder, request.ExecutionID)
+	if err != nil {
+		return jobstore.NewErrExecutionNotFound(request.ExecutionID)
+	}
+
+	// Record state transitions in metrics
+	recorder.AddAttributes(
+		jobstore.FromDesiredStateKey.String(existingExecution.DesiredState.StateType.String()),
+		jobstore.ToDesiredStateKey.String(request.NewValues.DesiredState.StateType.String()),
+		jobstore.AttrFromStateKey.String(existingExecution.ComputeState.StateType.String()),
+		jobstore.AttrToStateKey.String(request.NewValues.ComputeState.StateType.String()),
+	)
+
+	// Validate state transition
+	if err = request.Condition.Validate(existingExecution); err != nil {
+		return err
+	}
+	if existingExecution.IsTerminalComputeState() {
+		return jobstore.NewErrExecutionAlreadyTerminal(
+			request.ExecutionID, existingExecution.ComputeState.StateType, request.NewValues.ComputeState.StateType)
+	}
+
+	// populate default values, maintain existing execution createTime
+	newExecution := request.NewValues
+	newExecution.CreateTime = existingExecution.CreateTime
+	if newExecution.ModifyTime == 0 {
+		newExecution.ModifyTime = s.clock.Now().UTC().UnixNano()
+	}
+	if newExecution.Revision == 0 {
+		newExecution.Revision = existingExecution.Revision + 1
+	}
+	newExecution.Normalize()
+
+	if err = mergo.Merge(&newExecution, existingExecution); err != nil {
+		return err
+	}
+
+	data, err := s.marshal(ctx, recorder, newExecution)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE executions SET node_id = ?, in_progress = ?, spec = ? WHERE id = ?`,
+		newExecution.NodeID, !newExecution.IsTerminalState(), data, newExecution.ID)
+	if err != nil {
+		return NewSQLDBError(err)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartWrite)
+
+	// Add execution history
+	if err = s.addExecutionHistory(
+		ctx,
+		tx,
+		recorder,
+		newExecution.JobID,
+		newExecution.JobVersion,
+		newExecution.ID,
+		request.Events...,
+	); err != nil {
+		return err
+	}
+
+	if err = s.storeEvent(ctx, tx, recorder, watcher.OperationUpdate, jobstore.EventObjectExecutionUpsert,
+		models.ExecutionUpsert{
+			Current: &newExecution, Previous: &existingExecution, Events: request.Events,
+		}); err != nil {
+		return err
+	}
+
+	tx.OnCommit(func() {
+		if newExecution.IsTerminalState() {
+			analytics.Emit(analytics.NewTerminalExecutionEvent(newExecution))
+		}
+		if newExecution.IsDiscarded() {
+			analytics.Emit(analytics.NewComputeMessageExecutionEvent(newExecution))
+		}
+	})
+
+	return nil
+}
+
+// CreateEvaluation creates a new evaluation
+func (s *SQLJobStore) CreateEvaluation(ctx context.Context, eval models.Evaluation) (err error) {
+	recorder := s.metricRecorder(ctx, TableJobEvaluations, jobstore.AttrOperationCreate)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		return s.createEvaluation(ctx, tx, recorder, eval)
+	})
+}
+
+func (s *SQLJobStore) createEvaluation(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, eval models.Evaluation) error {
+	job, err := s.getJob(ctx, tx, recorder, eval.JobID)
+	if err != nil {
+		return err
+	}
+
+	// If there is no error getting an eval with this ID, then it already exists
+	if _, err = s.getEvaluation(ctx, tx, recorder, eval.ID); err == nil {
+		return jobstore.NewErrEvaluationAlreadyExists(eval.ID)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartValidate)
+
+	data, err := s.marshal(ctx, recorder, eval)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO evaluations (id, job_id, spec) VALUES (?, ?, ?)`, eval.ID, job.ID, data)
+	if err != nil {
+		return NewSQLDBError(err)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartWrite)
+
+	return s.storeEvent(ctx, tx, recorder, watcher.OperationCreate, jobstore.EventObjectEvaluation, eval)
+}
+
+// GetEvaluation retrieves the specified evaluation
+func (s *SQLJobStore) GetEvaluation(ctx context.Context, id string) (eval models.Evaluation, err error) {
+	recorder := s.metricRecorder(ctx, TableJobEvaluations, jobstore.AttrOperationGet)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		eval, err = s.getEvaluation(ctx, tx, recorder, id)
+		return
+	})
+
+	return eval, err
+}
+
+func (s *SQLJobStore) getEvaluation(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, id string) (models.Evaluation, error) {
+	var eval models.Evaluation
+
+	data, err := querySpec(ctx, tx, `SELECT spec FROM evaluations WHERE id = ?`, id)
+	if err != nil {
+		return eval, err
+	}
+	if data == nil {
+		return eval, jobstore.NewErrEvaluationNotFound(id)
+	}
+
+	err = s.unmarshal(ctx, recorder, data, &eval)
+	return eval, err
+}
+
+// DeleteEvaluation deletes the specified evaluation
+func (s *SQLJobStore) DeleteEvaluation(ctx context.Context, id string) (err error) {
+	recorder := s.metricRecorder(ctx, TableJobEvaluations, jobstore.AttrOperationDelete)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		eval, err := s.getEvaluation(ctx, tx, recorder, id)
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.ExecContext(ctx, `DELETE FROM evaluations WHERE id = ?`, id); err != nil {
+			return NewSQLDBError(err)
+		}
+		recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartDelete)
+
+		return s.storeEvent(ctx, tx, recorder, watcher.OperationDelete, jobstore.EventObjectEvaluation, eval)
+	})
+}
+
+// GetEventStore returns the event store
+func (s *SQLJobStore) GetEventStore() watcher.EventStore {
+	return s.eventStore
+}
+
+func (s *SQLJobStore) Close(ctx context.Context) error {
+	log.Ctx(ctx).Debug().Msg("closing sql-backed job store")
+	var mErr error
+	mErr = errors.Join(mErr, s.eventStore.Close(ctx))
+	mErr = errors.Join(mErr, s.database.Close())
+	return mErr
+}
+
+// Static check to ensure that SQLJobStore implements jobstore.Store
+var _ jobstore.Store = (*SQLJobStore)(nil)
+
+// GetJobByName retrieves a Job identified by its name and namespace. If the job isn't found
+// it will return an error indicating that it was not found.
+func (s *SQLJobStore) GetJobByName(ctx context.Context, name, namespace string) (job models.Job, err error) {
+	recorder := s.metricRecorder(ctx, TableJobs, jobstore.AttrOperationGet,
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeJob),
+		attribute.String("name", name),
+		attribute.String("namespace", namespace))
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		job, err = s.getJobByName(ctx, tx, recorder, name, namespace)
+		return
+	})
+	return job, err
+}
+
+func (s *SQLJobStore) getJobByName(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, name, namespace string) (models.Job, error) {
+	var job models.Job
+
+	if namespace == "" {
+		namespace = models.DefaultNamespace
+	}
+
+	data, err := querySpec(ctx, tx, `SELECT spec FROM jobs WHERE namespace = ? AND name = ?`, namespace, name)
+	if err != nil {
+		return job, err
+	}
+	if data == nil {
+		return job, jobstore.NewErrJobNameIndexNotFound(fmt.Sprintf("%s:%s", name, namespace))
+	}
+
+	err = s.unmarshal(ctx, recorder, data, &job)
+	return job, err
+}
+
+// GetJobVersion retrieves a specific version of a job by its ID and version number
+func (s *SQLJobStore) GetJobVersion(ctx context.Context, jobID string, version uint64) (job models.Job, err error) {
+	recorder := s.metricRecorder(ctx, TableJobVersions, jobstore.AttrOperationGet,
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeJob),
+		attribute.Int64("version", int64(version))) //nolint:gosec // G115: version within reasonable bounds
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		job, err = s.getJobVersion(ctx, tx, recorder, jobID, version)
+		return
+	})
+	return job, err
+}
+
+func (s *SQLJobStore) getJobVersion(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, jobID string, version uint64) (models.Job, error) {
+	var job models.Job
+
+	jobID, err := s.reifyJobID(ctx, tx, recorder, jobID)
+	if err != nil {
+		return job, err
+	}
+
+	// versions are stored as signed integers, so larger ones cannot exist
+	if version > math.MaxInt64 {
+		return job, jobstore.NewErrJobVersionNotFound(jobID, version)
+	}
+
+	data, err := querySpec(ctx, tx, `SELECT spec FROM job_versions WHERE job_id = ? AND version = ?`, jobID, version)
+	if err != nil {
+		return job, err
+	}
+	if data == nil {
+		return job, jobstore.NewErrJobVersionNotFound(jobID, version)
+	}
+
+	err = s.unmarshal(ctx, recorder, data, &job)
+	return job, err
+}
+
+// GetJobVersions returns all available versions of a job
+func (s *SQLJobStore) GetJobVersions(ctx context.Context, jobID string) (versions []models.Job, err error) {
+	recorder := s.metricRecorder(ctx, TableJobVersions, jobstore.AttrOperationList,
+		jobstore.AttrScopeKey.String(jobstore.AttrScopeJob))
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		job, err := s.getJob(ctx, tx, recorder, jobID)
+		if err != nil {
+			return err
+		}
+		versions, err = s.queryJobs(ctx, tx, recorder,
+			`SELECT spec FROM job_versions WHERE job_id = ? ORDER BY version`, job.ID)
+		return err
+	})
+	return versions, err
+}
+
+// getLatestJobVersion retrieves the latest version of a job by its ID without loading the full job object.
+func (s *SQLJobStore) getLatestJobVersion(ctx context.Context, tx *sqldblib.Tx, jobID string) (uint64, error) {
+	var version uint64
+	err := tx.QueryRowContext(ctx, `SELECT version FROM jobs WHERE id = ?`, jobID).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, jobstore.NewErrJobNotFound(jobID)
+	}
+	if err != nil {
+		return 0, NewSQLDBError(err)
+	}
+	return version, nil
+}
+
+// GetJobByIDOrName retrieves a Job identified by either its name or ID.
+func (s *SQLJobStore) GetJobByIDOrName(ctx context.Context, idOrName, namespace string) (job models.Job, err error) {
+	// First try to get by name
+	job, err = s.GetJobByName(ctx, idOrName, namespace)
+	if err != nil {
+		job, err = s.GetJob(ctx, idOrName)
+	}
+	return job, err
+}
+
+// GetNamespaceQuota retrieves the quota of the specified namespace
+func (s *SQLJobStore) GetNamespaceQuota(ctx context.Context, namespace string) (quota models.NamespaceQuota, err error) {
+	recorder := s.metricRecorder(ctx, TableNamespaceQuotas, jobstore.AttrOperationGet)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		quota, err = s.getNamespaceQuota(ctx, tx, recorder, namespace)
+		return
+	})
+	return quota, err
+}
+
+func (s *SQLJobStore) getNamespaceQuota(ctx context.Context, tx *sqldblib.Tx,
+	recorder *telemetry.MetricRecorder, namespace string) (models.NamespaceQuota, error) {
+	var quota models.NamespaceQuota
+	data, err := querySpec(ctx, tx, `SELECT spec FROM namespace_quotas WHERE namespace = ?`, namespace)
+	if err != nil {
+		return quota, err
+	}
+	if data == nil {
+		return quota, jobstore.NewErrNamespaceQuotaNotFound(namespace)
+	}
+
+	err = s.unmarshal(ctx, recorder, data, &quota)
+	return quota, err
+}
+
+// GetNamespaceQuotas retrieves the quotas of all namespaces that have one, sorted by namespace
+func (s *SQLJobStore) GetNamespaceQuotas(ctx context.Context) (quotas []models.NamespaceQuota, err error) {
+	recorder := s.metricRecorder(ctx, TableNamespaceQuotas, jobstore.AttrOperationList)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	err = sqldblib.View(ctx, s.database, func(tx *sqldblib.Tx) error {
+		specs, err := querySpecs(ctx, tx, `SELECT spec FROM namespace_quotas ORDER BY namespace`)
+		if err != nil {
+			return err
+		}
+		for _, data := range specs {
+			var quota models.NamespaceQuota
+			if err = s.unmarshal(ctx, recorder, data, &quota); err != nil {
+				return err
+			}
+			quotas = append(quotas, quota)
+		}
+		return nil
+	})
+	return quotas, err
+}
+
+// PutNamespaceQuota creates or replaces the quota of a namespace
+func (s *SQLJobStore) PutNamespaceQuota(ctx context.Context, quota models.NamespaceQuota) (err error) {
+	recorder := s.metricRecorder(ctx, TableNamespaceQuotas, jobstore.AttrOperationUpdate)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		return s.putNamespaceQuota(ctx, tx, recorder, quota)
+	})
+}
+
+func (s *SQLJobStore) putNamespaceQuota(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, quota models.NamespaceQuota) error {
+	quota.Normalize()
+	if err := quota.Validate(); err != nil {
+		return jobstore.NewBadRequestError(err.Error())
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartValidate)
+
+	now := s.clock.Now().UTC().UnixNano()
+	quota.CreateTime = now
+	if existing, err := s.getNamespaceQuota(ctx, tx, recorder, quota.Namespace); err == nil {
+		quota.CreateTime = existing.CreateTime
+	}
+	quota.ModifyTime = now
+
+	return s.writeNamespaceQuota(ctx, tx, recorder, quota)
+}
+
+// writeNamespaceQuota creates or replaces the quota of a namespace as is
+func (s *SQLJobStore) writeNamespaceQuota(
+	ctx context.Context, tx *sqldblib.Tx, recorder *telemetry.MetricRecorder, quota models.NamespaceQuota) error {
+	data, err := s.marshal(ctx, recorder, quota)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO namespace_quotas (namespace, spec) VALUES (?, ?)
+		ON CONFLICT (namespace) DO UPDATE SET spec = excluded.spec`, quota.Namespace, data)
+	if err != nil {
+		return NewSQLDBError(err)
+	}
+	recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartWrite)
+	return nil
+}
+
+// DeleteNamespaceQuota deletes the quota of the specified namespace
+func (s *SQLJobStore) DeleteNamespaceQuota(ctx context.Context, namespace string) (err error) {
+	recorder := s.metricRecorder(ctx, TableNamespaceQuotas, jobstore.AttrOperationDelete)
+	defer recorder.Done(ctx, jobstore.OperationDuration)
+	defer recorder.Error(err)
+
+	return sqldblib.Update(ctx, s.database, func(tx *sqldblib.Tx) (err error) {
+		result, err := tx.ExecContext(ctx, `DELETE FROM namespace_quotas WHERE namespace = ?`, namespace)
+		if err != nil {
+			return NewSQLDBError(err)
+		}
+		if deleted, _ := result.RowsAffected(); deleted == 0 {
+			return jobstore.NewErrNamespaceQuotaNotFound(namespace)
+		}
+		recorder.Latency(ctx, jobstore.OperationPartDuration, jobstore.AttrOperationPartDelete)
+		return nil
+	})
+}
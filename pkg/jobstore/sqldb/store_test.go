@@ -0,0 +1,162 @@
+//go:build unit || !integration
+
+package sqljobstore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	boltjobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/boltdb"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore/test"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/sqldblib"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+func TestStore(t *testing.T) {
+	test.RunStoreSuite(t, func(ctx context.Context, dbPath string, clock clock.Clock) (jobstore.Store, error) {
+		db, err := sqldblib.OpenSQLite(filepath.Join(dbPath, "test.sqlite"))
+		if err != nil {
+			return nil, err
+		}
+		return NewSQLJobStore(db, WithClock(clock))
+	})
+}
+
+// SQLJobstoreTestSuite covers the SQL specific parts of the job store.
+// The behaviour shared by all job stores is covered by test.StoreSuite.
+type SQLJobstoreTestSuite struct {
+	suite.Suite
+	store *SQLJobStore
+	ctx   context.Context
+	clock *clock.Mock
+}
+
+func TestSQLJobstoreTestSuite(t *testing.T) {
+	suite.Run(t, new(SQLJobstoreTestSuite))
+}
+
+func (s *SQLJobstoreTestSuite) SetupTest() {
+	s.clock = clock.NewMock()
+	s.ctx = context.Background()
+
+	db, err := sqldblib.OpenSQLite(filepath.Join(s.T().TempDir(), "test.sqlite"))
+	s.Require().NoError(err)
+	s.store, err = NewSQLJobStore(db, WithClock(s.clock))
+	s.Require().NoError(err)
+}
+
+func (s *SQLJobstoreTestSuite) TearDownTest() {
+	s.Require().NoError(s.store.Close(s.ctx))
+}
+
+func (s *SQLJobstoreTestSuite) TestBeginMultipleTransactions_Sequential() {
+	txCtx1, err := s.store.BeginTx(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(txCtx1)
+	tx1, ok := sqldblib.TxFromContext(txCtx1)
+	s.Require().True(ok)
+	// commit to release the transaction
+	s.Require().NoError(txCtx1.Commit())
+
+	// start second transaction, even through tcCtx1
+	txCtx2, err := s.store.BeginTx(txCtx1)
+	s.Require().NoError(err)
+	s.Require().NotNil(txCtx2)
+	tx2, ok := sqldblib.TxFromContext(txCtx2)
+	s.Require().True(ok)
+	// commit to release the transaction
+	s.Require().NoError(txCtx2.Commit())
+
+	// assert that the two transactions were different
+	s.Require().NotEqual(txCtx1, txCtx2)
+	s.Require().NotSame(tx1, tx2)
+}
+
+func (s *SQLJobstoreTestSuite) TestReopenKeepsData() {
+	// the schema is only created if missing, so reopening the same file keeps existing jobs
+	dbPath := filepath.Join(s.T().TempDir(), "reopen.sqlite")
+	db, err := sqldblib.OpenSQLite(dbPath)
+	s.Require().NoError(err)
+	store, err := NewSQLJobStore(db, WithClock(s.clock))
+	s.Require().NoError(err)
+
+	job := mock.Job()
+	s.Require().NoError(store.CreateJob(s.ctx, *job))
+	s.Require().NoError(store.Close(s.ctx))
+
+	db, err = sqldblib.OpenSQLite(dbPath)
+	s.Require().NoError(err)
+	store, err = NewSQLJobStore(db, WithClock(s.clock))
+	s.Require().NoError(err)
+	defer store.Close(s.ctx)
+
+	stored, err := store.GetJobByName(s.ctx, job.Name, job.Namespace)
+	s.Require().NoError(err)
+	s.Equal(job.ID, stored.ID)
+}
+
+func (s *SQLJobstoreTestSuite) TestMigrateFromBoltDB() {
+	source, err := boltjobstore.NewBoltJobStore(
+		filepath.Join(s.T().TempDir(), "jobs.db"), boltjobstore.WithClock(s.clock))
+	s.Require().NoError(err)
+	defer source.Close(s.ctx)
+
+	// a job with two versions, an execution with history, an evaluation and a namespace quota
+	job := mock.Job()
+	s.Require().NoError(source.CreateJob(s.ctx, *job))
+	s.Require().NoError(source.UpdateJob(s.ctx, *job))
+	s.Require().NoError(source.AddJobHistory(s.ctx, job.ID, 2, *models.NewEvent("test").WithMessage("updated")))
+
+	execution := mock.ExecutionForJob(job)
+	execution.JobVersion = 2
+	s.Require().NoError(source.CreateExecution(s.ctx, *execution))
+	s.Require().NoError(source.AddExecutionHistory(s.ctx, job.ID, 2, execution.ID, *models.NewEvent("test")))
+
+	eval := mock.EvalForJob(job)
+	s.Require().NoError(source.CreateEvaluation(s.ctx, *eval))
+	s.Require().NoError(source.PutNamespaceQuota(s.ctx, models.NamespaceQuota{
+		Namespace: job.Namespace, MaxQueuedJobs: 10}))
+
+	result, err := jobstore.Migrate(s.ctx, source, s.store)
+	s.Require().NoError(err)
+	s.Equal(jobstore.MigrationResult{Jobs: 1, NamespaceQuotas: 1}, result)
+
+	migratedJob, err := s.store.GetJob(s.ctx, job.ID)
+	s.Require().NoError(err)
+	sourceJob, err := source.GetJob(s.ctx, job.ID)
+	s.Require().NoError(err)
+	s.Equal(sourceJob, migratedJob)
+
+	versions, err := s.store.GetJobVersions(s.ctx, job.ID)
+	s.Require().NoError(err)
+	s.Len(versions, 2)
+
+	executions, err := s.store.GetExecutions(s.ctx, jobstore.GetExecutionsOptions{JobID: job.ID})
+	s.Require().NoError(err)
+	s.Require().Len(executions, 1)
+	s.Equal(execution.ID, executions[0].ID)
+
+	_, err = s.store.GetEvaluation(s.ctx, eval.ID)
+	s.Require().NoError(err)
+
+	sourceHistory, err := source.GetJobHistory(s.ctx, job.ID, jobstore.JobHistoryQuery{AllJobVersions: true})
+	s.Require().NoError(err)
+	history, err := s.store.GetJobHistory(s.ctx, job.ID, jobstore.JobHistoryQuery{AllJobVersions: true})
+	s.Require().NoError(err)
+	s.Equal(sourceHistory.JobHistory, history.JobHistory)
+
+	quota, err := s.store.GetNamespaceQuota(s.ctx, job.Namespace)
+	s.Require().NoError(err)
+	s.Equal(10, quota.MaxQueuedJobs)
+
+	// migrating again skips the jobs that were already copied
+	result, err = jobstore.Migrate(s.ctx, source, s.store)
+	s.Require().NoError(err)
+	s.Equal(jobstore.MigrationResult{SkippedJobs: 1, NamespaceQuotas: 1}, result)
+}
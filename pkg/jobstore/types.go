@@ -91,6 +91,18 @@ type Store interface {
 	// is provided, only active jobs of that type will be returned.
 	GetInProgressJobs(ctx context.Context, jobType string) ([]models.Job, error)
 
+	// GetInProgressJobsInNamespace retrieves the jobs of the namespace that
+	// are considered 'in progress'. Failure generates an error.
+	GetInProgressJobsInNamespace(ctx context.Context, namespace string) ([]models.Job, error)
+
+	// GetScheduledJobs retrieves all jobs that run on a recurring schedule,
+	// regardless of the state of their latest run, unless they have been stopped.
+	GetScheduledJobs(ctx context.Context) ([]models.Job, error)
+
+	// GetDependentJobs retrieves the in progress jobs that depend on the
+	// job with the given ID.
+	GetDependentJobs(ctx context.Context, jobID string) ([]models.Job, error)
+
 	// GetJobHistory retrieves the history for the specified job.  The
 	// history returned is filtered by the contents of the provided
 	// [JobHistoryFilterOptions].
@@ -137,6 +149,18 @@ type Store interface {
 	// DeleteEvaluation deletes the specified evaluation
 	DeleteEvaluation(ctx context.Context, id string) error
 
+	// GetNamespaceQuota retrieves the quota of the specified namespace
+	GetNamespaceQuota(ctx context.Context, namespace string) (models.NamespaceQuota, error)
+
+	// GetNamespaceQuotas retrieves the quotas of all namespaces that have one
+	GetNamespaceQuotas(ctx context.Context) ([]models.NamespaceQuota, error)
+
+	// PutNamespaceQuota creates or replaces the quota of a namespace
+	PutNamespaceQuota(ctx context.Context, quota models.NamespaceQuota) error
+
+	// DeleteNamespaceQuota deletes the quota of the specified namespace
+	DeleteNamespaceQuota(ctx context.Context, namespace string) error
+
 	// GetEventStore returns the event store for the execution store
 	GetEventStore() watcher.EventStore
 
@@ -0,0 +1,36 @@
+package jobstore
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// AggregateResourceUsage aggregates the resource usage reported by executions per job version,
+// ordered from the oldest version to the latest. Versions without executions reporting
+// their usage are left out.
+func AggregateResourceUsage(executions []models.Execution) []models.JobResourceUsage {
+	byVersion := make(map[uint64]*models.JobResourceUsage)
+	for i := range executions {
+		usage := executions[i].ResourceUsage
+		if usage.IsZero() {
+			continue
+		}
+		aggregate, ok := byVersion[executions[i].JobVersion]
+		if !ok {
+			aggregate = &models.JobResourceUsage{JobVersion: executions[i].JobVersion}
+			byVersion[executions[i].JobVersion] = aggregate
+		}
+		aggregate.Add(usage)
+	}
+
+	aggregates := make([]models.JobResourceUsage, 0, len(byVersion))
+	for _, aggregate := range byVersion {
+		aggregates = append(aggregates, *aggregate)
+	}
+	slices.SortFunc(aggregates, func(a, b models.JobResourceUsage) int {
+		return cmp.Compare(a.JobVersion, b.JobVersion)
+	})
+	return aggregates
+}
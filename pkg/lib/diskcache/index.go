@@ -0,0 +1,254 @@
+// Package diskcache implements the index of caches storing their entries on disk. The index records the size
+// and the last use of each entry, and evicts the least recently used entries to keep the total size of the
+// cache within its limit.
+package diskcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
+)
+
+// IndexParams configures an Index
+type IndexParams[T any] struct {
+	// Name describes the cache in errors and logs, such as "input cache"
+	Name string
+	// Path is the file the index is persisted to
+	Path string
+	// MaxSize is the maximum total size in bytes of the entries. Zero means no limit.
+	MaxSize uint64
+	// Remove deletes the content of an entry evicted from the cache
+	Remove func(ctx context.Context, key string, value T)
+	// SizeMetric, if set, records the total size of the entries
+	SizeMetric metric.Int64UpDownCounter
+	// EvictionsMetric, if set, counts the evicted entries
+	EvictionsMetric *telemetry.Counter
+}
+
+// Entry is an entry of the cache
+type Entry[T any] struct {
+	// Value describes the content of the entry, such as the files it is stored in
+	Value T `json:"Value"`
+	// Size is the size in bytes of the content of the entry
+	Size uint64 `json:"Size"`
+	// LastUsed is when the entry was last used, which drives the eviction of entries
+	LastUsed time.Time `json:"LastUsed"`
+	// pins is the number of users of the entry, which prevent its eviction
+	pins int
+}
+
+// Index is the index of the entries of a cache stored on disk, addressed by key. It is safe for concurrent use.
+//
+// Additions and evictions of entries are persisted right away, so that the index matches the content stored on
+// disk. Uses of entries are only persisted with the next change of the entries or with Save, as losing them
+// only affects the order of evictions after a restart.
+type Index[T any] struct {
+	name            string
+	path            string
+	maxSize         uint64
+	remove          func(ctx context.Context, key string, value T)
+	sizeMetric      metric.Int64UpDownCounter
+	evictionsMetric *telemetry.Counter
+
+	mu      sync.Mutex
+	entries map[string]*Entry[T]
+	size    uint64
+	dirty   bool
+}
+
+// NewIndex creates an empty index, which Load fills with the entries of a previous run
+func NewIndex[T any](params IndexParams[T]) *Index[T] {
+	return &Index[T]{
+		name:            params.Name,
+		path:            params.Path,
+		maxSize:         params.MaxSize,
+		remove:          params.Remove,
+		sizeMetric:      params.SizeMetric,
+		evictionsMetric: params.EvictionsMetric,
+		entries:         make(map[string]*Entry[T]),
+	}
+}
+
+// Load loads the entries persisted by a previous run, keeping those for which valid returns true, such as
+// the entries whose content is still on disk. A corrupted index is discarded. The cache is then evicted
+// down to its size limit.
+func (i *Index[T]) Load(ctx context.Context, valid func(key string, entry Entry[T]) bool) error {
+	data, err := os.ReadFile(i.path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read the index of the %s: %w", i.name, err)
+	}
+	var entries map[string]*Entry[T]
+	if len(data) > 0 {
+		if err = json.Unmarshal(data, &entries); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("discarding the corrupted index of the %s", i.name)
+			entries = nil
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for key, entry := range entries {
+		if entry != nil && valid(key, *entry) {
+			i.entries[key] = entry
+			i.size += entry.Size
+		}
+	}
+	i.recordSize(ctx, int64(i.size)) //nolint:gosec // sizes of cached files fit in int64
+	i.evict(ctx, "")
+	i.save(ctx)
+	return nil
+}
+
+// Has returns whether the key has an entry
+func (i *Index[T]) Has(key string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	_, ok := i.entries[key]
+	return ok
+}
+
+// Use returns the entry of the key, if any, and records its use
+func (i *Index[T]) Use(key string) (Entry[T], bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	entry, ok := i.entries[key]
+	if !ok {
+		return Entry[T]{}, false
+	}
+	entry.LastUsed = time.Now()
+	i.dirty = true
+	return *entry, true
+}
+
+// Pin records the use of the entry of the key and prevents its eviction until Unpin is called.
+// It returns false if the key has no entry.
+func (i *Index[T]) Pin(key string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	entry, ok := i.entries[key]
+	if !ok {
+		return false
+	}
+	entry.LastUsed = time.Now()
+	entry.pins++
+	i.dirty = true
+	return true
+}
+
+// Unpin releases a pin of the entry of the key taken by Pin
+func (i *Index[T]) Unpin(key string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if entry, ok := i.entries[key]; ok && entry.pins > 0 {
+		entry.pins--
+	}
+}
+
+// Add adds an entry for the key, and evicts the least recently used entries beyond the size limit of the cache
+func (i *Index[T]) Add(ctx context.Context, key string, value T, size uint64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	entry := &Entry[T]{Value: value, Size: size, LastUsed: time.Now()}
+	if previous, ok := i.entries[key]; ok {
+		entry.pins = previous.pins
+		i.size -= previous.Size
+		i.recordSize(ctx, -int64(previous.Size)) //nolint:gosec // sizes of cached files fit in int64
+	}
+	i.entries[key] = entry
+	i.size += size
+	i.recordSize(ctx, int64(size)) //nolint:gosec // sizes of cached files fit in int64
+	i.evict(ctx, key)
+	i.save(ctx)
+}
+
+// Entries returns a copy of the entries by key
+func (i *Index[T]) Entries() map[string]Entry[T] {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	entries := make(map[string]Entry[T], len(i.entries))
+	for key, entry := range i.entries {
+		entries[key] = *entry
+	}
+	return entries
+}
+
+// Size returns the total size in bytes of the entries
+func (i *Index[T]) Size() uint64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.size
+}
+
+// Save persists the uses of the entries recorded since the index was last persisted
+func (i *Index[T]) Save(ctx context.Context) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.dirty {
+		i.save(ctx)
+	}
+}
+
+// evict removes the least recently used entries that are not pinned, except the given one,
+// until the size of the cache is within its limit. It must be called with the lock held.
+func (i *Index[T]) evict(ctx context.Context, keep string) {
+	if i.maxSize == 0 || i.size <= i.maxSize {
+		return
+	}
+	keys := make([]string, 0, len(i.entries))
+	for key, entry := range i.entries {
+		if key != keep && entry.pins == 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		return i.entries[keys[a]].LastUsed.Before(i.entries[keys[b]].LastUsed)
+	})
+	for _, key := range keys {
+		if i.size <= i.maxSize {
+			return
+		}
+		entry := i.entries[key]
+		i.remove(ctx, key, entry.Value)
+		delete(i.entries, key)
+		i.size -= entry.Size
+		i.recordSize(ctx, -int64(entry.Size)) //nolint:gosec // sizes of cached files fit in int64
+		if i.evictionsMetric != nil {
+			i.evictionsMetric.Inc(ctx)
+		}
+	}
+}
+
+// recordSize records a change of the total size of the entries
+func (i *Index[T]) recordSize(ctx context.Context, delta int64) {
+	if i.sizeMetric != nil {
+		i.sizeMetric.Add(ctx, delta)
+	}
+}
+
+// save writes the entries to the index file. Failures are only logged, as they only cause the entries
+// added since the last successful write to be lost after a restart. It must be called with the lock held.
+func (i *Index[T]) save(ctx context.Context) {
+	data, err := json.Marshal(i.entries)
+	if err == nil {
+		tmpPath := i.path + ".tmp"
+		if err = os.WriteFile(tmpPath, data, 0o600); err == nil {
+			err = os.Rename(tmpPath, i.path)
+		}
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to save the index of the %s", i.name)
+		return
+	}
+	i.dirty = false
+}
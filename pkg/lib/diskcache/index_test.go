@@ -0,0 +1,109 @@
+//go:build unit || !integration
+
+package diskcache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type IndexSuite struct {
+	suite.Suite
+	ctx     context.Context
+	path    string
+	removed []string
+}
+
+func TestIndexSuite(t *testing.T) {
+	suite.Run(t, new(IndexSuite))
+}
+
+func (s *IndexSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.path = filepath.Join(s.T().TempDir(), "index.json")
+	s.removed = nil
+}
+
+func (s *IndexSuite) newIndex(maxSize uint64) *Index[string] {
+	index := NewIndex(IndexParams[string]{
+		Name:    "test cache",
+		Path:    s.path,
+		MaxSize: maxSize,
+		Remove: func(_ context.Context, key string, _ string) {
+			s.removed = append(s.removed, key)
+		},
+	})
+	s.Require().NoError(index.Load(s.ctx, func(string, Entry[string]) bool { return true }))
+	return index
+}
+
+func (s *IndexSuite) TestEvictsLeastRecentlyUsedEntries() {
+	index := s.newIndex(10)
+	index.Add(s.ctx, "a", "value-a", 5)
+	index.Add(s.ctx, "b", "value-b", 5)
+	// using a makes b the least recently used entry
+	_, ok := index.Use("a")
+	s.Require().True(ok)
+	index.Add(s.ctx, "c", "value-c", 5)
+
+	s.Equal([]string{"b"}, s.removed)
+	s.True(index.Has("a"))
+	s.True(index.Has("c"))
+	s.Equal(uint64(10), index.Size())
+}
+
+func (s *IndexSuite) TestPinnedEntriesAreNotEvicted() {
+	index := s.newIndex(5)
+	index.Add(s.ctx, "a", "value-a", 5)
+	s.Require().True(index.Pin("a"))
+	index.Add(s.ctx, "b", "value-b", 5)
+	s.Empty(s.removed)
+
+	index.Unpin("a")
+	index.Add(s.ctx, "c", "value-c", 5)
+	s.ElementsMatch([]string{"a", "b"}, s.removed)
+	s.False(index.Pin("a"))
+}
+
+func (s *IndexSuite) TestPersistsUsesLazily() {
+	index := s.newIndex(0)
+	index.Add(s.ctx, "a", "value-a", 5)
+	saved, err := os.ReadFile(s.path)
+	s.Require().NoError(err)
+
+	// uses are only persisted by Save
+	_, ok := index.Use("a")
+	s.Require().True(ok)
+	data, err := os.ReadFile(s.path)
+	s.Require().NoError(err)
+	s.Equal(saved, data)
+
+	index.Save(s.ctx)
+	data, err = os.ReadFile(s.path)
+	s.Require().NoError(err)
+	s.NotEqual(saved, data)
+}
+
+func (s *IndexSuite) TestLoadsValidEntries() {
+	index := s.newIndex(0)
+	index.Add(s.ctx, "a", "value-a", 5)
+	index.Add(s.ctx, "b", "value-b", 3)
+
+	reloaded := NewIndex(IndexParams[string]{Path: s.path})
+	s.Require().NoError(reloaded.Load(s.ctx, func(key string, entry Entry[string]) bool {
+		return key == "a" && entry.Value == "value-a"
+	}))
+	s.True(reloaded.Has("a"))
+	s.False(reloaded.Has("b"))
+	s.Equal(uint64(5), reloaded.Size())
+}
+
+func (s *IndexSuite) TestDiscardsCorruptedIndex() {
+	s.Require().NoError(os.WriteFile(s.path, []byte("{not json"), 0o600))
+	index := s.newIndex(0)
+	s.Empty(index.Entries())
+}
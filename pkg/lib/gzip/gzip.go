@@ -13,10 +13,10 @@ import (
 const DefaultMaxDecompressSize = 100 * 1024 * 1024 * 1024 // 100 GB
 
 // Compress compresses the sourcePath (which can be a file or a directory)
-// into a gzip archive written to targetFile. It uses relative paths for
+// into a gzip archive written to target. It uses relative paths for
 // the file headers within the archive to preserve the directory structure.
-func Compress(sourcePath string, targetFile *os.File) error {
-	gw := gzip.NewWriter(targetFile)
+func Compress(sourcePath string, target io.Writer) error {
+	gw := gzip.NewWriter(target)
 	defer func() { _ = gw.Close() }()
 
 	tarWriter := tar.NewWriter(gw)
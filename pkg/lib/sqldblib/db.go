@@ -0,0 +1,179 @@
+package sqldblib
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	// register the pure Go drivers of the supported dialects
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+)
+
+const component = "SQLDB"
+
+// Dialect is the SQL dialect spoken by a database
+type Dialect string
+
+const (
+	// DialectSQLite is an embedded SQLite database file
+	DialectSQLite Dialect = "sqlite"
+	// DialectPostgres is a PostgreSQL server
+	DialectPostgres Dialect = "postgres"
+)
+
+// writeLockKey is the key of the PostgreSQL advisory lock held by writable transactions
+const writeLockKey = 0x62616331
+
+// sqliteBusyTimeout is how long SQLite waits for locks held by other processes, in milliseconds
+const sqliteBusyTimeout = 5000
+
+// driverName returns the name of the database/sql driver of the dialect
+func (d Dialect) driverName() (string, error) {
+	switch d {
+	case DialectSQLite:
+		return "sqlite", nil
+	case DialectPostgres:
+		return "pgx", nil
+	default:
+		return "", fmt.Errorf("unsupported SQL dialect %q", d)
+	}
+}
+
+// AutoIncrementPrimaryKey returns the column definition of an integer primary key
+// that is assigned increasing values which are never reused, even after rows are deleted.
+func (d Dialect) AutoIncrementPrimaryKey() string {
+	if d == DialectPostgres {
+		return "BIGSERIAL PRIMARY KEY"
+	}
+	return "INTEGER PRIMARY KEY AUTOINCREMENT"
+}
+
+// DB is a SQL database that serializes its writable transactions, which gives them
+// the same semantics as BoltDB's: a writable transaction sees all the previously
+// committed ones, and begins only after the previous one is done.
+type DB struct {
+	*sql.DB
+	// writer runs the writable transactions. For SQLite, it is a separate single connection
+	// pool that begins transactions with BEGIN IMMEDIATE, so that writers of other processes
+	// sharing the file are waited for when the transaction begins rather than failing it
+	// when it first writes. For other dialects, it is the same pool as DB.
+	writer  *sql.DB
+	dialect Dialect
+	// writeMu serializes the writable transactions of this process. Writers of other
+	// processes sharing a PostgreSQL database are serialized by an advisory lock.
+	writeMu sync.Mutex
+}
+
+// Open opens the database identified by the dialect and data source name
+func Open(dialect Dialect, dsn string) (*DB, error) {
+	driver, err := dialect.driverName()
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, bacerrors.Wrapf(err, "failed to open %s database", dialect).WithComponent(component)
+	}
+	if err = db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, bacerrors.Wrapf(err, "failed to connect to %s database", dialect).
+			WithCode(bacerrors.ConfigurationError).
+			WithComponent(component)
+	}
+	return &DB{DB: db, writer: db, dialect: dialect}, nil
+}
+
+// OpenSQLite opens, and creates if missing, the SQLite database file at the given path.
+// The database uses write-ahead logging so that readers don't block the writer.
+func OpenSQLite(path string) (*DB, error) {
+	dsn := fmt.Sprintf("%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)",
+		path, sqliteBusyTimeout)
+	db, err := Open(DialectSQLite, dsn)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := sql.Open("sqlite", dsn+"&_txlock=immediate")
+	if err != nil {
+		_ = db.Close()
+		return nil, bacerrors.Wrapf(err, "failed to open %s database", DialectSQLite).WithComponent(component)
+	}
+	writer.SetMaxOpenConns(1)
+	db.writer = writer
+	return db, nil
+}
+
+// Close closes the database
+func (db *DB) Close() error {
+	err := db.DB.Close()
+	if db.writer != db.DB {
+		err = errors.Join(err, db.writer.Close())
+	}
+	return err
+}
+
+// Dialect returns the SQL dialect of the database
+func (db *DB) Dialect() Dialect {
+	return db.dialect
+}
+
+// Begin starts a new transaction. Writable transactions block until the ongoing
+// writable transaction, if any, is committed or rolled back.
+func (db *DB) Begin(ctx context.Context, writable bool) (*Tx, error) {
+	if writable {
+		db.writeMu.Lock()
+	}
+	pool := db.DB
+	if writable {
+		pool = db.writer
+	}
+	sqlTx, err := pool.BeginTx(ctx, nil)
+	if err != nil {
+		if writable {
+			db.writeMu.Unlock()
+		}
+		return nil, err
+	}
+
+	tx := &Tx{tx: sqlTx, db: db, writable: writable}
+	if writable {
+		tx.unlock = db.writeMu.Unlock
+		if db.dialect == DialectPostgres {
+			if _, err = sqlTx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", writeLockKey); err != nil {
+				_ = tx.Rollback()
+				return nil, fmt.Errorf("failed to acquire write lock: %w", err)
+			}
+		}
+	}
+	return tx, nil
+}
+
+// rebind rewrites the ? placeholders of a query to the placeholders of the dialect
+func (db *DB) rebind(query string) string {
+	if db.dialect != DialectPostgres || !strings.Contains(query, "?") {
+		return query
+	}
+	var builder strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			builder.WriteByte('$')
+			builder.WriteString(strconv.Itoa(n))
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// Placeholders returns n comma separated placeholders, to be used in IN clauses
+func Placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
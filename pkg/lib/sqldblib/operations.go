@@ -0,0 +1,71 @@
+package sqldblib
+
+import (
+	"context"
+	"fmt"
+)
+
+// Update is a helper function that runs the update within a writable transaction.
+// It reuses the transaction of the context if any, and otherwise commits a new one.
+// It checks context cancellation before starting any operation.
+func Update(ctx context.Context, db *DB, update func(tx *Tx) error) error {
+	// Check context cancellation before starting
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context cancelled before starting update: %w", err)
+	}
+
+	// Check for existing transaction in context
+	tx, externalTx := TxFromContext(ctx)
+	if externalTx {
+		if !tx.Writable() {
+			return fmt.Errorf("readonly transaction provided in context for update operation")
+		}
+		return update(tx)
+	}
+
+	// Start new writable transaction
+	tx, err := db.Begin(ctx, true)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Always rollback on error for internally created transactions
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = update(tx); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+// View is a helper function that runs the view within a read-only transaction.
+// It reuses the transaction of the context if any.
+// It checks context cancellation before starting any operation.
+func View(ctx context.Context, db *DB, view func(tx *Tx) error) error {
+	// Check context cancellation before starting
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context cancelled before starting view: %w", err)
+	}
+
+	// Check for existing transaction in context
+	tx, externalTx := TxFromContext(ctx)
+	if externalTx {
+		return view(tx)
+	}
+
+	// Start new read-only transaction
+	tx, err := db.Begin(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	// Always rollback read-only transactions
+	defer tx.Rollback() //nolint:errcheck
+
+	return view(tx)
+}
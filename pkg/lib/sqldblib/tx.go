@@ -0,0 +1,79 @@
+package sqldblib
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// Tx is a transaction of a DB. Queries use ? placeholders regardless of the dialect.
+type Tx struct {
+	tx       *sql.Tx
+	db       *DB
+	writable bool
+	onCommit []func()
+	// unlock releases the write lock of writable transactions
+	unlock     func()
+	unlockOnce sync.Once
+}
+
+// Writable returns true if the transaction can write to the database
+func (t *Tx) Writable() bool {
+	return t.writable
+}
+
+// Dialect returns the SQL dialect of the transaction's database
+func (t *Tx) Dialect() Dialect {
+	return t.db.dialect
+}
+
+// ExecContext executes a query that doesn't return rows
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, t.db.rebind(query), args...)
+}
+
+// QueryContext executes a query that returns rows
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return t.tx.QueryContext(ctx, t.db.rebind(query), args...)
+}
+
+// QueryRowContext executes a query that is expected to return at most one row
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return t.tx.QueryRowContext(ctx, t.db.rebind(query), args...)
+}
+
+// OnCommit adds a function to be called after the transaction is committed
+func (t *Tx) OnCommit(fn func()) {
+	t.onCommit = append(t.onCommit, fn)
+}
+
+// Commit commits the transaction, and calls the OnCommit functions if it succeeds
+func (t *Tx) Commit() error {
+	err := t.tx.Commit()
+	t.release()
+	if err != nil {
+		return err
+	}
+	for _, fn := range t.onCommit {
+		fn()
+	}
+	return nil
+}
+
+// Rollback rolls back the transaction. Rolling back a transaction that is already done is a no-op.
+func (t *Tx) Rollback() error {
+	err := t.tx.Rollback()
+	t.release()
+	if err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return err
+	}
+	return nil
+}
+
+// release releases the write lock, if held
+func (t *Tx) release() {
+	if t.unlock != nil {
+		t.unlockOnce.Do(t.unlock)
+	}
+}
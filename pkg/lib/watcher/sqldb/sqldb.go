@@ -0,0 +1,493 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/benbjohnson/clock"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/sqldblib"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/watcher"
+)
+
+type subscriber struct {
+	notify   chan struct{}
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+// EventStore implements the EventStore interface using a SQL database as the underlying storage.
+// It provides the same semantics as the BoltDB event store, and can be shared by several
+// processes using the same database: events are numbered in the order their transactions commit,
+// and long-polling requests periodically check for events stored by other processes.
+type EventStore struct {
+	db             *sqldblib.DB
+	options        *eventStoreOptions
+	cache          *lru.Cache[uint64, watcher.Event]
+	latestEventNum atomic.Uint64
+	clock          clock.Clock
+
+	subscribers sync.Map // map[*subscriber]struct{}
+	stopGC      chan struct{}
+	closeOnce   sync.Once
+}
+
+func (s *EventStore) createSubscriber() *subscriber {
+	sub := &subscriber{
+		notify: make(chan struct{}, 1), // Buffer of 1 is enough since we just need a signal
+		done:   make(chan struct{}),
+	}
+	s.subscribers.Store(sub, struct{}{})
+	return sub
+}
+
+func (s *EventStore) removeSubscriber(sub *subscriber) {
+	if sub == nil {
+		return
+	}
+	s.subscribers.Delete(sub)
+	sub.doneOnce.Do(func() {
+		close(sub.done) // Signal any pending operations to stop
+	})
+}
+
+func (s *EventStore) notifySubscribers() {
+	s.subscribers.Range(func(key, _ interface{}) bool {
+		sub := key.(*subscriber)
+		select {
+		case sub.notify <- struct{}{}:
+		case <-sub.done:
+			// Subscriber is being removed, skip it
+		default:
+			// Channel full (signal already pending), skip it
+		}
+		return true
+	})
+}
+
+// NewEventStore creates a new EventStore with the given options.
+//
+// It creates the events and checkpoints tables if they don't exist, sets up caching,
+// and starts the garbage collection process.
+//
+// Example usage:
+//
+//	db, err := sqldblib.OpenSQLite("events.db")
+//	if err != nil {
+//	    // handle error
+//	}
+//	defer db.Close()
+//
+//	store, err := NewEventStore(
+//	    db,
+//	    WithEventsTable("my_events"),
+//	    WithCheckpointsTable("my_checkpoints"),
+//	    WithEventSerializer(NewJSONSerializer()),
+//	)
+//	if err != nil {
+//	    // handle error
+//	}
+func NewEventStore(db *sqldblib.DB, opts ...EventStoreOption) (*EventStore, error) {
+	options := defaultEventStoreOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	err := errors.Join(
+		validate.NotNil(db, "database instance cannot be nil"),
+		options.validate(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := lru.New[uint64, watcher.Event](max(options.cacheSize, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache: %w", err)
+	}
+
+	store := &EventStore{
+		db:      db,
+		options: options,
+		cache:   cache,
+		clock:   options.clock,
+		stopGC:  make(chan struct{}),
+	}
+
+	err = sqldblib.Update(context.Background(), db, func(tx *sqldblib.Tx) error {
+		return store.createTables(context.Background(), tx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tables: %w", err)
+	}
+
+	// Start the garbage collection process
+	go store.runGCLoop()
+	return store, nil
+}
+
+// createTables creates the events and checkpoints tables if they don't exist
+func (s *EventStore) createTables(ctx context.Context, tx *sqldblib.Tx) error {
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			seq_num     %s,
+			timestamp   BIGINT NOT NULL,
+			operation   TEXT NOT NULL,
+			object_type TEXT NOT NULL,
+			event       TEXT NOT NULL
+		)`, s.options.eventsTable, tx.Dialect().AutoIncrementPrimaryKey()),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			watcher_id TEXT PRIMARY KEY,
+			seq_num    BIGINT NOT NULL
+		)`, s.options.checkpointsTable),
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StoreEvent stores a new event in the EventStore.
+// It wraps the storage operation in a transaction, or uses the transaction of the context if any.
+func (s *EventStore) StoreEvent(ctx context.Context, request watcher.StoreEventRequest) error {
+	return sqldblib.Update(ctx, s.db, func(tx *sqldblib.Tx) error {
+		return s.StoreEventTx(ctx, tx, request)
+	})
+}
+
+// StoreEventTx stores a new event within an existing transaction.
+// It serializes the event, stores it in the database, and once the transaction
+// is committed adds it to the cache and notifies watchers of the new event.
+func (s *EventStore) StoreEventTx(ctx context.Context, tx *sqldblib.Tx, request watcher.StoreEventRequest) error {
+	event := watcher.Event{
+		Operation:  request.Operation,
+		ObjectType: request.ObjectType,
+		Object:     request.Object,
+		Timestamp:  s.clock.Now(),
+	}
+
+	// the sequence number is assigned by the database, and set on the event when it is read
+	eventBytes, err := s.options.serializer.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	var id uint64
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (timestamp, operation, object_type, event) VALUES (?, ?, ?, ?) RETURNING seq_num`,
+		s.options.eventsTable),
+		event.Timestamp.UnixNano(), string(event.Operation), event.ObjectType, string(eventBytes),
+	).Scan(&id)
+	if err != nil {
+		return fmt.Errorf("failed to store event: %w", err)
+	}
+	event.SeqNum = id
+
+	tx.OnCommit(func() {
+		// Add to cache
+		s.cache.Add(id, event)
+		s.updateLatestEventNum(id)
+		s.notifySubscribers()
+	})
+
+	return nil
+}
+
+// updateLatestEventNum updates the latest event number, but only if the given one is higher
+func (s *EventStore) updateLatestEventNum(seqNum uint64) {
+	for {
+		current := s.latestEventNum.Load()
+		if seqNum <= current {
+			return
+		}
+		if s.latestEventNum.CompareAndSwap(current, seqNum) {
+			return
+		}
+	}
+}
+
+// GetEvents retrieves events from the store based on the provided query parameters.
+// It supports long-polling: if no events are immediately available, it waits for new events
+// or until the long-polling timeout is reached.
+func (s *EventStore) GetEvents(ctx context.Context, request watcher.GetEventsRequest) (*watcher.GetEventsResponse, error) {
+	// Create a new ephemeral subscriber
+	sub := s.createSubscriber()
+	defer s.removeSubscriber(sub)
+
+	log.Trace().Str("watcher_id", request.WatcherID).Msgf("GetEvents request: %+v", request)
+
+	timeout := s.clock.After(s.options.longPollingTimeout)
+	var result *watcher.GetEventsResponse
+	for {
+		err := sqldblib.View(ctx, s.db, func(tx *sqldblib.Tx) error {
+			var err error
+			result, err = s.getEventsTx(ctx, tx, request)
+			return err
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		// If we have events or the iterator changed, return immediately
+		if len(result.Events) > 0 || result.NextEventIterator != request.EventIterator {
+			log.Trace().Str("watcher_id", request.WatcherID).Msgf("GetEvents response: %+v", result)
+			return result, nil
+		}
+
+		// No events available, wait for notifications, events of other processes or timeout
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-sub.done:
+			return result, nil
+		case <-sub.notify:
+			continue // Try fetching again
+		case <-s.clock.After(s.options.pollingInterval):
+			continue
+		case <-timeout:
+			// Return empty result after timeout
+			return result, nil
+		}
+	}
+}
+
+// getEventsTx retrieves events within a transaction based on the provided query parameters.
+// The returned iterator moves past all the events that were considered, even the ones that
+// don't match the filter, so that watchers with a narrow filter don't hold back garbage collection.
+func (s *EventStore) getEventsTx(
+	ctx context.Context, tx *sqldblib.Tx, request watcher.GetEventsRequest) (*watcher.GetEventsResponse, error) {
+	iterator, err := s.resolveIteratorType(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	startCondition := "seq_num > ?"
+	if iterator.Type == watcher.EventIteratorAtSequenceNumber {
+		startCondition = "seq_num >= ?"
+	}
+
+	// bound the query by the latest event first, so that events committed while this query
+	// runs are not skipped by moving the iterator past them.
+	var upperBound sql.NullInt64
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(`SELECT MAX(seq_num) FROM %s WHERE %s`,
+		s.options.eventsTable, startCondition), iterator.SequenceNumber).Scan(&upperBound)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read latest event: %w", err)
+	}
+
+	// results to return
+	events := make([]watcher.Event, 0)
+	nextIterator := iterator
+	if !upperBound.Valid {
+		return &watcher.GetEventsResponse{Events: events, NextEventIterator: nextIterator}, nil
+	}
+
+	query := fmt.Sprintf(`SELECT seq_num, event FROM %s WHERE %s AND seq_num <= ?`,
+		s.options.eventsTable, startCondition)
+	args := []any{iterator.SequenceNumber, upperBound.Int64}
+	if len(request.Filter.ObjectTypes) > 0 {
+		query += fmt.Sprintf(" AND object_type IN (%s)", sqldblib.Placeholders(len(request.Filter.ObjectTypes)))
+		for _, objectType := range request.Filter.ObjectTypes {
+			args = append(args, objectType)
+		}
+	}
+	if len(request.Filter.Operations) > 0 {
+		query += fmt.Sprintf(" AND operation IN (%s)", sqldblib.Placeholders(len(request.Filter.Operations)))
+		for _, operation := range request.Filter.Operations {
+			args = append(args, string(operation))
+		}
+	}
+	query += " ORDER BY seq_num"
+	if request.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, request.Limit)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	for rows.Next() {
+		var seqNum uint64
+		var data []byte
+		if err = rows.Scan(&seqNum, &data); err != nil {
+			return nil, fmt.Errorf("failed to read event: %w", err)
+		}
+
+		event, found := s.cache.Get(seqNum)
+		if !found {
+			if err = s.options.serializer.Unmarshal(data, &event); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+			}
+			event.SeqNum = seqNum
+		}
+		events = append(events, event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	nextIterator = watcher.AfterSequenceNumberIterator(uint64(upperBound.Int64))
+	if request.Limit > 0 && len(events) == request.Limit {
+		nextIterator = watcher.AfterSequenceNumberIterator(events[len(events)-1].SeqNum)
+	}
+
+	return &watcher.GetEventsResponse{
+		Events:            events,
+		NextEventIterator: nextIterator,
+	}, nil
+}
+
+// resolveIteratorType resolves the event iterator type based on the provided query parameters.
+// It returns a new event iterator if the type is set to EventIteratorTrimHorizon or EventIteratorLatest.
+func (s *EventStore) resolveIteratorType(ctx context.Context, request watcher.GetEventsRequest) (watcher.EventIterator, error) {
+	switch request.EventIterator.Type {
+	case watcher.EventIteratorTrimHorizon:
+		return watcher.AfterSequenceNumberIterator(0), nil
+	case watcher.EventIteratorLatest:
+		seqNum, err := s.GetLatestEventNum(ctx)
+		if err != nil {
+			return watcher.EventIterator{}, fmt.Errorf("failed to get latest event num: %w", err)
+		}
+		return watcher.AfterSequenceNumberIterator(seqNum), nil
+	default:
+		return request.EventIterator, nil
+	}
+}
+
+// GetLatestEventNum retrieves the sequence number of the latest event in the store,
+// including the events stored by other processes sharing the database.
+func (s *EventStore) GetLatestEventNum(ctx context.Context) (uint64, error) {
+	var latest sql.NullInt64
+	err := sqldblib.View(ctx, s.db, func(tx *sqldblib.Tx) error {
+		return tx.QueryRowContext(ctx, fmt.Sprintf(`SELECT MAX(seq_num) FROM %s`, s.options.eventsTable)).Scan(&latest)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read latest event: %w", err)
+	}
+	if latest.Valid {
+		s.updateLatestEventNum(uint64(latest.Int64))
+	}
+	// the events table can be emptied by garbage collection, in which case the latest
+	// known sequence number is returned instead
+	return s.latestEventNum.Load(), nil
+}
+
+// StoreCheckpoint stores a checkpoint for a specific watcher.
+// Checkpoints are used to track which events have been processed by each watcher.
+func (s *EventStore) StoreCheckpoint(ctx context.Context, watcherID string, eventSeqNum uint64) error {
+	return sqldblib.Update(ctx, s.db, func(tx *sqldblib.Tx) error {
+		_, err := tx.ExecContext(ctx, fmt.Sprintf(
+			`INSERT INTO %s (watcher_id, seq_num) VALUES (?, ?)
+			ON CONFLICT (watcher_id) DO UPDATE SET seq_num = excluded.seq_num`, s.options.checkpointsTable),
+			watcherID, eventSeqNum)
+		return err
+	})
+}
+
+// GetCheckpoint retrieves the checkpoint for a specific watcher.
+// If the checkpoint is not found, it returns a CheckpointError.
+func (s *EventStore) GetCheckpoint(ctx context.Context, watcherID string) (uint64, error) {
+	var checkpoint uint64
+	err := sqldblib.View(ctx, s.db, func(tx *sqldblib.Tx) error {
+		return tx.QueryRowContext(ctx, fmt.Sprintf(`SELECT seq_num FROM %s WHERE watcher_id = ?`,
+			s.options.checkpointsTable), watcherID).Scan(&checkpoint)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, watcher.NewCheckpointError(watcherID, watcher.ErrCheckpointNotFound)
+		}
+		return 0, watcher.NewCheckpointError(watcherID, err)
+	}
+	return checkpoint, nil
+}
+
+// runGCLoop runs the garbage collection process at regular intervals.
+func (s *EventStore) runGCLoop() {
+	if s.options.gcAgeThreshold == 0 {
+		return // GC is disabled if threshold is not set
+	}
+
+	ticker := s.clock.Ticker(s.options.gcCadence)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.runGC(context.Background()); err != nil {
+				log.Error().Err(err).Msg("Error during garbage collection. Continuing...")
+			}
+		case <-s.stopGC:
+			return
+		}
+	}
+}
+
+// runGC performs garbage collection by pruning old events that are beyond all watchers' checkpoints
+// and older than the configured event age threshold.
+func (s *EventStore) runGC(ctx context.Context) error {
+	if s.options.gcAgeThreshold == 0 {
+		return nil // GC is disabled if threshold is not set
+	}
+
+	return sqldblib.Update(ctx, s.db, func(tx *sqldblib.Tx) error {
+		// Find the minimum checkpoint across all watchers
+		var minCheckpoint sql.NullInt64
+		err := tx.QueryRowContext(ctx, fmt.Sprintf(`SELECT MIN(seq_num) FROM %s`, s.options.checkpointsTable)).
+			Scan(&minCheckpoint)
+		if err != nil {
+			return fmt.Errorf("failed to find minimum checkpoint: %w", err)
+		}
+		if !minCheckpoint.Valid {
+			return nil // no watcher has processed any event yet
+		}
+
+		threshold := s.clock.Now().Add(-s.options.gcAgeThreshold).UnixNano()
+		var candidates strings.Builder
+		fmt.Fprintf(&candidates, `SELECT seq_num FROM %s WHERE seq_num <= ? AND timestamp < ? ORDER BY seq_num`,
+			s.options.eventsTable)
+		args := []any{minCheckpoint.Int64, threshold}
+		if s.options.gcMaxRecordsPerRun > 0 {
+			candidates.WriteString(" LIMIT ?")
+			args = append(args, s.options.gcMaxRecordsPerRun)
+		}
+
+		result, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE seq_num IN (%s)`,
+			s.options.eventsTable, candidates.String()), args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete old events: %w", err)
+		}
+		deleted, _ := result.RowsAffected()
+		log.Debug().Msgf("GC deleted %d events upto %d", deleted, minCheckpoint.Int64)
+		return nil
+	})
+}
+
+// Close stops the garbage collection process and purges the cache.
+func (s *EventStore) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		close(s.stopGC)
+		s.subscribers.Range(func(key, _ interface{}) bool {
+			s.removeSubscriber(key.(*subscriber))
+			return true
+		})
+		s.cache.Purge()
+	})
+	return nil
+}
+
+// compile-time check for interface conformance
+var _ watcher.EventStore = &EventStore{}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.etcd.io/bbolt"
 
+	"github.com/bacalhau-project/bacalhau/pkg/lib/sqldblib"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/watcher"
 )
 
@@ -32,6 +33,22 @@ func CreateBoltDB(t *testing.T) *bbolt.DB {
 	return db
 }
 
+// CreateSQLiteDB creates a new SQLite database for testing
+func CreateSQLiteDB(t *testing.T) *sqldblib.DB {
+	t.Helper()
+
+	// Create the database in a temporary file
+	db, err := sqldblib.OpenSQLite(filepath.Join(t.TempDir(), "test.sqlite"))
+	require.NoError(t, err)
+
+	// Register cleanup to close the database after the test completes
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return db
+}
+
 // CreateSerializer creates a new JSONSerializer for testing
 func CreateSerializer(t *testing.T) *watcher.JSONSerializer {
 	t.Helper()
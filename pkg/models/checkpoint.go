@@ -0,0 +1,114 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// CheckpointPath is the well-known directory tasks that enable checkpointing write their checkpoints to.
+	CheckpointPath = "/checkpoint"
+	// CheckpointResultName is the name of the result path the checkpoint directory is collected under.
+	CheckpointResultName = "checkpoint"
+	// ResumeCheckpointPath is where the last checkpoint of a previous execution is mounted when
+	// an execution is retried, so that the task can resume from it instead of starting over.
+	ResumeCheckpointPath = "/resume"
+	// ResumeCheckpointAlias is the alias of the input source restoring the last checkpoint.
+	ResumeCheckpointAlias = "checkpoint"
+
+	// DefaultCheckpointInterval is how often the checkpoint directory is published if not configured.
+	DefaultCheckpointInterval = 5 * time.Minute
+	// MinCheckpointInterval is the minimum interval between checkpoints.
+	MinCheckpointInterval = 10 * time.Second
+)
+
+// CheckpointConfig enables periodic checkpointing of a task. The task writes its progress to
+// CheckpointPath, which the compute node publishes through the task's publisher while it runs.
+// When the execution fails and is retried, the last published checkpoint is mounted at
+// ResumeCheckpointPath of the new execution.
+type CheckpointConfig struct {
+	// Interval is how often in seconds the checkpoint directory is published.
+	// Zero means the default interval.
+	Interval int64 `json:"Interval,omitempty"`
+}
+
+// GetInterval returns the interval between checkpoints
+func (c *CheckpointConfig) GetInterval() time.Duration {
+	if c == nil || c.Interval == 0 {
+		return DefaultCheckpointInterval
+	}
+	return time.Duration(c.Interval) * time.Second
+}
+
+// Copy returns a deep copy of the checkpoint config
+func (c *CheckpointConfig) Copy() *CheckpointConfig {
+	if c == nil {
+		return nil
+	}
+	return &CheckpointConfig{
+		Interval: c.Interval,
+	}
+}
+
+// Validate is used to check a checkpoint config for reasonable configuration
+func (c *CheckpointConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Interval < 0 {
+		return fmt.Errorf("checkpoint interval must be non-negative. Found: %d", c.Interval)
+	}
+	if c.Interval > 0 && c.GetInterval() < MinCheckpointInterval {
+		return fmt.Errorf("checkpoint interval must be at least %s. Found: %s", MinCheckpointInterval, c.GetInterval())
+	}
+	return nil
+}
+
+// Checkpoint is a snapshot of the checkpoint directory of an execution,
+// published while the execution is running.
+type Checkpoint struct {
+	// Result is the published location of the checkpoint
+	Result *SpecConfig `json:"Result"`
+	// Sequence is incremented with each checkpoint published by the execution, starting at 1
+	Sequence int `json:"Sequence"`
+	// CreateTime is the time the checkpoint was published
+	CreateTime int64 `json:"CreateTime"`
+}
+
+// GetCreateTime returns the time the checkpoint was published
+func (c *Checkpoint) GetCreateTime() time.Time {
+	return time.Unix(0, c.CreateTime).UTC()
+}
+
+// Copy returns a deep copy of the checkpoint
+func (c *Checkpoint) Copy() *Checkpoint {
+	if c == nil {
+		return nil
+	}
+	return &Checkpoint{
+		Result:     c.Result.Copy(),
+		Sequence:   c.Sequence,
+		CreateTime: c.CreateTime,
+	}
+}
+
+// Validate is used to check a checkpoint is complete
+func (c *Checkpoint) Validate() error {
+	if c == nil {
+		return errors.New("checkpoint is nil")
+	}
+	if c.Sequence < 1 {
+		return fmt.Errorf("checkpoint sequence must be positive. Found: %d", c.Sequence)
+	}
+	return c.Result.Validate()
+}
+
+// InputSource returns an input source restoring the checkpoint at ResumeCheckpointPath
+func (c *Checkpoint) InputSource() *InputSource {
+	return &InputSource{
+		Source: c.Result.Copy(),
+		Alias:  ResumeCheckpointAlias,
+		Target: ResumeCheckpointPath,
+	}
+}
@@ -43,11 +43,13 @@ const (
 	EngineNoop   = "noop"
 	EngineDocker = "docker"
 	EngineWasm   = "wasm"
+	EngineExec   = "exec"
 )
 
 var EngineNames = []string{
 	EngineDocker,
 	EngineWasm,
+	EngineExec,
 }
 
 const (
@@ -60,9 +62,11 @@ const (
 	StorageSourceInline         = "inline"
 	StorageSourceLocalDirectory = "localDirectory" // Deprecated: use StorageSourceLocal instead
 	StorageSourceLocal          = "local"
+	StorageSourceGit            = "git"
 )
 
 var StoragesNames = []string{
+	StorageSourceGit,
 	StorageSourceIPFS,
 	StorageSourceInline,
 	StorageSourceLocalDirectory,
@@ -108,4 +112,7 @@ const (
 	MetaServerInstanceID     = "bacalhau.org/server.instance.id"
 	MetaClientInstallationID = "bacalhau.org/client.installation.id"
 	MetaClientInstanceID     = "bacalhau.org/client.instance.id"
+
+	// MetaScheduledRunTime holds the schedule tick that triggered a version of a scheduled job
+	MetaScheduledRunTime = "bacalhau.org/schedule.run.time"
 )
@@ -17,19 +17,25 @@ const (
 )
 
 const (
-	EvalTriggerJobRegister = "job-register"
-	EvalTriggerJobCancel   = "job-cancel"
-	EvalTriggerJobRerun    = "job-rerun"
-	EvalTriggerJobUpdate   = "job-update"
-	EvalTriggerJobQueue    = "job-queue"
-	EvalTriggerJobTimeout  = "job-timeout"
+	EvalTriggerJobRegister   = "job-register"
+	EvalTriggerJobDependency = "job-dependency"
+	EvalTriggerJobCancel     = "job-cancel"
+	EvalTriggerJobRerun      = "job-rerun"
+	EvalTriggerJobUpdate     = "job-update"
+	EvalTriggerJobQueue      = "job-queue"
+	EvalTriggerJobTimeout    = "job-timeout"
+	EvalTriggerJobSchedule   = "job-schedule"
 
 	EvalTriggerExecFailure    = "exec-failure"
 	EvalTriggerExecUpdate     = "exec-update"
 	EvalTriggerExecTimeout    = "exec-timeout"
+	EvalTriggerExecPreempted  = "exec-preempted"
 	EvalTriggerExecutionLimit = "exec-limit"
 	EvalTriggerNodeJoin       = "node-join"
 	EvalTriggerNodeLeave      = "node-leave"
+	EvalTriggerNodeDrain      = "node-drain"
+
+	EvalTriggerLeaderElected = "leader-elected"
 )
 
 // Evaluation is just to ask the scheduler to reassess if additional job instances must be
@@ -119,6 +119,15 @@ type Execution struct {
 	// TODO: evaluate removing this from execution spec in favour of calling `bacalhau job logs`
 	RunOutput *RunCommandResult `json:"RunOutput"`
 
+	// Checkpoint is the last checkpoint published by the execution, if its task enables checkpointing
+	Checkpoint *Checkpoint `json:"Checkpoint,omitempty"`
+
+	// PartialResult is where the results published so far by the execution are, if its task enables streaming
+	PartialResult *PartialResult `json:"PartialResult,omitempty"`
+
+	// ResourceUsage is the summary of the resources the execution actually consumed, once it completed
+	ResourceUsage *ResourceUsage `json:"ResourceUsage,omitempty"`
+
 	// PreviousExecution is the execution that this execution is replacing
 	PreviousExecution string `json:"PreviousExecution"`
 
@@ -202,6 +211,9 @@ func (e *Execution) Copy() *Execution {
 	na.AllocatedResources = na.AllocatedResources.Copy()
 	na.PublishedResult = na.PublishedResult.Copy()
 	na.RunOutput = na.RunOutput.Copy()
+	na.Checkpoint = na.Checkpoint.Copy()
+	na.PartialResult = na.PartialResult.Copy()
+	na.ResourceUsage = na.ResourceUsage.Copy()
 	return na
 }
 
@@ -306,6 +318,12 @@ type RunCommandResult struct {
 
 	// Runner error
 	ErrorMsg string `json:"ErrorMsg"`
+
+	// number of instructions run, for executors metering them
+	InstructionsUsed uint64 `json:"InstructionsUsed,omitempty"`
+
+	// resources actually consumed by the run, for executors sampling them
+	ResourceUsage *ResourceUsage `json:"ResourceUsage,omitempty"`
 }
 
 func NewRunCommandResult() *RunCommandResult {
@@ -318,6 +336,14 @@ func NewRunCommandResult() *RunCommandResult {
 	}
 }
 
+// GetResourceUsage returns the resources consumed by the run, or nil if they were not measured
+func (r *RunCommandResult) GetResourceUsage() *ResourceUsage {
+	if r == nil {
+		return nil
+	}
+	return r.ResourceUsage
+}
+
 func (r *RunCommandResult) Copy() *RunCommandResult {
 	if r == nil {
 		return nil
@@ -325,8 +351,6 @@ func (r *RunCommandResult) Copy() *RunCommandResult {
 
 	newRCR := new(RunCommandResult)
 	*newRCR = *r
-
-	// Since all fields are simple types (string, bool, int),
-	// a shallow copy is sufficient.
+	newRCR.ResourceUsage = r.ResourceUsage.Copy()
 	return newRCR
 }
@@ -0,0 +1,26 @@
+package models
+
+type ExecOutputType string
+
+const (
+	// ExecOutputStarted is sent once the command is started and ready to receive input
+	ExecOutputStarted ExecOutputType = "started"
+	ExecOutputStdout  ExecOutputType = "stdout"
+	ExecOutputStderr  ExecOutputType = "stderr"
+	// ExecOutputExit is the last output of a command, carrying its exit code
+	ExecOutputExit ExecOutputType = "exit"
+)
+
+// ExecOutput is a chunk of output of a command run inside, or attached to, a running execution
+type ExecOutput struct {
+	Type     ExecOutputType
+	Data     []byte `json:",omitempty"`
+	ExitCode int    `json:",omitempty"`
+}
+
+// ExecInput is a chunk of input to a command run inside, or attached to, a running execution.
+// EOF closes the input of the command.
+type ExecInput struct {
+	Data []byte `json:",omitempty"`
+	EOF  bool   `json:",omitempty"`
+}
@@ -20,11 +20,16 @@ type InputSource struct {
 
 	// Target is the path where the artifact should be mounted on
 	Target string `json:"Target"`
+
+	// Extract unpacks the artifact into Target when it is a tar, zip, gzip or zstd archive,
+	// instead of mounting the archive itself.
+	Extract bool `json:"Extract,omitempty"`
 }
 
 func (a *InputSource) MarshalZerologObject(e *zerolog.Event) {
 	e.Str("alias", a.Alias).
 		Str("target", a.Target).
+		Bool("extract", a.Extract).
 		Object("source", a.Source)
 }
 
@@ -45,9 +50,10 @@ func (a *InputSource) Copy() *InputSource {
 		return nil
 	}
 	return &InputSource{
-		Source: a.Source.Copy(),
-		Alias:  a.Alias,
-		Target: a.Target,
+		Source:  a.Source.Copy(),
+		Alias:   a.Alias,
+		Target:  a.Target,
+		Extract: a.Extract,
 	}
 }
 
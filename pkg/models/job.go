@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -135,6 +136,21 @@ type Job struct {
 
 	Tasks []*Task `json:"Tasks"`
 
+	// DependsOn lists the IDs or names of jobs in the same namespace that must
+	// complete successfully before this job is scheduled. The job stays pending
+	// until all of its upstream jobs are completed, and fails if any of them fails.
+	// The published results of upstream jobs are mounted as input sources of this job.
+	DependsOn []string `json:"DependsOn,omitempty"`
+
+	// Schedule defines when a batch job should be run on a recurring basis.
+	// Scheduled jobs are not run on submission, but on each tick of their schedule,
+	// where each run creates a new version of the job.
+	Schedule *Schedule `json:"Schedule,omitempty"`
+
+	// Webhooks are HTTP endpoints notified of the job's lifecycle transitions,
+	// such as state changes and execution failures.
+	Webhooks []*Webhook `json:"Webhooks,omitempty"`
+
 	// State is the current state of the job.
 	State State[JobStateType] `json:"State"`
 
@@ -210,6 +226,11 @@ func (j *Job) Normalize() {
 	for _, task := range j.Tasks {
 		task.Normalize()
 	}
+
+	j.Schedule.Normalize()
+	for _, webhook := range j.Webhooks {
+		webhook.Normalize()
+	}
 }
 
 // Copy returns a deep copy of the Job. It is expected that callers use recover.
@@ -231,6 +252,14 @@ func (j *Job) Copy() *Job {
 	}
 
 	nj.Meta = maps.Clone(nj.Meta)
+	nj.DependsOn = slices.Clone(nj.DependsOn)
+	nj.Schedule = j.Schedule.Copy()
+	if j.Webhooks != nil {
+		nj.Webhooks = make([]*Webhook, len(j.Webhooks))
+		for i, webhook := range j.Webhooks {
+			nj.Webhooks[i] = webhook.Copy()
+		}
+	}
 	return nj
 }
 
@@ -308,6 +337,7 @@ func (j *Job) ValidateSubmission() error {
 			mErr = errors.Join(mErr, outer)
 		}
 	}
+	mErr = errors.Join(mErr, j.validateDependencies(), j.validateSchedule(), j.validateWebhooks())
 
 	// Validate the task group
 	for _, task := range j.Tasks {
@@ -320,6 +350,61 @@ func (j *Job) ValidateSubmission() error {
 	return mErr
 }
 
+// validateDependencies checks that the job's upstream dependencies are well-formed
+func (j *Job) validateDependencies() error {
+	if len(j.DependsOn) == 0 {
+		return nil
+	}
+	var mErr error
+	if j.Type != JobTypeBatch && j.Type != JobTypeService {
+		mErr = errors.Join(mErr, fmt.Errorf("%s jobs cannot depend on other jobs", j.Type))
+	}
+	seen := make(map[string]bool, len(j.DependsOn))
+	for _, dep := range j.DependsOn {
+		if err := validate.NotBlank(dep, "job dependency cannot be blank"); err != nil {
+			mErr = errors.Join(mErr, err)
+			continue
+		}
+		if seen[dep] {
+			mErr = errors.Join(mErr, fmt.Errorf("duplicate job dependency %q", dep))
+		}
+		if dep == j.Name || (j.ID != "" && dep == j.ID) {
+			mErr = errors.Join(mErr, errors.New("job cannot depend on itself"))
+		}
+		seen[dep] = true
+	}
+	return mErr
+}
+
+// validateSchedule checks that the job's schedule is well-formed
+func (j *Job) validateSchedule() error {
+	if j.Schedule == nil {
+		return nil
+	}
+	var mErr error
+	if j.Type != JobTypeBatch {
+		mErr = errors.Join(mErr, fmt.Errorf("%s jobs cannot be scheduled. only batch jobs can", j.Type))
+	}
+	if len(j.DependsOn) > 0 {
+		mErr = errors.Join(mErr, errors.New("scheduled jobs cannot depend on other jobs"))
+	}
+	if err := j.Schedule.Validate(); err != nil {
+		mErr = errors.Join(mErr, err)
+	}
+	return mErr
+}
+
+// validateWebhooks checks that the job's webhooks are well-formed
+func (j *Job) validateWebhooks() error {
+	var mErr error
+	for i, webhook := range j.Webhooks {
+		if err := webhook.Validate(); err != nil {
+			mErr = errors.Join(mErr, fmt.Errorf("webhook %d validation failed: %w", i+1, err))
+		}
+	}
+	return mErr
+}
+
 // SanitizeSubmission is used to sanitize a job for reasonable configuration when it is submitted.
 func (j *Job) SanitizeSubmission() (warnings []string) {
 	if !j.State.StateType.IsUndefined() {
@@ -406,6 +491,43 @@ func (j *Job) AllStorageTypes() []string {
 	return storageTypes
 }
 
+// HasDependencies returns true if the job depends on other jobs
+func (j *Job) HasDependencies() bool {
+	return j != nil && len(j.DependsOn) > 0
+}
+
+// IsScheduled returns true if the job runs on a recurring schedule
+func (j *Job) IsScheduled() bool {
+	return j != nil && j.Schedule != nil
+}
+
+// ScheduledRunTime returns the schedule tick that triggered the current version of the job,
+// or the zero time if the current version was not triggered by the job's schedule.
+func (j *Job) ScheduledRunTime() time.Time {
+	if j == nil {
+		return time.Time{}
+	}
+	runTime, err := time.Parse(time.RFC3339, j.Meta[MetaScheduledRunTime])
+	if err != nil {
+		return time.Time{}
+	}
+	return runTime
+}
+
+// IsScheduledRun returns true if the current version of the job was triggered by the job's schedule
+func (j *Job) IsScheduledRun() bool {
+	return j.IsScheduled() && !j.ScheduledRunTime().IsZero()
+}
+
+// GetStartTime returns the time the current run of the job started,
+// which is the schedule tick for scheduled runs, and the creation time otherwise.
+func (j *Job) GetStartTime() time.Time {
+	if runTime := j.ScheduledRunTime(); !runTime.IsZero() {
+		return runTime.UTC()
+	}
+	return j.GetCreateTime()
+}
+
 // IsLongRunning returns true if the job is long running
 func (j *Job) IsLongRunning() bool {
 	return j.Type == JobTypeService || j.Type == JobTypeDaemon
@@ -415,7 +537,7 @@ func (j *Job) IsLongRunning() bool {
 func (j *Job) IsExpired(expirationTime time.Time) bool {
 	return !j.IsTerminal() &&
 		j.Task().Timeouts.TotalTimeout > 0 &&
-		j.GetCreateTime().Before(expirationTime)
+		j.GetStartTime().Before(expirationTime)
 }
 
 // OrchestratorID returns the orchestrator ID for the job from its metadata
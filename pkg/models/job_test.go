@@ -168,6 +168,72 @@ func (suite *JobTestSuite) TestJobValidation() {
 				"ops jobs cannot specify count > 1",
 			},
 		},
+		{
+			name: "daemon job with dependencies",
+			job: &models.Job{
+				ID:        "test-job",
+				Name:      "test-job",
+				Namespace: "default",
+				Type:      models.JobTypeDaemon,
+				DependsOn: []string{"upstream"},
+			},
+			expectError: true,
+			errorMsgs: []string{
+				"daemon jobs cannot depend on other jobs",
+			},
+		},
+		{
+			name: "invalid dependencies",
+			job: &models.Job{
+				ID:        "test-job",
+				Name:      "test-job",
+				Namespace: "default",
+				Type:      models.JobTypeBatch,
+				DependsOn: []string{"upstream", "upstream", "test-job", " "},
+			},
+			expectError: true,
+			errorMsgs: []string{
+				"duplicate job dependency \"upstream\"",
+				"job cannot depend on itself",
+				"job dependency cannot be blank",
+			},
+		},
+		{
+			name: "invalid schedule",
+			job: &models.Job{
+				ID:        "test-job",
+				Name:      "test-job",
+				Namespace: "default",
+				Type:      models.JobTypeService,
+				DependsOn: []string{"upstream"},
+				Schedule:  &models.Schedule{Cron: "every day"},
+			},
+			expectError: true,
+			errorMsgs: []string{
+				"service jobs cannot be scheduled",
+				"scheduled jobs cannot depend on other jobs",
+				"invalid schedule cron expression",
+			},
+		},
+		{
+			name: "invalid webhooks",
+			job: &models.Job{
+				ID:        "test-job",
+				Name:      "test-job",
+				Namespace: "default",
+				Type:      models.JobTypeBatch,
+				Webhooks: []*models.Webhook{
+					{URL: "https://example.com/hook"},
+					{URL: "ftp://example.com", Events: []models.WebhookEventType{"JobDeleted"}},
+				},
+			},
+			expectError: true,
+			errorMsgs: []string{
+				"webhook 2 validation failed",
+				"invalid webhook URL",
+				"invalid webhook event \"JobDeleted\"",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,18 @@
+package models
+
+// JobUpsert represents a change to a job, containing the job before and after the change.
+// It is used for tracking and propagating job state changes.
+type JobUpsert struct {
+	// Current represents the job after the change, nil if the job was deleted
+	Current *Job
+	// Previous represents the job before the change, nil if this is a new job
+	Previous *Job
+}
+
+// HasStateChange returns true if the job's state changed
+func (u JobUpsert) HasStateChange() bool {
+	if u.Previous == nil || u.Current == nil {
+		return true // new and deleted jobs always count as a state change
+	}
+	return u.Previous.State.StateType != u.Current.State.StateType
+}
@@ -6,9 +6,11 @@ const (
 	BidRejectedMessageType     = "BidRejected"
 	CancelExecutionMessageType = "CancelExecution"
 
-	BidResultMessageType    = "BidResult"
-	RunResultMessageType    = "RunResult"
-	ComputeErrorMessageType = "ComputeError"
+	BidResultMessageType        = "BidResult"
+	RunResultMessageType        = "RunResult"
+	CheckpointResultMessageType = "CheckpointResult"
+	PartialResultMessageType    = "PartialResult"
+	ComputeErrorMessageType     = "ComputeError"
 
 	HandshakeRequestMessageType      = "transport.HandshakeRequest"
 	HeartbeatRequestMessageType      = "transport.HeartbeatRequest"
@@ -0,0 +1,15 @@
+package messages
+
+// ExecRequest runs a command inside a running execution, or attaches to its main process if no command is given
+type ExecRequest struct {
+	ExecutionID string
+	NodeID      string
+	Command     []string
+	// StdinSubject is the subject the input of the command is published to, if any
+	StdinSubject string
+}
+
+// IsAttach returns true if the request attaches to the main process of the execution
+func (r ExecRequest) IsAttach() bool {
+	return len(r.Command) == 0
+}
@@ -15,6 +15,18 @@ type RunResult struct {
 	RunCommandResult *models.RunCommandResult
 }
 
+// CheckpointResult reports a checkpoint published by a running execution
+type CheckpointResult struct {
+	BaseResponse
+	Checkpoint *models.Checkpoint
+}
+
+// PartialResult reports the results published so far by a running execution
+type PartialResult struct {
+	BaseResponse
+	PartialResult *models.PartialResult
+}
+
 type ComputeError struct {
 	BaseResponse
 }
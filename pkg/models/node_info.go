@@ -161,6 +161,8 @@ type ComputeNodeInfo struct {
 	// Address is the network location where this compute node can be reached
 	// Format: IPv4 or hostname (e.g., "192.168.1.100" or "node1.example.com")
 	Address string `json:"address"`
+	// CachedInputs identifies the most recently used input sources cached by this compute node
+	CachedInputs []string `json:"CachedInputs,omitempty"`
 }
 
 // Copy provides a copy of the allocation and deep copies the job
@@ -175,6 +177,7 @@ func (c *ComputeNodeInfo) Copy() *ComputeNodeInfo {
 	cpy.ExecutionEngines = slices.Clone(c.ExecutionEngines)
 	cpy.Publishers = slices.Clone(c.Publishers)
 	cpy.StorageSources = slices.Clone(c.StorageSources)
+	cpy.CachedInputs = slices.Clone(c.CachedInputs)
 	cpy.MaxCapacity = copyOrZero(c.MaxCapacity.Copy())
 	cpy.QueueUsedCapacity = copyOrZero(c.QueueUsedCapacity.Copy())
 	cpy.AvailableCapacity = copyOrZero(c.AvailableCapacity.Copy())
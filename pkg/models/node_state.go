@@ -10,6 +10,7 @@ type NodeState struct {
 	// Durable node information
 	Info       NodeInfo            `json:"Info"`
 	Membership NodeMembershipState `json:"Membership"`
+	Scheduling NodeScheduling      `json:"Scheduling"`
 
 	// Deprecated: Use ConnectionState.Status instead
 	Connection NodeConnectionState `json:"Connection"`
@@ -39,3 +40,42 @@ type ConnectionState struct {
 func (s *NodeState) IsConnected() bool {
 	return s.ConnectionState.Status == NodeStates.CONNECTED
 }
+
+// IsCordoned returns true if new executions should not be placed on the node,
+// which is the case for both cordoned and draining nodes.
+func (s *NodeState) IsCordoned() bool {
+	return s.Scheduling.Cordoned || s.Scheduling.Draining
+}
+
+// IsDraining returns true if the node's service and daemon executions should be moved to other nodes.
+func (s *NodeState) IsDraining() bool {
+	return s.Scheduling.Draining
+}
+
+// NodeScheduling tracks whether the orchestrator can place new executions on a node.
+type NodeScheduling struct {
+	// Cordoned nodes keep running their executions, but are not selected for new ones
+	Cordoned bool `json:"Cordoned,omitempty"`
+
+	// Draining nodes are cordoned, and their service and daemon executions are moved to other nodes
+	// while batch executions are left to complete
+	Draining bool `json:"Draining,omitempty"`
+
+	// Message is the reason the node was cordoned or drained
+	Message string `json:"Message,omitempty"`
+
+	// UpdateTime is when the node was last cordoned, drained or uncordoned
+	UpdateTime time.Time `json:"UpdateTime,omitempty"`
+}
+
+// String returns the scheduling state of the node
+func (s NodeScheduling) String() string {
+	switch {
+	case s.Draining:
+		return "DRAINING"
+	case s.Cordoned:
+		return "CORDONED"
+	default:
+		return "ELIGIBLE"
+	}
+}
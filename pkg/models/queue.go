@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// QueuedExecution describes an execution waiting in the queue of a compute node for enough capacity to run
+type QueuedExecution struct {
+	// ExecutionID is the ID of the queued execution
+	ExecutionID string `json:"ExecutionID"`
+	// JobID is the ID of the job of the execution
+	JobID string `json:"JobID"`
+	// JobName is the name of the job of the execution
+	JobName string `json:"JobName"`
+	// Namespace is the namespace of the job, which shares the node fairly with the other namespaces
+	Namespace string `json:"Namespace"`
+	// Priority is the priority of the job
+	Priority int `json:"Priority"`
+	// EffectivePriority is the priority the execution is ordered by, raised the longer it waits in the queue
+	EffectivePriority int `json:"EffectivePriority"`
+	// EnqueuedAt is when the execution was added to the queue
+	EnqueuedAt time.Time `json:"EnqueuedAt"`
+	// Resources is the resources the execution waits for
+	Resources *Resources `json:"Resources,omitempty"`
+}
+
+// ExecutionQueueProvider provides the executions queued on a compute node, in the order they are expected to run
+type ExecutionQueueProvider interface {
+	QueuedExecutions() []QueuedExecution
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+// NamespaceQuota limits the resources that jobs in a namespace can use, so that
+// a single namespace can't take over a shared cluster. Zero values mean no limit.
+type NamespaceQuota struct {
+	// Namespace the quota applies to
+	Namespace string `json:"Namespace"`
+
+	// MaxConcurrentExecutions is the maximum number of executions of the namespace's jobs
+	// that can be active at the same time.
+	MaxConcurrentExecutions int `json:"MaxConcurrentExecutions,omitempty"`
+
+	// MaxQueuedJobs is the maximum number of the namespace's jobs that can be waiting to be scheduled.
+	// Jobs submitted above this limit are rejected.
+	MaxQueuedJobs int `json:"MaxQueuedJobs,omitempty"`
+
+	// MaxResources is the maximum total resources that the active executions of the namespace's jobs
+	// can be allocated. Only the CPU, memory, disk and GPU values that are set are enforced.
+	MaxResources Resources `json:"MaxResources,omitempty"`
+
+	CreateTime int64 `json:"CreateTime"`
+	ModifyTime int64 `json:"ModifyTime"`
+}
+
+// NamespaceUsage is the current usage of a namespace that is counted against its quota
+type NamespaceUsage struct {
+	// Executions is the number of active executions of the namespace's jobs
+	Executions int `json:"Executions"`
+	// QueuedJobs is the number of the namespace's jobs waiting to be scheduled
+	QueuedJobs int `json:"QueuedJobs"`
+	// Resources is the total resources allocated to the active executions of the namespace's jobs
+	Resources Resources `json:"Resources"`
+}
+
+// Normalize sets default values for the quota
+func (q *NamespaceQuota) Normalize() {
+	if q == nil {
+		return
+	}
+	q.Namespace = strings.TrimSpace(q.Namespace)
+	q.MaxResources.GPUs = nil
+}
+
+// Copy returns a deep copy of the quota
+func (q *NamespaceQuota) Copy() *NamespaceQuota {
+	if q == nil {
+		return nil
+	}
+	cp := *q
+	cp.MaxResources = *q.MaxResources.Copy()
+	return &cp
+}
+
+// Validate checks the quota for reasonable configuration
+func (q *NamespaceQuota) Validate() error {
+	if q == nil {
+		return errors.New("missing namespace quota")
+	}
+	return errors.Join(
+		validate.NotBlank(q.Namespace, "namespace quota is missing a namespace"),
+		validate.IsGreaterOrEqualToZero(q.MaxConcurrentExecutions,
+			"max concurrent executions must be >= 0, got %d", q.MaxConcurrentExecutions),
+		validate.IsGreaterOrEqualToZero(q.MaxQueuedJobs, "max queued jobs must be >= 0, got %d", q.MaxQueuedJobs),
+		validate.IsGreaterOrEqualToZero(q.MaxResources.CPU, "max CPU must be >= 0, got %f", q.MaxResources.CPU),
+	)
+}
+
+// IsUnlimited returns true if the quota doesn't limit anything
+func (q *NamespaceQuota) IsUnlimited() bool {
+	return q.MaxConcurrentExecutions == 0 && q.MaxQueuedJobs == 0 && q.MaxResources.IsZero()
+}
+
+// CheckQueue returns a non-empty reason if another job can't be queued given the namespace's usage
+func (q *NamespaceQuota) CheckQueue(usage NamespaceUsage) string {
+	if q.MaxQueuedJobs > 0 && usage.QueuedJobs >= q.MaxQueuedJobs {
+		return fmt.Sprintf("namespace %s already has %d queued jobs out of %d allowed",
+			q.Namespace, usage.QueuedJobs, q.MaxQueuedJobs)
+	}
+	return ""
+}
+
+// CheckExecutions returns a non-empty reason if count new executions, each allocated the given resources,
+// can't be started on top of the namespace's usage without exceeding the quota.
+// Passing an empty usage checks whether the executions could ever fit in the quota.
+func (q *NamespaceQuota) CheckExecutions(usage NamespaceUsage, count int, resources Resources) string {
+	if q.MaxConcurrentExecutions > 0 && usage.Executions+count > q.MaxConcurrentExecutions {
+		return fmt.Sprintf("%d more executions would exceed the limit of %d concurrent executions in namespace %s (%d active)",
+			count, q.MaxConcurrentExecutions, q.Namespace, usage.Executions)
+	}
+	required := usage.Resources.Add(*resources.Multiply(float64(count)))
+	if exceeded := exceededResources(*required, q.MaxResources); len(exceeded) > 0 {
+		return fmt.Sprintf("%d more executions would exceed the %s limits of namespace %s: requires %s out of %s",
+			count, strings.Join(exceeded, ", "), q.Namespace, required.String(), q.MaxResources.String())
+	}
+	return ""
+}
+
+// String returns a human-readable representation of the quota's limits
+func (q *NamespaceQuota) String() string {
+	limits := make([]string, 0, 6)
+	if q.MaxConcurrentExecutions > 0 {
+		limits = append(limits, fmt.Sprintf("executions: %d", q.MaxConcurrentExecutions))
+	}
+	if q.MaxQueuedJobs > 0 {
+		limits = append(limits, fmt.Sprintf("queued jobs: %d", q.MaxQueuedJobs))
+	}
+	if q.MaxResources.CPU > 0 {
+		limits = append(limits, fmt.Sprintf("cpu: %g", q.MaxResources.CPU))
+	}
+	if q.MaxResources.Memory > 0 {
+		limits = append(limits, fmt.Sprintf("memory: %s", humanize.Bytes(q.MaxResources.Memory)))
+	}
+	if q.MaxResources.Disk > 0 {
+		limits = append(limits, fmt.Sprintf("disk: %s", humanize.Bytes(q.MaxResources.Disk)))
+	}
+	if q.MaxResources.GPU > 0 {
+		limits = append(limits, fmt.Sprintf("gpu: %d", q.MaxResources.GPU))
+	}
+	if len(limits) == 0 {
+		return "unlimited"
+	}
+	return strings.Join(limits, ", ")
+}
+
+// exceededResources returns the names of the resources used above their limit.
+// Resources with a zero limit are not limited.
+func exceededResources(used, limit Resources) []string {
+	var exceeded []string
+	if limit.CPU > 0 && used.CPU > limit.CPU {
+		exceeded = append(exceeded, "CPU")
+	}
+	if limit.Memory > 0 && used.Memory > limit.Memory {
+		exceeded = append(exceeded, "memory")
+	}
+	if limit.Disk > 0 && used.Disk > limit.Disk {
+		exceeded = append(exceeded, "disk")
+	}
+	if limit.GPU > 0 && used.GPU > limit.GPU {
+		exceeded = append(exceeded, "GPU")
+	}
+	return exceeded
+}
@@ -0,0 +1,68 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type NamespaceQuotaTestSuite struct {
+	suite.Suite
+}
+
+func TestNamespaceQuotaTestSuite(t *testing.T) {
+	suite.Run(t, new(NamespaceQuotaTestSuite))
+}
+
+func (suite *NamespaceQuotaTestSuite) TestValidate() {
+	suite.NoError((&NamespaceQuota{Namespace: "team-a"}).Validate())
+	suite.NoError((&NamespaceQuota{Namespace: "team-a", MaxConcurrentExecutions: 10, MaxQueuedJobs: 5}).Validate())
+
+	suite.ErrorContains((&NamespaceQuota{}).Validate(), "missing a namespace")
+	suite.ErrorContains((&NamespaceQuota{Namespace: "team-a", MaxConcurrentExecutions: -1}).Validate(),
+		"max concurrent executions")
+	suite.ErrorContains((&NamespaceQuota{Namespace: "team-a", MaxQueuedJobs: -1}).Validate(), "max queued jobs")
+	suite.ErrorContains((*NamespaceQuota)(nil).Validate(), "missing namespace quota")
+}
+
+func (suite *NamespaceQuotaTestSuite) TestCheckExecutions() {
+	quota := &NamespaceQuota{
+		Namespace:               "team-a",
+		MaxConcurrentExecutions: 4,
+		MaxResources:            Resources{CPU: 4, Memory: 8 * 1024},
+	}
+	execution := Resources{CPU: 1, Memory: 1024, Disk: 1024}
+
+	suite.Empty(quota.CheckExecutions(NamespaceUsage{}, 4, execution), "disk is not limited")
+	suite.Contains(quota.CheckExecutions(NamespaceUsage{}, 5, execution), "limit of 4 concurrent executions")
+
+	usage := NamespaceUsage{Executions: 1, Resources: Resources{CPU: 3.5}}
+	suite.Empty(quota.CheckExecutions(usage, 0, execution))
+	suite.Contains(quota.CheckExecutions(usage, 1, execution), "CPU limits")
+
+	usage = NamespaceUsage{Executions: 1, Resources: Resources{Memory: 8 * 1024}}
+	suite.Contains(quota.CheckExecutions(usage, 1, execution), "memory limits")
+
+	unlimited := &NamespaceQuota{Namespace: "team-b"}
+	suite.True(unlimited.IsUnlimited())
+	suite.Empty(unlimited.CheckExecutions(NamespaceUsage{Executions: 100}, 100, execution))
+}
+
+func (suite *NamespaceQuotaTestSuite) TestCheckQueue() {
+	quota := &NamespaceQuota{Namespace: "team-a", MaxQueuedJobs: 2}
+	suite.Empty(quota.CheckQueue(NamespaceUsage{QueuedJobs: 1}))
+	suite.Contains(quota.CheckQueue(NamespaceUsage{QueuedJobs: 2}), "2 queued jobs out of 2 allowed")
+	suite.Empty((&NamespaceQuota{Namespace: "team-a"}).CheckQueue(NamespaceUsage{QueuedJobs: 100}))
+}
+
+func (suite *NamespaceQuotaTestSuite) TestString() {
+	suite.Equal("unlimited", (&NamespaceQuota{Namespace: "team-a"}).String())
+	quota := &NamespaceQuota{
+		Namespace:               "team-a",
+		MaxConcurrentExecutions: 4,
+		MaxResources:            Resources{CPU: 2.5, GPU: 1},
+	}
+	suite.Equal("executions: 4, cpu: 2.5, gpu: 1", quota.String())
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+)
+
+// ResourceUsage is the summary of the resources an execution actually consumed, as opposed to
+// the resources allocated to it. It is sampled by executors while the execution runs, so
+// zero values mean the executor was not able to measure a resource.
+type ResourceUsage struct {
+	// CPUSeconds is the CPU time consumed by the execution, in seconds
+	CPUSeconds float64 `json:"CPUSeconds,omitempty"`
+	// WallSeconds is how long the execution ran for, in seconds
+	WallSeconds float64 `json:"WallSeconds,omitempty"`
+	// PeakMemory is the highest memory usage of the execution, in bytes
+	PeakMemory uint64 `json:"PeakMemory,omitempty"`
+	// Disk is the disk space written by the execution, in bytes
+	Disk uint64 `json:"Disk,omitempty"`
+}
+
+// Copy returns a deep copy of the resource usage
+func (u *ResourceUsage) Copy() *ResourceUsage {
+	if u == nil {
+		return nil
+	}
+	newU := new(ResourceUsage)
+	*newU = *u
+	return newU
+}
+
+// AverageCPU returns the average number of CPU cores used by the execution while it ran
+func (u *ResourceUsage) AverageCPU() float64 {
+	if u == nil || u.WallSeconds <= 0 {
+		return 0
+	}
+	return u.CPUSeconds / u.WallSeconds
+}
+
+// IsZero returns true if no resource usage was measured
+func (u *ResourceUsage) IsZero() bool {
+	return u == nil || (u.CPUSeconds == 0 && u.WallSeconds == 0 && u.PeakMemory == 0 && u.Disk == 0)
+}
+
+// String returns a human-readable representation of the resource usage
+func (u *ResourceUsage) String() string {
+	if u == nil {
+		return ""
+	}
+	return fmt.Sprintf("{CPU: %.2fs (avg %.2f cores), Peak Memory: %s, Disk: %s}",
+		u.CPUSeconds, u.AverageCPU(), humanize.Bytes(u.PeakMemory), humanize.Bytes(u.Disk))
+}
+
+// JobResourceUsage aggregates the resource usage reported by the executions of a job version
+type JobResourceUsage struct {
+	// JobVersion is the version of the job the usage was aggregated for
+	JobVersion uint64 `json:"JobVersion"`
+	// Executions is the number of executions that reported their usage
+	Executions int `json:"Executions"`
+	// PeakCPU is the highest average number of CPU cores used by an execution
+	PeakCPU float64 `json:"PeakCPU"`
+	// PeakMemory is the highest memory usage of an execution, in bytes
+	PeakMemory uint64 `json:"PeakMemory"`
+	// PeakDisk is the highest disk space written by an execution, in bytes
+	PeakDisk uint64 `json:"PeakDisk"`
+	// TotalCPUSeconds is the CPU time consumed by all the executions, in seconds
+	TotalCPUSeconds float64 `json:"TotalCPUSeconds"`
+}
+
+// Add aggregates the usage of an execution
+func (u *JobResourceUsage) Add(usage *ResourceUsage) {
+	if usage.IsZero() {
+		return
+	}
+	u.Executions++
+	u.PeakCPU = max(u.PeakCPU, usage.AverageCPU())
+	u.PeakMemory = max(u.PeakMemory, usage.PeakMemory)
+	u.PeakDisk = max(u.PeakDisk, usage.Disk)
+	u.TotalCPUSeconds += usage.CPUSeconds
+}
+
+// ResourceRecommendation is the resources a job should request, based on the peak usage
+// of its past executions across all of its versions.
+type ResourceRecommendation struct {
+	// JobID is the ID of the job the recommendation is for
+	JobID string `json:"JobID"`
+	// Requested is the resources requested by the latest version of the job
+	Requested *Resources `json:"Requested,omitempty"`
+	// Recommended is the resources the job should request, or nil if none of its executions reported their usage.
+	// Resources that were not measured are left as requested.
+	Recommended *Resources `json:"Recommended,omitempty"`
+	// Usage is the aggregated usage of each version of the job that has executions reporting it
+	Usage []JobResourceUsage `json:"Usage"`
+	// Warnings describe the resources requested far above the past peaks of the job
+	Warnings []string `json:"Warnings,omitempty"`
+}
@@ -0,0 +1,29 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceUsageAverageCPU(t *testing.T) {
+	require.InDelta(t, 2.0, (&ResourceUsage{CPUSeconds: 20, WallSeconds: 10}).AverageCPU(), 0.001)
+	require.Zero(t, (&ResourceUsage{CPUSeconds: 20}).AverageCPU())
+	require.Zero(t, (*ResourceUsage)(nil).AverageCPU())
+}
+
+func TestResourceUsageIsZero(t *testing.T) {
+	require.True(t, (*ResourceUsage)(nil).IsZero())
+	require.True(t, (&ResourceUsage{}).IsZero())
+	require.False(t, (&ResourceUsage{Disk: 1}).IsZero())
+}
+
+func TestRunCommandResultCopiesResourceUsage(t *testing.T) {
+	result := &RunCommandResult{ResourceUsage: &ResourceUsage{PeakMemory: 42}}
+	copied := result.Copy()
+	copied.ResourceUsage.PeakMemory = 1
+	require.Equal(t, uint64(42), result.GetResourceUsage().PeakMemory)
+	require.Nil(t, (*RunCommandResult)(nil).GetResourceUsage())
+}
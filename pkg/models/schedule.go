@@ -0,0 +1,135 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+// ScheduleOverlapPolicy defines what happens when a scheduled run is due
+// while the previous run of the same job is still in progress.
+type ScheduleOverlapPolicy string
+
+const (
+	// ScheduleOverlapSkip skips the due run if the previous run is still in progress.
+	ScheduleOverlapSkip ScheduleOverlapPolicy = "skip"
+	// ScheduleOverlapQueue starts the due run as soon as the previous run finishes.
+	// Multiple runs that become due while the previous run is in progress are coalesced into one.
+	ScheduleOverlapQueue ScheduleOverlapPolicy = "queue"
+	// ScheduleOverlapReplace stops the previous run and starts the due run.
+	ScheduleOverlapReplace ScheduleOverlapPolicy = "replace"
+)
+
+// maxScheduleTicks caps the number of ticks walked when looking for the latest due tick,
+// such as after a long orchestrator downtime with a high frequency schedule.
+const maxScheduleTicks = 100_000
+
+var cronParser = cron.NewParser(
+	cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// Schedule defines when a batch job should be run on a recurring basis.
+type Schedule struct {
+	// Cron is a standard five fields cron expression, such as "0 2 * * *",
+	// or a predefined descriptor, such as "@daily" or "@every 1h".
+	Cron string `json:"Cron"`
+
+	// Timezone is the IANA time zone name the cron expression is evaluated in, such as "Europe/Berlin".
+	// Defaults to UTC.
+	Timezone string `json:"Timezone,omitempty"`
+
+	// OverlapPolicy defines what happens when a run is due while the previous run is still in progress.
+	// Defaults to skip.
+	OverlapPolicy ScheduleOverlapPolicy `json:"OverlapPolicy,omitempty"`
+}
+
+// Normalize sets default values for the schedule
+func (s *Schedule) Normalize() {
+	if s == nil {
+		return
+	}
+	s.Cron = strings.TrimSpace(s.Cron)
+	s.Timezone = strings.TrimSpace(s.Timezone)
+	s.OverlapPolicy = ScheduleOverlapPolicy(strings.ToLower(strings.TrimSpace(string(s.OverlapPolicy))))
+	if s.OverlapPolicy == "" {
+		s.OverlapPolicy = ScheduleOverlapSkip
+	}
+}
+
+// Copy returns a deep copy of the schedule
+func (s *Schedule) Copy() *Schedule {
+	if s == nil {
+		return nil
+	}
+	cp := *s
+	return &cp
+}
+
+// Validate checks the schedule for reasonable configuration
+func (s *Schedule) Validate() error {
+	if s == nil {
+		return nil
+	}
+	mErr := validate.NotBlank(s.Cron, "schedule is missing a cron expression")
+	if s.Cron != "" {
+		if _, err := s.parse(); err != nil {
+			mErr = errors.Join(mErr, err)
+		}
+	}
+	switch s.OverlapPolicy {
+	case "", ScheduleOverlapSkip, ScheduleOverlapQueue, ScheduleOverlapReplace:
+	default:
+		mErr = errors.Join(mErr, fmt.Errorf("invalid schedule overlap policy %q. must be one of %s, %s or %s",
+			s.OverlapPolicy, ScheduleOverlapSkip, ScheduleOverlapQueue, ScheduleOverlapReplace))
+	}
+	return mErr
+}
+
+// Next returns the first tick of the schedule after the given time,
+// or the zero time if the schedule is invalid or will never fire.
+func (s *Schedule) Next(after time.Time) time.Time {
+	sched, err := s.parse()
+	if err != nil {
+		return time.Time{}
+	}
+	return sched.Next(after)
+}
+
+// LatestTick returns the latest tick of the schedule in the interval (from, to],
+// or the zero time if the schedule did not fire in that interval.
+func (s *Schedule) LatestTick(from, to time.Time) time.Time {
+	sched, err := s.parse()
+	if err != nil {
+		return time.Time{}
+	}
+	var latest time.Time
+	for i, next := 0, sched.Next(from); i < maxScheduleTicks && !next.IsZero() && !next.After(to); i++ {
+		latest = next
+		next = sched.Next(next)
+	}
+	return latest
+}
+
+func (s *Schedule) parse() (cron.Schedule, error) {
+	location := time.UTC
+	if s.Timezone != "" {
+		var err error
+		location, err = time.LoadLocation(s.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule timezone %q: %w", s.Timezone, err)
+		}
+	}
+	sched, err := cronParser.Parse(s.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule cron expression %q: %w", s.Cron, err)
+	}
+	if specSchedule, ok := sched.(*cron.SpecSchedule); ok {
+		specSchedule.Location = location
+	}
+	return sched, nil
+}
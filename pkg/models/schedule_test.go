@@ -0,0 +1,123 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ScheduleTestSuite struct {
+	suite.Suite
+}
+
+func TestScheduleTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduleTestSuite))
+}
+
+func (suite *ScheduleTestSuite) TestNormalize() {
+	schedule := &Schedule{Cron: " 0 2 * * * ", OverlapPolicy: " Queue "}
+	schedule.Normalize()
+	suite.Equal("0 2 * * *", schedule.Cron)
+	suite.Equal(ScheduleOverlapQueue, schedule.OverlapPolicy)
+
+	schedule = &Schedule{Cron: "@daily"}
+	schedule.Normalize()
+	suite.Equal(ScheduleOverlapSkip, schedule.OverlapPolicy, "overlap policy should default to skip")
+}
+
+func (suite *ScheduleTestSuite) TestValidate() {
+	tests := []struct {
+		name      string
+		schedule  *Schedule
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			name:     "ValidCron",
+			schedule: &Schedule{Cron: "0 2 * * *"},
+		},
+		{
+			name:     "ValidDescriptor",
+			schedule: &Schedule{Cron: "@every 1h", OverlapPolicy: ScheduleOverlapReplace},
+		},
+		{
+			name:     "ValidTimezone",
+			schedule: &Schedule{Cron: "30 9 * * 1-5", Timezone: "Europe/Berlin"},
+		},
+		{
+			name:      "MissingCron",
+			schedule:  &Schedule{},
+			expectErr: true,
+			errMsg:    "missing a cron expression",
+		},
+		{
+			name:      "InvalidCron",
+			schedule:  &Schedule{Cron: "not a cron"},
+			expectErr: true,
+			errMsg:    "invalid schedule cron expression",
+		},
+		{
+			name:      "SecondsNotSupported",
+			schedule:  &Schedule{Cron: "0 0 2 * * *"},
+			expectErr: true,
+			errMsg:    "invalid schedule cron expression",
+		},
+		{
+			name:      "InvalidTimezone",
+			schedule:  &Schedule{Cron: "@daily", Timezone: "Mars/Olympus"},
+			expectErr: true,
+			errMsg:    "invalid schedule timezone",
+		},
+		{
+			name:      "InvalidOverlapPolicy",
+			schedule:  &Schedule{Cron: "@daily", OverlapPolicy: "parallel"},
+			expectErr: true,
+			errMsg:    "invalid schedule overlap policy",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := tt.schedule.Validate()
+			if tt.expectErr {
+				suite.Require().Error(err)
+				suite.Contains(err.Error(), tt.errMsg)
+			} else {
+				suite.NoError(err)
+			}
+		})
+	}
+}
+
+func (suite *ScheduleTestSuite) TestNextUsesTimezone() {
+	schedule := &Schedule{Cron: "0 2 * * *", Timezone: "America/New_York"}
+	after := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	// 2am in New York is 7am UTC during standard time
+	suite.Equal(time.Date(2024, 1, 15, 7, 0, 0, 0, time.UTC), schedule.Next(after).UTC())
+}
+
+func (suite *ScheduleTestSuite) TestLatestTick() {
+	schedule := &Schedule{Cron: "0 * * * *"}
+	from := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	suite.True(schedule.LatestTick(from, from.Add(20*time.Minute)).IsZero(), "no tick should be due")
+	suite.Equal(time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC),
+		schedule.LatestTick(from, from.Add(30*time.Minute)), "tick at the end of the interval should be due")
+	suite.Equal(time.Date(2024, 1, 15, 13, 0, 0, 0, time.UTC),
+		schedule.LatestTick(from, from.Add(3*time.Hour)), "missed ticks should be coalesced into the latest one")
+
+	tick := time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)
+	suite.True(schedule.LatestTick(tick, tick.Add(59*time.Minute)).IsZero(), "start of the interval should be excluded")
+}
+
+func (suite *ScheduleTestSuite) TestCopy() {
+	original := &Schedule{Cron: "@daily", Timezone: "UTC", OverlapPolicy: ScheduleOverlapQueue}
+	cp := original.Copy()
+	suite.Equal(original, cp)
+	suite.NotSame(original, cp)
+	suite.Nil((*Schedule)(nil).Copy())
+}
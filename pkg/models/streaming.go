@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultStreamingInterval is how often the results of a task are published while it runs if not configured.
+	DefaultStreamingInterval = 1 * time.Minute
+	// MinStreamingInterval is the minimum interval between publishing the results of a running task.
+	MinStreamingInterval = 10 * time.Second
+)
+
+// StreamingConfig enables publishing the results of a task incrementally while it runs. The compute node
+// periodically publishes the files of the task's result paths that are new or changed through the task's
+// publisher, if the publisher supports streaming, so that results are available before the task completes.
+type StreamingConfig struct {
+	// Interval is how often in seconds the results are published.
+	// Zero means the default interval.
+	Interval int64 `json:"Interval,omitempty"`
+}
+
+// GetInterval returns the interval between publishing the results
+func (c *StreamingConfig) GetInterval() time.Duration {
+	if c == nil || c.Interval == 0 {
+		return DefaultStreamingInterval
+	}
+	return time.Duration(c.Interval) * time.Second
+}
+
+// Copy returns a deep copy of the streaming config
+func (c *StreamingConfig) Copy() *StreamingConfig {
+	if c == nil {
+		return nil
+	}
+	return &StreamingConfig{
+		Interval: c.Interval,
+	}
+}
+
+// Validate is used to check a streaming config for reasonable configuration
+func (c *StreamingConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Interval < 0 {
+		return fmt.Errorf("streaming interval must be non-negative. Found: %d", c.Interval)
+	}
+	if c.Interval > 0 && c.GetInterval() < MinStreamingInterval {
+		return fmt.Errorf("streaming interval must be at least %s. Found: %s", MinStreamingInterval, c.GetInterval())
+	}
+	return nil
+}
+
+// PartialResult is the location of the results published by an execution while it runs.
+type PartialResult struct {
+	// Result is the published location of the results so far
+	Result *SpecConfig `json:"Result"`
+	// Sequence is incremented with each update of the partial result, starting at 1
+	Sequence int `json:"Sequence"`
+	// Complete is true once the execution completed, and the partial result holds all of its results
+	Complete bool `json:"Complete,omitempty"`
+	// UpdateTime is the time the partial result was last updated
+	UpdateTime int64 `json:"UpdateTime"`
+}
+
+// GetUpdateTime returns the time the partial result was last updated
+func (r *PartialResult) GetUpdateTime() time.Time {
+	return time.Unix(0, r.UpdateTime).UTC()
+}
+
+// Copy returns a deep copy of the partial result
+func (r *PartialResult) Copy() *PartialResult {
+	if r == nil {
+		return nil
+	}
+	return &PartialResult{
+		Result:     r.Result.Copy(),
+		Sequence:   r.Sequence,
+		Complete:   r.Complete,
+		UpdateTime: r.UpdateTime,
+	}
+}
+
+// Validate is used to check a partial result is complete
+func (r *PartialResult) Validate() error {
+	if r == nil {
+		return errors.New("partial result is nil")
+	}
+	if r.Sequence < 1 {
+		return fmt.Errorf("partial result sequence must be positive. Found: %d", r.Sequence)
+	}
+	return r.Result.Validate()
+}
@@ -40,6 +40,14 @@ type Task struct {
 	Network *NetworkConfig `json:"Network,omitempty"`
 
 	Timeouts *TimeoutConfig `json:"Timeouts,omitempty"`
+
+	// Checkpoint enables periodic checkpointing of the task, so that a retried execution
+	// can resume from the last checkpoint instead of starting over. Nil disables checkpointing.
+	Checkpoint *CheckpointConfig `json:"Checkpoint,omitempty"`
+
+	// Streaming enables publishing the results of the task incrementally while it runs,
+	// if the task's publisher supports it. Nil publishes the results once the task completes.
+	Streaming *StreamingConfig `json:"Streaming,omitempty"`
 }
 
 func (t *Task) MetricAttributes() []attribute.KeyValue {
@@ -101,6 +109,8 @@ func (t *Task) Copy() *Task {
 	nt.Env = maps.Clone(t.Env)
 	nt.Network = t.Network.Copy()
 	nt.Timeouts = t.Timeouts.Copy()
+	nt.Checkpoint = t.Checkpoint.Copy()
+	nt.Streaming = t.Streaming.Copy()
 	return nt
 }
 
@@ -112,6 +122,12 @@ func (t *Task) Validate() error {
 	if len(t.ResultPaths) > 0 && t.Publisher.IsEmpty() {
 		mErr = errors.Join(mErr, errors.New("publisher must be set if result paths are set"))
 	}
+	if t.Checkpoint != nil && t.Publisher.IsEmpty() {
+		mErr = errors.Join(mErr, errors.New("publisher must be set if checkpointing is enabled"))
+	}
+	if t.Streaming != nil && t.Publisher.IsEmpty() {
+		mErr = errors.Join(mErr, errors.New("publisher must be set if streaming is enabled"))
+	}
 
 	if err := t.Timeouts.Validate(); err != nil {
 		mErr = errors.Join(mErr, fmt.Errorf("task timeouts validation failed: %v", err))
@@ -159,6 +175,14 @@ func (t *Task) ValidateSubmission() error {
 		mErr = errors.Join(mErr, fmt.Errorf("invalid network: %v", err))
 	}
 
+	if err := t.validateCheckpoint(); err != nil {
+		mErr = errors.Join(mErr, err)
+	}
+
+	if err := t.Streaming.Validate(); err != nil {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid streaming: %w", err))
+	}
+
 	return mErr
 }
 
@@ -202,6 +226,29 @@ func (t *Task) validateResultPaths() error {
 	return nil
 }
 
+// validateCheckpoint checks the checkpoint config, and that the paths reserved for checkpoints
+// are not used by the task's own input sources and result paths
+func (t *Task) validateCheckpoint() error {
+	if t.Checkpoint == nil {
+		return nil
+	}
+	if err := t.Checkpoint.Validate(); err != nil {
+		return fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	// the input source restoring the last checkpoint is only added by the orchestrator when retrying
+	for _, input := range t.InputSources {
+		if input.Target == CheckpointPath || (input.Target == ResumeCheckpointPath) != (input.Alias == ResumeCheckpointAlias) {
+			return fmt.Errorf("input source '%s' at '%s' is reserved for checkpoints", input.Alias, input.Target)
+		}
+	}
+	for _, result := range t.ResultPaths {
+		if result.Name == CheckpointResultName || result.Path == CheckpointPath {
+			return fmt.Errorf("result path '%s' is reserved for checkpoints", result.Path)
+		}
+	}
+	return nil
+}
+
 func (t *Task) AllStorageTypes() []string {
 	uniqueTypes := make(map[string]bool)
 	for _, a := range t.InputSources {
@@ -189,6 +189,27 @@ func (suite *TaskTestSuite) TestTaskValidation() {
 			validationMode: submissionError,
 			errMsg:         "invalid timeouts",
 		},
+		{
+			name: "Streaming without publisher",
+			task: &Task{
+				Name:      "missing-publisher",
+				Engine:    &SpecConfig{Type: "docker"},
+				Streaming: &StreamingConfig{},
+			},
+			validationMode: postSubmissionError,
+			errMsg:         "publisher must be set if streaming is enabled",
+		},
+		{
+			name: "Invalid streaming interval",
+			task: &Task{
+				Name:      "invalid-streaming",
+				Engine:    &SpecConfig{Type: "docker"},
+				Publisher: &SpecConfig{Type: "s3"},
+				Streaming: &StreamingConfig{Interval: 1},
+			},
+			validationMode: submissionError,
+			errMsg:         "invalid streaming",
+		},
 		{
 			name: "Invalid resources",
 			task: &Task{
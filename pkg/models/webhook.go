@@ -0,0 +1,131 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+// WebhookEventType is a job lifecycle transition that webhooks can be notified of
+type WebhookEventType string
+
+const (
+	// WebhookEventJobStateChanged is triggered when a job transitions to a new state
+	WebhookEventJobStateChanged WebhookEventType = "JobStateChanged"
+	// WebhookEventExecutionFailed is triggered when an execution of a job fails
+	WebhookEventExecutionFailed WebhookEventType = "ExecutionFailed"
+)
+
+// WebhookEventTypes returns all the webhook event types
+func WebhookEventTypes() []WebhookEventType {
+	return []WebhookEventType{WebhookEventJobStateChanged, WebhookEventExecutionFailed}
+}
+
+// Webhook is an HTTP endpoint that is notified of job lifecycle transitions.
+type Webhook struct {
+	// URL is the http or https endpoint notifications are posted to.
+	URL string `json:"URL"`
+
+	// Events filters the transitions the webhook is notified of.
+	// The webhook is notified of all transitions if empty.
+	Events []WebhookEventType `json:"Events,omitempty"`
+
+	// JobStates filters the job state changes the webhook is notified of to the given
+	// states, such as Completed and Failed. All state changes are notified if empty.
+	JobStates []JobStateType `json:"JobStates,omitempty"`
+}
+
+// Normalize canonicalizes the webhook's fields
+func (w *Webhook) Normalize() {
+	if w == nil {
+		return
+	}
+	w.URL = strings.TrimSpace(w.URL)
+	for i, event := range w.Events {
+		for _, typ := range WebhookEventTypes() {
+			if strings.EqualFold(string(typ), strings.TrimSpace(string(event))) {
+				w.Events[i] = typ
+			}
+		}
+	}
+}
+
+// Copy returns a deep copy of the webhook
+func (w *Webhook) Copy() *Webhook {
+	if w == nil {
+		return nil
+	}
+	cp := *w
+	cp.Events = slices.Clone(w.Events)
+	cp.JobStates = slices.Clone(w.JobStates)
+	return &cp
+}
+
+// Validate checks the webhook for reasonable configuration
+func (w *Webhook) Validate() error {
+	if w == nil {
+		return errors.New("webhook is nil")
+	}
+	mErr := validate.NotBlank(w.URL, "webhook is missing a URL")
+	if w.URL != "" {
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			mErr = errors.Join(mErr, fmt.Errorf("invalid webhook URL %q. must be an http or https URL", w.URL))
+		}
+	}
+	for _, event := range w.Events {
+		if !slices.Contains(WebhookEventTypes(), event) {
+			mErr = errors.Join(mErr, fmt.Errorf("invalid webhook event %q. valid events are %s and %s",
+				event, WebhookEventJobStateChanged, WebhookEventExecutionFailed))
+		}
+	}
+	for _, state := range w.JobStates {
+		if state.IsUndefined() {
+			mErr = errors.Join(mErr, errors.New("invalid webhook job state"))
+		}
+	}
+	return mErr
+}
+
+// NotifiesJobState returns true if the webhook is notified of jobs transitioning to the given state
+func (w *Webhook) NotifiesJobState(state JobStateType) bool {
+	return w.notifies(WebhookEventJobStateChanged) && (len(w.JobStates) == 0 || slices.Contains(w.JobStates, state))
+}
+
+// NotifiesExecutionFailure returns true if the webhook is notified of failed executions
+func (w *Webhook) NotifiesExecutionFailure() bool {
+	return w.notifies(WebhookEventExecutionFailed)
+}
+
+func (w *Webhook) notifies(event WebhookEventType) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
+
+// WebhookPayload is the JSON body posted to webhooks
+type WebhookPayload struct {
+	// ID identifies the notification, and is the same across delivery retries
+	ID        string           `json:"ID"`
+	Event     WebhookEventType `json:"Event"`
+	Timestamp time.Time        `json:"Timestamp"`
+
+	JobID      string `json:"JobID"`
+	JobName    string `json:"JobName"`
+	Namespace  string `json:"Namespace"`
+	JobVersion uint64 `json:"JobVersion"`
+	// JobState is the current state of the job
+	JobState JobStateType `json:"JobState"`
+	// PreviousJobState is the state the job transitioned from, if the job state changed
+	PreviousJobState JobStateType `json:"PreviousJobState,omitempty"`
+
+	// ExecutionID and NodeID identify the failed execution, if an execution failed
+	ExecutionID string `json:"ExecutionID,omitempty"`
+	NodeID      string `json:"NodeID,omitempty"`
+
+	// Message describes the transition, such as the reason of a failure
+	Message string `json:"Message,omitempty"`
+}
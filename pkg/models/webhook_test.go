@@ -0,0 +1,68 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
+
+func (suite *WebhookTestSuite) TestNormalize() {
+	webhook := &Webhook{URL: " https://example.com/hook ", Events: []WebhookEventType{" jobstatechanged "}}
+	webhook.Normalize()
+	suite.Equal("https://example.com/hook", webhook.URL)
+	suite.Equal([]WebhookEventType{WebhookEventJobStateChanged}, webhook.Events)
+}
+
+func (suite *WebhookTestSuite) TestValidate() {
+	suite.NoError((&Webhook{URL: "https://example.com/hook"}).Validate())
+	suite.NoError((&Webhook{
+		URL:       "http://ci.internal:8080/hook",
+		Events:    WebhookEventTypes(),
+		JobStates: []JobStateType{JobStateTypeCompleted, JobStateTypeFailed},
+	}).Validate())
+
+	suite.ErrorContains((&Webhook{}).Validate(), "missing a URL")
+	suite.ErrorContains((&Webhook{URL: "example.com/hook"}).Validate(), "invalid webhook URL")
+	suite.ErrorContains((&Webhook{URL: "https://example.com", Events: []WebhookEventType{"Other"}}).Validate(),
+		"invalid webhook event")
+	suite.ErrorContains((&Webhook{URL: "https://example.com", JobStates: []JobStateType{JobStateTypeUndefined}}).Validate(),
+		"invalid webhook job state")
+}
+
+func (suite *WebhookTestSuite) TestNotifies() {
+	all := &Webhook{URL: "https://example.com"}
+	suite.True(all.NotifiesJobState(JobStateTypeRunning))
+	suite.True(all.NotifiesExecutionFailure())
+
+	terminal := &Webhook{
+		URL:       "https://example.com",
+		Events:    []WebhookEventType{WebhookEventJobStateChanged},
+		JobStates: []JobStateType{JobStateTypeCompleted, JobStateTypeFailed},
+	}
+	suite.True(terminal.NotifiesJobState(JobStateTypeFailed))
+	suite.False(terminal.NotifiesJobState(JobStateTypeRunning))
+	suite.False(terminal.NotifiesExecutionFailure())
+}
+
+func (suite *WebhookTestSuite) TestJSON() {
+	var webhook Webhook
+	suite.Require().NoError(json.Unmarshal(
+		[]byte(`{"URL": "https://example.com", "JobStates": ["Completed", "failed"]}`), &webhook))
+	suite.Equal([]JobStateType{JobStateTypeCompleted, JobStateTypeFailed}, webhook.JobStates)
+
+	data, err := json.Marshal(WebhookPayload{JobState: JobStateTypeCompleted})
+	suite.Require().NoError(err)
+	suite.Contains(string(data), `"JobState":"Completed"`)
+	suite.NotContains(string(data), "PreviousJobState")
+}
@@ -0,0 +1,51 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// keyValueCreateAttemptTimeout bounds each attempt to create a key-value bucket. When the servers
+	// of a cluster are asked to create the same bucket concurrently, such as by orchestrators starting
+	// together, all but one of the requests may go unanswered, and succeed when retried.
+	keyValueCreateAttemptTimeout = 2 * time.Second
+
+	// keyValueCreateAttempts is the number of attempts to create a key-value bucket
+	keyValueCreateAttempts = 5
+)
+
+// CreateKeyValue creates a key-value bucket, or returns it if it exists with the same configuration.
+// Attempts that time out are retried.
+func CreateKeyValue(ctx context.Context, js jetstream.JetStream, cfg jetstream.KeyValueConfig) (jetstream.KeyValue, error) {
+	return retryKeyValueCreation(ctx, func(ctx context.Context) (jetstream.KeyValue, error) {
+		return js.CreateKeyValue(ctx, cfg)
+	})
+}
+
+// CreateOrUpdateKeyValue creates a key-value bucket, or updates the configuration of the existing one.
+// Attempts that time out are retried.
+func CreateOrUpdateKeyValue(
+	ctx context.Context, js jetstream.JetStream, cfg jetstream.KeyValueConfig) (jetstream.KeyValue, error) {
+	return retryKeyValueCreation(ctx, func(ctx context.Context) (jetstream.KeyValue, error) {
+		return js.CreateOrUpdateKeyValue(ctx, cfg)
+	})
+}
+
+func retryKeyValueCreation(
+	ctx context.Context, create func(ctx context.Context) (jetstream.KeyValue, error)) (jetstream.KeyValue, error) {
+	var err error
+	for attempt := 0; attempt < keyValueCreateAttempts; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, keyValueCreateAttemptTimeout)
+		var kv jetstream.KeyValue
+		kv, err = create(attemptCtx)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+			return kv, err
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,135 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/execstream"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models/messages"
+	"github.com/bacalhau-project/bacalhau/pkg/nats/stream"
+)
+
+// ExecHandlerParams defines parameters for creating a new ExecHandler.
+type ExecHandlerParams struct {
+	Name       string
+	Conn       *nats.Conn
+	ExecServer execstream.Server
+}
+
+// ExecHandler handles NATS requests to run commands inside executions running on a compute node.
+// The output of commands is streamed back the same way as logs, while their input is received
+// on a subject of its own chosen by the requester.
+type ExecHandler struct {
+	name            string
+	conn            *nats.Conn
+	execServer      execstream.Server
+	subscription    *nats.Subscription
+	streamingClient *stream.ProducerClient
+}
+
+// NewExecHandler creates a new ExecHandler.
+func NewExecHandler(ctx context.Context, params ExecHandlerParams) (*ExecHandler, error) {
+	streamingClient, err := stream.NewProducerClient(ctx, stream.ProducerClientParams{
+		Conn: params.Conn,
+		Config: stream.StreamProducerClientConfig{
+			HeartBeatIntervalDuration:        stream.DefaultHeartBeatIntervalDuration,
+			HeartBeatRequestTimeout:          stream.DefaultHeartBeatRequestTimeout,
+			StreamCancellationBufferDuration: stream.DefaultStreamCancellationBufferDuration,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	handler := &ExecHandler{
+		name:            params.Name,
+		conn:            params.Conn,
+		execServer:      params.ExecServer,
+		streamingClient: streamingClient,
+	}
+
+	subject := computeEndpointSubscribeSubject(handler.name)
+	subscription, err := handler.conn.Subscribe(subject, func(m *nats.Msg) {
+		handler.handleRequest(m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	handler.subscription = subscription
+	log.Debug().Msgf("NATS exec handler subscribed to %s", subject)
+	return handler, nil
+}
+
+// handleRequest handles incoming NATS requests.
+func (handler *ExecHandler) handleRequest(msg *nats.Msg) {
+	ctx := context.Background()
+
+	subjectParts := strings.Split(msg.Subject, ".")
+	method := subjectParts[len(subjectParts)-1]
+
+	switch method {
+	case Exec:
+		processAndStream(ctx, handler.streamingClient, msg, handler.exec)
+	default:
+		// Noop, not subscribed to this method
+		return
+	}
+}
+
+// exec runs the command of the request, with the input received on the request's stdin subject if any
+func (handler *ExecHandler) exec(ctx context.Context, request messages.ExecRequest) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	if request.StdinSubject == "" {
+		return handler.execServer.Exec(ctx, request, nil)
+	}
+
+	stdin, stdinWriter := io.Pipe()
+	subscription, err := handler.conn.Subscribe(request.StdinSubject, func(m *nats.Msg) {
+		input := new(models.ExecInput)
+		if err := json.Unmarshal(m.Data, input); err != nil {
+			_ = stdinWriter.CloseWithError(err)
+			return
+		}
+		if len(input.Data) > 0 {
+			if _, err := stdinWriter.Write(input.Data); err != nil {
+				return
+			}
+		}
+		if input.EOF {
+			_ = stdinWriter.Close()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() {
+		_ = subscription.Unsubscribe()
+		_ = stdinWriter.CloseWithError(io.ErrClosedPipe)
+	}
+
+	res, err := handler.execServer.Exec(ctx, request, stdin)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	out := make(chan *concurrency.AsyncResult[models.ExecOutput], asyncRequestChanLen)
+	go func() {
+		defer close(out)
+		defer cleanup()
+		for result := range res {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- result:
+			}
+		}
+	}()
+	return out, nil
+}
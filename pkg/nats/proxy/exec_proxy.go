@@ -0,0 +1,116 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/execstream"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models/messages"
+	"github.com/bacalhau-project/bacalhau/pkg/nats/stream"
+)
+
+// execInputChunkSize is the maximum size of the input of a command published in a single message
+const execInputChunkSize = 32 * 1024
+
+type ExecProxyParams struct {
+	Conn *nats.Conn
+}
+
+// ExecProxy is a proxy to the exec handlers of remote compute nodes. The output of commands is streamed
+// back from the compute node, while their input is published to a subject the compute node subscribes to.
+type ExecProxy struct {
+	conn            *nats.Conn
+	streamingClient *stream.ConsumerClient
+}
+
+func NewExecProxy(params ExecProxyParams) (*ExecProxy, error) {
+	sc, err := stream.NewConsumerClient(stream.ConsumerClientParams{
+		Conn: params.Conn,
+		Config: stream.StreamConsumerClientConfig{
+			StreamCancellationBufferDuration: streamCancellationBufferDuration,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ExecProxy{
+		conn:            params.Conn,
+		streamingClient: sc,
+	}, nil
+}
+
+// Exec runs a command inside an execution running on a remote compute node. The input, if not nil,
+// is forwarded to the compute node once the command is started.
+func (p *ExecProxy) Exec(ctx context.Context, request messages.ExecRequest, stdin io.Reader) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	if stdin != nil {
+		request.StdinSubject = nats.NewInbox()
+	}
+	res, err := proxyStreamingRequest[messages.ExecRequest, models.ExecOutput](
+		ctx, p.streamingClient, &BaseRequest[messages.ExecRequest]{
+			TargetNodeID: request.NodeID,
+			Method:       Exec,
+			Body:         request,
+		})
+	if err != nil || stdin == nil {
+		return res, err
+	}
+
+	out := make(chan *concurrency.AsyncResult[models.ExecOutput], asyncRequestChanLen)
+	go func() {
+		defer close(out)
+		forwarding := false
+		for result := range res {
+			// the compute node is only subscribed to the input once the command is started
+			if !forwarding && result.Err == nil && result.Value.Type == models.ExecOutputStarted {
+				forwarding = true
+				go p.forwardInput(ctx, request.StdinSubject, stdin)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- result:
+			}
+		}
+	}()
+	return out, nil
+}
+
+// forwardInput publishes the input to the subject until it is exhausted or the context is done
+func (p *ExecProxy) forwardInput(ctx context.Context, subject string, stdin io.Reader) {
+	publish := func(input models.ExecInput) error {
+		data, err := json.Marshal(input)
+		if err != nil {
+			return err
+		}
+		return p.conn.Publish(subject, data)
+	}
+
+	buf := make([]byte, execInputChunkSize)
+	for ctx.Err() == nil {
+		n, err := stdin.Read(buf)
+		if n > 0 {
+			if pubErr := publish(models.ExecInput{Data: buf[:n]}); pubErr != nil {
+				log.Ctx(ctx).Warn().Err(pubErr).Msg("failed to forward exec input")
+				return
+			}
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Ctx(ctx).Debug().Err(err).Msg("exec input closed")
+			}
+			_ = publish(models.ExecInput{EOF: true})
+			return
+		}
+	}
+}
+
+// Compile-time interface check:
+var _ execstream.Server = (*ExecProxy)(nil)
@@ -16,6 +16,13 @@ import (
 
 const ReadyForConnectionsTimeout = 5 * time.Second
 
+// JetStreamClusterTimeout is how long to wait for the servers of a cluster to elect the
+// leader of JetStream's metadata, without which JetStream can't be used
+const JetStreamClusterTimeout = 2 * time.Minute
+
+// jetStreamClusterPollInterval is how often to check whether JetStream's cluster is ready
+const jetStreamClusterPollInterval = 100 * time.Millisecond
+
 type ServerManagerParams struct {
 	Options           *server.Options
 	ConnectionTimeout time.Duration
@@ -67,6 +74,42 @@ func NewServerManager(ctx context.Context, params ServerManagerParams) (*ServerM
 	}, nil
 }
 
+// WaitForJetStreamCluster waits for the server to join a JetStream cluster with an elected
+// metadata leader, which requires a majority of the cluster's servers to be running.
+// It returns immediately if the server is not clustered.
+func (sm *ServerManager) WaitForJetStreamCluster(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(jetStreamClusterPollInterval)
+	defer ticker.Stop()
+	for !sm.jetStreamClusterReady() {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return bacerrors.Newf("orchestrator NATS server did not join a JetStream cluster within %s", timeout).
+				WithComponent(transportServerComponent).
+				WithCode(bacerrors.ConfigurationError).
+				WithHint("Make sure that a majority of the orchestrators of the cluster are running and " +
+					"reachable on the addresses configured in their cluster peers")
+		}
+	}
+	return nil
+}
+
+// jetStreamClusterReady returns true if the server is not clustered, or if it is current
+// with an elected metadata leader of its JetStream cluster
+func (sm *ServerManager) jetStreamClusterReady() bool {
+	if !sm.Server.JetStreamIsCurrent() {
+		return false
+	}
+	if !sm.Server.JetStreamIsClustered() {
+		return true
+	}
+	info, err := sm.Server.Jsz(&server.JSzOptions{})
+	return err == nil && info.Meta != nil && info.Meta.Leader != ""
+}
+
 // Stop stops the NATS server
 func (sm *ServerManager) Stop() {
 	sm.Server.Shutdown()
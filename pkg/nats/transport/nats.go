@@ -44,6 +44,8 @@ type NATSTransportConfig struct {
 	ClusterPort              int
 	ClusterAdvertisedAddress string
 	ClusterPeers             []string
+	// ClusterAllowLocalPeers keeps peers with a local address, such as when running several orchestrators on a single host
+	ClusterAllowLocalPeers bool
 
 	// TLS
 	ServerTLSCert    string
@@ -179,7 +181,7 @@ func NewNATSTransport(ctx context.Context,
 		// Only set cluster options if cluster peers are provided. Jetstream doesn't
 		// like the setting to be present with no values, or with values that are
 		// a local address (e.g. it can't RAFT to itself).
-		routes, err := nats_helper.RoutesFromSlice(config.ClusterPeers, false)
+		routes, err := nats_helper.RoutesFromSlice(config.ClusterPeers, config.ClusterAllowLocalPeers)
 		if err != nil {
 			return nil, err
 		}
@@ -202,6 +204,16 @@ func NewNATSTransport(ctx context.Context,
 			return nil, err
 		}
 
+		// JetStream, which backs the state shared by the orchestrators, is only
+		// available once a majority of the cluster's servers are running
+		if len(config.ClusterPeers) > 0 {
+			log.Ctx(ctx).Info().Msg("Waiting for orchestrator cluster peers to join the JetStream cluster")
+			if err = sm.WaitForJetStreamCluster(ctx, nats_helper.JetStreamClusterTimeout); err != nil {
+				sm.Stop()
+				return nil, err
+			}
+		}
+
 		if config.ServerSupportReverseProxy {
 			// Server.ClientURL() (in core NATS code), will check if TLSConfig of the server
 			// is not null, and changes the URL Scheme from "nats" to "tls". When running
@@ -15,6 +15,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity/disk"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/env"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/execstream"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/logstream"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/sensors"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
@@ -45,6 +46,7 @@ type Compute struct {
 	Publishers         publisher.PublisherProvider
 	Bidder             compute.Bidder
 	Watchers           watcher.Manager
+	Queue              models.ExecutionQueueProvider
 	cleanupFunc        func(ctx context.Context)
 	debugInfoProviders []models.DebugInfoProvider
 }
@@ -120,6 +122,11 @@ func NewComputeNode(
 	if cfg.BacalhauConfig.JobAdmissionControl.RejectNetworkedJobs {
 		defaultNetworkType = models.NetworkNone
 	}
+	resultStreamer := compute.NewResultStreamer(compute.ResultStreamerParams{
+		Store:       executionStore,
+		Publishers:  publishers,
+		ResultsPath: *resultsPath,
+	})
 	baseExecutor := compute.NewBaseExecutor(compute.BaseExecutorParams{
 		ID:                     cfg.NodeID,
 		Store:                  executionStore,
@@ -131,6 +138,7 @@ func NewComputeNode(
 		ResultsPath:            *resultsPath,
 		EnvResolver:            envResolver,
 		PortAllocator:          portAllocator,
+		ResultStreamer:         resultStreamer,
 		DefaultNetworkType:     defaultNetworkType,
 	})
 
@@ -139,6 +147,16 @@ func NewComputeNode(
 		DelegateExecutor:       baseExecutor,
 		RunningCapacityTracker: runningCapacityTracker,
 		EnqueuedUsageTracker:   enqueuedUsageTracker,
+		Checkpointer: compute.NewCheckpointer(compute.CheckpointerParams{
+			Store:       executionStore,
+			Publishers:  publishers,
+			ResultsPath: *resultsPath,
+		}),
+		ResultStreamer:   resultStreamer,
+		NamespaceWeights: cfg.BacalhauConfig.Compute.Queue.NamespaceWeights,
+		AgingInterval:    cfg.BacalhauConfig.Compute.Queue.AgingInterval.AsTimeDuration(),
+
+		ReservationThreshold: cfg.BacalhauConfig.Compute.Queue.ReservationThreshold.AsTimeDuration(),
 	})
 	runningInfoProvider := sensors.NewRunningExecutionsInfoProvider(sensors.RunningExecutionsInfoProviderParams{
 		Name:          "ActiveJobs",
@@ -236,6 +254,10 @@ func NewComputeNode(
 			Executors:      executors,
 			ResultsPath:    *resultsPath,
 		}),
+		ExecServer: execstream.NewServer(execstream.ServerParams{
+			ExecutionStore: executionStore,
+			Executors:      executors,
+		}),
 	})
 	if err != nil {
 		return nil, err
@@ -275,6 +297,7 @@ func NewComputeNode(
 		if err = executionStore.Close(ctx); err != nil {
 			log.Error().Err(err).Msg("failed to close execution store")
 		}
+		closeExecutors(ctx, executors)
 		// TODO: Remove this behaviour once we have proper execution metadata garbage collection.
 		if err = resultsPath.Close(); err != nil {
 			log.Error().Err(err).Msg("failed to close results path")
@@ -291,11 +314,27 @@ func NewComputeNode(
 		Publishers:         publishers,
 		Bidder:             bidder,
 		Watchers:           watcherRegistry,
+		Queue:              bufferRunner,
 		cleanupFunc:        cleanupFunc,
 		debugInfoProviders: debugInfoProviders,
 	}, nil
 }
 
+// closeExecutors releases the resources held by the executors that have any, such as databases
+func closeExecutors(ctx context.Context, executors executor.ExecProvider) {
+	for _, key := range executors.Keys(ctx) {
+		exec, err := executors.Get(ctx, key)
+		if err != nil {
+			continue
+		}
+		if c, ok := exec.(interface{ Close(context.Context) error }); ok {
+			if err = c.Close(ctx); err != nil {
+				log.Error().Err(err).Str("executor", key).Msg("failed to close executor")
+			}
+		}
+	}
+}
+
 func createExecutionStore(ctx context.Context, cfg NodeConfig) (store.ExecutionStore, error) {
 	executionStoreDBPath, err := cfg.BacalhauConfig.ExecutionStoreFilePath()
 	if err != nil {
@@ -29,6 +29,10 @@ type SystemConfig struct {
 	// ExecutionLimitBackoff is the duration to wait before creating a new evaluation when hitting execution limits
 	ExecutionLimitBackoff time.Duration
 
+	// AllowLocalClusterPeers keeps orchestrator cluster peers with a local address, which are otherwise
+	// ignored, to run a cluster of orchestrators on a single host for testing purposes
+	AllowLocalClusterPeers bool
+
 	///////////////////////////////
 	// Compute Specific Config
 	///////////////////////////////
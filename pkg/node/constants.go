@@ -16,4 +16,8 @@ const (
 	// orchestratorExecutionLoggerWatcherID is the ID of the watcher that listens for execution events
 	// and logs them.
 	orchestratorExecutionLoggerWatcherID = "orchestrator-logger"
+
+	// orchestratorWebhookNotifierWatcherID is the ID of the watcher that listens for job and execution
+	// events and notifies webhooks of job state changes and execution failures.
+	orchestratorWebhookNotifierWatcherID = "webhook-notifier"
 )
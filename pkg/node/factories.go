@@ -63,10 +63,22 @@ func NewStandardStorageProvidersFactory(cfg types.Bacalhau) StorageProvidersFact
 func NewStandardExecutorsFactory(cfg types.EngineConfig) ExecutorsFactory {
 	return ExecutorsFactoryFunc(
 		func(ctx context.Context, nodeConfig NodeConfig) (executor.ExecProvider, error) {
+			wasmCompilationCacheDir, err := nodeConfig.BacalhauConfig.WASMCompilationCacheDir()
+			if err != nil {
+				return nil, err
+			}
+			wasmKeyValueStorePath, err := nodeConfig.BacalhauConfig.WASMKeyValueStoreFilePath()
+			if err != nil {
+				return nil, err
+			}
 			pr, err := executor_util.NewStandardExecutorProvider(
 				cfg,
 				executor_util.StandardExecutorOptions{
-					DockerID: fmt.Sprintf("bacalhau-%s", nodeConfig.NodeID),
+					DockerID:                fmt.Sprintf("bacalhau-%s", nodeConfig.NodeID),
+					WASMCompilationCacheDir: wasmCompilationCacheDir,
+					WASMKeyValueStorePath:   wasmKeyValueStorePath,
+					AllowListedBinaries:     nodeConfig.BacalhauConfig.Compute.AllowListedBinaries,
+					AllowListedExecEnv:      nodeConfig.BacalhauConfig.Compute.AllowListedExecEnv,
 				},
 			)
 			if err != nil {
@@ -0,0 +1,278 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/watcher"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models/messages"
+	natsutil "github.com/bacalhau-project/bacalhau/pkg/nats"
+	nats_transport "github.com/bacalhau-project/bacalhau/pkg/nats/transport"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/evaluation"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/nodes"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/watchers"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/s3managed"
+	bprotocolorchestrator "github.com/bacalhau-project/bacalhau/pkg/transport/bprotocol/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/transport/nclprotocol"
+	transportorchestrator "github.com/bacalhau-project/bacalhau/pkg/transport/nclprotocol/orchestrator"
+)
+
+// maxClusterReplicas is the maximum number of orchestrators holding a copy of the
+// state shared through NATS when running with leader election
+const maxClusterReplicas = 3
+
+// leaderServices are the orchestrator components that must run on a single orchestrator at a time:
+// the workers and evaluation broker scheduling jobs, the watchers feeding the broker and notifying
+// webhooks, and the node manager and connections tracking the live state of compute nodes.
+// Without leader election, they run for the lifetime of the orchestrator. With leader election,
+// they run while the orchestrator is the leader, and the other orchestrators only serve API requests.
+type leaderServices struct {
+	cfg                            NodeConfig
+	transportLayer                 *nats_transport.NATSTransport
+	jobStore                       jobstore.Store
+	nodesManager                   nodes.Manager
+	protocolRouter                 *watchers.ProtocolRouter
+	evalBroker                     *evaluation.InMemoryBroker
+	schedulerProvider              orchestrator.SchedulerProvider
+	webhookNotifier                *watchers.WebhookNotifier
+	s3ManagedPublisherURLGenerator *s3managed.PreSignedURLGenerator
+	// restoreEvaluations creates an evaluation of every in progress job when the services start,
+	// as the evaluations that were in the broker of the previous leader are lost.
+	restoreEvaluations bool
+
+	mu sync.Mutex
+	// stopFuncs stop the running services in the reverse order they were started
+	stopFuncs []func(ctx context.Context)
+}
+
+// start starts the services, or does nothing if they are running.
+// If a service fails to start, the ones already started are stopped.
+func (l *leaderServices) start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.stopFuncs) > 0 {
+		return nil
+	}
+	if err := l.startServices(ctx); err != nil {
+		l.stopServices(ctx)
+		return err
+	}
+	return nil
+}
+
+// stop stops the services, or does nothing if they are not running
+func (l *leaderServices) stop(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stopServices(ctx)
+}
+
+// onLeadershipChange starts the services when the orchestrator is elected as leader, and stops them when it is not anymore
+func (l *leaderServices) onLeadershipChange(ctx context.Context, leader bool) error {
+	if leader {
+		return l.start(ctx)
+	}
+	l.stop(ctx)
+	return nil
+}
+
+func (l *leaderServices) stopServices(ctx context.Context) {
+	for i := len(l.stopFuncs) - 1; i >= 0; i-- {
+		l.stopFuncs[i](ctx)
+	}
+	l.stopFuncs = nil
+}
+
+func (l *leaderServices) onStop(fn func(ctx context.Context)) {
+	l.stopFuncs = append(l.stopFuncs, fn)
+}
+
+//nolint:funlen
+func (l *leaderServices) startServices(ctx context.Context) error {
+	l.evalBroker.SetEnabled(true)
+	l.onStop(func(context.Context) {
+		l.evalBroker.SetEnabled(false)
+	})
+
+	workerCount := l.cfg.BacalhauConfig.Orchestrator.Scheduler.WorkerCount
+	workers := make([]*orchestrator.Worker, 0, workerCount)
+	for i := 1; i <= workerCount; i++ {
+		log.Debug().Msgf("Starting worker %d", i)
+		// worker config the polls from the broker
+		worker := orchestrator.NewWorker(orchestrator.WorkerParams{
+			SchedulerProvider: l.schedulerProvider,
+			EvaluationBroker:  l.evalBroker,
+		})
+		workers = append(workers, worker)
+		worker.Start(ctx)
+	}
+	l.onStop(func(context.Context) {
+		for _, worker := range workers {
+			worker.Stop()
+		}
+	})
+
+	if err := l.nodesManager.Start(ctx); err != nil {
+		return err
+	}
+	l.onStop(func(ctx context.Context) {
+		if err := l.nodesManager.Stop(ctx); err != nil {
+			logDebugIfContextCancelled(ctx, err, "failed to cleanly shutdown node manager")
+		}
+	})
+
+	// the legacy connection manager has its own client, as closing it is
+	// what stops the handlers it subscribes to
+	legacyConn, err := l.transportLayer.CreateClient(ctx)
+	if err != nil {
+		return err
+	}
+	l.onStop(func(context.Context) {
+		legacyConn.Close()
+	})
+
+	// legacy connection manager
+	legacyConnectionManager, err := bprotocolorchestrator.NewConnectionManager(bprotocolorchestrator.Config{
+		NodeID:         l.cfg.NodeID,
+		NatsConn:       legacyConn,
+		NodeManager:    l.nodesManager,
+		EventStore:     l.jobStore.GetEventStore(),
+		ProtocolRouter: l.protocolRouter,
+		Callback:       orchestrator.NewCallback(&orchestrator.CallbackParams{ID: l.cfg.NodeID, Store: l.jobStore}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create connection manager: %w", err)
+	}
+	if err = legacyConnectionManager.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start connection manager: %w", err)
+	}
+	l.onStop(legacyConnectionManager.Stop)
+
+	// connection manager
+	connectionManager, err := transportorchestrator.NewComputeManager(transportorchestrator.Config{
+		NodeID:                  l.cfg.NodeID,
+		ClientFactory:           natsutil.ClientFactoryFunc(l.transportLayer.CreateClient),
+		NodeManager:             l.nodesManager,
+		HeartbeatTimeout:        l.cfg.BacalhauConfig.Orchestrator.NodeManager.DisconnectTimeout.AsTimeDuration(),
+		DataPlaneMessageHandler: orchestrator.NewMessageHandler(l.jobStore),
+		DataPlaneMessageCreatorFactory: watchers.NewNCLMessageCreatorFactory(watchers.NCLMessageCreatorFactoryParams{
+			ProtocolRouter: l.protocolRouter,
+			SubjectFn:      nclprotocol.NatsSubjectComputeInMsgs,
+		}),
+		EventStore: l.jobStore.GetEventStore(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create connection manager: %w", err)
+	}
+
+	if err = connectionManager.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start connection manager: %w", err)
+	}
+	l.onStop(func(ctx context.Context) {
+		if err := connectionManager.Stop(ctx); err != nil {
+			logDebugIfContextCancelled(ctx, err, "failed to cleanly shutdown connection manager")
+		}
+	})
+
+	// Register S3 managed publisher handlers.
+	// We want to always register these, even if the managed S3 publisher is not enabled,
+	// so the orchestrator can return meaningful errors to compute nodes that try to use the managed publisher.
+
+	// Message handler for generating pre-signed URLs for S3 managed publisher
+	err = connectionManager.RegisterDataPlaneHandler(
+		ctx,
+		messages.ManagedPublisherPreSignURLRequestType,
+		s3managed.NewPreSignedURLRequestHandler(l.s3ManagedPublisherURLGenerator),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register a handler for S3 managed publisher pre-sign url messages: %w", err)
+	}
+
+	watcherRegistry, err := setupLeaderWatchers(ctx, l.jobStore, l.evalBroker, l.webhookNotifier)
+	if err != nil {
+		return err
+	}
+	l.onStop(func(ctx context.Context) {
+		if err := watcherRegistry.Stop(ctx); err != nil {
+			logDebugIfContextCancelled(ctx, err, "failed to stop leader watcher registry")
+		}
+	})
+
+	if l.restoreEvaluations {
+		return l.createEvaluationsOfInProgressJobs(ctx)
+	}
+	return nil
+}
+
+// createEvaluationsOfInProgressJobs creates an evaluation of every in progress job, so that
+// jobs whose evaluations were pending in the broker of a previous leader are scheduled.
+func (l *leaderServices) createEvaluationsOfInProgressJobs(ctx context.Context) error {
+	jobs, err := l.jobStore.GetInProgressJobs(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to get in progress jobs to evaluate: %w", err)
+	}
+	for _, job := range jobs {
+		eval := models.NewEvaluation().
+			WithJob(&job).
+			WithTriggeredBy(models.EvalTriggerLeaderElected)
+		if err = l.jobStore.CreateEvaluation(ctx, *eval); err != nil {
+			return fmt.Errorf("failed to create evaluation of job %s: %w", job.ID, err)
+		}
+	}
+	log.Ctx(ctx).Debug().Int("Jobs", len(jobs)).Msg("Created evaluations of in progress jobs")
+	return nil
+}
+
+// setupLeaderWatchers creates the watchers that feed the evaluation broker and notify webhooks. Their
+// checkpoints are kept in the job store, so that a new leader resumes from where the previous one stopped.
+func setupLeaderWatchers(
+	ctx context.Context,
+	jobStore jobstore.Store,
+	evalBroker orchestrator.EvaluationBroker,
+	webhookNotifier *watchers.WebhookNotifier,
+) (watcher.Manager, error) {
+	watcherRegistry := watcher.NewManager(jobStore.GetEventStore())
+
+	// Start watching for evaluation events using latest iterator
+	_, err := watcherRegistry.Create(ctx, orchestratorEvaluationWatcherID,
+		watcher.WithHandler(evaluation.NewWatchHandler(evalBroker)),
+		watcher.WithAutoStart(),
+		watcher.WithInitialEventIterator(watcher.LatestIterator()),
+		watcher.WithFilter(watcher.EventFilter{
+			ObjectTypes: []string{jobstore.EventObjectEvaluation},
+			Operations:  []watcher.Operation{watcher.OperationCreate},
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start evaluation watcher: %w", err)
+	}
+
+	// Set up webhook notifier watcher
+	_, err = watcherRegistry.Create(ctx, orchestratorWebhookNotifierWatcherID,
+		watcher.WithHandler(webhookNotifier),
+		watcher.WithAutoStart(),
+		watcher.WithInitialEventIterator(watcher.LatestIterator()),
+		watcher.WithRetryStrategy(watcher.RetryStrategySkip),
+		watcher.WithFilter(watcher.EventFilter{
+			ObjectTypes: []string{jobstore.EventObjectJobUpsert, jobstore.EventObjectExecutionUpsert},
+			Operations:  []watcher.Operation{watcher.OperationCreate, watcher.OperationUpdate},
+		}),
+	)
+	if err != nil {
+		_ = watcherRegistry.Stop(ctx)
+		return nil, fmt.Errorf("failed to setup webhook notifier watcher: %w", err)
+	}
+
+	return watcherRegistry, nil
+}
+
+// clusterReplicas returns the number of orchestrators holding a copy of the state shared through NATS
+func clusterReplicas(cfg NodeConfig) int {
+	return min(len(cfg.BacalhauConfig.Orchestrator.Cluster.Peers)+1, maxClusterReplicas)
+}
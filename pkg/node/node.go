@@ -85,6 +85,15 @@ func (c *NodeConfig) Validate() error {
 			"when Users or Oauth2 is defined in API.Auth, Methods and AccessPolicyPath must be empty"))
 	}
 
+	// Leader election requires the orchestrators to share their job store over the network.
+	// SQLite files can't be shared, as its locking isn't reliable on network file systems.
+	orchestratorConfig := c.BacalhauConfig.Orchestrator
+	if orchestratorConfig.Enabled && orchestratorConfig.LeaderElection.Enabled &&
+		orchestratorConfig.JobStore.Type != types.JobStoreTypePostgres {
+		mErr = errors.Join(mErr, fmt.Errorf("leader election requires a job store shared by all orchestrators: "+
+			"%s must be %s", types.OrchestratorJobStoreTypeKey, types.JobStoreTypePostgres))
+	}
+
 	return mErr
 }
 
@@ -241,10 +250,16 @@ func NewNode(
 		debugInfoProviders = append(debugInfoProviders, computeNode.debugInfoProviders...)
 	}
 
+	var queueProvider models.ExecutionQueueProvider
+	if computeNode != nil {
+		queueProvider = computeNode.Queue
+	}
+
 	_, err = agent.NewEndpoint(agent.EndpointParams{
 		Router:             apiServer.Router,
 		NodeInfoProvider:   nodeInfoProvider,
 		DebugInfoProviders: debugInfoProviders,
+		QueueProvider:      queueProvider,
 		BacalhauConfig:     cfg.BacalhauConfig,
 	})
 	if err != nil {
@@ -370,6 +385,7 @@ func createTransport(ctx context.Context, cfg NodeConfig) (*nats_transport.NATST
 		ClusterPort:               cfg.BacalhauConfig.Orchestrator.Cluster.Port,
 		ClusterPeers:              cfg.BacalhauConfig.Orchestrator.Cluster.Peers,
 		ClusterAdvertisedAddress:  cfg.BacalhauConfig.Orchestrator.Cluster.Advertise,
+		ClusterAllowLocalPeers:    cfg.SystemConfig.AllowLocalClusterPeers,
 		IsRequesterNode:           cfg.BacalhauConfig.Orchestrator.Enabled,
 		ServerTLSCACert:           cfg.BacalhauConfig.Orchestrator.TLS.CACert,
 		ServerTLSCert:             cfg.BacalhauConfig.Orchestrator.TLS.ServerCert,
@@ -287,3 +287,29 @@ func TestNodeConfig_Validate_AuthConfig_InvalidCases(t *testing.T) {
 		assert.Contains(t, err.Error(), "mixing old and new auth mechanisms", "Error message should describe the issue")
 	})
 }
+
+func TestNodeConfig_Validate_LeaderElection(t *testing.T) {
+	newConfig := func(jobStoreType string) *NodeConfig {
+		bacConfig := types.Bacalhau{}
+		bacConfig.Orchestrator.Enabled = true
+		bacConfig.Orchestrator.LeaderElection.Enabled = true
+		bacConfig.Orchestrator.JobStore.Type = jobStoreType
+		return &NodeConfig{
+			NodeID:         "test-node-id",
+			CleanupManager: system.NewCleanupManager(),
+			BacalhauConfig: bacConfig,
+		}
+	}
+
+	t.Run("Postgres job store should be valid", func(t *testing.T) {
+		assert.NoError(t, newConfig(types.JobStoreTypePostgres).Validate())
+	})
+
+	for _, jobStoreType := range []string{types.JobStoreTypeBoltDB, types.JobStoreTypeSQLite} {
+		t.Run(jobStoreType+" job store should return error", func(t *testing.T) {
+			err := newConfig(jobStoreType).Validate()
+			assert.Error(t, err, "Validate() should reject leader election with a job store that can't be shared")
+			assert.Contains(t, err.Error(), "leader election requires a job store shared by all orchestrators")
+		})
+	}
+}
@@ -10,16 +10,18 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	boltjobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/boltdb"
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sqldb"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/sqldblib"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/watcher"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
-	"github.com/bacalhau-project/bacalhau/pkg/models/messages"
-	natsutil "github.com/bacalhau-project/bacalhau/pkg/nats"
 	"github.com/bacalhau-project/bacalhau/pkg/nats/proxy"
 	nats_transport "github.com/bacalhau-project/bacalhau/pkg/nats/transport"
 	"github.com/bacalhau-project/bacalhau/pkg/node/metrics"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/election"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/evaluation"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/nodes"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/nodes/kvstore"
@@ -37,9 +39,6 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/publisher/s3managed"
 	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
-	bprotocolorchestrator "github.com/bacalhau-project/bacalhau/pkg/transport/bprotocol/orchestrator"
-	"github.com/bacalhau-project/bacalhau/pkg/transport/nclprotocol"
-	transportorchestrator "github.com/bacalhau-project/bacalhau/pkg/transport/nclprotocol/orchestrator"
 )
 
 var (
@@ -52,6 +51,8 @@ type Requester struct {
 	// Visible for testing
 	Endpoint *orchestrator.BaseEndpoint
 	JobStore jobstore.Store
+	// Leadership reports whether the orchestrator is the leader, and is nil without leader election
+	Leadership orchestrator.Leadership
 	// We need a reference to the node info store until libp2p is removed
 	NodeInfoStore      nodes.Lookup
 	cleanupFunc        func(ctx context.Context)
@@ -82,15 +83,38 @@ func NewRequesterNode(
 		return nil, err
 	}
 
+	// with leader election, only the elected leader runs the leader services
+	leaderElectionConfig := cfg.BacalhauConfig.Orchestrator.LeaderElection
+	var elector *election.Elector
+	var leadership orchestrator.Leadership
+	if leaderElectionConfig.Enabled {
+		elector, err = election.NewElector(ctx, election.ElectorParams{
+			NodeID:        nodeID,
+			Client:        natsConn,
+			LeaseDuration: leaderElectionConfig.LeaseDuration.AsTimeDuration(),
+			Replicas:      clusterReplicas(cfg),
+		})
+		if err != nil {
+			return nil, bacerrors.Wrap(err, "failed to create leader elector")
+		}
+		leadership = elector
+	}
+
 	// evaluation broker
+	evalStore, err := createEvaluationStore(cfg)
+	if err != nil {
+		return nil, err
+	}
 	evalBroker, err := evaluation.NewInMemoryBroker(evaluation.InMemoryBrokerParams{
 		VisibilityTimeout: cfg.BacalhauConfig.Orchestrator.EvaluationBroker.VisibilityTimeout.AsTimeDuration(),
 		MaxReceiveCount:   cfg.BacalhauConfig.Orchestrator.EvaluationBroker.MaxRetryCount,
+		Store:             evalStore,
+		// an elected leader creates evaluations of the in progress jobs of the shared job store instead
+		DiscardStored: leaderElectionConfig.Enabled,
 	})
 	if err != nil {
 		return nil, err
 	}
-	evalBroker.SetEnabled(true)
 
 	// planners that execute the proposed plan by the scheduler
 	// order of the planners is important as they are executed in order
@@ -144,6 +168,20 @@ func NewRequesterNode(
 		ExecutionLimitBackoff: cfg.SystemConfig.ExecutionLimitBackoff,
 	})
 
+	preemptionConfig := scheduler.PreemptionConfig{
+		DefaultPolicy:     scheduler.PreemptionPolicy(cfg.BacalhauConfig.Orchestrator.Scheduler.Preemption.Policy),
+		NamespacePolicies: make(map[string]scheduler.PreemptionPolicy),
+	}
+	for namespace, policy := range cfg.BacalhauConfig.Orchestrator.Scheduler.Preemption.Namespaces {
+		preemptionConfig.NamespacePolicies[namespace] = scheduler.PreemptionPolicy(policy)
+	}
+	if err = preemptionConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid scheduler preemption config: %w", err)
+	}
+
+	// per-namespace quotas are enforced both at job submission and scheduling
+	quotaEnforcer := orchestrator.NewQuotaEnforcer(jobStore)
+
 	// scheduler provider
 	batchServiceJobScheduler := scheduler.NewBatchServiceJobScheduler(scheduler.BatchServiceJobSchedulerParams{
 		JobStore:      jobStore,
@@ -152,6 +190,8 @@ func NewRequesterNode(
 		RetryStrategy: retryStrategy,
 		QueueBackoff:  cfg.BacalhauConfig.Orchestrator.Scheduler.QueueBackoff.AsTimeDuration(),
 		RateLimiter:   executionRateLimiter,
+		Preemption:    preemptionConfig,
+		QuotaEnforcer: quotaEnforcer,
 	})
 	schedulerProvider := orchestrator.NewMappedSchedulerProvider(map[string]orchestrator.Scheduler{
 		models.JobTypeBatch:   batchServiceJobScheduler,
@@ -170,19 +210,6 @@ func NewRequesterNode(
 		}),
 	})
 
-	workerCount := cfg.BacalhauConfig.Orchestrator.Scheduler.WorkerCount
-	workers := make([]*orchestrator.Worker, 0, workerCount)
-	for i := 1; i <= workerCount; i++ {
-		log.Debug().Msgf("Starting worker %d", i)
-		// worker config the polls from the broker
-		worker := orchestrator.NewWorker(orchestrator.WorkerParams{
-			SchedulerProvider: schedulerProvider,
-			EvaluationBroker:  evalBroker,
-		})
-		workers = append(workers, worker)
-		worker.Start(ctx)
-	}
-
 	s3Config, err := s3helper.DefaultAWSConfig()
 	if err != nil {
 		return nil, err
@@ -235,18 +262,28 @@ func NewRequesterNode(
 		return nil, err
 	}
 
+	execProxy, err := proxy.NewExecProxy(proxy.ExecProxyParams{
+		Conn: natsConn,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	endpointV2 := orchestrator.NewBaseEndpoint(&orchestrator.BaseEndpointParams{
 		ID:                nodeID,
 		Store:             jobStore,
 		LogstreamServer:   logStreamProxy,
+		ExecServer:        execProxy,
 		JobTransformer:    jobTransformers,
 		ResultTransformer: resultTransformers,
+		QuotaEnforcer:     quotaEnforcer,
 	})
 
 	housekeeping, err := orchestrator.NewHousekeeping(orchestrator.HousekeepingParams{
 		JobStore:      jobStore,
 		Interval:      cfg.BacalhauConfig.Orchestrator.Scheduler.HousekeepingInterval.AsTimeDuration(),
 		TimeoutBuffer: cfg.BacalhauConfig.Orchestrator.Scheduler.HousekeepingTimeout.AsTimeDuration(),
+		Leadership:    leadership,
 	})
 	if err != nil {
 		return nil, err
@@ -274,58 +311,12 @@ func NewRequesterNode(
 	)
 	auth_endpoint.BindEndpoint(ctx, apiServer.Router, authenticators)
 
-	// legacy connection manager
-	legacyConnectionManager, err := bprotocolorchestrator.NewConnectionManager(bprotocolorchestrator.Config{
-		NodeID:         nodeID,
-		NatsConn:       natsConn,
-		NodeManager:    nodesManager,
-		EventStore:     jobStore.GetEventStore(),
-		ProtocolRouter: protocolRouter,
-		Callback:       orchestrator.NewCallback(&orchestrator.CallbackParams{ID: nodeID, Store: jobStore}),
-	})
-	if err != nil {
-		return nil, pkgerrors.Wrap(err, "failed to create connection manager")
-	}
-	if err = legacyConnectionManager.Start(ctx); err != nil {
-		return nil, pkgerrors.Wrap(err, "failed to start connection manager")
-	}
-
-	// connection manager
-	connectionManager, err := transportorchestrator.NewComputeManager(transportorchestrator.Config{
-		NodeID:                  cfg.NodeID,
-		ClientFactory:           natsutil.ClientFactoryFunc(transportLayer.CreateClient),
-		NodeManager:             nodesManager,
-		HeartbeatTimeout:        cfg.BacalhauConfig.Orchestrator.NodeManager.DisconnectTimeout.AsTimeDuration(),
-		DataPlaneMessageHandler: orchestrator.NewMessageHandler(jobStore),
-		DataPlaneMessageCreatorFactory: watchers.NewNCLMessageCreatorFactory(watchers.NCLMessageCreatorFactoryParams{
-			ProtocolRouter: protocolRouter,
-			SubjectFn:      nclprotocol.NatsSubjectComputeInMsgs,
-		}),
-		EventStore: jobStore.GetEventStore(),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create connection manager: %w", err)
-	}
-
-	if err = connectionManager.Start(ctx); err != nil {
-		return nil, fmt.Errorf("failed to start connection manager: %w", err)
-	}
-
-	// Register S3 managed publisher handlers.
-	// We want to always register these, even if the managed S3 publisher is not enabled,
-	// so the orchestrator can return meaningful errors to compute nodes that try to use the managed publisher.
-
-	// Message handler for generating pre-signed URLs for S3 managed publisher
-	err = connectionManager.RegisterDataPlaneHandler(
-		ctx,
-		messages.ManagedPublisherPreSignURLRequestType,
-		s3managed.NewPreSignedURLRequestHandler(s3ManagedPublisherURLGenerator),
-	)
+	webhookNotifier, err := createWebhookNotifier(cfg, jobStore)
 	if err != nil {
-		return nil, fmt.Errorf("failed to register a handler for S3 managed publisher pre-sign url messages: %w", err)
+		return nil, err
 	}
 
-	watcherRegistry, err := setupOrchestratorWatchers(ctx, jobStore, evalBroker)
+	watcherRegistry, err := setupOrchestratorWatchers(ctx, jobStore)
 	if err != nil {
 		return nil, err
 	}
@@ -341,15 +332,31 @@ func NewRequesterNode(
 		return nil, fmt.Errorf("failed to create re-evaluator: %w", err)
 	}
 
-	// Register ReEvaluator with node manager for connection state events
+	// Register ReEvaluator with node manager for connection and scheduling state events
 	nodesManager.OnConnectionStateChange(reEvaluator.HandleNodeConnectionEvent)
+	nodesManager.OnSchedulingStateChange(reEvaluator.HandleNodeSchedulingEvent)
 
 	// Start the ReEvaluator
 	if err = reEvaluator.Start(ctx); err != nil {
 		return nil, err
 	}
 
-	if err = nodesManager.Start(ctx); err != nil {
+	leader := &leaderServices{
+		cfg:                            cfg,
+		transportLayer:                 transportLayer,
+		jobStore:                       jobStore,
+		nodesManager:                   nodesManager,
+		protocolRouter:                 protocolRouter,
+		evalBroker:                     evalBroker,
+		schedulerProvider:              schedulerProvider,
+		webhookNotifier:                webhookNotifier,
+		s3ManagedPublisherURLGenerator: s3ManagedPublisherURLGenerator,
+		restoreEvaluations:             leaderElectionConfig.Enabled,
+	}
+	if elector != nil {
+		elector.OnLeadershipChange(leader.onLeadershipChange)
+		elector.Start(ctx)
+	} else if err = leader.start(ctx); err != nil {
 		return nil, err
 	}
 
@@ -362,42 +369,43 @@ func NewRequesterNode(
 			logDebugIfContextCancelled(ctx, cleanupErr, "failed to cleanly shutdown re-evaluator")
 		}
 
-		// stop the legacy connection manager
-		legacyConnectionManager.Stop(ctx)
-
-		// stop the connection manager
-		if cleanupErr = connectionManager.Stop(ctx); cleanupErr != nil {
-			logDebugIfContextCancelled(ctx, cleanupErr, "failed to cleanly shutdown connection manager")
+		// stop the leader services, and step down to let another orchestrator take over
+		if elector != nil {
+			elector.Stop(ctx)
 		}
+		leader.stop(ctx)
 
 		if cleanupErr = watcherRegistry.Stop(ctx); cleanupErr != nil {
 			logDebugIfContextCancelled(ctx, cleanupErr, "failed to stop watcher registry")
 		}
 
+		// stop pending webhook deliveries before the jobstore they record their outcome in is closed
+		if cleanupErr = webhookNotifier.Stop(ctx); cleanupErr != nil {
+			logDebugIfContextCancelled(ctx, cleanupErr, "failed to stop webhook notifier")
+		}
+
 		// stop the housekeeping background task
 		housekeeping.Stop(ctx)
-		for _, worker := range workers {
-			worker.Stop()
+
+		// Close the evaluation store after the evaluation broker is disabled
+		if evalStore != nil {
+			if cleanupErr = evalStore.Close(ctx); cleanupErr != nil {
+				logDebugIfContextCancelled(ctx, cleanupErr, "failed to cleanly shutdown evaluation store")
+			}
 		}
-		evalBroker.SetEnabled(false)
 
 		// Close the jobstore after the evaluation broker is disabled
 		cleanupErr = jobStore.Close(ctx)
 		if cleanupErr != nil {
 			logDebugIfContextCancelled(ctx, cleanupErr, "failed to cleanly shutdown jobstore")
 		}
-
-		// stop node manager
-		cleanupErr = nodesManager.Stop(ctx)
-		if cleanupErr != nil {
-			logDebugIfContextCancelled(ctx, cleanupErr, "failed to cleanly shutdown node manager")
-		}
 	}
 
 	return &Requester{
 		Endpoint:           endpointV2,
 		NodeInfoStore:      nodesManager,
 		JobStore:           jobStore,
+		Leadership:         leadership,
 		cleanupFunc:        cleanupFunc,
 		debugInfoProviders: debugInfoProviders,
 	}, nil
@@ -421,6 +429,7 @@ func createNodeRanker(cfg NodeConfig, jobStore jobstore.Store) (orchestrator.Nod
 		ranking.NewMinVersionNodeRanker(ranking.MinVersionNodeRankerParams{MinVersion: minBacalhauVersion}),
 		ranking.NewPreviousExecutionsNodeRanker(ranking.PreviousExecutionsNodeRankerParams{JobStore: jobStore}),
 		ranking.NewAvailableCapacityNodeRanker(),
+		ranking.NewInputCacheNodeRanker(),
 		// arbitrary rankers
 		ranking.NewRandomNodeRanker(ranking.RandomNodeRankerParams{
 			RandomnessRange: cfg.SystemConfig.NodeRankRandomnessRange,
@@ -430,6 +439,16 @@ func createNodeRanker(cfg NodeConfig, jobStore jobstore.Store) (orchestrator.Nod
 }
 
 func createJobStore(ct context.Context, cfg NodeConfig) (jobstore.Store, error) {
+	switch cfg.BacalhauConfig.Orchestrator.JobStore.Type {
+	case types.JobStoreTypeSQLite, types.JobStoreTypePostgres:
+		return OpenSQLJobStore(cfg.BacalhauConfig)
+	case types.JobStoreTypeBoltDB, "":
+	default:
+		return nil, bacerrors.Newf("unsupported job store type %q", cfg.BacalhauConfig.Orchestrator.JobStore.Type).
+			WithHint("Set %s to one of %s, %s or %s", types.OrchestratorJobStoreTypeKey,
+				types.JobStoreTypeBoltDB, types.JobStoreTypeSQLite, types.JobStoreTypePostgres)
+	}
+
 	jobStoreDBPath, err := cfg.BacalhauConfig.JobStoreFilePath()
 	if err != nil {
 		return nil, err
@@ -441,15 +460,80 @@ func createJobStore(ct context.Context, cfg NodeConfig) (jobstore.Store, error)
 	return jobStore, nil
 }
 
+// createEvaluationStore creates the store persisting the evaluations of the broker,
+// or returns nil if the broker keeps them in memory only.
+func createEvaluationStore(cfg NodeConfig) (evaluation.Store, error) {
+	switch cfg.BacalhauConfig.Orchestrator.EvaluationBroker.Type {
+	case types.EvaluationBrokerTypeInMemory, "":
+		return nil, nil
+	case types.EvaluationBrokerTypeBoltDB:
+	default:
+		return nil, bacerrors.Newf("unsupported evaluation broker type %q",
+			cfg.BacalhauConfig.Orchestrator.EvaluationBroker.Type).
+			WithHint("Set %s to one of %s or %s", types.OrchestratorEvaluationBrokerTypeKey,
+				types.EvaluationBrokerTypeInMemory, types.EvaluationBrokerTypeBoltDB)
+	}
+
+	path, err := cfg.BacalhauConfig.EvaluationBrokerFilePath()
+	if err != nil {
+		return nil, err
+	}
+	store, err := evaluation.NewBoltDBStore(path)
+	if err != nil {
+		return nil, bacerrors.Wrap(err, "failed to create evaluation store")
+	}
+	return store, nil
+}
+
+// OpenSQLJobStore opens the SQL job store configured in Orchestrator.JobStore, which is
+// a SQLite file, by default in the orchestrator's data directory, or a PostgreSQL database.
+func OpenSQLJobStore(cfg types.Bacalhau) (*sqljobstore.SQLJobStore, error) {
+	var db *sqldblib.DB
+	var err error
+	switch cfg.Orchestrator.JobStore.Type {
+	case types.JobStoreTypeSQLite:
+		path := cfg.Orchestrator.JobStore.DSN
+		if path == "" {
+			if path, err = cfg.SQLJobStoreFilePath(); err != nil {
+				return nil, err
+			}
+		}
+		db, err = sqldblib.OpenSQLite(path)
+	case types.JobStoreTypePostgres:
+		if cfg.Orchestrator.JobStore.DSN == "" {
+			return nil, bacerrors.Newf("a DSN is required for a %s job store", types.JobStoreTypePostgres).
+				WithHint("Set %s to the connection string of the database", types.OrchestratorJobStoreDSNKey)
+		}
+		db, err = sqldblib.Open(sqldblib.DialectPostgres, cfg.Orchestrator.JobStore.DSN)
+	default:
+		return nil, bacerrors.Newf("job store type %q is not a SQL database", cfg.Orchestrator.JobStore.Type)
+	}
+	if err != nil {
+		return nil, bacerrors.Wrap(err, "failed to open job store database")
+	}
+
+	jobStore, err := sqljobstore.NewSQLJobStore(db)
+	if err != nil {
+		_ = db.Close()
+		return nil, bacerrors.Wrap(err, "failed to create job store")
+	}
+	return jobStore, nil
+}
+
 func createNodeManager(ctx context.Context,
 	cfg NodeConfig,
 	eventStore watcher.EventStore,
 	nodeInfoProvider models.DecoratorNodeInfoProvider,
 	natsConn *nats.Conn) (nodes.Manager, nodes.Store, error) {
-	nodeInfoStore, err := kvstore.NewNodeStore(ctx, kvstore.NodeStoreParams{
+	nodeStoreParams := kvstore.NodeStoreParams{
 		BucketName: kvstore.BucketNameCurrent,
 		Client:     natsConn,
-	})
+	}
+	if cfg.BacalhauConfig.Orchestrator.LeaderElection.Enabled {
+		// replicate the node states so that they survive the failure of an orchestrator
+		nodeStoreParams.Replicas = clusterReplicas(cfg)
+	}
+	nodeInfoStore, err := kvstore.NewNodeStore(ctx, nodeStoreParams)
 	if err != nil {
 		return nil, nil, pkgerrors.Wrap(err, "failed to create node info store using NATS transport connection info")
 	}
@@ -469,29 +553,13 @@ func createNodeManager(ctx context.Context,
 	return nodeManager, nodeInfoStore, nil
 }
 
-func setupOrchestratorWatchers(
-	ctx context.Context,
-	jobStore jobstore.Store,
-	evalBroker orchestrator.EvaluationBroker,
-) (watcher.Manager, error) {
+// setupOrchestratorWatchers creates the watchers that run on every orchestrator,
+// including the ones that are not the leader.
+func setupOrchestratorWatchers(ctx context.Context, jobStore jobstore.Store) (watcher.Manager, error) {
 	watcherRegistry := watcher.NewManager(jobStore.GetEventStore())
 
-	// Start watching for evaluation events using latest iterator
-	_, err := watcherRegistry.Create(ctx, orchestratorEvaluationWatcherID,
-		watcher.WithHandler(evaluation.NewWatchHandler(evalBroker)),
-		watcher.WithAutoStart(),
-		watcher.WithInitialEventIterator(watcher.LatestIterator()),
-		watcher.WithFilter(watcher.EventFilter{
-			ObjectTypes: []string{jobstore.EventObjectEvaluation},
-			Operations:  []watcher.Operation{watcher.OperationCreate},
-		}),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to start evaluation watcher: %w", err)
-	}
-
 	// Set up execution logger watcher
-	_, err = watcherRegistry.Create(ctx, orchestratorExecutionLoggerWatcherID,
+	_, err := watcherRegistry.Create(ctx, orchestratorExecutionLoggerWatcherID,
 		watcher.WithHandler(watchers.NewExecutionLogger(log.Logger)),
 		watcher.WithEphemeral(),
 		watcher.WithAutoStart(),
@@ -508,6 +576,33 @@ func setupOrchestratorWatchers(
 	return watcherRegistry, nil
 }
 
+// createWebhookNotifier validates the namespace webhooks of the configuration and creates the
+// notifier of job lifecycle transitions
+func createWebhookNotifier(cfg NodeConfig, jobStore jobstore.Store) (*watchers.WebhookNotifier, error) {
+	webhooksConfig := cfg.BacalhauConfig.Orchestrator.Webhooks
+	namespaceWebhooks := make(map[string][]models.Webhook, len(webhooksConfig.Namespaces))
+	for namespace, webhooks := range webhooksConfig.Namespaces {
+		for _, webhook := range webhooks {
+			webhook = *webhook.Copy()
+			webhook.Normalize()
+			if err := webhook.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid webhook configured for namespace %s: %w", namespace, err)
+			}
+			namespaceWebhooks[namespace] = append(namespaceWebhooks[namespace], webhook)
+		}
+	}
+
+	return watchers.NewWebhookNotifier(watchers.WebhookNotifierParams{
+		JobStore:          jobStore,
+		NamespaceWebhooks: namespaceWebhooks,
+		SigningKey:        webhooksConfig.SigningKey,
+		MaxAttempts:       webhooksConfig.MaxAttempts,
+		Timeout:           webhooksConfig.Timeout.AsTimeDuration(),
+
+		AllowPrivateAddresses: webhooksConfig.AllowPrivateAddresses,
+	}), nil
+}
+
 func (r *Requester) cleanup(ctx context.Context) {
 	r.cleanupFunc(ctx)
 }
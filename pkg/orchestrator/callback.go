@@ -138,6 +138,7 @@ func (e *Callback) OnRunComplete(ctx context.Context, result legacy.RunResult) {
 		NewValues: models.Execution{
 			PublishedResult: result.PublishResult,
 			RunOutput:       result.RunCommandResult,
+			ResourceUsage:   result.RunCommandResult.GetResourceUsage(),
 			ComputeState:    models.NewExecutionState(models.ExecutionStateCompleted),
 			DesiredState:    models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped).WithMessage("execution completed"),
 		},
@@ -0,0 +1,88 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// ResolveJobDependencies replaces the IDs or names in the job's DependsOn list
+// with the IDs of the upstream jobs, and makes sure the dependencies don't form a cycle.
+// The job's ID must be set if the job already exists in the store.
+func ResolveJobDependencies(ctx context.Context, store jobstore.Store, job *models.Job) error {
+	for i, dep := range job.DependsOn {
+		upstream, err := store.GetJobByIDOrName(ctx, dep, job.Namespace)
+		if err != nil {
+			if bacerrors.IsErrorWithCode(err, bacerrors.NotFoundError) {
+				return bacerrors.Newf("job dependency %q not found in namespace %q", dep, job.Namespace).
+					WithCode(bacerrors.NotFoundError)
+			}
+			return err
+		}
+		if upstream.ID == job.ID {
+			return bacerrors.New("job cannot depend on itself").WithCode(bacerrors.ValidationError)
+		}
+		job.DependsOn[i] = upstream.ID
+	}
+
+	// a new job cannot be part of a cycle as no other job can depend on it yet
+	if job.ID == "" {
+		return nil
+	}
+	return detectDependencyCycle(ctx, store, job)
+}
+
+// detectDependencyCycle walks the upstream dependencies of the job and returns an error
+// if any of them depends, directly or transitively, on the job itself.
+func detectDependencyCycle(ctx context.Context, store jobstore.Store, job *models.Job) error {
+	visited := make(map[string]bool)
+	queue := slices.Clone(job.DependsOn)
+	for len(queue) > 0 {
+		jobID := queue[0]
+		queue = queue[1:]
+		if visited[jobID] {
+			continue
+		}
+		visited[jobID] = true
+
+		upstream, err := store.GetJob(ctx, jobID)
+		if err != nil {
+			if bacerrors.IsErrorWithCode(err, bacerrors.NotFoundError) {
+				continue
+			}
+			return err
+		}
+		if slices.Contains(upstream.DependsOn, job.ID) {
+			return bacerrors.Newf("job dependency on %q creates a cycle", upstream.Name).
+				WithCode(bacerrors.ValidationError)
+		}
+		queue = append(queue, upstream.DependsOn...)
+	}
+	return nil
+}
+
+// NewDependentJobEvaluations returns evaluations for the in-progress jobs that depend on
+// the given job, so they get re-evaluated when the job reaches a terminal state.
+func NewDependentJobEvaluations(ctx context.Context, store jobstore.Store, job models.Job) ([]*models.Evaluation, error) {
+	dependents, err := store.GetDependentJobs(ctx, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve jobs depending on %s: %w", job.ID, err)
+	}
+
+	var evals []*models.Evaluation
+	for i := range dependents {
+		dependent := &dependents[i]
+		if dependent.Namespace != job.Namespace {
+			continue
+		}
+		evals = append(evals, models.NewEvaluation().
+			WithJob(dependent).
+			WithTriggeredBy(models.EvalTriggerJobDependency).
+			WithComment(fmt.Sprintf("upstream job %s reached a terminal state", job.ID)))
+	}
+	return evals, nil
+}
@@ -0,0 +1,88 @@
+//go:build unit || !integration
+
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type DependenciesTestSuite struct {
+	suite.Suite
+	ctx          context.Context
+	mockJobStore *jobstore.MockStore
+}
+
+func (s *DependenciesTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockJobStore = jobstore.NewMockStore(gomock.NewController(s.T()))
+}
+
+func TestDependenciesTestSuite(t *testing.T) {
+	suite.Run(t, new(DependenciesTestSuite))
+}
+
+func (s *DependenciesTestSuite) TestResolveJobDependencies_ReplacesNamesWithIDs() {
+	upstream := mock.Job()
+	job := mock.Job()
+	job.ID = ""
+	job.DependsOn = []string{upstream.Name}
+
+	s.mockJobStore.EXPECT().GetJobByIDOrName(s.ctx, upstream.Name, job.Namespace).Return(*upstream, nil)
+
+	s.Require().NoError(ResolveJobDependencies(s.ctx, s.mockJobStore, job))
+	s.Equal([]string{upstream.ID}, job.DependsOn)
+}
+
+func (s *DependenciesTestSuite) TestResolveJobDependencies_NotFound() {
+	job := mock.Job()
+	job.ID = ""
+	job.DependsOn = []string{"missing"}
+
+	s.mockJobStore.EXPECT().GetJobByIDOrName(s.ctx, "missing", job.Namespace).
+		Return(models.Job{}, bacerrors.New("not found").WithCode(bacerrors.NotFoundError))
+
+	err := ResolveJobDependencies(s.ctx, s.mockJobStore, job)
+	s.Require().Error(err)
+	s.True(bacerrors.IsErrorWithCode(err, bacerrors.NotFoundError))
+}
+
+func (s *DependenciesTestSuite) TestResolveJobDependencies_DetectsCycle() {
+	job := mock.Job()
+	upstream := mock.Job()
+	upstream.DependsOn = []string{job.ID}
+	job.DependsOn = []string{upstream.ID}
+
+	s.mockJobStore.EXPECT().GetJobByIDOrName(s.ctx, upstream.ID, job.Namespace).Return(*upstream, nil)
+	s.mockJobStore.EXPECT().GetJob(s.ctx, upstream.ID).Return(*upstream, nil)
+
+	err := ResolveJobDependencies(s.ctx, s.mockJobStore, job)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "cycle")
+}
+
+func (s *DependenciesTestSuite) TestNewDependentJobEvaluations() {
+	upstream := mock.Job()
+	dependent := mock.Job()
+	dependent.DependsOn = []string{upstream.ID}
+	otherNamespace := mock.Job()
+	otherNamespace.Namespace = "other"
+	otherNamespace.DependsOn = []string{upstream.ID}
+
+	s.mockJobStore.EXPECT().GetDependentJobs(s.ctx, upstream.ID).
+		Return([]models.Job{*dependent, *otherNamespace}, nil)
+
+	evals, err := NewDependentJobEvaluations(s.ctx, s.mockJobStore, *upstream)
+	s.Require().NoError(err)
+	s.Require().Len(evals, 1)
+	s.Equal(dependent.ID, evals[0].JobID)
+	s.Equal(models.EvalTriggerJobDependency, evals[0].TriggeredBy)
+}
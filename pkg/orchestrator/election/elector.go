@@ -0,0 +1,282 @@
+package election
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+	natsutil "github.com/bacalhau-project/bacalhau/pkg/nats"
+)
+
+const (
+	// DefaultBucketName is the name of the key-value bucket holding the leader lease
+	DefaultBucketName = "orchestrator_leader"
+
+	// DefaultLeaseDuration is how long leadership is held without being renewed
+	DefaultLeaseDuration = 15 * time.Second
+
+	// leaderKey is the key of the leader lease in the bucket
+	leaderKey = "leader"
+
+	// renewalsPerLease is how many times the leader renews its lease within the lease duration,
+	// so that a few failed renewals don't lose the leadership
+	renewalsPerLease = 3
+)
+
+// LeadershipChangeHandler is called when the elector gains or loses the leadership.
+// If a handler fails when the leadership is gained, the elector steps down.
+type LeadershipChangeHandler func(ctx context.Context, leader bool) error
+
+// ElectorParams holds the configuration of an Elector
+type ElectorParams struct {
+	// NodeID is the ID of the orchestrator campaigning for the leadership
+	NodeID string
+	// Client is the connection to the NATS cluster shared by the orchestrators
+	Client *nats.Conn
+	// BucketName is the name of the key-value bucket holding the leader lease (optional)
+	BucketName string
+	// LeaseDuration is how long leadership is held without being renewed (optional)
+	LeaseDuration time.Duration
+	// Replicas is the number of NATS servers holding a copy of the leader lease (optional)
+	Replicas int
+}
+
+// Elector elects a single leader among the orchestrators sharing a NATS cluster.
+// The leader holds a lease stored in a JetStream key-value bucket whose entries expire
+// after the lease duration. The leader renews the lease periodically, and the other
+// orchestrators try to acquire it, which only succeeds once it has expired or was
+// released by a leader that stopped.
+type Elector struct {
+	nodeID        string
+	kv            jetstream.KeyValue
+	leaseDuration time.Duration
+
+	mu       sync.RWMutex
+	leader   bool
+	revision uint64
+	// deadline is when the leader's lease expires unless renewed
+	deadline time.Time
+
+	handlers []LeadershipChangeHandler
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+}
+
+// NewElector creates a new Elector, and the key-value bucket of the lease if it doesn't exist
+func NewElector(ctx context.Context, params ElectorParams) (*Elector, error) {
+	if params.BucketName == "" {
+		params.BucketName = DefaultBucketName
+	}
+	if params.LeaseDuration == 0 {
+		params.LeaseDuration = DefaultLeaseDuration
+	}
+	if params.Replicas == 0 {
+		params.Replicas = 1
+	}
+
+	if err := errors.Join(
+		validate.NotBlank(params.NodeID, "node ID required"),
+		validate.NotNil(params.Client, "NATS client required"),
+		validate.IsGreaterThanZero(params.LeaseDuration, "lease duration must be greater than zero"),
+		validate.IsGreaterThanZero(params.Replicas, "replicas must be greater than zero"),
+	); err != nil {
+		return nil, fmt.Errorf("invalid elector params: %w", err)
+	}
+
+	js, err := jetstream.New(params.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to jetstream: %w", err)
+	}
+
+	kv, err := natsutil.CreateOrUpdateKeyValue(ctx, js, jetstream.KeyValueConfig{
+		Bucket:   strings.ToLower(params.BucketName),
+		TTL:      params.LeaseDuration,
+		Replicas: params.Replicas,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create leader lease bucket: %w", err)
+	}
+
+	return &Elector{
+		nodeID:        params.NodeID,
+		kv:            kv,
+		leaseDuration: params.LeaseDuration,
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}, nil
+}
+
+// OnLeadershipChange registers a handler called when the elector gains or loses the leadership.
+// Handlers are called in the order they were registered, and must be registered before Start.
+func (e *Elector) OnLeadershipChange(handler LeadershipChangeHandler) {
+	e.handlers = append(e.handlers, handler)
+}
+
+// Start starts campaigning for the leadership in the background
+func (e *Elector) Start(ctx context.Context) {
+	e.startOnce.Do(func() {
+		go e.run(ctx)
+	})
+}
+
+// Stop stops campaigning. If the elector is the leader, it steps down and releases
+// the lease so that another orchestrator can take over without waiting for it to expire.
+func (e *Elector) Stop(ctx context.Context) {
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+		e.startOnce.Do(func() { close(e.doneCh) })
+	})
+
+	select {
+	case <-e.doneCh:
+	case <-ctx.Done():
+		return
+	}
+
+	e.stepDown(ctx)
+}
+
+// stepDown notifies the handlers that the leadership is lost, if the elector is the leader,
+// and releases the lease so that another orchestrator can take over.
+func (e *Elector) stepDown(ctx context.Context) {
+	e.mu.Lock()
+	wasLeader, revision := e.leader, e.revision
+	e.leader = false
+	e.mu.Unlock()
+	if !wasLeader {
+		return
+	}
+
+	if err := e.notify(ctx, false); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to stop leading")
+	}
+	if err := e.kv.Delete(ctx, leaderKey, jetstream.LastRevision(revision)); err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to release leader lease")
+	}
+}
+
+// IsLeader returns true if the elector holds an unexpired leader lease
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader && time.Now().Before(e.deadline)
+}
+
+// Leader returns the ID of the current leader, or an empty string if there is none
+func (e *Elector) Leader(ctx context.Context) (string, error) {
+	entry, err := e.kv.Get(ctx, leaderKey)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get leader lease: %w", err)
+	}
+	return string(entry.Value()), nil
+}
+
+// run campaigns for the leadership until the elector is stopped
+func (e *Elector) run(ctx context.Context) {
+	defer close(e.doneCh)
+
+	interval := e.leaseDuration / renewalsPerLease
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		e.campaign(ctx, interval)
+		select {
+		case <-ticker.C:
+		case <-e.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// campaign renews the lease if the elector is the leader, or tries to acquire it otherwise,
+// and notifies the handlers if the leadership changed.
+func (e *Elector) campaign(ctx context.Context, timeout time.Duration) {
+	// the lease is considered to expire a lease duration after the request was sent,
+	// which is never later than when the key-value bucket expires it
+	start := time.Now()
+	requestCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	e.mu.RLock()
+	wasLeader, revision := e.leader, e.revision
+	e.mu.RUnlock()
+
+	var err error
+	if wasLeader {
+		revision, err = e.kv.Update(requestCtx, leaderKey, []byte(e.nodeID), revision)
+	} else {
+		revision, err = e.acquire(requestCtx)
+	}
+
+	e.mu.Lock()
+	switch {
+	case err == nil:
+		e.leader = true
+		e.revision = revision
+		e.deadline = start.Add(e.leaseDuration)
+	case wasLeader && (errors.Is(err, jetstream.ErrKeyExists) || !time.Now().Before(e.deadline)):
+		// the lease expired, or was acquired by another orchestrator
+		e.leader = false
+	}
+	isLeader := e.leader
+	e.mu.Unlock()
+
+	if err != nil && !errors.Is(err, jetstream.ErrKeyExists) {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to renew or acquire leader lease")
+	}
+	if isLeader == wasLeader {
+		return
+	}
+	if !isLeader {
+		log.Ctx(ctx).Warn().Str("NodeID", e.nodeID).Msg("Lost orchestrator leadership")
+		if err = e.notify(ctx, false); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to stop leading")
+		}
+		return
+	}
+	log.Ctx(ctx).Info().Str("NodeID", e.nodeID).Msg("Elected as orchestrator leader")
+	if err = e.notify(ctx, true); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to start leading, stepping down")
+		e.stepDown(ctx)
+	}
+}
+
+// acquire creates the lease if there is none. A lease that is still held by this node,
+// such as after a restart, is taken over without waiting for it to expire.
+func (e *Elector) acquire(ctx context.Context) (uint64, error) {
+	revision, err := e.kv.Create(ctx, leaderKey, []byte(e.nodeID))
+	if !errors.Is(err, jetstream.ErrKeyExists) {
+		return revision, err
+	}
+	entry, getErr := e.kv.Get(ctx, leaderKey)
+	if getErr != nil || string(entry.Value()) != e.nodeID {
+		return 0, err
+	}
+	return e.kv.Update(ctx, leaderKey, []byte(e.nodeID), entry.Revision())
+}
+
+// notify calls the leadership change handlers, and returns the errors of the failed ones
+func (e *Elector) notify(ctx context.Context, leader bool) error {
+	var errs error
+	for _, handler := range e.handlers {
+		errs = errors.Join(errs, handler(ctx, leader))
+	}
+	return errs
+}
@@ -0,0 +1,191 @@
+//go:build unit || !integration
+
+package election
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/suite"
+)
+
+const testLeaseDuration = time.Second
+
+type ElectorSuite struct {
+	suite.Suite
+	nats   *server.Server
+	client *nats.Conn
+}
+
+func TestElectorSuite(t *testing.T) {
+	suite.Run(t, new(ElectorSuite))
+}
+
+func (s *ElectorSuite) SetupTest() {
+	opts := natsserver.DefaultTestOptions
+	opts.Port = server.RANDOM_PORT
+	opts.JetStream = true
+	opts.StoreDir = s.T().TempDir()
+	s.nats = natsserver.RunServer(&opts)
+
+	var err error
+	s.client, err = nats.Connect(s.nats.ClientURL())
+	s.Require().NoError(err)
+}
+
+func (s *ElectorSuite) TearDownTest() {
+	s.client.Close()
+	s.nats.Shutdown()
+}
+
+// leadershipRecorder records the leadership changes notified to an elector's handler
+type leadershipRecorder struct {
+	mu      sync.Mutex
+	changes []bool
+}
+
+func (r *leadershipRecorder) handle(_ context.Context, leader bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, leader)
+	return nil
+}
+
+func (r *leadershipRecorder) get() []bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]bool{}, r.changes...)
+}
+
+func (s *ElectorSuite) newElector(nodeID string) (*Elector, *leadershipRecorder) {
+	elector, err := NewElector(context.Background(), ElectorParams{
+		NodeID:        nodeID,
+		Client:        s.client,
+		LeaseDuration: testLeaseDuration,
+	})
+	s.Require().NoError(err)
+	recorder := &leadershipRecorder{}
+	elector.OnLeadershipChange(recorder.handle)
+	return elector, recorder
+}
+
+func (s *ElectorSuite) TestSingleElectorBecomesLeader() {
+	ctx := context.Background()
+	elector, recorder := s.newElector("node-1")
+	elector.Start(ctx)
+	defer elector.Stop(ctx)
+
+	s.Eventually(elector.IsLeader, 5*time.Second, 10*time.Millisecond)
+	s.Equal([]bool{true}, recorder.get())
+
+	leader, err := elector.Leader(ctx)
+	s.Require().NoError(err)
+	s.Equal("node-1", leader)
+
+	// the leader keeps renewing its lease
+	time.Sleep(2 * testLeaseDuration)
+	s.True(elector.IsLeader())
+	s.Equal([]bool{true}, recorder.get())
+}
+
+func (s *ElectorSuite) TestSingleLeader() {
+	ctx := context.Background()
+	first, _ := s.newElector("node-1")
+	first.Start(ctx)
+	defer first.Stop(ctx)
+	s.Require().Eventually(first.IsLeader, 5*time.Second, 10*time.Millisecond)
+
+	second, recorder := s.newElector("node-2")
+	second.Start(ctx)
+	defer second.Stop(ctx)
+
+	time.Sleep(2 * testLeaseDuration)
+	s.True(first.IsLeader())
+	s.False(second.IsLeader())
+	s.Empty(recorder.get())
+
+	leader, err := second.Leader(ctx)
+	s.Require().NoError(err)
+	s.Equal("node-1", leader)
+}
+
+func (s *ElectorSuite) TestStoppedLeaderReleasesLease() {
+	ctx := context.Background()
+	first, firstRecorder := s.newElector("node-1")
+	first.Start(ctx)
+	s.Require().Eventually(first.IsLeader, 5*time.Second, 10*time.Millisecond)
+
+	second, secondRecorder := s.newElector("node-2")
+	second.Start(ctx)
+	defer second.Stop(ctx)
+
+	first.Stop(ctx)
+	s.False(first.IsLeader())
+	s.Equal([]bool{true, false}, firstRecorder.get())
+
+	// the lease was released, so the follower takes over on its next attempt
+	s.Eventually(second.IsLeader, testLeaseDuration, 10*time.Millisecond)
+	s.Equal([]bool{true}, secondRecorder.get())
+}
+
+func (s *ElectorSuite) TestFailedLeaderLeaseExpires() {
+	ctx := context.Background()
+	firstCtx, cancel := context.WithCancel(ctx)
+	first, _ := s.newElector("node-1")
+	first.Start(firstCtx)
+	s.Require().Eventually(first.IsLeader, 5*time.Second, 10*time.Millisecond)
+
+	second, _ := s.newElector("node-2")
+	second.Start(ctx)
+	defer second.Stop(ctx)
+
+	// the leader stops renewing its lease without releasing it
+	cancel()
+
+	s.Eventually(func() bool {
+		return !first.IsLeader() && second.IsLeader()
+	}, 5*testLeaseDuration, 10*time.Millisecond)
+
+	leader, err := second.Leader(ctx)
+	s.Require().NoError(err)
+	s.Equal("node-2", leader)
+}
+
+func (s *ElectorSuite) TestRestartedLeaderKeepsLease() {
+	ctx := context.Background()
+	firstCtx, cancel := context.WithCancel(ctx)
+	first, _ := s.newElector("node-1")
+	first.Start(firstCtx)
+	s.Require().Eventually(first.IsLeader, 5*time.Second, 10*time.Millisecond)
+	cancel()
+
+	// a new elector of the same node takes over its unexpired lease
+	restarted, _ := s.newElector("node-1")
+	restarted.Start(ctx)
+	defer restarted.Stop(ctx)
+	s.Eventually(restarted.IsLeader, testLeaseDuration/2, 10*time.Millisecond)
+}
+
+func (s *ElectorSuite) TestStepsDownWhenFailingToLead() {
+	ctx := context.Background()
+	elector, recorder := s.newElector("node-1")
+	elector.OnLeadershipChange(func(_ context.Context, leader bool) error {
+		if leader {
+			return errors.New("failed to start leading")
+		}
+		return nil
+	})
+	elector.Start(ctx)
+	defer elector.Stop(ctx)
+
+	s.Eventually(func() bool {
+		changes := recorder.get()
+		return len(changes) >= 2 && changes[0] && !changes[1]
+	}, 5*time.Second, 10*time.Millisecond)
+}
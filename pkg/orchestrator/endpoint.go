@@ -2,13 +2,16 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 
 	"github.com/bacalhau-project/bacalhau/pkg/analytics"
 	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/execstream"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/logstream"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
@@ -21,19 +24,25 @@ const initialJobVersion = 1
 const jobVersionIncrement = 1
 
 type BaseEndpointParams struct {
-	ID                string
-	Store             jobstore.Store
-	LogstreamServer   logstream.Server
+	ID              string
+	Store           jobstore.Store
+	LogstreamServer logstream.Server
+	// ExecServer runs commands inside running executions. Optional, exec is not supported if not set.
+	ExecServer        execstream.Server
 	JobTransformer    transformer.JobTransformer
 	ResultTransformer transformer.ResultTransformer
+	// QuotaEnforcer rejects jobs exceeding their namespace's quota. Optional, no quotas are enforced if not set.
+	QuotaEnforcer *QuotaEnforcer
 }
 
 type BaseEndpoint struct {
 	id                string
 	store             jobstore.Store
 	logstreamServer   logstream.Server
+	execServer        execstream.Server
 	jobTransformer    transformer.JobTransformer
 	resultTransformer transformer.ResultTransformer
+	quotaEnforcer     *QuotaEnforcer
 }
 
 func NewBaseEndpoint(params *BaseEndpointParams) *BaseEndpoint {
@@ -41,8 +50,10 @@ func NewBaseEndpoint(params *BaseEndpointParams) *BaseEndpoint {
 		id:                params.ID,
 		store:             params.Store,
 		logstreamServer:   params.LogstreamServer,
+		execServer:        params.ExecServer,
 		jobTransformer:    params.JobTransformer,
 		resultTransformer: params.ResultTransformer,
+		quotaEnforcer:     params.QuotaEnforcer,
 	}
 }
 
@@ -83,6 +94,10 @@ func (e *BaseEndpoint) SubmitJob(ctx context.Context, request *SubmitJobRequest)
 		isUpdate = true
 		evalTriggeredBy = models.EvalTriggerJobUpdate
 
+		if err = ResolveJobDependencies(ctx, e.store, job); err != nil {
+			return nil, err
+		}
+
 		// Do a diff between jobs, and see if there is a difference
 		if !request.Force {
 			jobDiff := existingJob.CompareWith(job)
@@ -98,11 +113,18 @@ func (e *BaseEndpoint) SubmitJob(ctx context.Context, request *SubmitJobRequest)
 		if !bacerrors.IsErrorWithCode(existingJobErr, bacerrors.NotFoundError) {
 			return nil, existingJobErr
 		}
+		if err = ResolveJobDependencies(ctx, e.store, job); err != nil {
+			return nil, err
+		}
 	}
 
 	// set jobId for telemetry purposes
 	jobID = job.ID
 
+	if isUpdate {
+		warnings = append(warnings, e.overAllocationWarnings(ctx, *job)...)
+	}
+
 	txContext, err := e.store.BeginTx(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
@@ -110,6 +132,13 @@ func (e *BaseEndpoint) SubmitJob(ctx context.Context, request *SubmitJobRequest)
 
 	defer txContext.Rollback() //nolint:errcheck
 
+	// check the quota within the transaction, so that concurrent submissions can't exceed it together
+	if e.quotaEnforcer != nil {
+		if err = e.quotaEnforcer.CheckSubmission(txContext, job); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create or update the job based on whether it's a new job or an update
 	if isUpdate {
 		if err = e.store.UpdateJob(txContext, *job); err != nil {
@@ -138,6 +167,18 @@ func (e *BaseEndpoint) SubmitJob(ctx context.Context, request *SubmitJobRequest)
 		}
 	}
 
+	// scheduled jobs don't run right away, but wait for the next tick of their schedule
+	if job.IsScheduled() {
+		jobVersion := uint64(initialJobVersion)
+		if isUpdate {
+			jobVersion = existingJob.Version + jobVersionIncrement
+		}
+		nextRun := job.Schedule.Next(time.Now())
+		if err = e.store.AddJobHistory(txContext, job.ID, jobVersion, JobScheduledEvent(nextRun)); err != nil {
+			return nil, err
+		}
+	}
+
 	eval := &models.Evaluation{
 		ID:          uuid.NewString(),
 		JobID:       job.ID,
@@ -163,6 +204,25 @@ func (e *BaseEndpoint) SubmitJob(ctx context.Context, request *SubmitJobRequest)
 	}, nil
 }
 
+// overAllocationWarnings warns about the resources a job requests far above the peak usage reported
+// by the executions of its previous versions. Failing to compute them doesn't fail the submission.
+func (e *BaseEndpoint) overAllocationWarnings(ctx context.Context, job models.Job) []string {
+	executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+		JobID:          job.ID,
+		AllJobVersions: true,
+	})
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Str("job", job.ID).Msg("failed to get executions to recommend resources")
+		return nil
+	}
+	recommendation, err := RecommendResources(job, executions)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Str("job", job.ID).Msg("failed to recommend resources")
+		return nil
+	}
+	return recommendation.Warnings
+}
+
 func (e *BaseEndpoint) DiffJob(ctx context.Context, request *DiffJobRequest) (_ *DiffJobResponse, err error) {
 	job := request.Job
 	job.Normalize()
@@ -180,6 +240,9 @@ func (e *BaseEndpoint) DiffJob(ctx context.Context, request *DiffJobRequest) (_
 	}
 
 	job.ID = existingJob.ID
+	if err = ResolveJobDependencies(ctx, e.store, job); err != nil {
+		return nil, err
+	}
 	jobDiff := existingJob.CompareWith(job)
 
 	return &DiffJobResponse{
@@ -228,6 +291,17 @@ func (e *BaseEndpoint) StopJob(ctx context.Context, request *StopJobRequest) (St
 		return StopJobResponse{}, err
 	}
 
+	// let jobs waiting on this job know that it won't complete
+	dependentEvals, err := NewDependentJobEvaluations(txContext, e.store, job)
+	if err != nil {
+		return StopJobResponse{}, err
+	}
+	for _, eval := range dependentEvals {
+		if err = e.store.CreateEvaluation(txContext, *eval); err != nil {
+			return StopJobResponse{}, err
+		}
+	}
+
 	// enqueue evaluation to allow the scheduler to stop existing executions
 	// if the job is not terminal already, such as failed
 	evalID := ""
@@ -285,6 +359,11 @@ func (e *BaseEndpoint) RerunJob(ctx context.Context, request *RerunJobRequest) (
 			WithHint("try to use the job run command instead of the job rerun command")
 	}
 
+	// rerunning a scheduled job starts a new run right away, outside of its schedule
+	if job.IsScheduled() {
+		job.Meta[models.MetaScheduledRunTime] = time.Now().UTC().Format(time.RFC3339)
+	}
+
 	if err = e.store.UpdateJob(txContext, job); err != nil {
 		return nil, err
 	}
@@ -408,6 +487,119 @@ func (e *BaseEndpoint) ReadLogs(ctx context.Context, request ReadLogsRequest) (
 	return e.logstreamServer.GetLogStream(ctx, req)
 }
 
+// Exec runs a command inside a running execution of a job, or attaches to the main process of the execution
+// if no command is given, and returns a stream of the command's output. If no execution is requested, the job
+// must have a single running execution. Each command is audited in the history of the execution.
+func (e *BaseEndpoint) Exec(ctx context.Context, request ExecRequest) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	if e.execServer == nil {
+		return nil, bacerrors.New("exec is not supported by this orchestrator").WithCode(bacerrors.NotImplemented)
+	}
+	job, err := e.store.GetJobByIDOrName(ctx, request.JobID, request.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	// jobs are also looked up by ID regardless of their namespace, which is what exec is authorized for
+	namespace := request.Namespace
+	if namespace == "" {
+		namespace = models.DefaultNamespace
+	}
+	if job.Namespace != namespace {
+		return nil, jobstore.NewErrJobNotFound(request.JobID)
+	}
+	executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+		JobID:          job.ID,
+		AllJobVersions: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	execution, err := runningExecution(job, executions, request.ExecutionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = e.store.AddExecutionHistory(ctx, job.ID, execution.JobVersion, execution.ID,
+		ExecStartedEvent(request.Command, request.Stdin != nil)); err != nil {
+		return nil, err
+	}
+	// the audit entry of the end of the command is recorded even if the client is gone
+	auditCtx := context.WithoutCancel(ctx)
+	audit := func(exitCode int, err error) {
+		if auditErr := e.store.AddExecutionHistory(auditCtx, job.ID, execution.JobVersion, execution.ID,
+			ExecFinishedEvent(request.Command, exitCode, err)); auditErr != nil {
+			log.Ctx(ctx).Warn().Err(auditErr).Str("execution", execution.ID).Msg("failed to audit exec")
+		}
+	}
+
+	res, err := e.execServer.Exec(ctx, messages.ExecRequest{
+		ExecutionID: execution.ID,
+		NodeID:      execution.NodeID,
+		Command:     request.Command,
+	}, request.Stdin)
+	if err != nil {
+		audit(0, err)
+		return nil, err
+	}
+
+	out := make(chan *concurrency.AsyncResult[models.ExecOutput])
+	go func() {
+		defer close(out)
+		var exitCode int
+		var execErr error
+		exited := false
+		for result := range res {
+			if result.Err != nil {
+				execErr = result.Err
+			} else if result.Value.Type == models.ExecOutputExit {
+				exitCode, exited = result.Value.ExitCode, true
+			}
+			select {
+			case <-ctx.Done():
+			case out <- result:
+			}
+		}
+		if !exited && execErr == nil {
+			execErr = errors.New("exec stream closed before the command exited")
+		}
+		audit(exitCode, execErr)
+	}()
+	return out, nil
+}
+
+// runningExecution returns the running execution of the job with the given ID, or the only running
+// execution of the job if no ID is given.
+func runningExecution(job models.Job, executions []models.Execution, executionID string) (*models.Execution, error) {
+	var running []*models.Execution
+	for i := range executions {
+		execution := &executions[i]
+		if executionID != "" && execution.ID != executionID {
+			continue
+		}
+		if execution.ComputeState.StateType == models.ExecutionStateRunning {
+			running = append(running, execution)
+		} else if executionID != "" {
+			return nil, bacerrors.Newf("execution %s of job %s is not running", executionID, job.ID).
+				WithCode(bacerrors.BadRequestError)
+		}
+	}
+
+	switch {
+	case len(running) == 1:
+		return running[0], nil
+	case executionID != "":
+		return nil, bacerrors.Newf("unable to find execution %s in job %s", executionID, job.ID).
+			WithCode(bacerrors.NotFoundError)
+	case len(running) == 0:
+		return nil, bacerrors.Newf("job %s has no running executions", job.ID).
+			WithCode(bacerrors.BadRequestError)
+	default:
+		return nil, bacerrors.Newf("job %s has %d running executions", job.ID, len(running)).
+			WithCode(bacerrors.BadRequestError).
+			WithHint("Select one of the executions of the job to run the command in")
+	}
+}
+
 // GetResults returns the results of a job
 func (e *BaseEndpoint) GetResults(ctx context.Context, request *GetResultsRequest) (GetResultsResponse, error) {
 	job, err := e.store.GetJobByIDOrName(ctx, request.JobID, request.Namespace)
@@ -429,17 +621,22 @@ func (e *BaseEndpoint) GetResults(ctx context.Context, request *GetResultsReques
 
 	results := make([]*models.SpecConfig, 0)
 	for _, execution := range executions {
+		var result *models.SpecConfig
 		if execution.ComputeState.StateType == models.ExecutionStateCompleted {
-			result := execution.PublishedResult.Copy()
-			err = e.resultTransformer.Transform(ctx, result)
-			if err != nil {
-				return GetResultsResponse{}, err
-			}
+			result = execution.PublishedResult.Copy()
+		} else if request.Partial && execution.PartialResult != nil {
+			result = execution.PartialResult.Result.Copy()
+		} else {
+			continue
+		}
+		err = e.resultTransformer.Transform(ctx, result)
+		if err != nil {
+			return GetResultsResponse{}, err
+		}
 
-			// Only add valid results
-			if result.Type != "" {
-				results = append(results, result)
-			}
+		// Only add valid results
+		if result.Type != "" {
+			results = append(results, result)
 		}
 	}
 
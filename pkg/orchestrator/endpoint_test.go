@@ -4,7 +4,9 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"testing"
 	"time"
 
@@ -13,7 +15,9 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models/messages"
 	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
 	"github.com/google/uuid"
 )
@@ -562,6 +566,7 @@ func (s *EndpointTestSuite) TestSubmitJob_Success_UpdateExistingJob() {
 
 	// Setup expectations - job exists, so it's an update
 	s.mockJobStore.EXPECT().GetJobByName(ctx, job.Name, job.Namespace).Return(existingJob, nil)
+	s.mockJobStore.EXPECT().GetExecutions(ctx, gomock.Any()).Return(nil, nil)
 	s.mockJobStore.EXPECT().BeginTx(ctx).Return(s.mockTxCtx, nil)
 	s.mockJobStore.EXPECT().UpdateJob(s.mockTxCtx, gomock.Any()).Return(nil)
 	s.mockJobStore.EXPECT().UpdateJobState(s.mockTxCtx, gomock.Any()).Return(nil)
@@ -585,6 +590,37 @@ func (s *EndpointTestSuite) TestSubmitJob_Success_UpdateExistingJob() {
 	s.NotEmpty(response.EvaluationID)
 }
 
+func (s *EndpointTestSuite) TestSubmitJob_WarnsAboutOverAllocatedResources() {
+	ctx := context.Background()
+	job := s.createTestJobForSubmission("over-allocated-job", "default")
+	job.Task().ResourcesConfig = &models.ResourcesConfig{CPU: "4", Memory: "4gb"}
+	existingJob := s.createTestJob(job.ID, models.JobStateTypeCompleted)
+	existingJob.Name = job.Name
+	existingJob.Namespace = job.Namespace
+	existingJob.Version = 1
+	job.Meta["new-key"] = "new-value"
+
+	s.mockJobStore.EXPECT().GetJobByName(ctx, job.Name, job.Namespace).Return(existingJob, nil)
+	s.mockJobStore.EXPECT().GetExecutions(ctx, jobstore.GetExecutionsOptions{JobID: job.ID, AllJobVersions: true}).Return(
+		[]models.Execution{{
+			JobVersion:    1,
+			ResourceUsage: &models.ResourceUsage{CPUSeconds: 5, WallSeconds: 10, PeakMemory: 100 * 1024 * 1024},
+		}}, nil)
+	s.mockJobStore.EXPECT().BeginTx(ctx).Return(s.mockTxCtx, nil)
+	s.mockJobStore.EXPECT().UpdateJob(s.mockTxCtx, gomock.Any()).Return(nil)
+	s.mockJobStore.EXPECT().UpdateJobState(s.mockTxCtx, gomock.Any()).Return(nil)
+	s.mockJobStore.EXPECT().AddJobHistory(s.mockTxCtx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	s.mockJobStore.EXPECT().CreateEvaluation(s.mockTxCtx, gomock.Any()).Return(nil)
+	s.mockTxCtx.EXPECT().Commit().Return(nil)
+	s.mockTxCtx.EXPECT().Rollback().Return(nil)
+
+	response, err := s.endpoint.SubmitJob(ctx, &SubmitJobRequest{Job: job})
+	s.Require().NoError(err)
+	s.Contains(response.Warnings, "requested 4.00 CPU, but past executions of the job used at most 0.50. Consider requesting 0.6 CPU")
+	s.Contains(response.Warnings,
+		"requested 4.0 GB of memory, but past executions of the job used at most 105 MB. Consider requesting 126 MB")
+}
+
 func (s *EndpointTestSuite) TestSubmitJob_Success_ForceUpdateWithNoChanges() {
 	ctx := context.Background()
 	job := s.createTestJobForSubmission("force-job", "default")
@@ -599,6 +635,7 @@ func (s *EndpointTestSuite) TestSubmitJob_Success_ForceUpdateWithNoChanges() {
 	}
 
 	s.mockJobStore.EXPECT().GetJobByName(ctx, job.Name, job.Namespace).Return(existingJob, nil)
+	s.mockJobStore.EXPECT().GetExecutions(ctx, gomock.Any()).Return(nil, nil)
 	s.mockJobStore.EXPECT().BeginTx(ctx).Return(s.mockTxCtx, nil)
 	s.mockJobStore.EXPECT().UpdateJob(s.mockTxCtx, gomock.Any()).Return(nil)
 	s.mockJobStore.EXPECT().UpdateJobState(s.mockTxCtx, gomock.Any()).Return(nil)
@@ -728,6 +765,7 @@ func (s *EndpointTestSuite) TestSubmitJob_Error_UpdateJobFails() {
 	}
 
 	s.mockJobStore.EXPECT().GetJobByName(ctx, job.Name, job.Namespace).Return(existingJob, nil)
+	s.mockJobStore.EXPECT().GetExecutions(ctx, gomock.Any()).Return(nil, nil)
 	s.mockJobStore.EXPECT().BeginTx(ctx).Return(s.mockTxCtx, nil)
 	s.mockJobStore.EXPECT().UpdateJob(s.mockTxCtx, gomock.Any()).Return(fmt.Errorf("update job failed"))
 	s.mockTxCtx.EXPECT().Rollback().Return(nil)
@@ -752,6 +790,7 @@ func (s *EndpointTestSuite) TestSubmitJob_Error_UpdateJobStateFails() {
 	}
 
 	s.mockJobStore.EXPECT().GetJobByName(ctx, job.Name, job.Namespace).Return(existingJob, nil)
+	s.mockJobStore.EXPECT().GetExecutions(ctx, gomock.Any()).Return(nil, nil)
 	s.mockJobStore.EXPECT().BeginTx(ctx).Return(s.mockTxCtx, nil)
 	s.mockJobStore.EXPECT().UpdateJob(s.mockTxCtx, gomock.Any()).Return(nil)
 	s.mockJobStore.EXPECT().UpdateJobState(s.mockTxCtx, gomock.Any()).Return(fmt.Errorf("update job state failed"))
@@ -800,6 +839,7 @@ func (s *EndpointTestSuite) TestSubmitJob_Error_AddJobHistoryFails_UpdateJob() {
 	}
 
 	s.mockJobStore.EXPECT().GetJobByName(ctx, job.Name, job.Namespace).Return(existingJob, nil)
+	s.mockJobStore.EXPECT().GetExecutions(ctx, gomock.Any()).Return(nil, nil)
 	s.mockJobStore.EXPECT().BeginTx(ctx).Return(s.mockTxCtx, nil)
 	s.mockJobStore.EXPECT().UpdateJob(s.mockTxCtx, gomock.Any()).Return(nil)
 	s.mockJobStore.EXPECT().UpdateJobState(s.mockTxCtx, gomock.Any()).Return(nil)
@@ -874,6 +914,7 @@ func (s *EndpointTestSuite) TestSubmitJob_ValidatesUpdateJobStateRequestForUpdat
 	}
 
 	s.mockJobStore.EXPECT().GetJobByName(ctx, job.Name, job.Namespace).Return(existingJob, nil)
+	s.mockJobStore.EXPECT().GetExecutions(ctx, gomock.Any()).Return(nil, nil)
 	s.mockJobStore.EXPECT().BeginTx(ctx).Return(s.mockTxCtx, nil)
 	s.mockJobStore.EXPECT().UpdateJob(s.mockTxCtx, gomock.Any()).Return(nil)
 	s.mockJobStore.EXPECT().UpdateJobState(s.mockTxCtx, gomock.Any()).DoAndReturn(
@@ -936,6 +977,53 @@ func (s *EndpointTestSuite) TestSubmitJob_ValidatesEvaluationForNewJob() {
 	s.NotNil(response)
 }
 
+func (s *EndpointTestSuite) TestSubmitJob_ChecksQuotaWithinTransaction() {
+	ctx := context.Background()
+	s.endpoint.quotaEnforcer = NewQuotaEnforcer(s.mockJobStore)
+	job := s.createTestJobForSubmission("test-job", "default")
+	job.Count = 2
+
+	// the quota is read within the submission's transaction, and the job is not created when it exceeds it
+	s.mockJobStore.EXPECT().GetJobByName(ctx, job.Name, job.Namespace).
+		Return(models.Job{}, bacerrors.New("not found").WithCode(bacerrors.NotFoundError))
+	s.mockJobStore.EXPECT().BeginTx(ctx).Return(s.mockTxCtx, nil)
+	s.mockJobStore.EXPECT().GetNamespaceQuota(s.mockTxCtx, job.Namespace).
+		Return(models.NamespaceQuota{Namespace: job.Namespace, MaxConcurrentExecutions: 1}, nil)
+	s.mockTxCtx.EXPECT().Rollback().Return(nil)
+
+	response, err := s.endpoint.SubmitJob(ctx, &SubmitJobRequest{Job: job})
+
+	s.Require().Error(err)
+	s.True(bacerrors.IsErrorWithCode(err, bacerrors.ResourceExhausted))
+	s.Nil(response)
+}
+
+// unexpectedExecServer fails the test if a command is run through it
+type unexpectedExecServer struct {
+	s *EndpointTestSuite
+}
+
+func (u unexpectedExecServer) Exec(context.Context, messages.ExecRequest, io.Reader) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	u.s.Fail("unexpected exec")
+	return nil, errors.New("unexpected exec")
+}
+
+func (s *EndpointTestSuite) TestExec_JobInAnotherNamespace() {
+	ctx := context.Background()
+	s.endpoint.execServer = unexpectedExecServer{s: s}
+	job := s.createTestJobForSubmission("other-job", "other-namespace")
+
+	for _, namespace := range []string{"tenant-namespace", ""} {
+		// the job is found by its ID even though it is not in the requested namespace
+		s.mockJobStore.EXPECT().GetJobByIDOrName(ctx, job.ID, namespace).Return(*job, nil)
+
+		_, err := s.endpoint.Exec(ctx, ExecRequest{JobID: job.ID, Namespace: namespace, Command: []string{"ls"}})
+		s.Require().Error(err)
+		s.True(bacerrors.IsErrorWithCode(err, bacerrors.NotFoundError), err.Error())
+	}
+}
+
 // createTestJobForSubmission creates a test job specifically for submission tests
 func (s *EndpointTestSuite) createTestJobForSubmission(name, namespace string) *models.Job {
 	job := mock.Job()
@@ -0,0 +1,104 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/boltdblib"
+)
+
+const evaluationsBucket = "evaluations"
+
+// compile-time check to ensure type implements the Store interface
+var _ Store = &BoltDBStore{}
+
+// BoltDBStore is a Store backed by a boltdb database on disk.
+// Evaluations are stored in a bucket called `evaluations`, where each key is
+// an evaluation ID and the value is the JSON representation of the StoredEvaluation.
+type BoltDBStore struct {
+	database *bolt.DB
+}
+
+// NewBoltDBStore creates a new store backed by a boltdb database at the given path
+func NewBoltDBStore(dbPath string) (*BoltDBStore, error) {
+	database, err := boltdblib.Open(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	err = database.Update(func(tx *bolt.Tx) error {
+		_, err = tx.CreateBucketIfNotExists([]byte(evaluationsBucket))
+		return err
+	})
+	if err != nil {
+		_ = database.Close()
+		return nil, fmt.Errorf("error creating bucket %s: %w", evaluationsBucket, err)
+	}
+	return &BoltDBStore{database: database}, nil
+}
+
+// Put creates or replaces the stored evaluation
+func (s *BoltDBStore) Put(ctx context.Context, stored StoredEvaluation) error {
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to marshal evaluation %s: %w", stored.Evaluation.ID, err)
+	}
+	return boltdblib.Update(ctx, s.database, func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(evaluationsBucket)).Put([]byte(stored.Evaluation.ID), data)
+	})
+}
+
+// Delete deletes the evaluation with the given ID, if it is stored
+func (s *BoltDBStore) Delete(ctx context.Context, evalID string) error {
+	return boltdblib.Update(ctx, s.database, func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(evaluationsBucket)).Delete([]byte(evalID))
+	})
+}
+
+// Write puts and deletes a batch of evaluations in a single transaction
+func (s *BoltDBStore) Write(ctx context.Context, puts []StoredEvaluation, deletes []string) error {
+	data := make([][]byte, len(puts))
+	for i, stored := range puts {
+		var err error
+		if data[i], err = json.Marshal(stored); err != nil {
+			return fmt.Errorf("failed to marshal evaluation %s: %w", stored.Evaluation.ID, err)
+		}
+	}
+	return boltdblib.Update(ctx, s.database, func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(evaluationsBucket))
+		for i, stored := range puts {
+			if err := bucket.Put([]byte(stored.Evaluation.ID), data[i]); err != nil {
+				return err
+			}
+		}
+		for _, evalID := range deletes {
+			if err := bucket.Delete([]byte(evalID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// List returns all the stored evaluations
+func (s *BoltDBStore) List(ctx context.Context) ([]StoredEvaluation, error) {
+	var evaluations []StoredEvaluation
+	err := boltdblib.View(ctx, s.database, func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(evaluationsBucket)).ForEach(func(k, v []byte) error {
+			var stored StoredEvaluation
+			if err := json.Unmarshal(v, &stored); err != nil {
+				return fmt.Errorf("failed to unmarshal evaluation %s: %w", k, err)
+			}
+			evaluations = append(evaluations, stored)
+			return nil
+		})
+	})
+	return evaluations, err
+}
+
+// Close closes the database
+func (s *BoltDBStore) Close(ctx context.Context) error {
+	return s.database.Close()
+}
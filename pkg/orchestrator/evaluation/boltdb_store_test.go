@@ -0,0 +1,260 @@
+//go:build unit || !integration
+
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type BoltDBStoreTestSuite struct {
+	suite.Suite
+	store   *BoltDBStore
+	brokers []*InMemoryBroker
+}
+
+func TestBoltDBStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(BoltDBStoreTestSuite))
+}
+
+func (s *BoltDBStoreTestSuite) SetupTest() {
+	store, err := NewBoltDBStore(filepath.Join(s.T().TempDir(), "evaluations.db"))
+	s.Require().NoError(err)
+	s.store = store
+	s.brokers = nil
+}
+
+func (s *BoltDBStoreTestSuite) TearDownTest() {
+	for _, broker := range s.brokers {
+		broker.SetEnabled(false)
+	}
+	s.Require().NoError(s.store.Close(context.Background()))
+}
+
+// startBroker starts a broker persisting its evaluations in the store,
+// as an orchestrator does when it starts
+func (s *BoltDBStoreTestSuite) startBroker() *InMemoryBroker {
+	params := defaultBrokerParams
+	params.Store = s.store
+	return s.startBrokerWithParams(params)
+}
+
+func (s *BoltDBStoreTestSuite) startBrokerWithParams(params InMemoryBrokerParams) *InMemoryBroker {
+	broker, err := NewInMemoryBroker(params)
+	s.Require().NoError(err)
+	broker.SetEnabled(true)
+	s.brokers = append(s.brokers, broker)
+	return broker
+}
+
+func (s *BoltDBStoreTestSuite) TestPutListDelete() {
+	ctx := context.Background()
+	eval1, eval2 := mock.Eval(), mock.Eval()
+	s.Require().NoError(s.store.Put(ctx, StoredEvaluation{Evaluation: eval1}))
+	s.Require().NoError(s.store.Put(ctx, StoredEvaluation{Evaluation: eval2}))
+	s.Require().NoError(s.store.Put(ctx, StoredEvaluation{Evaluation: eval2, ReceiveCount: 2}))
+
+	stored, err := s.store.List(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(stored, 2)
+	receiveCounts := map[string]int{}
+	for _, e := range stored {
+		receiveCounts[e.Evaluation.ID] = e.ReceiveCount
+	}
+	s.Equal(map[string]int{eval1.ID: 0, eval2.ID: 2}, receiveCounts)
+
+	s.Require().NoError(s.store.Delete(ctx, eval1.ID))
+	s.Require().NoError(s.store.Delete(ctx, "unknown"))
+	stored, err = s.store.List(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(stored, 1)
+	s.Equal(eval2.ID, stored[0].Evaluation.ID)
+}
+
+func (s *BoltDBStoreTestSuite) TestWrite() {
+	ctx := context.Background()
+	eval1, eval2 := mock.Eval(), mock.Eval()
+	s.Require().NoError(s.store.Put(ctx, StoredEvaluation{Evaluation: eval1}))
+	s.Require().NoError(s.store.Write(ctx, []StoredEvaluation{{Evaluation: eval2, ReceiveCount: 1}}, []string{eval1.ID}))
+
+	stored, err := s.store.List(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(stored, 1)
+	s.Equal(eval2.ID, stored[0].Evaluation.ID)
+	s.Equal(1, stored[0].ReceiveCount)
+}
+
+func (s *BoltDBStoreTestSuite) TestRestoresReadyAndDelayedEvaluations() {
+	broker := s.startBroker()
+	ready := mock.Eval()
+	delayed := mock.Eval()
+	delayed.WaitUntil = time.Now().Add(200 * time.Millisecond)
+	s.Require().NoError(broker.Enqueue(ready))
+	s.Require().NoError(broker.Enqueue(delayed))
+	broker.SetEnabled(false)
+
+	restarted := s.startBroker()
+	stats := restarted.Stats()
+	s.Equal(1, stats.TotalReady)
+	s.Equal(1, stats.TotalWaiting)
+
+	out, _, err := restarted.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Equal(ready.ID, out.ID)
+
+	// the delayed evaluation is only delivered after its wait time
+	out, _, err = restarted.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Equal(delayed.ID, out.ID)
+	s.False(time.Now().Before(delayed.WaitUntil))
+}
+
+func (s *BoltDBStoreTestSuite) TestPersistsEnqueuedEvaluationBeforeReturning() {
+	broker := s.startBroker()
+	eval := mock.Eval()
+	s.Require().NoError(broker.Enqueue(eval))
+
+	stored, err := s.store.List(context.Background())
+	s.Require().NoError(err)
+	s.Require().Len(stored, 1)
+	s.Equal(eval.ID, stored[0].Evaluation.ID)
+}
+
+func (s *BoltDBStoreTestSuite) TestEnqueueFailsIfNotPersisted() {
+	params := defaultBrokerParams
+	params.Store = failingPutStore{s.store}
+	broker := s.startBrokerWithParams(params)
+
+	eval := mock.Eval()
+	s.Require().Error(broker.Enqueue(eval))
+	s.Zero(broker.Stats().TotalReady)
+	// the evaluation is not tracked, so that enqueuing it again is not deduplicated
+	s.NotContains(broker.evals, eval.ID)
+}
+
+func (s *BoltDBStoreTestSuite) TestDiscardsStoredEvaluations() {
+	broker := s.startBroker()
+	s.Require().NoError(broker.Enqueue(mock.Eval()))
+	broker.SetEnabled(false)
+
+	params := defaultBrokerParams
+	params.Store = s.store
+	params.DiscardStored = true
+	restarted := s.startBrokerWithParams(params)
+	s.Zero(restarted.Stats().TotalReady)
+	s.assertStoreEmpty()
+}
+
+func (s *BoltDBStoreTestSuite) TestRedeliversInflightEvaluation() {
+	broker := s.startBroker()
+	eval := mock.Eval()
+	s.Require().NoError(broker.Enqueue(eval))
+	_, receiptHandle, err := broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	broker.SetEnabled(false)
+
+	// the evaluation that was never acknowledged is delivered again with a new receipt handle
+	restarted := s.startBroker()
+	out, newReceiptHandle, err := restarted.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Equal(eval.ID, out.ID)
+	s.NotEqual(receiptHandle, newReceiptHandle)
+	s.Equal(2, restarted.evals[eval.ID])
+
+	s.Require().NoError(restarted.Ack(eval.ID, newReceiptHandle))
+	s.assertStoreEmpty()
+}
+
+// failingPutStore is a store that fails to put evaluations
+type failingPutStore struct {
+	*BoltDBStore
+}
+
+func (failingPutStore) Put(context.Context, StoredEvaluation) error {
+	return errors.New("store unavailable")
+}
+
+// assertStoreEmpty asserts that the evaluations are eventually deleted from the store,
+// as the broker writes to it in the background
+func (s *BoltDBStoreTestSuite) assertStoreEmpty() {
+	s.Eventually(func() bool {
+		stored, err := s.store.List(context.Background())
+		return err == nil && len(stored) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *BoltDBStoreTestSuite) TestRestoresOneInflightPerJob() {
+	broker := s.startBroker()
+	eval1 := mock.Eval()
+	eval2 := mock.Eval()
+	eval2.JobID = eval1.JobID
+	s.Require().NoError(broker.Enqueue(eval1))
+	s.Require().NoError(broker.Enqueue(eval2))
+	broker.SetEnabled(false)
+
+	restarted := s.startBroker()
+	stats := restarted.Stats()
+	s.Equal(1, stats.TotalReady)
+	s.Equal(1, stats.TotalPending)
+
+	first, receiptHandle, err := restarted.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	out, _, err := restarted.Dequeue(defaultSched, 50*time.Millisecond)
+	s.Require().NoError(err)
+	s.Nil(out, "only one evaluation of a job is inflight at a time")
+
+	s.Require().NoError(restarted.Ack(first.ID, receiptHandle))
+	out, _, err = restarted.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Require().NotNil(out)
+	s.NotEqual(first.ID, out.ID)
+}
+
+func (s *BoltDBStoreTestSuite) TestDoesNotPersistDeadLetterEvaluations() {
+	broker := s.startBroker()
+	eval := mock.Eval()
+	s.Require().NoError(broker.Enqueue(eval))
+	for i := 0; i < defaultBrokerParams.MaxReceiveCount; i++ {
+		_, receiptHandle, err := broker.Dequeue(defaultSched, time.Second)
+		s.Require().NoError(err)
+		s.Require().NoError(broker.Nack(eval.ID, receiptHandle))
+	}
+	s.Equal(1, broker.Stats().ByScheduler[deadLetterQueue].Ready)
+	s.assertStoreEmpty()
+	broker.SetEnabled(false)
+
+	restarted := s.startBroker()
+	s.Zero(restarted.Stats().TotalReady)
+}
+
+func (s *BoltDBStoreTestSuite) TestRestoresDeliveryLimit() {
+	broker := s.startBroker()
+	eval := mock.Eval()
+	s.Require().NoError(broker.Enqueue(eval))
+	for i := 0; i < defaultBrokerParams.MaxReceiveCount-1; i++ {
+		_, receiptHandle, err := broker.Dequeue(defaultSched, time.Second)
+		s.Require().NoError(err)
+		s.Require().NoError(broker.Nack(eval.ID, receiptHandle))
+	}
+	// the evaluation is inflight for the last time when the broker stops
+	_, _, err := broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	broker.SetEnabled(false)
+
+	// it is restored in the dead letter queue, and no longer persisted
+	restarted := s.startBroker()
+	stats := restarted.Stats()
+	s.Equal(1, stats.ByScheduler[deadLetterQueue].Ready)
+	s.assertStoreEmpty()
+
+	out, _, err := restarted.Dequeue([]string{deadLetterQueue}, time.Second)
+	s.Require().NoError(err)
+	s.Equal(eval.ID, out.ID)
+}
@@ -65,13 +65,42 @@ type InMemoryBrokerParams struct {
 	MaxReceiveCount      int
 	initialRetryDelay    time.Duration
 	subsequentRetryDelay time.Duration
+
+	// Store optionally persists the evaluations tracked by the broker,
+	// which are restored when the broker is enabled.
+	Store Store
+	// DiscardStored discards the evaluations of the store instead of restoring them when the broker is enabled,
+	// for when the evaluations are recreated from the job store, such as when an orchestrator is elected leader.
+	// The stored evaluations could otherwise be stale, as other orchestrators may have processed them since.
+	DiscardStored bool
 }
 
-// InMemoryBroker The broker is designed to be entirely in-memory.
+// InMemoryBroker The broker is designed to be entirely in-memory. If a Store is provided,
+// evaluations are also persisted until they are acknowledged or reach the delivery limit,
+// and restored in memory when the broker is enabled, so that they survive restarts.
 type InMemoryBroker struct {
 	visibilityTimeout time.Duration
 	maxReceiveCount   int
 	enabled           bool
+	store             Store
+	discardStored     bool
+
+	// writes tracks the changes to the persisted evaluations that are not written to the
+	// store yet by evaluation ID, with nil values for the evaluations to delete. They are
+	// written in batches in the background, so that the store isn't written to while holding
+	// the broker's lock. Enqueued evaluations are instead written before they are enqueued,
+	// so that they are not lost if the orchestrator stops, while losing the other changes
+	// only delivers evaluations again.
+	writes   map[string]*StoredEvaluation
+	writesL  sync.Mutex
+	writesCh chan struct{}
+
+	// storeL serializes the writes to the store, so that batches are written in order
+	storeL sync.Mutex
+
+	// storeWriterCancelFunc is used to stop the long running go routine
+	// that writes the changes to the persisted evaluations to the store
+	storeWriterCancelFunc context.CancelFunc
 
 	// evals tracks queued evaluations by ID to de-duplicate enqueue.
 	// The counter is the number of times we've attempted delivery,
@@ -139,6 +168,7 @@ type InMemoryBroker struct {
 //     evaluation available again
 //   - SubsequentNackDelay is the compounding delay before making evaluations
 //     available again, after the first Nack.
+//   - Store which optionally persists evaluations so they survive restarts.
 func NewInMemoryBroker(params InMemoryBrokerParams) (*InMemoryBroker, error) {
 	if params.VisibilityTimeout < 0 {
 		return nil, fmt.Errorf("timeout cannot be negative")
@@ -153,6 +183,10 @@ func NewInMemoryBroker(params InMemoryBrokerParams) (*InMemoryBroker, error) {
 		visibilityTimeout:    params.VisibilityTimeout,
 		maxReceiveCount:      params.MaxReceiveCount,
 		enabled:              false,
+		store:                params.Store,
+		discardStored:        params.DiscardStored,
+		writes:               make(map[string]*StoredEvaluation),
+		writesCh:             make(chan struct{}, 1),
 		stats:                new(BrokerStats),
 		evals:                make(map[string]int),
 		jobEvals:             make(map[models.NamespacedID]string),
@@ -193,16 +227,30 @@ func (b *InMemoryBroker) SetEnabled(enabled bool) {
 		b.delayedEvalCancelFunc = cancel
 		go b.runDelayedEvalsWatcher(ctx, b.delayedEvalsUpdateCh)
 
+		if b.store != nil {
+			writerCtx, writerCancel := context.WithCancel(context.Background())
+			b.storeWriterCancelFunc = writerCancel
+			go b.runStoreWriter(writerCtx)
+		}
+
 		metricRegistration, err := b.registerMetrics()
 		if err != nil {
 			log.Error().Err(err).Msg("failed to register metrics. Evaluation metrics will not be available")
 		} else {
 			b.metricRegistration = metricRegistration
 		}
+
+		if err = b.restore(); err != nil {
+			log.Error().Err(err).Msg("failed to restore persisted evaluations")
+		}
 	}
 
 	if !enabled {
 		b.flush()
+		// write the remaining changes before the store is closed
+		if b.store != nil {
+			b.writeStore()
+		}
 	}
 }
 
@@ -258,6 +306,9 @@ func (b *InMemoryBroker) processEnqueue(eval *models.Evaluation, receiptHandle s
 		}
 		return nil
 	} else {
+		if err := b.persistEnqueued(eval); err != nil {
+			return fmt.Errorf("failed to persist evaluation %s: %w", eval.ID, err)
+		}
 		b.evals[eval.ID] = 0
 	}
 
@@ -459,6 +510,7 @@ func (b *InMemoryBroker) dequeueForSched(jobType string) (*models.Evaluation, st
 
 	// Increment the dequeue count
 	b.evals[eval.ID] += 1
+	b.persist(eval, b.evals[eval.ID])
 
 	// Update the stats
 	b.stats.TotalReady -= 1
@@ -572,6 +624,7 @@ func (b *InMemoryBroker) Ack(evalID, receiptHandle string) error {
 	// Cleanup
 	delete(b.inflight, evalID)
 	delete(b.evals, evalID)
+	b.unpersist(evalID)
 
 	namespacedID := models.NamespacedID{
 		ID:        inflight.Eval.JobID,
@@ -583,6 +636,9 @@ func (b *InMemoryBroker) Ack(evalID, receiptHandle string) error {
 	if pending := b.pending[namespacedID]; len(pending) != 0 {
 		// Only enqueue the latest pending evaluation and cancel the rest
 		cancelable := pending.MarkForCancel()
+		for _, eval := range cancelable {
+			b.unpersist(eval.ID)
+		}
 		b.cancelable = append(b.cancelable, cancelable...)
 		b.stats.TotalCancelable = len(b.cancelable)
 		b.stats.TotalPending -= len(cancelable)
@@ -645,20 +701,165 @@ func (b *InMemoryBroker) Nack(evalID, receiptHandle string) error {
 	bySched.Inflight -= 1
 
 	// Check if we've hit the delivery limit, and re-enqueue
-	// in the failedQueue
+	// in the failedQueue. Failed evaluations are not persisted,
+	// so that they don't accumulate in the store across restarts.
 	dequeues := b.evals[evalID]
 	e := inflight.Eval
 	var queue string
 	if dequeues >= b.maxReceiveCount {
 		log.Debug().Msgf("Nack: %s has been dequeued %d times, moving to failedQueue", evalID, dequeues)
 		queue = deadLetterQueue
+		b.unpersist(evalID)
 	} else {
 		queue = e.Type
 		e.WaitUntil = time.Now().Add(b.nackReenqueueDelay(e, dequeues)).UTC()
+		b.persist(e, dequeues)
 	}
 	return b.enqueueLocked(e, queue)
 }
 
+// persist schedules storing the evaluation and its dequeue count, if the broker has a store.
+// It must be called with the lock held.
+func (b *InMemoryBroker) persist(eval *models.Evaluation, receiveCount int) {
+	if b.store == nil {
+		return
+	}
+	// copy the evaluation, as it is written after the lock is released
+	b.scheduleWrite(eval.ID, &StoredEvaluation{Evaluation: eval.Copy(), ReceiveCount: receiveCount})
+}
+
+// persistEnqueued stores the newly enqueued evaluation, if the broker has a store. Unlike the other changes,
+// it is written before returning, so that the evaluation is not lost if the orchestrator stops before it is
+// written in the background. It must be called with the lock held.
+func (b *InMemoryBroker) persistEnqueued(eval *models.Evaluation) error {
+	if b.store == nil {
+		return nil
+	}
+	b.storeL.Lock()
+	defer b.storeL.Unlock()
+	// a change to the evaluation that wasn't written yet, such as its deletion when the
+	// evaluation is enqueued again after being acknowledged, would overwrite it
+	b.writesL.Lock()
+	delete(b.writes, eval.ID)
+	b.writesL.Unlock()
+	return b.store.Put(context.Background(), StoredEvaluation{Evaluation: eval.Copy()})
+}
+
+// unpersist schedules deleting the evaluation from the store, if the broker has one.
+// It must be called with the lock held.
+func (b *InMemoryBroker) unpersist(evalID string) {
+	if b.store == nil {
+		return
+	}
+	b.scheduleWrite(evalID, nil)
+}
+
+// scheduleWrite records a change to the persisted evaluation, replacing any earlier change to it
+// that wasn't written yet, and notifies the store writer
+func (b *InMemoryBroker) scheduleWrite(evalID string, stored *StoredEvaluation) {
+	b.writesL.Lock()
+	b.writes[evalID] = stored
+	b.writesL.Unlock()
+	select {
+	case b.writesCh <- struct{}{}:
+	default:
+	}
+}
+
+// runStoreWriter is a long-lived function that writes the changes to the
+// persisted evaluations to the store as they are made
+func (b *InMemoryBroker) runStoreWriter(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.writesCh:
+			b.writeStore()
+		}
+	}
+}
+
+// writeStore writes the changes to the persisted evaluations made since the previous write to
+// the store in a single transaction. Changes that fail to be written are kept to be retried with
+// the next batch, unless the evaluation changed again in the meantime.
+func (b *InMemoryBroker) writeStore() {
+	b.storeL.Lock()
+	defer b.storeL.Unlock()
+
+	b.writesL.Lock()
+	writes := b.writes
+	b.writes = make(map[string]*StoredEvaluation)
+	b.writesL.Unlock()
+	if len(writes) == 0 {
+		return
+	}
+
+	var puts []StoredEvaluation
+	var deletes []string
+	for evalID, stored := range writes {
+		if stored == nil {
+			deletes = append(deletes, evalID)
+		} else {
+			puts = append(puts, *stored)
+		}
+	}
+	if err := b.store.Write(context.Background(), puts, deletes); err != nil {
+		log.Error().Err(err).Int("Evaluations", len(writes)).Msg("failed to write evaluations to the store")
+		b.writesL.Lock()
+		for evalID, stored := range writes {
+			if _, ok := b.writes[evalID]; !ok {
+				b.writes[evalID] = stored
+			}
+		}
+		b.writesL.Unlock()
+	}
+}
+
+// restore enqueues the evaluations persisted in the store, keeping their dequeue counts.
+// Evaluations that were inflight when the broker stopped are delivered again with new
+// receipt handles, and the ones that reached the delivery limit go to the dead letter queue
+// and are no longer persisted. The evaluations are deleted instead if the broker discards them.
+// It must be called with the lock held, after the broker is enabled.
+func (b *InMemoryBroker) restore() error {
+	if b.store == nil {
+		return nil
+	}
+	// write any change not written yet, so that the store is up to date
+	b.writeStore()
+	evaluations, err := b.store.List(context.Background())
+	if err != nil {
+		return err
+	}
+	if b.discardStored {
+		evalIDs := make([]string, len(evaluations))
+		for i, stored := range evaluations {
+			evalIDs[i] = stored.Evaluation.ID
+		}
+		if err = b.store.Write(context.Background(), nil, evalIDs); err != nil {
+			return err
+		}
+		log.Debug().Int("Evaluations", len(evaluations)).Msg("Discarded persisted evaluations")
+		return nil
+	}
+	for _, stored := range evaluations {
+		eval := stored.Evaluation
+		if _, ok := b.evals[eval.ID]; ok {
+			continue
+		}
+		b.evals[eval.ID] = stored.ReceiveCount
+		queue := eval.Type
+		if stored.ReceiveCount >= b.maxReceiveCount {
+			queue = deadLetterQueue
+			b.unpersist(eval.ID)
+		}
+		if err = b.enqueueLocked(eval, queue); err != nil {
+			return fmt.Errorf("failed to enqueue restored evaluation %s: %w", eval.ID, err)
+		}
+	}
+	log.Debug().Int("Evaluations", len(evaluations)).Msg("Restored persisted evaluations")
+	return nil
+}
+
 // nackReenqueueDelay is used to determine the delay that should be applied on
 // the evaluation given the number of previous attempts
 func (b *InMemoryBroker) nackReenqueueDelay(eval *models.Evaluation, prevDequeues int) time.Duration {
@@ -674,7 +875,7 @@ func (b *InMemoryBroker) nackReenqueueDelay(eval *models.Evaluation, prevDequeue
 }
 
 // Flush is used to clear the state of the broker. It must be called from within
-// the lock.
+// the lock. Persisted evaluations are kept, to be restored when the broker is enabled again.
 func (b *InMemoryBroker) flush() {
 	// Unblock any waiters
 	for _, waitCh := range b.waiting {
@@ -691,6 +892,12 @@ func (b *InMemoryBroker) flush() {
 		b.delayedEvalCancelFunc()
 	}
 
+	// Cancel the store writer goroutine
+	if b.storeWriterCancelFunc != nil {
+		b.storeWriterCancelFunc()
+		b.storeWriterCancelFunc = nil
+	}
+
 	if b.metricRegistration != nil {
 		_ = b.metricRegistration.Unregister()
 		b.metricRegistration = nil
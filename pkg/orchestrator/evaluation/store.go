@@ -0,0 +1,29 @@
+package evaluation
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// StoredEvaluation is an evaluation tracked by the broker, along with
+// the number of times it was dequeued.
+type StoredEvaluation struct {
+	Evaluation   *models.Evaluation `json:"Evaluation"`
+	ReceiveCount int                `json:"ReceiveCount"`
+}
+
+// Store persists the evaluations tracked by a broker until they are acknowledged,
+// so that pending, delayed and inflight evaluations survive a restart of the broker.
+type Store interface {
+	// Put creates or replaces the stored evaluation
+	Put(ctx context.Context, stored StoredEvaluation) error
+	// Delete deletes the evaluation with the given ID, if it is stored
+	Delete(ctx context.Context, evalID string) error
+	// Write puts and deletes a batch of evaluations in a single transaction
+	Write(ctx context.Context, puts []StoredEvaluation, deletes []string) error
+	// List returns all the stored evaluations
+	List(ctx context.Context) ([]StoredEvaluation, error)
+	// Close closes the store
+	Close(ctx context.Context) error
+}
@@ -2,6 +2,7 @@ package orchestrator
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +18,9 @@ const (
 	EventTopicExecutionTimeout models.EventTopic = "Exec Timeout"
 	EventTopicJobTimeout       models.EventTopic = "Job Timeout"
 	EventTopicExecution        models.EventTopic = "Execution"
+	EventTopicExecPreemption   models.EventTopic = "Exec Preemption"
+	EventTopicWebhook          models.EventTopic = "Webhook"
+	EventTopicExecAudit        models.EventTopic = "Exec Audit"
 )
 
 const (
@@ -29,18 +33,33 @@ const (
 	jobExhaustedRetriesMessage = "Job failed because it has been retried too many times"
 	JobTimeoutMessage          = "Job timed out"
 	jobExecutionsFailedMessage = "Job failed because one or more executions failed"
+	jobDependencyFailedMessage = "Job failed because an upstream job did not complete"
+	jobScheduledMessage        = "Job scheduled"
+	jobScheduledRunMessage     = "Scheduled run started"
+	jobScheduledSkipMessage    = "Scheduled run skipped because the previous run is still in progress"
 
 	execCompletedMessage                 = "Completed successfully"
 	execRunningMessage                   = "Running"
 	execStoppedByJobStopMessage          = "Execution stop requested because job has been stopped"
 	execStoppedByNodeUnhealthyMessage    = "Execution stop requested because node has disappeared"
 	execStoppedByNodeRejectedMessage     = "Execution stop requested because node has been rejected"
+	execStoppedByNodeDrainMessage        = "Execution stop requested because node is being drained"
 	execStoppedByOversubscriptionMessage = "Execution stop requested because there are more executions than needed"
 	execStoppedDueToJobFailureMessage    = "Execution stopped due to job failure"
 	execStoppedForJobUpdateMessage       = "Execution stopped for job update"
+	execPreemptedMessage                 = "Execution preempted to make room for a higher priority job"
+	execResumedFromCheckpointMessage     = "Execution resuming from the last checkpoint of a previous execution"
+
+	execAttachStartedMessage   = "Attached to execution"
+	execAttachFinishedMessage  = "Detached from execution"
+	execCommandStartedMessage  = "Command started inside execution"
+	execCommandFinishedMessage = "Command finished inside execution"
 
 	executionTimeoutMessage = "Execution timed out"
 
+	webhookDeliveredMessage = "Webhook notification delivered"
+	webhookFailedMessage    = "Webhook notification failed"
+
 	// TODO: message is duplicated in compute/errors.go. Find a better place for common errors
 	timeoutHint = "Increase the task timeout or allocate more resources"
 )
@@ -101,6 +120,31 @@ func JobExecutionsFailedEvent() models.Event {
 	return event(EventTopicJobScheduling, jobExecutionsFailedMessage, map[string]string{})
 }
 
+func JobDependencyFailedEvent(upstreamJobID, reason string) models.Event {
+	return event(EventTopicJobScheduling, jobDependencyFailedMessage, map[string]string{
+		"UpstreamJobID": upstreamJobID,
+		"Reason":        reason,
+	})
+}
+
+func JobScheduledEvent(nextRun time.Time) models.Event {
+	return event(EventTopicJobScheduling, jobScheduledMessage, map[string]string{
+		"NextRun": nextRun.Format(time.RFC3339),
+	})
+}
+
+func JobScheduledRunEvent(tick time.Time) models.Event {
+	return event(EventTopicJobScheduling, jobScheduledRunMessage, map[string]string{
+		"ScheduledTime": tick.Format(time.RFC3339),
+	})
+}
+
+func JobScheduledRunSkippedEvent(tick time.Time) models.Event {
+	return event(EventTopicJobScheduling, jobScheduledSkipMessage, map[string]string{
+		"ScheduledTime": tick.Format(time.RFC3339),
+	})
+}
+
 func JobQueueingEvent(reason string) models.Event {
 	message := jobQueuedMessage
 	if reason != "" {
@@ -149,6 +193,10 @@ func ExecStoppedByNodeRejectedEvent() models.Event {
 	return event(EventTopicJobScheduling, execStoppedByNodeRejectedMessage, map[string]string{})
 }
 
+func ExecStoppedByNodeDrainEvent() models.Event {
+	return event(EventTopicJobScheduling, execStoppedByNodeDrainMessage, map[string]string{})
+}
+
 func ExecStoppedByOversubscriptionEvent() models.Event {
 	return event(EventTopicJobScheduling, execStoppedByOversubscriptionMessage, map[string]string{})
 }
@@ -160,3 +208,68 @@ func ExecStoppedDueToJobFailureEvent() models.Event {
 func ExecStoppedForJobUpdateEvent() models.Event {
 	return event(EventTopicJobScheduling, execStoppedForJobUpdateMessage, map[string]string{})
 }
+
+func ExecPreemptedEvent(preemptedBy *models.Job) models.Event {
+	return event(EventTopicExecPreemption, execPreemptedMessage, map[string]string{
+		"PreemptedByJobID":    preemptedBy.ID,
+		"PreemptedByPriority": strconv.Itoa(preemptedBy.Priority),
+	})
+}
+
+// ExecResumedFromCheckpointEvent returns an event indicating that a new execution resumes
+// from the last checkpoint published by the previous execution it replaces
+func ExecResumedFromCheckpointEvent(previous *models.Execution) models.Event {
+	return event(EventTopicJobScheduling, execResumedFromCheckpointMessage, map[string]string{
+		"PreviousExecution":  previous.ID,
+		"CheckpointSequence": strconv.Itoa(previous.Checkpoint.Sequence),
+	})
+}
+
+// ExecStartedEvent returns an event auditing a command run inside an execution,
+// or an attachment to the execution's main process if the command is empty
+func ExecStartedEvent(command []string, interactive bool) models.Event {
+	if len(command) == 0 {
+		return event(EventTopicExecAudit, execAttachStartedMessage, map[string]string{
+			"Interactive": strconv.FormatBool(interactive),
+		})
+	}
+	return event(EventTopicExecAudit, execCommandStartedMessage, map[string]string{
+		"Command":     strings.Join(command, " "),
+		"Interactive": strconv.FormatBool(interactive),
+	})
+}
+
+// ExecFinishedEvent returns an event auditing the end of a command run inside an execution,
+// or of an attachment to the execution's main process if the command is empty
+func ExecFinishedEvent(command []string, exitCode int, err error) models.Event {
+	message := execCommandFinishedMessage
+	details := map[string]string{}
+	if len(command) == 0 {
+		message = execAttachFinishedMessage
+	} else {
+		details["Command"] = strings.Join(command, " ")
+	}
+	if err != nil {
+		details["Error"] = err.Error()
+	} else {
+		details["ExitCode"] = strconv.Itoa(exitCode)
+	}
+	return event(EventTopicExecAudit, message, details)
+}
+
+func WebhookDeliveredEvent(webhookEvent models.WebhookEventType, url string, attempts int) models.Event {
+	return event(EventTopicWebhook, webhookDeliveredMessage, map[string]string{
+		"Event":    string(webhookEvent),
+		"URL":      url,
+		"Attempts": strconv.Itoa(attempts),
+	})
+}
+
+func WebhookFailedEvent(webhookEvent models.WebhookEventType, url string, attempts int, err error) models.Event {
+	return event(EventTopicWebhook, fmt.Sprintf("%s after %d attempts: %s", webhookFailedMessage, attempts, err),
+		map[string]string{
+			"Event":    string(webhookEvent),
+			"URL":      url,
+			"Attempts": strconv.Itoa(attempts),
+		})
+}
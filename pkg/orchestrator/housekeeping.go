@@ -33,6 +33,9 @@ type HousekeepingParams struct {
 	// Clock is the clock used for time-based operations.
 	// If not provided, the system clock is used.
 	Clock clock.Clock
+	// Leadership reports whether this orchestrator is the leader of its cluster.
+	// If provided, housekeeping tasks only run on the leader.
+	Leadership Leadership
 }
 
 type Housekeeping struct {
@@ -47,6 +50,11 @@ type Housekeeping struct {
 	stopChan   chan struct{}
 	running    bool
 	clock      clock.Clock
+	leadership Leadership
+
+	// skippedRuns tracks the latest skipped scheduled run of each job
+	// to avoid recording the same skip multiple times
+	skippedRuns map[string]time.Time
 }
 
 func NewHousekeeping(params HousekeepingParams) (*Housekeeping, error) {
@@ -76,6 +84,8 @@ func NewHousekeeping(params HousekeepingParams) (*Housekeeping, error) {
 		workersSem:    make(chan struct{}, params.Workers),
 		stopChan:      make(chan struct{}),
 		clock:         params.Clock,
+		leadership:    params.Leadership,
+		skippedRuns:   make(map[string]time.Time),
 	}
 
 	return h, nil
@@ -86,11 +96,10 @@ func (h *Housekeeping) IsRunning() bool {
 	return h.running
 }
 
-// ShouldRun returns true if the housekeeping task should run.
-// This is just a placeholder for now until we introduce leader election or lease management for housekeeping
-// when we introduce more than one orchestrator.
+// ShouldRun returns true if the housekeeping task should run, which is only
+// on the leader when several orchestrators run with leader election.
 func (h *Housekeeping) ShouldRun() bool {
-	return true
+	return h.leadership == nil || h.leadership.IsLeader()
 }
 
 // Start starts the housekeeping task
@@ -131,6 +140,9 @@ func (h *Housekeeping) runHousekeepingTasks(ctx context.Context) {
 				continue
 			}
 
+			// start scheduled runs that are due
+			h.triggerScheduledJobs(ctx)
+
 			// fetch active executions
 			activeExecutions := h.fetchActiveExecutions(ctx)
 
@@ -245,3 +257,121 @@ func (h *Housekeeping) enqueueTimeoutTask(ctx context.Context, job *models.Job,
 		}
 	}()
 }
+
+// triggerScheduledJobs starts a new run of each scheduled job that is due,
+// following the job's overlap policy if its previous run is still in progress.
+func (h *Housekeeping) triggerScheduledJobs(ctx context.Context) {
+	scheduledJobs, err := h.jobStore.GetScheduledJobs(ctx)
+	if err != nil {
+		log.Ctx(ctx).Err(err).Msg("failed to get scheduled jobs")
+		return
+	}
+
+	now := h.clock.Now()
+	scheduled := make(map[string]bool, len(scheduledJobs))
+	for i := range scheduledJobs {
+		scheduled[scheduledJobs[i].ID] = true
+		// log error and avoid having a single job failure affect the scheduling of other jobs
+		if err = h.triggerScheduledJob(ctx, &scheduledJobs[i], now); err != nil {
+			log.Ctx(ctx).Err(err).Msgf("failed to trigger scheduled run of job %s", scheduledJobs[i].ID)
+		}
+	}
+
+	// forget the skipped runs of jobs that were stopped or deleted
+	for jobID := range h.skippedRuns {
+		if !scheduled[jobID] {
+			delete(h.skippedRuns, jobID)
+		}
+	}
+}
+
+func (h *Housekeeping) triggerScheduledJob(ctx context.Context, job *models.Job, now time.Time) error {
+	// the latest version of the job is either a scheduled run, or a newly submitted or updated job
+	// that is waiting for its first tick
+	lastRun := job.ScheduledRunTime()
+	if lastRun.IsZero() {
+		lastRun = job.GetModifyTime()
+	}
+	due := job.Schedule.LatestTick(lastRun, now)
+	if due.IsZero() {
+		return nil
+	}
+
+	inProgress := job.IsScheduledRun() && !job.IsTerminal()
+	switch {
+	case inProgress && job.Schedule.OverlapPolicy == models.ScheduleOverlapQueue:
+		// start the due run once the previous one is done
+		return nil
+	case inProgress && job.Schedule.OverlapPolicy == models.ScheduleOverlapReplace:
+		// starting a new run stops the executions of the previous one
+	case inProgress:
+		return h.skipScheduledRun(ctx, job, due)
+	case job.Schedule.OverlapPolicy == models.ScheduleOverlapSkip && job.IsScheduledRun() &&
+		!job.GetModifyTime().Before(due):
+		// the tick was due while the previous run was still in progress
+		return h.skipScheduledRun(ctx, job, due)
+	}
+
+	return h.startScheduledRun(ctx, job, due)
+}
+
+// skipScheduledRun records a job history event the first time a scheduled run is skipped
+func (h *Housekeeping) skipScheduledRun(ctx context.Context, job *models.Job, due time.Time) error {
+	if h.skippedRuns[job.ID].Equal(due) {
+		return nil
+	}
+	if err := h.jobStore.AddJobHistory(ctx, job.ID, job.Version, JobScheduledRunSkippedEvent(due)); err != nil {
+		return err
+	}
+	h.skippedRuns[job.ID] = due
+	return nil
+}
+
+// startScheduledRun creates a new version of the job flagged with the time of the scheduled run,
+// and enqueues an evaluation for the scheduler to run it, similar to a job rerun.
+func (h *Housekeeping) startScheduledRun(ctx context.Context, job *models.Job, due time.Time) (err error) {
+	txContext, err := h.jobStore.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = txContext.Rollback()
+		}
+	}()
+
+	run := job.Copy()
+	if run.Meta == nil {
+		run.Meta = make(map[string]string)
+	}
+	run.Meta[models.MetaScheduledRunTime] = due.UTC().Format(time.RFC3339)
+
+	if err = h.jobStore.UpdateJob(txContext, *run); err != nil {
+		return err
+	}
+	if err = h.jobStore.UpdateJobState(txContext, jobstore.UpdateJobStateRequest{
+		JobID:    job.ID,
+		NewState: models.JobStateTypePending,
+		Message:  "scheduled run",
+	}); err != nil {
+		return err
+	}
+	if err = h.jobStore.AddJobHistory(txContext, job.ID, job.Version+jobVersionIncrement, JobScheduledRunEvent(due)); err != nil {
+		return err
+	}
+
+	eval := models.NewEvaluation().
+		WithJob(run).
+		WithTriggeredBy(models.EvalTriggerJobSchedule).
+		WithComment(fmt.Sprintf("scheduled run of job %s due at %s", job.ID, due.Format(time.RFC3339))).
+		Normalize()
+	if err = h.jobStore.CreateEvaluation(txContext, *eval); err != nil {
+		return err
+	}
+
+	if err = txContext.Commit(); err != nil {
+		return err
+	}
+	delete(h.skippedRuns, job.ID)
+	return nil
+}
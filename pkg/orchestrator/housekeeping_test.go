@@ -43,6 +43,7 @@ func (s *HousekeepingTestSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.clock = clock.NewMock()
 	s.mockJobStore = jobstore.NewMockStore(s.ctrl)
+	s.mockJobStore.EXPECT().GetScheduledJobs(gomock.Any()).AnyTimes().Return(nil, nil)
 
 	h, _ := NewHousekeeping(HousekeepingParams{
 		JobStore:      s.mockJobStore,
@@ -307,6 +308,25 @@ func (s *HousekeepingTestSuite) TestShouldRun() {
 	s.True(s.housekeeping.ShouldRun())
 }
 
+func (s *HousekeepingTestSuite) TestShouldRunOnlyOnLeader() {
+	leadership := NewMockLeadership(s.ctrl)
+	h, err := NewHousekeeping(HousekeepingParams{
+		JobStore:      s.mockJobStore,
+		Interval:      200 * time.Millisecond,
+		Workers:       1,
+		TimeoutBuffer: timeoutBuffer,
+		Clock:         s.clock,
+		Leadership:    leadership,
+	})
+	s.Require().NoError(err)
+
+	leadership.EXPECT().IsLeader().Return(false)
+	s.False(h.ShouldRun())
+
+	leadership.EXPECT().IsLeader().Return(true)
+	s.True(h.ShouldRun())
+}
+
 func (s *HousekeepingTestSuite) TestStop() {
 	s.housekeeping.Start(context.Background())
 	s.Eventually(func() bool { return s.housekeeping.IsRunning() }, 1*time.Second, 10*time.Millisecond)
@@ -429,3 +449,154 @@ func (s *HousekeepingTestSuite) mockJob(CreateTime time.Duration, ModifyTime ...
 func TestHousekeepingTestSuite(t *testing.T) {
 	suite.Run(t, new(HousekeepingTestSuite))
 }
+
+type ScheduledJobsTestSuite struct {
+	suite.Suite
+	ctx           context.Context
+	ctrl          *gomock.Controller
+	clock         *clock.Mock
+	mockJobStore  *jobstore.MockStore
+	mockTxContext *jobstore.MockTxContext
+	housekeeping  *Housekeeping
+}
+
+func (s *ScheduledJobsTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.ctrl = gomock.NewController(s.T())
+	s.clock = clock.NewMock()
+	s.clock.Set(time.Date(2024, 1, 15, 10, 0, 30, 0, time.UTC))
+	s.mockJobStore = jobstore.NewMockStore(s.ctrl)
+	s.mockTxContext = jobstore.NewMockTxContext(s.ctrl)
+
+	h, err := NewHousekeeping(HousekeepingParams{
+		JobStore:      s.mockJobStore,
+		Interval:      200 * time.Millisecond,
+		TimeoutBuffer: timeoutBuffer,
+		Clock:         s.clock,
+	})
+	s.Require().NoError(err)
+	s.housekeeping = h
+}
+
+func TestScheduledJobsTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduledJobsTestSuite))
+}
+
+// mockScheduledJob returns an hourly scheduled job whose latest version was modified at the given time,
+// and that was triggered by the given tick if not zero.
+func (s *ScheduledJobsTestSuite) mockScheduledJob(
+	policy models.ScheduleOverlapPolicy, state models.JobStateType, modifyTime, runTime time.Time) *models.Job {
+	job := mock.Job()
+	job.Schedule = &models.Schedule{Cron: "@hourly", OverlapPolicy: policy}
+	job.State = models.NewJobState(state)
+	job.ModifyTime = modifyTime.UnixNano()
+	if !runTime.IsZero() {
+		job.Meta[models.MetaScheduledRunTime] = runTime.Format(time.RFC3339)
+	}
+	return job
+}
+
+func (s *ScheduledJobsTestSuite) expectScheduledRun(job *models.Job, due time.Time) {
+	s.mockJobStore.EXPECT().BeginTx(s.ctx).Return(s.mockTxContext, nil)
+	s.mockJobStore.EXPECT().UpdateJob(s.mockTxContext, gomock.Any()).Do(func(_ context.Context, run models.Job) {
+		s.Equal(due, run.ScheduledRunTime())
+	}).Return(nil)
+	s.mockJobStore.EXPECT().UpdateJobState(s.mockTxContext, jobstore.UpdateJobStateRequest{
+		JobID:    job.ID,
+		NewState: models.JobStateTypePending,
+		Message:  "scheduled run",
+	}).Return(nil)
+	s.mockJobStore.EXPECT().AddJobHistory(s.mockTxContext, job.ID, job.Version+1, gomock.Any()).Return(nil)
+	s.mockJobStore.EXPECT().CreateEvaluation(s.mockTxContext, gomock.Any()).Do(func(_ context.Context, eval models.Evaluation) {
+		s.Equal(job.ID, eval.JobID)
+		s.Equal(models.EvalTriggerJobSchedule, eval.TriggeredBy)
+	}).Return(nil)
+	s.mockTxContext.EXPECT().Commit().Return(nil)
+}
+
+func (s *ScheduledJobsTestSuite) TestFirstRun() {
+	due := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	job := s.mockScheduledJob(models.ScheduleOverlapSkip, models.JobStateTypePending, due.Add(-time.Minute), time.Time{})
+
+	s.mockJobStore.EXPECT().GetScheduledJobs(s.ctx).Return([]models.Job{*job}, nil)
+	s.expectScheduledRun(job, due)
+	s.housekeeping.triggerScheduledJobs(s.ctx)
+}
+
+func (s *ScheduledJobsTestSuite) TestNotDue() {
+	lastRun := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	job := s.mockScheduledJob(models.ScheduleOverlapSkip, models.JobStateTypeCompleted, lastRun.Add(time.Minute), lastRun)
+
+	s.mockJobStore.EXPECT().GetScheduledJobs(s.ctx).Return([]models.Job{*job}, nil)
+	s.housekeeping.triggerScheduledJobs(s.ctx)
+}
+
+func (s *ScheduledJobsTestSuite) TestNextRunAfterCompletion() {
+	lastRun := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	job := s.mockScheduledJob(models.ScheduleOverlapSkip, models.JobStateTypeCompleted, lastRun.Add(time.Minute), lastRun)
+
+	s.mockJobStore.EXPECT().GetScheduledJobs(s.ctx).Return([]models.Job{*job}, nil)
+	s.expectScheduledRun(job, lastRun.Add(time.Hour))
+	s.housekeeping.triggerScheduledJobs(s.ctx)
+}
+
+func (s *ScheduledJobsTestSuite) TestSkipWhileInProgress() {
+	lastRun := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	due := lastRun.Add(time.Hour)
+	job := s.mockScheduledJob(models.ScheduleOverlapSkip, models.JobStateTypeRunning, lastRun.Add(time.Minute), lastRun)
+
+	// the skip is only recorded once
+	s.mockJobStore.EXPECT().GetScheduledJobs(s.ctx).Return([]models.Job{*job}, nil).Times(2)
+	s.mockJobStore.EXPECT().AddJobHistory(s.ctx, job.ID, job.Version, gomock.Any()).Do(
+		func(_ context.Context, _ string, _ uint64, event models.Event) {
+			s.Equal(due.Format(time.RFC3339), event.Details["ScheduledTime"])
+		}).Return(nil)
+	s.housekeeping.triggerScheduledJobs(s.ctx)
+	s.housekeeping.triggerScheduledJobs(s.ctx)
+
+	// the skipped tick doesn't run once the previous run completes
+	job.State = models.NewJobState(models.JobStateTypeCompleted)
+	job.ModifyTime = due.Add(10 * time.Second).UnixNano()
+	s.mockJobStore.EXPECT().GetScheduledJobs(s.ctx).Return([]models.Job{*job}, nil)
+	s.housekeeping.triggerScheduledJobs(s.ctx)
+}
+
+func (s *ScheduledJobsTestSuite) TestForgetSkippedRunsOfStoppedJobs() {
+	lastRun := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	job := s.mockScheduledJob(models.ScheduleOverlapSkip, models.JobStateTypeRunning, lastRun.Add(time.Minute), lastRun)
+
+	s.mockJobStore.EXPECT().GetScheduledJobs(s.ctx).Return([]models.Job{*job}, nil)
+	s.mockJobStore.EXPECT().AddJobHistory(s.ctx, job.ID, job.Version, gomock.Any()).Return(nil)
+	s.housekeeping.triggerScheduledJobs(s.ctx)
+	s.Contains(s.housekeeping.skippedRuns, job.ID)
+
+	// the job is no longer scheduled once stopped or deleted
+	s.mockJobStore.EXPECT().GetScheduledJobs(s.ctx).Return([]models.Job{}, nil)
+	s.housekeeping.triggerScheduledJobs(s.ctx)
+	s.Empty(s.housekeeping.skippedRuns)
+}
+
+func (s *ScheduledJobsTestSuite) TestQueueWhileInProgress() {
+	lastRun := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	due := lastRun.Add(time.Hour)
+	job := s.mockScheduledJob(models.ScheduleOverlapQueue, models.JobStateTypeRunning, lastRun.Add(time.Minute), lastRun)
+
+	s.mockJobStore.EXPECT().GetScheduledJobs(s.ctx).Return([]models.Job{*job}, nil)
+	s.housekeeping.triggerScheduledJobs(s.ctx)
+
+	// the queued tick runs once the previous run completes
+	job.State = models.NewJobState(models.JobStateTypeCompleted)
+	job.ModifyTime = due.Add(10 * time.Second).UnixNano()
+	s.mockJobStore.EXPECT().GetScheduledJobs(s.ctx).Return([]models.Job{*job}, nil)
+	s.expectScheduledRun(job, due)
+	s.housekeeping.triggerScheduledJobs(s.ctx)
+}
+
+func (s *ScheduledJobsTestSuite) TestReplaceWhileInProgress() {
+	lastRun := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	job := s.mockScheduledJob(models.ScheduleOverlapReplace, models.JobStateTypeRunning, lastRun.Add(time.Minute), lastRun)
+
+	s.mockJobStore.EXPECT().GetScheduledJobs(s.ctx).Return([]models.Job{*job}, nil)
+	s.expectScheduledRun(job, lastRun.Add(time.Hour))
+	s.housekeeping.triggerScheduledJobs(s.ctx)
+}
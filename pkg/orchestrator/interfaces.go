@@ -98,6 +98,9 @@ type NodeSelector interface {
 	// AllNodes returns all nodes in the network.
 	AllNodes(ctx context.Context) ([]models.NodeInfo, error)
 
+	// DrainingNodes returns the IDs of the nodes that are being drained.
+	DrainingNodes(ctx context.Context) ([]string, error)
+
 	// MatchingNodes return the nodes that match job constraints order by rank in descending order.
 	// Also return the nodes that were filtered out and an error if any.
 	MatchingNodes(
@@ -110,3 +113,10 @@ type RetryStrategy interface {
 	// ShouldRetry returns true if the job can be retried.
 	ShouldRetry(ctx context.Context, request RetryRequest) bool
 }
+
+// Leadership reports whether this orchestrator is the leader of its cluster.
+// Only the leader runs the components that must not run on several orchestrators at once.
+type Leadership interface {
+	// IsLeader returns true if this orchestrator is currently the leader.
+	IsLeader() bool
+}
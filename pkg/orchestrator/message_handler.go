@@ -32,6 +32,8 @@ func NewMessageHandler(store jobstore.Store) *MessageHandler {
 func (m *MessageHandler) ShouldProcess(ctx context.Context, message *envelope.Message) bool {
 	return message.Metadata.Get(envelope.KeyMessageType) == messages.BidResultMessageType ||
 		message.Metadata.Get(envelope.KeyMessageType) == messages.RunResultMessageType ||
+		message.Metadata.Get(envelope.KeyMessageType) == messages.CheckpointResultMessageType ||
+		message.Metadata.Get(envelope.KeyMessageType) == messages.PartialResultMessageType ||
 		message.Metadata.Get(envelope.KeyMessageType) == messages.ComputeErrorMessageType
 }
 
@@ -52,6 +54,10 @@ func (m *MessageHandler) HandleMessage(ctx context.Context, message *envelope.Me
 		err = m.OnBidComplete(ctx, metrics, message)
 	case messages.RunResultMessageType:
 		err = m.OnRunComplete(ctx, metrics, message)
+	case messages.CheckpointResultMessageType:
+		err = m.OnCheckpoint(ctx, metrics, message)
+	case messages.PartialResultMessageType:
+		err = m.OnPartialResult(ctx, metrics, message)
 	case messages.ComputeErrorMessageType:
 		err = m.OnComputeFailure(ctx, metrics, message)
 	}
@@ -160,6 +166,7 @@ func (m *MessageHandler) OnRunComplete(ctx context.Context, metrics *telemetry.M
 		NewValues: models.Execution{
 			PublishedResult: result.PublishResult,
 			RunOutput:       result.RunCommandResult,
+			ResourceUsage:   result.RunCommandResult.GetResourceUsage(),
 			ComputeState:    models.NewExecutionState(models.ExecutionStateCompleted),
 			DesiredState:    models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped).WithMessage("execution completed"),
 		},
@@ -191,6 +198,60 @@ func (m *MessageHandler) OnRunComplete(ctx context.Context, metrics *telemetry.M
 	return err
 }
 
+// OnCheckpoint records the last checkpoint published by a running execution, which is handed to the
+// execution replacing it if it fails. No evaluation is enqueued as the execution's state didn't change.
+func (m *MessageHandler) OnCheckpoint(ctx context.Context, metrics *telemetry.MetricRecorder, message *envelope.Message) error {
+	result, ok := message.Payload.(*messages.CheckpointResult)
+	if !ok {
+		return envelope.NewErrUnexpectedPayloadType("CheckpointResult", reflect.TypeOf(message.Payload).String())
+	}
+	if err := result.Checkpoint.Validate(); err != nil {
+		return fmt.Errorf("invalid checkpoint for execution %s: %w", result.ExecutionID, err)
+	}
+
+	err := m.store.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: result.ExecutionID,
+		Condition: jobstore.UpdateExecutionCondition{
+			ExpectedDesiredStates: []models.ExecutionDesiredStateType{
+				models.ExecutionDesiredStateRunning,
+			},
+		},
+		NewValues: models.Execution{
+			Checkpoint: result.Checkpoint,
+		},
+		Events: result.Events,
+	})
+	metrics.Latency(ctx, messageHandlerProcessPartDuration, AttrPartUpdateExec)
+	return err
+}
+
+// OnPartialResult records where the results published so far by a running execution are, so that they can
+// be fetched before the execution completes. No evaluation is enqueued as the execution's state didn't change.
+func (m *MessageHandler) OnPartialResult(ctx context.Context, metrics *telemetry.MetricRecorder, message *envelope.Message) error {
+	result, ok := message.Payload.(*messages.PartialResult)
+	if !ok {
+		return envelope.NewErrUnexpectedPayloadType("PartialResult", reflect.TypeOf(message.Payload).String())
+	}
+	if err := result.PartialResult.Validate(); err != nil {
+		return fmt.Errorf("invalid partial result for execution %s: %w", result.ExecutionID, err)
+	}
+
+	err := m.store.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: result.ExecutionID,
+		Condition: jobstore.UpdateExecutionCondition{
+			ExpectedDesiredStates: []models.ExecutionDesiredStateType{
+				models.ExecutionDesiredStateRunning,
+			},
+		},
+		NewValues: models.Execution{
+			PartialResult: result.PartialResult,
+		},
+		Events: result.Events,
+	})
+	metrics.Latency(ctx, messageHandlerProcessPartDuration, AttrPartUpdateExec)
+	return err
+}
+
 func (m *MessageHandler) OnComputeFailure(ctx context.Context, metrics *telemetry.MetricRecorder, message *envelope.Message) error {
 	result, ok := message.Payload.(*messages.ComputeError)
 	if !ok {
@@ -38,6 +38,8 @@ func (suite *MessageHandlerTestSuite) TearDownTest() {
 func (suite *MessageHandlerTestSuite) TestShouldProcess() {
 	suite.True(suite.handler.ShouldProcess(context.Background(), envelope.NewMessage(nil).WithMetadataValue(envelope.KeyMessageType, messages.BidResultMessageType)))
 	suite.True(suite.handler.ShouldProcess(context.Background(), envelope.NewMessage(nil).WithMetadataValue(envelope.KeyMessageType, messages.RunResultMessageType)))
+	suite.True(suite.handler.ShouldProcess(context.Background(), envelope.NewMessage(nil).WithMetadataValue(envelope.KeyMessageType, messages.CheckpointResultMessageType)))
+	suite.True(suite.handler.ShouldProcess(context.Background(), envelope.NewMessage(nil).WithMetadataValue(envelope.KeyMessageType, messages.PartialResultMessageType)))
 	suite.True(suite.handler.ShouldProcess(context.Background(), envelope.NewMessage(nil).WithMetadataValue(envelope.KeyMessageType, messages.ComputeErrorMessageType)))
 	suite.False(suite.handler.ShouldProcess(context.Background(), envelope.NewMessage(nil).WithMetadataValue(envelope.KeyMessageType, "UnknownType")))
 }
@@ -134,6 +136,71 @@ func (suite *MessageHandlerTestSuite) TestHandleRunCompleteForLongRunningJob() {
 	suite.NoError(err)
 }
 
+func (suite *MessageHandlerTestSuite) TestHandleCheckpoint() {
+	ctx := context.Background()
+	checkpoint := &models.Checkpoint{Result: &models.SpecConfig{Type: "s3"}, Sequence: 2}
+	checkpointResult := &messages.CheckpointResult{
+		BaseResponse: messages.BaseResponse{
+			ExecutionID: "exec-1",
+			JobID:       "job-1",
+			JobType:     "batch",
+		},
+		Checkpoint: checkpoint,
+	}
+	message := envelope.NewMessage(checkpointResult).WithMetadataValue(envelope.KeyMessageType, messages.CheckpointResultMessageType)
+
+	// the checkpoint is recorded without enqueuing an evaluation
+	suite.mockStore.EXPECT().UpdateExecution(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, request jobstore.UpdateExecutionRequest) error {
+			suite.Equal("exec-1", request.ExecutionID)
+			suite.Equal(checkpoint, request.NewValues.Checkpoint)
+			suite.True(request.NewValues.ComputeState.StateType.IsUndefined())
+			return nil
+		})
+
+	err := suite.handler.HandleMessage(ctx, message)
+	suite.NoError(err)
+}
+
+func (suite *MessageHandlerTestSuite) TestHandleInvalidCheckpoint() {
+	ctx := context.Background()
+	checkpointResult := &messages.CheckpointResult{
+		BaseResponse: messages.BaseResponse{ExecutionID: "exec-1", JobID: "job-1"},
+		Checkpoint:   &models.Checkpoint{},
+	}
+	message := envelope.NewMessage(checkpointResult).WithMetadataValue(envelope.KeyMessageType, messages.CheckpointResultMessageType)
+
+	// errors are logged, and the store is not updated
+	err := suite.handler.HandleMessage(ctx, message)
+	suite.NoError(err)
+}
+
+func (suite *MessageHandlerTestSuite) TestHandlePartialResult() {
+	ctx := context.Background()
+	partial := &models.PartialResult{Result: &models.SpecConfig{Type: "s3"}, Sequence: 3}
+	partialResult := &messages.PartialResult{
+		BaseResponse: messages.BaseResponse{
+			ExecutionID: "exec-1",
+			JobID:       "job-1",
+			JobType:     "batch",
+		},
+		PartialResult: partial,
+	}
+	message := envelope.NewMessage(partialResult).WithMetadataValue(envelope.KeyMessageType, messages.PartialResultMessageType)
+
+	// the partial result is recorded without enqueuing an evaluation
+	suite.mockStore.EXPECT().UpdateExecution(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, request jobstore.UpdateExecutionRequest) error {
+			suite.Equal("exec-1", request.ExecutionID)
+			suite.Equal(partial, request.NewValues.PartialResult)
+			suite.True(request.NewValues.ComputeState.StateType.IsUndefined())
+			return nil
+		})
+
+	err := suite.handler.HandleMessage(ctx, message)
+	suite.NoError(err)
+}
+
 func (suite *MessageHandlerTestSuite) TestHandleComputeFailure() {
 	ctx := context.Background()
 	computeError := &messages.ComputeError{
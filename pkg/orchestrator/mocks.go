@@ -383,6 +383,21 @@ func (mr *MockNodeSelectorMockRecorder) AllNodes(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllNodes", reflect.TypeOf((*MockNodeSelector)(nil).AllNodes), ctx)
 }
 
+// DrainingNodes mocks base method.
+func (m *MockNodeSelector) DrainingNodes(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainingNodes", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DrainingNodes indicates an expected call of DrainingNodes.
+func (mr *MockNodeSelectorMockRecorder) DrainingNodes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainingNodes", reflect.TypeOf((*MockNodeSelector)(nil).DrainingNodes), ctx)
+}
+
 // MatchingNodes mocks base method.
 func (m *MockNodeSelector) MatchingNodes(ctx context.Context, job *models.Job) ([]NodeRank, []NodeRank, error) {
 	m.ctrl.T.Helper()
@@ -435,3 +450,40 @@ func (mr *MockRetryStrategyMockRecorder) ShouldRetry(ctx, request interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldRetry", reflect.TypeOf((*MockRetryStrategy)(nil).ShouldRetry), ctx, request)
 }
+
+// MockLeadership is a mock of Leadership interface.
+type MockLeadership struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeadershipMockRecorder
+}
+
+// MockLeadershipMockRecorder is the mock recorder for MockLeadership.
+type MockLeadershipMockRecorder struct {
+	mock *MockLeadership
+}
+
+// NewMockLeadership creates a new mock instance.
+func NewMockLeadership(ctrl *gomock.Controller) *MockLeadership {
+	mock := &MockLeadership{ctrl: ctrl}
+	mock.recorder = &MockLeadershipMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeadership) EXPECT() *MockLeadershipMockRecorder {
+	return m.recorder
+}
+
+// IsLeader mocks base method.
+func (m *MockLeadership) IsLeader() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLeader")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsLeader indicates an expected call of IsLeader.
+func (mr *MockLeadershipMockRecorder) IsLeader() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLeader", reflect.TypeOf((*MockLeadership)(nil).IsLeader))
+}
@@ -60,6 +60,30 @@ func NewErrNodeAlreadyRejected(nodeID string) bacerrors.Error {
 		WithComponent(errComponent)
 }
 
+// NewErrNodeAlreadyCordoned returns a standardized error for when a node is already cordoned
+func NewErrNodeAlreadyCordoned(nodeID string) bacerrors.Error {
+	return bacerrors.Newf("node %s already cordoned", nodeID).
+		WithCode(ConflictNodeState).
+		WithHTTPStatusCode(http.StatusConflict).
+		WithComponent(errComponent)
+}
+
+// NewErrNodeAlreadyDraining returns a standardized error for when a node is already being drained
+func NewErrNodeAlreadyDraining(nodeID string) bacerrors.Error {
+	return bacerrors.Newf("node %s already draining", nodeID).
+		WithCode(ConflictNodeState).
+		WithHTTPStatusCode(http.StatusConflict).
+		WithComponent(errComponent)
+}
+
+// NewErrNodeNotCordoned returns a standardized error for when uncordoning a node that is not cordoned
+func NewErrNodeNotCordoned(nodeID string) bacerrors.Error {
+	return bacerrors.Newf("node %s is not cordoned", nodeID).
+		WithCode(ConflictNodeState).
+		WithHTTPStatusCode(http.StatusConflict).
+		WithComponent(errComponent)
+}
+
 // NewErrConcurrentModification returns a standardized error for concurrent update conflicts
 func NewErrConcurrentModification() bacerrors.Error {
 	return bacerrors.New("concurrent modification detected").
@@ -12,6 +12,7 @@ import (
 	"github.com/samber/lo"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	natsutil "github.com/bacalhau-project/bacalhau/pkg/nats"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/nodes"
 )
 
@@ -23,6 +24,8 @@ const (
 type NodeStoreParams struct {
 	BucketName string
 	Client     *nats.Conn
+	// Replicas is the number of NATS servers of the cluster holding a copy of the node states (optional)
+	Replicas int
 }
 
 type NodeStore struct {
@@ -41,8 +44,9 @@ func NewNodeStore(ctx context.Context, params NodeStoreParams) (*NodeStore, erro
 		return nil, pkgerrors.New("bucket name is required")
 	}
 
-	kv, err := js.CreateKeyValue(ctx, jetstream.KeyValueConfig{
-		Bucket: bucketName,
+	kv, err := natsutil.CreateKeyValue(ctx, js, jetstream.KeyValueConfig{
+		Bucket:   bucketName,
+		Replicas: params.Replicas,
 	})
 	if err != nil {
 		return nil, pkgerrors.Wrap(err, "failed to create key-value store")
@@ -79,6 +79,7 @@ type nodesManager struct {
 	handlers struct {
 		sync.RWMutex
 		connectionState []ConnectionStateChangeHandler
+		schedulingState []SchedulingStateChangeHandler
 	}
 }
 
@@ -458,7 +459,7 @@ func (n *nodesManager) publishAnalytics() {
 //
 // For existing nodes, it:
 //   - Verifies the node isn't rejected
-//   - Restores previous membership status
+//   - Restores previous membership and scheduling status
 //   - Updates connection state
 //
 // Returns HandshakeResponse with acceptance status and reason.
@@ -511,6 +512,7 @@ func (n *nodesManager) Handshake(
 
 	if isReconnect {
 		state.Membership = existing.Membership
+		state.Scheduling = existing.Scheduling
 		state.ConnectionState.LastComputeSeqNum = existing.ConnectionState.LastComputeSeqNum
 	}
 
@@ -791,6 +793,97 @@ func (n *nodesManager) DeleteNode(ctx context.Context, nodeID string) error {
 	return nil
 }
 
+// CordonNode stops new executions from being placed on a node.
+// Executions already running on the node are not affected.
+//
+// Returns error if:
+//   - Node not found
+//   - Already cordoned or draining
+//   - Storage update fails
+func (n *nodesManager) CordonNode(ctx context.Context, nodeID string, message string) error {
+	state, err := n.GetByPrefix(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+
+	if state.IsCordoned() {
+		return NewErrNodeAlreadyCordoned(nodeID)
+	}
+
+	return n.updateScheduling(ctx, state, models.NodeScheduling{
+		Cordoned: true,
+		Message:  message,
+	})
+}
+
+// DrainNode cordons a node and flags it as draining, which signals the
+// schedulers to move the node's service and daemon executions to other nodes.
+// Batch executions are left to complete. A cordoned node can be drained.
+//
+// Returns error if:
+//   - Node not found
+//   - Already draining
+//   - Storage update fails
+func (n *nodesManager) DrainNode(ctx context.Context, nodeID string, message string) error {
+	state, err := n.GetByPrefix(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+
+	if state.IsDraining() {
+		return NewErrNodeAlreadyDraining(nodeID)
+	}
+
+	return n.updateScheduling(ctx, state, models.NodeScheduling{
+		Cordoned: true,
+		Draining: true,
+		Message:  message,
+	})
+}
+
+// UncordonNode allows new executions to be placed on a cordoned node again,
+// which also stops the node from being drained.
+//
+// Returns error if:
+//   - Node not found
+//   - Node not cordoned
+//   - Storage update fails
+func (n *nodesManager) UncordonNode(ctx context.Context, nodeID string) error {
+	state, err := n.GetByPrefix(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+
+	if !state.IsCordoned() {
+		return NewErrNodeNotCordoned(nodeID)
+	}
+
+	return n.updateScheduling(ctx, state, models.NodeScheduling{})
+}
+
+// updateScheduling persists the new scheduling state of a node and notifies the handlers of the change
+func (n *nodesManager) updateScheduling(ctx context.Context, state models.NodeState, scheduling models.NodeScheduling) error {
+	previous := state.Scheduling
+	scheduling.UpdateTime = n.clock.Now().UTC()
+	state.Scheduling = scheduling
+	if err := n.store.Put(ctx, state); err != nil {
+		return err
+	}
+
+	n.handlers.RLock()
+	defer n.handlers.RUnlock()
+	event := NodeSchedulingEvent{
+		NodeID:    state.Info.ID(),
+		Previous:  previous,
+		Current:   scheduling,
+		Timestamp: scheduling.UpdateTime,
+	}
+	for _, handler := range n.handlers.schedulingState {
+		handler(event)
+	}
+	return nil
+}
+
 // OnConnectionStateChange registers a handler for node connection state changes.
 // Handlers are called synchronously when node state transitions between:
 //   - CONNECTED <-> DISCONNECTED
@@ -805,6 +898,14 @@ func (n *nodesManager) OnConnectionStateChange(handler ConnectionStateChangeHand
 	n.handlers.connectionState = append(n.handlers.connectionState, handler)
 }
 
+// OnSchedulingStateChange registers a handler for nodes being cordoned, drained or uncordoned.
+// Handlers are called synchronously after the new state is persisted.
+func (n *nodesManager) OnSchedulingStateChange(handler SchedulingStateChangeHandler) {
+	n.handlers.Lock()
+	defer n.handlers.Unlock()
+	n.handlers.schedulingState = append(n.handlers.schedulingState, handler)
+}
+
 // notifyConnectionStateChange notifies all registered handlers of a state change
 func (n *nodesManager) notifyConnectionStateChange(event NodeConnectionEvent) {
 	// Update connected nodes counter
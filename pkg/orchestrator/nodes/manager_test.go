@@ -666,6 +666,65 @@ func (s *NodeManagerTestSuite) TestNodeDeletion() {
 	assert.True(s.T(), bacerrors.IsErrorWithCode(err, bacerrors.NotFoundError))
 }
 
+func (s *NodeManagerTestSuite) TestCordonDrainUncordonNode() {
+	nodeInfo := s.createNodeInfo("node1")
+	_, err := s.manager.Handshake(s.ctx, messages.HandshakeRequest{NodeInfo: nodeInfo})
+	s.Require().NoError(err)
+
+	var events []nodes.NodeSchedulingEvent
+	s.manager.OnSchedulingStateChange(func(event nodes.NodeSchedulingEvent) {
+		events = append(events, event)
+	})
+
+	// Cordon node
+	s.Require().NoError(s.manager.CordonNode(s.ctx, nodeInfo.ID(), "maintenance"))
+	state, err := s.manager.Get(s.ctx, nodeInfo.ID())
+	s.Require().NoError(err)
+	assert.True(s.T(), state.IsCordoned())
+	assert.False(s.T(), state.IsDraining())
+	assert.Equal(s.T(), "maintenance", state.Scheduling.Message)
+	assert.Equal(s.T(), models.NodeStates.CONNECTED, state.ConnectionState.Status)
+
+	err = s.manager.CordonNode(s.ctx, nodeInfo.ID(), "")
+	assert.ErrorContains(s.T(), err, "already cordoned")
+
+	// Drain cordoned node
+	s.Require().NoError(s.manager.DrainNode(s.ctx, nodeInfo.ID(), "upgrade"))
+	state, err = s.manager.Get(s.ctx, nodeInfo.ID())
+	s.Require().NoError(err)
+	assert.True(s.T(), state.IsCordoned())
+	assert.True(s.T(), state.IsDraining())
+
+	err = s.manager.DrainNode(s.ctx, nodeInfo.ID(), "")
+	assert.ErrorContains(s.T(), err, "already draining")
+
+	// Reconnecting keeps the node draining
+	s.clock.Add(s.disconnected + time.Second)
+	_, err = s.manager.Handshake(s.ctx, messages.HandshakeRequest{NodeInfo: nodeInfo})
+	s.Require().NoError(err)
+	drainingNodes, err := s.manager.List(s.ctx, nodes.DrainingNodeFilter)
+	s.Require().NoError(err)
+	assert.Len(s.T(), drainingNodes, 1)
+
+	// Uncordon node
+	s.Require().NoError(s.manager.UncordonNode(s.ctx, nodeInfo.ID()))
+	state, err = s.manager.Get(s.ctx, nodeInfo.ID())
+	s.Require().NoError(err)
+	assert.False(s.T(), state.IsCordoned())
+	assert.True(s.T(), nodes.SchedulableNodeFilter(state))
+
+	err = s.manager.UncordonNode(s.ctx, nodeInfo.ID())
+	assert.ErrorContains(s.T(), err, "not cordoned")
+
+	// Verify scheduling state change events were emitted
+	require.Len(s.T(), events, 3)
+	assert.True(s.T(), events[0].Current.Cordoned)
+	assert.True(s.T(), events[1].Previous.Cordoned)
+	assert.True(s.T(), events[1].Current.Draining)
+	assert.True(s.T(), events[2].Previous.Draining)
+	assert.Equal(s.T(), models.NodeScheduling{UpdateTime: s.clock.Now().UTC()}, events[2].Current)
+}
+
 func (s *NodeManagerTestSuite) TestConnectionStateChangeEvents() {
 	var events []nodes.NodeConnectionEvent
 	eventsMu := sync.Mutex{}
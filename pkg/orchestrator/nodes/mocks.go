@@ -50,6 +50,20 @@ func (mr *MockManagerMockRecorder) ApproveNode(ctx, nodeID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveNode", reflect.TypeOf((*MockManager)(nil).ApproveNode), ctx, nodeID)
 }
 
+// CordonNode mocks base method.
+func (m *MockManager) CordonNode(ctx context.Context, nodeID, message string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CordonNode", ctx, nodeID, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CordonNode indicates an expected call of CordonNode.
+func (mr *MockManagerMockRecorder) CordonNode(ctx, nodeID, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CordonNode", reflect.TypeOf((*MockManager)(nil).CordonNode), ctx, nodeID, message)
+}
+
 // DeleteNode mocks base method.
 func (m *MockManager) DeleteNode(ctx context.Context, nodeID string) error {
 	m.ctrl.T.Helper()
@@ -64,6 +78,20 @@ func (mr *MockManagerMockRecorder) DeleteNode(ctx, nodeID interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNode", reflect.TypeOf((*MockManager)(nil).DeleteNode), ctx, nodeID)
 }
 
+// DrainNode mocks base method.
+func (m *MockManager) DrainNode(ctx context.Context, nodeID, message string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainNode", ctx, nodeID, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DrainNode indicates an expected call of DrainNode.
+func (mr *MockManagerMockRecorder) DrainNode(ctx, nodeID, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainNode", reflect.TypeOf((*MockManager)(nil).DrainNode), ctx, nodeID, message)
+}
+
 // Get mocks base method.
 func (m *MockManager) Get(ctx context.Context, nodeID string) (models.NodeState, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +198,18 @@ func (mr *MockManagerMockRecorder) OnConnectionStateChange(handler interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnConnectionStateChange", reflect.TypeOf((*MockManager)(nil).OnConnectionStateChange), handler)
 }
 
+// OnSchedulingStateChange mocks base method.
+func (m *MockManager) OnSchedulingStateChange(handler SchedulingStateChangeHandler) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnSchedulingStateChange", handler)
+}
+
+// OnSchedulingStateChange indicates an expected call of OnSchedulingStateChange.
+func (mr *MockManagerMockRecorder) OnSchedulingStateChange(handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnSchedulingStateChange", reflect.TypeOf((*MockManager)(nil).OnSchedulingStateChange), handler)
+}
+
 // RejectNode mocks base method.
 func (m *MockManager) RejectNode(ctx context.Context, nodeID string) error {
 	m.ctrl.T.Helper()
@@ -241,6 +281,20 @@ func (mr *MockManagerMockRecorder) Stop(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockManager)(nil).Stop), ctx)
 }
 
+// UncordonNode mocks base method.
+func (m *MockManager) UncordonNode(ctx context.Context, nodeID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UncordonNode", ctx, nodeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UncordonNode indicates an expected call of UncordonNode.
+func (mr *MockManagerMockRecorder) UncordonNode(ctx, nodeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UncordonNode", reflect.TypeOf((*MockManager)(nil).UncordonNode), ctx, nodeID)
+}
+
 // UpdateNodeInfo mocks base method.
 func (m *MockManager) UpdateNodeInfo(ctx context.Context, request messages.UpdateNodeInfoRequest) (messages.UpdateNodeInfoResponse, error) {
 	m.ctrl.T.Helper()
@@ -45,13 +45,17 @@ type ReEvaluatorParams struct {
 //   - When nodes join/change specs: enqueue evaluations for all daemon jobs (new node might fit)
 //     and for batch/service jobs that have executions on that node (compatibility check)
 //   - When nodes disappear: enqueue evaluations for all jobs that had active executions on that node
+//   - When nodes are drained: enqueue evaluations for all jobs that have active executions on that node,
+//     so that their service and daemon executions are moved to other nodes
+//   - When nodes are uncordoned: handled like nodes joining, as they can accept new executions again
 //
 // The component implements rate limiting to prevent evaluation floods during node churn.
 //
 // Usage:
 //   - Create a ReEvaluator instance
 //   - Start the ReEvaluator
-//   - Register the ReEvaluator.HandleNodeConnectionEvent with the NodeManager
+//   - Register the ReEvaluator.HandleNodeConnectionEvent and ReEvaluator.HandleNodeSchedulingEvent
+//     with the NodeManager
 //   - The ReEvaluator will automatically process events and trigger job evaluations
 type ReEvaluator struct {
 	jobStore     jobstore.Store
@@ -76,6 +80,7 @@ type evaluatorEventType int
 const (
 	evaluatorEventJoin evaluatorEventType = iota
 	evaluatorEventLeave
+	evaluatorEventDrain
 )
 
 // String returns the string representation of the node event type
@@ -85,6 +90,8 @@ func (t evaluatorEventType) String() string {
 		return "join"
 	case evaluatorEventLeave:
 		return "leave"
+	case evaluatorEventDrain:
+		return "drain"
 	default:
 		return "unknown"
 	}
@@ -214,9 +221,46 @@ func (re *ReEvaluator) HandleNodeConnectionEvent(event NodeConnectionEvent) {
 		Str("eventType", eventType.String()).
 		Msg("Processing node connection event")
 
-	// Submit event to channel (non-blocking)
+	re.submitEvent(event.NodeID, eventType)
+}
+
+// HandleNodeSchedulingEvent processes a node being cordoned, drained or uncordoned
+func (re *ReEvaluator) HandleNodeSchedulingEvent(event NodeSchedulingEvent) {
+	if !re.running {
+		return
+	}
+
+	select {
+	case <-re.stopCh:
+		return
+	default:
+	}
+
+	// Determine event type based on scheduling state change.
+	// Cordoning a node doesn't affect its existing executions and is ignored.
+	var eventType evaluatorEventType
+	if event.Current.Draining && !event.Previous.Draining {
+		eventType = evaluatorEventDrain
+	} else if !event.Current.Cordoned && (event.Previous.Cordoned || event.Previous.Draining) {
+		eventType = evaluatorEventJoin
+	} else {
+		return
+	}
+
+	log.Debug().
+		Str("nodeID", event.NodeID).
+		Str("previous", event.Previous.String()).
+		Str("current", event.Current.String()).
+		Str("eventType", eventType.String()).
+		Msg("Processing node scheduling event")
+
+	re.submitEvent(event.NodeID, eventType)
+}
+
+// submitEvent adds a node event to the event channel without blocking
+func (re *ReEvaluator) submitEvent(nodeID string, eventType evaluatorEventType) {
 	nodeEvent := evaluatorNodeEvent{
-		nodeID:    event.NodeID,
+		nodeID:    nodeID,
 		eventType: eventType,
 	}
 
@@ -226,7 +270,7 @@ func (re *ReEvaluator) HandleNodeConnectionEvent(event NodeConnectionEvent) {
 	default:
 		// Channel is full, log and continue
 		log.Warn().
-			Str("nodeID", event.NodeID).
+			Str("nodeID", nodeID).
 			Str("eventType", eventType.String()).
 			Msg("Event channel full, dropping node event")
 	}
@@ -304,6 +348,7 @@ func (re *ReEvaluator) processEventBatch(ctx context.Context, batch evaluatorEve
 	allNodeIDs := make([]string, 0, len(batch.nodeEvents))
 	var joinNodeIDs []string
 	var leaveNodeIDs []string
+	var drainNodeIDs []string
 
 	for nodeID, eventType := range batch.nodeEvents {
 		allNodeIDs = append(allNodeIDs, nodeID)
@@ -312,6 +357,8 @@ func (re *ReEvaluator) processEventBatch(ctx context.Context, batch evaluatorEve
 			joinNodeIDs = append(joinNodeIDs, nodeID)
 		case evaluatorEventLeave:
 			leaveNodeIDs = append(leaveNodeIDs, nodeID)
+		case evaluatorEventDrain:
+			drainNodeIDs = append(drainNodeIDs, nodeID)
 		}
 	}
 
@@ -346,12 +393,14 @@ func (re *ReEvaluator) processEventBatch(ctx context.Context, batch evaluatorEve
 
 	// Enqueue evaluations for all affected jobs
 	for jobID, jobType := range jobs {
-		// Use appropriate trigger based on whether there were joins or leaves
+		// Use appropriate trigger based on whether there were joins, leaves or drains
 		var triggerType string
 		if len(joinNodeIDs) > 0 {
 			triggerType = models.EvalTriggerNodeJoin
-		} else {
+		} else if len(leaveNodeIDs) > 0 {
 			triggerType = models.EvalTriggerNodeLeave
+		} else {
+			triggerType = models.EvalTriggerNodeDrain
 		}
 		re.enqueueEvaluationForJob(ctx, jobID, jobType, triggerType)
 	}
@@ -361,6 +410,7 @@ func (re *ReEvaluator) processEventBatch(ctx context.Context, batch evaluatorEve
 		Int("totalNodes", len(allNodeIDs)).
 		Strs("joinNodes", joinNodeIDs).
 		Strs("leaveNodes", leaveNodeIDs).
+		Strs("drainNodes", drainNodeIDs).
 		Msg("Processed node event batch")
 }
 
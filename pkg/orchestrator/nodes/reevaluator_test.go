@@ -144,6 +144,48 @@ func (s *ReEvaluatorTestSuite) TestNodeLeaveEvent() {
 	s.waitForCompletion()
 }
 
+func (s *ReEvaluatorTestSuite) TestNodeDrainEvent() {
+	s.setupReEvaluator()
+
+	nodeID := "test-node-1"
+
+	// Create test job
+	serviceJob := s.createTestJob(models.JobTypeService)
+
+	// Mock executions query for the draining node
+	execution := s.createTestExecution(serviceJob.ID, nodeID)
+	execution.Job = serviceJob
+	s.expectExecutionsQuery([]string{nodeID}, []models.Execution{*execution})
+
+	// Expect evaluation to be created
+	s.expectEvaluationCreated(serviceJob, models.EvalTriggerNodeDrain)
+
+	// Trigger node drain event and wait for processing
+	s.reEvaluator.HandleNodeSchedulingEvent(NodeSchedulingEvent{
+		NodeID:    nodeID,
+		Previous:  models.NodeScheduling{Cordoned: true},
+		Current:   models.NodeScheduling{Cordoned: true, Draining: true},
+		Timestamp: s.clock.Now(),
+	})
+	s.triggerProcessing()
+
+	// Wait for processing to complete
+	s.waitForCompletion()
+}
+
+func (s *ReEvaluatorTestSuite) TestIgnoreCordonEvent() {
+	s.setupReEvaluator()
+
+	// Cordoning a node doesn't affect its executions
+	s.reEvaluator.HandleNodeSchedulingEvent(NodeSchedulingEvent{
+		NodeID:    "test-node",
+		Current:   models.NodeScheduling{Cordoned: true},
+		Timestamp: s.clock.Now(),
+	})
+	// Trigger processing - should not create any evaluations
+	s.triggerProcessing()
+}
+
 func (s *ReEvaluatorTestSuite) TestBatchingSameEventType() {
 	s.setupReEvaluator()
 
@@ -8,6 +8,7 @@
 //
 // Key features:
 //   - Node lifecycle management (registration, approval/rejection, deletion)
+//   - Node maintenance (cordoning and draining)
 //   - Health monitoring via heartbeats
 //   - Connection state tracking
 //   - Resource capacity tracking
@@ -84,9 +85,26 @@ type Manager interface {
 	// Returns error if node is not found.
 	DeleteNode(ctx context.Context, nodeID string) error
 
+	// CordonNode stops new executions from being placed on a node,
+	// without affecting the executions already running on it.
+	// Returns error if node is already cordoned or not found.
+	CordonNode(ctx context.Context, nodeID string, message string) error
+
+	// DrainNode cordons a node and moves its service and daemon executions to other nodes,
+	// while letting its batch executions complete.
+	// Returns error if node is already draining or not found.
+	DrainNode(ctx context.Context, nodeID string, message string) error
+
+	// UncordonNode allows new executions to be placed on a cordoned or draining node again.
+	// Returns error if node is not cordoned or not found.
+	UncordonNode(ctx context.Context, nodeID string) error
+
 	// OnConnectionStateChange registers a handler for node connection state changes.
 	OnConnectionStateChange(handler ConnectionStateChangeHandler)
 
+	// OnSchedulingStateChange registers a handler for nodes being cordoned, drained or uncordoned.
+	OnSchedulingStateChange(handler SchedulingStateChangeHandler)
+
 	Lookup
 
 	Tracker
@@ -127,6 +145,16 @@ func HealthyNodeFilter(state models.NodeState) bool {
 	return state.ConnectionState.Status == models.NodeStates.CONNECTED
 }
 
+// SchedulableNodeFilter is a filter that returns only nodes that can be selected for new executions.
+func SchedulableNodeFilter(state models.NodeState) bool {
+	return !state.IsCordoned()
+}
+
+// DrainingNodeFilter is a filter that returns only nodes that are being drained.
+func DrainingNodeFilter(state models.NodeState) bool {
+	return state.IsDraining()
+}
+
 // NodeConnectionEvent represents a change in a node's connection state.
 type NodeConnectionEvent struct {
 	// NodeID is the identifier of the node whose state changed
@@ -145,6 +173,24 @@ type NodeConnectionEvent struct {
 // ConnectionStateChangeHandler defines a function type for handling connection state changes.
 type ConnectionStateChangeHandler func(NodeConnectionEvent)
 
+// NodeSchedulingEvent represents a node being cordoned, drained or uncordoned.
+type NodeSchedulingEvent struct {
+	// NodeID is the identifier of the node whose scheduling state changed
+	NodeID string
+
+	// Previous is the previous scheduling state
+	Previous models.NodeScheduling
+
+	// Current is the new scheduling state
+	Current models.NodeScheduling
+
+	// Timestamp is when the state change occurred
+	Timestamp time.Time
+}
+
+// SchedulingStateChangeHandler defines a function type for handling scheduling state changes.
+type SchedulingStateChangeHandler func(NodeSchedulingEvent)
+
 // ExtendedHeartbeatRequest represents a heartbeat message with additional metadata.
 type ExtendedHeartbeatRequest struct {
 	messages.HeartbeatRequest
@@ -135,6 +135,15 @@ func (s *StateUpdater) processJobState(
 			return err
 		}
 		metrics.Latency(ctx, processPartDuration, AttrOperationPartUpdateJob)
+
+		// wake up jobs waiting on this job to finish
+		if plan.DesiredJobState.IsTerminal() {
+			evals, err := orchestrator.NewDependentJobEvaluations(txContext, s.store, *plan.Job)
+			if err != nil {
+				return err
+			}
+			plan.NewEvaluations = append(plan.NewEvaluations, evals...)
+		}
 	}
 	return nil
 }
@@ -167,7 +176,12 @@ func (s *StateUpdater) processEvents(
 
 	if len(plan.ExecutionEvents) > 0 {
 		for executionID, events := range plan.ExecutionEvents {
-			if err := s.store.AddExecutionHistory(txContext, plan.Job.ID, plan.Job.Version, executionID, events...); err != nil {
+			jobID, jobVersion := plan.Job.ID, plan.Job.Version
+			// executions of other jobs, such as preempted ones, are recorded in the history of their own job
+			if u, ok := plan.UpdatedExecutions[executionID]; ok && u.Execution.JobID != plan.Job.ID {
+				jobID, jobVersion = u.Execution.JobID, u.Execution.JobVersion
+			}
+			if err := s.store.AddExecutionHistory(txContext, jobID, jobVersion, executionID, events...); err != nil {
 				return err
 			}
 		}
@@ -75,6 +75,29 @@ func (suite *StateUpdaterSuite) TestStateUpdater_Process_UpdateJobState_Success(
 
 	suite.mockStore.EXPECT().BeginTx(suite.ctx).Return(suite.mockTxContext, nil).Times(1)
 	suite.mockStore.EXPECT().UpdateJobState(suite.mockTxContext, NewUpdateJobMatcherFromPlanUpdate(suite.T(), plan)).Times(1)
+	suite.mockStore.EXPECT().GetDependentJobs(suite.mockTxContext, plan.Job.ID).Return(nil, nil).Times(1)
+	suite.mockTxContext.EXPECT().Rollback() // always rollback in defer
+	suite.mockTxContext.EXPECT().Commit()
+	suite.NoError(suite.stateUpdater.Process(suite.ctx, plan))
+}
+
+func (suite *StateUpdaterSuite) TestStateUpdater_Process_UpdateJobState_EvaluatesDependents() {
+	plan := mock.Plan()
+	plan.DesiredJobState = models.JobStateTypeCompleted
+
+	dependent := mock.Job()
+	dependent.DependsOn = []string{plan.Job.ID}
+
+	suite.mockStore.EXPECT().BeginTx(suite.ctx).Return(suite.mockTxContext, nil).Times(1)
+	suite.mockStore.EXPECT().UpdateJobState(suite.mockTxContext, NewUpdateJobMatcherFromPlanUpdate(suite.T(), plan)).Times(1)
+	suite.mockStore.EXPECT().GetDependentJobs(suite.mockTxContext, plan.Job.ID).
+		Return([]models.Job{*dependent}, nil).Times(1)
+	suite.mockStore.EXPECT().CreateEvaluation(suite.mockTxContext, gomock.Any()).
+		DoAndReturn(func(_ context.Context, eval models.Evaluation) error {
+			suite.Equal(dependent.ID, eval.JobID)
+			suite.Equal(models.EvalTriggerJobDependency, eval.TriggeredBy)
+			return nil
+		}).Times(1)
 	suite.mockTxContext.EXPECT().Rollback() // always rollback in defer
 	suite.mockTxContext.EXPECT().Commit()
 	suite.NoError(suite.stateUpdater.Process(suite.ctx, plan))
@@ -133,6 +156,7 @@ func (suite *StateUpdaterSuite) TestStateUpdater_Process_MultiOp() {
 	suite.mockStore.EXPECT().UpdateExecution(suite.mockTxContext, NewUpdateExecutionMatcherFromPlanUpdate(suite.T(), update1)).Times(1)
 	suite.mockStore.EXPECT().UpdateExecution(suite.mockTxContext, NewUpdateExecutionMatcherFromPlanUpdate(suite.T(), update2)).Times(1)
 	suite.mockStore.EXPECT().UpdateJobState(suite.mockTxContext, NewUpdateJobMatcherFromPlanUpdate(suite.T(), plan)).Times(1)
+	suite.mockStore.EXPECT().GetDependentJobs(suite.mockTxContext, plan.Job.ID).Return(nil, nil).Times(1)
 	suite.mockStore.EXPECT().CreateEvaluation(suite.mockTxContext, *evaluation1).Times(1)
 	suite.mockStore.EXPECT().CreateEvaluation(suite.mockTxContext, *evaluation2).Times(1)
 	suite.mockTxContext.EXPECT().Rollback() // always rollback in defer
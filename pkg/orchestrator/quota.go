@@ -0,0 +1,197 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const QuotaComponent = "Quota"
+
+// QuotaEnforcer enforces the per-namespace quotas stored in the job store.
+// Jobs are rejected at submission if they could never run within their namespace's quota,
+// or if the namespace has too many queued jobs, and are kept queued by the scheduler
+// while starting them would exceed the quota.
+//
+// The scheduler checks the quota of a namespace and creates the executions of a job under the lock
+// of the namespace, so that concurrent evaluations don't start executions within the same remaining
+// quota. The limit of queued jobs is checked at submission without a lock, and is best-effort:
+// concurrent submissions can exceed it.
+type QuotaEnforcer struct {
+	store jobstore.Store
+
+	mu    sync.Mutex
+	locks map[string]*namespaceLock
+}
+
+// namespaceLock serializes the scheduling of the jobs of a namespace
+type namespaceLock struct {
+	sync.Mutex
+	// refs is the number of evaluations holding or waiting for the lock
+	refs int
+}
+
+// NewQuotaEnforcer creates a new quota enforcer backed by the given job store
+func NewQuotaEnforcer(store jobstore.Store) *QuotaEnforcer {
+	return &QuotaEnforcer{
+		store: store,
+		locks: make(map[string]*namespaceLock),
+	}
+}
+
+// Quota returns the quota of the namespace, or nil if the namespace has no quota
+func (q *QuotaEnforcer) Quota(ctx context.Context, namespace string) (*models.NamespaceQuota, error) {
+	quota, err := q.store.GetNamespaceQuota(ctx, namespace)
+	if err != nil {
+		if bacerrors.IsErrorWithCode(err, bacerrors.NotFoundError) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to retrieve quota of namespace %s: %w", namespace, err)
+	}
+	return &quota, nil
+}
+
+// Usage returns the current usage of the namespace counted against its quota
+func (q *QuotaEnforcer) Usage(ctx context.Context, namespace string) (models.NamespaceUsage, error) {
+	usage := models.NamespaceUsage{}
+	executions, err := q.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+		Namespace:      namespace,
+		InProgressOnly: true,
+		AllJobVersions: true,
+		IncludeJob:     true,
+	})
+	if err != nil {
+		return usage, fmt.Errorf("failed to retrieve active executions of namespace %s: %w", namespace, err)
+	}
+	for i := range executions {
+		usage.Executions++
+		resources, err := ExecutionResources(&executions[i])
+		if err != nil {
+			return usage, err
+		}
+		usage.Resources = *usage.Resources.Add(*resources)
+	}
+
+	jobs, err := q.store.GetInProgressJobsInNamespace(ctx, namespace)
+	if err != nil {
+		return usage, fmt.Errorf("failed to retrieve in progress jobs of namespace %s: %w", namespace, err)
+	}
+	for _, job := range jobs {
+		if isWaitingToBeScheduled(job) {
+			usage.QueuedJobs++
+		}
+	}
+	return usage, nil
+}
+
+// Lock serializes the scheduling of the job with the other jobs of its namespace if their executions
+// are limited by the namespace's quota, and returns the function releasing the lock.
+func (q *QuotaEnforcer) Lock(ctx context.Context, job *models.Job) (func(), error) {
+	if !isQuotaLimited(job) {
+		return func() {}, nil
+	}
+	quota, err := q.Quota(ctx, job.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if quota == nil || quota.IsUnlimited() {
+		return func() {}, nil
+	}
+
+	q.mu.Lock()
+	lock, ok := q.locks[job.Namespace]
+	if !ok {
+		lock = &namespaceLock{}
+		q.locks[job.Namespace] = lock
+	}
+	lock.refs++
+	q.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(q.locks, job.Namespace)
+		}
+	}, nil
+}
+
+// ExecutionResources returns the resources allocated to the execution by its compute node,
+// or the resources requested by its job if the execution was not allocated resources yet.
+func ExecutionResources(execution *models.Execution) (*models.Resources, error) {
+	if allocated := execution.AllocatedResources.Total(); allocated != nil && !allocated.IsZero() {
+		return allocated, nil
+	}
+	if execution.Job == nil {
+		return &models.Resources{}, nil
+	}
+	resources, err := execution.Job.Task().ResourcesConfig.ToResources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert resources of job %s: %w", execution.JobID, err)
+	}
+	return resources, nil
+}
+
+// CheckSubmission returns an error if the job can't be accepted in its namespace, because it
+// requires more executions or resources than its namespace's quota allows, or because the
+// namespace has reached its limit of queued jobs.
+func (q *QuotaEnforcer) CheckSubmission(ctx context.Context, job *models.Job) error {
+	if !isQuotaLimited(job) {
+		return nil
+	}
+	quota, err := q.Quota(ctx, job.Namespace)
+	if err != nil || quota == nil || quota.IsUnlimited() {
+		return err
+	}
+
+	resources, err := job.Task().ResourcesConfig.ToResources()
+	if err != nil {
+		return fmt.Errorf("failed to convert job resources config to resources: %w", err)
+	}
+	if reason := quota.CheckExecutions(models.NamespaceUsage{}, job.Count, *resources); reason != "" {
+		return NewErrQuotaExceeded(job.Namespace, reason).
+			WithHint("Reduce the job's count or resources, or ask an administrator to raise the namespace quota")
+	}
+
+	if quota.MaxQueuedJobs > 0 {
+		usage, err := q.Usage(ctx, job.Namespace)
+		if err != nil {
+			return err
+		}
+		// an update of a job waiting to be scheduled doesn't queue another job
+		if existing, err := q.store.GetJob(ctx, job.ID); err == nil && isWaitingToBeScheduled(existing) {
+			usage.QueuedJobs--
+		}
+		if reason := quota.CheckQueue(usage); reason != "" {
+			return NewErrQuotaExceeded(job.Namespace, reason).
+				WithHint("Wait for queued jobs to be scheduled, or stop some of them")
+		}
+	}
+	return nil
+}
+
+// NewErrQuotaExceeded returns an error for a job rejected because of its namespace's quota
+func NewErrQuotaExceeded(namespace, reason string) bacerrors.Error {
+	return bacerrors.Newf("job exceeds the quota of namespace %s: %s", namespace, reason).
+		WithCode(bacerrors.ResourceExhausted).
+		WithComponent(QuotaComponent)
+}
+
+// isQuotaLimited returns true if the job's executions are limited by quotas.
+// Daemon and ops jobs run on every matching node and are not limited.
+func isQuotaLimited(job *models.Job) bool {
+	return job.Type == models.JobTypeBatch || job.Type == models.JobTypeService
+}
+
+// isWaitingToBeScheduled returns true if the job is counted as queued against its namespace's quota
+func isWaitingToBeScheduled(job models.Job) bool {
+	return isQuotaLimited(&job) &&
+		(job.State.StateType == models.JobStateTypePending || job.State.StateType == models.JobStateTypeQueued)
+}
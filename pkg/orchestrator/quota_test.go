@@ -0,0 +1,155 @@
+//go:build unit || !integration
+
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type QuotaEnforcerTestSuite struct {
+	suite.Suite
+	ctx          context.Context
+	mockJobStore *jobstore.MockStore
+	enforcer     *QuotaEnforcer
+}
+
+func (s *QuotaEnforcerTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockJobStore = jobstore.NewMockStore(gomock.NewController(s.T()))
+	s.enforcer = NewQuotaEnforcer(s.mockJobStore)
+}
+
+func TestQuotaEnforcerTestSuite(t *testing.T) {
+	suite.Run(t, new(QuotaEnforcerTestSuite))
+}
+
+func (s *QuotaEnforcerTestSuite) newJob(count int, cpu string) *models.Job {
+	job := mock.Job()
+	job.Count = count
+	job.Task().ResourcesConfig = &models.ResourcesConfig{CPU: cpu}
+	return job
+}
+
+func (s *QuotaEnforcerTestSuite) mockQuota(quota models.NamespaceQuota) {
+	quota.Namespace = models.DefaultNamespace
+	s.mockJobStore.EXPECT().GetNamespaceQuota(s.ctx, models.DefaultNamespace).Return(quota, nil)
+}
+
+func (s *QuotaEnforcerTestSuite) TestCheckSubmission_NoQuota() {
+	s.mockJobStore.EXPECT().GetNamespaceQuota(s.ctx, models.DefaultNamespace).
+		Return(models.NamespaceQuota{}, jobstore.NewErrNamespaceQuotaNotFound(models.DefaultNamespace))
+	s.NoError(s.enforcer.CheckSubmission(s.ctx, s.newJob(100, "100")))
+}
+
+func (s *QuotaEnforcerTestSuite) TestCheckSubmission_NotLimitedJobType() {
+	job := s.newJob(1, "100")
+	job.Type = models.JobTypeDaemon
+	s.NoError(s.enforcer.CheckSubmission(s.ctx, job))
+}
+
+func (s *QuotaEnforcerTestSuite) TestCheckSubmission_RejectsJobThatCanNeverRun() {
+	s.mockQuota(models.NamespaceQuota{MaxConcurrentExecutions: 2})
+	err := s.enforcer.CheckSubmission(s.ctx, s.newJob(3, "1"))
+	s.Require().Error(err)
+	s.True(bacerrors.IsErrorWithCode(err, bacerrors.ResourceExhausted))
+	s.ErrorContains(err, "limit of 2 concurrent executions")
+
+	s.mockQuota(models.NamespaceQuota{MaxResources: models.Resources{CPU: 4}})
+	err = s.enforcer.CheckSubmission(s.ctx, s.newJob(2, "3"))
+	s.Require().Error(err)
+	s.ErrorContains(err, "CPU limits")
+}
+
+func (s *QuotaEnforcerTestSuite) TestCheckSubmission_AcceptsJobWithinLimits() {
+	// the job is accepted even if the namespace's executions are currently using all its quota
+	s.mockQuota(models.NamespaceQuota{MaxConcurrentExecutions: 2, MaxResources: models.Resources{CPU: 4}})
+	s.NoError(s.enforcer.CheckSubmission(s.ctx, s.newJob(2, "2")))
+}
+
+func (s *QuotaEnforcerTestSuite) TestCheckSubmission_MaxQueuedJobs() {
+	queued := mock.Job()
+	queued.State = models.NewJobState(models.JobStateTypeQueued)
+	running := mock.Job()
+	running.State = models.NewJobState(models.JobStateTypeRunning)
+
+	mockUsage := func() {
+		s.mockJobStore.EXPECT().GetExecutions(s.ctx, gomock.Any()).Return(nil, nil)
+		s.mockJobStore.EXPECT().GetInProgressJobsInNamespace(s.ctx, models.DefaultNamespace).
+			Return([]models.Job{*queued, *running}, nil)
+	}
+
+	// a new job is rejected once the namespace reached its limit of queued jobs
+	job := s.newJob(1, "1")
+	s.mockQuota(models.NamespaceQuota{MaxQueuedJobs: 1})
+	mockUsage()
+	s.mockJobStore.EXPECT().GetJob(s.ctx, job.ID).Return(models.Job{}, jobstore.NewErrJobNotFound(job.ID))
+	err := s.enforcer.CheckSubmission(s.ctx, job)
+	s.Require().Error(err)
+	s.True(bacerrors.IsErrorWithCode(err, bacerrors.ResourceExhausted))
+	s.ErrorContains(err, "1 queued jobs out of 1 allowed")
+
+	// but updating the queued job doesn't queue another job
+	s.mockQuota(models.NamespaceQuota{MaxQueuedJobs: 1})
+	mockUsage()
+	s.mockJobStore.EXPECT().GetJob(s.ctx, queued.ID).Return(*queued, nil)
+	s.NoError(s.enforcer.CheckSubmission(s.ctx, queued))
+}
+
+func (s *QuotaEnforcerTestSuite) TestLock_SerializesNamespaceWithQuota() {
+	s.mockJobStore.EXPECT().GetNamespaceQuota(s.ctx, models.DefaultNamespace).
+		Return(models.NamespaceQuota{Namespace: models.DefaultNamespace, MaxConcurrentExecutions: 1}, nil).Times(2)
+	unlock, err := s.enforcer.Lock(s.ctx, mock.Job())
+	s.Require().NoError(err)
+
+	locked := make(chan struct{})
+	go func() {
+		unlockOther, err := s.enforcer.Lock(s.ctx, mock.Job())
+		s.NoError(err)
+		close(locked)
+		unlockOther()
+	}()
+	select {
+	case <-locked:
+		s.Fail("the namespace was locked twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	s.Eventually(func() bool {
+		select {
+		case <-locked:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+	s.Eventually(func() bool {
+		s.enforcer.mu.Lock()
+		defer s.enforcer.mu.Unlock()
+		return len(s.enforcer.locks) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *QuotaEnforcerTestSuite) TestLock_NoQuota() {
+	s.mockJobStore.EXPECT().GetNamespaceQuota(s.ctx, models.DefaultNamespace).
+		Return(models.NamespaceQuota{}, jobstore.NewErrNamespaceQuotaNotFound(models.DefaultNamespace)).Times(2)
+	unlock, err := s.enforcer.Lock(s.ctx, mock.Job())
+	s.Require().NoError(err)
+	defer unlock()
+
+	// namespaces without quota are not locked
+	unlockOther, err := s.enforcer.Lock(s.ctx, mock.Job())
+	s.Require().NoError(err)
+	unlockOther()
+	s.Empty(s.enforcer.locks)
+}
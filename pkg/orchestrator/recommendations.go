@@ -0,0 +1,82 @@
+package orchestrator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const (
+	// recommendationHeadroom is the margin added to the peak usage of a job when recommending its resources
+	recommendationHeadroom = 1.2
+	// overAllocationFactor is how many times above the peak usage of a job its requested resources
+	// have to be for the job to be considered over-allocated
+	overAllocationFactor = 3
+	// minRecommendedCPU is the lowest CPU recommended, as jobs using less than that are not worth tuning
+	minRecommendedCPU = 0.1
+	// recommendedBytesUnit is the unit memory and disk recommendations are rounded up to, a megabyte
+	recommendedBytesUnit = 1_000_000
+)
+
+// RecommendResources recommends the resources a job should request from the peak usage reported by
+// its executions, and warns about the resources it requests far above them.
+func RecommendResources(job models.Job, executions []models.Execution) (*models.ResourceRecommendation, error) {
+	requested, err := job.Task().ResourcesConfig.ToResources()
+	if err != nil {
+		return nil, err
+	}
+	recommendation := &models.ResourceRecommendation{
+		JobID:     job.ID,
+		Requested: requested,
+		Usage:     jobstore.AggregateResourceUsage(executions),
+	}
+	if len(recommendation.Usage) == 0 {
+		return recommendation, nil
+	}
+
+	var peak models.JobResourceUsage
+	for _, usage := range recommendation.Usage {
+		peak.PeakCPU = max(peak.PeakCPU, usage.PeakCPU)
+		peak.PeakMemory = max(peak.PeakMemory, usage.PeakMemory)
+		peak.PeakDisk = max(peak.PeakDisk, usage.PeakDisk)
+	}
+
+	recommended := requested.Copy()
+	if peak.PeakCPU > 0 {
+		recommended.CPU = max(minRecommendedCPU, math.Ceil(peak.PeakCPU*recommendationHeadroom*10)/10) //nolint:mnd
+	}
+	if peak.PeakMemory > 0 {
+		recommended.Memory = roundUpBytes(peak.PeakMemory)
+	}
+	if peak.PeakDisk > 0 {
+		recommended.Disk = roundUpBytes(peak.PeakDisk)
+	}
+	recommendation.Recommended = recommended
+
+	if requested.CPU > overAllocationFactor*peak.PeakCPU && peak.PeakCPU > 0 {
+		recommendation.Warnings = append(recommendation.Warnings, fmt.Sprintf(
+			"requested %.2f CPU, but past executions of the job used at most %.2f. Consider requesting %.1f CPU",
+			requested.CPU, peak.PeakCPU, recommended.CPU))
+	}
+	if requested.Memory > overAllocationFactor*peak.PeakMemory && peak.PeakMemory > 0 {
+		recommendation.Warnings = append(recommendation.Warnings, fmt.Sprintf(
+			"requested %s of memory, but past executions of the job used at most %s. Consider requesting %s",
+			humanize.Bytes(requested.Memory), humanize.Bytes(peak.PeakMemory), humanize.Bytes(recommended.Memory)))
+	}
+	if requested.Disk > overAllocationFactor*peak.PeakDisk && peak.PeakDisk > 0 {
+		recommendation.Warnings = append(recommendation.Warnings, fmt.Sprintf(
+			"requested %s of disk, but past executions of the job wrote at most %s. Consider requesting %s",
+			humanize.Bytes(requested.Disk), humanize.Bytes(peak.PeakDisk), humanize.Bytes(recommended.Disk)))
+	}
+	return recommendation, nil
+}
+
+// roundUpBytes adds headroom to a peak number of bytes, rounded up to the unit of recommendations
+func roundUpBytes(peak uint64) uint64 {
+	withHeadroom := uint64(math.Ceil(float64(peak) * recommendationHeadroom))
+	return (withHeadroom + recommendedBytesUnit - 1) / recommendedBytesUnit * recommendedBytesUnit
+}
@@ -0,0 +1,72 @@
+//go:build unit || !integration
+
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type RecommendationsTestSuite struct {
+	suite.Suite
+	job models.Job
+}
+
+func TestRecommendationsTestSuite(t *testing.T) {
+	suite.Run(t, new(RecommendationsTestSuite))
+}
+
+func (s *RecommendationsTestSuite) SetupTest() {
+	s.job = *mock.Job()
+	s.job.Task().ResourcesConfig = &models.ResourcesConfig{CPU: "2", Memory: "1gb", Disk: "1gb"}
+}
+
+func (s *RecommendationsTestSuite) TestNoUsage() {
+	recommendation, err := RecommendResources(s.job, []models.Execution{{JobVersion: 1}})
+	s.Require().NoError(err)
+	s.Equal(s.job.ID, recommendation.JobID)
+	s.Equal(2.0, recommendation.Requested.CPU)
+	s.Nil(recommendation.Recommended)
+	s.Empty(recommendation.Usage)
+	s.Empty(recommendation.Warnings)
+}
+
+func (s *RecommendationsTestSuite) TestAggregatesUsagePerVersion() {
+	recommendation, err := RecommendResources(s.job, []models.Execution{
+		{JobVersion: 2, ResourceUsage: &models.ResourceUsage{CPUSeconds: 15, WallSeconds: 10, PeakMemory: 300 << 20}},
+		{JobVersion: 1, ResourceUsage: &models.ResourceUsage{CPUSeconds: 10, WallSeconds: 10, PeakMemory: 500 << 20, Disk: 10 << 20}},
+		{JobVersion: 1, ResourceUsage: &models.ResourceUsage{CPUSeconds: 5, WallSeconds: 10, PeakMemory: 400 << 20}},
+		{JobVersion: 2},
+	})
+	s.Require().NoError(err)
+	s.Equal([]models.JobResourceUsage{
+		{JobVersion: 1, Executions: 2, PeakCPU: 1, PeakMemory: 500 << 20, PeakDisk: 10 << 20, TotalCPUSeconds: 15},
+		{JobVersion: 2, Executions: 1, PeakCPU: 1.5, PeakMemory: 300 << 20, TotalCPUSeconds: 15},
+	}, recommendation.Usage)
+
+	// peaks across all versions, with headroom
+	s.Require().NotNil(recommendation.Recommended)
+	s.InDelta(1.8, recommendation.Recommended.CPU, 0.001)
+	s.Equal(uint64(630_000_000), recommendation.Recommended.Memory)
+	s.Equal(uint64(13_000_000), recommendation.Recommended.Disk)
+
+	// only the disk is requested far above its peak
+	s.Require().Len(recommendation.Warnings, 1)
+	s.Contains(recommendation.Warnings[0], "requested 1.0 GB of disk, but past executions of the job wrote at most 10 MB")
+}
+
+func (s *RecommendationsTestSuite) TestMinimumCPU() {
+	recommendation, err := RecommendResources(s.job, []models.Execution{
+		{JobVersion: 1, ResourceUsage: &models.ResourceUsage{CPUSeconds: 0.01, WallSeconds: 10}},
+	})
+	s.Require().NoError(err)
+	s.Equal(minRecommendedCPU, recommendation.Recommended.CPU)
+	// resources that were not measured are left as requested
+	s.Equal(recommendation.Requested.Memory, recommendation.Recommended.Memory)
+	s.Require().Len(recommendation.Warnings, 1)
+	s.Contains(recommendation.Warnings[0], "Consider requesting 0.1 CPU")
+}
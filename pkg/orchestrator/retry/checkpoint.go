@@ -0,0 +1,42 @@
+package retry
+
+import (
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// LatestCheckpoint returns the execution that published the most recent checkpoint among
+// the given executions, or nil if none of them published a checkpoint.
+func LatestCheckpoint(executions []*models.Execution) *models.Execution {
+	var latest *models.Execution
+	for _, execution := range executions {
+		if execution.Checkpoint == nil || execution.Checkpoint.Result.IsEmpty() {
+			continue
+		}
+		if latest == nil || execution.Checkpoint.CreateTime > latest.Checkpoint.CreateTime {
+			latest = execution
+		}
+	}
+	return latest
+}
+
+// ResumeFromCheckpoint returns a copy of the job whose task restores the last checkpoint
+// of the given execution as an extra input source, so that the execution retrying it can
+// resume from the checkpoint. Any checkpoint restored by the job is replaced.
+func ResumeFromCheckpoint(job *models.Job, execution *models.Execution) *models.Job {
+	if job.Task() == nil || execution.Checkpoint == nil {
+		return job
+	}
+	// copy the task to avoid mutating the job spec shared with other executions
+	task := job.Task().Copy()
+	inputs := make([]*models.InputSource, 0, len(task.InputSources)+1)
+	for _, input := range task.InputSources {
+		if input.Alias != models.ResumeCheckpointAlias || input.Target != models.ResumeCheckpointPath {
+			inputs = append(inputs, input)
+		}
+	}
+	task.InputSources = append(inputs, execution.Checkpoint.InputSource())
+
+	resumed := *job
+	resumed.Tasks = append([]*models.Task{task}, job.Tasks[1:]...)
+	return &resumed
+}
@@ -56,9 +56,16 @@ func (s *BaseTestSuite) mockAllNodes(nodeIDs ...string) []models.NodeInfo {
 		nodeInfos[i] = fakeNodeInfo(s.T(), nodeID)
 	}
 	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	// no nodes are draining, unless mockDrainingNodes was called before
+	s.nodeSelector.EXPECT().DrainingNodes(gomock.Any()).Return(nil, nil).MaxTimes(1)
 	return nodeInfos
 }
 
+// mockDrainingNodes mocks the nodes being drained. It must be called before mockAllNodes.
+func (s *BaseTestSuite) mockDrainingNodes(nodeIDs ...string) {
+	s.nodeSelector.EXPECT().DrainingNodes(gomock.Any()).Return(nodeIDs, nil)
+}
+
 func (s *BaseTestSuite) mockMatchingNodes(scenario *Scenario, nodeIDs ...string) []orchestrator.NodeRank {
 	nodeRanks := make([]orchestrator.NodeRank, len(nodeIDs))
 	for i, nodeID := range nodeIDs {
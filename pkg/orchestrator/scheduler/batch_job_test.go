@@ -4,14 +4,18 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
 
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/retry"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
 )
 
 type BatchJobSchedulerTestSuite struct {
@@ -45,6 +49,234 @@ func (s *BatchJobSchedulerTestSuite) TestProcess_AlreadyEnoughExecutions() {
 	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
 }
 
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldWaitForDependencies() {
+	upstream := mock.Job()
+	upstream.State = models.NewJobState(models.JobStateTypeRunning)
+	scenario := NewScenario(
+		WithJobState(models.JobStateTypePending),
+		WithDependsOn(upstream.ID),
+	)
+	s.mockJobStore(scenario)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), upstream.ID).Return(*upstream, nil)
+
+	// empty plan, and no attempt to match nodes
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: scenario.evaluation,
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldWaitForSchedule() {
+	scenario := NewScenario(
+		WithJobState(models.JobStateTypePending),
+		WithSchedule("@hourly", time.Time{}),
+		WithExecution("node1", models.ExecutionStateBidAccepted),
+	)
+	s.mockJobStore(scenario)
+
+	// executions of a previous run are stopped, and no attempt to match nodes
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: scenario.evaluation,
+		UpdatedExecutions: []ExecutionStateUpdate{
+			{
+				ExecutionID:  scenario.executions[0].ID,
+				DesiredState: models.ExecutionDesiredStateStopped,
+				ComputeState: models.ExecutionStateCancelled,
+			},
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldRunScheduledRun() {
+	scenario := NewScenario(
+		WithJobState(models.JobStateTypePending),
+		WithCount(1),
+		WithSchedule("@hourly", time.Now().Add(-time.Minute)),
+	)
+	s.mockJobStore(scenario)
+	s.nodeSelector.EXPECT().MatchingNodes(gomock.Any(), gomock.Any()).Return(
+		[]orchestrator.NodeRank{*fakeNodeRank(s.T(), "node0")}, []orchestrator.NodeRank{}, nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: scenario.evaluation,
+		NewExecutions: []*models.Execution{
+			{NodeID: "node0", PartitionIndex: 0},
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldFailWhenDependencyFails() {
+	upstream := mock.Job()
+	upstream.State = models.NewJobState(models.JobStateTypeFailed)
+	scenario := NewScenario(
+		WithJobState(models.JobStateTypePending),
+		WithDependsOn(upstream.ID),
+	)
+	s.mockJobStore(scenario)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), upstream.ID).Return(*upstream, nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: scenario.evaluation,
+		JobState:   models.JobStateTypeFailed,
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldWireUpstreamResults() {
+	upstream := mock.Job()
+	upstream.State = models.NewJobState(models.JobStateTypeCompleted)
+	upstreamExecution := mock.ExecutionForJob(upstream)
+	upstreamExecution.ComputeState = models.NewExecutionState(models.ExecutionStateCompleted)
+	upstreamExecution.PublishedResult = &models.SpecConfig{
+		Type:   models.StorageSourceURL,
+		Params: map[string]interface{}{"URL": "http://example.com/results.tar.gz"},
+	}
+
+	scenario := NewScenario(
+		WithJobState(models.JobStateTypePending),
+		WithDependsOn(upstream.ID),
+		WithCount(1),
+	)
+	s.mockJobStore(scenario)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), upstream.ID).Return(*upstream, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{
+		JobID:      upstream.ID,
+		JobVersion: upstream.Version,
+	}).Return([]models.Execution{*upstreamExecution}, nil)
+	s.nodeSelector.EXPECT().MatchingNodes(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, job *models.Job) ([]orchestrator.NodeRank, []orchestrator.NodeRank, error) {
+			inputs := job.Task().InputSources
+			s.Require().NotEmpty(inputs)
+			wired := inputs[len(inputs)-1]
+			s.Equal(upstreamExecution.PublishedResult, wired.Source)
+			s.Equal("/inputs/"+upstream.Name, wired.Target)
+			return []orchestrator.NodeRank{*fakeNodeRank(s.T(), "node0")}, []orchestrator.NodeRank{}, nil
+		})
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: scenario.evaluation,
+		NewExecutions: []*models.Execution{
+			{NodeID: "node0", PartitionIndex: 0},
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+	s.Empty(scenario.job.Task().InputSources, "stored job spec should not be mutated")
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldWirePartitionedUpstreamResults() {
+	upstream := mock.Job()
+	upstream.State = models.NewJobState(models.JobStateTypeCompleted)
+	var upstreamExecutions []models.Execution
+	for partition := 0; partition < 2; partition++ {
+		execution := mock.ExecutionForJob(upstream)
+		execution.PartitionIndex = partition
+		execution.ComputeState = models.NewExecutionState(models.ExecutionStateCompleted)
+		execution.PublishedResult = &models.SpecConfig{
+			Type:   models.StorageSourceURL,
+			Params: map[string]interface{}{"URL": fmt.Sprintf("http://example.com/results-%d.tar.gz", partition)},
+		}
+		upstreamExecutions = append(upstreamExecutions, *execution)
+	}
+
+	scenario := NewScenario(
+		WithJobState(models.JobStateTypePending),
+		WithDependsOn(upstream.ID),
+		WithCount(1),
+	)
+	s.mockJobStore(scenario)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), upstream.ID).Return(*upstream, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{
+		JobID:      upstream.ID,
+		JobVersion: upstream.Version,
+	}).Return(upstreamExecutions, nil)
+	s.nodeSelector.EXPECT().MatchingNodes(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, job *models.Job) ([]orchestrator.NodeRank, []orchestrator.NodeRank, error) {
+			inputs := job.Task().InputSources
+			s.Require().Len(inputs, 2)
+			for i, input := range inputs {
+				s.Equal(fmt.Sprintf("%s-%d", upstream.Name, i), input.Alias)
+				s.Equal(fmt.Sprintf("/inputs/%s/%d", upstream.Name, i), input.Target)
+			}
+			// each partition gets an alias of its own, so the task stays valid
+			s.NoError(job.Task().Validate())
+			return []orchestrator.NodeRank{*fakeNodeRank(s.T(), "node0")}, []orchestrator.NodeRank{}, nil
+		})
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: scenario.evaluation,
+		NewExecutions: []*models.Execution{
+			{NodeID: "node0", PartitionIndex: 0},
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldResumeFromLatestCheckpoint() {
+	now := time.Now()
+	scenario := NewScenario(
+		WithCount(2),
+		WithCheckpointing(),
+		WithPartitionedExecution("node0", models.ExecutionStateFailed, 0),
+		WithExecutionCheckpoint(3, now.Add(-time.Hour)),
+		WithPartitionedExecution("node1", models.ExecutionStateFailed, 0),
+		WithExecutionCheckpoint(1, now.Add(-time.Minute)),
+		WithPartitionedExecution("node2", models.ExecutionStateFailed, 1),
+	)
+	s.mockJobStore(scenario)
+	s.mockMatchingNodes(scenario, "node3", "node4")
+
+	latest := scenario.executions[1]
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, plan *models.Plan) error {
+		s.Require().Len(plan.NewExecutions, 2)
+		for _, execution := range plan.NewExecutions {
+			inputs := execution.Job.Task().InputSources
+			if execution.PartitionIndex == 1 {
+				// the partition didn't publish a checkpoint, so it starts over
+				s.Empty(execution.PreviousExecution)
+				s.Empty(inputs)
+				continue
+			}
+			s.Equal(latest.ID, execution.PreviousExecution)
+			s.Require().Len(inputs, 1)
+			s.Equal(latest.Checkpoint.Result, inputs[0].Source)
+			s.Equal(models.ResumeCheckpointPath, inputs[0].Target)
+			s.Len(plan.ExecutionEvents[execution.ID], 2)
+		}
+		return nil
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+	s.Empty(scenario.job.Task().InputSources, "stored job spec should not be mutated")
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldRescheduleCancelledExecutionWithoutRetry() {
+	scenario := NewScenario(
+		WithCheckpointing(),
+		WithPartitionedExecution("node0", models.ExecutionStateCancelled, 0),
+		WithExecutionCheckpoint(2, time.Now()),
+	)
+	s.mockJobStore(scenario)
+	s.mockMatchingNodes(scenario, "node1")
+	// cancelled executions, such as preempted ones, don't consume retries
+	s.scheduler.retryStrategy = retry.NewFixedStrategy(retry.FixedStrategyParams{ShouldRetry: false})
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, plan *models.Plan) error {
+		s.False(plan.IsJobFailed())
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Equal("node1", plan.NewExecutions[0].NodeID)
+		s.Equal(scenario.executions[0].ID, plan.NewExecutions[0].PreviousExecution)
+		return nil
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
+
 func (s *BatchJobSchedulerTestSuite) TestProcess_TooManyExecutions() {
 	scenario := NewScenario(
 		WithCount(2),
@@ -3,6 +3,8 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"path"
+	"strconv"
 	"time"
 
 	"github.com/benbjohnson/clock"
@@ -10,13 +12,18 @@ import (
 	"github.com/rs/zerolog/log"
 	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/retry"
 	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
 	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
 )
 
+// upstreamInputsDir is the directory under which the results of upstream jobs are mounted
+const upstreamInputsDir = "/inputs"
+
 type BatchServiceJobSchedulerParams struct {
 	JobStore      jobstore.Store
 	Planner       orchestrator.Planner
@@ -26,6 +33,12 @@ type BatchServiceJobSchedulerParams struct {
 	// RateLimiter controls the rate at which new executions are created
 	// If not provided, a NoopRateLimiter is used
 	RateLimiter ExecutionRateLimiter
+	// Preemption configures which lower priority executions a job can preempt
+	// when no node has enough capacity to run it. If not provided, jobs never preempt other executions.
+	Preemption PreemptionConfig
+	// QuotaEnforcer keeps jobs queued while starting their executions would exceed their namespace's quota.
+	// If not provided, no quotas are enforced.
+	QuotaEnforcer *orchestrator.QuotaEnforcer
 	// Clock is the clock used for time-based operations.
 	// If not provided, the system clock is used.
 	Clock clock.Clock
@@ -61,6 +74,8 @@ type BatchServiceJobScheduler struct {
 	retryStrategy orchestrator.RetryStrategy
 	queueBackoff  time.Duration
 	rateLimiter   ExecutionRateLimiter
+	preemption    PreemptionConfig
+	quotaEnforcer *orchestrator.QuotaEnforcer
 	clock         clock.Clock
 }
 
@@ -78,6 +93,8 @@ func NewBatchServiceJobScheduler(params BatchServiceJobSchedulerParams) *BatchSe
 		retryStrategy: params.RetryStrategy,
 		queueBackoff:  params.QueueBackoff,
 		rateLimiter:   params.RateLimiter,
+		preemption:    params.Preemption,
+		quotaEnforcer: params.QuotaEnforcer,
 		clock:         params.Clock,
 	}
 }
@@ -111,6 +128,16 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 		return err
 	}
 
+	// hold the lock of the job's namespace until its plan is applied, so that the executions created
+	// by concurrent evaluations are counted against its quota
+	if b.quotaEnforcer != nil {
+		unlock, lockErr := b.quotaEnforcer.Lock(ctx, &job)
+		if lockErr != nil {
+			return lockErr
+		}
+		defer unlock()
+	}
+
 	// Plan to hold the actions to be taken
 	plan := models.NewPlan(evaluation, &job)
 	existingExecs := execSetFromSliceOfValues(allJobExecutions)
@@ -123,6 +150,27 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 		return b.planner.Process(ctx, plan)
 	}
 
+	// scheduled jobs only run when triggered by their schedule. Submitting or updating
+	// a scheduled job stops any in progress run, and waits for the next tick.
+	if job.IsScheduled() && !job.IsScheduledRun() {
+		nonTerminalExecs.markCancelled(plan, orchestrator.ExecStoppedForJobUpdateEvent())
+		metrics.AddAttributes(AttrOutcomeKey.String(AttrOutcomeAwaitingSchedule))
+		return b.planner.Process(ctx, plan)
+	}
+
+	// hold off scheduling until all upstream jobs have completed
+	if job.HasDependencies() {
+		var ready bool
+		ready, err = b.handleDependencies(ctx, plan)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			metrics.AddAttributes(AttrOutcomeKey.String(AttrOutcomeAwaitingDependencies))
+			return b.planner.Process(ctx, plan)
+		}
+	}
+
 	// Retrieve the info for all the nodes that have executions for this job
 	nodeInfos, err := existingNodeInfos(ctx, b.selector, nonTerminalExecs)
 	if err != nil {
@@ -141,6 +189,14 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 		allFailedExecs = allFailedExecs.union(lost)
 	}
 
+	// Move service executions away from draining nodes. Batch executions are left to complete.
+	if job.Type == models.JobTypeService {
+		nonTerminalExecs, err = stopDrainingExecs(ctx, metrics, b.selector, plan, nonTerminalExecs)
+		if err != nil {
+			return err
+		}
+	}
+
 	// After this statement, nonTerminalExecs will include:
 	// 1- Running execs - Old Job Versions
 	// 2- Running Execs - New Job Version
@@ -173,7 +229,8 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 	b.approveRejectExecs(nonDiscardedExecs, plan)
 
 	// schedule remaining partitions and assign to new executions
-	err = b.scheduleRemainingPartitions(ctx, metrics, plan, nonDiscardedExecs, allFailedExecs)
+	err = b.scheduleRemainingPartitions(ctx, metrics, plan, nonDiscardedExecs, allFailedExecs,
+		existingExecs.filterByState(models.ExecutionStateCancelled))
 	if err != nil {
 		return err
 	}
@@ -218,6 +275,98 @@ func (b *BatchServiceJobScheduler) loadJobState(ctx context.Context, metrics *te
 	return job, jobExecutions, nil
 }
 
+// handleDependencies checks the state of the job's upstream dependencies and returns true if
+// the job is ready to be scheduled. While the job is pending, it waits for all upstream jobs
+// to complete, and is marked as failed if any of them failed or was stopped. Once the upstream
+// jobs have completed, their published results are wired as input sources of the job's task.
+func (b *BatchServiceJobScheduler) handleDependencies(ctx context.Context, plan *models.Plan) (bool, error) {
+	// dependencies only gate the initial scheduling of the job. Jobs that have already
+	// started, such as when retrying failed executions, keep consuming the upstream results.
+	gated := plan.Job.State.StateType == models.JobStateTypePending
+
+	upstreamJobs := make([]models.Job, 0, len(plan.Job.DependsOn))
+	waitingOn := make([]string, 0)
+	for _, dep := range plan.Job.DependsOn {
+		upstream, err := b.jobStore.GetJob(ctx, dep)
+		if err != nil {
+			if gated && bacerrors.IsErrorWithCode(err, bacerrors.NotFoundError) {
+				plan.MarkJobFailed(orchestrator.JobDependencyFailedEvent(dep, "not found"))
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to retrieve upstream job %s: %w", dep, err)
+		}
+		switch upstream.State.StateType {
+		case models.JobStateTypeCompleted:
+			upstreamJobs = append(upstreamJobs, upstream)
+		case models.JobStateTypeFailed, models.JobStateTypeStopped:
+			if gated {
+				plan.MarkJobFailed(orchestrator.JobDependencyFailedEvent(upstream.ID, upstream.State.StateType.String()))
+				return false, nil
+			}
+		default:
+			waitingOn = append(waitingOn, upstream.ID)
+		}
+	}
+
+	if gated && len(waitingOn) > 0 {
+		log.Ctx(ctx).Debug().Strs("WaitingOn", waitingOn).Msg("job is waiting on upstream jobs to complete")
+		return false, nil
+	}
+
+	inputs, err := b.upstreamInputSources(ctx, upstreamJobs)
+	if err != nil {
+		return false, err
+	}
+	if len(inputs) > 0 && plan.Job.Task() != nil {
+		// copy the task to avoid mutating the job spec shared with the store
+		task := plan.Job.Task().Copy()
+		task.InputSources = append(task.InputSources, inputs...)
+		plan.Job.Tasks = append([]*models.Task{task}, plan.Job.Tasks[1:]...)
+	}
+	return true, nil
+}
+
+// upstreamInputSources returns the published results of the completed upstream jobs as input sources.
+// Results are mounted under /inputs/<upstream job name>, with a sub-directory per partition
+// when the upstream job ran more than one partition. The inputs are aliased the same way,
+// as <upstream job name>-<partition> for partitioned upstream jobs, so that aliases stay unique.
+func (b *BatchServiceJobScheduler) upstreamInputSources(
+	ctx context.Context, upstreamJobs []models.Job) ([]*models.InputSource, error) {
+	var inputs []*models.InputSource
+	for _, upstream := range upstreamJobs {
+		executions, err := b.jobStore.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+			JobID:      upstream.ID,
+			JobVersion: upstream.Version,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve executions for upstream job %s: %w", upstream.ID, err)
+		}
+
+		var results []models.Execution
+		for _, execution := range executions {
+			if execution.ComputeState.StateType == models.ExecutionStateCompleted &&
+				execution.PublishedResult != nil && execution.PublishedResult.Type != "" {
+				results = append(results, execution)
+			}
+		}
+
+		for _, execution := range results {
+			alias := upstream.Name
+			target := path.Join(upstreamInputsDir, upstream.Name)
+			if len(results) > 1 {
+				alias = fmt.Sprintf("%s-%d", upstream.Name, execution.PartitionIndex)
+				target = path.Join(target, strconv.Itoa(execution.PartitionIndex))
+			}
+			inputs = append(inputs, &models.InputSource{
+				Source: execution.PublishedResult.Copy(),
+				Alias:  alias,
+				Target: target,
+			})
+		}
+	}
+	return inputs, nil
+}
+
 func (b *BatchServiceJobScheduler) handleTimeouts(ctx context.Context, metrics *telemetry.MetricRecorder,
 	plan *models.Plan, nonTerminalExecs, allFailedExecs execSet) (execSet, execSet) {
 	// Mark job/executions that have exceeded their total/execution timeout as failed
@@ -264,7 +413,7 @@ func (b *BatchServiceJobScheduler) approveRejectExecs(nonDiscardedExecs execSet,
 }
 
 func (b *BatchServiceJobScheduler) scheduleRemainingPartitions(ctx context.Context, metrics *telemetry.MetricRecorder, plan *models.Plan,
-	nonDiscardedExecs execSet, allFailedExecs execSet) error {
+	nonDiscardedExecs execSet, allFailedExecs execSet, cancelledExecs execSet) error {
 	remainingPartitions := nonDiscardedExecs.remainingPartitions(plan.Job.Count)
 	if len(remainingPartitions) == 0 {
 		return nil
@@ -282,8 +431,25 @@ func (b *BatchServiceJobScheduler) scheduleRemainingPartitions(ctx context.Conte
 		}
 	}
 
-	// find matching nodes for the remaining executions
-	return b.createMissingExecs(ctx, metrics, plan, remainingPartitions)
+	// find matching nodes for the remaining executions. Cancelled executions, such as preempted ones,
+	// don't count as retries but their checkpoints are still resumed from.
+	checkpoints := latestCheckpoints(plan.Job, allFailedExecs.union(cancelledExecs))
+	return b.createMissingExecs(ctx, metrics, plan, remainingPartitions, checkpoints)
+}
+
+// latestCheckpoints returns, for each partition, the stopped execution of the current job version that published
+// the most recent checkpoint, so that the execution retrying the partition resumes from it.
+func latestCheckpoints(job *models.Job, failedExecs execSet) map[int]*models.Execution {
+	checkpoints := make(map[int]*models.Execution)
+	if job.Task() == nil || job.Task().Checkpoint == nil {
+		return checkpoints
+	}
+	for partition, execs := range failedExecs.filterByJobVersion(job.Version).groupByPartition() {
+		if latest := retry.LatestCheckpoint(execs.ordered()); latest != nil {
+			checkpoints[partition] = latest
+		}
+	}
+	return checkpoints
 }
 
 // createMissingExecs creates new executions for partitions that need them.
@@ -296,8 +462,25 @@ func (b *BatchServiceJobScheduler) scheduleRemainingPartitions(ctx context.Conte
 // - Initial: remainingPartitions = [0,1,2]
 // - If partition 1 fails: remainingPartitions = [1]
 // - If all complete (batch): remainingPartitions = []
+//
+// Executions retrying a partition whose failed execution published a checkpoint, as found in checkpoints,
+// restore the checkpoint so that they resume from it.
 func (b *BatchServiceJobScheduler) createMissingExecs(
-	ctx context.Context, metrics *telemetry.MetricRecorder, plan *models.Plan, remainingPartitions []int) error {
+	ctx context.Context, metrics *telemetry.MetricRecorder, plan *models.Plan, remainingPartitions []int,
+	checkpoints map[int]*models.Execution) error {
+	// keep the job queued while starting its missing executions would exceed its namespace's quota
+	quotaReason, err := b.checkQuota(ctx, plan, len(remainingPartitions))
+	if err != nil {
+		return err
+	}
+	if quotaReason != "" {
+		comment := fmt.Sprintf("waiting for namespace quota: %s", quotaReason)
+		b.createDelayedEvaluation(ctx, plan, comment)
+		b.markJobQueued(plan, comment)
+		metrics.AddAttributes(AttrOutcomeKey.String(AttrOutcomeQuotaExceeded))
+		return nil
+	}
+
 	// find matching nodes for the job
 	matching, rejected, err := b.selector.MatchingNodes(ctx, plan.Job)
 	if err != nil {
@@ -307,6 +490,17 @@ func (b *BatchServiceJobScheduler) createMissingExecs(
 	metrics.Histogram(ctx, nodesMatched, float64(len(matching)))
 	metrics.Histogram(ctx, nodesRejected, float64(len(rejected)))
 
+	// preempt executions of lower priority jobs if there are not enough nodes with capacity for the job
+	if len(matching) < len(remainingPartitions) {
+		var preempted []orchestrator.NodeRank
+		preempted, rejected, err = b.preemptExecutions(ctx, metrics, plan, rejected, len(remainingPartitions)-len(matching))
+		if err != nil {
+			return err
+		}
+		matching = append(matching, preempted...)
+		metrics.Latency(ctx, processPartDuration, AttrOperationPartPreempt)
+	}
+
 	// fail fast if there are not enough nodes, and we've passed job queue timeout
 	if len(matching) < len(remainingPartitions) {
 		// check if we can retry scheduling the job at a later time if we don't have enough nodes
@@ -316,7 +510,7 @@ func (b *BatchServiceJobScheduler) createMissingExecs(
 		// TODO: we are calculating queue timeout based on job creation time, but we should probably
 		//  calculate it based on the time the job stayed in the queue so that rescheduling the job
 		//  would reset the queue timeout.
-		if plan.Job.GetCreateTime().Before(expirationTime) {
+		if plan.Job.GetStartTime().Before(expirationTime) {
 			plan.MarkJobFailed(*models.EventFromError(
 				orchestrator.EventTopicJobScheduling,
 				orchestrator.NewErrNotEnoughNodes(len(remainingPartitions), append(matching, rejected...))))
@@ -332,11 +526,7 @@ func (b *BatchServiceJobScheduler) createMissingExecs(
 		// if not a single node was matched, then the job if fully queued and we should reflect that
 		// in the job state and events
 		if len(matching) == 0 {
-			// only update the state if the is running, or pending and triggered by job registration
-			if plan.Job.State.StateType == models.JobStateTypeRunning ||
-				plan.Eval.TriggeredBy == models.EvalTriggerJobRegister {
-				plan.MarkJobQueued(orchestrator.JobQueueingEvent(comment))
-			}
+			b.markJobQueued(plan, comment)
 		}
 	}
 
@@ -359,8 +549,16 @@ func (b *BatchServiceJobScheduler) createMissingExecs(
 			DesiredState:   models.NewExecutionDesiredState(models.ExecutionDesiredStatePending),
 			PartitionIndex: remainingPartitions[i],
 		}
+		previous, resume := checkpoints[execution.PartitionIndex]
+		if resume {
+			execution.Job = retry.ResumeFromCheckpoint(plan.Job, previous)
+			execution.PreviousExecution = previous.ID
+		}
 		execution.Normalize()
 		plan.AppendExecution(execution, orchestrator.ExecCreatedEvent(execution))
+		if resume {
+			plan.AppendExecutionEvent(execution.ID, orchestrator.ExecResumedFromCheckpointEvent(previous))
+		}
 		count++
 	}
 	metrics.CountAndHistogram(ctx, executionsCreatedTotal, executionsCreated, count)
@@ -368,6 +566,48 @@ func (b *BatchServiceJobScheduler) createMissingExecs(
 	return nil
 }
 
+// markJobQueued reflects in the job state and events that the job is fully queued.
+// The state is only updated if the job is running, or pending and triggered by job registration.
+func (b *BatchServiceJobScheduler) markJobQueued(plan *models.Plan, comment string) {
+	if plan.Job.State.StateType == models.JobStateTypeRunning ||
+		plan.Eval.TriggeredBy == models.EvalTriggerJobRegister {
+		plan.MarkJobQueued(orchestrator.JobQueueingEvent(comment))
+	}
+}
+
+// checkQuota returns a non-empty reason if starting count new executions of the job would exceed
+// its namespace's quota. Executions that the plan is already stopping are not counted.
+func (b *BatchServiceJobScheduler) checkQuota(ctx context.Context, plan *models.Plan, count int) (string, error) {
+	if b.quotaEnforcer == nil {
+		return "", nil
+	}
+	quota, err := b.quotaEnforcer.Quota(ctx, plan.Job.Namespace)
+	if err != nil || quota == nil || quota.IsUnlimited() {
+		return "", err
+	}
+	usage, err := b.quotaEnforcer.Usage(ctx, plan.Job.Namespace)
+	if err != nil {
+		return "", err
+	}
+	for _, update := range plan.UpdatedExecutions {
+		if update.DesiredState != models.ExecutionDesiredStateStopped || update.Execution.IsTerminalState() {
+			continue
+		}
+		resources, err := orchestrator.ExecutionResources(update.Execution)
+		if err != nil {
+			return "", err
+		}
+		usage.Executions--
+		usage.Resources = *usage.Resources.Sub(*resources)
+	}
+
+	required, err := plan.Job.Task().ResourcesConfig.ToResources()
+	if err != nil {
+		return "", fmt.Errorf("failed to convert job resources config to resources: %w", err)
+	}
+	return quota.CheckExecutions(usage, count, *required), nil
+}
+
 // createDelayedEvaluation creates a delayed evaluation with the queue backoff
 func (b *BatchServiceJobScheduler) createDelayedEvaluation(ctx context.Context, plan *models.Plan, comment string) {
 	waitUntil := b.clock.Now().Add(b.queueBackoff)
@@ -108,6 +108,12 @@ func (b *DaemonJobScheduler) Process(ctx context.Context, evaluation *models.Eva
 	lost.markFailed(plan, orchestrator.ExecStoppedByNodeUnhealthyEvent())
 	metrics.CountAndHistogram(ctx, executionsLostTotal, executionsLost, float64(len(lost)))
 
+	// Stop executions running on draining nodes, as no new executions are placed on them
+	nonTerminalExecs, err = stopDrainingExecs(ctx, metrics, b.nodeSelector, plan, nonTerminalExecs)
+	if err != nil {
+		return err
+	}
+
 	// After this call, nonTerminalExecs will now include:
 	//	Running execs - Old Job versions (those were left after rate limiting)
 	//  Running Execs - New Job version
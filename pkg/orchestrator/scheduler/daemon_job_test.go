@@ -96,6 +96,31 @@ func (s *DaemonJobSchedulerTestSuite) TestProcess_ShouldMarkLostExecutionsOnUnhe
 	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
 }
 
+func (s *DaemonJobSchedulerTestSuite) TestProcess_ShouldStopExecutionsOnDrainingNodes() {
+	scenario := NewScenario(
+		WithJobType(models.JobTypeDaemon),
+		WithExecution("node0", models.ExecutionStateRunning),
+		WithExecution("node1", models.ExecutionStateRunning),
+	)
+	s.mockJobStore(scenario)
+	s.mockDrainingNodes("node0")
+	s.mockAllNodes("node0", "node1")
+	s.mockMatchingNodes(scenario, "node1")
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: scenario.evaluation,
+		UpdatedExecutions: []ExecutionStateUpdate{
+			{
+				ExecutionID:  scenario.executions[0].ID,
+				DesiredState: models.ExecutionDesiredStateStopped,
+				ComputeState: models.ExecutionStateCancelled,
+			},
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
+
 // Even when an execution has failed, we don't mark the job as failed and continue waiting
 // for more nodes that match the job selection to join.
 // This requires a revisit in the future if all or a high percentage of nodes keep failing
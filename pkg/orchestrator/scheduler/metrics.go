@@ -63,6 +63,18 @@ var (
 		metric.WithUnit("1"),
 	))
 
+	executionsPreempted = telemetry.Must(Meter.Int64Counter(
+		"scheduler.executions.preempted",
+		metric.WithDescription("Number of executions preempted by higher priority jobs"),
+		metric.WithUnit("1"),
+	))
+
+	executionsDrained = telemetry.Must(Meter.Int64Counter(
+		"scheduler.executions.drained",
+		metric.WithDescription("Number of executions stopped to be moved away from draining nodes"),
+		metric.WithUnit("1"),
+	))
+
 	executionsTimedOut = telemetry.Must(Meter.Int64Counter(
 		"scheduler.executions.timeout",
 		metric.WithDescription("Number of executions that timed out"),
@@ -107,14 +119,18 @@ const (
 	AttrOperationPartGetExecs    = "get_executions"
 	AttrOperationPartGetNodes    = "get_node_infos"
 	AttrOperationPartMatchNodes  = "match_nodes"
+	AttrOperationPartPreempt     = "preempt"
 	AttrOperationPartProcessPlan = "process_plan"
 
-	AttrOutcomeKey              = attribute.Key("outcome")
-	AttrOutcomeSuccess          = "success"
-	AttrOutcomeFailure          = "failure"
-	AttrOutcomeAlreadyTerminal  = "already_terminal"
-	AttrOutcomeExhaustedRetries = "exhausted_retries"
-	AttrOutcomeQueueing         = "queueing"
-	AttrOutcomeTimeout          = "timeout"
-	AttrOutcomeQueueTimeout     = "queue_timeout"
+	AttrOutcomeKey                  = attribute.Key("outcome")
+	AttrOutcomeSuccess              = "success"
+	AttrOutcomeFailure              = "failure"
+	AttrOutcomeAlreadyTerminal      = "already_terminal"
+	AttrOutcomeExhaustedRetries     = "exhausted_retries"
+	AttrOutcomeQueueing             = "queueing"
+	AttrOutcomeTimeout              = "timeout"
+	AttrOutcomeQueueTimeout         = "queue_timeout"
+	AttrOutcomeAwaitingDependencies = "awaiting_dependencies"
+	AttrOutcomeAwaitingSchedule     = "awaiting_schedule"
+	AttrOutcomeQuotaExceeded        = "quota_exceeded"
 )
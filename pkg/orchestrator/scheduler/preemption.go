@@ -0,0 +1,193 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
+)
+
+// PreemptionPolicy defines which executions a job is allowed to preempt
+// when no node has enough capacity to run it.
+type PreemptionPolicy string
+
+const (
+	// PreemptionNever never preempts other executions.
+	PreemptionNever PreemptionPolicy = "never"
+	// PreemptionSameNamespace preempts executions of lower priority jobs in the same namespace only.
+	PreemptionSameNamespace PreemptionPolicy = "namespace"
+	// PreemptionAnyNamespace preempts executions of lower priority jobs in any namespace.
+	PreemptionAnyNamespace PreemptionPolicy = "any"
+)
+
+// PreemptionConfig holds the preemption policies applied to jobs based on their namespace.
+type PreemptionConfig struct {
+	// DefaultPolicy applies to jobs in namespaces without a specific policy. Defaults to never.
+	DefaultPolicy PreemptionPolicy
+	// NamespacePolicies overrides the default policy for jobs in specific namespaces.
+	NamespacePolicies map[string]PreemptionPolicy
+}
+
+// Validate checks the preemption policies are supported
+func (c PreemptionConfig) Validate() error {
+	if err := validatePreemptionPolicy(c.DefaultPolicy); err != nil {
+		return err
+	}
+	for namespace, policy := range c.NamespacePolicies {
+		if err := validatePreemptionPolicy(policy); err != nil {
+			return fmt.Errorf("namespace %s: %w", namespace, err)
+		}
+	}
+	return nil
+}
+
+// PolicyFor returns the preemption policy applied to jobs in the given namespace
+func (c PreemptionConfig) PolicyFor(namespace string) PreemptionPolicy {
+	if policy, ok := c.NamespacePolicies[namespace]; ok && policy != "" {
+		return policy
+	}
+	if c.DefaultPolicy == "" {
+		return PreemptionNever
+	}
+	return c.DefaultPolicy
+}
+
+// canPreempt returns true if the given job is allowed to preempt executions of the victim job
+func (c PreemptionConfig) canPreempt(job, victim *models.Job) bool {
+	// only batch and service executions are rescheduled elsewhere when preempted
+	if victim.ID == job.ID || victim.Priority >= job.Priority ||
+		(victim.Type != models.JobTypeBatch && victim.Type != models.JobTypeService) {
+		return false
+	}
+	switch c.PolicyFor(job.Namespace) {
+	case PreemptionAnyNamespace:
+		return true
+	case PreemptionSameNamespace:
+		return victim.Namespace == job.Namespace
+	default:
+		return false
+	}
+}
+
+func validatePreemptionPolicy(policy PreemptionPolicy) error {
+	switch policy {
+	case "", PreemptionNever, PreemptionSameNamespace, PreemptionAnyNamespace:
+		return nil
+	default:
+		return fmt.Errorf("invalid preemption policy %q. must be one of %s, %s or %s",
+			policy, PreemptionNever, PreemptionSameNamespace, PreemptionAnyNamespace)
+	}
+}
+
+// preemptExecutions looks for up to count nodes that were rejected only for transient reasons, such as being busy,
+// and on which enough capacity can be freed for the job by stopping executions of lower priority jobs.
+// The preempted executions are marked as cancelled, so that they don't count against their job's retries,
+// and their jobs re-evaluated to reschedule them elsewhere. It returns the nodes that can run the job after
+// preemption, and the remaining rejected nodes.
+func (b *BatchServiceJobScheduler) preemptExecutions(
+	ctx context.Context,
+	metrics *telemetry.MetricRecorder,
+	plan *models.Plan,
+	rejected []orchestrator.NodeRank,
+	count int,
+) (preempted, remaining []orchestrator.NodeRank, err error) {
+	if count <= 0 || b.preemption.PolicyFor(plan.Job.Namespace) == PreemptionNever {
+		return nil, rejected, nil
+	}
+
+	required, err := plan.Job.Task().ResourcesConfig.ToResources()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert job resources config to resources: %w", err)
+	}
+
+	evaluatedJobs := make(map[string]bool)
+	for _, node := range rejected {
+		if len(preempted) == count || !node.Retryable {
+			remaining = append(remaining, node)
+			continue
+		}
+
+		victims, err := b.findPreemptibleExecutions(ctx, plan.Job, node.NodeInfo, *required)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(victims) == 0 {
+			remaining = append(remaining, node)
+			continue
+		}
+
+		for _, victim := range victims {
+			plan.AppendStoppedExecution(victim, orchestrator.ExecPreemptedEvent(plan.Job), models.ExecutionStateCancelled)
+			if !evaluatedJobs[victim.JobID] {
+				evaluatedJobs[victim.JobID] = true
+				plan.AppendEvaluation(models.NewEvaluation().
+					WithJob(victim.Job).
+					WithTriggeredBy(models.EvalTriggerExecPreempted).
+					WithComment(fmt.Sprintf("execution %s preempted by job %s", victim.ID, plan.Job.ID)))
+			}
+		}
+		log.Ctx(ctx).Debug().Str("NodeID", node.NodeInfo.ID()).Int("Preempted", len(victims)).
+			Msg("preempting lower priority executions")
+		metrics.CountN(ctx, executionsPreempted, int64(len(victims)))
+		preempted = append(preempted, node)
+	}
+	return preempted, remaining, nil
+}
+
+// findPreemptibleExecutions returns the running executions on the node that the job is allowed to preempt
+// and that free enough capacity to run the job, or nil if the job wouldn't fit on the node after preemption.
+// Executions of the lowest priority jobs are preempted first, and the most recent ones among them to
+// waste as little work as possible.
+func (b *BatchServiceJobScheduler) findPreemptibleExecutions(
+	ctx context.Context, job *models.Job, node models.NodeInfo, required models.Resources) ([]*models.Execution, error) {
+	if node.ComputeNodeInfo.MaxCapacity.IsZero() || !required.LessThanEq(node.ComputeNodeInfo.MaxCapacity) {
+		return nil, nil
+	}
+
+	executions, err := b.jobStore.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+		NodeIDs:        []string{node.ID()},
+		InProgressOnly: true,
+		IncludeJob:     true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve executions on node %s: %w", node.ID(), err)
+	}
+
+	candidates := make([]*models.Execution, 0, len(executions))
+	for i := range executions {
+		execution := &executions[i]
+		if execution.ComputeState.StateType != models.ExecutionStateRunning ||
+			execution.Job == nil || !b.preemption.canPreempt(job, execution.Job) {
+			continue
+		}
+		candidates = append(candidates, execution)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Job.Priority != candidates[j].Job.Priority {
+			return candidates[i].Job.Priority < candidates[j].Job.Priority
+		}
+		return candidates[i].CreateTime > candidates[j].CreateTime
+	})
+
+	available := node.ComputeNodeInfo.AvailableCapacity.Copy()
+	for i, candidate := range candidates {
+		if required.LessThanEq(*available) {
+			return candidates[:i], nil
+		}
+		freed, err := candidate.Job.Task().ResourcesConfig.ToResources()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert resources of job %s: %w", candidate.JobID, err)
+		}
+		available = available.Add(*freed)
+	}
+	if len(candidates) > 0 && required.LessThanEq(*available) {
+		return candidates, nil
+	}
+	return nil, nil
+}
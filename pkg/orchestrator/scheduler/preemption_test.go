@@ -0,0 +1,209 @@
+//go:build unit || !integration
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type PreemptionTestSuite struct {
+	BaseTestSuite
+	scheduler *BatchServiceJobScheduler
+}
+
+func (s *PreemptionTestSuite) SetupTest() {
+	s.BaseTestSuite.SetupTest()
+	s.newScheduler(PreemptionConfig{DefaultPolicy: PreemptionSameNamespace})
+}
+
+func TestPreemptionTestSuite(t *testing.T) {
+	suite.Run(t, new(PreemptionTestSuite))
+}
+
+func (s *PreemptionTestSuite) newScheduler(config PreemptionConfig) {
+	s.scheduler = NewBatchServiceJobScheduler(BatchServiceJobSchedulerParams{
+		JobStore:      s.jobStore,
+		Planner:       s.planner,
+		NodeSelector:  s.nodeSelector,
+		RetryStrategy: s.retryStrategy,
+		QueueBackoff:  5 * time.Second,
+		Preemption:    config,
+		Clock:         s.clock,
+	})
+}
+
+// newScenario returns a scenario of a job waiting in the queue for a node
+func (s *PreemptionTestSuite) newScenario() *Scenario {
+	return NewScenario(
+		WithCount(1),
+		WithCreateTime(s.clock.Now().UnixNano()),
+		WithQueueTimeout(60*time.Minute),
+	)
+}
+
+// mockBusyNode mocks a single busy node that was rejected for the job, and returns it
+func (s *PreemptionTestSuite) mockBusyNode(scenario *Scenario, nodeID string) orchestrator.NodeRank {
+	node := orchestrator.NodeRank{
+		NodeInfo: models.NodeInfo{
+			NodeID:   nodeID,
+			NodeType: models.NodeTypeCompute,
+			ComputeNodeInfo: models.ComputeNodeInfo{
+				MaxCapacity:       models.Resources{CPU: 1, Memory: 1024 * 1024 * 1024},
+				AvailableCapacity: models.Resources{},
+			},
+		},
+		Rank:      orchestrator.RankUnsuitable,
+		Reason:    "node busy",
+		Retryable: true,
+	}
+	s.nodeSelector.EXPECT().MatchingNodes(gomock.Any(), scenario.job).
+		Return([]orchestrator.NodeRank{}, []orchestrator.NodeRank{node}, nil)
+	return node
+}
+
+// mockRunningExecutions mocks the running executions on the node for the given jobs
+func (s *PreemptionTestSuite) mockRunningExecutions(nodeID string, jobs ...*models.Job) []models.Execution {
+	executions := make([]models.Execution, len(jobs))
+	for i, job := range jobs {
+		execution := mock.ExecutionForJob(job)
+		execution.NodeID = nodeID
+		execution.ComputeState = models.NewExecutionState(models.ExecutionStateRunning)
+		execution.Job = job
+		executions[i] = *execution
+	}
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{
+		NodeIDs:        []string{nodeID},
+		InProgressOnly: true,
+		IncludeJob:     true,
+	}).Return(executions, nil)
+	return executions
+}
+
+func (s *PreemptionTestSuite) lowPriorityJob(namespace string) *models.Job {
+	job := mock.Job()
+	job.Namespace = namespace
+	job.Priority = 10
+	return job
+}
+
+func (s *PreemptionTestSuite) TestPreemptsLowerPriorityExecution() {
+	scenario := s.newScenario()
+	scenario.job.Priority = 50
+	s.mockJobStore(scenario)
+	s.mockBusyNode(scenario, "node0")
+	victims := s.mockRunningExecutions("node0", s.lowPriorityJob(scenario.job.Namespace))
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, plan *models.Plan) error {
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Equal("node0", plan.NewExecutions[0].NodeID)
+
+		s.Require().Len(plan.UpdatedExecutions, 1)
+		update := plan.UpdatedExecutions[victims[0].ID]
+		s.Require().NotNil(update)
+		s.Equal(models.ExecutionStateCancelled, update.ComputeState)
+		s.Equal(models.ExecutionDesiredStateStopped, update.DesiredState)
+		s.Equal(orchestrator.EventTopicExecPreemption, update.Event.Topic)
+		s.Equal(scenario.job.ID, update.Event.Details["PreemptedByJobID"])
+
+		// the preempted job is re-evaluated to reschedule the execution elsewhere
+		s.Require().Len(plan.NewEvaluations, 1)
+		s.Equal(victims[0].JobID, plan.NewEvaluations[0].JobID)
+		s.Equal(models.EvalTriggerExecPreempted, plan.NewEvaluations[0].TriggeredBy)
+		return nil
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
+
+func (s *PreemptionTestSuite) TestDoesNotPreemptHigherPriorityExecution() {
+	scenario := s.newScenario()
+	scenario.job.Priority = 5
+	s.mockJobStore(scenario)
+	s.mockBusyNode(scenario, "node0")
+	s.mockRunningExecutions("node0", s.lowPriorityJob(scenario.job.Namespace))
+
+	s.assertQueued(scenario)
+}
+
+func (s *PreemptionTestSuite) TestDoesNotPreemptOtherNamespaces() {
+	scenario := s.newScenario()
+	scenario.job.Priority = 50
+	s.mockJobStore(scenario)
+	s.mockBusyNode(scenario, "node0")
+	s.mockRunningExecutions("node0", s.lowPriorityJob("other"))
+
+	s.assertQueued(scenario)
+}
+
+func (s *PreemptionTestSuite) TestPreemptsOtherNamespacesWhenAllowed() {
+	s.newScheduler(PreemptionConfig{
+		DefaultPolicy:     PreemptionNever,
+		NamespacePolicies: map[string]PreemptionPolicy{"default": PreemptionAnyNamespace},
+	})
+	scenario := s.newScenario()
+	scenario.job.Namespace = "default"
+	scenario.job.Priority = 50
+	s.mockJobStore(scenario)
+	s.mockBusyNode(scenario, "node0")
+	s.mockRunningExecutions("node0", s.lowPriorityJob("other"))
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, plan *models.Plan) error {
+		s.Len(plan.NewExecutions, 1)
+		s.Len(plan.UpdatedExecutions, 1)
+		return nil
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
+
+func (s *PreemptionTestSuite) TestNeverPreempts() {
+	s.newScheduler(PreemptionConfig{})
+	scenario := s.newScenario()
+	scenario.job.Priority = 50
+	s.mockJobStore(scenario)
+	s.mockBusyNode(scenario, "node0")
+
+	s.assertQueued(scenario)
+}
+
+func (s *PreemptionTestSuite) TestDoesNotPreemptNonRetryableNodes() {
+	scenario := s.newScenario()
+	s.mockJobStore(scenario)
+	node := orchestrator.NodeRank{NodeInfo: fakeNodeInfo(s.T(), "node0"), Rank: orchestrator.RankUnsuitable}
+	s.nodeSelector.EXPECT().MatchingNodes(gomock.Any(), scenario.job).
+		Return([]orchestrator.NodeRank{}, []orchestrator.NodeRank{node}, nil)
+
+	s.assertQueued(scenario)
+}
+
+func (s *PreemptionTestSuite) TestPreemptionConfig() {
+	config := PreemptionConfig{
+		NamespacePolicies: map[string]PreemptionPolicy{"prod": PreemptionAnyNamespace},
+	}
+	s.NoError(config.Validate())
+	s.Equal(PreemptionNever, config.PolicyFor("dev"))
+	s.Equal(PreemptionAnyNamespace, config.PolicyFor("prod"))
+
+	config.NamespacePolicies["dev"] = "always"
+	s.ErrorContains(config.Validate(), "invalid preemption policy")
+}
+
+// assertQueued asserts the job is queued without preempting any execution
+func (s *PreemptionTestSuite) assertQueued(scenario *Scenario) {
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: scenario.evaluation,
+		ExpectedNewEvaluations: []ExpectedEvaluation{
+			{WaitUntil: s.clock.Now().Add(5 * time.Second), TriggeredBy: models.EvalTriggerJobQueue},
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
@@ -0,0 +1,133 @@
+//go:build unit || !integration
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type QuotaTestSuite struct {
+	BaseTestSuite
+	scheduler *BatchServiceJobScheduler
+}
+
+func (s *QuotaTestSuite) SetupTest() {
+	s.BaseTestSuite.SetupTest()
+	s.scheduler = NewBatchServiceJobScheduler(BatchServiceJobSchedulerParams{
+		JobStore:      s.jobStore,
+		Planner:       s.planner,
+		NodeSelector:  s.nodeSelector,
+		RetryStrategy: s.retryStrategy,
+		QueueBackoff:  5 * time.Second,
+		QuotaEnforcer: orchestrator.NewQuotaEnforcer(s.jobStore),
+		Clock:         s.clock,
+	})
+}
+
+func TestQuotaTestSuite(t *testing.T) {
+	suite.Run(t, new(QuotaTestSuite))
+}
+
+// newScenario returns a scenario of a newly submitted job with a single partition
+func (s *QuotaTestSuite) newScenario() *Scenario {
+	scenario := NewScenario(
+		WithCount(1),
+		WithCreateTime(s.clock.Now().UnixNano()),
+	)
+	scenario.evaluation.TriggeredBy = models.EvalTriggerJobRegister
+	return scenario
+}
+
+// mockNamespaceUsage mocks the quota of the job's namespace and the executions running in it
+func (s *QuotaTestSuite) mockNamespaceUsage(namespace string, quota models.NamespaceQuota, running int) {
+	quota.Namespace = namespace
+	// the quota is read to lock the namespace, and to check the job against it
+	s.jobStore.EXPECT().GetNamespaceQuota(gomock.Any(), namespace).Return(quota, nil).Times(2)
+
+	executions := make([]models.Execution, running)
+	for i := range executions {
+		job := mock.Job()
+		execution := mock.ExecutionForJob(job)
+		execution.Job = job
+		execution.AllocateResources(job.Task().Name, models.Resources{CPU: 1})
+		executions[i] = *execution
+	}
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{
+		Namespace:      namespace,
+		InProgressOnly: true,
+		AllJobVersions: true,
+		IncludeJob:     true,
+	}).Return(executions, nil)
+	s.jobStore.EXPECT().GetInProgressJobsInNamespace(gomock.Any(), namespace).Return(nil, nil)
+}
+
+func (s *QuotaTestSuite) TestQueuesJobExceedingExecutionsQuota() {
+	scenario := s.newScenario()
+	s.mockJobStore(scenario)
+	s.mockNamespaceUsage(scenario.job.Namespace, models.NamespaceQuota{MaxConcurrentExecutions: 2}, 2)
+
+	// no nodes are matched for a job exceeding its quota
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, plan *models.Plan) error {
+		s.Empty(plan.NewExecutions)
+		s.Equal(models.JobStateTypeQueued, plan.DesiredJobState)
+		s.Contains(plan.UpdateMessage, "waiting for namespace quota")
+		s.Contains(plan.UpdateMessage, "limit of 2 concurrent executions")
+
+		s.Require().Len(plan.NewEvaluations, 1)
+		s.Equal(models.EvalTriggerJobQueue, plan.NewEvaluations[0].TriggeredBy)
+		s.Equal(s.clock.Now().Add(5*time.Second).UnixNano(), plan.NewEvaluations[0].WaitUntil.UnixNano())
+		return nil
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
+
+func (s *QuotaTestSuite) TestQueuesJobExceedingResourcesQuota() {
+	scenario := s.newScenario()
+	s.mockJobStore(scenario)
+	s.mockNamespaceUsage(scenario.job.Namespace, models.NamespaceQuota{MaxResources: models.Resources{CPU: 1}}, 1)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, plan *models.Plan) error {
+		s.Empty(plan.NewExecutions)
+		s.Contains(plan.UpdateMessage, "CPU limits")
+		return nil
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
+
+func (s *QuotaTestSuite) TestSchedulesJobWithinQuota() {
+	scenario := s.newScenario()
+	s.mockJobStore(scenario)
+	s.mockNamespaceUsage(scenario.job.Namespace, models.NamespaceQuota{MaxConcurrentExecutions: 2}, 1)
+	s.mockMatchingNodes(scenario, "node0")
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, plan *models.Plan) error {
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Equal("node0", plan.NewExecutions[0].NodeID)
+		return nil
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
+
+func (s *QuotaTestSuite) TestSchedulesJobWithoutQuota() {
+	scenario := s.newScenario()
+	s.mockJobStore(scenario)
+	s.jobStore.EXPECT().GetNamespaceQuota(gomock.Any(), scenario.job.Namespace).
+		Return(models.NamespaceQuota{}, jobstore.NewErrNamespaceQuotaNotFound(scenario.job.Namespace)).Times(2)
+	s.mockMatchingNodes(scenario, "node0")
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, plan *models.Plan) error {
+		s.Len(plan.NewExecutions, 1)
+		return nil
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
@@ -113,6 +113,36 @@ func (s *ServiceJobSchedulerTestSuite) TestFailUnhealthyExecs_ShouldMarkExecutio
 	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
 }
 
+func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldMoveExecutionsAwayFromDrainingNodes() {
+	scenario := NewScenario(
+		WithJobType(models.JobTypeService),
+		WithCount(2),
+		WithPartitionedExecution("node0", models.ExecutionStateRunning, 0),
+		WithPartitionedExecution("node1", models.ExecutionStateRunning, 1),
+	)
+	s.mockJobStore(scenario)
+	s.mockDrainingNodes("node1")
+	s.mockAllNodes("node0", "node1", "node2")
+	// draining nodes are not matched for new executions
+	s.mockMatchingNodes(scenario, "node2")
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: scenario.evaluation,
+		NewExecutions: []*models.Execution{
+			{NodeID: "node2", PartitionIndex: 1},
+		},
+		UpdatedExecutions: []ExecutionStateUpdate{
+			{
+				ExecutionID:  scenario.executions[1].ID,
+				DesiredState: models.ExecutionDesiredStateStopped,
+				ComputeState: models.ExecutionStateCancelled,
+			},
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(context.Background(), scenario.evaluation))
+}
+
 // It is a bug if a long running execution is completed. The scheduler treat those as failed executions,
 // try to reschedule, or fail the job if can no longer reschedule
 func (s *ServiceJobSchedulerTestSuite) TestProcess_TreatCompletedExecutionsAsFailed() {
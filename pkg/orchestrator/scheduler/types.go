@@ -139,6 +139,20 @@ func (set execSet) groupByNodeHealth(nodeInfos map[string]*models.NodeInfo) (hea
 	return healthy, lost
 }
 
+// groupByNodeDraining partitions executions based on whether their node is being drained.
+func (set execSet) groupByNodeDraining(drainingNodes map[string]struct{}) (remaining execSet, draining execSet) {
+	remaining = make(execSet)
+	draining = make(execSet)
+	for _, exec := range set {
+		if _, ok := drainingNodes[exec.NodeID]; ok {
+			draining[exec.ID] = exec
+		} else {
+			remaining[exec.ID] = exec
+		}
+	}
+	return remaining, draining
+}
+
 // groupByExecutionTimeout partitions executions based on their timeout status.
 func (set execSet) groupByExecutionTimeout(expirationTime time.Time) (remaining, timedOut execSet) {
 	remaining = make(execSet)
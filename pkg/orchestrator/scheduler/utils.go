@@ -6,6 +6,7 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
 )
 
 // existingNodeInfos returns a map of nodeID to NodeInfo for all the nodes that have executions for this job
@@ -43,3 +44,42 @@ func existingNodeInfos(ctx context.Context,
 	}
 	return out, nil
 }
+
+// drainingNodeIDs returns the IDs of the nodes being drained that have executions for this job
+func drainingNodeIDs(ctx context.Context,
+	nodeSelector orchestrator.NodeSelector,
+	existingExecutions execSet) (map[string]struct{}, error) {
+	out := make(map[string]struct{})
+	if len(existingExecutions) == 0 {
+		return out, nil
+	}
+
+	draining, err := nodeSelector.DrainingNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list draining nodes: %w", err)
+	}
+	for _, nodeID := range draining {
+		out[nodeID] = struct{}{}
+	}
+	return out, nil
+}
+
+// stopDrainingExecs stops the executions running on nodes that are being drained,
+// and returns the remaining executions. The stopped executions are not considered failed,
+// and will be replaced by new executions on other nodes.
+func stopDrainingExecs(ctx context.Context,
+	metrics *telemetry.MetricRecorder,
+	nodeSelector orchestrator.NodeSelector,
+	plan *models.Plan,
+	executions execSet) (execSet, error) {
+	drainingNodes, err := drainingNodeIDs(ctx, nodeSelector, executions)
+	if err != nil {
+		return nil, err
+	}
+	remaining, draining := executions.groupByNodeDraining(drainingNodes)
+	if len(draining) > 0 {
+		draining.markCancelled(plan, orchestrator.ExecStoppedByNodeDrainEvent())
+		metrics.CountN(ctx, executionsDrained, int64(len(draining)))
+	}
+	return remaining, nil
+}
@@ -32,6 +32,21 @@ func WithCount(count int) ScenarioBuilderOption {
 	}
 }
 
+func WithDependsOn(jobIDs ...string) ScenarioBuilderOption {
+	return func(b *Scenario) {
+		b.job.DependsOn = jobIDs
+	}
+}
+
+func WithSchedule(cron string, runTime time.Time) ScenarioBuilderOption {
+	return func(b *Scenario) {
+		b.job.Schedule = &models.Schedule{Cron: cron, OverlapPolicy: models.ScheduleOverlapSkip}
+		if !runTime.IsZero() {
+			b.job.Meta[models.MetaScheduledRunTime] = runTime.UTC().Format(time.RFC3339)
+		}
+	}
+}
+
 func WithExecution(nodeID string, state models.ExecutionStateType) ScenarioBuilderOption {
 	return func(b *Scenario) {
 		execution := mock.ExecutionForJob(b.job)
@@ -51,6 +66,30 @@ func WithPartitionedExecution(nodeID string, state models.ExecutionStateType, pa
 	}
 }
 
+// WithCheckpointing enables checkpointing of the job's task.
+func WithCheckpointing() ScenarioBuilderOption {
+	return func(b *Scenario) {
+		b.job.Task().Checkpoint = &models.CheckpointConfig{}
+	}
+}
+
+// WithExecutionCheckpoint sets the last checkpoint published by the latest execution added to the scenario.
+func WithExecutionCheckpoint(sequence int, createTime time.Time) ScenarioBuilderOption {
+	return func(b *Scenario) {
+		if len(b.executions) == 0 {
+			panic("no executions to set checkpoint")
+		}
+		b.executions[len(b.executions)-1].Checkpoint = &models.Checkpoint{
+			Result: &models.SpecConfig{
+				Type:   models.StorageSourceURL,
+				Params: map[string]interface{}{"URL": fmt.Sprintf("http://example.com/checkpoint-%d.tar.gz", sequence)},
+			},
+			Sequence:   sequence,
+			CreateTime: createTime.UnixNano(),
+		}
+	}
+}
+
 // WithDesiredState sets the desired state of the latest execution added to the scenario.
 func WithDesiredState(state models.ExecutionDesiredStateType) ScenarioBuilderOption {
 	return func(b *Scenario) {
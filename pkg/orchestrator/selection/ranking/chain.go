@@ -39,8 +39,11 @@ func (c *Chain) RankNodes(ctx context.Context, job models.Job, nodes []models.No
 		}
 		for _, nodeRank := range nodeRanks {
 			if !nodeRank.MeetsRequirement() {
-				ranksMap[nodeRank.NodeInfo.ID()].Rank = orchestrator.RankUnsuitable
-				ranksMap[nodeRank.NodeInfo.ID()].Reason = nodeRank.Reason
+				// a rejected node is only retryable if all the reasons it was rejected for are transient
+				rank := ranksMap[nodeRank.NodeInfo.ID()]
+				rank.Retryable = nodeRank.Retryable && (rank.MeetsRequirement() || rank.Retryable)
+				rank.Rank = orchestrator.RankUnsuitable
+				rank.Reason = nodeRank.Reason
 			} else if ranksMap[nodeRank.NodeInfo.ID()].MeetsRequirement() {
 				ranksMap[nodeRank.NodeInfo.ID()].Rank += nodeRank.Rank
 			}
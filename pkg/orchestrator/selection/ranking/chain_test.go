@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -70,3 +71,32 @@ func (s *ChainSuite) TestRankNodes_AllNegative() {
 	assertEquals(s.T(), ranks, "peerID2", -1)
 	assertEquals(s.T(), ranks, "peerID3", -1)
 }
+
+func (s *ChainSuite) TestRankNodes_Retryable() {
+	s.chain.Add(&retryableRanker{NewFixedRanker(-1, -1, 10)})
+	s.chain.Add(NewFixedRanker(0, -1, 10))
+
+	ranks, err := s.chain.RankNodes(context.Background(), models.Job{}, []models.NodeInfo{s.peerID1, s.peerID2, s.peerID3})
+	s.NoError(err)
+	for _, rank := range ranks {
+		switch rank.NodeInfo.ID() {
+		case "peerID1":
+			s.True(rank.Retryable, "node only rejected for transient reasons should be retryable")
+		case "peerID2":
+			s.False(rank.Retryable, "node also rejected for non transient reasons should not be retryable")
+		}
+	}
+}
+
+// retryableRanker marks the ranks of the wrapped ranker as retryable
+type retryableRanker struct {
+	ranker *fixedRanker
+}
+
+func (r *retryableRanker) RankNodes(ctx context.Context, job models.Job, nodes []models.NodeInfo) ([]orchestrator.NodeRank, error) {
+	ranks, err := r.ranker.RankNodes(ctx, job, nodes)
+	for i := range ranks {
+		ranks[i].Retryable = true
+	}
+	return ranks, err
+}
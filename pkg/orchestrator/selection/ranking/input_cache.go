@@ -0,0 +1,70 @@
+package ranking
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/inputcache"
+)
+
+// maxInputCacheRank is the rank of the nodes that have all the inputs of a job in their input cache
+const maxInputCacheRank = 3 * orchestrator.RankPreferred
+
+// InputCacheNodeRanker ranks nodes based on whether they already have the inputs of the job in their
+// input cache, as advertised in their node info, so that jobs prefer the nodes that do not need to
+// download their inputs.
+type InputCacheNodeRanker struct{}
+
+func NewInputCacheNodeRanker() *InputCacheNodeRanker {
+	return &InputCacheNodeRanker{}
+}
+
+// RankNodes ranks nodes based on the share of the inputs of the job they have cached:
+// - Rank 30: Node has all the inputs of the job cached.
+// - Rank between 0 and 30: Node has some of the inputs of the job cached.
+// - Rank 0: Node has none of the inputs of the job cached, or the job has no inputs.
+func (s *InputCacheNodeRanker) RankNodes(
+	ctx context.Context, job models.Job, nodes []models.NodeInfo) ([]orchestrator.NodeRank, error) {
+	var sources []string
+	for _, task := range job.Tasks {
+		for _, input := range task.InputSources {
+			sources = append(sources, inputcache.SourceID(input.Source))
+		}
+	}
+
+	ranks := make([]orchestrator.NodeRank, len(nodes))
+	for i, node := range nodes {
+		rank := orchestrator.RankPossible
+		reason := "no inputs cached"
+		if len(sources) > 0 {
+			cached := make(map[string]bool, len(node.ComputeNodeInfo.CachedInputs))
+			for _, source := range node.ComputeNodeInfo.CachedInputs {
+				cached[source] = true
+			}
+			found := 0
+			for _, source := range sources {
+				if cached[source] {
+					found++
+				}
+			}
+			if found > 0 {
+				rank = maxInputCacheRank * found / len(sources)
+				reason = fmt.Sprintf("%d of %d inputs cached", found, len(sources))
+			}
+		}
+		ranks[i] = orchestrator.NodeRank{
+			NodeInfo: node,
+			Rank:     rank,
+			Reason:   reason,
+		}
+		log.Ctx(ctx).Trace().Object("Rank", ranks[i]).Msg("Ranked node")
+	}
+	return ranks, nil
+}
+
+// compile-time interface check
+var _ orchestrator.NodeRanker = (*InputCacheNodeRanker)(nil)
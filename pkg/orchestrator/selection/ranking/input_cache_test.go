@@ -0,0 +1,72 @@
+//go:build unit || !integration
+
+package ranking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/inputcache"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type InputCacheNodeRankerSuite struct {
+	suite.Suite
+	ranker *InputCacheNodeRanker
+	first  *models.SpecConfig
+	second *models.SpecConfig
+}
+
+func TestInputCacheNodeRankerSuite(t *testing.T) {
+	suite.Run(t, new(InputCacheNodeRankerSuite))
+}
+
+func (s *InputCacheNodeRankerSuite) SetupTest() {
+	s.ranker = NewInputCacheNodeRanker()
+	s.first = &models.SpecConfig{
+		Type:   models.StorageSourceURL,
+		Params: map[string]interface{}{"URL": "https://example.com/first.txt"},
+	}
+	s.second = &models.SpecConfig{
+		Type:   models.StorageSourceURL,
+		Params: map[string]interface{}{"URL": "https://example.com/second.txt"},
+	}
+}
+
+func (s *InputCacheNodeRankerSuite) node(id string, cached ...*models.SpecConfig) models.NodeInfo {
+	node := models.NodeInfo{NodeID: id}
+	for _, source := range cached {
+		node.ComputeNodeInfo.CachedInputs = append(node.ComputeNodeInfo.CachedInputs, inputcache.SourceID(source))
+	}
+	return node
+}
+
+func (s *InputCacheNodeRankerSuite) TestRankNodes() {
+	job := mock.Job()
+	job.Task().InputSources = []*models.InputSource{
+		{Source: s.first, Target: "/inputs/first"},
+		{Source: s.second, Target: "/inputs/second"},
+	}
+	nodes := []models.NodeInfo{
+		s.node("cold"),
+		s.node("warm", s.first),
+		s.node("hot", s.first, s.second),
+	}
+	ranks, err := s.ranker.RankNodes(context.Background(), *job, nodes)
+	s.Require().NoError(err)
+	s.Len(ranks, len(nodes))
+	assertEquals(s.T(), ranks, "cold", 0, "no inputs cached")
+	assertEquals(s.T(), ranks, "warm", 15, "1 of 2 inputs cached")
+	assertEquals(s.T(), ranks, "hot", 30, "2 of 2 inputs cached")
+}
+
+func (s *InputCacheNodeRankerSuite) TestRankNodes_NoInputs() {
+	job := mock.Job()
+	job.Task().InputSources = nil
+	ranks, err := s.ranker.RankNodes(context.Background(), *job, []models.NodeInfo{s.node("hot", s.first)})
+	s.Require().NoError(err)
+	assertEquals(s.T(), ranks, "hot", 0)
+}
@@ -44,6 +44,14 @@ func (n NodeSelector) AllNodes(ctx context.Context) ([]models.NodeInfo, error) {
 	return nodeInfos, nil
 }
 
+func (n NodeSelector) DrainingNodes(ctx context.Context) ([]string, error) {
+	nodeStates, err := n.discoverer.List(ctx, nodes.DrainingNodeFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list draining nodes: %w", err)
+	}
+	return lo.Map(nodeStates, func(ns models.NodeState, _ int) string { return ns.Info.ID() }), nil
+}
+
 func (n NodeSelector) MatchingNodes(
 	ctx context.Context,
 	job *models.Job,
@@ -72,6 +80,7 @@ func (n NodeSelector) rankAndFilterNodes(
 	// - compute nodes
 	// - approved to executor jobs
 	// - connected (alive)
+	// - not cordoned or draining
 	nodeStates := lo.Filter(listed, func(nodeState models.NodeState, index int) bool {
 		if nodeState.Info.NodeType != models.NodeTypeCompute {
 			return false
@@ -85,7 +94,7 @@ func (n NodeSelector) rankAndFilterNodes(
 			return false
 		}
 
-		return true
+		return nodes.SchedulableNodeFilter(nodeState)
 	})
 
 	// extract the nodeInfo from the slice of node states for ranking
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
 	wasmmodels "github.com/bacalhau-project/bacalhau/pkg/executor/wasm/models"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 )
@@ -33,8 +34,8 @@ func (t *LegacyWasmModuleTransformer) Transform(ctx context.Context, job *models
 	}
 
 	// First try to decode as new format
-	spec, err := wasmmodels.DecodeSpec(job.Task().Engine)
-	if err == nil && spec.EntryModule != "" {
+	spec, specErr := wasmmodels.DecodeSpec(job.Task().Engine)
+	if specErr == nil && spec.EntryModule != "" {
 		return nil // Already in new format, nothing to do
 	}
 
@@ -46,6 +47,10 @@ func (t *LegacyWasmModuleTransformer) Transform(ctx context.Context, job *models
 
 	var legacy legacySpec
 	if err = json.Unmarshal(paramsBytes, &legacy); err != nil {
+		// Not in legacy format either, so reject the invalid spec at submission
+		if specErr != nil {
+			return bacerrors.Wrap(specErr, "invalid wasm engine spec").WithCode(bacerrors.ValidationError)
+		}
 		return err
 	}
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
 	wasmmodels "github.com/bacalhau-project/bacalhau/pkg/executor/wasm/models"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	storage_url "github.com/bacalhau-project/bacalhau/pkg/storage/url/urldownload"
@@ -72,6 +73,22 @@ func (s *LegacyWasmModuleTransformerSuite) TestIgnoresNewFormatJobs() {
 	s.Require().Equal([]string{"--arg1", "value1"}, job.Task().Engine.Params["Parameters"])
 }
 
+func (s *LegacyWasmModuleTransformerSuite) TestRejectsInvalidSpec() {
+	job := &models.Job{
+		Tasks: []*models.Task{
+			{
+				Engine: wasmmodels.NewWasmEngineBuilder("/entry.wasm").MustBuild(),
+			},
+		},
+	}
+	job.Task().Engine.Params["KeyValueScope"] = "cluster"
+
+	err := s.transformer.Transform(s.ctx, job)
+	s.Require().Error(err)
+	s.True(bacerrors.IsErrorWithCode(err, bacerrors.ValidationError))
+	s.ErrorContains(err, "invalid wasm engine key-value scope")
+}
+
 func (s *LegacyWasmModuleTransformerSuite) TestTransformsLegacyFormat() {
 	// Create a job with legacy format
 	entryModuleSpec, err := storage_url.NewSpecConfig("https://example.com/entry.wasm")
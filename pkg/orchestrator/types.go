@@ -1,6 +1,8 @@
 package orchestrator
 
 import (
+	"io"
+
 	"github.com/rs/zerolog"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -63,6 +65,17 @@ type ReadLogsRequest struct {
 	Follow         bool
 }
 
+type ExecRequest struct {
+	JobID     string
+	Namespace string
+	// ExecutionID is the execution to run the command in. Optional if the job has a single running execution.
+	ExecutionID string
+	// Command to run inside the execution. Attaches to the execution's main process if empty.
+	Command []string
+	// Stdin is the input of the command, if any
+	Stdin io.Reader
+}
+
 type ReadLogsResponse struct {
 	Address           string
 	ExecutionComplete bool
@@ -71,6 +84,8 @@ type ReadLogsResponse struct {
 type GetResultsRequest struct {
 	JobID     string
 	Namespace string
+	// Partial also returns the results published so far by executions that did not complete yet
+	Partial bool
 }
 
 type GetResultsResponse struct {
@@ -0,0 +1,375 @@
+package watchers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/backoff"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/watcher"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+const (
+	// WebhookSignatureHeader holds the HMAC-SHA256 signature of the payload, formatted as sha256=<hex digest>
+	WebhookSignatureHeader = "X-Bacalhau-Signature"
+	// WebhookEventHeader holds the type of event the notification is about
+	WebhookEventHeader = "X-Bacalhau-Event"
+	// WebhookDeliveryHeader holds the ID of the notification, which is the same across delivery retries
+	WebhookDeliveryHeader = "X-Bacalhau-Delivery"
+
+	defaultWebhookMaxAttempts = 5
+	defaultWebhookTimeout     = 10 * time.Second
+
+	// maxWebhookResponseSize is the maximum size of a response body read from a webhook
+	maxWebhookResponseSize = 64 * 1024
+
+	webhookDialTimeout   = 30 * time.Second
+	webhookDialKeepAlive = 30 * time.Second
+)
+
+// errWebhookAddressNotAllowed is returned when a job's webhook connects to an address that is not public
+var errWebhookAddressNotAllowed = errors.New(
+	"webhook address is not allowed. webhooks of jobs cannot be loopback, link-local or private addresses")
+
+// nonPublicPrefixes are the IPv4 ranges that are neither public nor covered by the checks of netip.Addr
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // this network
+	netip.MustParsePrefix("100.64.0.0/10"), // shared address space of carrier-grade NATs
+}
+
+// WebhookNotifierParams holds the dependencies of a WebhookNotifier
+type WebhookNotifierParams struct {
+	JobStore jobstore.Store
+	// NamespaceWebhooks are notified of the transitions of all jobs in their namespace,
+	// in addition to the webhooks declared by the jobs.
+	NamespaceWebhooks map[string][]models.Webhook
+	// SigningKey signs the payloads if set
+	SigningKey string
+	// MaxAttempts is the maximum number of attempts to deliver a notification
+	MaxAttempts int
+	// Timeout is the timeout of a single delivery attempt
+	Timeout time.Duration
+	// Backoff is the backoff between delivery attempts
+	Backoff    backoff.Backoff
+	HTTPClient *http.Client
+	// AllowPrivateAddresses allows the webhooks of jobs to connect to loopback, link-local and private addresses.
+	// Otherwise, only the namespace webhooks configured by the operator can, so that jobs cannot make the
+	// orchestrator send requests to its internal network, such as cloud metadata endpoints.
+	AllowPrivateAddresses bool
+}
+
+// WebhookNotifier handles job and execution events, and notifies webhooks of job state changes
+// and execution failures. Notifications are delivered in the background so that slow endpoints
+// don't block the event pipeline, which means notifications of a job can arrive out of order.
+// The outcome of each delivery is recorded in the job's history.
+type WebhookNotifier struct {
+	jobStore          jobstore.Store
+	namespaceWebhooks map[string][]models.Webhook
+	signingKey        []byte
+	maxAttempts       int
+	timeout           time.Duration
+	backoff           backoff.Backoff
+	client            *http.Client
+	// jobClient delivers the notifications of the webhooks declared by jobs
+	jobClient *http.Client
+
+	// ctx is cancelled to stop pending deliveries
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWebhookNotifier creates a new WebhookNotifier
+func NewWebhookNotifier(params WebhookNotifierParams) *WebhookNotifier {
+	if params.MaxAttempts <= 0 {
+		params.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if params.Timeout <= 0 {
+		params.Timeout = defaultWebhookTimeout
+	}
+	if params.Backoff == nil {
+		params.Backoff = backoff.NewExponential(time.Second, time.Minute)
+	}
+	if params.HTTPClient == nil {
+		params.HTTPClient = &http.Client{}
+	}
+	jobClient := params.HTTPClient
+	if !params.AllowPrivateAddresses {
+		publicClient := *params.HTTPClient
+		publicClient.Transport = newPublicWebhookTransport()
+		jobClient = &publicClient
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookNotifier{
+		jobStore:          params.JobStore,
+		namespaceWebhooks: params.NamespaceWebhooks,
+		signingKey:        []byte(params.SigningKey),
+		maxAttempts:       params.MaxAttempts,
+		timeout:           params.Timeout,
+		backoff:           params.Backoff,
+		client:            params.HTTPClient,
+		jobClient:         jobClient,
+		ctx:               ctx,
+		cancel:            cancel,
+	}
+}
+
+// HandleEvent notifies webhooks of the job lifecycle transition described by the event, if any
+func (n *WebhookNotifier) HandleEvent(ctx context.Context, event watcher.Event) error {
+	switch upsert := event.Object.(type) {
+	case models.JobUpsert:
+		n.handleJobUpsert(event, upsert)
+	case models.ExecutionUpsert:
+		return n.handleExecutionUpsert(ctx, event, upsert)
+	}
+	return nil
+}
+
+// Stop cancels pending deliveries and waits for in-flight ones to return
+func (n *WebhookNotifier) Stop(ctx context.Context) error {
+	n.cancel()
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (n *WebhookNotifier) handleJobUpsert(event watcher.Event, upsert models.JobUpsert) {
+	job := upsert.Current
+	if job == nil || !upsert.HasStateChange() {
+		return
+	}
+	payload := newWebhookPayload(models.WebhookEventJobStateChanged, event, job)
+	payload.JobVersion = job.Version
+	payload.Message = job.State.Message
+	if upsert.Previous != nil {
+		payload.PreviousJobState = upsert.Previous.State.StateType
+	}
+	n.notify(event, job, payload, func(webhook *models.Webhook) bool {
+		return webhook.NotifiesJobState(job.State.StateType)
+	})
+}
+
+func (n *WebhookNotifier) handleExecutionUpsert(ctx context.Context, event watcher.Event, upsert models.ExecutionUpsert) error {
+	execution := upsert.Current
+	if execution == nil || execution.ComputeState.StateType != models.ExecutionStateFailed {
+		return nil
+	}
+	if upsert.Previous != nil && upsert.Previous.ComputeState.StateType == models.ExecutionStateFailed {
+		return nil
+	}
+
+	job := execution.Job
+	if job == nil {
+		storedJob, err := n.jobStore.GetJob(ctx, execution.JobID)
+		if err != nil {
+			if bacerrors.IsErrorWithCode(err, bacerrors.NotFoundError) {
+				return nil // the job has been deleted since
+			}
+			return err
+		}
+		job = &storedJob
+	}
+
+	payload := newWebhookPayload(models.WebhookEventExecutionFailed, event, job)
+	payload.JobVersion = execution.JobVersion
+	payload.ExecutionID = execution.ID
+	payload.NodeID = execution.NodeID
+	payload.Message = execution.ComputeState.Message
+	n.notify(event, job, payload, (*models.Webhook).NotifiesExecutionFailure)
+	return nil
+}
+
+func newWebhookPayload(eventType models.WebhookEventType, event watcher.Event, job *models.Job) models.WebhookPayload {
+	return models.WebhookPayload{
+		Event:     eventType,
+		Timestamp: event.Timestamp,
+		JobID:     job.ID,
+		JobName:   job.Name,
+		Namespace: job.Namespace,
+		JobState:  job.State.StateType,
+	}
+}
+
+// notify starts the delivery of the payload to the job's and its namespace's webhooks matching the filter
+func (n *WebhookNotifier) notify(
+	event watcher.Event, job *models.Job, payload models.WebhookPayload, filter func(*models.Webhook) bool) {
+	webhooks := job.Webhooks
+	for i := range n.namespaceWebhooks[job.Namespace] {
+		webhooks = append(webhooks, &n.namespaceWebhooks[job.Namespace][i])
+	}
+
+	for i, webhook := range webhooks {
+		if !filter(webhook) {
+			continue
+		}
+		client := n.client
+		if i < len(job.Webhooks) {
+			client = n.jobClient
+		}
+		webhookPayload := payload
+		webhookPayload.ID = fmt.Sprintf("%d-%d", event.SeqNum, i)
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			n.deliver(client, webhook.URL, webhookPayload)
+		}()
+	}
+}
+
+// deliver posts the payload to the webhook, retrying with backoff on transient failures,
+// and records the outcome in the job's history
+func (n *WebhookNotifier) deliver(client *http.Client, url string, payload models.WebhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Error().Err(err).Str("JobID", payload.JobID).Msg("failed to marshal webhook payload")
+		return
+	}
+
+	attempts := 0
+	for {
+		attempts++
+		err = n.post(client, url, payload, body)
+		if err == nil || attempts >= n.maxAttempts || !isRetryableWebhookError(err) {
+			break
+		}
+		n.backoff.Backoff(n.ctx, attempts)
+		if n.ctx.Err() != nil {
+			return
+		}
+	}
+	if n.ctx.Err() != nil {
+		return
+	}
+
+	historyEvent := orchestrator.WebhookDeliveredEvent(payload.Event, url, attempts)
+	if err != nil {
+		log.Warn().Err(err).Str("JobID", payload.JobID).Str("URL", url).
+			Msgf("failed to deliver %s webhook notification after %d attempts", payload.Event, attempts)
+		historyEvent = orchestrator.WebhookFailedEvent(payload.Event, url, attempts, err)
+	}
+	if err = n.jobStore.AddJobHistory(n.ctx, payload.JobID, payload.JobVersion, historyEvent); err != nil {
+		log.Warn().Err(err).Str("JobID", payload.JobID).Msg("failed to record webhook delivery in job history")
+	}
+}
+
+// post makes a single delivery attempt of the payload to the webhook
+func (n *WebhookNotifier) post(client *http.Client, url string, payload models.WebhookPayload, body []byte) error {
+	ctx, cancel := context.WithTimeout(n.ctx, n.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(payload.Event))
+	req.Header.Set(WebhookDeliveryHeader, payload.ID)
+	if len(n.signingKey) > 0 {
+		req.Header.Set(WebhookSignatureHeader, signWebhookPayload(n.signingKey, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookResponseSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &webhookStatusError{statusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// signWebhookPayload returns the HMAC-SHA256 signature of the body, formatted as sha256=<hex digest>
+func signWebhookPayload(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookStatusError is returned when a webhook responds with a non-success status
+type webhookStatusError struct {
+	statusCode int
+}
+
+func (e *webhookStatusError) Error() string {
+	return fmt.Sprintf("webhook responded with status %d", e.statusCode)
+}
+
+// newPublicWebhookTransport returns a transport that only connects to public addresses. The addresses are checked
+// when connecting, after the host of the webhook is resolved, so that hosts resolving to a different address
+// than when validated cannot bypass the check. Proxies are not used, as the check must apply to the webhook.
+func newPublicWebhookTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   webhookDialTimeout,
+		KeepAlive: webhookDialKeepAlive,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !isPublicAddress(addrPort.Addr()) {
+				return errWebhookAddressNotAllowed
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+// isPublicAddress returns whether the address is a public unicast address, and not a loopback, link-local,
+// private or otherwise reserved address
+func isPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// isRetryableWebhookError returns true if the delivery can succeed on a later attempt.
+// Client errors other than timeouts and rate limiting, and addresses that are not allowed, are not retried.
+func isRetryableWebhookError(err error) bool {
+	if errors.Is(err, errWebhookAddressNotAllowed) {
+		return false
+	}
+	var statusErr *webhookStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode >= http.StatusInternalServerError ||
+			statusErr.statusCode == http.StatusRequestTimeout ||
+			statusErr.statusCode == http.StatusTooManyRequests
+	}
+	return true
+}
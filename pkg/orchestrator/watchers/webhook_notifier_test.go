@@ -0,0 +1,323 @@
+//go:build unit || !integration
+
+package watchers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/backoff"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/watcher"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+const testSigningKey = "test-signing-key"
+
+// webhookRequest is a notification received by the test server
+type webhookRequest struct {
+	header  http.Header
+	body    []byte
+	payload models.WebhookPayload
+}
+
+type WebhookNotifierSuite struct {
+	suite.Suite
+	ctx      context.Context
+	ctrl     *gomock.Controller
+	jobStore *jobstore.MockStore
+	server   *httptest.Server
+	// statuses are the response status codes of the test server, in order.
+	// The last status is returned once exhausted.
+	statuses  []int
+	calls     atomic.Int32
+	requests  chan webhookRequest
+	histories chan models.Event
+	notifier  *WebhookNotifier
+}
+
+func TestWebhookNotifierSuite(t *testing.T) {
+	suite.Run(t, new(WebhookNotifierSuite))
+}
+
+func (s *WebhookNotifierSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.ctrl = gomock.NewController(s.T())
+	s.jobStore = jobstore.NewMockStore(s.ctrl)
+	s.statuses = []int{http.StatusOK}
+	s.calls.Store(0)
+	s.requests = make(chan webhookRequest, 10)
+	s.histories = make(chan models.Event, 10)
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		s.Require().NoError(err)
+		request := webhookRequest{header: r.Header, body: body}
+		s.Require().NoError(json.Unmarshal(body, &request.payload))
+		s.requests <- request
+
+		call := int(s.calls.Add(1)) - 1
+		w.WriteHeader(s.statuses[min(call, len(s.statuses)-1)])
+	}))
+
+	s.jobStore.EXPECT().AddJobHistory(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ uint64, events ...models.Event) error {
+			for _, event := range events {
+				s.histories <- event
+			}
+			return nil
+		}).AnyTimes()
+
+	s.notifier = NewWebhookNotifier(WebhookNotifierParams{
+		JobStore:    s.jobStore,
+		SigningKey:  testSigningKey,
+		MaxAttempts: 3,
+		Timeout:     time.Second,
+		Backoff:     backoff.NewNoop(),
+		// the test server listens on a loopback address
+		AllowPrivateAddresses: true,
+	})
+}
+
+func (s *WebhookNotifierSuite) TearDownTest() {
+	s.Require().NoError(s.notifier.Stop(s.ctx))
+	s.server.Close()
+}
+
+func (s *WebhookNotifierSuite) TestJobStateChanged() {
+	upsert := s.jobTransition(models.JobStateTypeRunning, models.JobStateTypeCompleted)
+	upsert.Current.Webhooks = []*models.Webhook{{URL: s.server.URL}}
+
+	s.Require().NoError(s.notifier.HandleEvent(s.ctx, watcher.Event{SeqNum: 7, Object: upsert}))
+
+	request := s.nextRequest()
+	s.Equal("7-0", request.payload.ID)
+	s.Equal(models.WebhookEventJobStateChanged, request.payload.Event)
+	s.Equal(upsert.Current.ID, request.payload.JobID)
+	s.Equal(models.JobStateTypeCompleted, request.payload.JobState)
+	s.Equal(models.JobStateTypeRunning, request.payload.PreviousJobState)
+	s.Equal(string(models.WebhookEventJobStateChanged), request.header.Get(WebhookEventHeader))
+	s.Equal("7-0", request.header.Get(WebhookDeliveryHeader))
+	s.Equal(signWebhookPayload([]byte(testSigningKey), request.body), request.header.Get(WebhookSignatureHeader))
+
+	history := s.nextHistory()
+	s.Equal(orchestrator.EventTopicWebhook, history.Topic)
+	s.Equal("1", history.Details["Attempts"])
+}
+
+func (s *WebhookNotifierSuite) TestJobStateFilter() {
+	upsert := s.jobTransition(models.JobStateTypePending, models.JobStateTypeRunning)
+	upsert.Current.Webhooks = []*models.Webhook{{
+		URL:       s.server.URL,
+		JobStates: []models.JobStateType{models.JobStateTypeCompleted, models.JobStateTypeFailed},
+	}}
+	s.Require().NoError(s.notifier.HandleEvent(s.ctx, watcher.Event{Object: upsert}))
+
+	// a job update without a state change is not notified either
+	upsert = s.jobTransition(models.JobStateTypeRunning, models.JobStateTypeRunning)
+	upsert.Current.Webhooks = []*models.Webhook{{URL: s.server.URL}}
+	s.Require().NoError(s.notifier.HandleEvent(s.ctx, watcher.Event{Object: upsert}))
+
+	s.Require().NoError(s.notifier.Stop(s.ctx))
+	s.Empty(s.requests)
+}
+
+func (s *WebhookNotifierSuite) TestNamespaceWebhooks() {
+	s.notifier = NewWebhookNotifier(WebhookNotifierParams{
+		JobStore: s.jobStore,
+		NamespaceWebhooks: map[string][]models.Webhook{
+			models.DefaultNamespace: {{URL: s.server.URL}},
+			"other":                 {{URL: s.server.URL + "/other"}},
+		},
+		Backoff: backoff.NewNoop(),
+	})
+
+	upsert := s.jobTransition(models.JobStateTypeRunning, models.JobStateTypeFailed)
+	s.Require().NoError(s.notifier.HandleEvent(s.ctx, watcher.Event{Object: upsert}))
+
+	request := s.nextRequest()
+	s.Equal(models.JobStateTypeFailed, request.payload.JobState)
+	s.Empty(request.header.Get(WebhookSignatureHeader), "payloads are only signed when a key is configured")
+	s.nextHistory()
+	s.Empty(s.requests)
+}
+
+func (s *WebhookNotifierSuite) TestJobWebhookPrivateAddress() {
+	s.notifier = NewWebhookNotifier(WebhookNotifierParams{
+		JobStore: s.jobStore,
+		NamespaceWebhooks: map[string][]models.Webhook{
+			models.DefaultNamespace: {{URL: s.server.URL + "/namespace"}},
+		},
+		MaxAttempts: 3,
+		Backoff:     backoff.NewNoop(),
+	})
+
+	upsert := s.jobTransition(models.JobStateTypeRunning, models.JobStateTypeCompleted)
+	upsert.Current.Webhooks = []*models.Webhook{{URL: s.server.URL + "/job"}}
+	s.Require().NoError(s.notifier.HandleEvent(s.ctx, watcher.Event{Object: upsert}))
+
+	// only the namespace webhook configured by the operator reaches the loopback address
+	s.nextRequest()
+	s.Equal(int32(1), s.calls.Load())
+
+	histories := map[string]models.Event{}
+	for range 2 {
+		history := s.nextHistory()
+		histories[history.Details["URL"]] = history
+	}
+	failed := histories[s.server.URL+"/job"]
+	s.Equal("1", failed.Details["Attempts"], "addresses that are not allowed are not retried")
+	s.Contains(failed.Message, errWebhookAddressNotAllowed.Error())
+	s.Empty(s.requests)
+}
+
+func TestIsPublicAddress(t *testing.T) {
+	for address, public := range map[string]bool{
+		"8.8.8.8":         true,
+		"2001:4860::8888": true,
+		"127.0.0.1":       false,
+		"::1":             false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"224.0.0.1":       false,
+		"::ffff:10.0.0.1": false,
+	} {
+		assert.Equal(t, public, isPublicAddress(netip.MustParseAddr(address)), address)
+	}
+}
+
+func (s *WebhookNotifierSuite) TestExecutionFailed() {
+	upsert := setupStateTransition(
+		models.ExecutionDesiredStateRunning, models.ExecutionStateRunning,
+		models.ExecutionDesiredStateStopped, models.ExecutionStateFailed)
+	upsert.Current.ComputeState.Message = "out of memory"
+	upsert.Current.Job = nil
+
+	job := mock.Job()
+	job.ID = upsert.Current.JobID
+	job.Webhooks = []*models.Webhook{
+		{URL: s.server.URL, Events: []models.WebhookEventType{models.WebhookEventExecutionFailed}},
+		{URL: s.server.URL, Events: []models.WebhookEventType{models.WebhookEventJobStateChanged}},
+	}
+	s.jobStore.EXPECT().GetJob(s.ctx, job.ID).Return(*job, nil)
+
+	s.Require().NoError(s.notifier.HandleEvent(s.ctx, watcher.Event{Object: upsert}))
+
+	request := s.nextRequest()
+	s.Equal(models.WebhookEventExecutionFailed, request.payload.Event)
+	s.Equal(upsert.Current.ID, request.payload.ExecutionID)
+	s.Equal(upsert.Current.NodeID, request.payload.NodeID)
+	s.Equal("out of memory", request.payload.Message)
+	s.nextHistory()
+	s.Empty(s.requests)
+}
+
+func (s *WebhookNotifierSuite) TestExecutionFailedJobDeleted() {
+	upsert := setupNewExecution(models.ExecutionDesiredStateStopped, models.ExecutionStateFailed)
+	upsert.Current.Job = nil
+	s.jobStore.EXPECT().GetJob(s.ctx, upsert.Current.JobID).
+		Return(models.Job{}, bacerrors.New("not found").WithCode(bacerrors.NotFoundError))
+
+	s.NoError(s.notifier.HandleEvent(s.ctx, watcher.Event{Object: upsert}))
+}
+
+func (s *WebhookNotifierSuite) TestExecutionNotFailed() {
+	upsert := setupStateTransition(
+		models.ExecutionDesiredStateRunning, models.ExecutionStateRunning,
+		models.ExecutionDesiredStateStopped, models.ExecutionStateCompleted)
+	upsert.Current.Job.Webhooks = []*models.Webhook{{URL: s.server.URL}}
+
+	s.Require().NoError(s.notifier.HandleEvent(s.ctx, watcher.Event{Object: upsert}))
+	s.Require().NoError(s.notifier.Stop(s.ctx))
+	s.Empty(s.requests)
+}
+
+func (s *WebhookNotifierSuite) TestRetryOnServerError() {
+	s.statuses = []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK}
+	upsert := s.jobTransition(models.JobStateTypeRunning, models.JobStateTypeCompleted)
+	upsert.Current.Webhooks = []*models.Webhook{{URL: s.server.URL}}
+
+	s.Require().NoError(s.notifier.HandleEvent(s.ctx, watcher.Event{SeqNum: 3, Object: upsert}))
+
+	for i := 0; i < 3; i++ {
+		s.Equal("3-0", s.nextRequest().payload.ID, "the notification ID is stable across retries")
+	}
+	history := s.nextHistory()
+	s.Equal(orchestrator.WebhookDeliveredEvent(models.WebhookEventJobStateChanged, s.server.URL, 3).Message,
+		history.Message)
+	s.Equal("3", history.Details["Attempts"])
+}
+
+func (s *WebhookNotifierSuite) TestGiveUpAfterMaxAttempts() {
+	s.statuses = []int{http.StatusServiceUnavailable}
+	upsert := s.jobTransition(models.JobStateTypeRunning, models.JobStateTypeCompleted)
+	upsert.Current.Webhooks = []*models.Webhook{{URL: s.server.URL}}
+
+	s.Require().NoError(s.notifier.HandleEvent(s.ctx, watcher.Event{Object: upsert}))
+
+	history := s.nextHistory()
+	s.Equal("3", history.Details["Attempts"])
+	s.Contains(history.Message, "status 503")
+	s.Len(s.requests, 3)
+}
+
+func (s *WebhookNotifierSuite) TestNoRetryOnClientError() {
+	s.statuses = []int{http.StatusBadRequest, http.StatusOK}
+	upsert := s.jobTransition(models.JobStateTypeRunning, models.JobStateTypeCompleted)
+	upsert.Current.Webhooks = []*models.Webhook{{URL: s.server.URL}}
+
+	s.Require().NoError(s.notifier.HandleEvent(s.ctx, watcher.Event{Object: upsert}))
+
+	history := s.nextHistory()
+	s.Equal("1", history.Details["Attempts"])
+	s.Len(s.requests, 1)
+}
+
+// jobTransition creates an upsert of a job transitioning between the given states
+func (s *WebhookNotifierSuite) jobTransition(from, to models.JobStateType) models.JobUpsert {
+	previous := mock.Job()
+	previous.State = models.NewJobState(from)
+	current := previous.Copy()
+	current.State = models.NewJobState(to)
+	return models.JobUpsert{Previous: previous, Current: current}
+}
+
+func (s *WebhookNotifierSuite) nextRequest() webhookRequest {
+	select {
+	case request := <-s.requests:
+		return request
+	case <-time.After(5 * time.Second):
+		s.FailNow("timed out waiting for a webhook notification")
+	}
+	return webhookRequest{}
+}
+
+func (s *WebhookNotifierSuite) nextHistory() models.Event {
+	select {
+	case event := <-s.histories:
+		return event
+	case <-time.After(5 * time.Second):
+		s.FailNow("timed out waiting for the webhook delivery to be recorded")
+	}
+	return models.Event{}
+}
@@ -34,6 +34,12 @@ type GetAgentNodeResponse struct {
 	*models.NodeInfo
 }
 
+// GetAgentQueueResponse is the response to the request to get the executions queued on the agent node.
+type GetAgentQueueResponse struct {
+	BaseGetResponse
+	Executions []models.QueuedExecution `json:"Executions"`
+}
+
 type GetAgentConfigResponse struct {
 	BaseGetResponse
 	Config types.Bacalhau `json:"config"`
@@ -0,0 +1,74 @@
+package apimodels
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// EventObjectType is the type of object changed by a streamed event
+type EventObjectType string
+
+const (
+	EventObjectJob       EventObjectType = "job"
+	EventObjectExecution EventObjectType = "execution"
+)
+
+// EventOperation is the operation performed on the object of a streamed event
+type EventOperation string
+
+const (
+	EventOperationCreate EventOperation = "CREATE"
+	EventOperationUpdate EventOperation = "UPDATE"
+	EventOperationDelete EventOperation = "DELETE"
+)
+
+// StreamEventsRequest is the request to stream job and execution changes.
+// Without a sequence number to resume after, only changes made after the stream
+// is opened are returned.
+type StreamEventsRequest struct {
+	BaseGetRequest
+	// JobID limits the events to a single job, referenced by ID or name
+	JobID string `query:"job_id"`
+	// ObjectTypes limits the events to the given object types. All types are returned if empty.
+	ObjectTypes []EventObjectType `query:"object_types"`
+	// Operations limits the events to the given operations. All operations are returned if empty.
+	Operations []EventOperation `query:"operations"`
+	// After resumes the stream after the event with this sequence number
+	After uint64 `query:"after"`
+}
+
+// ToHTTPRequest is used to convert the request to an HTTP request
+func (o *StreamEventsRequest) ToHTTPRequest() *HTTPRequest {
+	r := o.BaseGetRequest.ToHTTPRequest()
+
+	if o.JobID != "" {
+		r.Params.Set("job_id", o.JobID)
+	}
+	for _, objectType := range o.ObjectTypes {
+		r.Params.Add("object_types", string(objectType))
+	}
+	for _, operation := range o.Operations {
+		r.Params.Add("operations", string(operation))
+	}
+	if o.After != 0 {
+		r.Params.Set("after", strconv.FormatUint(o.After, 10))
+	}
+	return r
+}
+
+// Event is a change to a job or an execution streamed by the orchestrator
+type Event struct {
+	// SeqNum is the sequence number of the event, which can be used to resume the stream
+	SeqNum     uint64          `json:"SeqNum"`
+	Operation  EventOperation  `json:"Operation"`
+	ObjectType EventObjectType `json:"ObjectType"`
+	Timestamp  time.Time       `json:"Timestamp"`
+	// Job is the job after the change, or before its deletion
+	Job *models.Job `json:"Job,omitempty"`
+	// Execution is the execution after the change
+	Execution *models.Execution `json:"Execution,omitempty"`
+	// Events describe the execution change, if any
+	Events []*models.Event `json:"Events,omitempty"`
+}
@@ -176,6 +176,18 @@ type ListJobVersionsResponse struct {
 type ListJobResultsRequest struct {
 	BaseListRequest
 	JobID string `query:"-"`
+	// Partial also returns the results published so far by executions that did not complete yet
+	Partial bool `query:"partial"`
+}
+
+// ToHTTPRequest is used to convert the request to an HTTP request
+func (o *ListJobResultsRequest) ToHTTPRequest() *HTTPRequest {
+	r := o.BaseListRequest.ToHTTPRequest()
+
+	if o.Partial {
+		r.Params.Set("partial", "true")
+	}
+	return r
 }
 
 type ListJobResultsResponse struct {
@@ -183,6 +195,16 @@ type ListJobResultsResponse struct {
 	Items []*models.SpecConfig `json:"Items"`
 }
 
+type GetJobRecommendationsRequest struct {
+	BaseGetRequest
+	JobIDOrName string `query:"-"`
+}
+
+type GetJobRecommendationsResponse struct {
+	BaseGetResponse
+	Recommendation *models.ResourceRecommendation `json:"Recommendation"`
+}
+
 type StopJobRequest struct {
 	BasePutRequest
 	JobID  string `json:"-"`
@@ -209,6 +231,33 @@ type RerunJobResponse struct {
 	Warnings     []string `json:"Warnings"`
 }
 
+// ExecJobRequest runs a command inside a running execution of a job,
+// or attaches to the main process of the execution if no command is given
+type ExecJobRequest struct {
+	BaseGetRequest
+	JobID       string   `query:"-"`
+	ExecutionID string   `query:"execution_id" validate:"omitempty"`
+	Command     []string `query:"command" validate:"omitempty"`
+	// Stdin forwards the input sent over the connection to the command
+	Stdin bool `query:"stdin"`
+}
+
+// ToHTTPRequest is used to convert the request to an HTTP request
+func (o *ExecJobRequest) ToHTTPRequest() *HTTPRequest {
+	r := o.BaseGetRequest.ToHTTPRequest()
+
+	if o.ExecutionID != "" {
+		r.Params.Set("execution_id", o.ExecutionID)
+	}
+	for _, arg := range o.Command {
+		r.Params.Add("command", arg)
+	}
+	if o.Stdin {
+		r.Params.Set("stdin", "true")
+	}
+	return r
+}
+
 type GetLogsRequest struct {
 	BaseGetRequest
 	JobID          string `query:"-"`
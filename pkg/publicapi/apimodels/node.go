@@ -14,6 +14,8 @@ type GetNodeRequest struct {
 type GetNodeResponse struct {
 	BaseGetResponse
 	Node *models.NodeState `json:"Node"`
+	// Drain is the progress of draining the node, and is only set for draining nodes
+	Drain *NodeDrainStatus `json:"Drain,omitempty"`
 }
 
 type ListNodesRequest struct {
@@ -58,14 +60,27 @@ type PutNodeResponse struct {
 	BasePutResponse
 	Success bool   `json:"Success"`
 	Error   string `json:"Error,omitempty"`
+	// Drain is the progress of draining the node, and is only set for drain actions
+	Drain *NodeDrainStatus `json:"Drain,omitempty"`
+}
+
+// NodeDrainStatus reports the progress of draining a node
+type NodeDrainStatus struct {
+	// RemainingExecutions is the number of executions still active on the node
+	RemainingExecutions int `json:"RemainingExecutions"`
+	// Complete is true once no executions are left on the node
+	Complete bool `json:"Complete"`
 }
 
 type NodeAction string
 
 const (
-	NodeActionApprove NodeAction = "approve"
-	NodeActionReject  NodeAction = "reject"
-	NodeActionDelete  NodeAction = "delete"
+	NodeActionApprove  NodeAction = "approve"
+	NodeActionReject   NodeAction = "reject"
+	NodeActionDelete   NodeAction = "delete"
+	NodeActionCordon   NodeAction = "cordon"
+	NodeActionUncordon NodeAction = "uncordon"
+	NodeActionDrain    NodeAction = "drain"
 )
 
 func (n NodeAction) Description() string {
@@ -76,10 +91,21 @@ func (n NodeAction) Description() string {
 		return "Reject a node whose membership is pending"
 	case NodeActionDelete:
 		return "Delete a node from the cluster."
+	case NodeActionCordon:
+		return "Stop new executions from being scheduled on a node"
+	case NodeActionUncordon:
+		return "Allow new executions to be scheduled on a cordoned or draining node"
+	case NodeActionDrain:
+		return "Cordon a node and move its service and daemon executions to other nodes"
 	}
 	return ""
 }
 
 func (n NodeAction) IsValid() bool {
-	return n == NodeActionApprove || n == NodeActionReject || n == NodeActionDelete
+	switch n {
+	case NodeActionApprove, NodeActionReject, NodeActionDelete,
+		NodeActionCordon, NodeActionUncordon, NodeActionDrain:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,51 @@
+package apimodels
+
+import (
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type GetNamespaceQuotaRequest struct {
+	BaseGetRequest
+	// Name of the namespace
+	Name string `query:"-"`
+}
+
+type GetNamespaceQuotaResponse struct {
+	BaseGetResponse
+	Quota *models.NamespaceQuota `json:"Quota"`
+	Usage models.NamespaceUsage  `json:"Usage"`
+}
+
+type ListNamespaceQuotasRequest struct {
+	BaseListRequest
+}
+
+type ListNamespaceQuotasResponse struct {
+	BaseListResponse
+	Quotas []*models.NamespaceQuota `json:"Quotas"`
+}
+
+type PutNamespaceQuotaRequest struct {
+	BasePutRequest
+	Quota *models.NamespaceQuota `json:"Quota"`
+}
+
+// Validate is used to validate fields in the PutNamespaceQuotaRequest.
+func (r *PutNamespaceQuotaRequest) Validate() error {
+	return r.Quota.Validate()
+}
+
+type PutNamespaceQuotaResponse struct {
+	BasePutResponse
+	Quota *models.NamespaceQuota `json:"Quota"`
+}
+
+type DeleteNamespaceQuotaRequest struct {
+	BasePutRequest
+	// Name of the namespace
+	Name string `json:"-"`
+}
+
+type DeleteNamespaceQuotaResponse struct {
+	BasePutResponse
+}
@@ -6,7 +6,9 @@ package client
 type API interface {
 	Agent() *Agent
 	Auth() *Auth
+	Events() *Events
 	Jobs() *Jobs
+	Namespaces() *Namespaces
 	Nodes() *Nodes
 }
 
@@ -22,10 +24,18 @@ func (c *api) Auth() *Auth {
 	return &Auth{client: c.Client}
 }
 
+func (c *api) Events() *Events {
+	return &Events{client: c.Client}
+}
+
 func (c *api) Jobs() *Jobs {
 	return &Jobs{client: c.Client}
 }
 
+func (c *api) Namespaces() *Namespaces {
+	return &Namespaces{client: c.Client}
+}
+
 func (c *api) Nodes() *Nodes {
 	return &Nodes{client: c.Client}
 }
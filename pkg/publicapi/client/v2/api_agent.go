@@ -31,6 +31,13 @@ func (c *Agent) Node(ctx context.Context, req *apimodels.GetAgentNodeRequest) (*
 	return &res, err
 }
 
+// Queue is used to get the executions queued on the agent node.
+func (c *Agent) Queue(ctx context.Context) (*apimodels.GetAgentQueueResponse, error) {
+	var res apimodels.GetAgentQueueResponse
+	err := c.client.Get(ctx, "/api/v1/agent/queue", &apimodels.BaseGetRequest{}, &res)
+	return &res, err
+}
+
 func (c *Agent) Config(ctx context.Context) (*apimodels.GetAgentConfigResponse, error) {
 	var res apimodels.GetAgentConfigResponse
 	err := c.client.Get(ctx, "/api/v1/agent/config", &apimodels.BaseGetRequest{}, &res)
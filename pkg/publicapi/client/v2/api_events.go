@@ -0,0 +1,21 @@
+package client
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+const eventsPath = "/api/v1/orchestrator/events"
+
+type Events struct {
+	client Client
+}
+
+// Stream returns a stream of job and execution changes matching the request filters.
+// The stream continues until the context is cancelled or the connection is closed.
+func (e *Events) Stream(
+	ctx context.Context, r *apimodels.StreamEventsRequest) (<-chan *concurrency.AsyncResult[apimodels.Event], error) {
+	return DialAsyncResult[*apimodels.StreamEventsRequest, apimodels.Event](ctx, e.client, eventsPath, r)
+}
@@ -2,6 +2,9 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/url"
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
@@ -94,6 +97,18 @@ func (j *Jobs) Results(ctx context.Context, r *apimodels.ListJobResultsRequest)
 	return &resp, nil
 }
 
+// Recommendations returns the resources recommended for a job from the usage of its past executions.
+func (j *Jobs) Recommendations(
+	ctx context.Context,
+	r *apimodels.GetJobRecommendationsRequest,
+) (*apimodels.GetJobRecommendationsResponse, error) {
+	var resp apimodels.GetJobRecommendationsResponse
+	if err := j.client.Get(ctx, jobsPath+"/"+url.PathEscape(r.JobIDOrName)+"/recommendations", r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // Stop is used to stop a job by ID.
 func (j *Jobs) Stop(ctx context.Context, r *apimodels.StopJobRequest) (*apimodels.StopJobResponse, error) {
 	var resp apimodels.StopJobResponse
@@ -116,3 +131,56 @@ func (j *Jobs) Rerun(ctx context.Context, r *apimodels.RerunJobRequest) (*apimod
 func (j *Jobs) Logs(ctx context.Context, r *apimodels.GetLogsRequest) (<-chan *concurrency.AsyncResult[models.ExecutionLog], error) {
 	return DialAsyncResult[*apimodels.GetLogsRequest, models.ExecutionLog](ctx, j.client, jobsPath+"/"+r.JobID+"/logs", r)
 }
+
+// Exec runs a command inside a running execution of a job, or attaches to the main process of the execution
+// if the request has no command, and returns a stream of the command's output. The input, if not nil,
+// is forwarded to the command until it is exhausted.
+func (j *Jobs) Exec(ctx context.Context, r *apimodels.ExecJobRequest, stdin io.Reader) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	endpoint := jobsPath + "/" + url.PathEscape(r.JobID) + "/exec"
+	if len(r.Command) == 0 {
+		endpoint = jobsPath + "/" + url.PathEscape(r.JobID) + "/attach"
+	}
+	var input chan []byte
+	if stdin != nil {
+		r.Stdin = true
+		input = make(chan []byte)
+		go forwardExecInput(ctx, stdin, input)
+	}
+	return DialAsyncResultWithInput[*apimodels.ExecJobRequest, models.ExecOutput](ctx, j.client, endpoint, r, input)
+}
+
+// execInputChunkSize is the maximum size of the input of a command sent in a single message
+const execInputChunkSize = 32 * 1024
+
+// forwardExecInput sends the input to the channel as exec input messages until it is exhausted,
+// and closes the channel.
+func forwardExecInput(ctx context.Context, stdin io.Reader, input chan<- []byte) {
+	defer close(input)
+	send := func(execInput models.ExecInput) bool {
+		data, err := json.Marshal(execInput)
+		if err != nil {
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case input <- data:
+			return true
+		}
+	}
+
+	buf := make([]byte, execInputChunkSize)
+	for {
+		n, err := stdin.Read(buf)
+		if n > 0 && !send(models.ExecInput{Data: buf[:n]}) {
+			return
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				send(models.ExecInput{EOF: true})
+			}
+			return
+		}
+	}
+}
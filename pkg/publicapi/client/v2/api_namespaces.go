@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+const namespacesPath = "/api/v1/orchestrator/namespaces"
+
+type Namespaces struct {
+	client Client
+}
+
+// GetQuota is used to get the quota and usage of a namespace.
+func (n *Namespaces) GetQuota(
+	ctx context.Context, r *apimodels.GetNamespaceQuotaRequest) (*apimodels.GetNamespaceQuotaResponse, error) {
+	var resp apimodels.GetNamespaceQuotaResponse
+	if err := n.client.Get(ctx, namespacesPath+"/"+url.PathEscape(r.Name)+"/quota", r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListQuotas is used to list the quotas of all namespaces.
+func (n *Namespaces) ListQuotas(
+	ctx context.Context, r *apimodels.ListNamespaceQuotasRequest) (*apimodels.ListNamespaceQuotasResponse, error) {
+	var resp apimodels.ListNamespaceQuotasResponse
+	if err := n.client.List(ctx, namespacesPath+"/quotas", r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PutQuota is used to create or replace the quota of a namespace.
+func (n *Namespaces) PutQuota(
+	ctx context.Context, r *apimodels.PutNamespaceQuotaRequest) (*apimodels.PutNamespaceQuotaResponse, error) {
+	var resp apimodels.PutNamespaceQuotaResponse
+	if err := n.client.Put(ctx, namespacesPath+"/"+url.PathEscape(r.Quota.Namespace)+"/quota", r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteQuota is used to delete the quota of a namespace.
+func (n *Namespaces) DeleteQuota(
+	ctx context.Context, r *apimodels.DeleteNamespaceQuotaRequest) (*apimodels.DeleteNamespaceQuotaResponse, error) {
+	var resp apimodels.DeleteNamespaceQuotaResponse
+	if err := n.client.Delete(ctx, namespacesPath+"/"+url.PathEscape(r.Name)+"/quota", r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -34,6 +35,7 @@ type Client interface {
 	Post(context.Context, string, apimodels.PutRequest, apimodels.PutResponse) error
 	Delete(context.Context, string, apimodels.PutRequest, apimodels.Response) error
 	Dial(context.Context, string, apimodels.Request) (<-chan *concurrency.AsyncResult[[]byte], error)
+	DialWithInput(context.Context, string, apimodels.Request, <-chan []byte) (<-chan *concurrency.AsyncResult[[]byte], error)
 }
 
 // New creates a new transport.
@@ -152,6 +154,17 @@ func (c *httpClient) Delete(ctx context.Context, endpoint string, in apimodels.P
 // successfully dialed, from which point on the returned channel will contain
 // every received message.
 func (c *httpClient) Dial(ctx context.Context, endpoint string, in apimodels.Request) (<-chan *concurrency.AsyncResult[[]byte], error) {
+	return c.DialWithInput(ctx, endpoint, in, nil)
+}
+
+// DialWithInput is like Dial, but also sends every message received from the
+// input channel to the endpoint until the channel is closed.
+func (c *httpClient) DialWithInput(
+	ctx context.Context,
+	endpoint string,
+	in apimodels.Request,
+	input <-chan []byte,
+) (<-chan *concurrency.AsyncResult[[]byte], error) {
 	r := in.ToHTTPRequest()
 	httpR, err := c.toHTTP(ctx, http.MethodGet, endpoint, r)
 	if err != nil {
@@ -178,9 +191,44 @@ func (c *httpClient) Dial(ctx context.Context, endpoint string, in apimodels.Req
 	// the context is cancelled. We have to read them here because the reader
 	// will be discarded upon the next call to NextReader.
 	output := make(chan *concurrency.AsyncResult[[]byte], c.config.WebsocketChannelBuffer)
+	done := make(chan struct{})
+	// the connection supports a single concurrent writer
+	var writeMu sync.Mutex
+	if input != nil {
+		go func() {
+			for {
+				select {
+				case <-done:
+					return
+				case msg, ok := <-input:
+					if !ok {
+						return
+					}
+					writeMu.Lock()
+					err := conn.WriteMessage(websocket.TextMessage, msg)
+					writeMu.Unlock()
+					if err != nil {
+						return
+					}
+				}
+			}
+		}()
+	}
+	go func() {
+		// Close the connection when the context is cancelled to unblock any pending
+		// read, as streams can stay idle for a long time.
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
 	go func() {
 		defer func() {
+			close(done)
+			writeMu.Lock()
 			_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			writeMu.Unlock()
 			_ = conn.Close()
 			close(output)
 		}()
@@ -531,6 +579,20 @@ func (t *AuthenticatingClient) Dial(
 	return output, err
 }
 
+func (t *AuthenticatingClient) DialWithInput(
+	ctx context.Context,
+	path string,
+	in apimodels.Request,
+	input <-chan []byte,
+) (<-chan *concurrency.AsyncResult[[]byte], error) {
+	var output <-chan *concurrency.AsyncResult[[]byte]
+	err := doRequest(ctx, t, in, func(req apimodels.Request) (err error) {
+		output, err = t.Client.DialWithInput(ctx, path, req, input)
+		return
+	})
+	return output, err
+}
+
 func doRequest[R apimodels.Request](ctx context.Context, t *AuthenticatingClient, request R, runRequest func(R) error) (err error) {
 	if t.NewAuthenticationFlowEnabled {
 		// Skip all legacy credential flow
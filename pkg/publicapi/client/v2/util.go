@@ -108,10 +108,22 @@ func DialAsyncResult[In apimodels.Request, Out any](
 	client Client,
 	endpoint string,
 	r In,
+) (<-chan *concurrency.AsyncResult[Out], error) {
+	return DialAsyncResultWithInput[In, Out](ctx, client, endpoint, r, nil)
+}
+
+// DialAsyncResultWithInput is like DialAsyncResult, but also sends every message
+// received from the input channel to the endpoint until the channel is closed.
+func DialAsyncResultWithInput[In apimodels.Request, Out any](
+	ctx context.Context,
+	client Client,
+	endpoint string,
+	r In,
+	messages <-chan []byte,
 ) (<-chan *concurrency.AsyncResult[Out], error) {
 	output := make(chan *concurrency.AsyncResult[Out])
 
-	input, err := client.Dial(ctx, endpoint, r)
+	input, err := client.DialWithInput(ctx, endpoint, r, messages)
 	if err != nil {
 		return nil, err
 	}
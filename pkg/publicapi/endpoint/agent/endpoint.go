@@ -18,13 +18,16 @@ type EndpointParams struct {
 	Router             *echo.Echo
 	NodeInfoProvider   models.NodeInfoProvider
 	DebugInfoProviders []models.DebugInfoProvider
-	BacalhauConfig     types.Bacalhau
+	// QueueProvider provides the executions queued on the node. Nil if the node is not a compute node.
+	QueueProvider  models.ExecutionQueueProvider
+	BacalhauConfig types.Bacalhau
 }
 
 type Endpoint struct {
 	router             *echo.Echo
 	nodeInfoProvider   models.NodeInfoProvider
 	debugInfoProviders []models.DebugInfoProvider
+	queueProvider      models.ExecutionQueueProvider
 	bacalhauConfig     types.Bacalhau
 }
 
@@ -33,6 +36,7 @@ func NewEndpoint(params EndpointParams) (*Endpoint, error) {
 		router:             params.Router,
 		nodeInfoProvider:   params.NodeInfoProvider,
 		debugInfoProviders: params.DebugInfoProviders,
+		queueProvider:      params.QueueProvider,
 		bacalhauConfig:     params.BacalhauConfig,
 	}
 
@@ -43,6 +47,7 @@ func NewEndpoint(params EndpointParams) (*Endpoint, error) {
 	g.GET("/version", e.version)
 	g.GET("/node", e.node)
 	g.GET("/debug", e.debug)
+	g.GET("/queue", e.queue)
 	g.GET("/config", e.config)
 	g.GET("/authconfig", e.nodeAuthConfig)
 
@@ -116,6 +121,24 @@ func (e *Endpoint) debug(c echo.Context) error {
 	return c.JSON(http.StatusOK, debugInfoMap)
 }
 
+// queue godoc
+//
+//	@ID			agent/queue
+//	@Summary	Returns the executions waiting for capacity on the node, in the order they are expected to run.
+//	@Tags		Ops
+//	@Produce	json
+//	@Success	200	{object}	apimodels.GetAgentQueueResponse
+//	@Failure	404	{object}	string
+//	@Router		/api/v1/agent/queue [get]
+func (e *Endpoint) queue(c echo.Context) error {
+	if e.queueProvider == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "node is not a compute node")
+	}
+	return c.JSON(http.StatusOK, apimodels.GetAgentQueueResponse{
+		Executions: e.queueProvider.QueuedExecutions(),
+	})
+}
+
 // config godoc
 //
 //	@ID			agent/config
@@ -17,18 +17,20 @@ type EndpointParams struct {
 }
 
 type Endpoint struct {
-	router       *echo.Echo
-	orchestrator *orchestrator.BaseEndpoint
-	store        jobstore.Store
-	nodeManager  nodes.Manager
+	router        *echo.Echo
+	orchestrator  *orchestrator.BaseEndpoint
+	store         jobstore.Store
+	nodeManager   nodes.Manager
+	quotaEnforcer *orchestrator.QuotaEnforcer
 }
 
 func NewEndpoint(params EndpointParams) *Endpoint {
 	e := &Endpoint{
-		router:       params.Router,
-		orchestrator: params.Orchestrator,
-		store:        params.JobStore,
-		nodeManager:  params.NodeManager,
+		router:        params.Router,
+		orchestrator:  params.Orchestrator,
+		store:         params.JobStore,
+		nodeManager:   params.NodeManager,
+		quotaEnforcer: orchestrator.NewQuotaEnforcer(params.JobStore),
 	}
 
 	// JSON group
@@ -45,9 +47,17 @@ func NewEndpoint(params EndpointParams) *Endpoint {
 	g.GET("/jobs/:id/executions", e.jobExecutions)
 	g.GET("/jobs/:id/versions", e.jobVersions)
 	g.GET("/jobs/:id/results", e.jobResults)
+	g.GET("/jobs/:id/recommendations", e.jobRecommendations)
 	g.GET("/jobs/:id/logs", e.logs)
+	g.GET("/jobs/:id/exec", e.exec)
+	g.GET("/jobs/:id/attach", e.attach)
+	g.GET("/events", e.streamEvents)
 	g.GET("/nodes", e.listNodes)
 	g.GET("/nodes/:id", e.getNode)
 	g.PUT("/nodes/:id", e.updateNode)
+	g.GET("/namespaces/quotas", e.listNamespaceQuotas)
+	g.GET("/namespaces/:namespace/quota", e.getNamespaceQuota)
+	g.PUT("/namespaces/:namespace/quota", e.putNamespaceQuota)
+	g.DELETE("/namespaces/:namespace/quota", e.deleteNamespaceQuota)
 	return e
 }
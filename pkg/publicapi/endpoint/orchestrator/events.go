@@ -0,0 +1,225 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/watcher"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// eventsBatchSize is the maximum number of events read from the event store at once
+const eventsBatchSize = 100
+
+// eventsFilter selects the events streamed to a client
+type eventsFilter struct {
+	// namespace of the streamed events, or empty for all namespaces
+	namespace string
+	jobID     string
+	store     watcher.EventFilter
+}
+
+// godoc for Orchestrator StreamEvents
+//
+//	@ID				orchestrator/events
+//	@Summary		Streams job and execution changes via WebSocket
+//	@Description	Establishes a WebSocket connection to stream changes to jobs and executions as they happen.
+//	@Description	The stream continues until the client disconnects. Pass the sequence number of the last
+//	@Description	received event as `after` to resume a stream without missing events.
+//	@Tags			Orchestrator
+//	@Produce		json
+//	@Param			namespace		query		string		false	"Namespace of the events, or * for all namespaces"
+//	@Param			job_id			query		string		false	"Only stream events of this job ID or name"
+//	@Param			object_types	query		[]string	false	"Only stream events of these object types (job, execution)"
+//	@Param			operations		query		[]string	false	"Only stream events of these operations (CREATE, UPDATE, DELETE)"
+//	@Param			after			query		int			false	"Resume the stream after this event sequence number"
+//	@Success		101				{object}	apimodels.Event	"Switching Protocols to WebSocket"
+//	@Failure		400				{object}	string			"Bad Request"
+//	@Failure		404				{object}	string			"Not Found"
+//	@Failure		500				{object}	string			"Internal Server Error"
+//	@Router			/api/v1/orchestrator/events [get]
+func (e *Endpoint) streamEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.StreamEventsRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	// resolve the filter and the starting point before upgrading the connection, so that
+	// invalid requests are rejected with a proper status and no event is missed once the
+	// client is connected.
+	filter, err := e.newEventsFilter(ctx, args)
+	if err != nil {
+		return err
+	}
+	iterator := watcher.AfterSequenceNumberIterator(args.After)
+	if args.After == 0 {
+		latest, err := e.store.GetEventStore().GetLatestEventNum(ctx)
+		if err != nil {
+			return err
+		}
+		iterator = watcher.AfterSequenceNumberIterator(latest)
+	}
+
+	ws, err := publicapi.WebsocketUpgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade websocket connection: %w", err)
+	}
+	defer func() { _ = ws.Close() }()
+
+	// the client doesn't send any messages, so reading only detects when it disconnects
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, _, readErr := ws.ReadMessage(); readErr != nil {
+				return
+			}
+		}
+	}()
+
+	err = e.streamEventsWS(ctx, ws, filter, iterator)
+	if err != nil && ctx.Err() == nil {
+		log.Ctx(ctx).Error().Err(err).Msg("websocket failure")
+		err = ws.WriteJSON(concurrency.AsyncResult[apimodels.Event]{
+			Err: err,
+		})
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to write error to websocket")
+		}
+	}
+	_ = ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return nil
+}
+
+func (e *Endpoint) streamEventsWS(
+	ctx context.Context, ws *websocket.Conn, filter eventsFilter, iterator watcher.EventIterator) error {
+	for {
+		response, err := e.store.GetEventStore().GetEvents(ctx, watcher.GetEventsRequest{
+			EventIterator: iterator,
+			Limit:         eventsBatchSize,
+			Filter:        filter.store,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		for _, storeEvent := range response.Events {
+			event, ok := filter.toAPIEvent(storeEvent)
+			if !ok {
+				continue
+			}
+			if err = ws.WriteJSON(concurrency.AsyncResult[apimodels.Event]{Value: event}); err != nil {
+				return err
+			}
+		}
+		iterator = response.NextEventIterator
+	}
+}
+
+// newEventsFilter validates the request filters and maps them to the event store's filter
+func (e *Endpoint) newEventsFilter(ctx context.Context, args apimodels.StreamEventsRequest) (eventsFilter, error) {
+	filter := eventsFilter{namespace: args.Namespace}
+	switch args.Namespace {
+	case "":
+		filter.namespace = models.DefaultNamespace
+	case apimodels.AllNamespacesNamespace:
+		filter.namespace = ""
+	}
+
+	if args.JobID != "" {
+		job, err := e.store.GetJobByIDOrName(ctx, args.JobID, args.Namespace)
+		if err != nil {
+			return eventsFilter{}, err
+		}
+		filter.jobID = job.ID
+	}
+
+	objectTypes := args.ObjectTypes
+	if len(objectTypes) == 0 {
+		objectTypes = []apimodels.EventObjectType{apimodels.EventObjectJob, apimodels.EventObjectExecution}
+	}
+	for _, objectType := range objectTypes {
+		switch objectType {
+		case apimodels.EventObjectJob:
+			filter.store.ObjectTypes = append(filter.store.ObjectTypes, jobstore.EventObjectJobUpsert)
+		case apimodels.EventObjectExecution:
+			filter.store.ObjectTypes = append(filter.store.ObjectTypes, jobstore.EventObjectExecutionUpsert)
+		default:
+			return eventsFilter{}, echo.NewHTTPError(http.StatusBadRequest,
+				fmt.Sprintf("invalid object type %q. valid types are %s and %s",
+					objectType, apimodels.EventObjectJob, apimodels.EventObjectExecution))
+		}
+	}
+
+	for _, operation := range args.Operations {
+		switch operation {
+		case apimodels.EventOperationCreate, apimodels.EventOperationUpdate, apimodels.EventOperationDelete:
+			filter.store.Operations = append(filter.store.Operations, watcher.Operation(operation))
+		default:
+			return eventsFilter{}, echo.NewHTTPError(http.StatusBadRequest,
+				fmt.Sprintf("invalid operation %q. valid operations are %s, %s and %s", operation,
+					apimodels.EventOperationCreate, apimodels.EventOperationUpdate, apimodels.EventOperationDelete))
+		}
+	}
+	return filter, nil
+}
+
+// toAPIEvent converts an event store event to its public representation.
+// It returns false if the event doesn't match the namespace or job of the filter.
+func (f eventsFilter) toAPIEvent(event watcher.Event) (apimodels.Event, bool) {
+	result := apimodels.Event{
+		SeqNum:    event.SeqNum,
+		Operation: apimodels.EventOperation(event.Operation),
+		Timestamp: event.Timestamp,
+	}
+
+	var namespace, jobID string
+	switch object := event.Object.(type) {
+	case models.JobUpsert:
+		job := object.Current
+		if job == nil {
+			job = object.Previous
+		}
+		if job == nil {
+			return apimodels.Event{}, false
+		}
+		result.ObjectType = apimodels.EventObjectJob
+		result.Job = job
+		namespace, jobID = job.Namespace, job.ID
+	case models.ExecutionUpsert:
+		if object.Current == nil {
+			return apimodels.Event{}, false
+		}
+		result.ObjectType = apimodels.EventObjectExecution
+		result.Execution = object.Current
+		result.Events = object.Events
+		namespace, jobID = object.Current.Namespace, object.Current.JobID
+	default:
+		return apimodels.Event{}, false
+	}
+
+	if f.namespace != "" && f.namespace != namespace {
+		return apimodels.Event{}, false
+	}
+	if f.jobID != "" && f.jobID != jobID {
+		return apimodels.Event{}, false
+	}
+	return result, true
+}
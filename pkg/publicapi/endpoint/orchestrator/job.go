@@ -1,7 +1,10 @@
 package orchestrator
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -552,10 +555,11 @@ func (e *Endpoint) jobVersions(c echo.Context) error {
 //	@Tags			Orchestrator
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string	true	"ID to get the job results for"
-//	@Success		200	{object}	apimodels.ListJobResultsResponse
-//	@Failure		400	{object}	string
-//	@Failure		500	{object}	string
+//	@Param			id		path		string	true	"ID to get the job results for"
+//	@Param			partial	query		bool	false	"Also return the results published so far by executions that did not complete yet"
+//	@Success		200		{object}	apimodels.ListJobResultsResponse
+//	@Failure		400		{object}	string
+//	@Failure		500		{object}	string
 //	@Router			/api/v1/orchestrator/jobs/{id}/results [get]
 func (e *Endpoint) jobResults(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -576,6 +580,7 @@ func (e *Endpoint) jobResults(c echo.Context) error {
 	resp, err := e.orchestrator.GetResults(ctx, &orchestrator.GetResultsRequest{
 		JobID:     job.ID,
 		Namespace: args.Namespace,
+		Partial:   args.Partial,
 	})
 	if err != nil {
 		return err
@@ -586,6 +591,54 @@ func (e *Endpoint) jobResults(c echo.Context) error {
 	return publicapi.UnescapedJSON(c, http.StatusOK, result)
 }
 
+// godoc for Orchestrator JobRecommendations
+//
+//	@ID				orchestrator/jobRecommendations
+//	@Summary		Returns the resources recommended for a job.
+//	@Description	Returns the resources recommended for a job, based on the peak usage reported by the executions of all its versions.
+//	@Tags			Orchestrator
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string	true	"ID or Name of the job to get recommendations for"
+//	@Param			namespace	query		string	false	"Namespace of the job"
+//	@Success		200			{object}	apimodels.GetJobRecommendationsResponse
+//	@Failure		400			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/orchestrator/jobs/{id}/recommendations [get]
+func (e *Endpoint) jobRecommendations(c echo.Context) error {
+	ctx := c.Request().Context()
+	jobIDOrName := c.Param("id")
+	var args apimodels.GetJobRecommendationsRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	job, err := e.store.GetJobByIDOrName(ctx, jobIDOrName, args.Namespace)
+	if err != nil {
+		return err
+	}
+
+	executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+		JobID:          job.ID,
+		AllJobVersions: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	recommendation, err := orchestrator.RecommendResources(job, executions)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &apimodels.GetJobRecommendationsResponse{
+		Recommendation: recommendation,
+	})
+}
+
 // godoc for Orchestrator JobLogs
 //
 //	@ID				orchestrator/logs
@@ -658,3 +711,139 @@ func (e *Endpoint) logsWS(c echo.Context, ws *websocket.Conn) error {
 	}
 	return nil
 }
+
+// godoc for Orchestrator JobExec
+//
+//	@ID				orchestrator/exec
+//	@Summary		Runs a command inside a running execution of a job via WebSocket
+//	@Description	Establishes a WebSocket connection to run a command inside a running execution of the job specified by `id`,
+//	@Description	and streams its output until it exits. If `stdin` is set, models.ExecInput messages sent by the client are
+//	@Description	forwarded to the command.
+//	@Tags			Orchestrator
+//	@Accept			json
+//	@Produce		octet-stream
+//	@Param			id				path		string				true	"ID of the job to run the command in"
+//	@Param			execution_id	query		string				false	"Execution to run the command in"
+//	@Param			command			query		[]string			true	"Command to run and its arguments"
+//	@Param			stdin			query		bool				false	"Forward input to the command"
+//	@Success		101				{object}	models.ExecOutput	"Switching Protocols to WebSocket"
+//	@Failure		400				{object}	string				"Bad Request"
+//	@Failure		500				{object}	string				"Internal Server Error"
+//	@Router			/api/v1/orchestrator/jobs/{id}/exec [get]
+func (e *Endpoint) exec(c echo.Context) error {
+	return e.execStream(c, false)
+}
+
+// godoc for Orchestrator JobAttach
+//
+//	@ID				orchestrator/attach
+//	@Summary		Attaches to the main process of a running execution of a job via WebSocket
+//	@Description	Establishes a WebSocket connection to stream the output of the main process of a running execution of the
+//	@Description	job specified by `id`, until it exits or the client disconnects. If `stdin` is set, models.ExecInput messages
+//	@Description	sent by the client are forwarded to the process when supported by the engine.
+//	@Tags			Orchestrator
+//	@Accept			json
+//	@Produce		octet-stream
+//	@Param			id				path		string				true	"ID of the job to attach to"
+//	@Param			execution_id	query		string				false	"Execution to attach to"
+//	@Param			stdin			query		bool				false	"Forward input to the process"
+//	@Success		101				{object}	models.ExecOutput	"Switching Protocols to WebSocket"
+//	@Failure		400				{object}	string				"Bad Request"
+//	@Failure		500				{object}	string				"Internal Server Error"
+//	@Router			/api/v1/orchestrator/jobs/{id}/attach [get]
+func (e *Endpoint) attach(c echo.Context) error {
+	return e.execStream(c, true)
+}
+
+func (e *Endpoint) execStream(c echo.Context, attach bool) error {
+	ws, err := publicapi.WebsocketUpgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade websocket connection: %w", err)
+	}
+	defer func() { _ = ws.Close() }()
+
+	err = e.execWS(c, ws, attach)
+	if err != nil {
+		log.Ctx(c.Request().Context()).Error().Err(err).Msg("websocket failure")
+		err = ws.WriteJSON(concurrency.AsyncResult[models.ExecOutput]{
+			Err: err,
+		})
+		if err != nil {
+			log.Ctx(c.Request().Context()).Error().Err(err).Msg("failed to write error to websocket")
+		}
+	}
+	_ = ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return nil
+}
+
+func (e *Endpoint) execWS(c echo.Context, ws *websocket.Conn, attach bool) error {
+	var args apimodels.ExecJobRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+	if attach {
+		args.Command = nil
+	} else if len(args.Command) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing command to run")
+	}
+
+	// the command is stopped when the client disconnects
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	var stdin *io.PipeReader
+	var stdinWriter *io.PipeWriter
+	if args.Stdin {
+		stdin, stdinWriter = io.Pipe()
+	}
+	go func() {
+		defer cancel()
+		for {
+			_, data, err := ws.ReadMessage()
+			if err != nil {
+				if stdinWriter != nil {
+					_ = stdinWriter.CloseWithError(err)
+				}
+				return
+			}
+			if stdinWriter == nil {
+				continue
+			}
+			var input models.ExecInput
+			if err = json.Unmarshal(data, &input); err != nil {
+				_ = stdinWriter.CloseWithError(err)
+				continue
+			}
+			if len(input.Data) > 0 {
+				_, _ = stdinWriter.Write(input.Data)
+			}
+			if input.EOF {
+				_ = stdinWriter.Close()
+			}
+		}
+	}()
+
+	request := orchestrator.ExecRequest{
+		JobID:       c.Param("id"),
+		Namespace:   args.Namespace,
+		ExecutionID: args.ExecutionID,
+		Command:     args.Command,
+	}
+	if stdin != nil {
+		request.Stdin = stdin
+	}
+	outputCh, err := e.orchestrator.Exec(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to exec into job %s: %w", request.JobID, err)
+	}
+
+	for output := range outputCh {
+		if err = ws.WriteJSON(output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"golang.org/x/exp/slices"
 	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
 	"github.com/bacalhau-project/bacalhau/pkg/util"
@@ -37,9 +38,15 @@ func (e *Endpoint) getNode(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, apimodels.GetNodeResponse{
+	response := apimodels.GetNodeResponse{
 		Node: &nodeState,
-	})
+	}
+	if nodeState.IsDraining() {
+		if response.Drain, err = e.drainStatus(ctx, nodeState.Info.ID()); err != nil {
+			return err
+		}
+	}
+	return c.JSON(http.StatusOK, response)
 }
 
 // godoc for Orchestrator ListNodes
@@ -208,23 +215,54 @@ func (e *Endpoint) updateNode(c echo.Context) error {
 		return err
 	}
 
-	var action func(context.Context, string) error
-	if args.Action == string(apimodels.NodeActionApprove) {
-		action = e.nodeManager.ApproveNode
-	} else if args.Action == string(apimodels.NodeActionReject) {
-		action = e.nodeManager.RejectNode
-	} else if args.Action == string(apimodels.NodeActionDelete) {
-		action = e.nodeManager.DeleteNode
-	} else {
-		action = func(context.Context, string) error {
-			return fmt.Errorf("unsupported action %s", args.Action)
-		}
+	var err error
+	switch apimodels.NodeAction(args.Action) {
+	case apimodels.NodeActionApprove:
+		err = e.nodeManager.ApproveNode(ctx, nodeID)
+	case apimodels.NodeActionReject:
+		err = e.nodeManager.RejectNode(ctx, nodeID)
+	case apimodels.NodeActionDelete:
+		err = e.nodeManager.DeleteNode(ctx, nodeID)
+	case apimodels.NodeActionCordon:
+		err = e.nodeManager.CordonNode(ctx, nodeID, args.Message)
+	case apimodels.NodeActionUncordon:
+		err = e.nodeManager.UncordonNode(ctx, nodeID)
+	case apimodels.NodeActionDrain:
+		err = e.nodeManager.DrainNode(ctx, nodeID, args.Message)
+	default:
+		err = fmt.Errorf("unsupported action %s", args.Action)
 	}
-
-	if err := action(ctx, nodeID); err != nil {
+	if err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, apimodels.PutNodeResponse{
+
+	response := apimodels.PutNodeResponse{
 		Success: true,
+	}
+	if args.Action == string(apimodels.NodeActionDrain) {
+		nodeState, err := e.nodeManager.GetByPrefix(ctx, nodeID)
+		if err != nil {
+			return err
+		}
+		if response.Drain, err = e.drainStatus(ctx, nodeState.Info.ID()); err != nil {
+			return err
+		}
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// drainStatus returns the progress of draining a node, based on the executions still active on it
+func (e *Endpoint) drainStatus(ctx context.Context, nodeID string) (*apimodels.NodeDrainStatus, error) {
+	executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+		NodeIDs:        []string{nodeID},
+		InProgressOnly: true,
+		AllJobVersions: true,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &apimodels.NodeDrainStatus{
+		RemainingExecutions: len(executions),
+		Complete:            len(executions) == 0,
+	}, nil
 }
@@ -0,0 +1,157 @@
+package orchestrator
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// godoc for Orchestrator GetNamespaceQuota
+//
+//	@ID				orchestrator/getNamespaceQuota
+//	@Summary		Returns the quota of a namespace.
+//	@Description	Returns the quota of a namespace along with its current usage.
+//	@Tags			Orchestrator
+//	@Produce		json
+//	@Param			namespace	path		string	true	"Namespace to get the quota for"
+//	@Success		200			{object}	apimodels.GetNamespaceQuotaResponse
+//	@Failure		400			{object}	string
+//	@Failure		404			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/orchestrator/namespaces/{namespace}/quota [get]
+func (e *Endpoint) getNamespaceQuota(c echo.Context) error {
+	ctx := c.Request().Context()
+	namespace := c.Param("namespace")
+	if namespace == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing namespace")
+	}
+
+	quota, err := e.store.GetNamespaceQuota(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	usage, err := e.quotaEnforcer.Usage(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, apimodels.GetNamespaceQuotaResponse{
+		Quota: &quota,
+		Usage: usage,
+	})
+}
+
+// godoc for Orchestrator ListNamespaceQuotas
+//
+//	@ID				orchestrator/listNamespaceQuotas
+//	@Summary		Returns the quotas of all namespaces.
+//	@Description	Returns the quotas of all namespaces that have one, sorted by namespace.
+//	@Tags			Orchestrator
+//	@Produce		json
+//	@Success		200	{object}	apimodels.ListNamespaceQuotasResponse
+//	@Failure		400	{object}	string
+//	@Failure		500	{object}	string
+//	@Router			/api/v1/orchestrator/namespaces/quotas [get]
+func (e *Endpoint) listNamespaceQuotas(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.ListNamespaceQuotasRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	quotas, err := e.store.GetNamespaceQuotas(ctx)
+	if err != nil {
+		return err
+	}
+	res := make([]*models.NamespaceQuota, len(quotas))
+	for i := range quotas {
+		res[i] = &quotas[i]
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if args.Reverse {
+			return res[i].Namespace > res[j].Namespace
+		}
+		return res[i].Namespace < res[j].Namespace
+	})
+	return c.JSON(http.StatusOK, &apimodels.ListNamespaceQuotasResponse{
+		Quotas: res,
+	})
+}
+
+// godoc for Orchestrator PutNamespaceQuota
+//
+//	@ID				orchestrator/putNamespaceQuota
+//	@Summary		Sets the quota of a namespace.
+//	@Description	Creates or replaces the quota of a namespace. Zero limits mean no limit.
+//	@Tags			Orchestrator
+//	@Accept			json
+//	@Produce		json
+//	@Param			namespace					path		string								true	"Namespace to set the quota for"
+//	@Param			putNamespaceQuotaRequest	body		apimodels.PutNamespaceQuotaRequest	true	"Quota to set"
+//	@Success		200							{object}	apimodels.PutNamespaceQuotaResponse
+//	@Failure		400							{object}	string
+//	@Failure		500							{object}	string
+//	@Router			/api/v1/orchestrator/namespaces/{namespace}/quota [put]
+func (e *Endpoint) putNamespaceQuota(c echo.Context) error {
+	ctx := c.Request().Context()
+	namespace := c.Param("namespace")
+
+	var args apimodels.PutNamespaceQuotaRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if args.Quota == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing quota")
+	}
+	if args.Quota.Namespace != "" && args.Quota.Namespace != namespace {
+		return echo.NewHTTPError(http.StatusBadRequest, "quota namespace does not match the namespace in the path")
+	}
+	args.Quota.Namespace = namespace
+	args.Quota.Normalize()
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	if err := e.store.PutNamespaceQuota(ctx, *args.Quota); err != nil {
+		return err
+	}
+	quota, err := e.store.GetNamespaceQuota(ctx, args.Quota.Namespace)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.PutNamespaceQuotaResponse{
+		Quota: &quota,
+	})
+}
+
+// godoc for Orchestrator DeleteNamespaceQuota
+//
+//	@ID				orchestrator/deleteNamespaceQuota
+//	@Summary		Deletes the quota of a namespace.
+//	@Description	Deletes the quota of a namespace, which removes all its limits.
+//	@Tags			Orchestrator
+//	@Produce		json
+//	@Param			namespace	path		string	true	"Namespace to delete the quota of"
+//	@Success		200			{object}	apimodels.DeleteNamespaceQuotaResponse
+//	@Failure		400			{object}	string
+//	@Failure		404			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/orchestrator/namespaces/{namespace}/quota [delete]
+func (e *Endpoint) deleteNamespaceQuota(c echo.Context) error {
+	ctx := c.Request().Context()
+	namespace := c.Param("namespace")
+	if namespace == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing namespace")
+	}
+
+	if err := e.store.DeleteNamespaceQuota(ctx, namespace); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.DeleteNamespaceQuotaResponse{})
+}
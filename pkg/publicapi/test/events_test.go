@@ -0,0 +1,92 @@
+//go:build unit || !integration
+
+package test
+
+import (
+	"context"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+func (s *ServerSuite) TestStreamEvents() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := s.client.Events().Stream(ctx, &apimodels.StreamEventsRequest{})
+	s.Require().NoError(err)
+
+	putResponse, err := s.client.Jobs().Put(ctx, &apimodels.PutJobRequest{Job: mock.Job()})
+	s.Require().NoError(err)
+	jobID := putResponse.JobID
+
+	// collect the job's events until it completes
+	var events []apimodels.Event
+	for !s.isJobCompleted(events) {
+		events = append(events, s.nextEvent(stream))
+	}
+
+	var jobEvents, executionEvents []apimodels.Event
+	for _, event := range events {
+		switch event.ObjectType {
+		case apimodels.EventObjectJob:
+			s.Require().NotNil(event.Job)
+			if event.Job.ID == jobID {
+				jobEvents = append(jobEvents, event)
+			}
+		case apimodels.EventObjectExecution:
+			s.Require().NotNil(event.Execution)
+			if event.Execution.JobID == jobID {
+				executionEvents = append(executionEvents, event)
+			}
+		}
+	}
+	s.Require().NotEmpty(jobEvents)
+	s.Require().NotEmpty(executionEvents)
+	s.Equal(apimodels.EventOperationCreate, jobEvents[0].Operation)
+	s.Equal(apimodels.EventOperationCreate, executionEvents[0].Operation)
+
+	// resume the stream of the job's updates after its creation
+	resumed, err := s.client.Events().Stream(ctx, &apimodels.StreamEventsRequest{
+		JobID:       jobID,
+		ObjectTypes: []apimodels.EventObjectType{apimodels.EventObjectJob},
+		Operations:  []apimodels.EventOperation{apimodels.EventOperationUpdate},
+		After:       jobEvents[0].SeqNum,
+	})
+	s.Require().NoError(err)
+	event := s.nextEvent(resumed)
+	s.Equal(jobEvents[1].SeqNum, event.SeqNum)
+	s.Equal(apimodels.EventOperationUpdate, event.Operation)
+	s.Equal(jobID, event.Job.ID)
+}
+
+func (s *ServerSuite) TestStreamEventsInvalidFilter() {
+	ctx := context.Background()
+	_, err := s.client.Events().Stream(ctx, &apimodels.StreamEventsRequest{
+		ObjectTypes: []apimodels.EventObjectType{"node"},
+	})
+	s.Require().Error(err)
+
+	_, err = s.client.Events().Stream(ctx, &apimodels.StreamEventsRequest{JobID: "unknown-job"})
+	s.Require().Error(err)
+}
+
+// nextEvent returns the next event of the stream, failing the test on errors
+func (s *ServerSuite) nextEvent(stream <-chan *concurrency.AsyncResult[apimodels.Event]) apimodels.Event {
+	result, ok := <-stream
+	s.Require().True(ok, "event stream closed")
+	s.Require().NoError(result.Err)
+	return result.Value
+}
+
+// isJobCompleted returns true if the events include a job reaching a terminal state
+func (s *ServerSuite) isJobCompleted(events []apimodels.Event) bool {
+	for _, event := range events {
+		if event.Job != nil && event.Job.IsTerminal() {
+			return true
+		}
+	}
+	return false
+}
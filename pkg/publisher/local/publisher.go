@@ -2,11 +2,14 @@ package local
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"path"
+	"path/filepath"
 
 	pkgerrors "github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -112,7 +115,95 @@ func (p *Publisher) PublishResult(
 	}, nil
 }
 
+// PublishPartialResult copies the changed files of the results directory to a directory of the execution in
+// the base directory, which the local server serves as a tar.gz archive. Files are copied atomically, so that
+// the archive only contains complete files.
+func (p *Publisher) PublishPartialResult(
+	ctx context.Context, execution *models.Execution, resultPath string, update publisher.ResultUpdate,
+) (models.SpecConfig, error) {
+	dir := path.Join(p.baseDirectory, execution.ID+partialSuffix)
+	if err := os.MkdirAll(dir, partialDirPerm); err != nil {
+		return models.SpecConfig{}, pkgerrors.Wrap(err, "local publisher failed to create partial result directory")
+	}
+	for _, name := range update.Removed {
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(name))); err != nil && !os.IsNotExist(err) {
+			return models.SpecConfig{}, pkgerrors.Wrap(err, "local publisher failed to remove partial result file")
+		}
+	}
+	for _, name := range update.Changed {
+		name = filepath.FromSlash(name)
+		if err := p.copyFile(filepath.Join(resultPath, name), filepath.Join(dir, name)); err != nil {
+			return models.SpecConfig{}, pkgerrors.Wrap(err, "local publisher failed to copy partial result file")
+		}
+	}
+	manifest, err := json.Marshal(update.Manifest)
+	if err != nil {
+		return models.SpecConfig{}, pkgerrors.Wrap(err, "local publisher failed to encode manifest")
+	}
+	if err = p.writeFile(filepath.Join(dir, publisher.ManifestFileName), func(w io.Writer) error {
+		_, err := w.Write(manifest)
+		return err
+	}); err != nil {
+		return models.SpecConfig{}, pkgerrors.Wrap(err, "local publisher failed to write manifest")
+	}
+
+	downloadURL, err := url.JoinPath(p.urlPrefix, execution.ID+partialSuffix+".tar.gz")
+	if err != nil {
+		return models.SpecConfig{}, pkgerrors.Wrap(err, "local publisher failed to generate download URL")
+	}
+	return models.SpecConfig{
+		Type: models.StorageSourceURL,
+		Params: map[string]interface{}{
+			"URL": downloadURL,
+		},
+	}, nil
+}
+
+// CleanupPartialResult removes the directory of the partial results of the execution, which is no longer
+// served once the execution ended
+func (p *Publisher) CleanupPartialResult(ctx context.Context, execution *models.Execution) error {
+	if err := os.RemoveAll(path.Join(p.baseDirectory, execution.ID+partialSuffix)); err != nil {
+		return pkgerrors.Wrap(err, "local publisher failed to remove partial result directory")
+	}
+	return nil
+}
+
+// copyFile copies the source file to the target file
+func (p *Publisher) copyFile(source, target string) error {
+	src, err := os.Open(source) //nolint:gosec // G304: source from local result storage, application controlled
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+	return p.writeFile(target, func(w io.Writer) error {
+		_, err := io.Copy(w, src)
+		return err
+	})
+}
+
+// writeFile writes the target file through a temporary file of the base directory,
+// which is renamed to the target once written
+func (p *Publisher) writeFile(target string, write func(io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(target), partialDirPerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(p.baseDirectory, ".partial-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if err = write(tmp); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
 var _ publisher.Publisher = (*Publisher)(nil)
+var _ publisher.StreamingPublisher = (*Publisher)(nil)
 
 func ResolveAddress(ctx context.Context, address string) string {
 	addressType, ok := network.AddressTypeFromString(address)
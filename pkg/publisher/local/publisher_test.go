@@ -4,6 +4,7 @@ package local_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher/local"
 )
 
@@ -69,3 +71,65 @@ func (s *PublisherTestSuite) TestPublishFolder() {
 	expected := fmt.Sprintf("http://%s:%d/eid.tar.gz", defaultHost, defaultPort)
 	s.Require().Equal(expected, cfg.Params["URL"])
 }
+
+func (s *PublisherTestSuite) TestPublishPartialResult() {
+	source := s.T().TempDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(source, "file.txt"), []byte("test"), 0644))
+	s.Require().NoError(os.Mkdir(filepath.Join(source, "subdir"), 0755))
+	s.Require().NoError(os.WriteFile(filepath.Join(source, "subdir", "file.txt"), []byte("test"), 0644))
+
+	exec := models.Execution{
+		ID:    "eid",
+		JobID: "jid",
+	}
+	manifest, err := publisher.NewManifest(source)
+	s.Require().NoError(err)
+	changed, _ := manifest.Diff(publisher.Manifest{})
+
+	cfg, err := s.pub.PublishPartialResult(s.ctx, &exec, source, publisher.ResultUpdate{
+		Manifest: manifest,
+		Changed:  changed,
+	})
+	s.Require().NoError(err)
+
+	expected := fmt.Sprintf("http://%s:%d/eid-partial.tar.gz", defaultHost, defaultPort)
+	s.Require().Equal(expected, cfg.Params["URL"])
+
+	partialDir := filepath.Join(s.baseDir, "eid-partial")
+	s.FileExists(filepath.Join(partialDir, "file.txt"))
+	s.FileExists(filepath.Join(partialDir, "subdir", "file.txt"))
+	s.FileExists(filepath.Join(partialDir, publisher.ManifestFileName))
+
+	// removed files are removed from the partial result
+	s.Require().NoError(os.Remove(filepath.Join(source, "subdir", "file.txt")))
+	previous := manifest
+	manifest, err = publisher.NewManifest(source)
+	s.Require().NoError(err)
+	manifest.Complete = true
+	changed, removed := manifest.Diff(previous)
+
+	_, err = s.pub.PublishPartialResult(s.ctx, &exec, source, publisher.ResultUpdate{
+		Manifest: manifest,
+		Changed:  changed,
+		Removed:  removed,
+		Previous: &cfg,
+	})
+	s.Require().NoError(err)
+	s.NoFileExists(filepath.Join(partialDir, "subdir", "file.txt"))
+	s.FileExists(filepath.Join(partialDir, "file.txt"))
+
+	content, err := os.ReadFile(filepath.Join(partialDir, publisher.ManifestFileName))
+	s.Require().NoError(err)
+	var published publisher.Manifest
+	s.Require().NoError(json.Unmarshal(content, &published))
+	s.Equal(manifest, published)
+
+	// no temporary files are left in the base directory
+	entries, err := os.ReadDir(s.baseDir)
+	s.Require().NoError(err)
+	s.Len(entries, 1)
+
+	// the partial result is removed once the execution ended
+	s.Require().NoError(s.pub.CleanupPartialResult(s.ctx, &exec))
+	s.NoDirExists(partialDir)
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/gzip"
 )
 
 type LocalPublisherServer struct {
@@ -29,10 +34,29 @@ func NewLocalPublisherServer(ctx context.Context, directory string, port int) *L
 	}
 }
 
+// servePartialResults serves the directories of partial results as tar.gz archives,
+// and the other files with the files handler
+func (s *LocalPublisherServer) servePartialResults(files http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, isArchive := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/"), ".tar.gz")
+		if isArchive && strings.HasSuffix(name, partialSuffix) && !strings.Contains(name, "/") {
+			dir := filepath.Join(s.rootDirectory, name)
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				w.Header().Set("Content-Type", "application/gzip")
+				if err = gzip.Compress(dir, w); err != nil {
+					log.Ctx(r.Context()).Error().Err(err).Str("path", dir).Msg("failed to serve partial result")
+				}
+				return
+			}
+		}
+		files.ServeHTTP(w, r)
+	})
+}
+
 func (s *LocalPublisherServer) Run(ctx context.Context) {
 	fs := http.FileServer(http.Dir(s.rootDirectory))
 	mux := http.NewServeMux()
-	mux.Handle("/", fs)
+	mux.Handle("/", s.servePartialResults(fs))
 
 	errChan := make(chan error, 1)
 	server := &http.Server{
@@ -6,6 +6,13 @@ import (
 
 const errComponent = "LocalPublisher"
 
+const (
+	// partialSuffix is appended to the ID of an execution to name the directory of its partial result,
+	// which the server serves as an archive named after the directory
+	partialSuffix  = "-partial"
+	partialDirPerm = 0o755
+)
+
 // PublisherSpec mainly exists as a form of documentation to indicate the local publisher spec does not contain params
 type PublisherSpec struct{}
 
@@ -0,0 +1,81 @@
+package publisher
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// ManifestFileName is the name of the manifest written along with the results published while an execution runs
+const ManifestFileName = "bacalhau-manifest.json"
+
+// Manifest lists the files of the results of an execution published while it runs
+type Manifest struct {
+	// Complete is true once the execution completed, and the manifest lists all of its results
+	Complete bool `json:"Complete"`
+	// Files are the published files, by their path relative to the results directory
+	Files map[string]ManifestFile `json:"Files"`
+}
+
+// ManifestFile is a file of a manifest
+type ManifestFile struct {
+	Size    int64 `json:"Size"`
+	ModTime int64 `json:"ModTime"`
+}
+
+// NewManifest returns the manifest of the regular files of the results directory. The top-level entries
+// of the directory with the excluded names are left out of the manifest.
+func NewManifest(dir string, exclude ...string) (Manifest, error) {
+	manifest := Manifest{Files: make(map[string]ManifestFile)}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if filepath.Dir(rel) == "." && slices.Contains(exclude, rel) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || rel == ManifestFileName {
+			return nil
+		}
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			// removed while walking the directory
+			return nil
+		} else if err != nil {
+			return err
+		}
+		manifest.Files[filepath.ToSlash(rel)] = ManifestFile{
+			Size:    info.Size(),
+			ModTime: info.ModTime().UnixNano(),
+		}
+		return nil
+	})
+	return manifest, err
+}
+
+// Diff returns the files of the manifest that are new or changed since the previous manifest,
+// and the files of the previous manifest that were removed
+func (m Manifest) Diff(previous Manifest) (changed []string, removed []string) {
+	for name, file := range m.Files {
+		if previousFile, ok := previous.Files[name]; !ok || previousFile != file {
+			changed = append(changed, name)
+		}
+	}
+	for name := range previous.Files {
+		if _, ok := m.Files[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(removed)
+	return changed, removed
+}
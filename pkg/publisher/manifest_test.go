@@ -0,0 +1,72 @@
+//go:build unit || !integration
+
+package publisher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ManifestTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func TestManifestTestSuite(t *testing.T) {
+	suite.Run(t, new(ManifestTestSuite))
+}
+
+func (s *ManifestTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.writeFile("stdout", "hello")
+	s.writeFile("outputs/data.csv", "a,b")
+	s.writeFile("checkpoint/state", "step 1")
+	s.writeFile(ManifestFileName, "{}")
+}
+
+func (s *ManifestTestSuite) writeFile(name, content string) {
+	path := filepath.Join(s.dir, filepath.FromSlash(name))
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o644))
+}
+
+func (s *ManifestTestSuite) TestNewManifest() {
+	manifest, err := NewManifest(s.dir, "checkpoint")
+	s.Require().NoError(err)
+	s.False(manifest.Complete)
+	s.Len(manifest.Files, 2)
+	s.Equal(int64(5), manifest.Files["stdout"].Size)
+	s.Equal(int64(3), manifest.Files["outputs/data.csv"].Size)
+	s.NotZero(manifest.Files["outputs/data.csv"].ModTime)
+}
+
+func (s *ManifestTestSuite) TestNewManifestMissingDir() {
+	_, err := NewManifest(filepath.Join(s.dir, "missing"))
+	s.Error(err)
+}
+
+func (s *ManifestTestSuite) TestDiff() {
+	previous, err := NewManifest(s.dir)
+	s.Require().NoError(err)
+
+	changed, removed := previous.Diff(Manifest{})
+	s.Equal([]string{"checkpoint/state", "outputs/data.csv", "stdout"}, changed)
+	s.Empty(removed)
+
+	changed, removed = previous.Diff(previous)
+	s.Empty(changed)
+	s.Empty(removed)
+
+	s.writeFile("stdout", "hello world")
+	s.writeFile("outputs/more.csv", "c,d")
+	s.Require().NoError(os.Remove(filepath.Join(s.dir, "checkpoint", "state")))
+	current, err := NewManifest(s.dir)
+	s.Require().NoError(err)
+
+	changed, removed = current.Diff(previous)
+	s.Equal([]string{"outputs/more.csv", "stdout"}, changed)
+	s.Equal([]string{"checkpoint/state"}, removed)
+}
@@ -1,10 +1,13 @@
 package s3
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -13,7 +16,7 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/gzip"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
-	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	pkgpublisher "github.com/bacalhau-project/bacalhau/pkg/publisher"
 	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
 )
 
@@ -23,7 +26,7 @@ type PublisherParams struct {
 }
 
 // Compile-time check that publisher implements the correct interface:
-var _ publisher.Publisher = (*Publisher)(nil)
+var _ pkgpublisher.StreamingPublisher = (*Publisher)(nil)
 
 type Publisher struct {
 	localDir       string
@@ -143,42 +146,78 @@ func (publisher *Publisher) publishDirectory(
 		if info.IsDir() {
 			return nil // skip directories
 		}
-		// Read the file contents.
-		data, err := os.Open(path) //nolint:gosec // G304: path from local result storage, application controlled
-		if err != nil {
-			return err
-		}
-		defer func() { _ = data.Close() }()
-
 		relativePath, err := filepath.Rel(resultPath, path)
 		if err != nil {
 			return err
 		}
+		return uploadFile(ctx, client, spec.Bucket, key+filepath.ToSlash(relativePath), path)
+	})
 
-		putObjectInput := &s3.PutObjectInput{
-			Bucket: aws.String(spec.Bucket),
-			Key:    aws.String(key + filepath.ToSlash(relativePath)),
-			Body:   data,
-		}
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+
+	return models.SpecConfig{
+		Type: models.StorageSourceS3,
+		Params: s3helper.SourceSpec{
+			Bucket:   spec.Bucket,
+			Key:      key,
+			Region:   spec.Region,
+			Endpoint: spec.Endpoint,
+		}.ToMap(),
+	}, nil
+}
+
+// PublishPartialResult uploads the changed files of the results directory of a running execution, and deletes
+// the removed ones, as objects under a prefix of the execution. The manifest of the update is uploaded along
+// with them. The prefix is the published key as a directory, with the .tar.gz extension of archives trimmed,
+// so that plain encoded results are streamed to where they are eventually published.
+func (publisher *Publisher) PublishPartialResult(
+	ctx context.Context,
+	execution *models.Execution,
+	resultPath string,
+	update pkgpublisher.ResultUpdate,
+) (models.SpecConfig, error) {
+	spec, err := s3helper.DecodePublisherSpec(execution.Job.Task().Publisher)
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+	client := publisher.clientProvider.GetClient(spec.Endpoint, spec.Region)
 
-		// Only use SHA256 checksums if the endpoint is AWS, as it is
-		// not supported by other S3-compatible providers, such as GCP buckets
-		if client.IsAWSEndpoint() {
-			putObjectInput.ChecksumAlgorithm = types.ChecksumAlgorithmSha256
+	// keep publishing under the prefix of the previous partial result, as the key may include the time
+	key := ParsePublishedKey(strings.TrimSuffix(spec.Key, ".tar.gz"), execution, false)
+	if update.Previous != nil {
+		if previous, err := s3helper.DecodeSourceSpec(update.Previous); err == nil {
+			key = previous.Key
 		}
+	}
 
-		// Upload the file to S3.
-		res, err := client.Uploader.Upload(ctx, putObjectInput)
-		if err != nil {
-			return err
+	for _, name := range update.Removed {
+		if _, err = client.S3.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(spec.Bucket),
+			Key:    aws.String(key + name),
+		}); err != nil {
+			return models.SpecConfig{}, s3helper.NewS3PublisherServiceError(err)
 		}
-		log.Debug().Msgf("Uploaded s3://%s/%s", spec.Bucket, aws.ToString(res.Key))
-		return nil
-	})
+	}
+	for _, name := range update.Changed {
+		if err = uploadFile(ctx, client, spec.Bucket, key+name, filepath.Join(resultPath, filepath.FromSlash(name))); err != nil {
+			return models.SpecConfig{}, s3helper.NewS3PublisherServiceError(err)
+		}
+	}
 
+	manifest, err := json.Marshal(update.Manifest)
 	if err != nil {
 		return models.SpecConfig{}, err
 	}
+	if _, err = client.Uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(spec.Bucket),
+		Key:         aws.String(key + pkgpublisher.ManifestFileName),
+		Body:        bytes.NewReader(manifest),
+		ContentType: aws.String("application/json"),
+	}); err != nil {
+		return models.SpecConfig{}, s3helper.NewS3PublisherServiceError(err)
+	}
 
 	return models.SpecConfig{
 		Type: models.StorageSourceS3,
@@ -190,3 +229,37 @@ func (publisher *Publisher) publishDirectory(
 		}.ToMap(),
 	}, nil
 }
+
+// CleanupPartialResult does nothing, as partial results are uploaded where the results are published
+func (publisher *Publisher) CleanupPartialResult(ctx context.Context, execution *models.Execution) error {
+	return nil
+}
+
+// uploadFile uploads the file at path to the key of the bucket
+func uploadFile(ctx context.Context, client *s3helper.ClientWrapper, bucket, key, path string) error {
+	data, err := os.Open(path) //nolint:gosec // G304: path from local result storage, application controlled
+	if err != nil {
+		return err
+	}
+	defer func() { _ = data.Close() }()
+
+	putObjectInput := &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   data,
+	}
+
+	// Only use SHA256 checksums if the endpoint is AWS, as it is
+	// not supported by other S3-compatible providers, such as GCP buckets
+	if client.IsAWSEndpoint() {
+		putObjectInput.ChecksumAlgorithm = types.ChecksumAlgorithmSha256
+	}
+
+	// Upload the file to S3.
+	res, err := client.Uploader.Upload(ctx, putObjectInput)
+	if err != nil {
+		return err
+	}
+	log.Debug().Msgf("Uploaded s3://%s/%s", bucket, aws.ToString(res.Key))
+	return nil
+}
@@ -4,9 +4,11 @@ package s3_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -18,6 +20,7 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/gzip"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
 	s3publisher "github.com/bacalhau-project/bacalhau/pkg/publisher/s3"
 	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
 	s3test "github.com/bacalhau-project/bacalhau/pkg/s3/test"
@@ -277,3 +280,58 @@ func (s *PublisherTestSuite) TestPublish() {
 		})
 	}
 }
+
+func (s *PublisherTestSuite) TestPublishPartialResult() {
+	params := s.PreparePublisherSpec(s3helper.EncodingGzip)
+	resultPath := s.PrepareResultsPath()
+	execution := s.MockExecution(params)
+
+	manifest, err := publisher.NewManifest(resultPath)
+	s.Require().NoError(err)
+	changed, _ := manifest.Diff(publisher.Manifest{})
+	storageSpec, err := s.Publisher.PublishPartialResult(s.Ctx, execution, resultPath, publisher.ResultUpdate{
+		Manifest: manifest,
+		Changed:  changed,
+	})
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == "AccessDenied" {
+			s.T().Skip("No access to S3 bucket " + s.Bucket)
+		}
+	}
+	s.Require().NoError(err)
+
+	// partial results are published as plain objects under the published key
+	sourceSpec, err := s3helper.DecodeSourceSpec(&storageSpec)
+	s.Require().NoError(err)
+	s.Equal(strings.TrimSuffix(params.Key, ".tar.gz")+"/", sourceSpec.Key)
+
+	// removed files are deleted, and the following updates are published under the same key
+	s.Require().NoError(os.Remove(filepath.Join(resultPath, "outputs", "1.txt")))
+	previous := manifest
+	manifest, err = publisher.NewManifest(resultPath)
+	s.Require().NoError(err)
+	manifest.Complete = true
+	changed, removed := manifest.Diff(previous)
+	s.Require().Equal([]string{"outputs/1.txt"}, removed)
+	storageSpec, err = s.Publisher.PublishPartialResult(s.Ctx, execution, resultPath, publisher.ResultUpdate{
+		Manifest: manifest,
+		Changed:  changed,
+		Removed:  removed,
+		Previous: &storageSpec,
+	})
+	s.Require().NoError(err)
+	updatedSpec, err := s3helper.DecodeSourceSpec(&storageSpec)
+	s.Require().NoError(err)
+	s.Equal(sourceSpec.Key, updatedSpec.Key)
+
+	fetchedResults := s.GetResult(execution, &storageSpec)
+	content, err := os.ReadFile(filepath.Join(fetchedResults, publisher.ManifestFileName))
+	s.Require().NoError(err)
+	var published publisher.Manifest
+	s.Require().NoError(json.Unmarshal(content, &published))
+	s.Equal(manifest, published)
+
+	s.Require().NoError(os.Remove(filepath.Join(fetchedResults, publisher.ManifestFileName)))
+	s3test.AssertEqualDirectories(s.T(), resultPath, fetchedResults)
+}
@@ -18,11 +18,22 @@ type tracingPublisher struct {
 	name     string
 }
 
+// tracingStreamingPublisher traces publishers that also publish results while executions run
+type tracingStreamingPublisher struct {
+	*tracingPublisher
+	streaming publisher.StreamingPublisher
+}
+
+// Wrap returns a publisher tracing the delegate, which is a streaming publisher if the delegate is one
 func Wrap(delegate publisher.Publisher) publisher.Publisher {
-	return &tracingPublisher{
+	p := &tracingPublisher{
 		delegate: delegate,
 		name:     reflection.StructName(delegate),
 	}
+	if streaming, ok := delegate.(publisher.StreamingPublisher); ok {
+		return &tracingStreamingPublisher{tracingPublisher: p, streaming: streaming}
+	}
+	return p
 }
 
 func (t *tracingPublisher) IsInstalled(ctx context.Context) (bool, error) {
@@ -57,4 +68,23 @@ func (t *tracingPublisher) PublishResult(
 	return t.delegate.PublishResult(ctx, execution, resultPath)
 }
 
+func (t *tracingStreamingPublisher) PublishPartialResult(
+	ctx context.Context, execution *models.Execution, resultPath string, update publisher.ResultUpdate,
+) (models.SpecConfig, error) {
+	ctx, span := telemetry.NewSpan(ctx, telemetry.GetTracer(), fmt.Sprintf("%s.PublishPartialResult", t.name),
+		trace.WithAttributes(execution.Job.MetricAttributes()...))
+	defer span.End()
+
+	return t.streaming.PublishPartialResult(ctx, execution, resultPath, update)
+}
+
+func (t *tracingStreamingPublisher) CleanupPartialResult(ctx context.Context, execution *models.Execution) error {
+	ctx, span := telemetry.NewSpan(ctx, telemetry.GetTracer(), fmt.Sprintf("%s.CleanupPartialResult", t.name),
+		trace.WithAttributes(execution.Job.MetricAttributes()...))
+	defer span.End()
+
+	return t.streaming.CleanupPartialResult(ctx, execution)
+}
+
 var _ publisher.Publisher = &tracingPublisher{}
+var _ publisher.StreamingPublisher = &tracingStreamingPublisher{}
@@ -34,3 +34,36 @@ type Publisher interface {
 		resultPath string,
 	) (models.SpecConfig, error)
 }
+
+// StreamingPublisher is an optional interface of publishers that can publish the results of an execution
+// incrementally while it runs, so that they are available before the execution completes.
+type StreamingPublisher interface {
+	Publisher
+
+	// PublishPartialResult publishes the files of the results directory that changed since the previous
+	// partial result of the execution, removes the files that were removed, and writes the manifest of
+	// the update along with them. It returns the location of the results published so far.
+	PublishPartialResult(
+		ctx context.Context,
+		execution *models.Execution,
+		resultPath string,
+		update ResultUpdate,
+	) (models.SpecConfig, error)
+
+	// CleanupPartialResult releases the partial results of an execution once it ended, after its results
+	// were published as a whole if it completed. Publishers whose partial results stay available, such as
+	// those published where the results are eventually published, have nothing to clean up.
+	CleanupPartialResult(ctx context.Context, execution *models.Execution) error
+}
+
+// ResultUpdate describes the changes to the results of a running execution since its previous partial result
+type ResultUpdate struct {
+	// Manifest lists all the files of the results directory
+	Manifest Manifest
+	// Changed are the files of the manifest that are new or changed since the previous partial result
+	Changed []string
+	// Removed are the files of the previous partial result that were removed
+	Removed []string
+	// Previous is the location of the previous partial result of the execution, if any
+	Previous *models.SpecConfig
+}
@@ -84,7 +84,7 @@ func NewTestHelper(t *testing.T, params HelperSuiteParams) *HelperSuite {
 	testConfig, err := config.NewTestConfig()
 	require.NoError(t, err)
 
-	storage := s3storage.NewStorage(time.Duration(testConfig.InputSources.ReadTimeout), clientProvider)
+	storage := s3storage.NewStorage(time.Duration(testConfig.InputSources.ReadTimeout), clientProvider, nil)
 
 	return &HelperSuite{
 		Bucket:         params.Bucket,
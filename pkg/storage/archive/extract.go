@@ -0,0 +1,297 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/bacalhau-project/bacalhau/pkg/util/closer"
+	"github.com/bacalhau-project/bacalhau/pkg/util/targzip"
+)
+
+const (
+	dirPerm fs.FileMode = 0o755
+
+	// tarMagicOffset is the offset of the magic of tar headers, which identifies tar archives
+	tarMagicOffset = 257
+	// sniffSize is the number of bytes read to identify the format of an archive
+	sniffSize = 512
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	zipMagic  = []byte("PK\x03\x04")
+	tarMagic  = []byte("ustar")
+
+	// compressedExtensions are stripped from the name of compressed files that are not tar archives
+	compressedExtensions = []string{".gz", ".gzip", ".zst", ".zstd"}
+)
+
+// ErrSizeLimitExceeded is returned when the extracted content of an archive is bigger than the limit
+var ErrSizeLimitExceeded = errors.New("extracted content exceeds the size limit")
+
+// Extract extracts the archive into the destination directory, which must exist. Tar archives,
+// optionally compressed with gzip or zstd, and zip archives are extracted. Files compressed with gzip
+// or zstd that are not tar archives are decompressed into the directory.
+// Extraction fails if the extracted content is bigger than limit bytes.
+func Extract(ctx context.Context, archivePath, dst string, limit uint64) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer closer.CloseWithLogOnError(archivePath, f)
+
+	r := bufio.NewReaderSize(f, sniffSize)
+	header, err := r.Peek(sniffSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	l := &limiter{remaining: limit, limit: limit}
+
+	switch {
+	case bytes.HasPrefix(header, zipMagic):
+		return extractZip(ctx, archivePath, dst, l)
+	case bytes.HasPrefix(header, gzipMagic):
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer closer.CloseWithLogOnError(archivePath, zr)
+		return extractCompressed(ctx, zr, archivePath, dst, l)
+	case bytes.HasPrefix(header, zstdMagic):
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		return extractCompressed(ctx, zr, archivePath, dst, l)
+	case isTar(header):
+		return extractTar(ctx, r, dst, l)
+	default:
+		return fmt.Errorf("%s is not a tar, zip, gzip or zstd archive", filepath.Base(archivePath))
+	}
+}
+
+// extractCompressed extracts the decompressed stream into the directory, either as a tar archive,
+// or as a single file named after the compressed file
+func extractCompressed(ctx context.Context, src io.Reader, archivePath, dst string, l *limiter) error {
+	r := bufio.NewReaderSize(src, sniffSize)
+	header, err := r.Peek(sniffSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if isTar(header) {
+		return extractTar(ctx, r, dst, l)
+	}
+	return writeFile(filepath.Join(dst, decompressedName(archivePath)), r, 0o644, l)
+}
+
+func extractTar(ctx context.Context, src io.Reader, dst string, l *limiter) error {
+	tr := tar.NewReader(src)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target, err := safePath(dst, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(target, dirPerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err = writeFile(target, tr, fs.FileMode(header.Mode), l); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err = symlink(dst, target, header.Linkname); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			source, err := safePath(dst, header.Linkname)
+			if err != nil {
+				return err
+			}
+			if err = os.MkdirAll(filepath.Dir(target), dirPerm); err != nil {
+				return err
+			}
+			if err = os.Link(source, target); err != nil {
+				return err
+			}
+		default:
+			// devices, fifos and other special files are not extracted
+		}
+	}
+}
+
+func extractZip(ctx context.Context, archivePath, dst string, l *limiter) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return err
+	}
+	defer closer.CloseWithLogOnError(archivePath, zr)
+
+	for _, file := range zr.File {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		target, err := safePath(dst, file.Name)
+		if err != nil {
+			return err
+		}
+		mode := file.Mode()
+		switch {
+		case mode.IsDir():
+			err = os.MkdirAll(target, dirPerm)
+		case mode&fs.ModeSymlink != 0:
+			err = extractZipSymlink(file, dst, target)
+		case mode.IsRegular():
+			err = extractZipFile(file, target, l)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractZipFile(file *zip.File, target string, l *limiter) error {
+	r, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer closer.CloseWithLogOnError(file.Name, r)
+	return writeFile(target, r, file.Mode(), l)
+}
+
+// extractZipSymlink creates the symlink of the zip entry, whose content is the target of the link
+func extractZipSymlink(file *zip.File, dst, target string) error {
+	r, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer closer.CloseWithLogOnError(file.Name, r)
+	linkname, err := io.ReadAll(io.LimitReader(r, sniffSize))
+	if err != nil {
+		return err
+	}
+	return symlink(dst, target, string(linkname))
+}
+
+// writeFile writes the content to the file, counting its size against the limit
+func writeFile(target string, src io.Reader, mode fs.FileMode, l *limiter) error {
+	if err := os.MkdirAll(filepath.Dir(target), dirPerm); err != nil {
+		return err
+	}
+	// only keep the permission bits, without setuid, setgid or sticky bits
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm()|0o200)
+	if err != nil {
+		return err
+	}
+	defer closer.CloseWithLogOnError(target, f)
+	return l.copy(f, src)
+}
+
+// symlink creates the symlink, if its target is a relative path within the destination directory
+func symlink(dst, target, linkname string) error {
+	if filepath.IsAbs(linkname) {
+		return fmt.Errorf("archive contained symlink %s to absolute path %q", filepath.Base(target), linkname)
+	}
+	resolved := filepath.Join(filepath.Dir(target), linkname)
+	root := filepath.Clean(dst)
+	if resolved != root && !strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+		return fmt.Errorf("archive contained symlink %s to %q outside of the extracted content", filepath.Base(target), linkname)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), dirPerm); err != nil {
+		return err
+	}
+	return os.Symlink(linkname, target)
+}
+
+// safePath returns the path in the destination directory of an entry of the archive. Entries cannot
+// escape the directory through their name, nor through the symlinks previously extracted.
+func safePath(dst, name string) (string, error) {
+	target, err := targzip.SafeArchivePath(dst, name)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(dst, target)
+	if err != nil || rel == "." {
+		return target, err
+	}
+	current := dst
+	for _, component := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, component)
+		info, err := os.Lstat(current)
+		if errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return "", fmt.Errorf("archive contained entry %q through symlink %q", name, component)
+		}
+	}
+	return target, nil
+}
+
+func isTar(header []byte) bool {
+	return len(header) >= tarMagicOffset+len(tarMagic) &&
+		bytes.Equal(header[tarMagicOffset:tarMagicOffset+len(tarMagic)], tarMagic)
+}
+
+// decompressedName returns the name of a compressed file once decompressed
+func decompressedName(archivePath string) string {
+	name := filepath.Base(archivePath)
+	for _, ext := range compressedExtensions {
+		if trimmed, found := strings.CutSuffix(name, ext); found && trimmed != "" {
+			return trimmed
+		}
+	}
+	return name
+}
+
+// limiter bounds the total size of the extracted files
+type limiter struct {
+	remaining uint64
+	limit     uint64
+}
+
+func (l *limiter) copy(dst io.Writer, src io.Reader) error {
+	// copy one more byte than remaining to detect content exceeding the limit
+	n, err := io.CopyN(dst, src, int64(min(l.remaining, uint64(math.MaxInt64-1)))+1)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if uint64(n) > l.remaining {
+		return fmt.Errorf("%w of %s", ErrSizeLimitExceeded, datasize.ByteSize(l.limit).HumanReadable())
+	}
+	l.remaining -= uint64(n)
+	return nil
+}
@@ -0,0 +1,197 @@
+//go:build unit || !integration
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// entry is an entry of a test archive, which is a symlink if link is set
+type entry struct {
+	name    string
+	content string
+	link    string
+}
+
+var testEntries = []entry{
+	{name: "data/a.txt", content: "hello"},
+	{name: "data/b.txt", content: "world"},
+	{name: "data/latest.txt", link: "b.txt"},
+}
+
+type ExtractSuite struct {
+	suite.Suite
+	ctx context.Context
+	dir string
+	dst string
+}
+
+func TestExtractSuite(t *testing.T) {
+	suite.Run(t, new(ExtractSuite))
+}
+
+func (s *ExtractSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.dir = s.T().TempDir()
+	s.dst = s.T().TempDir()
+}
+
+func tarArchive(t *testing.T, entries []entry) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.content)), Typeflag: tar.TypeReg}
+		if e.link != "" {
+			header = &tar.Header{Name: e.name, Linkname: e.link, Typeflag: tar.TypeSymlink}
+		}
+		require.NoError(t, tw.WriteHeader(header))
+		_, err := tw.Write([]byte(e.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T, entries []entry) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name}
+		content := e.content
+		header.SetMode(0o644)
+		if e.link != "" {
+			header.SetMode(os.ModeSymlink | 0o777)
+			content = e.link
+		}
+		w, err := zw.CreateHeader(header)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func zstded(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	zw, err := zstd.NewWriter(&buf)
+	require.NoError(t, err)
+	_, err = zw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func (s *ExtractSuite) write(name string, data []byte) string {
+	path := filepath.Join(s.dir, name)
+	s.Require().NoError(os.WriteFile(path, data, 0o644))
+	return path
+}
+
+func (s *ExtractSuite) assertContent(name, expected string) {
+	content, err := os.ReadFile(filepath.Join(s.dst, name))
+	s.Require().NoError(err)
+	s.Equal(expected, string(content))
+}
+
+func (s *ExtractSuite) TestFormats() {
+	for _, test := range []struct {
+		name string
+		data []byte
+	}{
+		{name: "data.tar", data: tarArchive(s.T(), testEntries)},
+		{name: "data.tar.gz", data: gzipped(s.T(), tarArchive(s.T(), testEntries))},
+		{name: "data.tar.zst", data: zstded(s.T(), tarArchive(s.T(), testEntries))},
+		{name: "data.zip", data: zipArchive(s.T(), testEntries)},
+		// formats are detected from the content rather than the name
+		{name: "download", data: gzipped(s.T(), tarArchive(s.T(), testEntries))},
+	} {
+		s.Run(test.name, func() {
+			s.dst = s.T().TempDir()
+			s.Require().NoError(Extract(s.ctx, s.write(test.name, test.data), s.dst, 1024))
+			s.assertContent("data/a.txt", "hello")
+			s.assertContent("data/b.txt", "world")
+			s.assertContent("data/latest.txt", "world")
+		})
+	}
+}
+
+func (s *ExtractSuite) TestCompressedFile() {
+	s.Require().NoError(Extract(s.ctx, s.write("data.csv.gz", gzipped(s.T(), []byte("a,b"))), s.dst, 1024))
+	s.assertContent("data.csv", "a,b")
+
+	s.Require().NoError(Extract(s.ctx, s.write("data.json.zst", zstded(s.T(), []byte("{}"))), s.dst, 1024))
+	s.assertContent("data.json", "{}")
+}
+
+func (s *ExtractSuite) TestSizeLimit() {
+	path := s.write("data.tar", tarArchive(s.T(), testEntries))
+	s.Require().NoError(Extract(s.ctx, path, s.dst, uint64(len("helloworld"))))
+
+	err := Extract(s.ctx, path, s.T().TempDir(), uint64(len("helloworld"))-1)
+	s.Require().ErrorIs(err, ErrSizeLimitExceeded)
+
+	// the limit applies to the decompressed content
+	err = Extract(s.ctx, s.write("big.gz", gzipped(s.T(), make([]byte, 1<<20))), s.T().TempDir(), 1<<10)
+	s.Require().ErrorIs(err, ErrSizeLimitExceeded)
+}
+
+func (s *ExtractSuite) TestPathTraversal() {
+	for _, test := range []struct {
+		name    string
+		entries []entry
+	}{
+		{name: "parent", entries: []entry{{name: "../evil.txt", content: "evil"}}},
+		{name: "nested parent", entries: []entry{{name: "data/../../evil.txt", content: "evil"}}},
+		{name: "absolute", entries: []entry{{name: "/tmp/evil.txt", content: "evil"}}},
+		{name: "absolute symlink", entries: []entry{{name: "passwd", link: "/etc/passwd"}}},
+		{name: "escaping symlink", entries: []entry{{name: "data/up", link: "../.."}}},
+		{name: "write through symlink", entries: []entry{
+			{name: "data/up", link: ".."},
+			{name: "data/up/evil.txt", content: "evil"},
+		}},
+	} {
+		s.Run(test.name, func() {
+			dst := filepath.Join(s.T().TempDir(), "dst")
+			s.Require().NoError(os.Mkdir(dst, 0o755))
+			for _, data := range [][]byte{tarArchive(s.T(), test.entries), zipArchive(s.T(), test.entries)} {
+				s.Require().Error(Extract(s.ctx, s.write("archive", data), dst, 1024))
+			}
+			s.NoFileExists(filepath.Join(filepath.Dir(dst), "evil.txt"))
+		})
+	}
+}
+
+func (s *ExtractSuite) TestUnsupportedFormat() {
+	err := Extract(s.ctx, s.write("data.txt", []byte("plain text")), s.dst, 1024)
+	s.Require().ErrorContains(err, "is not a tar, zip, gzip or zstd archive")
+}
+
+func (s *ExtractSuite) TestCancelled() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	cancel()
+	err := Extract(ctx, s.write("data.tar", tarArchive(s.T(), testEntries)), s.dst, 1024)
+	s.Require().ErrorIs(err, context.Canceled)
+	_, err = os.Stat(filepath.Join(s.dst, "data"))
+	s.ErrorIs(err, os.ErrNotExist)
+}
@@ -0,0 +1,166 @@
+// Package archive provides a storage decorator that extracts archive inputs into their target,
+// so that jobs can use the content of archives without tools to extract them, such as WASM jobs.
+//
+// The decorator applies to the inputs that set Extract. As the size of the extracted content is not
+// known before the archive is downloaded, it is bounded by a ratio of the size of the archive, and a
+// maximum size. The volume size reported for bidding includes that bound, and extraction fails if the
+// content of the archive exceeds it.
+package archive
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+)
+
+// Params are the limits of the size of the extracted content of archives
+type Params struct {
+	// MaxRatio is how many times bigger than an archive its extracted content can be
+	MaxRatio uint64
+	// MaxSize is the maximum size of the extracted content of an archive. Unbounded if zero.
+	MaxSize uint64
+}
+
+type archiveStorage struct {
+	delegate storage.Storage
+	maxRatio uint64
+	maxSize  uint64
+}
+
+// Wrap returns a storage extracting the inputs of the delegate storage that set Extract
+func Wrap(delegate storage.Storage, params Params) storage.Storage {
+	return &archiveStorage{
+		delegate: delegate,
+		maxRatio: params.MaxRatio,
+		maxSize:  params.MaxSize,
+	}
+}
+
+func (s *archiveStorage) IsInstalled(ctx context.Context) (bool, error) {
+	return s.delegate.IsInstalled(ctx)
+}
+
+// CachedSources returns the input sources cached by the delegate storage, if it caches inputs
+func (s *archiveStorage) CachedSources() []string {
+	if provider, ok := s.delegate.(storage.CachedSourcesProvider); ok {
+		return provider.CachedSources()
+	}
+	return nil
+}
+
+func (s *archiveStorage) HasStorageLocally(ctx context.Context, input models.InputSource) (bool, error) {
+	return s.delegate.HasStorageLocally(ctx, input)
+}
+
+// GetVolumeSize returns the size of the archive and of its extracted content for inputs to extract,
+// as both are on disk while the archive is extracted
+func (s *archiveStorage) GetVolumeSize(
+	ctx context.Context, execution *models.Execution, input models.InputSource) (uint64, error) {
+	size, err := s.delegate.GetVolumeSize(ctx, execution, input)
+	if err != nil || !input.Extract {
+		return size, err
+	}
+	extracted := s.extractedSizeLimit(size)
+	if extracted > math.MaxUint64-size {
+		return math.MaxUint64, nil
+	}
+	return size + extracted, nil
+}
+
+// PrepareStorage prepares the input with the delegate storage, and extracts it into a new directory
+// of the storage directory if the input sets Extract. The archive is cleaned up once extracted.
+func (s *archiveStorage) PrepareStorage(
+	ctx context.Context,
+	storageDirectory string,
+	execution *models.Execution,
+	input models.InputSource) (storage.StorageVolume, error) {
+	volume, err := s.delegate.PrepareStorage(ctx, storageDirectory, execution, input)
+	if err != nil || !input.Extract {
+		return volume, err
+	}
+	defer func() {
+		if cleanupErr := s.delegate.CleanupStorage(ctx, input, volume); cleanupErr != nil {
+			log.Ctx(ctx).Warn().Err(cleanupErr).Str("Source", volume.Source).Msg("failed to cleanup extracted archive")
+		}
+	}()
+
+	archivePath, err := archiveFile(volume.Source)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+
+	outputPath, err := os.MkdirTemp(storageDirectory, "extract-*")
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	if err = Extract(ctx, archivePath, outputPath, s.extractedSizeLimit(uint64(info.Size()))); err != nil {
+		_ = os.RemoveAll(outputPath)
+		return storage.StorageVolume{}, fmt.Errorf("failed to extract input %s: %w", filepath.Base(archivePath), err)
+	}
+
+	// the extracted content belongs to the execution, unlike the prepared archive that can be shared
+	return storage.StorageVolume{
+		Type:   storage.StorageVolumeConnectorBind,
+		Source: outputPath,
+		Target: input.Target,
+	}, nil
+}
+
+func (s *archiveStorage) CleanupStorage(ctx context.Context, input models.InputSource, volume storage.StorageVolume) error {
+	if !input.Extract {
+		return s.delegate.CleanupStorage(ctx, input, volume)
+	}
+	log.Ctx(ctx).Debug().Str("Path", volume.Source).Msg("Cleaning up extracted input")
+	return os.RemoveAll(volume.Source)
+}
+
+func (s *archiveStorage) Upload(ctx context.Context, path string) (models.SpecConfig, error) {
+	return s.delegate.Upload(ctx, path)
+}
+
+// extractedSizeLimit returns the maximum size of the extracted content of an archive of the size
+func (s *archiveStorage) extractedSizeLimit(archiveSize uint64) uint64 {
+	limit := uint64(math.MaxUint64)
+	if s.maxRatio > 0 && archiveSize <= math.MaxUint64/s.maxRatio {
+		limit = archiveSize * s.maxRatio
+	}
+	if s.maxSize > 0 {
+		limit = min(limit, s.maxSize)
+	}
+	return limit
+}
+
+// archiveFile returns the archive of a prepared volume, which is either the volume itself,
+// or the single file of the volume directory
+func archiveFile(source string) (string, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return source, nil
+	}
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) != 1 || entries[0].IsDir() {
+		return "", fmt.Errorf("cannot extract input %s: expected a single archive, found %d entries",
+			filepath.Base(source), len(entries))
+	}
+	return filepath.Join(source, entries[0].Name()), nil
+}
+
+var _ storage.Storage = (*archiveStorage)(nil)
+var _ storage.CachedSourcesProvider = (*archiveStorage)(nil)
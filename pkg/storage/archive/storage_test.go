@@ -0,0 +1,151 @@
+//go:build unit || !integration
+
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+// fileStorage prepares inputs as a copy of a file in a new directory, and records the cleaned up volumes
+type fileStorage struct {
+	storage.Storage
+	path    string
+	cleaned []string
+}
+
+func (f *fileStorage) GetVolumeSize(context.Context, *models.Execution, models.InputSource) (uint64, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(info.Size()), nil
+}
+
+func (f *fileStorage) PrepareStorage(
+	_ context.Context, storageDirectory string, _ *models.Execution, input models.InputSource) (storage.StorageVolume, error) {
+	dir, err := os.MkdirTemp(storageDirectory, "*")
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	source := filepath.Join(dir, filepath.Base(f.path))
+	if err = os.WriteFile(source, data, 0o644); err != nil {
+		return storage.StorageVolume{}, err
+	}
+	return storage.StorageVolume{
+		Type:     storage.StorageVolumeConnectorBind,
+		ReadOnly: true,
+		Source:   source,
+		Target:   input.Target,
+	}, nil
+}
+
+func (f *fileStorage) CleanupStorage(_ context.Context, _ models.InputSource, volume storage.StorageVolume) error {
+	f.cleaned = append(f.cleaned, volume.Source)
+	return os.RemoveAll(filepath.Dir(volume.Source))
+}
+
+type StorageSuite struct {
+	suite.Suite
+	ctx      context.Context
+	delegate *fileStorage
+}
+
+func TestStorageSuite(t *testing.T) {
+	suite.Run(t, new(StorageSuite))
+}
+
+func (s *StorageSuite) SetupTest() {
+	s.ctx = context.Background()
+	path := filepath.Join(s.T().TempDir(), "data.tar.gz")
+	s.Require().NoError(os.WriteFile(path, gzipped(s.T(), tarArchive(s.T(), testEntries)), 0o644))
+	s.delegate = &fileStorage{path: path}
+}
+
+func (s *StorageSuite) input(extract bool) models.InputSource {
+	return models.InputSource{
+		Source:  &models.SpecConfig{Type: models.StorageSourceURL},
+		Target:  "/inputs",
+		Extract: extract,
+	}
+}
+
+func (s *StorageSuite) TestPrepareStorage() {
+	strg := Wrap(s.delegate, Params{MaxRatio: 20})
+	input := s.input(true)
+	volume, err := strg.PrepareStorage(s.ctx, s.T().TempDir(), mock.Execution(), input)
+	s.Require().NoError(err)
+	s.Equal("/inputs", volume.Target)
+	s.False(volume.ReadOnly)
+
+	content, err := os.ReadFile(filepath.Join(volume.Source, "data", "latest.txt"))
+	s.Require().NoError(err)
+	s.Equal("world", string(content))
+
+	// the archive is cleaned up once extracted
+	s.Require().Len(s.delegate.cleaned, 1)
+	s.NoFileExists(s.delegate.cleaned[0])
+
+	s.Require().NoError(strg.CleanupStorage(s.ctx, input, volume))
+	s.NoDirExists(volume.Source)
+	s.Len(s.delegate.cleaned, 1)
+}
+
+func (s *StorageSuite) TestPrepareStorage_WithoutExtract() {
+	strg := Wrap(s.delegate, Params{MaxRatio: 20})
+	input := s.input(false)
+	volume, err := strg.PrepareStorage(s.ctx, s.T().TempDir(), mock.Execution(), input)
+	s.Require().NoError(err)
+	s.True(volume.ReadOnly)
+	s.Equal("data.tar.gz", filepath.Base(volume.Source))
+	s.Empty(s.delegate.cleaned)
+
+	s.Require().NoError(strg.CleanupStorage(s.ctx, input, volume))
+	s.Equal([]string{volume.Source}, s.delegate.cleaned)
+}
+
+func (s *StorageSuite) TestPrepareStorage_SizeLimit() {
+	strg := Wrap(s.delegate, Params{MaxRatio: 20, MaxSize: uint64(len("hello"))})
+	storageDirectory := s.T().TempDir()
+	_, err := strg.PrepareStorage(s.ctx, storageDirectory, mock.Execution(), s.input(true))
+	s.Require().ErrorIs(err, ErrSizeLimitExceeded)
+
+	// nothing is left in the storage directory
+	entries, err := os.ReadDir(storageDirectory)
+	s.Require().NoError(err)
+	s.Empty(entries)
+}
+
+func (s *StorageSuite) TestGetVolumeSize() {
+	archiveSize, err := s.delegate.GetVolumeSize(s.ctx, mock.Execution(), s.input(true))
+	s.Require().NoError(err)
+
+	for _, test := range []struct {
+		name     string
+		params   Params
+		extract  bool
+		expected uint64
+	}{
+		{name: "without extract", params: Params{MaxRatio: 20}, expected: archiveSize},
+		{name: "ratio", params: Params{MaxRatio: 20}, extract: true, expected: archiveSize * 21},
+		{name: "max size", params: Params{MaxRatio: 20, MaxSize: 10}, extract: true, expected: archiveSize + 10},
+	} {
+		s.Run(test.name, func() {
+			size, err := Wrap(s.delegate, test.params).GetVolumeSize(s.ctx, mock.Execution(), s.input(test.extract))
+			s.Require().NoError(err)
+			s.Equal(test.expected, size)
+		})
+	}
+}
@@ -0,0 +1,570 @@
+// Package git provides a storage that checks out git repositories as inputs of jobs,
+// so that jobs don't need networking to clone their sources.
+//
+// Repositories are checked out with the git binary of the compute node, with a shallow
+// fetch of the requested ref, and optionally a sparse checkout of some of their directories
+// and a recursive checkout of their submodules. The fetch done to compute the size of a
+// checkout is kept for a while, so that the checkout of the same execution reuses it.
+//
+// Git runs without the configuration, credential helpers, ssh agent and ssh keys of the
+// compute node's user, so that jobs can only access the repositories that they could
+// access with their own credential.
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/util"
+)
+
+const (
+	// allowedProtocols are the transports git can use, including for submodules,
+	// which prevents repositories from reading files of the compute node through their submodules
+	allowedProtocols = "https:http:ssh:git"
+
+	// credentialUsername is the username sent with the credential, which is accepted by the common git hosts
+	credentialUsername = "x-access-token"
+
+	// defaultRepoName is the name of the checkout directory when it cannot be derived from the repo url
+	defaultRepoName = "repo"
+
+	// fetchedRef is the ref of the fetched commit in the repositories fetched to compute the size of checkouts
+	fetchedRef = "refs/heads/fetched"
+
+	// fetchTTL is how long a repository fetched to compute the size of a checkout is kept for the checkout
+	// of the execution, after which it is removed and the checkout fetches the repository again
+	fetchTTL = 10 * time.Minute
+
+	// defaultMaxFetchSize is the default maximum size of the repositories fetched to compute the size of checkouts
+	defaultMaxFetchSize = 10 << 30
+
+	// fetchSizeCheckInterval is the interval of checking the size of a repository while it is fetched
+	fetchSizeCheckInterval = 500 * time.Millisecond
+
+	// sshCommand is the ssh command run by git, which doesn't use the configuration, agent or keys of the user
+	sshCommand = "ssh -F /dev/null -o IdentityAgent=none -o IdentitiesOnly=yes -o IdentityFile=none -o BatchMode=yes"
+)
+
+// isolatedEnvPrefixes are the prefixes of the environment variables of the compute node that git doesn't inherit,
+// which include the ssh agent socket, and the variables changing the configuration or credentials of git
+var isolatedEnvPrefixes = []string{"GIT_", "SSH_"}
+
+// isolatedEnvNames are the other environment variables of the compute node that git doesn't inherit,
+// which locate the configuration of the user
+var isolatedEnvNames = []string{"HOME", "XDG_CONFIG_HOME"}
+
+// CredentialResolver resolves the credential references of the sources, such as env:GIT_TOKEN
+type CredentialResolver interface {
+	Value(value string) (string, error)
+}
+
+type StorageProviderParams struct {
+	// Timeout is the maximum duration of checking out a repository
+	Timeout time.Duration
+	// Resolver resolves the credentials of the sources. Credentials are used as is if nil.
+	Resolver CredentialResolver
+	// MaxFetchSize is the maximum size in bytes of the repository fetched to compute the size of a checkout,
+	// above which the fetch is stopped. Defaults to 10GiB if zero.
+	MaxFetchSize uint64
+}
+
+type StorageProvider struct {
+	timeout      time.Duration
+	resolver     CredentialResolver
+	maxFetchSize uint64
+
+	mu      sync.Mutex
+	fetches map[string]fetched
+}
+
+// fetched is a bare repository fetched to compute the size of a checkout, kept for the checkout
+type fetched struct {
+	dir     string
+	expires time.Time
+}
+
+func NewStorage(params StorageProviderParams) *StorageProvider {
+	maxFetchSize := params.MaxFetchSize
+	if maxFetchSize == 0 {
+		maxFetchSize = defaultMaxFetchSize
+	}
+	return &StorageProvider{
+		timeout:      params.Timeout,
+		resolver:     params.Resolver,
+		maxFetchSize: maxFetchSize,
+		fetches:      make(map[string]fetched),
+	}
+}
+
+// IsInstalled returns whether the git binary is available on the node
+func (s *StorageProvider) IsInstalled(context.Context) (bool, error) {
+	_, err := exec.LookPath("git")
+	return err == nil, nil
+}
+
+// HasStorageLocally returns false as repositories are checked out for each execution
+func (s *StorageProvider) HasStorageLocally(context.Context, models.InputSource) (bool, error) {
+	return false, nil
+}
+
+// GetVolumeSize returns the size of the checkout, which is the size of the files of the checked out paths
+// in the ref, and of the fetched git objects. The size of the submodules is not included.
+// The fetched repository is kept for the checkout of the execution by PrepareStorage.
+// The fetch fails if the repository exceeds the maximum fetch size.
+func (s *StorageProvider) GetVolumeSize(ctx context.Context, execution *models.Execution, input models.InputSource) (uint64, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	source, err := DecodeSpec(input.Source)
+	if err != nil {
+		return 0, err
+	}
+	g, err := s.newGit(source)
+	if err != nil {
+		return 0, err
+	}
+	defer g.close()
+
+	dir, err := os.MkdirTemp("", "git-size-*")
+	if err != nil {
+		return 0, err
+	}
+	keep := false
+	defer func() {
+		if !keep {
+			removeFetched(ctx, dir)
+		}
+	}()
+
+	if _, err = g.run(ctx, "", "init", "--quiet", "--bare", dir); err != nil {
+		return 0, err
+	}
+	if err = s.fetchLimited(ctx, g, dir, source); err != nil {
+		return 0, err
+	}
+	if _, err = g.run(ctx, dir, "update-ref", fetchedRef, "FETCH_HEAD"); err != nil {
+		return 0, err
+	}
+
+	args := []string{"ls-tree", "-r", "-l", "FETCH_HEAD"}
+	if len(source.Paths) > 0 {
+		args = append(append(args, "--"), cleanPaths(source.Paths)...)
+	}
+	out, err := g.run(ctx, dir, args...)
+	if err != nil {
+		return 0, err
+	}
+	size, err := blobsSize(out)
+	if err != nil {
+		return 0, err
+	}
+	objectsSize, err := dirSize(dir)
+	if err != nil {
+		return 0, err
+	}
+	s.keepFetched(ctx, fetchKey(execution, source), dir)
+	keep = true
+	return size + objectsSize, nil
+}
+
+// PrepareStorage checks out the repository in a new directory of the storage directory,
+// and returns a read-only volume of the checkout. The repository is fetched from the one
+// fetched by GetVolumeSize for the execution if it is still kept.
+func (s *StorageProvider) PrepareStorage(
+	ctx context.Context,
+	storageDirectory string,
+	execution *models.Execution,
+	input models.InputSource) (storage.StorageVolume, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	source, err := DecodeSpec(input.Source)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	g, err := s.newGit(source)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	defer g.close()
+
+	outputPath, err := os.MkdirTemp(storageDirectory, "git-*")
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	checkout := filepath.Join(outputPath, repoName(source.Repo))
+	fetchedDir := s.takeFetched(ctx, fetchKey(execution, source))
+	if fetchedDir != "" {
+		defer removeFetched(ctx, fetchedDir)
+	}
+	if err = g.checkout(ctx, checkout, source, fetchedDir); err != nil {
+		_ = os.RemoveAll(outputPath)
+		return storage.StorageVolume{}, err
+	}
+	if err = makeReadOnly(checkout); err != nil {
+		_ = os.RemoveAll(outputPath)
+		return storage.StorageVolume{}, err
+	}
+
+	return storage.StorageVolume{
+		Type:     storage.StorageVolumeConnectorBind,
+		ReadOnly: true,
+		Source:   checkout,
+		Target:   input.Target,
+	}, nil
+}
+
+func (s *StorageProvider) CleanupStorage(ctx context.Context, _ models.InputSource, volume storage.StorageVolume) error {
+	pathToCleanup := filepath.Dir(volume.Source)
+	log.Ctx(ctx).Debug().Str("Path", pathToCleanup).Msg("Cleaning up git checkout")
+	return os.RemoveAll(pathToCleanup)
+}
+
+func (s *StorageProvider) Upload(context.Context, string) (models.SpecConfig, error) {
+	// we don't push anything to a repository
+	return models.SpecConfig{}, fmt.Errorf("not implemented")
+}
+
+func (s *StorageProvider) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.timeout)
+}
+
+// fetchKey returns the key of the repository fetched for the source of the execution
+func fetchKey(execution *models.Execution, source Source) string {
+	return strings.Join([]string{execution.ID, source.Repo, source.Ref, source.Credential,
+		strings.Join(cleanPaths(source.Paths), ",")}, "\x00")
+}
+
+// keepFetched keeps the repository fetched in dir for the checkout with the key,
+// and removes the fetched repositories that expired
+func (s *StorageProvider) keepFetched(ctx context.Context, key, dir string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for k, f := range s.fetches {
+		if k == key || now.After(f.expires) {
+			removeFetched(ctx, f.dir)
+			delete(s.fetches, k)
+		}
+	}
+	s.fetches[key] = fetched{dir: dir, expires: now.Add(fetchTTL)}
+}
+
+// takeFetched returns the directory of the repository fetched for the checkout with the key,
+// which must be removed by the caller, or an empty string if there is none
+func (s *StorageProvider) takeFetched(ctx context.Context, key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.fetches[key]
+	if !ok {
+		return ""
+	}
+	delete(s.fetches, key)
+	if time.Now().After(f.expires) {
+		removeFetched(ctx, f.dir)
+		return ""
+	}
+	return f.dir
+}
+
+// fetchLimited fetches the source in the bare repository of the directory, and stops the fetch
+// if the repository exceeds the maximum fetch size
+func (s *StorageProvider) fetchLimited(ctx context.Context, g *gitRunner, dir string, source Source) error {
+	errSizeExceeded := fmt.Errorf("git repository %s exceeds the maximum size of %d bytes", source.Repo, s.maxFetchSize)
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(fetchSizeCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if size, err := dirSize(dir); err == nil && size > s.maxFetchSize {
+					cancel(errSizeExceeded)
+					return
+				}
+			}
+		}
+	}()
+
+	if err := g.fetch(ctx, dir, source, "", false); err != nil {
+		if errors.Is(context.Cause(ctx), errSizeExceeded) {
+			return errSizeExceeded
+		}
+		return err
+	}
+	size, err := dirSize(dir)
+	if err != nil {
+		return err
+	}
+	if size > s.maxFetchSize {
+		return errSizeExceeded
+	}
+	return nil
+}
+
+// removeFetched removes a repository fetched to compute the size of a checkout
+func removeFetched(ctx context.Context, dir string) {
+	if err := os.RemoveAll(dir); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("Dir", dir).Msg("failed to remove git volume size directory")
+	}
+}
+
+// newGit returns the git runner of the source, authenticating with the resolved credential of the source.
+// The runner must be closed to remove its home directory.
+func (s *StorageProvider) newGit(source Source) (*gitRunner, error) {
+	credential := source.Credential
+	if credential != "" && s.resolver != nil {
+		var err error
+		if credential, err = s.resolver.Value(source.Credential); err != nil {
+			return nil, fmt.Errorf("failed to resolve the credential of git repository %s: %w", source.Repo, err)
+		}
+	}
+	u, err := url.Parse(source.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	home, err := os.MkdirTemp("", "git-home-*")
+	if err != nil {
+		return nil, err
+	}
+	env := append(isolatedEnv(),
+		"HOME="+home,
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_CONFIG_GLOBAL="+os.DevNull,
+		"GIT_SSH_COMMAND="+sshCommand,
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_ALLOW_PROTOCOL="+allowedProtocols,
+	)
+	if credential == "" {
+		return &gitRunner{env: env, home: home}, nil
+	}
+
+	// the credential is passed through the environment rather than the arguments so that it is not
+	// visible in the process list, and only sent to the host of the repository
+	token := base64.StdEncoding.EncodeToString([]byte(credentialUsername + ":" + credential))
+	env = append(env,
+		"GIT_CONFIG_COUNT=1",
+		fmt.Sprintf("GIT_CONFIG_KEY_0=http.%s://%s/.extraHeader", u.Scheme, u.Host),
+		"GIT_CONFIG_VALUE_0=Authorization: Basic "+token,
+	)
+	return &gitRunner{env: env, home: home}, nil
+}
+
+// isolatedEnv returns the environment of the compute node without the variables that git must not inherit
+func isolatedEnv() []string {
+	var env []string
+	for _, variable := range os.Environ() {
+		name, _, _ := strings.Cut(variable, "=")
+		if !isIsolatedEnv(name) {
+			env = append(env, variable)
+		}
+	}
+	return env
+}
+
+// isIsolatedEnv returns whether the environment variable of the compute node must not be inherited by git
+func isIsolatedEnv(name string) bool {
+	for _, prefix := range isolatedEnvPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	for _, isolatedName := range isolatedEnvNames {
+		if name == isolatedName {
+			return true
+		}
+	}
+	return false
+}
+
+// gitRunner runs git commands
+type gitRunner struct {
+	env []string
+	// home is the empty home directory of git, so that it doesn't read the files of the user of the node
+	home string
+}
+
+// close removes the home directory of git
+func (g *gitRunner) close() {
+	_ = os.RemoveAll(g.home)
+}
+
+// withLocalProtocol returns a git runner that can also use local repositories, which must only be used
+// for the repositories fetched by the storage, and not for the repositories of the sources
+func (g *gitRunner) withLocalProtocol() *gitRunner {
+	// the last value of a variable is the one used by the command
+	return &gitRunner{
+		env:  append(append([]string{}, g.env...), "GIT_ALLOW_PROTOCOL="+allowedProtocols+":file"),
+		home: g.home,
+	}
+}
+
+// run runs the git command in the directory, and returns its output
+func (g *gitRunner) run(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = g.env
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("git %s: %w", args[0], ctx.Err())
+		}
+		return nil, fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// fetch fetches the ref of the source without its history in the repository of the directory,
+// from the repository already fetched in fetchedDir if set. Only the trees of the ref are fetched
+// from the source if the blobs are only needed for some paths.
+func (g *gitRunner) fetch(ctx context.Context, dir string, source Source, fetchedDir string, sparse bool) error {
+	if _, err := g.run(ctx, dir, "remote", "add", "origin", source.Repo); err != nil {
+		return err
+	}
+	args := []string{"fetch", "--quiet", "--depth=1", "--no-tags"}
+	if fetchedDir != "" {
+		_, err := g.withLocalProtocol().run(ctx, dir, append(args, fetchedDir, fetchedRef)...)
+		return err
+	}
+	if sparse {
+		args = append(args, "--filter=blob:none")
+	}
+	ref := source.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	_, err := g.run(ctx, dir, append(args, "origin", ref)...)
+	return err
+}
+
+// checkout checks out the ref of the source in the directory, fetching it from the repository
+// already fetched in fetchedDir if set
+func (g *gitRunner) checkout(ctx context.Context, dir string, source Source, fetchedDir string) error {
+	if _, err := g.run(ctx, "", "init", "--quiet", dir); err != nil {
+		return err
+	}
+	sparse := len(source.Paths) > 0
+	if sparse {
+		if _, err := g.run(ctx, dir, append([]string{"sparse-checkout", "set"}, cleanPaths(source.Paths)...)...); err != nil {
+			return err
+		}
+	}
+	if err := g.fetch(ctx, dir, source, fetchedDir, sparse); err != nil {
+		return err
+	}
+	if _, err := g.run(ctx, dir, "checkout", "--quiet", "FETCH_HEAD"); err != nil {
+		return err
+	}
+	if source.Submodules {
+		if _, err := g.run(ctx, dir, "submodule", "update", "--quiet", "--init", "--recursive", "--depth=1"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanPaths returns the paths relative to the root of the repository
+func cleanPaths(paths []string) []string {
+	cleaned := make([]string, len(paths))
+	for i, p := range paths {
+		cleaned[i] = path.Clean(strings.TrimPrefix(p, "/"))
+	}
+	return cleaned
+}
+
+// repoName returns the name of the checkout directory of the repository, as git clone does
+func repoName(repo string) string {
+	u, err := url.Parse(repo)
+	if err != nil {
+		return defaultRepoName
+	}
+	name := strings.TrimSuffix(path.Base(strings.TrimSuffix(u.Path, "/")), ".git")
+	if name == "" || name == "." || name == "/" {
+		return defaultRepoName
+	}
+	return name
+}
+
+// blobsSize sums the sizes of the blobs listed by git ls-tree -l
+func blobsSize(lsTree []byte) (uint64, error) {
+	var size uint64
+	scanner := bufio.NewScanner(bytes.NewReader(lsTree))
+	for scanner.Scan() {
+		// <mode> SP <type> SP <object> SP+ <size> TAB <path>
+		info, _, _ := strings.Cut(scanner.Text(), "\t")
+		fields := strings.Fields(info)
+		if len(fields) != 4 || fields[1] != "blob" {
+			continue
+		}
+		blobSize, err := strconv.ParseUint(fields[3], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid size of git blob %s: %w", fields[2], err)
+		}
+		size += blobSize
+	}
+	return size, scanner.Err()
+}
+
+// dirSize returns the size of the files in the directory
+func dirSize(dir string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += uint64(info.Size())
+		return nil
+	})
+	return size, err
+}
+
+// makeReadOnly removes the write permissions of the files of the checkout, so that executions
+// running on the host cannot modify them either
+func makeReadOnly(dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return os.Chmod(p, info.Mode().Perm()&^util.OS_ALL_W)
+	})
+}
+
+var _ storage.Storage = (*StorageProvider)(nil)
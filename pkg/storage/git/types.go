@@ -0,0 +1,103 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/fatih/structs"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// supportedSchemes are the schemes of the repositories that can be checked out
+var supportedSchemes = []string{"https", "http", "ssh", "git"}
+
+type Source struct {
+	// Repo is the URL of the repository to check out
+	Repo string
+	// Ref is the branch, tag or commit to check out. The default branch of the repository is checked out if empty.
+	Ref string
+	// Paths are the directories of the repository to check out with a sparse checkout.
+	// The whole repository is checked out if empty.
+	Paths []string
+	// Submodules checks out the submodules of the repository recursively
+	Submodules bool
+	// Credential is the token used to authenticate with the repository over HTTP.
+	// It is resolved by the environment variable resolvers of the compute node, e.g. env:GIT_TOKEN,
+	// so that secrets don't have to be part of the job spec.
+	Credential string
+}
+
+func (c Source) Validate() error {
+	if c.Repo == "" {
+		return errors.New("invalid git storage params: repo cannot be empty")
+	}
+	u, err := url.Parse(c.Repo)
+	if err != nil {
+		return fmt.Errorf("invalid git storage params: invalid repo url %q: %w", c.Repo, err)
+	}
+	if !isSupportedScheme(u.Scheme) {
+		return fmt.Errorf("invalid git storage params: unsupported repo url scheme %q, expected one of %s",
+			u.Scheme, strings.Join(supportedSchemes, ", "))
+	}
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			return errors.New("invalid git storage params: repo url cannot contain a password, use a credential instead")
+		}
+	}
+	if strings.HasPrefix(c.Ref, "-") {
+		return fmt.Errorf("invalid git storage params: invalid ref %q", c.Ref)
+	}
+	for _, p := range c.Paths {
+		cleaned := path.Clean(strings.TrimPrefix(p, "/"))
+		if p == "" || cleaned == "." || cleaned == ".." ||
+			strings.HasPrefix(cleaned, "../") || strings.HasPrefix(cleaned, "-") {
+			return fmt.Errorf("invalid git storage params: invalid path %q", p)
+		}
+	}
+	return nil
+}
+
+func (c Source) ToMap() map[string]interface{} {
+	return structs.Map(c)
+}
+
+func DecodeSpec(spec *models.SpecConfig) (Source, error) {
+	if !spec.IsType(models.StorageSourceGit) {
+		return Source{}, errors.New("invalid storage source type. expected " + models.StorageSourceGit + ", but received: " + spec.Type)
+	}
+	inputParams := spec.Params
+	if inputParams == nil {
+		return Source{}, errors.New("invalid storage source params. cannot be nil")
+	}
+
+	var c Source
+	if err := mapstructure.Decode(spec.Params, &c); err != nil {
+		return c, err
+	}
+
+	return c, c.Validate()
+}
+
+func NewSpecConfig(source Source) (*models.SpecConfig, error) {
+	if err := source.Validate(); err != nil {
+		return nil, fmt.Errorf("creating %s storage spec: %w", models.StorageSourceGit, err)
+	}
+	return &models.SpecConfig{
+		Type:   models.StorageSourceGit,
+		Params: source.ToMap(),
+	}, nil
+}
+
+func isSupportedScheme(scheme string) bool {
+	for _, s := range supportedSchemes {
+		if strings.EqualFold(scheme, s) {
+			return true
+		}
+	}
+	return false
+}
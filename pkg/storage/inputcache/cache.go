@@ -0,0 +1,369 @@
+// Package inputcache implements a cache of the inputs of executions shared by all the executions of a compute
+// node, so that inputs used by many executions are only downloaded once.
+package inputcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/diskcache"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const (
+	// indexFile is the name of the file in the cache directory that records which inputs are cached,
+	// and when they were last used
+	indexFile = "index.json"
+	// fetchDirPrefix is the prefix of the directories inputs are fetched in before being added to the cache
+	fetchDirPrefix = "fetch-"
+	// maxAdvertisedSources is the maximum number of cached input sources returned by CachedSources
+	maxAdvertisedSources = 100
+	// readOnlyFilePerm is the permission of the cached files, which are shared by executions
+	readOnlyFilePerm = 0o444
+)
+
+// Params configures a Cache
+type Params struct {
+	// Dir is the directory in which cached inputs are stored
+	Dir string
+	// MaxSize is the maximum total size in bytes of the inputs stored in Dir.
+	// Zero means no limit.
+	MaxSize uint64
+}
+
+// Cache is a node-level cache of the inputs of executions, stored on disk and addressed by a key identifying
+// the content of an input, such as the URL and ETag of a file or the CID of IPFS content.
+//
+// Executions are handed the cached content through hardlinks of its files, which are read-only, so that the
+// content can be evicted while executions still use it. If the files cannot be hardlinked, such as when the
+// cache and the executions are on different filesystems, executions get the cached content itself to bind mount
+// read-only, and it is pinned in the cache until released. Executions whose engine doesn't mount inputs
+// read-only, such as host processes that can change the permissions of the files they are given, get a copy
+// of the content instead, so that they cannot alter the cached content. The least recently used inputs are
+// evicted to keep the total size of the cache within its limit.
+type Cache struct {
+	dir   string
+	index *diskcache.Index[string]
+
+	mu       sync.Mutex
+	fetching map[string]chan struct{}
+}
+
+// New creates a cache storing inputs in the given directory.
+// Inputs cached by a previous run of the node are reused, and files not recorded in the index are removed.
+func New(params Params) (*Cache, error) {
+	if params.Dir == "" {
+		return nil, errors.New("input cache directory is required")
+	}
+	if err := os.MkdirAll(params.Dir, models.DownloadFolderPerm); err != nil {
+		return nil, fmt.Errorf("failed to create input cache directory %s: %w", params.Dir, err)
+	}
+	c := &Cache{
+		dir:      params.Dir,
+		fetching: make(map[string]chan struct{}),
+	}
+	// the value of the entries is the identifier of their input source, as returned by SourceID
+	c.index = diskcache.NewIndex(diskcache.IndexParams[string]{
+		Name:            "input cache",
+		Path:            filepath.Join(params.Dir, indexFile),
+		MaxSize:         params.MaxSize,
+		Remove:          c.removeContent,
+		SizeMetric:      InputCacheSize,
+		EvictionsMetric: InputCacheEvictions,
+	})
+	if err := c.loadIndex(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SourceID returns the identifier of an input source, which is the same for all the jobs reading the same input
+// with the same parameters. Compute nodes advertise the identifiers of their cached inputs, which lets the
+// orchestrator prefer the nodes that already have the inputs of a job.
+func SourceID(source *models.SpecConfig) string {
+	if source == nil {
+		return ""
+	}
+	// maps are marshalled with sorted keys, which makes the identifier stable
+	params, err := json.Marshal(source.Params)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(params)
+	return strings.ToLower(source.Type) + ":" + hex.EncodeToString(hash[:])
+}
+
+// SharesContent returns whether the cached content can be shared with the execution, which requires its engine
+// to mount inputs read-only. The exec engine runs tasks as host processes of the node's user, which can make
+// the cached files writable again, so its executions are given copies of the content.
+func SharesContent(execution *models.Execution) bool {
+	return !execution.Job.Task().Engine.IsType(models.EngineExec)
+}
+
+// Has returns whether the content of the key is cached
+func (c *Cache) Has(key string) bool {
+	return c.index.Has(hashKey(key))
+}
+
+// CachedSources returns the identifiers of the input sources of the cached inputs, most recently used first.
+// At most maxAdvertisedSources are returned to bound the size of the node info.
+func (c *Cache) CachedSources() []string {
+	var entries []diskcache.Entry[string]
+	for _, e := range c.index.Entries() {
+		if e.Value != "" {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].LastUsed.After(entries[j].LastUsed) })
+
+	sources := make([]string, 0, min(len(entries), maxAdvertisedSources))
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if len(sources) == maxAdvertisedSources {
+			break
+		}
+		if !seen[e.Value] {
+			seen[e.Value] = true
+			sources = append(sources, e.Value)
+		}
+	}
+	return sources
+}
+
+// Get hands the content of the key to an execution in dir, which must be an empty directory, fetching it first
+// with fetch if it is not cached yet. fetch is given an empty directory to write the content in, and source
+// identifies the input source it is fetched for. Get returns the directory holding the content for the execution,
+// which is either dir or a directory of the cache that must be released with Release once the execution is done.
+// If share is false, the content is copied to dir instead, as returned by SharesContent for the execution.
+func (c *Cache) Get(
+	ctx context.Context, key, source, dir string, share bool, fetch func(ctx context.Context, dir string) error) (string, error) {
+	hash := hashKey(key)
+	if err := c.ensure(ctx, hash, source, fetch); err != nil {
+		return "", err
+	}
+	if !share {
+		return dir, c.copy(hash, dir)
+	}
+	return c.link(ctx, hash, dir)
+}
+
+// Release releases the cached content an execution was given by Get, if path is within it.
+// It returns false if path is not within the cache, and must be cleaned up by the caller.
+func (c *Cache) Release(path string) bool {
+	rel, err := filepath.Rel(c.dir, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	c.index.Unpin(strings.Split(rel, string(filepath.Separator))[0])
+	return true
+}
+
+// ensure fetches the content of the key if it is not cached, waiting for any fetch of the same key in progress
+func (c *Cache) ensure(ctx context.Context, hash, source string, fetch func(ctx context.Context, dir string) error) error {
+	for {
+		if _, ok := c.index.Use(hash); ok {
+			InputCacheHits.Inc(ctx)
+			return nil
+		}
+
+		c.mu.Lock()
+		inProgress, ok := c.fetching[hash]
+		if !ok {
+			done := make(chan struct{})
+			c.fetching[hash] = done
+			c.mu.Unlock()
+			defer func() {
+				c.mu.Lock()
+				delete(c.fetching, hash)
+				c.mu.Unlock()
+				close(done)
+			}()
+			break
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-inProgress:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	InputCacheMisses.Inc(ctx)
+	fetchDir, err := os.MkdirTemp(c.dir, fetchDirPrefix+"*")
+	if err != nil {
+		return err
+	}
+	if err = fetch(ctx, fetchDir); err != nil {
+		_ = os.RemoveAll(fetchDir)
+		return err
+	}
+	size, err := makeReadOnly(fetchDir)
+	if err != nil {
+		_ = os.RemoveAll(fetchDir)
+		return err
+	}
+	if err = os.Rename(fetchDir, filepath.Join(c.dir, hash)); err != nil {
+		_ = os.RemoveAll(fetchDir)
+		return fmt.Errorf("failed to add input to the cache: %w", err)
+	}
+	c.index.Add(ctx, hash, source, size)
+	return nil
+}
+
+// link hardlinks the cached content of the key into dir, or pins it and returns its directory in the cache
+// if the files cannot be hardlinked. The content is pinned while it is linked, so that it isn't evicted meanwhile.
+func (c *Cache) link(ctx context.Context, hash, dir string) (string, error) {
+	if !c.index.Pin(hash) {
+		return "", fmt.Errorf("input %s was evicted from the cache before it could be used", hash)
+	}
+
+	cached := filepath.Join(c.dir, hash)
+	err := filepath.WalkDir(cached, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(cached, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, models.DownloadFolderPerm)
+		}
+		return os.Link(path, target)
+	})
+	if err == nil {
+		c.index.Unpin(hash)
+		return dir, nil
+	}
+
+	log.Ctx(ctx).Debug().Err(err).Msg("failed to hardlink cached input, using the cached content directly")
+	entries, readErr := os.ReadDir(dir)
+	if readErr == nil {
+		for _, dirEntry := range entries {
+			_ = os.RemoveAll(filepath.Join(dir, dirEntry.Name()))
+		}
+	}
+	return cached, nil
+}
+
+// copy copies the cached content of the key into dir. The content is pinned while it is copied,
+// so that it isn't evicted meanwhile.
+func (c *Cache) copy(hash, dir string) error {
+	if !c.index.Pin(hash) {
+		return fmt.Errorf("input %s was evicted from the cache before it could be used", hash)
+	}
+	defer c.index.Unpin(hash)
+
+	cached := filepath.Join(c.dir, hash)
+	return filepath.WalkDir(cached, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(cached, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, models.DownloadFolderPerm)
+		}
+		return copyFile(path, target)
+	})
+}
+
+// removeContent deletes the content of an input evicted from the cache
+func (c *Cache) removeContent(ctx context.Context, hash string, _ string) {
+	if err := os.RemoveAll(filepath.Join(c.dir, hash)); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("input", hash).Msg("failed to remove cached input")
+	}
+}
+
+// loadIndex loads the index of the entries of a previous run, dropping the entries whose content is missing,
+// and removes the content that is not part of any entry, such as inputs whose fetch was interrupted.
+func (c *Cache) loadIndex() error {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to list the input cache: %w", err)
+	}
+	dirs := make(map[string]bool)
+	for _, dirEntry := range dirEntries {
+		dirs[dirEntry.Name()] = dirEntry.IsDir()
+	}
+	err = c.index.Load(context.Background(), func(hash string, _ diskcache.Entry[string]) bool {
+		return dirs[hash]
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if name == indexFile || strings.HasSuffix(name, ".tmp") || c.index.Has(name) {
+			continue
+		}
+		if err = os.RemoveAll(filepath.Join(c.dir, name)); err != nil {
+			log.Warn().Err(err).Str("input", name).Msg("failed to remove unindexed cached input")
+		}
+	}
+	return nil
+}
+
+// hashKey returns the name of the directory of the cached content of a key
+func hashKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// copyFile copies the content of a file to a new file
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, models.DownloadFilePerm)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// makeReadOnly makes the files of a directory read-only, so that executions cannot alter the cached
+// content through their hardlinks, and returns their total size
+func makeReadOnly(dir string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += uint64(info.Size()) //nolint:gosec // file sizes are not negative
+			return os.Chmod(path, readOnlyFilePerm)
+		}
+		return nil
+	})
+	return size, err
+}
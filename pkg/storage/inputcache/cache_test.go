@@ -0,0 +1,199 @@
+//go:build unit || !integration
+
+package inputcache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/logger"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type CacheSuite struct {
+	suite.Suite
+	ctx context.Context
+	dir string
+}
+
+func TestCacheSuite(t *testing.T) {
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) SetupTest() {
+	logger.ConfigureTestLogging(s.T())
+	s.ctx = context.Background()
+	s.dir = filepath.Join(s.T().TempDir(), "cache")
+}
+
+func (s *CacheSuite) newCache(maxSize uint64) *Cache {
+	cache, err := New(Params{Dir: s.dir, MaxSize: maxSize})
+	s.Require().NoError(err)
+	return cache
+}
+
+// writeFetcher returns a fetch function writing the content to a file, and counting its calls
+func writeFetcher(content string, calls *int) func(context.Context, string) error {
+	return func(_ context.Context, dir string) error {
+		*calls++
+		return os.WriteFile(filepath.Join(dir, "data.txt"), []byte(content), 0o644)
+	}
+}
+
+// get hands the content of the key to a new execution directory, and returns the directory holding it
+func (s *CacheSuite) get(cache *Cache, key string, fetch func(context.Context, string) error) string {
+	dir := s.T().TempDir()
+	path, err := cache.Get(s.ctx, key, "source-"+key, dir, true, fetch)
+	s.Require().NoError(err)
+	return path
+}
+
+func (s *CacheSuite) TestFetchesOnce() {
+	cache := s.newCache(0)
+	calls := 0
+	first := s.get(cache, "key", writeFetcher("hello", &calls))
+	second := s.get(cache, "key", writeFetcher("hello", &calls))
+	s.Equal(1, calls)
+	s.True(cache.Has("key"))
+	s.False(cache.Has("other"))
+
+	for _, dir := range []string{first, second} {
+		content, err := os.ReadFile(filepath.Join(dir, "data.txt"))
+		s.Require().NoError(err)
+		s.Equal("hello", string(content))
+	}
+
+	// executions are handed read-only hardlinks of the cached file
+	info, err := os.Stat(filepath.Join(first, "data.txt"))
+	s.Require().NoError(err)
+	s.Equal(os.FileMode(readOnlyFilePerm), info.Mode().Perm())
+	cached, err := os.Stat(filepath.Join(s.dir, hashKey("key"), "data.txt"))
+	s.Require().NoError(err)
+	s.True(os.SameFile(info, cached))
+
+	// releasing content outside of the cache is left to the caller
+	s.False(cache.Release(filepath.Join(first, "data.txt")))
+}
+
+func (s *CacheSuite) TestCopiesContentNotShared() {
+	cache := s.newCache(5)
+	calls := 0
+	s.get(cache, "key", writeFetcher("hello", &calls))
+
+	dir := s.T().TempDir()
+	path, err := cache.Get(s.ctx, "key", "source-key", dir, false, writeFetcher("hello", &calls))
+	s.Require().NoError(err)
+	s.Equal(dir, path)
+	s.Equal(1, calls)
+
+	// the execution gets a writable copy, which doesn't alter the cached content
+	copied := filepath.Join(dir, "data.txt")
+	info, err := os.Stat(copied)
+	s.Require().NoError(err)
+	cachedPath := filepath.Join(s.dir, hashKey("key"), "data.txt")
+	cached, err := os.Stat(cachedPath)
+	s.Require().NoError(err)
+	s.False(os.SameFile(info, cached))
+	s.Require().NoError(os.WriteFile(copied, []byte("changed"), 0o644))
+	content, err := os.ReadFile(cachedPath)
+	s.Require().NoError(err)
+	s.Equal("hello", string(content))
+
+	// the content is no longer pinned once copied, so it can be evicted
+	s.get(cache, "other", writeFetcher("world", &calls))
+	s.False(cache.Has("key"))
+}
+
+func (s *CacheSuite) TestSharesContent() {
+	job := mock.Job()
+	s.True(SharesContent(mock.ExecutionForJob(job)))
+	job.Task().Engine = &models.SpecConfig{Type: models.EngineExec}
+	s.False(SharesContent(mock.ExecutionForJob(job)))
+}
+
+func (s *CacheSuite) TestFetchFailure() {
+	cache := s.newCache(0)
+	_, err := cache.Get(s.ctx, "key", "source", s.T().TempDir(), true, func(context.Context, string) error {
+		return errors.New("boom")
+	})
+	s.Require().ErrorContains(err, "boom")
+	s.False(cache.Has("key"))
+
+	// no fetch directory is left behind
+	entries, err := os.ReadDir(s.dir)
+	s.Require().NoError(err)
+	for _, entry := range entries {
+		s.False(strings.HasPrefix(entry.Name(), fetchDirPrefix), entry.Name())
+	}
+}
+
+func (s *CacheSuite) TestEvictsLeastRecentlyUsedInputs() {
+	cache := s.newCache(10)
+	calls := 0
+	s.get(cache, "a", writeFetcher("12345", &calls))
+	s.get(cache, "b", writeFetcher("12345", &calls))
+	// using a makes b the least recently used input
+	s.get(cache, "a", writeFetcher("12345", &calls))
+	s.get(cache, "c", writeFetcher("12345", &calls))
+
+	s.True(cache.Has("a"))
+	s.False(cache.Has("b"))
+	s.True(cache.Has("c"))
+	s.Equal(uint64(10), cache.index.Size())
+	s.NoDirExists(filepath.Join(s.dir, hashKey("b")))
+}
+
+func (s *CacheSuite) TestPinnedInputsAreNotEvicted() {
+	cache := s.newCache(5)
+	calls := 0
+	s.get(cache, "a", writeFetcher("12345", &calls))
+
+	// pin a, as an execution using the cached content directly does
+	cached := filepath.Join(s.dir, hashKey("a"))
+	s.Require().True(cache.index.Pin(hashKey("a")))
+
+	s.get(cache, "b", writeFetcher("12345", &calls))
+	s.True(cache.Has("a"))
+
+	s.True(cache.Release(filepath.Join(cached, "data.txt")))
+	s.get(cache, "c", writeFetcher("12345", &calls))
+	s.False(cache.Has("a"))
+}
+
+func (s *CacheSuite) TestReloadsIndex() {
+	cache := s.newCache(0)
+	calls := 0
+	s.get(cache, "a", writeFetcher("hello", &calls))
+	// content not recorded in the index is removed
+	s.Require().NoError(os.MkdirAll(filepath.Join(s.dir, fetchDirPrefix+"interrupted"), 0o755))
+
+	reloaded := s.newCache(0)
+	s.True(reloaded.Has("a"))
+	s.Equal(uint64(5), reloaded.index.Size())
+	s.Equal([]string{"source-a"}, reloaded.CachedSources())
+	s.NoDirExists(filepath.Join(s.dir, fetchDirPrefix+"interrupted"))
+
+	s.get(reloaded, "a", writeFetcher("hello", &calls))
+	s.Equal(1, calls)
+}
+
+func (s *CacheSuite) TestSourceID() {
+	source := &models.SpecConfig{
+		Type:   models.StorageSourceURL,
+		Params: map[string]interface{}{"URL": "https://example.com/data.txt"},
+	}
+	s.Equal(SourceID(source), SourceID(source.Copy()))
+	s.True(strings.HasPrefix(SourceID(source), strings.ToLower(models.StorageSourceURL)+":"))
+	s.NotEqual(SourceID(source), SourceID(&models.SpecConfig{
+		Type:   models.StorageSourceURL,
+		Params: map[string]interface{}{"URL": "https://example.com/other.txt"},
+	}))
+	s.Empty(SourceID(nil))
+}
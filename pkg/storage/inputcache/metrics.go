@@ -0,0 +1,39 @@
+package inputcache
+
+import (
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
+)
+
+var (
+	inputCacheMeter = otel.GetMeterProvider().Meter("input-cache")
+)
+
+var (
+	InputCacheHits = lo.Must(telemetry.NewCounter(
+		inputCacheMeter,
+		"input_cache_hits",
+		"Number of execution inputs found in the input cache",
+	))
+
+	InputCacheMisses = lo.Must(telemetry.NewCounter(
+		inputCacheMeter,
+		"input_cache_misses",
+		"Number of execution inputs downloaded because they were not in the input cache",
+	))
+
+	InputCacheEvictions = lo.Must(telemetry.NewCounter(
+		inputCacheMeter,
+		"input_cache_evictions",
+		"Number of inputs evicted from the input cache to stay within its size limit",
+	))
+
+	InputCacheSize = lo.Must(inputCacheMeter.Int64UpDownCounter(
+		"input_cache_size",
+		metric.WithDescription("Total size of the inputs in the input cache"),
+		metric.WithUnit("By"),
+	))
+)
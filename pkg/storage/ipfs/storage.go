@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -13,6 +14,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/ipfs"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/inputcache"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
 )
 
@@ -21,15 +23,22 @@ import (
 // to a local directory in preparation for
 // a job to run - it will remove the folder/file once complete
 
+// cachedInputDirPrefix is the prefix of the directories executions are handed cached content in
+const cachedInputDirPrefix = "ipfs-input-"
+
 type StorageProvider struct {
 	ipfsClient       ipfs.Client
 	getVolumeTimeout time.Duration
+	cache            *inputcache.Cache
 }
 
-func NewStorage(cl ipfs.Client, getVolumeTimeout time.Duration) (*StorageProvider, error) {
+// NewStorage creates an IPFS storage provider. Content is shared by executions through the
+// input cache, if not nil.
+func NewStorage(cl ipfs.Client, getVolumeTimeout time.Duration, cache *inputcache.Cache) (*StorageProvider, error) {
 	storageHandler := &StorageProvider{
 		ipfsClient:       cl,
 		getVolumeTimeout: getVolumeTimeout,
+		cache:            cache,
 	}
 
 	log.Trace().Msgf("IPFS API Copy driver created with address: %s", cl.APIAddress())
@@ -46,6 +55,9 @@ func (s *StorageProvider) HasStorageLocally(ctx context.Context, volume models.I
 	if err != nil {
 		return false, err
 	}
+	if s.cache != nil && s.cache.Has(cacheKey(source.CID)) {
+		return true, nil
+	}
 	return s.ipfsClient.HasCID(ctx, source.CID)
 }
 
@@ -78,7 +90,7 @@ func (s *StorageProvider) GetVolumeSize(ctx context.Context, _ *models.Execution
 func (s *StorageProvider) PrepareStorage(
 	ctx context.Context,
 	storageDirectory string,
-	_ *models.Execution,
+	execution *models.Execution,
 	storageSpec models.InputSource) (storage.StorageVolume, error) {
 	source, err := DecodeSpec(storageSpec.Source)
 	if err != nil {
@@ -94,7 +106,11 @@ func (s *StorageProvider) PrepareStorage(
 	}
 
 	var volume storage.StorageVolume
-	volume, err = s.getFileFromIPFS(ctx, source.CID, storageDirectory, storageSpec.Target)
+	if s.cache != nil {
+		volume, err = s.getCachedFileFromIPFS(ctx, source.CID, storageDirectory, execution, storageSpec)
+	} else {
+		volume, err = s.getFileFromIPFS(ctx, source.CID, storageDirectory, storageSpec.Target)
+	}
 	if err != nil {
 		return storage.StorageVolume{}, fmt.Errorf("failed to copy %s to volume: %w", storageSpec.Target, err)
 	}
@@ -103,6 +119,14 @@ func (s *StorageProvider) PrepareStorage(
 }
 
 func (s *StorageProvider) CleanupStorage(_ context.Context, storageSpec models.InputSource, vol storage.StorageVolume) error {
+	if s.cache != nil {
+		if s.cache.Release(vol.Source) {
+			return nil
+		}
+		if dir := filepath.Dir(vol.Source); strings.HasPrefix(filepath.Base(dir), cachedInputDirPrefix) {
+			return os.RemoveAll(dir)
+		}
+	}
 	fileInfo, err := os.Stat(vol.Source)
 	if err != nil {
 		return err
@@ -115,6 +139,14 @@ func (s *StorageProvider) CleanupStorage(_ context.Context, storageSpec models.I
 	return os.Remove(vol.Source)
 }
 
+// CachedSources returns the input sources in the input cache
+func (s *StorageProvider) CachedSources() []string {
+	if s.cache == nil {
+		return nil
+	}
+	return s.cache.CachedSources()
+}
+
 func (s *StorageProvider) Upload(ctx context.Context, localPath string) (models.SpecConfig, error) {
 	cid, err := s.ipfsClient.Put(ctx, localPath)
 	if err != nil {
@@ -155,5 +187,41 @@ func (s *StorageProvider) getFileFromIPFS(ctx context.Context, cid, storageDirec
 	return volume, nil
 }
 
+// getCachedFileFromIPFS hands the execution the content of the CID from the input cache, fetching it
+// first if it is not cached yet. The content is read-only, as it is shared with the other executions.
+func (s *StorageProvider) getCachedFileFromIPFS(
+	ctx context.Context, cid, storageDirectory string, execution *models.Execution, storageSpec models.InputSource,
+) (storage.StorageVolume, error) {
+	outputDir, err := os.MkdirTemp(storageDirectory, cachedInputDirPrefix+"*")
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	dir, err := s.cache.Get(ctx, cacheKey(cid), inputcache.SourceID(storageSpec.Source), outputDir, inputcache.SharesContent(execution),
+		func(ctx context.Context, dir string) error {
+			return s.ipfsClient.Get(ctx, cid, filepath.Join(dir, cid))
+		})
+	if err != nil {
+		_ = os.RemoveAll(outputDir)
+		return storage.StorageVolume{}, err
+	}
+	if dir != outputDir {
+		// the execution uses the cached content directly
+		_ = os.Remove(outputDir)
+	}
+
+	return storage.StorageVolume{
+		Type:     storage.StorageVolumeConnectorBind,
+		ReadOnly: true,
+		Source:   filepath.Join(dir, cid),
+		Target:   storageSpec.Target,
+	}, nil
+}
+
+// cacheKey returns the key of the content of a CID in the input cache
+func cacheKey(cid string) string {
+	return "ipfs:" + cid
+}
+
 // Compile time interface check:
 var _ storage.Storage = (*StorageProvider)(nil)
+var _ storage.CachedSourcesProvider = (*StorageProvider)(nil)
@@ -38,7 +38,7 @@ func (s *StorageSuite) SetupSuite() {
 
 	var err error
 	s.ipfsClient, err = ipfs.NewClient(context.Background(), cfg.ResultDownloaders.Types.IPFS.Endpoint)
-	s.storage, err = NewStorage(*s.ipfsClient, 5*time.Second)
+	s.storage, err = NewStorage(*s.ipfsClient, 5*time.Second, nil)
 	s.Require().NoError(err)
 }
 
@@ -18,6 +18,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/inputcache"
 )
 
 /*
@@ -38,12 +39,17 @@ type StorageProviderParams struct {
 type StorageProvider struct {
 	clientProvider *s3helper.ClientProvider
 	timeout        time.Duration
+	cache          *inputcache.Cache
 }
 
-func NewStorage(getVolumeTimeout time.Duration, provider *s3helper.ClientProvider) *StorageProvider {
+// NewStorage creates an S3 storage provider. Objects pinned to a version or checksum are shared by
+// executions through the input cache, if not nil.
+func NewStorage(
+	getVolumeTimeout time.Duration, provider *s3helper.ClientProvider, cache *inputcache.Cache) *StorageProvider {
 	return &StorageProvider{
 		clientProvider: provider,
 		timeout:        getVolumeTimeout,
+		cache:          cache,
 	}
 }
 
@@ -56,10 +62,26 @@ func (s *StorageProvider) IsInstalled(_ context.Context) (bool, error) {
 	return s.clientProvider.IsInstalled(), nil
 }
 
-// HasStorageLocally checks if the requested content is hosted locally.
-func (s *StorageProvider) HasStorageLocally(_ context.Context, _ models.InputSource) (bool, error) {
+// HasStorageLocally checks if the requested content is in the input cache.
+func (s *StorageProvider) HasStorageLocally(_ context.Context, input models.InputSource) (bool, error) {
 	// TODO: return true if the content is on the same AZ or datacenter as the host
-	return false, nil
+	source, err := s3helper.DecodeSourceSpec(input.Source)
+	if err != nil {
+		return false, err
+	}
+	key, ok := s.cacheKey(source)
+	return ok && s.cache.Has(key), nil
+}
+
+// cacheKey returns the key of the object of the source in the input cache. Only single objects
+// pinned to a version or a checksum are cached, as they are the only ones whose content cannot change.
+func (s *StorageProvider) cacheKey(source s3helper.SourceSpec) (string, bool) {
+	if s.cache == nil || (source.VersionID == "" && source.ChecksumSHA256 == "") ||
+		source.Key == "" || strings.HasSuffix(source.Key, "*") || strings.HasSuffix(source.Key, "/") {
+		return "", false
+	}
+	return fmt.Sprintf("s3:%s/%s/%s?versionId=%s&sha256=%s",
+		source.Endpoint, source.Bucket, source.Key, source.VersionID, source.ChecksumSHA256), true
 }
 
 func (s *StorageProvider) GetVolumeSize(ctx context.Context, execution *models.Execution, volume models.InputSource) (uint64, error) {
@@ -134,6 +156,27 @@ func (s *StorageProvider) PrepareStorage(
 
 	prefixTokens := strings.Split(s.sanitizeKey(source.Key), "/")
 
+	if key, ok := s.cacheKey(source); ok && len(objects) == 1 {
+		var dir string
+		dir, err = s.cache.Get(ctx, key, inputcache.SourceID(input.Source), outputDir, inputcache.SharesContent(execution),
+			func(ctx context.Context, dir string) error {
+				return s.downloadObject(ctx, client, source, objects[0], dir, prefixTokens)
+			})
+		if err != nil {
+			return storage.StorageVolume{}, err
+		}
+		if dir != outputDir {
+			// the execution uses the cached object directly
+			_ = os.Remove(outputDir)
+		}
+		return storage.StorageVolume{
+			Type:     storage.StorageVolumeConnectorBind,
+			ReadOnly: true,
+			Source:   dir,
+			Target:   input.Target,
+		}, nil
+	}
+
 	for _, object := range objects {
 		err = s.downloadObject(ctx, client, source, object, outputDir, prefixTokens)
 		if err != nil {
@@ -200,6 +243,9 @@ func (s *StorageProvider) downloadObject(ctx context.Context,
 }
 
 func (s *StorageProvider) CleanupStorage(_ context.Context, _ models.InputSource, volume storage.StorageVolume) error {
+	if s.cache != nil && s.cache.Release(volume.Source) {
+		return nil
+	}
 	fileInfo, err := os.Stat(volume.Source)
 	if err != nil {
 		return err
@@ -212,6 +258,14 @@ func (s *StorageProvider) CleanupStorage(_ context.Context, _ models.InputSource
 	return os.Remove(volume.Source)
 }
 
+// CachedSources returns the input sources in the input cache
+func (s *StorageProvider) CachedSources() []string {
+	if s.cache == nil {
+		return nil
+	}
+	return s.cache.CachedSources()
+}
+
 func (s *StorageProvider) Upload(_ context.Context, _ string) (models.SpecConfig, error) {
 	return models.SpecConfig{}, fmt.Errorf("not implemented")
 }
@@ -302,3 +356,4 @@ func (s *StorageProvider) sanitizeKey(key string) string {
 
 // Compile time interface check:
 var _ storage.Storage = (*StorageProvider)(nil)
+var _ storage.CachedSourcesProvider = (*StorageProvider)(nil)
@@ -31,6 +31,14 @@ func (t *tracingStorage) IsInstalled(ctx context.Context) (bool, error) {
 	return t.delegate.IsInstalled(ctx)
 }
 
+// CachedSources returns the input sources cached by the delegate storage, if it caches inputs
+func (t *tracingStorage) CachedSources() []string {
+	if provider, ok := t.delegate.(storage.CachedSourcesProvider); ok {
+		return provider.CachedSources()
+	}
+	return nil
+}
+
 func (t *tracingStorage) HasStorageLocally(ctx context.Context, spec models.InputSource) (bool, error) {
 	ctx, span := telemetry.NewSpan(ctx, telemetry.GetTracer(), fmt.Sprintf("%s.HasStorageLocally", t.name))
 	defer span.End()
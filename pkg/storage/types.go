@@ -31,6 +31,13 @@ type Storage interface {
 	Upload(context.Context, string) (models.SpecConfig, error)
 }
 
+// CachedSourcesProvider is implemented by storages that cache inputs on the node, which lets the node
+// advertise the inputs it already has to the orchestrator.
+type CachedSourcesProvider interface {
+	// CachedSources returns the identifiers of the input sources cached on the node
+	CachedSources() []string
+}
+
 // a storage entity that is consumed are produced by a job
 // input storage specs are turned into storage volumes by drivers
 // for example - the input storage spec might be ipfs cid XXX
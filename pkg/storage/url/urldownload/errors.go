@@ -0,0 +1,34 @@
+package urldownload
+
+import (
+	"net/url"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+)
+
+const Component = "URLDownload"
+
+// URL download error codes
+const (
+	ChecksumMismatch  = "URLChecksumMismatch"
+	SizeLimitExceeded = "URLSizeLimitExceeded"
+)
+
+// newChecksumMismatchError is returned when the downloaded content of a URL doesn't match the expected
+// checksum. The execution fails, as downloading the same content again would not match either.
+func newChecksumMismatchError(u *url.URL, expected, actual string) bacerrors.Error {
+	return bacerrors.Newf("content of url %s does not match checksum %s: got %s", u, expected, actual).
+		WithCode(ChecksumMismatch).
+		WithComponent(Component).
+		WithHint("Make sure the checksum of the input is the digest of the content served by the url.").
+		WithFailsExecution()
+}
+
+// newSizeLimitExceededError is returned when the content of a URL is bigger than its maximum size
+func newSizeLimitExceededError(u *url.URL, maxSize uint64) bacerrors.Error {
+	return bacerrors.Newf("content of url %s exceeds the maximum size of %d bytes", u, maxSize).
+		WithCode(SizeLimitExceeded).
+		WithComponent(Component).
+		WithHint("Increase the maximum size of the input, or use a smaller file.").
+		WithFailsExecution()
+}
@@ -1,10 +1,14 @@
 package urldownload
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"math"
 	"mime"
 	"net/http"
 	"net/url"
@@ -24,6 +28,7 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/inputcache"
 	"github.com/bacalhau-project/bacalhau/pkg/util/closer"
 )
 
@@ -31,19 +36,32 @@ var (
 	ErrNoContentLengthFound = errors.New("content-length not provided by the server")
 )
 
+// maxRedirects is the maximum number of redirects followed by a request, which is the default of http.Client
+const maxRedirects = 10
+
+// HeaderResolver resolves the header values of the sources, such as env:API_KEY
+type HeaderResolver interface {
+	Value(value string) (string, error)
+}
+
 // StorageProvider downloads data on request from a URL to a local
 // directory.
 
 type StorageProvider struct {
-	client *retryablehttp.Client
+	client   *retryablehttp.Client
+	cache    *inputcache.Cache
+	resolver HeaderResolver
 }
 
-func NewStorage(timeout time.Duration, maxRetries int) *StorageProvider {
+// NewStorage creates a URL storage provider. Downloaded files are shared by executions through the
+// input cache, if not nil. The header values of the sources are resolved by the resolver, or used as is if nil.
+func NewStorage(timeout time.Duration, maxRetries int, cache *inputcache.Cache, resolver HeaderResolver) *StorageProvider {
 	log.Debug().Msg("URL download driver created")
 
 	client := retryablehttp.NewClient()
 	client.HTTPClient = &http.Client{
-		Timeout: timeout,
+		Timeout:       timeout,
+		CheckRedirect: checkRedirect,
 		Transport: otelhttp.NewTransport(nil, otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
 			return fmt.Sprintf("%s %s", r.Method, r.URL.Path)
 		}), otelhttp.WithSpanOptions(trace.WithAttributes(semconv.PeerService("url-download")))),
@@ -68,7 +86,9 @@ func NewStorage(timeout time.Duration, maxRetries int) *StorageProvider {
 	}
 
 	return &StorageProvider{
-		client: client,
+		client:   client,
+		cache:    cache,
+		resolver: resolver,
 	}
 }
 
@@ -76,52 +96,62 @@ func (sp *StorageProvider) IsInstalled(context.Context) (bool, error) {
 	return true, nil
 }
 
-func (sp *StorageProvider) HasStorageLocally(context.Context, models.InputSource) (bool, error) {
-	return false, nil
-}
-
-func (sp *StorageProvider) GetVolumeSize(ctx context.Context, _ *models.Execution, storageSpec models.InputSource) (uint64, error) {
-	source, err := DecodeSpec(storageSpec.Source)
+// HasStorageLocally returns whether the file of the URL is in the input cache
+func (sp *StorageProvider) HasStorageLocally(ctx context.Context, input models.InputSource) (bool, error) {
+	if sp.cache == nil {
+		return false, nil
+	}
+	source, err := sp.decodeSpec(input.Source)
 	if err != nil {
-		return 0, err
+		return false, err
 	}
-
 	u, err := IsURLSupported(source.URL)
 	if err != nil {
-		return 0, err
+		return false, err
 	}
+	key, _, ok := sp.cacheKey(ctx, source, u)
+	return ok && sp.cache.Has(key), nil
+}
 
-	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+func (sp *StorageProvider) GetVolumeSize(ctx context.Context, _ *models.Execution, storageSpec models.InputSource) (uint64, error) {
+	source, err := sp.decodeSpec(storageSpec.Source)
 	if err != nil {
 		return 0, err
 	}
 
-	res, err := sp.client.Do(req) //nolint:bodyclose // this is being closed - golangci-lint is wrong again
+	u, err := IsURLSupported(source.URL)
 	if err != nil {
 		return 0, err
 	}
-	defer closer.DrainAndCloseWithLogOnError(ctx, "response", res.Body)
 
-	if res.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("received non-OK response code %d while fetching size of file download", res.StatusCode)
+	res, err := sp.head(ctx, u, source.Headers)
+	if err != nil {
+		return 0, err
 	}
 
-	// Ideally if the content size is not provided by server we should try and fetch the file with max size
-	// as the one provided in the storageSpec
+	// If the content size is not provided by server, the download is bounded by the max size of the source
 	if res.ContentLength < 0 {
+		if source.MaxSize > 0 {
+			return source.MaxSize, nil
+		}
 		return 0, ErrNoContentLengthFound
 	}
+	if source.MaxSize > 0 && uint64(res.ContentLength) > source.MaxSize {
+		return 0, newSizeLimitExceededError(u, source.MaxSize)
+	}
 
 	return uint64(res.ContentLength), nil
 }
 
-// PrepareStorage will download the file from the URL
+// PrepareStorage will download the file from the URL, or hand the execution the file from the input cache
+// if it was already downloaded. The download is verified against the checksum and max size of the source
+// before the execution starts.
 func (sp *StorageProvider) PrepareStorage(
 	ctx context.Context,
 	storageDirectory string,
 	execution *models.Execution,
 	input models.InputSource) (storage.StorageVolume, error) {
-	source, err := DecodeSpec(input.Source)
+	source, err := sp.decodeSpec(input.Source)
 	if err != nil {
 		return storage.StorageVolume{}, err
 	}
@@ -136,38 +166,159 @@ func (sp *StorageProvider) PrepareStorage(
 		return storage.StorageVolume{}, err
 	}
 
-	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if key, etag, ok := sp.cacheKey(ctx, source, u); ok {
+		volume, err := sp.prepareCachedStorage(ctx, outputPath, execution, source, u, input, key, etag)
+		if err != nil {
+			_ = os.RemoveAll(outputPath)
+		}
+		return volume, err
+	}
+
+	fileName, err := sp.download(ctx, source, u, outputPath, "")
 	if err != nil {
+		_ = os.RemoveAll(outputPath)
 		return storage.StorageVolume{}, err
 	}
 
-	requestDidRedirect := false
+	volume := storage.StorageVolume{
+		Type:   storage.StorageVolumeConnectorBind,
+		Source: filepath.Join(outputPath, fileName),   // The source is the full path to the file
+		Target: filepath.Join(input.Target, fileName), // So we should alter the target to include the file name
+	}
 
-	// Install handler which can recognize whether we have performed a redirect or not.
-	previousRedirect := sp.client.HTTPClient.CheckRedirect
-	sp.client.HTTPClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-		requestDidRedirect = true
-		return nil
+	return volume, nil
+}
+
+// prepareCachedStorage hands the execution the file of the URL from the input cache, downloading it first if
+// it is not cached yet. The file is read-only, as it is shared with the other executions using the same URL.
+func (sp *StorageProvider) prepareCachedStorage(
+	ctx context.Context,
+	outputPath string,
+	execution *models.Execution,
+	source Source,
+	u *url.URL,
+	input models.InputSource,
+	key, etag string) (storage.StorageVolume, error) {
+	dir, err := sp.cache.Get(ctx, key, inputcache.SourceID(input.Source), outputPath, inputcache.SharesContent(execution),
+		func(ctx context.Context, dir string) error {
+			_, err := sp.download(ctx, source, u, dir, etag)
+			return err
+		})
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	if dir != outputPath {
+		// the execution uses the cached file directly
+		_ = os.Remove(outputPath)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	if len(entries) != 1 {
+		return storage.StorageVolume{}, fmt.Errorf("expected a single cached file for url %s, found %d", u, len(entries))
+	}
+	fileName := entries[0].Name()
+
+	return storage.StorageVolume{
+		Type:     storage.StorageVolumeConnectorBind,
+		ReadOnly: true,
+		Source:   filepath.Join(dir, fileName),
+		Target:   filepath.Join(input.Target, fileName),
+	}, nil
+}
+
+// cacheKey returns the key of the content of the URL in the input cache, along with its ETag.
+// Sources with a checksum are cached by their checksum and URL, as the checksum identifies their content,
+// and the URL the name of the downloaded file. Otherwise,
+// only URLs whose server returns a strong ETag are cached, as the ETag is what tells whether
+// their content changed.
+func (sp *StorageProvider) cacheKey(ctx context.Context, source Source, u *url.URL) (string, string, bool) {
+	if sp.cache == nil {
+		return "", "", false
+	}
+	if source.Checksum != "" {
+		return "checksum:" + strings.ToLower(source.Checksum) + "#" + u.String(), "", true
+	}
+	res, err := sp.head(ctx, u, source.Headers)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Stringer("url", u).Msg("failed to get the ETag of the url, not caching it")
+		return "", "", false
+	}
+	etag := res.Header.Get("ETag")
+	if etag == "" || strings.HasPrefix(etag, "W/") {
+		return "", "", false
+	}
+	return "url:" + u.String() + "#" + etag, etag, true
+}
+
+// decodeSpec decodes the source of the spec, and resolves the values of its headers
+func (sp *StorageProvider) decodeSpec(spec *models.SpecConfig) (Source, error) {
+	source, err := DecodeSpec(spec)
+	if err != nil || sp.resolver == nil || len(source.Headers) == 0 {
+		return source, err
+	}
+	headers := make(map[string]string, len(source.Headers))
+	for name, value := range source.Headers {
+		if headers[name], err = sp.resolver.Value(value); err != nil {
+			return Source{}, fmt.Errorf("failed to resolve the value of header %s of url %s: %w", name, source.URL, err)
+		}
+	}
+	source.Headers = headers
+	return source, nil
+}
+
+// head sends a HEAD request to the URL with the headers
+func (sp *StorageProvider) head(ctx context.Context, u *url.URL, headers map[string]string) (*http.Response, error) {
+	req, _, err := newRequest(ctx, http.MethodHead, u, headers)
+	if err != nil {
+		return nil, err
 	}
 
 	res, err := sp.client.Do(req) //nolint:bodyclose // this is being closed - golangci-lint is wrong again
 	if err != nil {
-		return storage.StorageVolume{}, fmt.Errorf("failed to begin download from url %s: %w", u, err)
+		return nil, err
 	}
 	defer closer.DrainAndCloseWithLogOnError(ctx, "response", res.Body)
 
-	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
-		return storage.StorageVolume{}, fmt.Errorf("non-200 response from URL (%s): %s", source.URL, res.Status)
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received non-OK response code %d while fetching headers of url %s", res.StatusCode, u)
+	}
+	return res, nil
+}
+
+// download downloads the file of the URL into the output path, and returns its name. If etag is not empty,
+// the download fails if the ETag of the content of the URL is different. The download also fails if the
+// content doesn't match the checksum of the source, or exceeds its max size.
+func (sp *StorageProvider) download(ctx context.Context, source Source, u *url.URL, outputPath, etag string) (string, error) {
+	req, redirects, err := newRequest(ctx, http.MethodGet, u, source.Headers)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := sp.client.Do(req) //nolint:bodyclose // this is being closed - golangci-lint is wrong again
+	if err != nil {
+		return "", fmt.Errorf("failed to begin download from url %s: %w", u, err)
 	}
+	defer closer.DrainAndCloseWithLogOnError(ctx, "response", res.Body)
 
-	// Reset previous redirect handler
-	sp.client.HTTPClient.CheckRedirect = previousRedirect
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return "", fmt.Errorf("non-200 response from URL (%s): %s", u, res.Status)
+	}
+	if etag != "" && res.Header.Get("ETag") != etag {
+		return "", fmt.Errorf("content of url %s changed while downloading it: expected ETag %s, got %s",
+			u, etag, res.Header.Get("ETag"))
+	}
+	if source.MaxSize > 0 && res.ContentLength > 0 && uint64(res.ContentLength) > source.MaxSize {
+		return "", newSizeLimitExceededError(u, source.MaxSize)
+	}
 
 	var fileName string
 	baseName := path.Base(res.Request.URL.Path)
 
 	// Check whether content-disposition is set, but only after a redirect
-	if requestDidRedirect {
+	if redirects.redirected {
 		fileName = filenameFromDisposition(res.Header.Get("content-disposition"))
 	}
 
@@ -183,36 +334,96 @@ func (sp *StorageProvider) PrepareStorage(
 	filePath := filepath.Join(outputPath, fileName)
 	w, err := os.Create(filePath) //nolint:gosec // G304: filePath validated by caller
 	if err != nil {
-		return storage.StorageVolume{}, fmt.Errorf("failed to create file %s: %s", filePath, err)
+		return "", fmt.Errorf("failed to create file %s: %s", filePath, err)
 	}
 
 	defer closer.CloseWithLogOnError("file", w)
 
+	var dst io.Writer = w
+	var h hash.Hash
+	var expected []byte
+	if source.Checksum != "" {
+		if h, expected, err = parseChecksum(source.Checksum); err != nil {
+			return "", err
+		}
+		dst = io.MultiWriter(w, h)
+	}
+	var body io.Reader = res.Body
+	if source.MaxSize > 0 {
+		// read one more byte than the max size to detect servers sending more than their content length
+		body = io.LimitReader(res.Body, int64(min(source.MaxSize, uint64(math.MaxInt64-1)))+1)
+	}
+
 	// stream the body to the client without fully loading it into memory
-	if _, err := io.Copy(w, res.Body); err != nil {
-		return storage.StorageVolume{}, fmt.Errorf("failed to write to file %s: %s", filePath, err)
+	n, err := io.Copy(dst, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to write to file %s: %s", filePath, err)
+	}
+	if source.MaxSize > 0 && uint64(n) > source.MaxSize {
+		return "", newSizeLimitExceededError(u, source.MaxSize)
+	}
+	if h != nil {
+		if actual := h.Sum(nil); !bytes.Equal(actual, expected) {
+			return "", newChecksumMismatchError(u, source.Checksum, hex.EncodeToString(actual))
+		}
 	}
 
 	if err := w.Sync(); err != nil {
-		return storage.StorageVolume{}, fmt.Errorf("failed to sync file %s: %w", filePath, err)
+		return "", fmt.Errorf("failed to sync file %s: %w", filePath, err)
 	}
 
-	targetPath := filepath.Join(input.Target, fileName)
-
 	log.Ctx(ctx).Debug().
 		Stringer("url", u).
 		Stringer("final-url", res.Request.URL).
 		Str("file", filePath).
-		Str("targetFile", targetPath).
 		Msg("Downloaded file")
 
-	volume := storage.StorageVolume{
-		Type:   storage.StorageVolumeConnectorBind,
-		Source: filePath,   // The source is the full path to the file
-		Target: targetPath, // So we should alter the target to include the file name
+	return fileName, nil
+}
+
+// redirectState tracks the redirects of a request, and is stored in the context of the request for checkRedirect
+type redirectState struct {
+	// headers are the names of the headers of the source, which are only sent to the host of its URL
+	headers []string
+	// redirected is true once the request was redirected
+	redirected bool
+}
+
+type redirectStateKey struct{}
+
+// newRequest creates a request of the URL with the headers of the source, and returns the state of its redirects
+func newRequest(
+	ctx context.Context, method string, u *url.URL, headers map[string]string) (*retryablehttp.Request, *redirectState, error) {
+	state := &redirectState{}
+	req, err := retryablehttp.NewRequestWithContext(context.WithValue(ctx, redirectStateKey{}, state), method, u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+		state.headers = append(state.headers, name)
 	}
+	return req, state, nil
+}
 
-	return volume, nil
+// checkRedirect records the redirects of requests, and removes the headers of the source from the requests
+// redirected to another host, as they may hold secrets such as API keys. The http client already removes
+// the standard credential headers, but not custom ones.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	state, ok := req.Context().Value(redirectStateKey{}).(*redirectState)
+	if !ok {
+		return nil
+	}
+	state.redirected = true
+	if req.URL.Host != via[0].URL.Host {
+		for _, name := range state.headers {
+			req.Header.Del(name)
+		}
+	}
+	return nil
 }
 
 func filenameFromDisposition(contentDispositionHdr string) string {
@@ -245,11 +456,22 @@ func (sp *StorageProvider) CleanupStorage(
 	_ models.InputSource,
 	volume storage.StorageVolume,
 ) error {
+	if sp.cache != nil && sp.cache.Release(volume.Source) {
+		return nil
+	}
 	pathToCleanup := filepath.Dir(volume.Source)
 	log.Ctx(ctx).Debug().Str("ResultPath", pathToCleanup).Msg("Cleaning up")
 	return os.RemoveAll(pathToCleanup)
 }
 
+// CachedSources returns the input sources in the input cache
+func (sp *StorageProvider) CachedSources() []string {
+	if sp.cache == nil {
+		return nil
+	}
+	return sp.cache.CachedSources()
+}
+
 func (sp *StorageProvider) Upload(context.Context, string) (models.SpecConfig, error) {
 	// we don't "upload" anything to a URL
 	return models.SpecConfig{}, fmt.Errorf("not implemented")
@@ -277,6 +499,7 @@ func IsURLSupported(rawURL string) (*url.URL, error) {
 }
 
 var _ storage.Storage = (*StorageProvider)(nil)
+var _ storage.CachedSourcesProvider = (*StorageProvider)(nil)
 
 var _ retryablehttp.LeveledLogger = retryLogger{}
 
@@ -4,23 +4,39 @@ package urldownload
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
 	"github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/logger"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/inputcache"
 	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
 )
 
+// mapResolver resolves the header values from a map
+type mapResolver map[string]string
+
+func (r mapResolver) Value(value string) (string, error) {
+	if v, ok := r[value]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("value %s not found", value)
+}
+
 // Define the suite, and absorb the built-in basic suite
 // functionality from testify - including a T() method which
 // returns the current testing context
@@ -42,7 +58,7 @@ func (s *StorageSuite) TestHasStorageLocally() {
 	testConfig, err := config.NewTestConfig()
 	s.Require().NoError(err)
 
-	sp := NewStorage(time.Duration(testConfig.InputSources.ReadTimeout), testConfig.InputSources.MaxRetryCount)
+	sp := NewStorage(time.Duration(testConfig.InputSources.ReadTimeout), testConfig.InputSources.MaxRetryCount, nil, nil)
 
 	spec := models.InputSource{
 		Source: &models.SpecConfig{
@@ -308,7 +324,7 @@ func (s *StorageSuite) TestPrepareStorageURL() {
 			testConfig, err := config.NewTestConfig()
 			s.Require().NoError(err)
 
-			sp := NewStorage(time.Duration(testConfig.InputSources.ReadTimeout), testConfig.InputSources.MaxRetryCount)
+			sp := NewStorage(time.Duration(testConfig.InputSources.ReadTimeout), testConfig.InputSources.MaxRetryCount, nil, nil)
 
 			url := fmt.Sprintf("%s%s", ts.URL, test.requests[0].path)
 			spec := models.InputSource{
@@ -374,7 +390,7 @@ func (s *StorageSuite) TestGetVolumeSize_WithServerReturningValidSize() {
 	testConfig, err := config.NewTestConfig()
 	s.Require().NoError(err)
 
-	sp := NewStorage(time.Duration(testConfig.InputSources.ReadTimeout), testConfig.InputSources.MaxRetryCount)
+	sp := NewStorage(time.Duration(testConfig.InputSources.ReadTimeout), testConfig.InputSources.MaxRetryCount, nil, nil)
 
 	url := fmt.Sprintf("%s%s", ts.URL, path)
 	spec := models.InputSource{
@@ -415,7 +431,7 @@ func (s *StorageSuite) TestGetVolumeSize_WithServerReturningInvalidSize() {
 	testConfig, err := config.NewTestConfig()
 	s.Require().NoError(err)
 
-	sp := NewStorage(time.Duration(testConfig.InputSources.ReadTimeout), testConfig.InputSources.MaxRetryCount)
+	sp := NewStorage(time.Duration(testConfig.InputSources.ReadTimeout), testConfig.InputSources.MaxRetryCount, nil, nil)
 
 	url := fmt.Sprintf("%s%s", ts.URL, path)
 	spec := models.InputSource{
@@ -432,3 +448,274 @@ func (s *StorageSuite) TestGetVolumeSize_WithServerReturningInvalidSize() {
 	s.Require().ErrorIs(err, ErrNoContentLengthFound)
 
 }
+
+func (s *StorageSuite) TestPrepareStorageWithCache() {
+	gets := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Method == http.MethodGet {
+			gets++
+		}
+		_, err := w.Write([]byte("cached content"))
+		s.NoError(err)
+	}))
+	s.T().Cleanup(ts.Close)
+
+	testConfig, err := config.NewTestConfig()
+	s.Require().NoError(err)
+	cache, err := inputcache.New(inputcache.Params{Dir: s.T().TempDir()})
+	s.Require().NoError(err)
+	sp := NewStorage(time.Duration(testConfig.InputSources.ReadTimeout), testConfig.InputSources.MaxRetryCount, cache, nil)
+
+	spec := models.InputSource{
+		Source: &models.SpecConfig{
+			Type: models.StorageSourceURL,
+			Params: Source{
+				URL: ts.URL + "/data.txt",
+			}.ToMap(),
+		},
+		Target: "/inputs",
+	}
+
+	locally, err := sp.HasStorageLocally(context.Background(), spec)
+	s.Require().NoError(err)
+	s.False(locally)
+
+	for i := 0; i < 2; i++ {
+		vol, err := sp.PrepareStorage(context.Background(), s.T().TempDir(), mock.Execution(), spec)
+		s.Require().NoError(err)
+		s.True(vol.ReadOnly)
+		s.Equal("/inputs/data.txt", vol.Target)
+		content, err := os.ReadFile(vol.Source)
+		s.Require().NoError(err)
+		s.Equal("cached content", string(content))
+		s.Require().NoError(sp.CleanupStorage(context.Background(), spec, vol))
+	}
+	s.Equal(1, gets, "the url should be downloaded once")
+
+	locally, err = sp.HasStorageLocally(context.Background(), spec)
+	s.Require().NoError(err)
+	s.True(locally)
+	s.Equal([]string{inputcache.SourceID(spec.Source)}, sp.CachedSources())
+}
+
+// verifiedServer serves content, requiring the X-Api-Key header if apiKey is not empty
+func (s *StorageSuite) verifiedServer(content, apiKey string) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if apiKey != "" && r.Header.Get("X-Api-Key") != apiKey {
+			http.Error(w, "missing api key", http.StatusUnauthorized)
+			return
+		}
+		_, err := w.Write([]byte(content))
+		s.NoError(err)
+	}))
+	s.T().Cleanup(ts.Close)
+	return ts
+}
+
+func (s *StorageSuite) verifiedInput(source Source) models.InputSource {
+	return models.InputSource{
+		Source: &models.SpecConfig{Type: models.StorageSourceURL, Params: source.ToMap()},
+		Target: "/inputs",
+	}
+}
+
+func (s *StorageSuite) TestPrepareStorage_Checksum() {
+	ts := s.verifiedServer("verified content", "")
+	sha256Sum := sha256.Sum256([]byte("verified content"))
+	sha512Sum := sha512.Sum512([]byte("verified content"))
+	cache, err := inputcache.New(inputcache.Params{Dir: s.T().TempDir()})
+	s.Require().NoError(err)
+
+	for _, test := range []struct {
+		name     string
+		checksum string
+		cache    *inputcache.Cache
+		matches  bool
+	}{
+		{name: "sha256", checksum: "sha256:" + hex.EncodeToString(sha256Sum[:]), matches: true},
+		{name: "sha512", checksum: "SHA512:" + hex.EncodeToString(sha512Sum[:]), matches: true},
+		{name: "cached", checksum: "sha256:" + hex.EncodeToString(sha256Sum[:]), cache: cache, matches: true},
+		{name: "mismatch", checksum: "sha256:" + strings.Repeat("00", sha256.Size)},
+		{name: "cached mismatch", checksum: "sha256:" + strings.Repeat("00", sha256.Size), cache: cache},
+	} {
+		s.Run(test.name, func() {
+			sp := NewStorage(time.Minute, 0, test.cache, nil)
+			input := s.verifiedInput(Source{URL: ts.URL + "/data.txt", Checksum: test.checksum})
+			storageDirectory := s.T().TempDir()
+			vol, err := sp.PrepareStorage(context.Background(), storageDirectory, mock.Execution(), input)
+			if !test.matches {
+				s.Require().Error(err)
+				s.True(bacerrors.IsErrorWithCode(err, ChecksumMismatch), err.Error())
+				entries, err := os.ReadDir(storageDirectory)
+				s.Require().NoError(err)
+				s.Empty(entries, "the download is cleaned up")
+				return
+			}
+			s.Require().NoError(err)
+			content, err := os.ReadFile(vol.Source)
+			s.Require().NoError(err)
+			s.Equal("verified content", string(content))
+			s.Require().NoError(sp.CleanupStorage(context.Background(), input, vol))
+		})
+	}
+}
+
+func (s *StorageSuite) TestPrepareStorage_Headers() {
+	ts := s.verifiedServer("private content", "key")
+	sp := NewStorage(time.Minute, 0, nil, nil)
+
+	_, err := sp.PrepareStorage(context.Background(), s.T().TempDir(), mock.Execution(),
+		s.verifiedInput(Source{URL: ts.URL + "/data.txt"}))
+	s.Require().Error(err)
+
+	input := s.verifiedInput(Source{URL: ts.URL + "/data.txt", Headers: map[string]string{"X-Api-Key": "key"}})
+	size, err := sp.GetVolumeSize(context.Background(), mock.Execution(), input)
+	s.Require().NoError(err)
+	s.Equal(uint64(len("private content")), size)
+
+	vol, err := sp.PrepareStorage(context.Background(), s.T().TempDir(), mock.Execution(), input)
+	s.Require().NoError(err)
+	content, err := os.ReadFile(vol.Source)
+	s.Require().NoError(err)
+	s.Equal("private content", string(content))
+}
+
+func (s *StorageSuite) TestPrepareStorage_HeadersNotSentToOtherHosts() {
+	var otherHostKey string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherHostKey = r.Header.Get("X-Api-Key")
+		_, _ = w.Write([]byte("other content"))
+	}))
+	s.T().Cleanup(other.Close)
+
+	var sameHostKey string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/same.txt":
+			http.Redirect(w, r, "/target.txt", http.StatusFound)
+		case "/target.txt":
+			sameHostKey = r.Header.Get("X-Api-Key")
+			_, _ = w.Write([]byte("same content"))
+		default:
+			http.Redirect(w, r, other.URL+"/data.txt", http.StatusFound)
+		}
+	}))
+	s.T().Cleanup(ts.Close)
+	sp := NewStorage(time.Minute, 0, nil, nil)
+
+	for _, path := range []string{"/same.txt", "/other.txt"} {
+		input := s.verifiedInput(Source{URL: ts.URL + path, Headers: map[string]string{"X-Api-Key": "key"}})
+		_, err := sp.PrepareStorage(context.Background(), s.T().TempDir(), mock.Execution(), input)
+		s.Require().NoError(err)
+	}
+	s.Equal("key", sameHostKey)
+	s.Empty(otherHostKey)
+}
+
+func (s *StorageSuite) TestPrepareStorage_ResolvedHeaders() {
+	ts := s.verifiedServer("private content", "key")
+	sp := NewStorage(time.Minute, 0, nil, mapResolver{"env:API_KEY": "key"})
+
+	input := s.verifiedInput(Source{URL: ts.URL + "/data.txt", Headers: map[string]string{"X-Api-Key": "env:API_KEY"}})
+	size, err := sp.GetVolumeSize(context.Background(), mock.Execution(), input)
+	s.Require().NoError(err)
+	s.Equal(uint64(len("private content")), size)
+
+	vol, err := sp.PrepareStorage(context.Background(), s.T().TempDir(), mock.Execution(), input)
+	s.Require().NoError(err)
+	content, err := os.ReadFile(vol.Source)
+	s.Require().NoError(err)
+	s.Equal("private content", string(content))
+
+	_, err = sp.PrepareStorage(context.Background(), s.T().TempDir(), mock.Execution(),
+		s.verifiedInput(Source{URL: ts.URL + "/data.txt", Headers: map[string]string{"X-Api-Key": "env:MISSING"}}))
+	s.Require().ErrorContains(err, "failed to resolve the value of header X-Api-Key")
+}
+
+func (s *StorageSuite) TestPrepareStorage_ChecksumCachedPerURL() {
+	ts := s.verifiedServer("verified content", "")
+	sum := sha256.Sum256([]byte("verified content"))
+	checksum := "sha256:" + hex.EncodeToString(sum[:])
+	cache, err := inputcache.New(inputcache.Params{Dir: s.T().TempDir()})
+	s.Require().NoError(err)
+	sp := NewStorage(time.Minute, 0, cache, nil)
+
+	// the same content is mounted under the name of the file of each url
+	for _, name := range []string{"first.txt", "second.txt"} {
+		input := s.verifiedInput(Source{URL: ts.URL + "/" + name, Checksum: checksum})
+		vol, err := sp.PrepareStorage(context.Background(), s.T().TempDir(), mock.Execution(), input)
+		s.Require().NoError(err)
+		s.Equal("/inputs/"+name, vol.Target)
+		s.Equal(name, filepath.Base(vol.Source))
+		s.Require().NoError(sp.CleanupStorage(context.Background(), input, vol))
+	}
+}
+
+func (s *StorageSuite) TestPrepareStorage_MaxSize() {
+	ts := s.verifiedServer("large content", "")
+	// unknownLength streams its content without a Content-Length
+	unknownLength := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, err := w.Write([]byte("large"))
+		s.NoError(err)
+		w.(http.Flusher).Flush()
+		_, err = w.Write([]byte(" content"))
+		s.NoError(err)
+	}))
+	s.T().Cleanup(unknownLength.Close)
+	sp := NewStorage(time.Minute, 0, nil, nil)
+
+	input := s.verifiedInput(Source{URL: ts.URL + "/data.txt", MaxSize: uint64(len("large content"))})
+	vol, err := sp.PrepareStorage(context.Background(), s.T().TempDir(), mock.Execution(), input)
+	s.Require().NoError(err)
+	s.Require().NoError(sp.CleanupStorage(context.Background(), input, vol))
+
+	for _, u := range []string{ts.URL, unknownLength.URL} {
+		input = s.verifiedInput(Source{URL: u + "/data.txt", MaxSize: 5})
+		_, err = sp.PrepareStorage(context.Background(), s.T().TempDir(), mock.Execution(), input)
+		s.Require().Error(err)
+		s.True(bacerrors.IsErrorWithCode(err, SizeLimitExceeded), err.Error())
+	}
+
+	// the size is bounded by the max size when the server doesn't provide it
+	size, err := sp.GetVolumeSize(context.Background(), mock.Execution(),
+		s.verifiedInput(Source{URL: unknownLength.URL + "/data.txt", MaxSize: 5}))
+	s.Require().NoError(err)
+	s.Equal(uint64(5), size)
+
+	_, err = sp.GetVolumeSize(context.Background(), mock.Execution(),
+		s.verifiedInput(Source{URL: ts.URL + "/data.txt", MaxSize: 5}))
+	s.True(bacerrors.IsErrorWithCode(err, SizeLimitExceeded))
+}
+
+func TestSourceValidate(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		source Source
+		valid  bool
+	}{
+		{name: "url", source: Source{URL: "https://example.com/data.csv"}, valid: true},
+		{name: "sha256", source: Source{URL: "https://example.com/data.csv", Checksum: "sha256:" + strings.Repeat("ab", 32)}, valid: true},
+		{name: "sha512", source: Source{URL: "https://example.com/data.csv", Checksum: "sha512:" + strings.Repeat("ab", 64)}, valid: true},
+		{name: "headers", valid: true, source: Source{URL: "https://example.com/data.csv",
+			Headers: map[string]string{"Authorization": "Bearer token"}}},
+		{name: "unknown algorithm", source: Source{URL: "https://example.com/data.csv", Checksum: "md5:" + strings.Repeat("ab", 16)}},
+		{name: "missing algorithm", source: Source{URL: "https://example.com/data.csv", Checksum: strings.Repeat("ab", 32)}},
+		{name: "short digest", source: Source{URL: "https://example.com/data.csv", Checksum: "sha256:abcd"}},
+		{name: "invalid digest", source: Source{URL: "https://example.com/data.csv", Checksum: "sha256:" + strings.Repeat("zz", 32)}},
+		{name: "invalid header", source: Source{URL: "https://example.com/data.csv", Headers: map[string]string{"Bad Header": "value"}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.source.Validate()
+			if test.valid && err != nil {
+				t.Errorf("expected valid source, got %s", err)
+			}
+			if !test.valid && err == nil {
+				t.Error("expected invalid source")
+			}
+		})
+	}
+}
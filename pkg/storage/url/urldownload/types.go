@@ -1,8 +1,13 @@
 package urldownload
 
 import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
+	"strings"
 
 	"github.com/fatih/structs"
 	"github.com/mitchellh/mapstructure"
@@ -10,8 +15,23 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 )
 
+// checksumAlgorithms are the supported digest algorithms of checksums
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
 type Source struct {
 	URL string
+	// Checksum is the expected digest of the content of the URL, as <algorithm>:<hex digest>,
+	// with sha256 or sha512 as algorithm. The download fails if the content doesn't match it.
+	Checksum string `structs:",omitempty"`
+	// Headers are the HTTP headers sent with the requests to the URL. Their values are resolved by the
+	// environment variable resolvers of the compute node, e.g. env:API_KEY, so that secrets don't have
+	// to be part of the job spec.
+	Headers map[string]string `structs:",omitempty"`
+	// MaxSize is the maximum size in bytes of the content of the URL. The size is not limited if zero.
+	MaxSize uint64 `structs:",omitempty"`
 }
 
 func (c Source) Validate() error {
@@ -21,9 +41,35 @@ func (c Source) Validate() error {
 	if _, err := IsURLSupported(c.URL); err != nil {
 		return fmt.Errorf("invalid url storage params: %w", err)
 	}
+	if c.Checksum != "" {
+		if _, _, err := parseChecksum(c.Checksum); err != nil {
+			return fmt.Errorf("invalid url storage params: %w", err)
+		}
+	}
+	for name := range c.Headers {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+			return fmt.Errorf("invalid url storage params: invalid header name %q", name)
+		}
+	}
 	return nil
 }
 
+// parseChecksum returns the hash and the expected digest of a checksum
+func parseChecksum(checksum string) (hash.Hash, []byte, error) {
+	algorithm, digest, found := strings.Cut(checksum, ":")
+	newHash, supported := checksumAlgorithms[strings.ToLower(algorithm)]
+	if !found || !supported {
+		return nil, nil, fmt.Errorf("invalid checksum %q: expected sha256:<hex digest> or sha512:<hex digest>", checksum)
+	}
+	expected, err := hex.DecodeString(digest)
+	h := newHash()
+	if err != nil || len(expected) != h.Size() {
+		return nil, nil, fmt.Errorf("invalid checksum %q: expected a %s digest of %d hex characters",
+			checksum, algorithm, 2*h.Size())
+	}
+	return h, expected, nil
+}
+
 func (c Source) ToMap() map[string]interface{} {
 	return structs.Map(c)
 }
@@ -46,7 +92,11 @@ func DecodeSpec(spec *models.SpecConfig) (Source, error) {
 }
 
 func NewSpecConfig(url string) (*models.SpecConfig, error) {
-	s := Source{URL: url}
+	return NewSourceSpecConfig(Source{URL: url})
+}
+
+// NewSourceSpecConfig returns the spec of the source, which can verify the content of the URL
+func NewSourceSpecConfig(s Source) (*models.SpecConfig, error) {
 	if err := s.Validate(); err != nil {
 		return nil, fmt.Errorf("creating %s storage spec: %w", models.StorageSourceURL, err)
 	}
@@ -0,0 +1,166 @@
+//go:build integration || !unit
+
+package devstack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+	"github.com/bacalhau-project/bacalhau/pkg/devstack"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/noop"
+	"github.com/bacalhau-project/bacalhau/pkg/logger"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/node"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	clientv2 "github.com/bacalhau-project/bacalhau/pkg/publicapi/client/v2"
+	"github.com/bacalhau-project/bacalhau/pkg/system"
+	"github.com/bacalhau-project/bacalhau/pkg/test/scenario"
+	"github.com/bacalhau-project/bacalhau/pkg/test/teststack"
+)
+
+// postgresDSNEnv is the environment variable of the DSN of an empty Postgres database
+// shared by the orchestrators, as leader election requires a Postgres job store
+const postgresDSNEnv = "BACALHAU_TEST_POSTGRES_DSN"
+
+type LeaderElectionSuite struct {
+	suite.Suite
+	dsn   string
+	stack *devstack.DevStack
+}
+
+func TestLeaderElectionSuite(t *testing.T) {
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", postgresDSNEnv)
+	}
+	suite.Run(t, &LeaderElectionSuite{dsn: dsn})
+}
+
+func (s *LeaderElectionSuite) SetupTest() {
+	logger.ConfigureTestLogging(s.T())
+	ctx := context.Background()
+	cm := system.NewCleanupManager()
+	s.T().Cleanup(func() {
+		cm.Cleanup(ctx)
+	})
+
+	// only the leader tracks the compute nodes, so the stack isn't set up with teststack,
+	// which waits for every orchestrator to discover all the nodes
+	var err error
+	s.stack, err = devstack.Setup(ctx, cm,
+		devstack.WithBasePath(s.T().TempDir()),
+		devstack.WithNumberOfRequesterOnlyNodes(3),
+		devstack.WithNumberOfComputeOnlyNodes(1),
+		devstack.WithLeaderElection(true),
+		devstack.WithBacalhauConfigOverride(types.Bacalhau{
+			Orchestrator: types.Orchestrator{
+				JobStore: types.JobStore{Type: types.JobStoreTypePostgres, DSN: s.dsn},
+			},
+		}),
+		teststack.WithNoopExecutor(noop.ExecutorConfig{}, types.EngineConfig{}),
+	)
+	s.Require().NoError(err)
+}
+
+// orchestrators returns the orchestrators of the stack that are running
+func (s *LeaderElectionSuite) orchestrators() []*node.Node {
+	var orchestrators []*node.Node
+	for _, n := range s.stack.Nodes {
+		if n.IsRequesterNode() {
+			orchestrators = append(orchestrators, n)
+		}
+	}
+	return orchestrators
+}
+
+// waitForLeader waits until a single orchestrator among the given ones is the leader, and returns it
+func (s *LeaderElectionSuite) waitForLeader(orchestrators []*node.Node) *node.Node {
+	var leader *node.Node
+	s.Require().Eventually(func() bool {
+		leader = nil
+		for _, n := range orchestrators {
+			if n.RequesterNode.Leadership.IsLeader() {
+				if leader != nil {
+					return false
+				}
+				leader = n
+			}
+		}
+		return leader != nil
+	}, 30*time.Second, 100*time.Millisecond, "no orchestrator was elected as leader")
+	return leader
+}
+
+// waitForComputeNodes waits until the leader tracks all the compute nodes as connected
+func (s *LeaderElectionSuite) waitForComputeNodes(ctx context.Context, leader *node.Node) {
+	s.Require().Eventually(func() bool {
+		for _, n := range s.stack.Nodes {
+			if !n.IsComputeNode() {
+				continue
+			}
+			state, err := leader.RequesterNode.NodeInfoStore.Get(ctx, n.ID)
+			if err != nil || state.ConnectionState.Status != models.NodeStates.CONNECTED {
+				return false
+			}
+		}
+		return true
+	}, 60*time.Second, 100*time.Millisecond, "compute nodes did not connect to the leader")
+}
+
+// runJob submits a job to the orchestrator, and waits for it to complete
+func (s *LeaderElectionSuite) runJob(ctx context.Context, orchestrator *node.Node, name string) {
+	client := clientv2.New(fmt.Sprintf("http://%s:%d", orchestrator.APIServer.Address, orchestrator.APIServer.Port))
+	job := &models.Job{
+		Name:  name,
+		Type:  models.JobTypeBatch,
+		Count: 1,
+		Tasks: []*models.Task{
+			{
+				Name: s.T().Name(),
+				Engine: &models.SpecConfig{
+					Type:   models.EngineNoop,
+					Params: make(map[string]interface{}),
+				},
+			},
+		},
+	}
+	job.Normalize()
+	response, err := client.Jobs().Put(ctx, &apimodels.PutJobRequest{Job: job})
+	s.Require().NoError(err)
+
+	stateResolver := scenario.NewStateResolverFromAPI(client)
+	s.Require().NoError(stateResolver.Wait(ctx, response.JobID, scenario.WaitForSuccessfulCompletion()))
+}
+
+func (s *LeaderElectionSuite) TestStandbyTakesOverWhenLeaderStops() {
+	ctx := context.Background()
+	orchestrators := s.orchestrators()
+	leader := s.waitForLeader(orchestrators)
+	s.waitForComputeNodes(ctx, leader)
+
+	// jobs submitted to any orchestrator are scheduled by the leader
+	for _, orchestrator := range orchestrators {
+		s.runJob(ctx, orchestrator, "before-failover-"+orchestrator.ID)
+	}
+
+	s.Require().NoError(leader.Stop(ctx))
+	var standbys []*node.Node
+	for _, orchestrator := range orchestrators {
+		if orchestrator != leader {
+			standbys = append(standbys, orchestrator)
+		}
+	}
+
+	newLeader := s.waitForLeader(standbys)
+	s.waitForComputeNodes(ctx, newLeader)
+	for _, orchestrator := range standbys {
+		s.runJob(ctx, orchestrator, "after-failover-"+orchestrator.ID)
+	}
+	s.NotEqual(leader.ID, newLeader.ID)
+}
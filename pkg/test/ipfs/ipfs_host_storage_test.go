@@ -55,7 +55,7 @@ func (suite *IPFSHostStorageSuite) TestIpfsApiCopyFile() {
 		func(ctx context.Context, api ipfs.Client) (
 			storage.Storage, error) {
 
-			return ipfs_storage.NewStorage(api, 3*time.Second)
+			return ipfs_storage.NewStorage(api, 3*time.Second, nil)
 		},
 	)
 }
@@ -65,7 +65,7 @@ func (suite *IPFSHostStorageSuite) TestIPFSAPICopyFolder() {
 		func(ctx context.Context, api ipfs.Client) (
 			storage.Storage, error) {
 
-			return ipfs_storage.NewStorage(api, 3*time.Second)
+			return ipfs_storage.NewStorage(api, 3*time.Second, nil)
 		},
 	)
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/devstack"
 	"github.com/bacalhau-project/bacalhau/pkg/downloader"
 	dockmodels "github.com/bacalhau-project/bacalhau/pkg/executor/docker/models"
+	execmodels "github.com/bacalhau-project/bacalhau/pkg/executor/exec/models"
 	http "github.com/bacalhau-project/bacalhau/pkg/executor/wasm/funcs/http/testdata"
 	wasmmodels "github.com/bacalhau-project/bacalhau/pkg/executor/wasm/models"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -542,6 +543,71 @@ func WasmNoNetworking(t testing.TB) Scenario {
 	}
 }
 
+func WasmInstructionLimit(t testing.TB) Scenario {
+	return Scenario{
+		CommandResultsChecker: ErrorMessageContains("execution exceeded its limit of 1000 instructions"),
+		Job: &models.Job{
+			Name:  t.Name(),
+			Type:  models.JobTypeBatch,
+			Count: 1,
+			Tasks: []*models.Task{
+				{
+					Name: t.Name(),
+					InputSources: []*models.InputSource{
+						InlineDataWithTarget(exit_code.Program(), "main.wasm"),
+					},
+					Engine: wasmmodels.NewWasmEngineBuilder("main.wasm").
+						WithEntrypoint("_start").
+						WithInstructionLimit(1000).
+						MustBuild(),
+				},
+			},
+		},
+	}
+}
+
+func ExecCatFileToVolume(t testing.TB) Scenario {
+	rootSourceDir := t.TempDir()
+
+	return Scenario{
+		Stack: &StackConfig{
+			DevStackOptions: []devstack.ConfigOption{
+				devstack.WithAllowListedLocalPaths([]string{rootSourceDir + AllowedListedLocalPathsSuffix}),
+				devstack.WithAllowListedBinaries([]string{"/**/sh"}),
+			},
+		},
+		Inputs: StoredText(
+			rootSourceDir,
+			helloWorld,
+			simpleMountPath,
+		),
+		ResultsChecker: ManyChecks(
+			FileEquals(downloader.DownloadFilenameStdout, helloWorld),
+			FileEquals("test/output_file.txt", helloWorld),
+		),
+		Outputs: []*models.ResultPath{
+			{
+				Name: "test",
+				Path: "/output_data",
+			},
+		},
+		Job: &models.Job{
+			Name:  t.Name(),
+			Type:  models.JobTypeBatch,
+			Count: 1,
+			Tasks: []*models.Task{
+				{
+					Name: t.Name(),
+					// inputs and outputs are linked relative to the working directory of the process
+					Engine: execmodels.NewExecEngineBuilder("sh").
+						WithArguments("-c", "cat ."+simpleMountPath+" | tee ."+simpleOutputPath).
+						MustBuild(),
+				},
+			},
+		},
+	}
+}
+
 func GetAllScenarios(t testing.TB) map[string]Scenario {
 	scenarios := map[string]Scenario{
 		"cat_file_to_stdout":        CatFileToStdout(t),
@@ -559,12 +625,16 @@ func GetAllScenarios(t testing.TB) map[string]Scenario {
 		"wasm_http_get_allowlist":   WasmGetHTTPAllowList(t),
 		"wasm_http_not_allowlisted": WasmGetHTTPNotAllowList(t),
 		"wasm_no_networking":        WasmNoNetworking(t),
+		"wasm_instruction_limit":    WasmInstructionLimit(t),
+		"exec_cat_file_to_volume":   ExecCatFileToVolume(t),
 	}
 
 	if runtime.GOOS == "windows" {
 		// Temporarily skip the wasm_env_vars test on windows to avoid
 		// flakiness until we can resolve the problem.
 		delete(scenarios, "wasm_env_vars")
+		// exec scenarios run a POSIX shell of the host
+		delete(scenarios, "exec_cat_file_to_volume")
 	}
 
 	return scenarios
@@ -6,6 +6,7 @@ import (
 
 	"github.com/benbjohnson/clock"
 
+	"github.com/bacalhau-project/bacalhau/pkg/compute/execstream"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/logstream"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/backoff"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/envelope"
@@ -40,6 +41,7 @@ type Config struct {
 	EventStore              watcher.EventStore
 	DispatcherConfig        dispatcher.Config
 	LogStreamServer         logstream.Server
+	ExecServer              execstream.Server // Runs commands inside executions, exec is not supported if nil
 
 	// Checkpoint config
 	Checkpointer       nclprotocol.Checkpointer
@@ -107,6 +107,18 @@ func (dp *DataPlane) Start(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to set up log stream handler: %w", err)
 	}
+
+	// Set up exec into running executions
+	if dp.config.ExecServer != nil {
+		_, err = proxy.NewExecHandler(ctx, proxy.ExecHandlerParams{
+			Name:       dp.config.NodeID,
+			Conn:       dp.Client,
+			ExecServer: dp.config.ExecServer,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set up exec handler: %w", err)
+		}
+	}
 	// Initialize ordered publisher for reliable message delivery
 	dp.Publisher, err = ncl.NewOrderedPublisher(dp.Client, ncl.OrderedPublisherConfig{
 		Name:              dp.config.NodeID,
@@ -17,6 +17,8 @@ func CreateMessageRegistry() (*envelope.Registry, error) {
 		reg.Register(messages.CancelExecutionMessageType, messages.CancelExecutionRequest{}),
 		reg.Register(messages.BidResultMessageType, messages.BidResult{}),
 		reg.Register(messages.RunResultMessageType, messages.RunResult{}),
+		reg.Register(messages.CheckpointResultMessageType, messages.CheckpointResult{}),
+		reg.Register(messages.PartialResultMessageType, messages.PartialResult{}),
 		reg.Register(messages.ComputeErrorMessageType, messages.ComputeError{}),
 
 		// Control plane messages
@@ -196,15 +196,8 @@ func decompress(src io.Reader, dst string, max datasize.ByteSize) error {
 		if err != nil {
 			return err
 		}
-		target := header.Name
-
-		// validate name against path traversal
-		if !validRelPath(header.Name) {
-			return fmt.Errorf("tar contained invalid name error %q", target)
-		}
-
-		// add dst + re-format slashes according to system
-		target, err = sanitizeArchivePath(dst, header.Name)
+		// validate name against path traversal, add dst + re-format slashes according to system
+		target, err := SafeArchivePath(dst, header.Name)
 		if err != nil {
 			return err
 		}
@@ -246,6 +239,15 @@ func decompress(src io.Reader, dst string, max datasize.ByteSize) error {
 	return nil
 }
 
+// SafeArchivePath returns the path in the destination directory of an entry of an archive,
+// or an error if the name of the entry would escape the directory.
+func SafeArchivePath(dst, name string) (string, error) {
+	if !validRelPath(name) {
+		return "", fmt.Errorf("archive contained invalid name error %q", name)
+	}
+	return sanitizeArchivePath(dst, name)
+}
+
 // check for path traversal and correct forward slashes
 func validRelPath(p string) bool {
 	if p == "" || strings.Contains(p, `\`) || strings.HasPrefix(p, "/") || strings.Contains(p, "../") {